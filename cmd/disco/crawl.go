@@ -0,0 +1,245 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/gnostic/compiler"
+	discovery "github.com/google/gnostic/discovery"
+)
+
+// Statuses of the APIs of a crawl.
+const (
+	// statusConverted APIs were fetched and converted.
+	statusConverted = "converted"
+	// statusUnchanged APIs were converted by an earlier crawl and their
+	// descriptions haven't changed since.
+	statusUnchanged = "unchanged"
+	// statusFailed APIs couldn't be fetched or converted. They are
+	// retried by the next crawl.
+	statusFailed = "failed"
+	// statusPending APIs weren't reached before the crawl was cancelled.
+	statusPending = "pending"
+)
+
+// apiStatus records the result of crawling an API.
+type apiStatus struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+	ETag    string `json:"etag,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// crawlState is the content of the state file of a crawl. It records the
+// APIs that were converted with the ETags of their descriptions, so that
+// a crawl that is run again skips the APIs whose descriptions haven't
+// changed.
+type crawlState struct {
+	APIs map[string]*apiStatus `json:"apis"`
+}
+
+// readCrawlState reads a state file. A missing file is an empty state.
+func readCrawlState(path string) (*crawlState, error) {
+	state := &crawlState{APIs: make(map[string]*apiStatus)}
+	if path == "" {
+		return state, nil
+	}
+	bytes, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bytes, state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %v", path, err)
+	}
+	if state.APIs == nil {
+		state.APIs = make(map[string]*apiStatus)
+	}
+	return state, nil
+}
+
+// writeJSONFile writes a value to a file in JSON. The file is replaced
+// atomically, so that it is intact if the crawl is interrupted.
+func writeJSONFile(path string, v interface{}) error {
+	bytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return compiler.WriteFileAtomically(path, append(bytes, '\n'))
+}
+
+// tokenBucket limits the rate of requests. It holds up to burst tokens,
+// which are added at a rate of qps per second, and each request takes one.
+type tokenBucket struct {
+	mutex  sync.Mutex
+	qps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a token bucket that allows qps requests per
+// second, or nil if qps isn't positive. A nil bucket doesn't limit
+// requests.
+func newTokenBucket(qps float64, burst int) *tokenBucket {
+	if qps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{qps: qps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait takes a token, waiting until one is available or the context is done.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if b == nil {
+		return ctx.Err()
+	}
+	for {
+		b.mutex.Lock()
+		now := time.Now()
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.qps)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mutex.Unlock()
+			return ctx.Err()
+		}
+		wait := time.Duration((1 - b.tokens) / b.qps * float64(time.Second))
+		b.mutex.Unlock()
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// crawler fetches the descriptions of the APIs in the discovery directory
+// and converts them.
+type crawler struct {
+	client  *http.Client
+	listURL string
+	limiter *tokenBucket
+	// statePath is the name of the state file, or "" for no state file.
+	statePath string
+	// convert converts the description of an API.
+	convert func(bytes []byte) error
+}
+
+// crawl converts the APIs of the directory. It stops when the context is
+// cancelled and returns the status of each API of the directory, in the
+// order of the directory. The state file is updated after each API, so
+// that a cancelled crawl can be resumed.
+func (c *crawler) crawl(ctx context.Context) ([]*apiStatus, error) {
+	state, err := readCrawlState(c.statePath)
+	if err != nil {
+		return nil, err
+	}
+	bytes, _, _, err := c.fetch(ctx, c.listURL, "")
+	if err != nil {
+		return nil, err
+	}
+	list, err := discovery.ParseList(bytes)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]*apiStatus, len(list.APIs))
+	for i, api := range list.APIs {
+		statuses[i] = &apiStatus{ID: api.ID, Name: api.Name, Version: api.Version, Status: statusPending}
+	}
+	for i, api := range list.APIs {
+		if err := ctx.Err(); err != nil {
+			return statuses, err
+		}
+		status := statuses[i]
+		previous := state.APIs[api.ID]
+		etag := ""
+		if previous != nil && previous.Status != statusFailed {
+			etag = previous.ETag
+		}
+		bytes, newETag, notModified, err := c.fetch(ctx, api.DiscoveryRestURL, etag)
+		switch {
+		case ctx.Err() != nil:
+			return statuses, ctx.Err()
+		case err != nil:
+			status.Status, status.Error = statusFailed, err.Error()
+		case notModified:
+			status.Status, status.ETag = statusUnchanged, etag
+		default:
+			if err := c.convert(bytes); err != nil {
+				if ctx.Err() != nil {
+					return statuses, ctx.Err()
+				}
+				status.Status, status.Error = statusFailed, err.Error()
+			} else {
+				status.Status, status.ETag = statusConverted, newETag
+			}
+		}
+		// Unchanged APIs keep the status of the crawl that converted them.
+		if status.Status != statusUnchanged {
+			state.APIs[api.ID] = status
+		}
+		if c.statePath != "" {
+			if err := writeJSONFile(c.statePath, state); err != nil {
+				return statuses, err
+			}
+		}
+	}
+	return statuses, nil
+}
+
+// fetch reads a URL. If etag is not empty, the request is conditional and
+// notModified is true if the resource still has that ETag.
+func (c *crawler) fetch(ctx context.Context, url, etag string) (bytes []byte, newETag string, notModified bool, err error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, "", false, err
+	}
+	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if etag != "" {
+		request.Header.Set("If-None-Match", etag)
+	}
+	response, err := c.client.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, "", false, err
+	}
+	defer response.Body.Close()
+	switch {
+	case response.StatusCode == http.StatusNotModified && etag != "":
+		return nil, etag, true, nil
+	case response.StatusCode != http.StatusOK:
+		return nil, "", false, fmt.Errorf("fetching %s: %s", url, response.Status)
+	}
+	bytes, err = ioutil.ReadAll(response.Body)
+	return bytes, response.Header.Get("ETag"), false, err
+}
@@ -0,0 +1,189 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// directoryServer is a fake discovery directory with n APIs named api0,
+// api1, ... whose descriptions have the ETags "v0", "v1", ...
+type directoryServer struct {
+	*httptest.Server
+	mutex sync.Mutex
+	// conditional counts the requests that had a matching If-None-Match.
+	conditional int
+}
+
+func newDirectoryServer(n int) *directoryServer {
+	s := &directoryServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/apis" {
+			items := make([]map[string]string, n)
+			for i := range items {
+				items[i] = map[string]string{
+					"id":               fmt.Sprintf("api%d:v1", i),
+					"name":             fmt.Sprintf("api%d", i),
+					"version":          "v1",
+					"discoveryRestUrl": fmt.Sprintf("%s/apis/api%d", s.URL, i),
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/apis/")
+		etag := `"` + strings.Replace(name, "api", "v", 1) + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			s.mutex.Lock()
+			s.conditional++
+			s.mutex.Unlock()
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprintf(w, `{"name": %q, "version": "v1"}`, name)
+	}))
+	return s
+}
+
+func TestCrawlResumesAfterInterrupt(t *testing.T) {
+	server := newDirectoryServer(5)
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "disco")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(dir)
+	statePath := filepath.Join(dir, "state.json")
+
+	// The first crawl is cancelled after two APIs are converted.
+	ctx, cancel := context.WithCancel(context.Background())
+	var converted []string
+	c := &crawler{
+		client:    server.Client(),
+		listURL:   server.URL + "/apis",
+		statePath: statePath,
+		convert: func(bytes []byte) error {
+			converted = append(converted, string(bytes))
+			if len(converted) == 2 {
+				cancel()
+			}
+			return nil
+		},
+	}
+	statuses, err := c.crawl(ctx)
+	if err != context.Canceled {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(statuses) != 5 || statuses[1].Status != statusConverted || statuses[2].Status != statusPending {
+		t.Fatalf("Unexpected statuses: %+v", statuses)
+	}
+	state, err := readCrawlState(statePath)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(state.APIs) != 2 || state.APIs["api1:v1"].ETag != `"v1"` {
+		t.Fatalf("Unexpected state: %+v", state.APIs)
+	}
+
+	// The second crawl skips the APIs that were converted.
+	converted = nil
+	c.convert = func(bytes []byte) error {
+		converted = append(converted, string(bytes))
+		return nil
+	}
+	statuses, err = c.crawl(context.Background())
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(converted) != 3 || !strings.Contains(converted[0], "api2") {
+		t.Errorf("Unexpected conversions: %v", converted)
+	}
+	if server.conditional != 2 {
+		t.Errorf("Unexpected number of conditional requests: %d", server.conditional)
+	}
+	for i, status := range statuses {
+		expected := statusConverted
+		if i < 2 {
+			expected = statusUnchanged
+		}
+		if status.Status != expected {
+			t.Errorf("Unexpected status for %s: %s", status.ID, status.Status)
+		}
+	}
+	state, err = readCrawlState(statePath)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(state.APIs) != 5 || state.APIs["api0:v1"].Status != statusConverted {
+		t.Errorf("Unexpected state: %+v", state.APIs)
+	}
+}
+
+func TestCrawlRecordsFailures(t *testing.T) {
+	server := newDirectoryServer(2)
+	defer server.Close()
+	c := &crawler{
+		client:  server.Client(),
+		listURL: server.URL + "/apis",
+		convert: func(bytes []byte) error {
+			if strings.Contains(string(bytes), "api0") {
+				return fmt.Errorf("conversion failed")
+			}
+			return nil
+		},
+	}
+	statuses, err := c.crawl(context.Background())
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if statuses[0].Status != statusFailed || statuses[0].Error != "conversion failed" || statuses[1].Status != statusConverted {
+		t.Errorf("Unexpected statuses: %+v, %+v", statuses[0], statuses[1])
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	bucket := newTokenBucket(50, 1)
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := bucket.Wait(context.Background()); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+	// The first request takes the initial token and the next five wait
+	// for 20ms each.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Requests were not limited: %v", elapsed)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := newTokenBucket(0.001, 1).Wait(ctx); err != context.Canceled {
+		t.Errorf("Unexpected error for a cancelled wait: %v", err)
+	}
+	if newTokenBucket(0, 1) != nil {
+		t.Errorf("A bucket without a rate should not limit requests")
+	}
+}
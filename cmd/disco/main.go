@@ -15,10 +15,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
 
 	"github.com/docopt/docopt-go"
@@ -34,6 +38,7 @@ Usage:
 	disco help
 	disco list [--raw]
 	disco get [<api>] [<version>] [--raw] [--openapi2] [--openapi3] [--features] [--schemas] [--all]
+	disco crawl [--raw] [--openapi2] [--openapi3] [--features] [--schemas] [--qps=<qps>] [--state=<file>] [--summary-out=<file>]
 	disco <file> [--openapi2] [--openapi3] [--features] [--schemas]
 	`
 	arguments, err := docopt.Parse(usage, nil, false, "Disco 1.0", false)
@@ -132,6 +137,59 @@ Usage:
 		}
 	}
 
+	// Crawl the directory, resuming an earlier crawl.
+	if arguments["crawl"].(bool) {
+		if !arguments["--raw"].(bool) &&
+			!arguments["--openapi2"].(bool) &&
+			!arguments["--openapi3"].(bool) &&
+			!arguments["--features"].(bool) &&
+			!arguments["--schemas"].(bool) {
+			log.Fatalf("Please specify an output option.")
+		}
+		qps := 0.0
+		if arguments["--qps"] != nil {
+			qps, err = strconv.ParseFloat(arguments["--qps"].(string), 64)
+			if err != nil {
+				log.Fatalf("Invalid --qps: %s", arguments["--qps"])
+			}
+		}
+		c := &crawler{
+			client:  http.DefaultClient,
+			listURL: discovery.APIsListServiceURL,
+			limiter: newTokenBucket(qps, 1),
+			convert: func(bytes []byte) error {
+				_, err := handleExportArgumentsForBytes(arguments, bytes)
+				return err
+			},
+		}
+		if arguments["--state"] != nil {
+			c.statePath = arguments["--state"].(string)
+		}
+		// Interrupts cancel the crawl after the state file is updated.
+		ctx, cancel := context.WithCancel(context.Background())
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		go func() {
+			<-interrupts
+			log.Printf("Interrupted, stopping the crawl")
+			cancel()
+		}()
+		statuses, err := c.crawl(ctx)
+		if arguments["--summary-out"] != nil && statuses != nil {
+			if err := writeJSONFile(arguments["--summary-out"].(string), statuses); err != nil {
+				log.Fatalf("%+v", err)
+			}
+		}
+		if err != nil {
+			log.Fatalf("%+v", err)
+		}
+		for _, status := range statuses {
+			if status.Status == statusFailed {
+				log.Printf("%s/%s: %s", status.Name, status.Version, status.Error)
+			}
+		}
+	}
+
 	// Do something with a local API description.
 	if arguments["<file>"] != nil {
 		// Read the local file.
@@ -0,0 +1,114 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fieldbehavior translates the google.api.field_behavior and
+// google.api.resource_reference annotations of proto fields into the
+// terms of schemas. It is shared by protoc-gen-openapi and
+// protoc-gen-jsonschema.
+package fieldbehavior
+
+import (
+	"log"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Names of the extensions that describe annotations that have no schema
+// keyword.
+const (
+	ImmutableExtension         = "x-immutable"
+	ResourceReferenceExtension = "x-resource-reference"
+)
+
+// Behavior describes the annotations of a field.
+type Behavior struct {
+	// Required fields belong in the required list of their message schema.
+	Required bool
+	// OutputOnly fields are readOnly.
+	OutputOnly bool
+	// InputOnly fields are writeOnly.
+	InputOnly bool
+	// Immutable fields can only be set when a resource is created.
+	Immutable bool
+	// ResourceType is the type of the resource that a field references,
+	// e.g. "pubsub.googleapis.com/Topic". It is the value of the
+	// x-resource-reference extension.
+	ResourceType string
+	// ParentResourceType is set instead of ResourceType for fields that
+	// reference the parent of a resource of this type. The type of the
+	// parent is unknown, so these fields have no x-resource-reference
+	// extension.
+	ParentResourceType string
+}
+
+// ForField returns the behavior of a field.
+func ForField(field protoreflect.FieldDescriptor) *Behavior {
+	b := &Behavior{}
+	switch v := proto.GetExtension(field.Options(), annotations.E_FieldBehavior).(type) {
+	case []annotations.FieldBehavior:
+		for _, behavior := range v {
+			switch behavior {
+			case annotations.FieldBehavior_REQUIRED:
+				b.Required = true
+			case annotations.FieldBehavior_OUTPUT_ONLY:
+				b.OutputOnly = true
+			case annotations.FieldBehavior_INPUT_ONLY:
+				b.InputOnly = true
+			case annotations.FieldBehavior_IMMUTABLE:
+				b.Immutable = true
+			}
+		}
+	case nil:
+	default:
+		log.Printf("unsupported extension type %T", v)
+	}
+	if field.Kind() == protoreflect.StringKind {
+		if reference, ok := proto.GetExtension(field.Options(), annotations.E_ResourceReference).(*annotations.ResourceReference); ok && reference != nil {
+			b.ResourceType = reference.GetType()
+			if b.ResourceType == "" {
+				b.ParentResourceType = reference.GetChildType()
+			}
+		}
+	}
+	return b
+}
+
+// ResourceDescription returns a sentence that describes the resource
+// reference of a field, or "" if the field references no resource.
+func (b *Behavior) ResourceDescription() string {
+	switch {
+	case b.ResourceType == "*":
+		return "Refers to a resource of any type."
+	case b.ResourceType != "":
+		return "Refers to a resource of type " + b.ResourceType + "."
+	case b.ParentResourceType != "":
+		return "Refers to the parent of a resource of type " + b.ParentResourceType + "."
+	}
+	return ""
+}
+
+// AppendResourceDescription appends the description of the resource
+// reference of a field to a description.
+func (b *Behavior) AppendResourceDescription(description string) string {
+	sentence := b.ResourceDescription()
+	switch {
+	case sentence == "":
+		return description
+	case description == "":
+		return sentence
+	}
+	return description + "\n\n" + sentence
+}
@@ -0,0 +1,8 @@
+// Generated with protoc-gen-jsonschema. DO NOT EDIT.
+
+import { Book } from "./Book";
+
+/** An audiobook extends the book resource. Its JSON encoding inlines the fields of the book. */
+export interface Audiobook extends Book {
+  narrator?: string;
+}
@@ -0,0 +1,9 @@
+// Generated with protoc-gen-jsonschema. DO NOT EDIT.
+
+import { Book } from "./Book";
+
+/** A review refers to a book but doesn't extend it, because the book isn't its first field. */
+export interface Review {
+  name?: string;
+  book?: Book;
+}
@@ -0,0 +1,25 @@
+// Generated with protoc-gen-jsonschema. DO NOT EDIT.
+
+import { Author } from "./Author";
+import { Message_Attachment } from "./Message_Attachment";
+
+/** A message with nested messages, enums and oneofs. */
+export interface Message {
+  body?: null | Message_Text | Message_Image;
+  messageId?: string;
+  kind?: "KIND_UNSPECIFIED" | "KIND_TEXT" | "KIND_IMAGE";
+  attachments?: Message_Attachment[];
+  authorsById?: Record<string, Author>;
+  subject?: null | string;
+  replyTo?: Message;
+}
+
+export interface Message_Text {
+  kind: "text";
+  value?: string;
+}
+
+export interface Message_Image {
+  kind: "image";
+  value?: Message_Attachment;
+}
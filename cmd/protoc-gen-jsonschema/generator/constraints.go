@@ -0,0 +1,118 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/google/gnostic/jsonschema"
+	v3 "github.com/google/gnostic/openapiv3"
+)
+
+// Field numbers of the protoc-gen-validate options that are read from
+// unknown fields, so that the generator doesn't depend on its Go package.
+const (
+	validateRulesFieldNumber          = 1071 // (validate.rules)
+	validateRepeatedRulesFieldNumber  = 18   // FieldRules.repeated
+	validateRepeatedUniqueFieldNumber = 3    // RepeatedRules.unique
+)
+
+// addFieldConstraints sets uniqueItems and multipleOf for a field.
+// Repeated fields have unique items if they have a protoc-gen-validate
+// "repeated.unique" rule or an (openapi.v3.property) option with unique_items.
+// Numeric fields can set multiple_of with an (openapi.v3.property) option.
+func addFieldConstraints(field protoreflect.FieldDescriptor, schema *jsonschema.Schema) {
+	unique := validateRepeatedUnique(field.Options())
+	multipleOf := 0.0
+	if property, ok := proto.GetExtension(field.Options(), v3.E_Property).(*v3.Schema); ok && property != nil {
+		unique = unique || property.UniqueItems
+		multipleOf = property.MultipleOf
+	}
+	if unique && field.IsList() {
+		schema.UniqueItems = &unique
+	}
+	valueSchema := schema
+	if field.IsList() && schema.Items != nil {
+		valueSchema = schema.Items.Schema
+	}
+	if multipleOf > 0 && valueSchema != nil {
+		if valueSchema.TypeIs(typeInteger) && multipleOf == math.Trunc(multipleOf) {
+			valueSchema.MultipleOf = jsonschema.NewSchemaNumberWithInteger(int64(multipleOf))
+		} else if valueSchema.TypeIs(typeNumber) || valueSchema.TypeIs(typeInteger) {
+			valueSchema.MultipleOf = jsonschema.NewSchemaNumberWithFloat(multipleOf)
+		}
+	}
+}
+
+// validateRepeatedUnique returns true if field options contain a
+// (validate.rules).repeated.unique rule.
+func validateRepeatedUnique(options proto.Message) bool {
+	if options == nil {
+		return false
+	}
+	rules := unknownMessageField(options.ProtoReflect().GetUnknown(), validateRulesFieldNumber)
+	repeated := unknownMessageField(rules, validateRepeatedRulesFieldNumber)
+	unique := false
+	for b := repeated; len(b) > 0; {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+		if num == validateRepeatedUniqueFieldNumber && typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return false
+			}
+			unique = v != 0
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+	}
+	return unique
+}
+
+// unknownMessageField returns the concatenated encodings of a message field
+// in a wire-format message. Concatenated encodings merge like the messages do.
+func unknownMessageField(b []byte, number protowire.Number) []byte {
+	var result []byte
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil
+		}
+		b = b[n:]
+		if num == number && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return nil
+			}
+			result = append(result, v...)
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return nil
+		}
+		b = b[n:]
+	}
+	return result
+}
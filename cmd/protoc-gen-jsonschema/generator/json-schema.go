@@ -18,7 +18,9 @@ package generator
 import (
 	"fmt"
 	"log"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"google.golang.org/genproto/googleapis/api/annotations"
@@ -27,7 +29,10 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"gopkg.in/yaml.v3"
 
+	"github.com/google/gnostic/cmd/internal/fieldbehavior"
+	"github.com/google/gnostic/compiler"
 	"github.com/google/gnostic/jsonschema"
+	v3 "github.com/google/gnostic/openapiv3"
 )
 
 var (
@@ -56,10 +61,29 @@ func init() {
 }
 
 type Configuration struct {
-	BaseURL  *string
-	Version  *string
-	Naming   *string
-	EnumType *string
+	BaseURL       *string
+	Version       *string
+	Naming        *string
+	EnumType      *string
+	OutputFormat  *string
+	ParseExamples *bool
+	// EnumDescriptions describes the values of string enums with a
+	// oneOf of const schemas that have the comments of the values.
+	EnumDescriptions *bool
+	// TimestampFormat is the format of google.protobuf.Timestamp fields.
+	TimestampFormat *string
+	// Logger receives warnings about unsupported fields and skipped
+	// bindings. If it is nil, the Logger that was set with
+	// compiler.SetLogger is used.
+	Logger compiler.Logger
+}
+
+// logger returns the Logger of a configuration.
+func (conf Configuration) logger() compiler.Logger {
+	if conf.Logger != nil {
+		return conf.Logger
+	}
+	return compiler.CurrentLogger()
 }
 
 // JSONSchemaGenerator holds internal state needed to generate the JSON Schema documents for a transcoded Protocol Buffer service.
@@ -67,6 +91,11 @@ type JSONSchemaGenerator struct {
 	conf   Configuration
 	plugin *protogen.Plugin
 
+	// versions are the $schema URLs requested with the version parameter.
+	versions []string
+	// version is the $schema URL of the schemas that are being built.
+	version string
+
 	linterRulePattern *regexp.Regexp
 }
 
@@ -78,9 +107,13 @@ func NewJSONSchemaGenerator(plugin *protogen.Plugin, conf Configuration) *JSONSc
 	}
 	conf.BaseURL = &baseURL
 
+	versions := schemaVersions(*conf.Version)
+
 	return &JSONSchemaGenerator{
-		conf:   conf,
-		plugin: plugin,
+		conf:     conf,
+		plugin:   plugin,
+		versions: versions,
+		version:  versions[0],
 
 		linterRulePattern: regexp.MustCompile(`\(-- .* --\)`),
 	}
@@ -88,12 +121,38 @@ func NewJSONSchemaGenerator(plugin *protogen.Plugin, conf Configuration) *JSONSc
 
 // Run runs the generator.
 func (g *JSONSchemaGenerator) Run() error {
-	for _, file := range g.plugin.Files {
-		if file.Generate {
-			schemas := g.buildSchemasFromMessages(file.Messages)
-			for _, schema := range schemas {
-				outputFile := g.plugin.NewGeneratedFile(fmt.Sprintf("%s.json", schema.Name), "")
-				outputFile.Write([]byte(schema.Value.JSONString()))
+	if g.conf.OutputFormat != nil && *g.conf.OutputFormat == "openapi3" {
+		bytes, err := g.openAPIDocument()
+		if err != nil {
+			return err
+		}
+		outputFile := g.plugin.NewGeneratedFile("openapi.yaml", "")
+		outputFile.Write(bytes)
+		return nil
+	}
+
+	if g.conf.OutputFormat != nil && *g.conf.OutputFormat == "dts" {
+		for _, file := range g.plugin.Files {
+			if file.Generate {
+				for _, schema := range g.buildSchemasFromMessages(file.Messages) {
+					outputFile := g.plugin.NewGeneratedFile(fmt.Sprintf("%s.d.ts", g.formatMessageNameString(schema.Name)), "")
+					outputFile.Write([]byte(g.typeScriptDeclarations(schema)))
+				}
+			}
+		}
+		return nil
+	}
+
+	// When more than one version is requested, the schemas for each version
+	// are written to a directory named after the draft.
+	for _, version := range g.versions {
+		g.version = version
+		for _, file := range g.plugin.Files {
+			if file.Generate {
+				for _, schema := range g.buildSchemasFromMessages(file.Messages) {
+					outputFile := g.plugin.NewGeneratedFile(g.schemaPath(schema.Name), "")
+					outputFile.Write([]byte(schema.Value.JSONString()))
+				}
 			}
 		}
 	}
@@ -101,6 +160,60 @@ func (g *JSONSchemaGenerator) Run() error {
 	return nil
 }
 
+// schemaVersionURLs maps the short names of JSON Schema drafts to their $schema URLs.
+var schemaVersionURLs = map[string]string{
+	"draft-04": "http://json-schema.org/draft-04/schema#",
+	"draft-06": "http://json-schema.org/draft-06/schema#",
+	"draft-07": "http://json-schema.org/draft-07/schema#",
+	"2019-09":  "https://json-schema.org/draft/2019-09/schema",
+	"2020-12":  "https://json-schema.org/draft/2020-12/schema",
+}
+
+// schemaVersions returns the $schema URLs of a comma-separated list of
+// versions. Each version is either a $schema URL or the short name of a draft.
+func schemaVersions(value string) []string {
+	versions := []string{}
+	for _, version := range strings.Split(value, ",") {
+		version = strings.TrimSpace(version)
+		if versionURL, ok := schemaVersionURLs[version]; ok {
+			version = versionURL
+		}
+		if version != "" {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		versions = append(versions, "")
+	}
+	return versions
+}
+
+// draftName returns the short name of the draft of a $schema URL,
+// e.g. "draft-07" or "2020-12".
+func draftName(version string) string {
+	for name, versionURL := range schemaVersionURLs {
+		if versionURL == version {
+			return name
+		}
+	}
+	if matches := reSchemaVersion.FindStringSubmatch(version); len(matches) == 2 {
+		if strings.Contains(matches[1], "-") {
+			return matches[1]
+		}
+		return "draft-" + matches[1]
+	}
+	return url.PathEscape(version)
+}
+
+// schemaPath returns the path of the schema file for a message,
+// relative to the output directory and to the base URL.
+func (g *JSONSchemaGenerator) schemaPath(schemaName string) string {
+	if len(g.versions) > 1 {
+		return fmt.Sprintf("%s/%s.json", draftName(g.version), schemaName)
+	}
+	return fmt.Sprintf("%s.json", schemaName)
+}
+
 // filterCommentString removes line breaks and linter rules from comments.
 func (g *JSONSchemaGenerator) filterCommentString(c protogen.Comments, removeNewLines bool) string {
 	comment := string(c)
@@ -166,6 +279,29 @@ func messageDefinitionName(desc protoreflect.MessageDescriptor) string {
 	return strings.Replace(name, ".", "_", -1)
 }
 
+// nullableSchema returns a schema that accepts null or values of the given schema.
+// Wrapper types like google.protobuf.BoolValue are represented this way.
+func nullableSchema(schema *jsonschema.Schema) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		OneOf: &[]*jsonschema.Schema{
+			{Type: &jsonschema.StringOrStringArray{String: &typeNull}},
+			schema,
+		},
+	}
+}
+
+// optionalSchema returns a schema that also accepts null, the JSON value of
+// an absent proto3 optional field. Scalar types get null as a second type,
+// references and enumerations are wrapped with nullableSchema.
+func optionalSchema(schema *jsonschema.Schema) *jsonschema.Schema {
+	if schema.Ref != nil || schema.Enumeration != nil || schema.Type == nil || schema.Type.String == nil {
+		return nullableSchema(schema)
+	}
+	schema.Type = &jsonschema.StringOrStringArray{StringArray: &[]string{*schema.Type.String, typeNull}}
+	schema.Default = &jsonschema.DefaultValue{NullTag: true}
+	return schema
+}
+
 func (g *JSONSchemaGenerator) schemaOrReferenceForType(desc protoreflect.MessageDescriptor) *jsonschema.Schema {
 	// Create the full typeName
 	typeName := fmt.Sprintf(".%s.%s", desc.ParentFile().Package(), desc.Name())
@@ -174,7 +310,11 @@ func (g *JSONSchemaGenerator) schemaOrReferenceForType(desc protoreflect.Message
 
 	case ".google.protobuf.Timestamp":
 		// Timestamps are serialized as strings
-		return &jsonschema.Schema{Type: &jsonschema.StringOrStringArray{String: &typeString}, Format: &formatDateTime}
+		format := formatDateTime
+		if g.conf.TimestampFormat != nil && *g.conf.TimestampFormat != "" {
+			format = *g.conf.TimestampFormat
+		}
+		return &jsonschema.Schema{Type: &jsonschema.StringOrStringArray{String: &typeString}, Format: &format}
 
 	case ".google.type.Date":
 		// Dates are serialized as strings
@@ -199,6 +339,25 @@ func (g *JSONSchemaGenerator) schemaOrReferenceForType(desc protoreflect.Message
 	case ".google.protobuf.Empty":
 		// Empty is close to JSON undefined than null, so ignore this field
 		return nil
+
+	case ".google.protobuf.BoolValue":
+		return nullableSchema(&jsonschema.Schema{Type: &jsonschema.StringOrStringArray{String: &typeBoolean}})
+
+	case ".google.protobuf.StringValue":
+		return nullableSchema(&jsonschema.Schema{Type: &jsonschema.StringOrStringArray{String: &typeString}})
+
+	case ".google.protobuf.BytesValue":
+		return nullableSchema(&jsonschema.Schema{Type: &jsonschema.StringOrStringArray{String: &typeString}, Format: &formatBytes})
+
+	case ".google.protobuf.Int32Value", ".google.protobuf.UInt32Value",
+		".google.protobuf.Int64Value", ".google.protobuf.UInt64Value":
+		// Use the same formats as the corresponding scalar fields, e.g. "int32" for Int32Value
+		format := strings.ToLower(strings.TrimSuffix(string(desc.Name()), "Value"))
+		return nullableSchema(&jsonschema.Schema{Type: &jsonschema.StringOrStringArray{String: &typeInteger}, Format: &format})
+
+	case ".google.protobuf.FloatValue", ".google.protobuf.DoubleValue":
+		format := strings.ToLower(strings.TrimSuffix(string(desc.Name()), "Value"))
+		return nullableSchema(&jsonschema.Schema{Type: &jsonschema.StringOrStringArray{String: &typeNumber}, Format: &format})
 	}
 
 	typeName = messageDefinitionName(desc)
@@ -251,6 +410,10 @@ func (g *JSONSchemaGenerator) schemaOrReferenceForField(field protoreflect.Field
 					kindSchema.Default = &jsonschema.DefaultValue{StringValue: &name}
 				}
 			}
+			if g.conf.EnumDescriptions != nil && *g.conf.EnumDescriptions && g.versionAtLeast("06") {
+				kindSchema.Enumeration = nil
+				kindSchema.OneOf = g.enumValueSchemas(field.Enum())
+			}
 		} else {
 			kindSchema.Type = &jsonschema.StringOrStringArray{String: &typeInteger}
 			kindSchema.Default = &jsonschema.DefaultValue{Int64Value: &emptyInt64}
@@ -267,7 +430,7 @@ func (g *JSONSchemaGenerator) schemaOrReferenceForField(field protoreflect.Field
 		kindSchema = &jsonschema.Schema{Type: &jsonschema.StringOrStringArray{String: &typeString}, Format: &formatBytes, Default: &jsonschema.DefaultValue{StringValue: &emptyString}}
 
 	default:
-		log.Printf("(TODO) Unsupported field type: %+v", field.Message().FullName())
+		g.conf.logger().Warnf("(TODO) Unsupported field type: %+v", field.Message().FullName())
 	}
 
 	if field.IsList() {
@@ -285,33 +448,38 @@ func (g *JSONSchemaGenerator) schemaOrReferenceForField(field protoreflect.Field
 }
 
 func (g *JSONSchemaGenerator) namedSchemaForField(field *protogen.Field, schema *jsonschema.NamedSchema, isValueProp bool) *jsonschema.NamedSchema {
+	// Fields of skipped messages would refer to schemas that don't exist.
+	if refersToSkippedMessage(field.Desc) {
+		return nil
+	}
+
 	// The field is either described by a reference or a schema.
 	fieldSchema := g.schemaOrReferenceForField(field.Desc, schema.Value.Definitions)
 	if fieldSchema == nil {
 		return nil
 	}
 
+	// Fields with the proto3 optional keyword are null when they are absent.
+	if field.Desc.HasOptionalKeyword() {
+		fieldSchema = optionalSchema(fieldSchema)
+	}
+
 	// Handle readonly and writeonly properties, if the schema version can handle it.
 	if getSchemaVersion(schema.Value) >= "07" {
 		t := true
 		// Check the field annotations to see if this is a readonly field.
-		extension := proto.GetExtension(field.Desc.Options(), annotations.E_FieldBehavior)
-		if extension != nil {
-			switch v := extension.(type) {
-			case []annotations.FieldBehavior:
-				for _, vv := range v {
-					if vv == annotations.FieldBehavior_OUTPUT_ONLY {
-						fieldSchema.ReadOnly = &t
-					} else if vv == annotations.FieldBehavior_INPUT_ONLY {
-						fieldSchema.WriteOnly = &t
-					}
-				}
-			default:
-				log.Printf("unsupported extension type %T", extension)
-			}
+		behavior := fieldbehavior.ForField(field.Desc)
+		if behavior.OutputOnly {
+			fieldSchema.ReadOnly = &t
+		}
+		if behavior.InputOnly {
+			fieldSchema.WriteOnly = &t
 		}
 	}
 
+	// Add constraints from field options.
+	addFieldConstraints(field.Desc, fieldSchema)
+
 	fieldName := "value"
 	if !isValueProp {
 		fieldName = g.formatFieldName(field)
@@ -322,8 +490,18 @@ func (g *JSONSchemaGenerator) namedSchemaForField(field *protogen.Field, schema
 		fieldSchema.Title = &fieldName
 	}
 
+	// Examples are taken from the comments if requested and supported by the schema version.
+	comments := field.Comments.Leading
+	if g.conf.ParseExamples != nil && *g.conf.ParseExamples && getSchemaVersion(schema.Value) >= "06" {
+		var examples []string
+		comments, examples = splitExamples(comments)
+		if len(examples) > 0 {
+			fieldSchema.Examples = examplesForField(field.Desc, examples)
+		}
+	}
+
 	// Get the field description from the comments.
-	description := g.filterCommentString(field.Comments.Leading, true)
+	description := g.filterCommentString(comments, true)
 	if description != "" {
 		// Note: Description will be ignored if $ref is set, but is still useful
 		fieldSchema.Description = &description
@@ -335,14 +513,79 @@ func (g *JSONSchemaGenerator) namedSchemaForField(field *protogen.Field, schema
 	}
 }
 
+var reExample = regexp.MustCompile(`^\s*Example:\s*(.*?)\s*$`)
+
+// splitExamples removes lines like "Example: shelves/1" from comments
+// and returns the remaining comments and the examples.
+func splitExamples(c protogen.Comments) (protogen.Comments, []string) {
+	var lines, examples []string
+	for _, line := range strings.Split(string(c), "\n") {
+		if matches := reExample.FindStringSubmatch(line); matches != nil {
+			examples = append(examples, matches[1])
+		} else {
+			lines = append(lines, line)
+		}
+	}
+	return protogen.Comments(strings.Join(lines, "\n")), examples
+}
+
+// enumValueSchemas returns a schema for each value of a string enum
+// that only accepts the name of the value and is described by its comment.
+func (g *JSONSchemaGenerator) enumValueSchemas(enum protoreflect.EnumDescriptor) *[]*jsonschema.Schema {
+	values := enum.Values()
+	schemas := make([]*jsonschema.Schema, 0, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		value := values.Get(i)
+		name := string(value.Name())
+		schema := &jsonschema.Schema{Const: &jsonschema.SchemaEnumValue{String: &name}, Title: &name}
+		comments := value.ParentFile().SourceLocations().ByDescriptor(value).LeadingComments
+		if description := g.filterCommentString(protogen.Comments(comments), false); description != "" {
+			schema.Description = &description
+		}
+		schemas = append(schemas, schema)
+	}
+	return &schemas
+}
+
+// examplesForField returns the values of examples of a field. Examples
+// of bool and numeric fields are booleans and numbers, all others are strings.
+func examplesForField(field protoreflect.FieldDescriptor, examples []string) *[]*jsonschema.DefaultValue {
+	values := make([]*jsonschema.DefaultValue, 0, len(examples))
+	for i := range examples {
+		switch field.Kind() {
+		case protoreflect.BoolKind:
+			if b, err := strconv.ParseBool(examples[i]); err == nil {
+				values = append(values, &jsonschema.DefaultValue{BooleanValue: &b})
+				continue
+			}
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Uint32Kind,
+			protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Uint64Kind,
+			protoreflect.Sfixed32Kind, protoreflect.Fixed32Kind, protoreflect.Sfixed64Kind,
+			protoreflect.Fixed64Kind:
+			if n, err := strconv.ParseInt(examples[i], 10, 64); err == nil {
+				values = append(values, &jsonschema.DefaultValue{Int64Value: &n})
+				continue
+			}
+		case protoreflect.FloatKind, protoreflect.DoubleKind:
+			if f, err := strconv.ParseFloat(examples[i], 64); err == nil {
+				values = append(values, &jsonschema.DefaultValue{Float64Value: &f})
+				continue
+			}
+		}
+		values = append(values, &jsonschema.DefaultValue{StringValue: &examples[i]})
+	}
+	return &values
+}
+
 func (g *JSONSchemaGenerator) setupSchemaForMessage(schemaName string, comments protogen.Comments) *jsonschema.NamedSchema {
 	typ := "object"
-	id := fmt.Sprintf("%s%s.json", *g.conf.BaseURL, schemaName)
+	id := *g.conf.BaseURL + g.schemaPath(schemaName)
+	version := g.version
 
 	schema := &jsonschema.NamedSchema{
 		Name: schemaName,
 		Value: &jsonschema.Schema{
-			Schema:     g.conf.Version,
+			Schema:     &version,
 			ID:         &id,
 			Type:       &jsonschema.StringOrStringArray{String: &typ},
 			Title:      &schemaName,
@@ -363,16 +606,12 @@ func (g *JSONSchemaGenerator) buildKindProperty(propertyValue string) *jsonschem
 	kindProperty := &jsonschema.NamedSchema{
 		Name: kind,
 		Value: &jsonschema.Schema{
-			Title:       &kind,
-			Type:        &jsonschema.StringOrStringArray{String: &typeString},
-			Enumeration: &[]jsonschema.SchemaEnumValue{},
-			Default:     &jsonschema.DefaultValue{StringValue: &propertyValue},
+			Title:   &kind,
+			Type:    &jsonschema.StringOrStringArray{String: &typeString},
+			Const:   &jsonschema.SchemaEnumValue{String: &propertyValue},
+			Default: &jsonschema.DefaultValue{StringValue: &propertyValue},
 		},
 	}
-	*kindProperty.Value.Enumeration = append(
-		*kindProperty.Value.Enumeration,
-		jsonschema.SchemaEnumValue{String: &propertyValue},
-	)
 	return kindProperty
 }
 
@@ -382,6 +621,11 @@ func (g *JSONSchemaGenerator) addOneofFieldsToSchema(oneofs []*protogen.Oneof, s
 	}
 
 	for _, oneOfProto := range oneofs {
+		// Fields with the proto3 optional keyword are in synthetic oneofs,
+		// but they are represented as nullable fields.
+		if oneOfProto.Desc.IsSynthetic() {
+			continue
+		}
 		oneOfSchema := jsonschema.Schema{
 			OneOf:   &[]*jsonschema.Schema{},
 			Default: &jsonschema.DefaultValue{NullTag: true},
@@ -411,10 +655,7 @@ func (g *JSONSchemaGenerator) addOneofFieldsToSchema(oneofs []*protogen.Oneof, s
 				actualProperty,
 			)
 
-			if schema.Value.Definitions == nil {
-				schema.Value.Definitions = &[]*jsonschema.NamedSchema{}
-			}
-			*schema.Value.Definitions = append(*schema.Value.Definitions, oneofFieldSchema)
+			schema.Value.AddDefinition(oneofFieldSchema.Name, oneofFieldSchema.Value)
 
 			definitionsRef := "#/definitions/" + ref
 			*oneOfSchema.OneOf = append(*oneOfSchema.OneOf, &jsonschema.Schema{Ref: &definitionsRef})
@@ -430,12 +671,100 @@ func (g *JSONSchemaGenerator) addOneofFieldsToSchema(oneofs []*protogen.Oneof, s
 	}
 }
 
-// buildSchemasFromMessages creates a schema for each message.
+// codegenSkipExtension is the extension that excludes a message from schema generation.
+const codegenSkipExtension = "x-codegen-skip"
+
+var reCodegenSkip = regexp.MustCompile(`(?m)^\s*x-codegen-skip(:\s*true)?\s*$`)
+
+// skipMessage returns true if a message or a message that contains it is
+// excluded from schema generation, either with an x-codegen-skip: true
+// extension in an (openapi.v3.schema) option or with a line that reads
+// "x-codegen-skip" or "x-codegen-skip: true" in its leading comments.
+func skipMessage(desc protoreflect.MessageDescriptor) bool {
+	for d := protoreflect.Descriptor(desc); d != nil; d = d.Parent() {
+		message, ok := d.(protoreflect.MessageDescriptor)
+		if !ok {
+			return false
+		}
+		if schema, ok := proto.GetExtension(message.Options(), v3.E_Schema).(*v3.Schema); ok && schema != nil {
+			for _, extension := range schema.SpecificationExtension {
+				if extension.Name == codegenSkipExtension && strings.TrimSpace(extension.GetValue().GetYaml()) == "true" {
+					return true
+				}
+			}
+		}
+		comments := message.ParentFile().SourceLocations().ByDescriptor(message).LeadingComments
+		if reCodegenSkip.MatchString(comments) {
+			return true
+		}
+	}
+	return false
+}
+
+// refersToSkippedMessage returns true if the type of a field, or the value
+// type of a map field, is a skipped message.
+func refersToSkippedMessage(field protoreflect.FieldDescriptor) bool {
+	if field.IsMap() {
+		field = field.MapValue()
+	}
+	return field.Message() != nil && skipMessage(field.Message())
+}
+
+// isResource returns true if a message has a google.api.resource option.
+func isResource(desc protoreflect.MessageDescriptor) bool {
+	resource, ok := proto.GetExtension(desc.Options(), annotations.E_Resource).(*annotations.ResourceDescriptor)
+	return ok && resource != nil
+}
+
+// baseResourceExtension marks the field of a resource message that holds
+// the base resource that the message extends.
+const baseResourceExtension = "x-base-resource"
+
+var reBaseResource = regexp.MustCompile(`(?m)^\s*x-base-resource(:\s*true)?\s*$`)
+
+// isMarkedBaseResource returns true if a field is marked as the base
+// resource of its message, either with an x-base-resource: true extension
+// in an (openapi.v3.property) option or with a line that reads
+// "x-base-resource" or "x-base-resource: true" in its leading comments.
+func isMarkedBaseResource(field *protogen.Field) bool {
+	if property, ok := proto.GetExtension(field.Desc.Options(), v3.E_Property).(*v3.Schema); ok && property != nil {
+		for _, extension := range property.SpecificationExtension {
+			if extension.Name == baseResourceExtension && strings.TrimSpace(extension.GetValue().GetYaml()) == "true" {
+				return true
+			}
+		}
+	}
+	return reBaseResource.MatchString(string(field.Comments.Leading))
+}
+
+// baseResourceField returns the field of a resource message that holds the
+// base resource that the message extends, or nil. The base is the first
+// field of the message if it is marked with x-base-resource and is a
+// singular field whose type is another resource message.
+func baseResourceField(message *protogen.Message) *protogen.Field {
+	if !isResource(message.Desc) || len(message.Fields) == 0 {
+		return nil
+	}
+	field := message.Fields[0]
+	if !isMarkedBaseResource(field) {
+		return nil
+	}
+	if field.Message == nil || field.Desc.IsList() || field.Desc.IsMap() || field.Oneof != nil ||
+		field.Message.Desc == message.Desc || !isResource(field.Message.Desc) || skipMessage(field.Message.Desc) {
+		return nil
+	}
+	return field
+}
+
+// buildSchemasFromMessages creates a schema for each message that isn't skipped.
 func (g *JSONSchemaGenerator) buildSchemasFromMessages(messages []*protogen.Message) []*jsonschema.NamedSchema {
 	schemas := []*jsonschema.NamedSchema{}
 
 	// For each message, generate a schema.
 	for _, message := range messages {
+		if skipMessage(message.Desc) {
+			continue
+		}
 		schemaName := messageDefinitionName(message.Desc)
 		schema := g.setupSchemaForMessage(schemaName, message.Comments.Leading)
 
@@ -453,8 +782,17 @@ func (g *JSONSchemaGenerator) buildSchemasFromMessages(messages []*protogen.Mess
 
 		g.addOneofFieldsToSchema(message.Oneofs, schema)
 
+		// Resources that extend a base resource are composed with its schema.
+		base := baseResourceField(message)
+		if base != nil {
+			schema.Value.AllOf = &[]*jsonschema.Schema{g.schemaOrReferenceForType(base.Message.Desc)}
+		}
+
 		for _, field := range message.Fields {
-			if field.Oneof != nil {
+			if field == base {
+				continue
+			}
+			if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() {
 				continue
 			}
 
@@ -477,6 +815,15 @@ func (g *JSONSchemaGenerator) buildSchemasFromMessages(messages []*protogen.Mess
 
 var reSchemaVersion = regexp.MustCompile(`https*://json-schema.org/draft[/-]([^/]+)/schema`)
 
+// versionAtLeast returns true if the schemas that are being built use a
+// draft that is the same as or newer than a draft, e.g. "06".
+func (g *JSONSchemaGenerator) versionAtLeast(draft string) bool {
+	matches := reSchemaVersion.FindStringSubmatch(g.version)
+	return len(matches) == 2 && matches[1] >= draft
+}
+
+// getSchemaVersion returns the draft of a schema, e.g. "07" or "2020-12".
+// Drafts compare in release order as strings.
 func getSchemaVersion(schema *jsonschema.Schema) string {
 	schemaSchema := *schema.Schema
 	matches := reSchemaVersion.FindStringSubmatch(schemaSchema)
@@ -0,0 +1,499 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/jsonschema"
+)
+
+const componentsSchemasPrefix = "#/components/schemas/"
+
+var rePathParameter = regexp.MustCompile(`{([^}=]+)(=[^}]*)?}`)
+
+// openAPIWriter builds an OpenAPI 3.0 document from the schemas of messages.
+type openAPIWriter struct {
+	g          *JSONSchemaGenerator
+	schemas    []*yaml.Node
+	names      map[string]bool
+	references []string
+	paths      []*yaml.Node
+}
+
+// openAPIDocument returns an OpenAPI 3.0 document that contains the schemas of
+// all messages in the generated files under components.schemas, along with the
+// messages that they refer to. Methods with google.api.http bindings are added
+// to paths with their request and response bodies.
+func (g *JSONSchemaGenerator) openAPIDocument() ([]byte, error) {
+	w := &openAPIWriter{g: g, names: make(map[string]bool)}
+	title := ""
+	services := 0
+	for _, file := range g.plugin.Files {
+		if !file.Generate {
+			continue
+		}
+		w.addSchemas(g.buildSchemasFromMessages(file.Messages))
+		for _, service := range file.Services {
+			if w.addPaths(service) {
+				title = service.GoName + " API"
+				services++
+			}
+		}
+	}
+	if services != 1 {
+		title = ""
+	}
+
+	// Add the schemas of messages that are only referenced.
+	messages := make(map[string]*protogen.Message)
+	for _, file := range g.plugin.Files {
+		g.indexMessages(file.Messages, messages)
+	}
+	for len(w.references) > 0 {
+		name := w.references[0]
+		w.references = w.references[1:]
+		if w.names[name] {
+			continue
+		}
+		if message, ok := messages[name]; ok {
+			w.addSchemas(g.buildSchemasFromMessages([]*protogen.Message{message}))
+		}
+	}
+
+	document := nodeForMapping(
+		nodeForString("openapi"), nodeForString("3.0.3"),
+		nodeForString("info"), nodeForMapping(
+			nodeForString("title"), nodeForString(title),
+			nodeForString("version"), nodeForString("0.0.1"),
+		),
+		nodeForString("paths"), nodeForMapping(w.paths...),
+		nodeForString("components"), nodeForMapping(
+			nodeForString("schemas"), nodeForMapping(w.schemas...),
+		),
+	)
+	bytes, err := yaml.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	header := "# Generated with protoc-gen-jsonschema\n# https://github.com/google/gnostic/tree/master/cmd/protoc-gen-jsonschema\n\n"
+	return append([]byte(header), bytes...), nil
+}
+
+// indexMessages adds messages and their nested messages to an index by schema name.
+func (g *JSONSchemaGenerator) indexMessages(messages []*protogen.Message, index map[string]*protogen.Message) {
+	for _, message := range messages {
+		index[g.formatMessageNameString(messageDefinitionName(message.Desc))] = message
+		g.indexMessages(message.Messages, index)
+	}
+}
+
+// addSchemas adds message schemas and their definitions to components.schemas.
+func (w *openAPIWriter) addSchemas(schemas []*jsonschema.NamedSchema) {
+	for _, schema := range schemas {
+		w.addSchema(w.g.formatMessageNameString(schema.Name), schema.Value)
+		if schema.Value.Definitions != nil {
+			for _, definition := range *schema.Value.Definitions {
+				w.addSchema(definition.Name, definition.Value)
+			}
+		}
+	}
+}
+
+func (w *openAPIWriter) addSchema(name string, schema *jsonschema.Schema) {
+	if w.names[name] {
+		return
+	}
+	w.names[name] = true
+	w.schemas = append(w.schemas, nodeForString(name), w.schemaNode(schema))
+}
+
+// schemaNode returns an OpenAPI 3.0 representation of a JSON schema.
+func (w *openAPIWriter) schemaNode(schema *jsonschema.Schema) *yaml.Node {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(schema.YAMLString()), &document); err != nil || len(document.Content) == 0 {
+		return nodeForMapping()
+	}
+	node := document.Content[0]
+	w.convertSchemaNode(node)
+	return node
+}
+
+// convertSchemaNode rewrites a JSON schema in place to use the subset of JSON
+// Schema that is supported by OpenAPI 3.0. References to message schemas
+// and definitions are changed to refer to components.schemas, "null" types
+// are replaced with nullable, type arrays are replaced with anyOf and const
+// is replaced with a single-valued enum. Alternatives that each accept a
+// single described value are replaced with an enum and the x-enum-varnames
+// and x-enum-descriptions extensions.
+func (w *openAPIWriter) convertSchemaNode(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	content := make([]*yaml.Node, 0, len(node.Content))
+	nullable := false
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch key.Value {
+		case "$id", "$schema", "definitions":
+			continue
+		case "$ref":
+			value = nodeForString(w.componentReference(value.Value))
+		case "const":
+			key = nodeForString("enum")
+			value = nodeForSequence(value)
+		case "type":
+			if value.Kind == yaml.SequenceNode {
+				types := make([]*yaml.Node, 0)
+				for _, t := range value.Content {
+					if t.Value == typeNull {
+						nullable = true
+					} else {
+						types = append(types, t)
+					}
+				}
+				if len(types) == 1 {
+					value = types[0]
+				} else {
+					alternatives := make([]*yaml.Node, 0)
+					for _, t := range types {
+						alternatives = append(alternatives, nodeForMapping(nodeForString("type"), t))
+					}
+					key = nodeForString("anyOf")
+					value = nodeForSequence(alternatives...)
+				}
+			} else if value.Value == typeNull {
+				nullable = true
+				continue
+			}
+		case "oneOf", "anyOf", "allOf":
+			if enum := enumNodes(value); enum != nil && key.Value != "allOf" {
+				content = append(content, enum...)
+				continue
+			}
+			alternatives := make([]*yaml.Node, 0)
+			for _, alternative := range value.Content {
+				if isNullSchemaNode(alternative) {
+					nullable = true
+					continue
+				}
+				w.convertSchemaNode(alternative)
+				alternatives = append(alternatives, alternative)
+			}
+			if len(alternatives) == 1 && key.Value != "allOf" {
+				if mappingValue(alternatives[0], "$ref") == nil {
+					// Inline a single remaining schema.
+					content = append(content, alternatives[0].Content...)
+					continue
+				}
+				key = nodeForString("allOf")
+			}
+			value = nodeForSequence(alternatives...)
+		case "properties", "patternProperties":
+			for j := 1; j < len(value.Content); j += 2 {
+				w.convertSchemaNode(value.Content[j])
+			}
+		case "items":
+			if value.Kind == yaml.SequenceNode {
+				for _, item := range value.Content {
+					w.convertSchemaNode(item)
+				}
+			} else {
+				w.convertSchemaNode(value)
+			}
+		case "additionalProperties", "not":
+			w.convertSchemaNode(value)
+		}
+		content = append(content, key, value)
+	}
+	if nullable {
+		content = append(content, nodeForString("nullable"), nodeForBoolean(true))
+	}
+	node.Content = content
+}
+
+// enumNodes returns the keys and values of an enum for a list of schemas
+// that only have a const, a title and a description. The titles become
+// x-enum-varnames if all schemas have one, and the descriptions become
+// x-enum-descriptions if any schema has one. It returns nil if any schema
+// has other keywords.
+func enumNodes(alternatives *yaml.Node) []*yaml.Node {
+	if alternatives.Kind != yaml.SequenceNode || len(alternatives.Content) == 0 {
+		return nil
+	}
+	values := make([]*yaml.Node, 0)
+	names := make([]*yaml.Node, 0)
+	descriptions := make([]*yaml.Node, 0)
+	described := false
+	for _, alternative := range alternatives.Content {
+		value := mappingValue(alternative, "const")
+		if value == nil {
+			return nil
+		}
+		for i := 0; i < len(alternative.Content); i += 2 {
+			switch alternative.Content[i].Value {
+			case "const", "title", "description":
+			default:
+				return nil
+			}
+		}
+		values = append(values, value)
+		if title := mappingValue(alternative, "title"); title != nil {
+			names = append(names, title)
+		}
+		if description := mappingValue(alternative, "description"); description != nil {
+			descriptions = append(descriptions, description)
+			described = true
+		} else {
+			descriptions = append(descriptions, nodeForString(""))
+		}
+	}
+	content := []*yaml.Node{nodeForString("enum"), nodeForSequence(values...)}
+	if len(names) == len(values) {
+		content = append(content, nodeForString("x-enum-varnames"), nodeForSequence(names...))
+	}
+	if described {
+		content = append(content, nodeForString("x-enum-descriptions"), nodeForSequence(descriptions...))
+	}
+	return content
+}
+
+// componentReference returns the components.schemas reference for a
+// reference to a message schema or to a definition.
+func (w *openAPIWriter) componentReference(ref string) string {
+	var name string
+	if strings.HasPrefix(ref, "#/definitions/") {
+		name = strings.TrimPrefix(ref, "#/definitions/")
+	} else {
+		name = strings.TrimSuffix(ref[strings.LastIndex(ref, "/")+1:], ".json")
+		w.references = append(w.references, name)
+	}
+	return componentsSchemasPrefix + name
+}
+
+// addPaths adds the methods of a service with HTTP bindings to paths and
+// returns true if the service has any. Each binding of a method, including
+// its additional_bindings, becomes an operation. Operations of additional
+// bindings have operationIds with the index of the binding as suffix.
+func (w *openAPIWriter) addPaths(service *protogen.Service) bool {
+	found := false
+	for _, method := range service.Methods {
+		rule, ok := proto.GetExtension(method.Desc.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil || rule.Pattern == nil {
+			continue
+		}
+		found = true
+		rules := append([]*annotations.HttpRule{rule}, rule.AdditionalBindings...)
+		for i, rule := range rules {
+			var path, methodName string
+			switch pattern := rule.Pattern.(type) {
+			case *annotations.HttpRule_Get:
+				path, methodName = pattern.Get, "get"
+			case *annotations.HttpRule_Post:
+				path, methodName = pattern.Post, "post"
+			case *annotations.HttpRule_Put:
+				path, methodName = pattern.Put, "put"
+			case *annotations.HttpRule_Delete:
+				path, methodName = pattern.Delete, "delete"
+			case *annotations.HttpRule_Patch:
+				path, methodName = pattern.Patch, "patch"
+			case *annotations.HttpRule_Custom:
+				// Only custom verbs that are also OpenAPI operations are supported.
+				path, methodName = pattern.Custom.GetPath(), strings.ToLower(pattern.Custom.GetKind())
+				if methodName != "head" && methodName != "options" && methodName != "trace" {
+					continue
+				}
+			default:
+				continue
+			}
+			operationID := service.GoName + "_" + method.GoName
+			if i > 0 {
+				operationID += "_" + strconv.Itoa(i)
+			}
+			// Bindings of a path and method that is already bound are skipped.
+			if !w.addOperation(rePathParameter.ReplaceAllString(path, "{$1}"), methodName, func() *yaml.Node {
+				return w.operationNode(service, method, operationID, path, rule)
+			}) {
+				w.g.conf.logger().Warnf("Skipping duplicate binding %s %s of %s", strings.ToUpper(methodName), path, method.Desc.FullName())
+			}
+		}
+	}
+	return found
+}
+
+// addOperation adds an operation to the path item for a path and returns
+// false if the path item already has an operation for the method.
+func (w *openAPIWriter) addOperation(path, methodName string, operation func() *yaml.Node) bool {
+	for i := 0; i+1 < len(w.paths); i += 2 {
+		if w.paths[i].Value == path {
+			item := w.paths[i+1]
+			if mappingValue(item, methodName) != nil {
+				return false
+			}
+			item.Content = append(item.Content, nodeForString(methodName), operation())
+			return true
+		}
+	}
+	w.paths = append(w.paths, nodeForString(path), nodeForMapping(nodeForString(methodName), operation()))
+	return true
+}
+
+// operationNode builds an operation for an HTTP binding of a method.
+func (w *openAPIWriter) operationNode(service *protogen.Service, method *protogen.Method, operationID, path string, rule *annotations.HttpRule) *yaml.Node {
+	g := w.g
+	content := []*yaml.Node{
+		nodeForString("tags"), nodeForSequence(nodeForString(service.GoName)),
+	}
+	if description := g.filterCommentString(method.Comments.Leading, false); description != "" {
+		content = append(content, nodeForString("description"), nodeForString(description))
+	}
+	content = append(content, nodeForString("operationId"), nodeForString(operationID))
+
+	// Fields that are bound to the path or the body are not query parameters.
+	covered := make(map[string]bool)
+	parameters := make([]*yaml.Node, 0)
+	for _, match := range rePathParameter.FindAllStringSubmatch(path, -1) {
+		name := match[1]
+		covered[name] = true
+		schema := nodeForMapping(nodeForString("type"), nodeForString(typeString))
+		if field := fieldWithName(method.Input, name); field != nil {
+			if fieldSchema := g.schemaOrReferenceForField(field.Desc, &[]*jsonschema.NamedSchema{}); fieldSchema != nil {
+				schema = w.schemaNode(fieldSchema)
+			}
+		}
+		parameters = append(parameters, nodeForMapping(
+			nodeForString("name"), nodeForString(name),
+			nodeForString("in"), nodeForString("path"),
+			nodeForString("required"), nodeForBoolean(true),
+			nodeForString("schema"), schema,
+		))
+	}
+	if rule.Body != "*" {
+		covered[rule.Body] = true
+		for _, field := range method.Input.Fields {
+			if covered[string(field.Desc.Name())] || field.Desc.Kind() == protoreflect.MessageKind || field.Desc.IsMap() {
+				continue
+			}
+			fieldSchema := g.schemaOrReferenceForField(field.Desc, &[]*jsonschema.NamedSchema{})
+			if fieldSchema == nil {
+				continue
+			}
+			parameters = append(parameters, nodeForMapping(
+				nodeForString("name"), nodeForString(g.formatFieldName(field)),
+				nodeForString("in"), nodeForString("query"),
+				nodeForString("schema"), w.schemaNode(fieldSchema),
+			))
+		}
+	}
+	if len(parameters) > 0 {
+		content = append(content, nodeForString("parameters"), nodeForSequence(parameters...))
+	}
+
+	if rule.Body != "" {
+		var schema *jsonschema.Schema
+		if rule.Body == "*" {
+			schema = g.schemaOrReferenceForType(method.Input.Desc)
+		} else if field := fieldWithName(method.Input, rule.Body); field != nil {
+			schema = g.schemaOrReferenceForField(field.Desc, &[]*jsonschema.NamedSchema{})
+		}
+		if schema != nil {
+			content = append(content, nodeForString("requestBody"), nodeForMapping(
+				nodeForString("content"), jsonContentNode(w.schemaNode(schema)),
+				nodeForString("required"), nodeForBoolean(true),
+			))
+		}
+	}
+
+	var schema *jsonschema.Schema
+	if rule.ResponseBody != "" {
+		if field := fieldWithName(method.Output, rule.ResponseBody); field != nil {
+			schema = g.schemaOrReferenceForField(field.Desc, &[]*jsonschema.NamedSchema{})
+		}
+	} else {
+		schema = g.schemaOrReferenceForType(method.Output.Desc)
+	}
+	response := []*yaml.Node{nodeForString("description"), nodeForString("OK")}
+	if schema != nil {
+		response = append(response, nodeForString("content"), jsonContentNode(w.schemaNode(schema)))
+	}
+	content = append(content, nodeForString("responses"), nodeForMapping(
+		nodeForString("200"), nodeForMapping(response...),
+	))
+	return nodeForMapping(content...)
+}
+
+// fieldWithName returns the field of a message with a proto name, or nil.
+func fieldWithName(message *protogen.Message, name string) *protogen.Field {
+	for _, field := range message.Fields {
+		if string(field.Desc.Name()) == name {
+			return field
+		}
+	}
+	return nil
+}
+
+func jsonContentNode(schema *yaml.Node) *yaml.Node {
+	return nodeForMapping(
+		nodeForString("application/json"), nodeForMapping(
+			nodeForString("schema"), schema,
+		),
+	)
+}
+
+// isNullSchemaNode returns true if a schema only accepts null.
+func isNullSchemaNode(node *yaml.Node) bool {
+	t := mappingValue(node, "type")
+	return t != nil && t.Value == typeNull && len(node.Content) == 2
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func nodeForMapping(content ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Content: content}
+}
+
+func nodeForSequence(content ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.SequenceNode, Content: content}
+}
+
+func nodeForString(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func nodeForBoolean(value bool) *yaml.Node {
+	if value {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"}
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/gnostic/jsonschema"
+)
+
+var reTypeScriptIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeScriptWriter renders a schema and its definitions as TypeScript declarations.
+type typeScriptWriter struct {
+	name    string
+	builder strings.Builder
+	imports map[string]bool
+}
+
+// typeScriptDeclarations returns a .d.ts file that declares an interface
+// for a message schema and for each of its definitions.
+func (g *JSONSchemaGenerator) typeScriptDeclarations(schema *jsonschema.NamedSchema) string {
+	w := &typeScriptWriter{name: g.formatMessageNameString(schema.Name), imports: make(map[string]bool)}
+	w.writeInterface(w.name, schema.Value)
+	if schema.Value.Definitions != nil {
+		for _, definition := range *schema.Value.Definitions {
+			w.builder.WriteString("\n")
+			w.writeInterface(definition.Name, definition.Value)
+		}
+	}
+
+	var header strings.Builder
+	header.WriteString("// Generated with protoc-gen-jsonschema. DO NOT EDIT.\n\n")
+	if len(w.imports) > 0 {
+		names := make([]string, 0, len(w.imports))
+		for name := range w.imports {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			header.WriteString(fmt.Sprintf("import { %s } from \"./%s\";\n", name, name))
+		}
+		header.WriteString("\n")
+	}
+	return header.String() + w.builder.String()
+}
+
+func (w *typeScriptWriter) writeInterface(name string, schema *jsonschema.Schema) {
+	if schema.Description != nil {
+		w.writeComment("", *schema.Description)
+	}
+	// Schemas that are composed with references extend their interfaces.
+	extends := ""
+	if schema.AllOf != nil {
+		bases := make([]string, 0)
+		for _, s := range *schema.AllOf {
+			if s.Ref != nil {
+				bases = append(bases, w.typeForSchema(s))
+			}
+		}
+		if len(bases) > 0 {
+			extends = " extends " + strings.Join(bases, ", ")
+		}
+	}
+	w.builder.WriteString(fmt.Sprintf("export interface %s%s {\n", name, extends))
+	if schema.Properties != nil {
+		for _, property := range *schema.Properties {
+			if property.Value.Description != nil {
+				w.writeComment("  ", *property.Value.Description)
+			}
+			optional := "?"
+			if isRequiredProperty(schema, property) {
+				optional = ""
+			}
+			w.builder.WriteString(fmt.Sprintf("  %s%s: %s;\n", typeScriptPropertyName(property.Name), optional, w.typeForSchema(property.Value)))
+		}
+	}
+	w.builder.WriteString("}\n")
+}
+
+func (w *typeScriptWriter) writeComment(indent string, text string) {
+	w.builder.WriteString(indent + "/** " + strings.Replace(text, "*/", "*\\/", -1) + " */\n")
+}
+
+// isRequiredProperty returns true for required properties and for the
+// "kind" discriminator of the definitions that represent oneof fields.
+func isRequiredProperty(schema *jsonschema.Schema, property *jsonschema.NamedSchema) bool {
+	if schema.Required != nil {
+		for _, name := range *schema.Required {
+			if name == property.Name {
+				return true
+			}
+		}
+	}
+	return property.Name == "kind" && property.Value.Const != nil
+}
+
+func typeScriptPropertyName(name string) string {
+	if reTypeScriptIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// typeForSchema returns a TypeScript type expression for a schema.
+func (w *typeScriptWriter) typeForSchema(schema *jsonschema.Schema) string {
+	if schema == nil {
+		return "unknown"
+	}
+	if schema.Ref != nil {
+		ref := *schema.Ref
+		if strings.HasPrefix(ref, "#/definitions/") {
+			return strings.TrimPrefix(ref, "#/definitions/")
+		}
+		name := strings.TrimSuffix(ref[strings.LastIndex(ref, "/")+1:], ".json")
+		if name != w.name {
+			w.imports[name] = true
+		}
+		return name
+	}
+	if schema.OneOf != nil {
+		types := make([]string, 0)
+		for _, s := range *schema.OneOf {
+			types = append(types, w.typeForSchema(s))
+		}
+		return strings.Join(types, " | ")
+	}
+	if schema.Const != nil && schema.Const.String != nil {
+		return strconv.Quote(*schema.Const.String)
+	}
+	if schema.Enumeration != nil && schema.TypeIs(typeString) {
+		literals := make([]string, 0)
+		for _, value := range *schema.Enumeration {
+			if value.String != nil {
+				literals = append(literals, strconv.Quote(*value.String))
+			}
+		}
+		if len(literals) > 0 {
+			return strings.Join(literals, " | ")
+		}
+	}
+	if schema.Type == nil {
+		return "unknown"
+	}
+	if schema.Type.StringArray != nil {
+		types := make([]string, 0)
+		for _, t := range *schema.Type.StringArray {
+			types = appendUnique(types, w.typeForTypeName(t, schema))
+		}
+		return strings.Join(types, " | ")
+	}
+	return w.typeForTypeName(*schema.Type.String, schema)
+}
+
+func (w *typeScriptWriter) typeForTypeName(typeName string, schema *jsonschema.Schema) string {
+	switch typeName {
+	case typeString:
+		return "string"
+	case typeNumber, typeInteger:
+		return "number"
+	case typeBoolean:
+		return "boolean"
+	case typeNull:
+		return "null"
+	case typeArray:
+		if schema.Items != nil && schema.Items.Schema != nil {
+			itemType := w.typeForSchema(schema.Items.Schema)
+			if strings.Contains(itemType, " | ") {
+				itemType = "(" + itemType + ")"
+			}
+			return itemType + "[]"
+		}
+		return "unknown[]"
+	case typeObject:
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			return "Record<string, " + w.typeForSchema(schema.AdditionalProperties.Schema) + ">"
+		}
+		return "Record<string, unknown>"
+	default:
+		return "unknown"
+	}
+}
+
+// appendUnique appends a string, to a string slice, if the string is not already in the slice
+func appendUnique(s []string, e string) []string {
+	for _, a := range s {
+		if a == e {
+			return s
+		}
+	}
+	return append(s, e)
+}
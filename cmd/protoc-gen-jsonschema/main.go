@@ -27,14 +27,27 @@ var flags flag.FlagSet
 
 func main() {
 	conf := generator.Configuration{
-		BaseURL:  flags.String("baseurl", "", "the base url to use in schema ids"),
-		Version:  flags.String("version", "http://json-schema.org/draft-07/schema#", "schema version URL used in $schema. Currently supported: draft-06, draft-07"),
-		Naming:   flags.String("naming", "json", `naming convention. Use "proto" for passing names directly from the proto files`),
-		EnumType: flags.String("enum_type", "integer", `type for enum serialization. Use "string" for string-based serialization`),
+		BaseURL:          flags.String("baseurl", "", "the base url to use in schema ids"),
+		Version:          flags.String("version", "http://json-schema.org/draft-07/schema#", "schema version URL used in $schema, or a comma-separated list of versions to write schemas for each of them into a directory per draft. Versions can be given as URLs or as draft-06, draft-07, 2019-09 or 2020-12"),
+		Naming:           flags.String("naming", "json", `naming convention. Use "proto" for passing names directly from the proto files`),
+		EnumType:         flags.String("enum_type", "integer", `type for enum serialization. Use "string" for string-based serialization`),
+		OutputFormat:     flags.String("output_format", "json", `output format. Use "dts" to generate TypeScript declarations (.d.ts) or "openapi3" to generate a single OpenAPI 3.0 document (openapi.yaml) instead of JSON Schemas`),
+		ParseExamples:    flags.Bool("parse_examples", false, `parse "Example: value" lines of field comments into "examples". Requires draft-06 or later`),
+		EnumDescriptions: flags.Bool("enum_descriptions", false, `describe the values of string enums with "oneOf" alternatives that have a "const", the name of the value as "title" and its comment as "description". Requires draft-06 or later`),
+		TimestampFormat:  flags.String("timestamp_format", "date-time", `format of google.protobuf.Timestamp fields, e.g. "date-time-utc" or "unix-timestamp"`),
 	}
 
+	lastParam := ""
 	opts := protogen.Options{
-		ParamFunc: flags.Set,
+		ParamFunc: func(name, value string) error {
+			// protoc separates parameters with commas, so the additional
+			// versions of "version=draft-07,2020-12" arrive without values.
+			if lastParam == "version" && value == "" && flags.Lookup(name) == nil {
+				return flags.Set("version", *conf.Version+","+name)
+			}
+			lastParam = name
+			return flags.Set(name, value)
+		},
 	}
 
 	opts.Run(func(plugin *protogen.Plugin) error {
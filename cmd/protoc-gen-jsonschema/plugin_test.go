@@ -22,6 +22,8 @@ import (
 	"testing"
 
 	"github.com/flowstack/go-jsonschema"
+
+	openapi_v3 "github.com/google/gnostic-models/openapiv3"
 )
 
 var (
@@ -40,6 +42,18 @@ var jsonschemaTests = []struct {
 	{name: "Embedded messages", path: "examples/tests/embedded/", pkg: "", protofile: "message.proto"},
 	{name: "Protobuf types", path: "examples/tests/protobuftypes/", pkg: "", protofile: "message.proto"},
 	{name: "Enum Options", path: "examples/tests/enumoptions/", pkg: "", protofile: "message.proto"},
+	{name: "Wrapper types", path: "examples/tests/wrappers/", pkg: "", protofile: "message.proto"},
+	{name: "Oneof fields", path: "examples/tests/oneofs/", pkg: "", protofile: "message.proto"},
+	{name: "Constraints", path: "examples/tests/constraints/", pkg: "", protofile: "message.proto"},
+	{name: "TypeScript declarations", path: "examples/tests/typescript/", pkg: "", protofile: "message.proto"},
+	{name: "OpenAPI 3.0 document", path: "examples/tests/openapi3/", pkg: "", protofile: "message.proto"},
+	{name: "Examples", path: "examples/tests/examples/", pkg: "", protofile: "message.proto"},
+	{name: "Drafts", path: "examples/tests/drafts/", pkg: "", protofile: "message.proto"},
+	{name: "Timestamps", path: "examples/tests/timestamps/", pkg: "", protofile: "message.proto"},
+	{name: "Codegen skip", path: "examples/tests/codegenskip/", pkg: "", protofile: "message.proto"},
+	{name: "Optional fields", path: "examples/tests/optional/", pkg: "", protofile: "message.proto"},
+	{name: "Enum descriptions", path: "examples/tests/enumdescriptions/", pkg: "", protofile: "message.proto"},
+	{name: "Resource inheritance", path: "examples/tests/inheritance/", pkg: "", protofile: "message.proto"},
 }
 
 func TestJSONSchemaProtobufNaming(t *testing.T) {
@@ -202,3 +216,227 @@ func TestJSONSchemaStringEnums(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONSchemaEnumDescriptions(t *testing.T) {
+	for _, tt := range jsonschemaTests {
+		for _, variant := range []struct {
+			dir     string
+			options []string
+		}{
+			{"schemas_enum_descriptions", []string{"--jsonschema_opt=baseurl=http://example.com/schemas"}},
+			{"schemas_openapi3_enum_descriptions", []string{"--jsonschema_opt=output_format=openapi3"}},
+		} {
+			schemasPath := path.Join(tt.path, variant.dir)
+			if _, err := os.Stat(schemasPath); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			t.Run(tt.name, func(t *testing.T) {
+				os.RemoveAll(testSchemasPath)
+				os.MkdirAll(testSchemasPath, 0777)
+				// Run protoc and the protoc-gen-jsonschema plugin to generate string enums with described values.
+				args := []string{
+					"-I", "../../",
+					"-I", "../../third_party",
+					"-I", "examples",
+					path.Join(tt.path, tt.protofile),
+					"--jsonschema_opt=enum_type=string",
+					"--jsonschema_opt=enum_descriptions=true",
+				}
+				args = append(args, variant.options...)
+				err := exec.Command("protoc", append(args, "--jsonschema_out="+testSchemasPath)...).Run()
+				if err != nil {
+					t.Fatalf("protoc failed: %+v", err)
+				}
+
+				// Verify that the generated schemas match our expected version.
+				err = exec.Command("diff", testSchemasPath, schemasPath).Run()
+				if err != nil {
+					t.Fatalf("Diff failed: %+v", err)
+				}
+
+				// if the test succeeded, clean up
+				os.RemoveAll(testSchemasPath)
+			})
+		}
+	}
+}
+
+func TestJSONSchemaExamples(t *testing.T) {
+	for _, tt := range jsonschemaTests {
+		schemasPath := path.Join(tt.path, "schemas_examples")
+		if _, err := os.Stat(schemasPath); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			os.RemoveAll(testSchemasPath)
+			os.MkdirAll(testSchemasPath, 0777)
+			// Run protoc and the protoc-gen-jsonschema plugin to generate JSON Schema(s) with examples.
+			err := exec.Command("protoc",
+				"-I", "../../",
+				"-I", "../../third_party",
+				"-I", "examples",
+				path.Join(tt.path, tt.protofile),
+				"--jsonschema_opt=baseurl=http://example.com/schemas",
+				"--jsonschema_opt=parse_examples=true",
+				"--jsonschema_out="+testSchemasPath).Run()
+			if err != nil {
+				t.Fatalf("protoc failed: %+v", err)
+			}
+
+			// Verify that the generated spec matches our expected version.
+			err = exec.Command("diff", testSchemasPath, schemasPath).Run()
+			if err != nil {
+				t.Fatalf("Diff failed: %+v", err)
+			}
+
+			// if the test succeeded, clean up
+			os.RemoveAll(testSchemasPath)
+		})
+	}
+}
+
+func TestJSONSchemaDrafts(t *testing.T) {
+	for _, tt := range jsonschemaTests {
+		schemasPath := path.Join(tt.path, "schemas_drafts")
+		if _, err := os.Stat(schemasPath); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			os.RemoveAll(testSchemasPath)
+			os.MkdirAll(testSchemasPath, 0777)
+			// Run protoc and the protoc-gen-jsonschema plugin to generate JSON Schema(s) for two drafts.
+			err := exec.Command("protoc",
+				"-I", "../../",
+				"-I", "../../third_party",
+				"-I", "examples",
+				path.Join(tt.path, tt.protofile),
+				"--jsonschema_opt=baseurl=http://example.com/schemas",
+				"--jsonschema_opt=parse_examples=true",
+				"--jsonschema_opt=version=draft-07,2020-12",
+				"--jsonschema_out="+testSchemasPath).Run()
+			if err != nil {
+				t.Fatalf("protoc failed: %+v", err)
+			}
+
+			// Verify that the generated specs match our expected versions.
+			err = exec.Command("diff", "-r", testSchemasPath, schemasPath).Run()
+			if err != nil {
+				t.Fatalf("Diff failed: %+v", err)
+			}
+
+			// if the test succeeded, clean up
+			os.RemoveAll(testSchemasPath)
+		})
+	}
+}
+
+func TestJSONSchemaTimestampFormat(t *testing.T) {
+	for _, tt := range jsonschemaTests {
+		schemasPath := path.Join(tt.path, "schemas_timestamp_format")
+		if _, err := os.Stat(schemasPath); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			os.RemoveAll(testSchemasPath)
+			os.MkdirAll(testSchemasPath, 0777)
+			// Run protoc and the protoc-gen-jsonschema plugin to generate JSON Schema(s) with a custom timestamp format.
+			err := exec.Command("protoc",
+				"-I", "../../",
+				"-I", "../../third_party",
+				"-I", "examples",
+				path.Join(tt.path, tt.protofile),
+				"--jsonschema_opt=baseurl=http://example.com/schemas",
+				"--jsonschema_opt=timestamp_format=unix-timestamp",
+				"--jsonschema_out="+testSchemasPath).Run()
+			if err != nil {
+				t.Fatalf("protoc failed: %+v", err)
+			}
+
+			// Verify that the generated spec matches our expected version.
+			err = exec.Command("diff", testSchemasPath, schemasPath).Run()
+			if err != nil {
+				t.Fatalf("Diff failed: %+v", err)
+			}
+
+			// if the test succeeded, clean up
+			os.RemoveAll(testSchemasPath)
+		})
+	}
+}
+
+func TestJSONSchemaTypeScript(t *testing.T) {
+	for _, tt := range jsonschemaTests {
+		schemasPath := path.Join(tt.path, "schemas_dts")
+		if _, err := os.Stat(schemasPath); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			os.RemoveAll(testSchemasPath)
+			os.MkdirAll(testSchemasPath, 0777)
+			// Run protoc and the protoc-gen-jsonschema plugin to generate TypeScript declarations.
+			err := exec.Command("protoc",
+				"-I", "../../",
+				"-I", "../../third_party",
+				"-I", "examples",
+				path.Join(tt.path, tt.protofile),
+				"--jsonschema_opt=baseurl=http://example.com/schemas",
+				"--jsonschema_opt=enum_type=string",
+				"--jsonschema_opt=output_format=dts",
+				"--jsonschema_out="+testSchemasPath).Run()
+			if err != nil {
+				t.Fatalf("protoc failed: %+v", err)
+			}
+
+			// Verify that the generated declarations match our expected version.
+			err = exec.Command("diff", testSchemasPath, schemasPath).Run()
+			if err != nil {
+				t.Fatalf("Diff failed: %+v", err)
+			}
+
+			// if the test succeeded, clean up
+			os.RemoveAll(testSchemasPath)
+		})
+	}
+}
+
+func TestJSONSchemaOpenAPI3(t *testing.T) {
+	for _, tt := range jsonschemaTests {
+		schemasPath := path.Join(tt.path, "schemas_openapi3")
+		if _, err := os.Stat(schemasPath); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			os.RemoveAll(testSchemasPath)
+			os.MkdirAll(testSchemasPath, 0777)
+			// Run protoc and the protoc-gen-jsonschema plugin to generate an OpenAPI 3.0 document.
+			err := exec.Command("protoc",
+				"-I", "../../",
+				"-I", "../../third_party",
+				"-I", "examples",
+				path.Join(tt.path, tt.protofile),
+				"--jsonschema_opt=output_format=openapi3",
+				"--jsonschema_out="+testSchemasPath).Run()
+			if err != nil {
+				t.Fatalf("protoc failed: %+v", err)
+			}
+
+			// Verify that the generated document is a valid OpenAPI 3.0 document.
+			bytes, err := os.ReadFile(path.Join(testSchemasPath, "openapi.yaml"))
+			if err != nil {
+				t.Fatalf("Can't read generated document: %+v", err)
+			}
+			if _, err = openapi_v3.ParseDocument(bytes); err != nil {
+				t.Fatalf("Generated document is invalid: %+v", err)
+			}
+
+			// Verify that the generated document matches our expected version.
+			err = exec.Command("diff", testSchemasPath, schemasPath).Run()
+			if err != nil {
+				t.Fatalf("Diff failed: %+v", err)
+			}
+
+			// if the test succeeded, clean up
+			os.RemoveAll(testSchemasPath)
+		})
+	}
+}
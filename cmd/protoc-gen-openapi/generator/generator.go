@@ -17,7 +17,6 @@ package generator
 
 import (
 	"fmt"
-	"log"
 	"net/url"
 	"regexp"
 	"sort"
@@ -29,21 +28,40 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	any_pb "google.golang.org/protobuf/types/known/anypb"
+	"gopkg.in/yaml.v3"
 
+	"github.com/google/gnostic/cmd/internal/fieldbehavior"
 	wk "github.com/google/gnostic/cmd/protoc-gen-openapi/generator/wellknown"
+	"github.com/google/gnostic/compiler"
 	v3 "github.com/google/gnostic/openapiv3"
 )
 
 type Configuration struct {
-	Version         *string
-	Title           *string
-	Description     *string
-	Naming          *string
-	FQSchemaNaming  *bool
-	EnumType        *string
-	CircularDepth   *int
-	DefaultResponse *bool
-	OutputMode      *string
+	Version             *string
+	Title               *string
+	Description         *string
+	Naming              *string
+	FQSchemaNaming      *bool
+	EnumType            *string
+	EnumPrefixStrip     *bool
+	EnumExtensions      *bool
+	CircularDepth       *int
+	DefaultResponse     *bool
+	OutputMode          *string
+	ExtensionPrefix     *string
+	SecurityDefinitions *string
+	MediaTypeAccess     *bool
+	// Logger receives warnings about unsupported fields. If it is nil,
+	// the Logger that was set with compiler.SetLogger is used.
+	Logger compiler.Logger
+}
+
+// logger returns the Logger of a configuration.
+func (conf Configuration) logger() compiler.Logger {
+	if conf.Logger != nil {
+		return conf.Logger
+	}
+	return compiler.CurrentLogger()
 }
 
 const (
@@ -56,17 +74,19 @@ const (
 var statusProtoDesc = (&status_pb.Status{}).ProtoReflect().Descriptor()
 var anyProtoDesc = (&any_pb.Any{}).ProtoReflect().Descriptor()
 
+// linterRulePattern matches the linter rules that are removed from comments.
+var linterRulePattern = regexp.MustCompile(`\(-- .* --\)`)
+
 // OpenAPIv3Generator holds internal state needed to generate an OpenAPIv3 document for a transcoded Protocol Buffer service.
 type OpenAPIv3Generator struct {
 	conf   Configuration
 	plugin *protogen.Plugin
 
-	inputFiles        []*protogen.File
-	reflect           *OpenAPIv3Reflector
-	generatedSchemas  []string // Names of schemas that have already been generated.
-	linterRulePattern *regexp.Regexp
-	pathPattern       *regexp.Regexp
-	namedPathPattern  *regexp.Regexp
+	inputFiles       []*protogen.File
+	reflect          *OpenAPIv3Reflector
+	generatedSchemas []string // Names of schemas that have already been generated.
+	pathPattern      *regexp.Regexp
+	namedPathPattern *regexp.Regexp
 }
 
 // NewOpenAPIv3Generator creates a new generator for a protoc plugin invocation.
@@ -75,19 +95,27 @@ func NewOpenAPIv3Generator(plugin *protogen.Plugin, conf Configuration, inputFil
 		conf:   conf,
 		plugin: plugin,
 
-		inputFiles:        inputFiles,
-		reflect:           NewOpenAPIv3Reflector(conf),
-		generatedSchemas:  make([]string, 0),
-		linterRulePattern: regexp.MustCompile(`\(-- .* --\)`),
-		pathPattern:       regexp.MustCompile("{([^=}]+)}"),
-		namedPathPattern:  regexp.MustCompile("{(.+)=(.+)}"),
+		inputFiles:       inputFiles,
+		reflect:          NewOpenAPIv3Reflector(conf),
+		generatedSchemas: make([]string, 0),
+		pathPattern:      regexp.MustCompile("{([^=}]+)}"),
+		namedPathPattern: regexp.MustCompile("{(.+)=(.+)}"),
 	}
 }
 
 // Run runs the generator.
 func (g *OpenAPIv3Generator) Run(outputFile *protogen.GeneratedFile) error {
 	d := g.buildDocumentV3()
-	bytes, err := d.YAMLValue("Generated with protoc-gen-openapi\n" + infoURL)
+	if err := g.addSecuritySchemesToDocumentV3(d); err != nil {
+		return err
+	}
+	info := d.ToRawInfo()
+	addScopesToRawInfo(info, d)
+	bytes, err := yaml.Marshal(&yaml.Node{
+		Kind:        yaml.DocumentNode,
+		Content:     []*yaml.Node{info},
+		HeadComment: "Generated with protoc-gen-openapi\n" + infoURL,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to marshal yaml: %s", err.Error())
 	}
@@ -240,12 +268,16 @@ func (g *OpenAPIv3Generator) buildDocumentV3() *v3.Document {
 		})
 		d.Components.Schemas.AdditionalProperties = pairs
 	}
+	// Prefix all vendor extensions with the configured prefix.
+	if g.conf.ExtensionPrefix != nil && *g.conf.ExtensionPrefix != "" {
+		prefixExtensions(d.ProtoReflect(), *g.conf.ExtensionPrefix)
+	}
 	return d
 }
 
 // filterCommentString removes linter rules from comments.
 func (g *OpenAPIv3Generator) filterCommentString(c protogen.Comments) string {
-	comment := g.linterRulePattern.ReplaceAllString(string(c), "")
+	comment := linterRulePattern.ReplaceAllString(string(c), "")
 	return strings.TrimSpace(comment)
 }
 
@@ -640,7 +672,7 @@ func (g *OpenAPIv3Generator) buildOperationV3(
 						requestSchema = g.reflect.schemaOrReferenceForMessage(field.Message.Desc)
 
 					default:
-						log.Printf("unsupported field type %+v", field.Desc)
+						g.conf.logger().Warnf("unsupported field type %+v", field.Desc)
 					}
 					break
 				}
@@ -759,6 +791,10 @@ func (g *OpenAPIv3Generator) addPathsToDocumentV3(d *v3.Document, services []*pr
 						proto.Merge(op, extOperation.(*v3.Operation))
 					}
 
+					if *g.conf.MediaTypeAccess {
+						markMediaTypeSchemasV3(op, methodName)
+					}
+
 					g.addOperationToDocumentV3(d, op, path2, methodName)
 				}
 			}
@@ -771,6 +807,29 @@ func (g *OpenAPIv3Generator) addPathsToDocumentV3(d *v3.Document, services []*pr
 	}
 }
 
+// markMediaTypeSchemasV3 marks the inline schemas of the responses of GET
+// operations as readOnly and the inline schemas of the request bodies of
+// POST and PUT operations as writeOnly. References are left unchanged
+// because OpenAPI 3.0 ignores the siblings of $ref.
+func markMediaTypeSchemasV3(op *v3.Operation, methodName string) {
+	switch methodName {
+	case "GET":
+		for _, response := range op.GetResponses().GetResponseOrReference() {
+			for _, mediaType := range response.GetValue().GetResponse().GetContent().GetAdditionalProperties() {
+				if schema := mediaType.GetValue().GetSchema().GetSchema(); schema != nil {
+					schema.ReadOnly = true
+				}
+			}
+		}
+	case "POST", "PUT":
+		for _, mediaType := range op.GetRequestBody().GetRequestBody().GetContent().GetAdditionalProperties() {
+			if schema := mediaType.GetValue().GetSchema().GetSchema(); schema != nil {
+				schema.WriteOnly = true
+			}
+		}
+	}
+}
+
 // addSchemaForMessageToDocumentV3 adds the schema to the document if required
 func (g *OpenAPIv3Generator) addSchemaToDocumentV3(d *v3.Document, schema *v3.NamedSchemaOrReference) {
 	if contains(g.generatedSchemas, schema.Name) {
@@ -823,27 +882,13 @@ func (g *OpenAPIv3Generator) addSchemasForMessagesToDocumentV3(d *v3.Document, m
 		for _, field := range message.Fields {
 			// Get the field description from the comments.
 			description := g.filterCommentString(field.Comments.Leading)
-			// Check the field annotations to see if this is a readonly or writeonly field.
-			inputOnly := false
-			outputOnly := false
-			extension := proto.GetExtension(field.Desc.Options(), annotations.E_FieldBehavior)
-			if extension != nil {
-				switch v := extension.(type) {
-				case []annotations.FieldBehavior:
-					for _, vv := range v {
-						switch vv {
-						case annotations.FieldBehavior_OUTPUT_ONLY:
-							outputOnly = true
-						case annotations.FieldBehavior_INPUT_ONLY:
-							inputOnly = true
-						case annotations.FieldBehavior_REQUIRED:
-							required = append(required, g.reflect.formatFieldName(field.Desc))
-						}
-					}
-				default:
-					log.Printf("unsupported extension type %T", extension)
-				}
+			// Check the field annotations for required, readonly, writeonly,
+			// immutable and resource reference fields.
+			behavior := fieldbehavior.ForField(field.Desc)
+			if behavior.Required {
+				required = append(required, g.reflect.formatFieldName(field.Desc))
 			}
+			description = behavior.AppendResourceDescription(description)
 
 			// The field is either described by a reference or a schema.
 			fieldSchema := g.reflect.schemaOrReferenceForField(field.Desc)
@@ -852,7 +897,7 @@ func (g *OpenAPIv3Generator) addSchemasForMessagesToDocumentV3(d *v3.Document, m
 			}
 
 			// If this field has siblings and is a $ref now, create a new schema use `allOf` to wrap it
-			wrapperNeeded := inputOnly || outputOnly || description != ""
+			wrapperNeeded := behavior.InputOnly || behavior.OutputOnly || behavior.Immutable || description != ""
 			if wrapperNeeded {
 				if _, ok := fieldSchema.Oneof.(*v3.SchemaOrReference_Reference); ok {
 					fieldSchema = &v3.SchemaOrReference{Oneof: &v3.SchemaOrReference_Schema{Schema: &v3.Schema{
@@ -863,8 +908,17 @@ func (g *OpenAPIv3Generator) addSchemasForMessagesToDocumentV3(d *v3.Document, m
 
 			if schema, ok := fieldSchema.Oneof.(*v3.SchemaOrReference_Schema); ok {
 				schema.Schema.Description = description
-				schema.Schema.ReadOnly = outputOnly
-				schema.Schema.WriteOnly = inputOnly
+				schema.Schema.ReadOnly = behavior.OutputOnly
+				schema.Schema.WriteOnly = behavior.InputOnly
+				if behavior.Immutable {
+					schema.Schema.SpecificationExtension = append(schema.Schema.SpecificationExtension,
+						&v3.NamedAny{Name: fieldbehavior.ImmutableExtension, Value: &v3.Any{Yaml: "true"}})
+				}
+				if behavior.ResourceType != "" {
+					resourceType, _ := yaml.Marshal(behavior.ResourceType)
+					schema.Schema.SpecificationExtension = append(schema.Schema.SpecificationExtension,
+						&v3.NamedAny{Name: fieldbehavior.ResourceReferenceExtension, Value: &v3.Any{Yaml: string(resourceType)}})
+				}
 
 				// Merge any `Property` annotations with the current
 				extProperty := proto.GetExtension(field.Desc.Options(), v3.E_Property)
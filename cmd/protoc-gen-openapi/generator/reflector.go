@@ -16,7 +16,6 @@
 package generator
 
 import (
-	"log"
 	"strings"
 
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -177,6 +176,18 @@ func (r *OpenAPIv3Reflector) schemaOrReferenceForMessage(message protoreflect.Me
 	}
 }
 
+// enumValueDescriptions returns the leading comments of the values of an enum.
+func (r *OpenAPIv3Reflector) enumValueDescriptions(enum protoreflect.EnumDescriptor) []string {
+	values := enum.Values()
+	descriptions := make([]string, values.Len())
+	for i := range descriptions {
+		value := values.Get(i)
+		comments := value.ParentFile().SourceLocations().ByDescriptor(value).LeadingComments
+		descriptions[i] = strings.TrimSpace(linterRulePattern.ReplaceAllString(comments, ""))
+	}
+	return descriptions
+}
+
 func (r *OpenAPIv3Reflector) schemaOrReferenceForField(field protoreflect.FieldDescriptor) *v3.SchemaOrReference {
 	var kindSchema *v3.SchemaOrReference
 
@@ -216,7 +227,10 @@ func (r *OpenAPIv3Reflector) schemaOrReferenceForField(field protoreflect.FieldD
 		kindSchema = wk.NewStringSchema()
 
 	case protoreflect.EnumKind:
-		kindSchema = wk.NewEnumSchema(*&r.conf.EnumType, field)
+		kindSchema = wk.NewEnumSchema(r.conf.EnumType, r.conf.EnumPrefixStrip, field)
+		if r.conf.EnumExtensions != nil && *r.conf.EnumExtensions {
+			wk.AddEnumExtensions(kindSchema.GetSchema(), r.conf.EnumPrefixStrip, field.Enum(), r.enumValueDescriptions(field.Enum()))
+		}
 
 	case protoreflect.BoolKind:
 		kindSchema = wk.NewBooleanSchema()
@@ -228,7 +242,7 @@ func (r *OpenAPIv3Reflector) schemaOrReferenceForField(field protoreflect.FieldD
 		kindSchema = wk.NewBytesSchema()
 
 	default:
-		log.Printf("(TODO) Unsupported field type: %+v", r.fullMessageTypeName(field.Message()))
+		r.conf.logger().Warnf("(TODO) Unsupported field type: %+v", r.fullMessageTypeName(field.Message()))
 	}
 
 	if field.IsList() {
@@ -0,0 +1,117 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	v3 "github.com/google/gnostic/openapiv3"
+)
+
+// readSecuritySchemes reads a YAML file that contains a map of OpenAPI
+// security schemes. The map can also be the value of a "securitySchemes" key,
+// so that files can be shared with the components of other documents.
+func readSecuritySchemes(filename string) (*v3.SecuritySchemesOrReferences, error) {
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read security definitions: %s", err.Error())
+	}
+	info, err := compiler.ParseYAML(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	if info.Kind == yaml.DocumentNode && len(info.Content) > 0 {
+		info = info.Content[0]
+	}
+	if value := compiler.MapValueForKey(info, "securitySchemes"); value != nil {
+		info = value
+	}
+	schemes, err := v3.NewSecuritySchemesOrReferences(info, compiler.NewContext("securitySchemes", info, nil))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err.Error())
+	}
+	return schemes, nil
+}
+
+// addSecuritySchemesToDocumentV3 merges the security schemes of the
+// security_definitions file into the components of a document.
+// Schemes that are already defined are reported as errors.
+func (g *OpenAPIv3Generator) addSecuritySchemesToDocumentV3(d *v3.Document) error {
+	if g.conf.SecurityDefinitions == nil || *g.conf.SecurityDefinitions == "" {
+		return nil
+	}
+	filename := *g.conf.SecurityDefinitions
+	schemes, err := readSecuritySchemes(filename)
+	if err != nil {
+		return err
+	}
+	if d.Components.SecuritySchemes == nil {
+		d.Components.SecuritySchemes = &v3.SecuritySchemesOrReferences{}
+	}
+	defined := make(map[string]bool)
+	for _, pair := range d.Components.SecuritySchemes.AdditionalProperties {
+		defined[pair.Name] = true
+	}
+	var duplicates []string
+	for _, pair := range schemes.AdditionalProperties {
+		if defined[pair.Name] {
+			duplicates = append(duplicates, pair.Name)
+			continue
+		}
+		defined[pair.Name] = true
+		d.Components.SecuritySchemes.AdditionalProperties = append(d.Components.SecuritySchemes.AdditionalProperties, pair)
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("%s: security schemes are already defined: %s", filename, strings.Join(duplicates, ", "))
+	}
+	return nil
+}
+
+// addScopesToRawInfo adds the scopes of OAuth flows to the YAML of a
+// document. They are omitted by the generated ToRawInfo of Strings values.
+func addScopesToRawInfo(info *yaml.Node, d *v3.Document) {
+	schemesInfo := compiler.MapValueForKey(compiler.MapValueForKey(info, "components"), "securitySchemes")
+	for _, pair := range d.GetComponents().GetSecuritySchemes().GetAdditionalProperties() {
+		flows := pair.Value.GetSecurityScheme().GetFlows()
+		if flows == nil {
+			continue
+		}
+		flowsInfo := compiler.MapValueForKey(compiler.MapValueForKey(schemesInfo, pair.Name), "flows")
+		for _, flow := range []struct {
+			name string
+			flow *v3.OauthFlow
+		}{
+			{"implicit", flows.Implicit},
+			{"password", flows.Password},
+			{"clientCredentials", flows.ClientCredentials},
+			{"authorizationCode", flows.AuthorizationCode},
+		} {
+			scopesInfo := compiler.MapValueForKey(compiler.MapValueForKey(flowsInfo, flow.name), "scopes")
+			if scopesInfo == nil || len(scopesInfo.Content) > 0 {
+				continue
+			}
+			for _, scope := range flow.flow.GetScopes().GetAdditionalProperties() {
+				scopesInfo.Content = append(scopesInfo.Content,
+					compiler.NewScalarNodeForString(scope.Name),
+					compiler.NewScalarNodeForString(scope.Value))
+			}
+		}
+	}
+}
@@ -62,3 +62,43 @@ func getValueField(message protoreflect.MessageDescriptor) protoreflect.FieldDes
 	fields := message.Fields()
 	return fields.ByName("value")
 }
+
+// prefixExtension inserts a prefix into the name of a vendor extension,
+// e.g. "x-go-type" becomes "x-acme-go-type" for the prefix "acme".
+func prefixExtension(name string, prefix string) string {
+	prefix = "x-" + strings.TrimSuffix(strings.TrimPrefix(prefix, "x-"), "-") + "-"
+	if !strings.HasPrefix(name, "x-") || strings.HasPrefix(name, prefix) {
+		return name
+	}
+	return prefix + strings.TrimPrefix(name, "x-")
+}
+
+// prefixExtensions renames the vendor extensions of a message and
+// all messages that it contains.
+func prefixExtensions(m protoreflect.Message, prefix string) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				item := list.Get(i).Message()
+				if fd.Name() == "specification_extension" {
+					name := item.Descriptor().Fields().ByName("name")
+					item.Set(name, protoreflect.ValueOfString(prefixExtension(item.Get(name).String(), prefix)))
+				}
+				prefixExtensions(item, prefix)
+			}
+		case fd.IsMap():
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				prefixExtensions(mv.Message(), prefix)
+				return true
+			})
+		default:
+			prefixExtensions(v.Message(), prefix)
+		}
+		return true
+	})
+}
@@ -16,8 +16,13 @@
 package wellknown
 
 import (
+	"strconv"
+	"strings"
+	"unicode"
+
 	v3 "github.com/google/gnostic/openapiv3"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
 )
 
 func NewStringSchema() *v3.SchemaOrReference {
@@ -50,15 +55,19 @@ func NewNumberSchema(format string) *v3.SchemaOrReference {
 			Schema: &v3.Schema{Type: "number", Format: format}}}
 }
 
-func NewEnumSchema(enum_type *string, field protoreflect.FieldDescriptor) *v3.SchemaOrReference {
+func NewEnumSchema(enum_type *string, enum_prefix_strip *bool, field protoreflect.FieldDescriptor) *v3.SchemaOrReference {
 	schema := &v3.Schema{Format: "enum"}
 	if enum_type != nil && *enum_type == "string" {
 		schema.Type = "string"
 		schema.Enum = make([]*v3.Any, 0, field.Enum().Values().Len())
 		for i := 0; i < field.Enum().Values().Len(); i++ {
-			schema.Enum = append(schema.Enum, &v3.Any{
-				Yaml: string(field.Enum().Values().Get(i).Name()),
-			})
+			name := enumValueName(enum_prefix_strip, field.Enum(), field.Enum().Values().Get(i))
+			// Names like "1" or "TRUE" that remain after stripping are quoted.
+			value, err := yaml.Marshal(name)
+			if err != nil {
+				continue
+			}
+			schema.Enum = append(schema.Enum, &v3.Any{Yaml: strings.TrimSpace(string(value))})
 		}
 	} else {
 		schema.Type = "integer"
@@ -68,6 +77,78 @@ func NewEnumSchema(enum_type *string, field protoreflect.FieldDescriptor) *v3.Sc
 			Schema: schema}}
 }
 
+// AddEnumExtensions adds the names of the values of an enum as
+// x-enum-varnames and their comments as x-enum-descriptions to an enum
+// schema. Code generators use them to name and document enum constants.
+// Integer enums also list their values, and the descriptions are only
+// added if any value has one.
+func AddEnumExtensions(schema *v3.Schema, enum_prefix_strip *bool, enum protoreflect.EnumDescriptor, descriptions []string) {
+	values := enum.Values()
+	names := make([]string, 0, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		value := values.Get(i)
+		if schema.Type == "integer" {
+			schema.Enum = append(schema.Enum, &v3.Any{Yaml: strconv.Itoa(int(value.Number()))})
+			names = append(names, string(value.Name()))
+		} else {
+			names = append(names, enumValueName(enum_prefix_strip, enum, value))
+		}
+	}
+	schema.SpecificationExtension = append(schema.SpecificationExtension, newSequenceExtension("x-enum-varnames", names))
+	for _, description := range descriptions {
+		if description != "" {
+			schema.SpecificationExtension = append(schema.SpecificationExtension, newSequenceExtension("x-enum-descriptions", descriptions))
+			break
+		}
+	}
+}
+
+// newSequenceExtension returns an extension with a list of strings as value.
+func newSequenceExtension(name string, values []string) *v3.NamedAny {
+	bytes, err := yaml.Marshal(values)
+	if err != nil {
+		return &v3.NamedAny{Name: name, Value: &v3.Any{Yaml: "[]"}}
+	}
+	return &v3.NamedAny{Name: name, Value: &v3.Any{Yaml: string(bytes)}}
+}
+
+// enumValueName returns the name of an enum value in string enums.
+func enumValueName(enum_prefix_strip *bool, enum protoreflect.EnumDescriptor, value protoreflect.EnumValueDescriptor) string {
+	name := string(value.Name())
+	if enum_prefix_strip != nil && *enum_prefix_strip {
+		name = StripEnumPrefix(string(enum.Name()), name)
+	}
+	return name
+}
+
+// StripEnumPrefix removes the name of an enum type from the start of the
+// name of one of its values, e.g. STATUS_ACTIVE becomes ACTIVE for an enum
+// named Status. The prefix is the enum name in upper snake case followed
+// by an underscore and is compared case-insensitively. Names that don't
+// have the prefix, or that would become empty, are returned unchanged.
+func StripEnumPrefix(enumName, valueName string) string {
+	prefix := upperSnakeCase(enumName) + "_"
+	if len(valueName) > len(prefix) && strings.EqualFold(valueName[:len(prefix)], prefix) {
+		return valueName[len(prefix):]
+	}
+	return valueName
+}
+
+// upperSnakeCase converts a CamelCase name to UPPER_SNAKE_CASE.
+// Acronyms are kept together, e.g. HTTPMethod becomes HTTP_METHOD.
+func upperSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && runes[i-1] != '_' &&
+			(!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
 func NewListSchema(item_schema *v3.SchemaOrReference) *v3.SchemaOrReference {
 	return &v3.SchemaOrReference{
 		Oneof: &v3.SchemaOrReference_Schema{
@@ -29,15 +29,20 @@ var flags flag.FlagSet
 
 func main() {
 	conf := generator.Configuration{
-		Version:         flags.String("version", "0.0.1", "version number text, e.g. 1.2.3"),
-		Title:           flags.String("title", "", "name of the API"),
-		Description:     flags.String("description", "", "description of the API"),
-		Naming:          flags.String("naming", "json", `naming convention. Use "proto" for passing names directly from the proto files`),
-		FQSchemaNaming:  flags.Bool("fq_schema_naming", false, `schema naming convention. If "true", generates fully-qualified schema names by prefixing them with the proto message package name`),
-		EnumType:        flags.String("enum_type", "integer", `type for enum serialization. Use "string" for string-based serialization`),
-		CircularDepth:   flags.Int("depth", 2, "depth of recursion for circular messages"),
-		DefaultResponse: flags.Bool("default_response", true, `add default response. If "true", automatically adds a default response to operations which use the google.rpc.Status message. Useful if you use envoy or grpc-gateway to transcode as they use this type for their default error responses.`),
-		OutputMode:      flags.String("output_mode", "merged", `output generation mode. By default, a single openapi.yaml is generated at the out folder. Use "source_relative' to generate a separate '[inputfile].openapi.yaml' next to each '[inputfile].proto'.`),
+		Version:             flags.String("version", "0.0.1", "version number text, e.g. 1.2.3"),
+		Title:               flags.String("title", "", "name of the API"),
+		Description:         flags.String("description", "", "description of the API"),
+		Naming:              flags.String("naming", "json", `naming convention. Use "proto" for passing names directly from the proto files`),
+		FQSchemaNaming:      flags.Bool("fq_schema_naming", false, `schema naming convention. If "true", generates fully-qualified schema names by prefixing them with the proto message package name`),
+		EnumType:            flags.String("enum_type", "integer", `type for enum serialization. Use "string" for string-based serialization`),
+		EnumPrefixStrip:     flags.Bool("enum_prefix_strip", false, `remove the name of the enum type from the names of its values in string enums, e.g. "STATUS_ACTIVE" becomes "ACTIVE" for an enum named "Status". Names that would become empty are kept`),
+		EnumExtensions:      flags.Bool("enum_extensions", false, `add the names and comments of enum values as "x-enum-varnames" and "x-enum-descriptions" extensions, which code generators use to name and document enum constants. Integer enums also list their values`),
+		CircularDepth:       flags.Int("depth", 2, "depth of recursion for circular messages"),
+		DefaultResponse:     flags.Bool("default_response", true, `add default response. If "true", automatically adds a default response to operations which use the google.rpc.Status message. Useful if you use envoy or grpc-gateway to transcode as they use this type for their default error responses.`),
+		OutputMode:          flags.String("output_mode", "merged", `output generation mode. By default, a single openapi.yaml is generated at the out folder. Use "source_relative' to generate a separate '[inputfile].openapi.yaml' next to each '[inputfile].proto'.`),
+		ExtensionPrefix:     flags.String("extension_prefix", "", `prefix for vendor extension names. If set to e.g. "acme", emitted extensions such as "x-go-type" are renamed to "x-acme-go-type"`),
+		SecurityDefinitions: flags.String("security_definitions", "", `name of a YAML file with a map of security schemes to add to components.securitySchemes. Schemes that are already defined are reported as errors`),
+		MediaTypeAccess:     flags.Bool("media_type_access", false, `mark the inline schemas of GET responses as readOnly and of POST and PUT request bodies as writeOnly. Referenced schemas are not marked because OpenAPI 3.0 ignores the siblings of $ref`),
 	}
 
 	opts := protogen.Options{
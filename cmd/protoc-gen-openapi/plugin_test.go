@@ -43,6 +43,12 @@ var openapiTests = []struct {
 	{name: "OpenAPIv3 Annotations", path: "examples/tests/openapiv3annotations/", protofile: "message.proto"},
 	{name: "AllOf Wrap Message", path: "examples/tests/allofwrap/", protofile: "message.proto"},
 	{name: "Additional Bindings", path: "examples/tests/additional_bindings/", protofile: "message.proto"},
+	{name: "Extension Prefix", path: "examples/tests/extensionprefix/", protofile: "message.proto"},
+	{name: "Security Definitions", path: "examples/tests/securitydefinitions/", protofile: "message.proto"},
+	{name: "Enum Prefix", path: "examples/tests/enumprefix/", protofile: "message.proto"},
+	{name: "Enum Descriptions", path: "examples/tests/enumdescriptions/", protofile: "message.proto"},
+	{name: "Field behavior", path: "examples/tests/fieldbehavior/", protofile: "message.proto"},
+	{name: "Media type access", path: "examples/tests/mediatypeaccess/", protofile: "message.proto"},
 }
 
 // Set this to true to generate/overwrite the fixtures. Make sure you set it back
@@ -255,6 +261,88 @@ func TestOpenAPIStringEnums(t *testing.T) {
 	}
 }
 
+func TestOpenAPIEnumPrefixStrip(t *testing.T) {
+	for _, tt := range openapiTests {
+		fixture := path.Join(tt.path, "openapi_enum_prefix_strip.yaml")
+		if _, err := os.Stat(fixture); errors.Is(err, os.ErrNotExist) {
+			if !GENERATE_FIXTURES {
+				continue
+			}
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			// Run protoc and the protoc-gen-openapi plugin to generate an OpenAPI spec with unprefixed string Enums.
+			err := exec.Command("protoc",
+				"-I", "../../",
+				"-I", "../../third_party",
+				"-I", "examples",
+				path.Join(tt.path, tt.protofile),
+				"--openapi_out=enum_type=string,enum_prefix_strip=true:.").Run()
+			if err != nil {
+				t.Fatalf("protoc failed: %+v", err)
+			}
+			if GENERATE_FIXTURES {
+				err := CopyFixture(TEMP_FILE, fixture)
+				if err != nil {
+					t.Fatalf("Can't generate fixture: %+v", err)
+				}
+			} else {
+				// Verify that the generated spec matches our expected version.
+				err = exec.Command("diff", TEMP_FILE, fixture).Run()
+				if err != nil {
+					t.Fatalf("diff failed: %+v", err)
+				}
+			}
+			// if the test succeeded, clean up
+			os.Remove(TEMP_FILE)
+		})
+	}
+}
+
+func TestOpenAPIEnumExtensions(t *testing.T) {
+	for _, tt := range openapiTests {
+		for _, variant := range []struct {
+			fixture string
+			options string
+		}{
+			{"openapi_enum_extensions.yaml", "enum_extensions=true"},
+			{"openapi_string_enum_extensions.yaml", "enum_type=string,enum_prefix_strip=true,enum_extensions=true"},
+		} {
+			fixture := path.Join(tt.path, variant.fixture)
+			if _, err := os.Stat(fixture); errors.Is(err, os.ErrNotExist) {
+				if !GENERATE_FIXTURES {
+					continue
+				}
+			}
+			t.Run(tt.name, func(t *testing.T) {
+				// Run protoc and the protoc-gen-openapi plugin to generate an OpenAPI spec with enum extensions.
+				err := exec.Command("protoc",
+					"-I", "../../",
+					"-I", "../../third_party",
+					"-I", "examples",
+					path.Join(tt.path, tt.protofile),
+					"--openapi_out="+variant.options+":.").Run()
+				if err != nil {
+					t.Fatalf("protoc failed: %+v", err)
+				}
+				if GENERATE_FIXTURES {
+					err := CopyFixture(TEMP_FILE, fixture)
+					if err != nil {
+						t.Fatalf("Can't generate fixture: %+v", err)
+					}
+				} else {
+					// Verify that the generated spec matches our expected version.
+					err = exec.Command("diff", TEMP_FILE, fixture).Run()
+					if err != nil {
+						t.Fatalf("diff failed: %+v", err)
+					}
+				}
+				// if the test succeeded, clean up
+				os.Remove(TEMP_FILE)
+			})
+		}
+	}
+}
+
 func TestOpenAPIDefaultResponse(t *testing.T) {
 	for _, tt := range openapiTests {
 		fixture := path.Join(tt.path, "openapi_default_response.yaml")
@@ -291,3 +379,131 @@ func TestOpenAPIDefaultResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenAPIExtensionPrefix(t *testing.T) {
+	for _, tt := range openapiTests {
+		fixture := path.Join(tt.path, "openapi_extension_prefix.yaml")
+		if _, err := os.Stat(fixture); errors.Is(err, os.ErrNotExist) {
+			if !GENERATE_FIXTURES {
+				continue
+			}
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			// Run protoc and the protoc-gen-openapi plugin to generate an OpenAPI spec with prefixed extensions.
+			err := exec.Command("protoc",
+				"-I", "../../",
+				"-I", "../../third_party",
+				"-I", "examples",
+				path.Join(tt.path, tt.protofile),
+				"--openapi_out=extension_prefix=acme:.").Run()
+			if err != nil {
+				t.Fatalf("protoc failed: %+v", err)
+			}
+			if GENERATE_FIXTURES {
+				err := CopyFixture(TEMP_FILE, fixture)
+				if err != nil {
+					t.Fatalf("Can't generate fixture: %+v", err)
+				}
+			} else {
+				// Verify that the generated spec matches our expected version.
+				err = exec.Command("diff", TEMP_FILE, fixture).Run()
+				if err != nil {
+					t.Fatalf("diff failed: %+v", err)
+				}
+			}
+			// if the test succeeded, clean up
+			os.Remove(TEMP_FILE)
+		})
+	}
+}
+
+func TestOpenAPIMediaTypeAccess(t *testing.T) {
+	for _, tt := range openapiTests {
+		fixture := path.Join(tt.path, "openapi_media_type_access.yaml")
+		if _, err := os.Stat(fixture); errors.Is(err, os.ErrNotExist) {
+			if !GENERATE_FIXTURES {
+				continue
+			}
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			// Run protoc and the protoc-gen-openapi plugin to generate an OpenAPI spec with readOnly responses and writeOnly request bodies.
+			err := exec.Command("protoc",
+				"-I", "../../",
+				"-I", "../../third_party",
+				"-I", "examples",
+				path.Join(tt.path, tt.protofile),
+				"--openapi_out=media_type_access=true:.").Run()
+			if err != nil {
+				t.Fatalf("protoc failed: %+v", err)
+			}
+			if GENERATE_FIXTURES {
+				err := CopyFixture(TEMP_FILE, fixture)
+				if err != nil {
+					t.Fatalf("Can't generate fixture: %+v", err)
+				}
+			} else {
+				// Verify that the generated spec matches our expected version.
+				err = exec.Command("diff", TEMP_FILE, fixture).Run()
+				if err != nil {
+					t.Fatalf("diff failed: %+v", err)
+				}
+			}
+			// if the test succeeded, clean up
+			os.Remove(TEMP_FILE)
+		})
+	}
+}
+
+func TestOpenAPISecurityDefinitions(t *testing.T) {
+	for _, tt := range openapiTests {
+		fixture := path.Join(tt.path, "openapi_security_definitions.yaml")
+		if _, err := os.Stat(fixture); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			// Run protoc and the protoc-gen-openapi plugin to generate an OpenAPI spec with shared security schemes.
+			err := exec.Command("protoc",
+				"-I", "../../",
+				"-I", "../../third_party",
+				"-I", "examples",
+				path.Join(tt.path, tt.protofile),
+				"--openapi_out=security_definitions="+path.Join(tt.path, "security.yaml")+":.").Run()
+			if err != nil {
+				t.Fatalf("protoc failed: %+v", err)
+			}
+			if GENERATE_FIXTURES {
+				err := CopyFixture(TEMP_FILE, fixture)
+				if err != nil {
+					t.Fatalf("Can't generate fixture: %+v", err)
+				}
+			} else {
+				// Verify that the generated spec matches our expected version.
+				err = exec.Command("diff", TEMP_FILE, fixture).Run()
+				if err != nil {
+					t.Fatalf("diff failed: %+v", err)
+				}
+			}
+			// if the test succeeded, clean up
+			os.Remove(TEMP_FILE)
+		})
+	}
+}
+
+func TestOpenAPISecurityDefinitionsConflict(t *testing.T) {
+	// BasicAuth is also defined by an (openapi.v3.document) option of the proto file.
+	dir := "examples/tests/securitydefinitions/"
+	output, err := exec.Command("protoc",
+		"-I", "../../",
+		"-I", "../../third_party",
+		"-I", "examples",
+		path.Join(dir, "message.proto"),
+		"--openapi_out=security_definitions="+path.Join(dir, "security_conflict.yaml")+":.").CombinedOutput()
+	os.Remove(TEMP_FILE)
+	if err == nil {
+		t.Fatalf("protoc succeeded with a duplicate security scheme")
+	}
+	expected := "security schemes are already defined: BasicAuth"
+	if !strings.Contains(string(output), expected) {
+		t.Errorf("protoc output %q does not contain %q", output, expected)
+	}
+}
@@ -22,9 +22,12 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
+	"gopkg.in/yaml.v3"
 
+	"github.com/google/gnostic/plugins/gnostic-report/report"
 	"github.com/google/gnostic/printer"
 
 	pb "github.com/google/gnostic/openapiv2"
@@ -82,17 +85,25 @@ func printDocument(code *printer.Code, document *pb.Document) {
 		code.Print("%+v", pair.Name)
 		code.Indent()
 		v := pair.Value
-		if v.Get != nil {
-			code.Print("GET")
+		if len(v.Parameters) > 0 {
+			code.Print("Parameters:")
 			code.Indent()
-			printOperation(code, v.Get)
+			printParameters(code, v.Parameters)
 			code.Outdent()
 		}
-		if v.Post != nil {
-			code.Print("POST")
-			code.Indent()
-			printOperation(code, v.Post)
-			code.Outdent()
+		for _, o := range []struct {
+			method    string
+			operation *pb.Operation
+		}{
+			{"GET", v.Get}, {"PUT", v.Put}, {"POST", v.Post}, {"DELETE", v.Delete},
+			{"OPTIONS", v.Options}, {"HEAD", v.Head}, {"PATCH", v.Patch},
+		} {
+			if o.operation != nil {
+				code.Print(o.method)
+				code.Indent()
+				printOperation(code, o.operation)
+				code.Outdent()
+			}
 		}
 		code.Outdent()
 	}
@@ -161,16 +172,7 @@ func printOperation(code *printer.Code, operation *pb.Operation) {
 	code.Print("OperationId: %+v", operation.OperationId)
 	code.Print("Parameters:")
 	code.Indent()
-	for _, item := range operation.Parameters {
-		switch t := item.Oneof.(type) {
-		default:
-			code.Print("unexpected type %T", t) // %T prints whatever type t has
-		case *pb.ParametersItem_JsonReference:
-			code.Print("JsonReference: %+v", t)
-		case *pb.ParametersItem_Parameter:
-			code.Print("Parameter: %+v", t)
-		}
-	}
+	printParameters(code, operation.Parameters)
 	code.Outdent()
 	code.Print("Produces: %+v", operation.Produces)
 	code.Print("Responses:")
@@ -190,6 +192,19 @@ func printOperation(code *printer.Code, operation *pb.Operation) {
 	printVendorExtension(code, operation.VendorExtension)
 }
 
+func printParameters(code *printer.Code, parameters []*pb.ParametersItem) {
+	for _, item := range parameters {
+		switch t := item.Oneof.(type) {
+		default:
+			code.Print("unexpected type %T", t) // %T prints whatever type t has
+		case *pb.ParametersItem_JsonReference:
+			code.Print("JsonReference: %+v", t)
+		case *pb.ParametersItem_Parameter:
+			code.Print("Parameter: %+v", t)
+		}
+	}
+}
+
 func printSchema(code *printer.Code, schema *pb.Schema) {
 	//code.Print("%+v", schema)
 	if schema.Format != "" {
@@ -209,12 +224,42 @@ func printSchema(code *printer.Code, schema *pb.Schema) {
 	if schema.Type != nil {
 		code.Print("Type: %+v", schema.Type)
 	}
+	if len(schema.Enum) > 0 {
+		printEnum(code, schema.Enum, schema.VendorExtension)
+	}
 	if schema.Xml != nil {
 		code.Print("Xml: %+v", schema.Xml)
 	}
 	printVendorExtension(code, schema.VendorExtension)
 }
 
+// printEnum prints the values of an enum with the names and descriptions
+// from their x-enum-varnames and x-enum-descriptions extensions.
+func printEnum(code *printer.Code, values []*pb.Any, vendorExtension []*pb.NamedAny) {
+	var names, descriptions []string
+	for _, extension := range vendorExtension {
+		switch extension.Name {
+		case "x-enum-varnames":
+			yaml.Unmarshal([]byte(extension.Value.GetYaml()), &names)
+		case "x-enum-descriptions":
+			yaml.Unmarshal([]byte(extension.Value.GetYaml()), &descriptions)
+		}
+	}
+	code.Print("Enum:")
+	code.Indent()
+	for i, value := range values {
+		line := strings.TrimSpace(value.GetYaml())
+		if i < len(names) && names[i] != "" {
+			line += " (" + names[i] + ")"
+		}
+		if i < len(descriptions) && descriptions[i] != "" {
+			line += ": " + descriptions[i]
+		}
+		code.Print("%s", line)
+	}
+	code.Outdent()
+}
+
 func printVendorExtension(code *printer.Code, vendorExtension []*pb.NamedAny) {
 	if len(vendorExtension) > 0 {
 		code.Print("VendorExtension: %+v", vendorExtension)
@@ -222,11 +267,12 @@ func printVendorExtension(code *printer.Code, vendorExtension []*pb.NamedAny) {
 }
 
 func main() {
+	output := flag.String("output", "text", `output format. Use "json" for the JSON format of the gnostic-report plugin`)
 	flag.Parse()
 	args := flag.Args()
 
 	if len(args) != 1 {
-		fmt.Printf("Usage: report <file.pb>\n")
+		fmt.Printf("Usage: report [--output=text|json] <file.pb>\n")
 		return
 	}
 
@@ -236,6 +282,15 @@ func main() {
 		log.Printf("Error reading %s. This sample expects OpenAPI v2.", args[0])
 		os.Exit(-1)
 	}
+	if *output == "json" {
+		bytes, err := report.NewReportV2(args[0], document).JSON()
+		if err != nil {
+			log.Printf("Error writing report: %+v", err)
+			os.Exit(-1)
+		}
+		os.Stdout.Write(bytes)
+		return
+	}
 	code := &printer.Code{}
 	code.Print("API REPORT")
 	code.Print("----------")
@@ -0,0 +1,42 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/google/gnostic/jsonwriter"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// canonicalValue returns a representation of a YAML fragment that
+// is independent of key order, formatting, and comments.
+// This is the fragment's canonical JSON (see jsonwriter.Canonical);
+// fragments that can't be written as JSON fall back to their YAML.
+func canonicalValue(in *yaml.Node) string {
+	if bytes, err := jsonwriter.MarshalStyle(in, jsonwriter.Canonical); err == nil {
+		return string(bytes)
+	}
+	return string(Marshal(in))
+}
+
+// CanonicalHash returns the hex-encoded SHA-256 hash of a YAML fragment.
+// Fragments that differ only in key order, formatting, comments, and
+// the spelling of equal numbers (such as 1, 1.0 and 1e0) have the same hash.
+func CanonicalHash(in *yaml.Node) string {
+	sum := sha256.Sum256([]byte(canonicalValue(in)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestCanonicalHash(t *testing.T) {
+	hash := func(source string) string {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(source), &node); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return CanonicalHash(&node)
+	}
+	a := hash("type: object\nproperties:\n  name: {type: string}\n")
+	b := hash("# A pet.\nproperties:\n  name:\n    type: string\ntype: object\n")
+	c := hash("type: object\nproperties:\n  name: {type: integer}\n")
+	d := hash("{maximum: 1.0, minimum: -0, multipleOf: 1e-7}")
+	e := hash("{multipleOf: 0.0000001, minimum: 0, maximum: 1}")
+	if a != b {
+		t.Errorf("equivalent fragments have different hashes: %s %s", a, b)
+	}
+	if d != e {
+		t.Errorf("fragments with equal numbers have different hashes: %s %s", d, e)
+	}
+	if a == c {
+		t.Errorf("different fragments have the same hash: %s", a)
+	}
+	if len(a) != 64 {
+		t.Errorf("unexpected hash length: %d", len(a))
+	}
+}
@@ -15,7 +15,11 @@
 package compiler
 
 import (
+	"reflect"
+	"sync"
+
 	"github.com/google/gnostic-models/compiler"
+	"gopkg.in/yaml.v3"
 )
 
 // Context contains state of the compiler as it traverses a document.
@@ -26,3 +30,192 @@ var NewContextWithExtensions = compiler.NewContextWithExtensions
 
 // NewContext returns a new object representing the compiler state
 var NewContext = compiler.NewContext
+
+// NewContextFromNode returns a new object representing the compiler state
+// at a yaml.v3 node. Unlike NewContext, it keeps the node of contexts that
+// have no parent, so that line numbers are available for root contexts.
+// Extension handlers are inherited from the parent.
+func NewContextFromNode(name string, node *yaml.Node, parent *Context) *Context {
+	context := &Context{Name: name, Node: node, Parent: parent}
+	if parent != nil {
+		context.ExtensionHandlers = parent.ExtensionHandlers
+	}
+	return context
+}
+
+// documentKey is the key of the documents that NewContextWithDocument
+// attaches to contexts.
+type documentKey struct{}
+
+// NewContextWithDocument returns a root context for compiling a document
+// that carries the document, so that compiler rules can find it with
+// Document from any context that descends from the root context.
+// The document can be a yaml.Node or a compiled model. Callers should
+// release it with ReleaseUserData when compilation is finished.
+func NewContextWithDocument(name string, node *yaml.Node, document interface{}, extensionHandlers *[]ExtensionHandler) *Context {
+	return WithUserData(NewContextWithExtensions(name, node, nil, extensionHandlers), documentKey{}, document)
+}
+
+// Document returns the document that NewContextWithDocument attached to
+// the root of a context, or, if there is none, the node of the root
+// context, which is the root of the parsed document tree. It returns nil
+// if neither is available. Since Context is defined in gnostic-models,
+// this is a function rather than a method.
+func Document(context *Context) interface{} {
+	if document, ok := UserData(context, documentKey{}); ok {
+		return document
+	}
+	if context == nil {
+		return nil
+	}
+	root := context
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	if root.Node == nil {
+		return nil
+	}
+	return root.Node
+}
+
+// loggerKey is the key of the Loggers that WithLogger attaches to contexts.
+type loggerKey struct{}
+
+// WithLogger returns a copy of a context that carries a Logger. Extension
+// handlers that are called with the context, or with contexts that descend
+// from it, log to that Logger until the context is released with
+// ReleaseUserData. Since Context is defined in gnostic-models,
+// this is a function rather than a method.
+func WithLogger(context *Context, logger Logger) *Context {
+	return WithUserData(context, loggerKey{}, logger)
+}
+
+// ContextLogger returns the Logger that WithLogger attached to a context
+// or to one of its ancestors, or the Logger that was set with SetLogger.
+func ContextLogger(context *Context) Logger {
+	if value, ok := UserData(context, loggerKey{}); ok {
+		if logger, ok := value.(Logger); ok {
+			return logger
+		}
+	}
+	return CurrentLogger()
+}
+
+// validationKey is the key of the settings that WithValidation attaches
+// to contexts.
+type validationKey struct{}
+
+// WithValidation returns a copy of a context that enables or disables the
+// checks for missing required keys and unexpected keys in the models that
+// are built with it or with contexts that descend from it. Checks that
+// decide which possibility of a oneof a value matches always run.
+// Since Context is defined in gnostic-models, this is a function
+// rather than a method.
+func WithValidation(context *Context, enabled bool) *Context {
+	return WithUserData(context, validationKey{}, enabled)
+}
+
+// ValidationEnabled returns false if a context or one of its ancestors
+// was returned by WithValidation with validation disabled.
+func ValidationEnabled(context *Context) bool {
+	if value, ok := UserData(context, validationKey{}); ok {
+		if enabled, ok := value.(bool); ok {
+			return enabled
+		}
+	}
+	return true
+}
+
+// LineNumber returns the line of the source YAML that a context describes,
+// or, if the context has no node, the line of its nearest ancestor that
+// has one. It returns 0 if no line information is available.
+// Since Context is defined in gnostic-models, this is a function
+// rather than a method.
+func LineNumber(context *Context) int {
+	for c := context; c != nil; c = c.Parent {
+		if c.Node != nil && c.Node.Line > 0 {
+			return c.Node.Line
+		}
+	}
+	return 0
+}
+
+// userData is a value attached to a context with WithUserData.
+type userData struct {
+	key, value interface{}
+	// base is the context that WithUserData copied.
+	base *Context
+}
+
+// Contexts are defined in gnostic-models and have no field for user data,
+// so values are kept in a side table indexed by the contexts that
+// WithUserData returns. The table holds references to these contexts
+// (and to the contexts they were copied from), so its entries live until
+// they are removed with ReleaseUserData. All accesses are guarded by
+// userDataMutex, so contexts can be created and read concurrently.
+var (
+	userDataMutex sync.Mutex
+	userDataTable = make(map[*Context]*userData)
+)
+
+// WithUserData returns a copy of a context that carries a value for a key.
+// The value is visible to UserData calls on the returned context and on
+// the contexts that descend from it, unless they carry a value for the
+// same key. Like the keys of context.Context values, keys should be of
+// unexported types to avoid collisions between packages.
+// The value is kept until ReleaseUserData is called with the returned
+// context, which callers should do when they are done with it.
+// Since Context is defined in gnostic-models, this is a function
+// rather than a method.
+func WithUserData(context *Context, key, value interface{}) *Context {
+	if key == nil {
+		panic("nil key")
+	}
+	if !reflect.TypeOf(key).Comparable() {
+		panic("key is not comparable")
+	}
+	c := &Context{}
+	if context != nil {
+		*c = *context
+	}
+	userDataMutex.Lock()
+	userDataTable[c] = &userData{key: key, value: value, base: context}
+	userDataMutex.Unlock()
+	return c
+}
+
+// UserData returns the value that WithUserData attached to a context or
+// to one of its ancestors for a key, and whether a value was found.
+func UserData(context *Context, key interface{}) (interface{}, bool) {
+	userDataMutex.Lock()
+	defer userDataMutex.Unlock()
+	for c := context; c != nil; {
+		if data, ok := userDataTable[c]; ok {
+			if data.key == key {
+				return data.value, true
+			}
+			c = data.base
+			continue
+		}
+		c = c.Parent
+	}
+	return nil, false
+}
+
+// ReleaseUserData removes the values that WithUserData attached to a
+// context and to the contexts that it was copied from with WithUserData.
+// After it returns, neither these contexts nor the contexts that descend
+// from them carry user data, so it should only be called when they are
+// no longer used.
+func ReleaseUserData(context *Context) {
+	userDataMutex.Lock()
+	defer userDataMutex.Unlock()
+	for c := context; c != nil; {
+		data, ok := userDataTable[c]
+		if !ok {
+			return
+		}
+		delete(userDataTable, c)
+		c = data.base
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewContextFromNode(t *testing.T) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte("openapi: 3.0.0\ninfo:\n  title: Test\n"), &node); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document := node.Content[0]
+	handlers := &[]ExtensionHandler{{Name: "gnostic-x-test"}}
+	root := NewContextFromNode("$root", document, nil)
+	if root.Node != document {
+		t.Errorf("root context has no node")
+	}
+	if line := LineNumber(root); line != 1 {
+		t.Errorf("unexpected line for the root context: %d (expected 1)", line)
+	}
+	root.ExtensionHandlers = handlers
+	info := NewContextFromNode("info", MapValueForKey(document, "info"), root)
+	if line := LineNumber(info); line != 3 {
+		t.Errorf("unexpected line for info: %d (expected 3)", line)
+	}
+	if info.ExtensionHandlers != handlers {
+		t.Errorf("extension handlers were not inherited")
+	}
+	if description := info.Description(); description != "$root.info" {
+		t.Errorf("unexpected description: %s", description)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte("openapi: 3.0.0\ninfo:\n  title: Test\n"), &node); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document := node.Content[0]
+	root := NewContextWithExtensions("$root", document, nil, nil)
+	info := NewContext("info", MapValueForKey(document, "info"), root)
+	if Document(info) != document {
+		t.Errorf("Document did not return the node of the root context")
+	}
+	model := &struct{ title string }{"Test"}
+	handlers := &[]ExtensionHandler{{Name: "gnostic-x-test"}}
+	root = NewContextWithDocument("$root", document, model, handlers)
+	defer ReleaseUserData(root)
+	info = NewContext("info", MapValueForKey(document, "info"), root)
+	if Document(info) != model {
+		t.Errorf("Document did not return the attached document")
+	}
+	if info.ExtensionHandlers != handlers {
+		t.Errorf("extension handlers were not inherited")
+	}
+	if Document(NewContext("$ref", document, nil)) != nil {
+		t.Errorf("Document returned a value for a context without a document")
+	}
+	if Document(nil) != nil {
+		t.Errorf("Document returned a value for a nil context")
+	}
+}
+
+type userDataKey string
+
+func TestUserData(t *testing.T) {
+	root := NewContext("$root", nil, nil)
+	if _, ok := UserData(root, userDataKey("base")); ok {
+		t.Errorf("unexpected user data in a new context")
+	}
+	withBase := WithUserData(root, userDataKey("base"), "https://example.com")
+	if withBase == root || withBase.Name != "$root" {
+		t.Errorf("WithUserData did not return a copy of the context")
+	}
+	withConfig := WithUserData(withBase, userDataKey("config"), 42)
+	info := NewContext("info", nil, withConfig)
+	if value, ok := UserData(info, userDataKey("base")); !ok || value != "https://example.com" {
+		t.Errorf("unexpected value for base: %v", value)
+	}
+	if value, ok := UserData(info, userDataKey("config")); !ok || value != 42 {
+		t.Errorf("unexpected value for config: %v", value)
+	}
+	if _, ok := UserData(info, "base"); ok {
+		t.Errorf("keys of different types should not match")
+	}
+	if _, ok := UserData(root, userDataKey("base")); ok {
+		t.Errorf("WithUserData modified the original context")
+	}
+	overridden := WithUserData(info, userDataKey("base"), "https://example.org")
+	if value, _ := UserData(overridden, userDataKey("base")); value != "https://example.org" {
+		t.Errorf("unexpected value for an overridden key: %v", value)
+	}
+	if value, _ := UserData(info, userDataKey("base")); value != "https://example.com" {
+		t.Errorf("overriding a key changed the value of its parent: %v", value)
+	}
+	if description := NewContext("title", nil, overridden).Description(); description != "$root.info.title" {
+		t.Errorf("unexpected description: %s", description)
+	}
+	ReleaseUserData(overridden)
+	if value, _ := UserData(overridden, userDataKey("base")); value != "https://example.com" {
+		t.Errorf("ReleaseUserData did not remove the value of the context: %v", value)
+	}
+	if value, _ := UserData(info, userDataKey("config")); value != 42 {
+		t.Errorf("ReleaseUserData removed the values of a parent: %v", value)
+	}
+	ReleaseUserData(withConfig)
+	if _, ok := UserData(info, userDataKey("base")); ok {
+		t.Errorf("ReleaseUserData did not remove the values of the copied contexts")
+	}
+}
+
+func TestValidationEnabled(t *testing.T) {
+	root := NewContext("$root", nil, nil)
+	if !ValidationEnabled(root) {
+		t.Errorf("validation is disabled in a new context")
+	}
+	disabled := WithValidation(root, false)
+	defer ReleaseUserData(disabled)
+	if ValidationEnabled(NewContext("info", nil, disabled)) {
+		t.Errorf("validation is enabled in a context that descends from a disabled one")
+	}
+	enabled := WithValidation(disabled, true)
+	defer ReleaseUserData(enabled)
+	if !ValidationEnabled(NewContext("info", nil, enabled)) {
+		t.Errorf("validation was not enabled again")
+	}
+}
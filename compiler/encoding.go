@@ -0,0 +1,161 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// unsupportedEncodings are byte order marks of encodings that can't be read.
+// UTF-32 marks are checked before UTF-16 marks, which are their prefixes.
+var unsupportedEncodings = []struct {
+	name string
+	mark []byte
+}{
+	{"UTF-32BE", []byte{0x00, 0x00, 0xFE, 0xFF}},
+	{"UTF-32LE", []byte{0xFF, 0xFE, 0x00, 0x00}},
+	{"UTF-7", []byte{0x2B, 0x2F, 0x76}},
+	{"UTF-1", []byte{0xF7, 0x64, 0x4C}},
+	{"UTF-EBCDIC", []byte{0xDD, 0x73, 0x66, 0x73}},
+	{"SCSU", []byte{0x0E, 0xFE, 0xFF}},
+	{"BOCU-1", []byte{0xFB, 0xEE, 0x28}},
+	{"GB-18030", []byte{0x84, 0x31, 0x95, 0x33}},
+}
+
+// DecodeText converts the contents of a text file to UTF-8. Gzip-compressed
+// text is decompressed, byte order marks are removed and UTF-16 text is
+// transcoded. As in YAML, UTF-16
+// without a byte order mark is recognized by the zero bytes of an ASCII
+// first character. Other encodings are reported as errors that name
+// the file and the encoding.
+func DecodeText(filename string, text []byte) ([]byte, error) {
+	text, err := Decompress(filename, text)
+	if err != nil {
+		return nil, err
+	}
+	return decodeText(filename, text)
+}
+
+// decodeText converts text that isn't compressed to UTF-8 like DecodeText.
+func decodeText(filename string, text []byte) ([]byte, error) {
+	for _, encoding := range unsupportedEncodings {
+		if bytes.HasPrefix(text, encoding.mark) {
+			return nil, encodingError(filename, "unsupported encoding "+encoding.name)
+		}
+	}
+	switch {
+	case bytes.HasPrefix(text, []byte{0xEF, 0xBB, 0xBF}):
+		text = text[3:]
+	case bytes.HasPrefix(text, []byte{0xFE, 0xFF}):
+		return decodeUTF16(filename, text[2:], binary.BigEndian, "UTF-16BE")
+	case bytes.HasPrefix(text, []byte{0xFF, 0xFE}):
+		return decodeUTF16(filename, text[2:], binary.LittleEndian, "UTF-16LE")
+	case len(text) >= 4 && text[0] == 0 && text[1] == 0 && text[2] == 0 && text[3] != 0:
+		return nil, encodingError(filename, "unsupported encoding UTF-32BE")
+	case len(text) >= 4 && text[0] != 0 && text[1] == 0 && text[2] == 0 && text[3] == 0:
+		return nil, encodingError(filename, "unsupported encoding UTF-32LE")
+	case len(text) >= 2 && text[0] == 0 && text[1] != 0:
+		return decodeUTF16(filename, text, binary.BigEndian, "UTF-16BE")
+	case len(text) >= 2 && text[0] != 0 && text[1] == 0:
+		return decodeUTF16(filename, text, binary.LittleEndian, "UTF-16LE")
+	}
+	if !utf8.Valid(text) {
+		return nil, encodingError(filename, "unsupported encoding: the text is not valid UTF-8")
+	}
+	return text, nil
+}
+
+// decodeUTF16 transcodes UTF-16 text that follows its byte order mark, if
+// any, to UTF-8. Unpaired surrogates are replaced with U+FFFD.
+func decodeUTF16(filename string, text []byte, order binary.ByteOrder, name string) ([]byte, error) {
+	if len(text)%2 != 0 {
+		return nil, encodingError(filename, "invalid "+name+" text: odd number of bytes")
+	}
+	units := make([]uint16, len(text)/2)
+	for i := range units {
+		units[i] = order.Uint16(text[2*i:])
+	}
+	return []byte(string(utf16.Decode(units))), nil
+}
+
+// MaxDecompressedBytes limits the size of the contents of gzip-compressed
+// files that are decompressed by Decompress and DecodeText. Compilers limit
+// it to their MaxFetchedBytes instead.
+const MaxDecompressedBytes = DefaultMaxFetchedBytes
+
+// IsGzipped returns true if data starts with the gzip magic number.
+func IsGzipped(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1F && data[1] == 0x8B
+}
+
+// Decompress returns the contents of gzip-compressed data and other data
+// unchanged. Compressed data is recognized by its magic number, not by
+// the name of its file, so that piped data can be compressed too.
+func Decompress(filename string, data []byte) ([]byte, error) {
+	return decompress(filename, data, MaxDecompressedBytes)
+}
+
+// decompress decompresses data like Decompress with a limit on the size
+// of its contents. A negative limit removes the limit.
+func decompress(filename string, data []byte, limit int64) ([]byte, error) {
+	if !IsGzipped(data) {
+		return data, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, encodingError(filename, "invalid gzip data: "+err.Error())
+	}
+	defer reader.Close()
+	var contents []byte
+	if limit < 0 {
+		contents, err = ioutil.ReadAll(reader)
+	} else {
+		contents, err = ioutil.ReadAll(io.LimitReader(reader, limit+1))
+	}
+	if err != nil {
+		return nil, encodingError(filename, "invalid gzip data: "+err.Error())
+	}
+	if limit >= 0 && int64(len(contents)) > limit {
+		return nil, encodingError(filename, fmt.Sprintf("gzip data expands to more than %d bytes", limit))
+	}
+	return contents, nil
+}
+
+// Compress returns gzip-compressed data.
+func Compress(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func encodingError(filename string, message string) error {
+	if filename != "" {
+		message = filename + ": " + message
+	}
+	return NewErrorWithCode(nil, CodeUnsupportedEncoding, message)
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDecodeText(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		input    string
+		expected string
+		err      string
+	}{
+		{"utf-8", "title: Café", "title: Café", ""},
+		{"empty", "", "", ""},
+		{"utf-8 bom", "\xef\xbb\xbftitle: x", "title: x", ""},
+		{"utf-16le bom", "\xff\xfea\x00:\x00 \x00\xe9\x00", "a: é", ""},
+		{"utf-16be bom", "\xfe\xff\x00a\x00:\x00 \x00\xe9", "a: é", ""},
+		{"utf-16le", "a\x00:\x00 \x00=\xd8\x00\xde", "a: \U0001f600", ""},
+		{"utf-16be", "\x00a\x00:\x00 \xd8=\xde\x00", "a: \U0001f600", ""},
+		{"odd utf-16", "\xff\xfea\x00:", "", "api.yaml: invalid UTF-16LE text: odd number of bytes"},
+		{"utf-32le bom", "\xff\xfe\x00\x00a\x00\x00\x00", "", "api.yaml: unsupported encoding UTF-32LE"},
+		{"utf-32be", "\x00\x00\x00a\x00\x00\x00:", "", "api.yaml: unsupported encoding UTF-32BE"},
+		{"utf-7", "+/v8title: x", "", "api.yaml: unsupported encoding UTF-7"},
+		{"latin-1", "title: Caf\xe9", "", "api.yaml: unsupported encoding: the text is not valid UTF-8"},
+		{"gzip", gzipped("\xef\xbb\xbftitle: Café"), "title: Café", ""},
+		{"invalid gzip", "\x1f\x8b\x08title", "", "api.yaml: invalid gzip data: unexpected EOF"},
+	} {
+		output, err := DecodeText("api.yaml", []byte(test.input))
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s: unexpected error %v (expected %s)", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %+v", test.name, err)
+		} else if string(output) != test.expected {
+			t.Errorf("%s: unexpected output %q (expected %q)", test.name, output, test.expected)
+		}
+	}
+}
+
+func gzipped(text string) string {
+	data, err := Compress([]byte(text))
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+func TestDecompress(t *testing.T) {
+	data, err := Compress([]byte("openapi: 3.0.0"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !IsGzipped(data) || IsGzipped([]byte("openapi: 3.0.0")) {
+		t.Errorf("IsGzipped doesn't recognize the gzip magic number")
+	}
+	for _, input := range [][]byte{data, []byte("openapi: 3.0.0")} {
+		if output, err := Decompress("api.yaml.gz", input); err != nil || string(output) != "openapi: 3.0.0" {
+			t.Errorf("Unexpected result %q, %v", output, err)
+		}
+	}
+}
+
+func TestRemoteRefEncoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/utf16.yaml":
+			w.Write([]byte("\xff\xfeP\x00e\x00t\x00:\x00 \x00\xe9\x00"))
+		default:
+			w.Write([]byte("\x00\x00\xfe\xff"))
+		}
+	}))
+	defer server.Close()
+	ClearCaches()
+	info, err := ReadInfoForRef("openapi.yaml", server.URL+"/utf16.yaml#/Pet")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if info.Value != "é" {
+		t.Errorf("unexpected value %q", info.Value)
+	}
+	_, err = ReadInfoForRef("openapi.yaml", server.URL+"/utf32.yaml#/Pet")
+	if expected := server.URL + "/utf32.yaml: unsupported encoding UTF-32BE"; err == nil || err.Error() != expected {
+		t.Errorf("unexpected error %v (expected %s)", err, expected)
+	}
+}
@@ -29,3 +29,25 @@ type ErrorGroup = compiler.ErrorGroup
 
 // NewErrorGroupOrNil returns a new ErrorGroup for a slice of errors or nil if the slice is empty.
 var NewErrorGroupOrNil = compiler.NewErrorGroupOrNil
+
+// CodedError is an Error with a stable code that identifies the problem
+// that it describes, one of the values returned by MessageCodes.
+type CodedError struct {
+	Err  *Error
+	Code string
+}
+
+// NewErrorWithCode creates an Error with a code.
+func NewErrorWithCode(context *Context, code string, message string) *CodedError {
+	return &CodedError{Err: NewError(context, message), Code: code}
+}
+
+// Error returns the string value of the Error of a CodedError.
+func (err *CodedError) Error() string {
+	return err.Err.Error()
+}
+
+// Unwrap returns the Error of a CodedError.
+func (err *CodedError) Unwrap() error {
+	return err.Err
+}
@@ -15,11 +15,226 @@
 package compiler
 
 import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
 	"github.com/google/gnostic-models/compiler"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	yaml "gopkg.in/yaml.v3"
+
+	extensions "github.com/google/gnostic/extensions"
 )
 
 // ExtensionHandler describes a binary that is called by the compiler to handle specification extensions.
 type ExtensionHandler = compiler.ExtensionHandler
 
+// extensionResult holds the memoized result of an extension handler call.
+type extensionResult struct {
+	response *anypb.Any
+	err      error
+}
+
+// ExtensionCacheStats counts the calls to extension handlers that
+// were answered from the extension cache.
+type ExtensionCacheStats struct {
+	Hits   int
+	Misses int
+}
+
+// extensionCache holds the results of the extension handler calls of
+// a compilation. Its lock protects the results and the statistics.
+type extensionCache struct {
+	mutex   sync.Mutex
+	results map[string]*extensionResult
+	stats   ExtensionCacheStats
+}
+
+// extensionCacheKey is the key of the caches that WithExtensionCache
+// attaches to contexts.
+type extensionCacheKey struct{}
+
+// WithExtensionCache returns a copy of a context that carries an empty
+// extension cache. Within a compilation that uses the context, each
+// handler is called once for each distinct extension name and value;
+// repeated calls are answered from the cache, unless the handler declared
+// that its response is nondeterministic. The cache is discarded when the
+// context is released with ReleaseUserData. Since Context is defined in
+// gnostic-models, this is a function rather than a method.
+func WithExtensionCache(context *Context) *Context {
+	return WithUserData(context, extensionCacheKey{}, &extensionCache{results: make(map[string]*extensionResult)})
+}
+
+// contextExtensionCache returns the cache that WithExtensionCache attached
+// to a context or to one of its ancestors, or nil if there is none.
+func contextExtensionCache(context *Context) *extensionCache {
+	if value, ok := UserData(context, extensionCacheKey{}); ok {
+		if cache, ok := value.(*extensionCache); ok {
+			return cache
+		}
+	}
+	return nil
+}
+
+// GetExtensionCacheStats returns the statistics of the extension cache
+// of a context, which are zero if the context has no extension cache.
+func GetExtensionCacheStats(context *Context) ExtensionCacheStats {
+	cache := contextExtensionCache(context)
+	if cache == nil {
+		return ExtensionCacheStats{}
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.stats
+}
+
 // CallExtension calls a binary extension handler.
-var CallExtension = compiler.CallExtension
+// If the context has an extension cache, repeated calls are answered
+// from it (see WithExtensionCache).
+func CallExtension(context *Context, in *yaml.Node, extensionName string) (handled bool, response *anypb.Any, err error) {
+	if context == nil || context.ExtensionHandlers == nil {
+		return false, nil, nil
+	}
+	if trace := currentTrace(); trace != nil {
+		defer trace.StartPhase("extensions")()
+	}
+	cache := contextExtensionCache(context)
+	value := ""
+	if cache != nil {
+		value = taggedValue(in)
+	}
+	for _, handler := range *(context.ExtensionHandlers) {
+		response, err = callExtensionHandler(handler, context, cache, in, extensionName, value)
+		if response != nil {
+			return true, response, err
+		}
+	}
+	return false, nil, err
+}
+
+func callExtensionHandler(handler ExtensionHandler, context *Context, cache *extensionCache, in *yaml.Node, extensionName, value string) (*anypb.Any, error) {
+	key := handler.Name + "\x00" + extensionName + "\x00" + value
+	logger := ContextLogger(context)
+	if cache != nil {
+		cache.mutex.Lock()
+		if result, ok := cache.results[key]; ok {
+			cache.stats.Hits++
+			cache.mutex.Unlock()
+			logger.Debugf("using cached result of extension handler %s for %s", handler.Name, extensionName)
+			return cloneAny(result.response), result.err
+		}
+		cache.stats.Misses++
+		cache.mutex.Unlock()
+	}
+
+	logger.Debugf("calling extension handler %s for %s", handler.Name, extensionName)
+	response, nondeterministic, err := runExtensionHandler(handler, in, extensionName)
+
+	if cache != nil && !nondeterministic {
+		cache.mutex.Lock()
+		cache.results[key] = &extensionResult{response: response, err: err}
+		cache.mutex.Unlock()
+	}
+	return cloneAny(response), err
+}
+
+// runExtensionHandler runs the binary of an extension handler, like
+// the compiler of gnostic-models, and also returns whether the handler
+// declared that its response is nondeterministic. The response is nil
+// if the handler didn't handle the extension.
+func runExtensionHandler(handler ExtensionHandler, in *yaml.Node, extensionName string) (*anypb.Any, bool, error) {
+	if handler.Name == "" {
+		return nil, false, nil
+	}
+	yamlData, _ := yaml.Marshal(in)
+	request := &extensions.ExtensionHandlerRequest{
+		CompilerVersion: &extensions.Version{
+			Major: 0,
+			Minor: 1,
+			Patch: 0,
+		},
+		Wrapper: &extensions.Wrapper{
+			Version:       "unknown",
+			Yaml:          string(yamlData),
+			ExtensionName: extensionName,
+		},
+	}
+	requestBytes, _ := proto.Marshal(request)
+	cmd := exec.Command(handler.Name)
+	cmd.Stdin = bytes.NewReader(requestBytes)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, false, err
+	}
+	response := &extensions.ExtensionHandlerResponse{}
+	if err = proto.Unmarshal(output, response); err != nil {
+		return nil, false, err
+	}
+	nondeterministic := extensions.IsNondeterministic(response)
+	if !response.Handled {
+		return nil, nondeterministic, nil
+	}
+	if len(response.Errors) != 0 {
+		return nil, nondeterministic, fmt.Errorf("Errors when parsing: %+v for field %s by vendor extension handler %s. Details %+v", in, extensionName, handler.Name, strings.Join(response.Errors, ","))
+	}
+	return response.Value, nondeterministic, nil
+}
+
+// taggedValue returns a representation of a YAML fragment that is
+// independent of key order, formatting and comments, like canonicalValue,
+// but that keeps the tags and the text of scalars. Handlers receive the
+// YAML of extensions, so values such as 1 and 1.0 must not share results.
+func taggedValue(in *yaml.Node) string {
+	var b strings.Builder
+	writeTaggedValue(&b, in)
+	return b.String()
+}
+
+func writeTaggedValue(b *strings.Builder, in *yaml.Node) {
+	if in == nil {
+		b.WriteString("null")
+		return
+	}
+	switch in.Kind {
+	case yaml.DocumentNode:
+		for _, node := range in.Content {
+			writeTaggedValue(b, node)
+		}
+	case yaml.AliasNode:
+		writeTaggedValue(b, in.Alias)
+	case yaml.SequenceNode:
+		b.WriteString("[")
+		for i, node := range in.Content {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			writeTaggedValue(b, node)
+		}
+		b.WriteString("]")
+	case yaml.MappingNode:
+		pairs := make([]string, 0, len(in.Content)/2)
+		for i := 0; i+1 < len(in.Content); i += 2 {
+			var pair strings.Builder
+			writeTaggedValue(&pair, in.Content[i])
+			pair.WriteString(":")
+			writeTaggedValue(&pair, in.Content[i+1])
+			pairs = append(pairs, pair.String())
+		}
+		sort.Strings(pairs)
+		b.WriteString("{" + strings.Join(pairs, ",") + "}")
+	default:
+		b.WriteString(in.ShortTag() + " " + strconv.Quote(in.Value))
+	}
+}
+
+func cloneAny(a *anypb.Any) *anypb.Any {
+	if a == nil {
+		return nil
+	}
+	return proto.Clone(a).(*anypb.Any)
+}
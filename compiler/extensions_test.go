@@ -0,0 +1,107 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	extensions "github.com/google/gnostic-models/extensions"
+	gnostic_extension_v1 "github.com/google/gnostic/extensions"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// When this variable is set, the test binary acts as an extension handler,
+// which declares that it is nondeterministic if the value is
+// "nondeterministic".
+const testExtensionHandlerVariable = "GNOSTIC_TEST_EXTENSION_HANDLER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testExtensionHandlerVariable) != "" {
+		runTestExtensionHandler()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func runTestExtensionHandler() {
+	data, _ := ioutil.ReadAll(os.Stdin)
+	request := &extensions.ExtensionHandlerRequest{}
+	if err := proto.Unmarshal(data, request); err != nil {
+		os.Exit(1)
+	}
+	value, _ := anypb.New(wrapperspb.String(request.Wrapper.Yaml))
+	response := &extensions.ExtensionHandlerResponse{Handled: true, Value: value}
+	if os.Getenv(testExtensionHandlerVariable) == "nondeterministic" {
+		gnostic_extension_v1.Nondeterministic(response)
+	}
+	responseBytes, _ := proto.Marshal(response)
+	os.Stdout.Write(responseBytes)
+}
+
+func TestCallExtensionMemoization(t *testing.T) {
+	os.Setenv(testExtensionHandlerVariable, "1")
+	defer os.Unsetenv(testExtensionHandlerVariable)
+
+	handlers := []ExtensionHandler{{Name: os.Args[0]}}
+	context := WithExtensionCache(NewContextWithExtensions("$root", nil, nil, &handlers))
+	fragments := []string{
+		"{limit: 10, window: 1m}",
+		"window: 1m\nlimit: 10 # same value, different layout",
+		"{limit: 20, window: 1m}",
+		"{limit: 10.0, window: 1m} # equal numbers with different tags",
+	}
+	call := func() {
+		for _, fragment := range fragments {
+			var node yaml.Node
+			if err := yaml.Unmarshal([]byte(fragment), &node); err != nil {
+				t.Fatalf("%s", err.Error())
+			}
+			handled, response, err := CallExtension(context, node.Content[0], "x-ratelimit")
+			if !handled || response == nil || err != nil {
+				t.Fatalf("extension was not handled: %+v", err)
+			}
+		}
+	}
+
+	call()
+	if stats := GetExtensionCacheStats(context); stats.Misses != 3 || stats.Hits != 1 {
+		t.Errorf("unexpected cache statistics: %+v (expected 3 misses and 1 hit)", stats)
+	}
+
+	// Each compilation has its own cache.
+	other := WithExtensionCache(NewContextWithExtensions("$root", nil, nil, &handlers))
+	defer ReleaseUserData(other)
+	if stats := GetExtensionCacheStats(other); stats.Misses != 0 || stats.Hits != 0 {
+		t.Errorf("unexpected cache statistics of a new cache: %+v", stats)
+	}
+	ReleaseUserData(context)
+	if stats := GetExtensionCacheStats(context); stats.Misses != 0 || stats.Hits != 0 {
+		t.Errorf("unexpected cache statistics of a released cache: %+v", stats)
+	}
+
+	// Responses that handlers declare nondeterministic are not reused.
+	os.Setenv(testExtensionHandlerVariable, "nondeterministic")
+	context = WithExtensionCache(NewContextWithExtensions("$root", nil, nil, &handlers))
+	defer ReleaseUserData(context)
+	call()
+	if stats := GetExtensionCacheStats(context); stats.Misses != 4 || stats.Hits != 0 {
+		t.Errorf("unexpected cache statistics: %+v (expected 4 misses and no hits)", stats)
+	}
+}
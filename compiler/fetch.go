@@ -0,0 +1,307 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// A Fetcher reads remote files. FetchFile, ReadBytesForFile and
+// ReadInfoForRef use the Fetcher that was set with SetFetcher to read
+// files that are named by URLs.
+type Fetcher interface {
+	Fetch(fileurl string) ([]byte, error)
+}
+
+var (
+	fetcherMutex sync.Mutex
+	fetcher      Fetcher = &HTTPFetcher{}
+)
+
+// SetFetcher sets the Fetcher that reads remote files. If f is nil, the
+// default Fetcher, an HTTPFetcher without a cache directory, is restored.
+func SetFetcher(f Fetcher) {
+	fetcherMutex.Lock()
+	defer fetcherMutex.Unlock()
+	if f == nil {
+		f = &HTTPFetcher{}
+	}
+	fetcher = f
+}
+
+func currentFetcher() Fetcher {
+	fetcherMutex.Lock()
+	defer fetcherMutex.Unlock()
+	return fetcher
+}
+
+// DefaultFetchBackoff is the delay before the first retry of an HTTPFetcher
+// that doesn't set Backoff.
+const DefaultFetchBackoff = 100 * time.Millisecond
+
+// maxFetchBackoff bounds the delay between retries.
+const maxFetchBackoff = 10 * time.Second
+
+// HTTPFetcher reads remote files with HTTP GET requests.
+type HTTPFetcher struct {
+	// Client sends the requests. If it is nil, http.DefaultClient is used.
+	Client *http.Client
+	// CacheDir is a directory that stores responses with their ETag and
+	// Last-Modified headers. Stored responses are revalidated with
+	// conditional requests and reused when the server replies with
+	// 304 Not Modified. If CacheDir is empty, nothing is stored.
+	CacheDir string
+	// Retries is the number of times that a request is retried after a
+	// network error or a 5xx response.
+	Retries int
+	// Backoff is the delay before the first retry. It doubles with each
+	// retry, up to ten seconds. If it is zero, DefaultFetchBackoff is used.
+	Backoff time.Duration
+	// MaxBytes limits the size of responses. Reading stops after MaxBytes
+	// bytes and larger responses fail with ErrFileTooLarge. If it is zero,
+	// responses of any size are read.
+	MaxBytes int64
+	// Logger receives the requests, retries and revalidated responses.
+	// If it is nil, the Logger that was set with SetLogger is used.
+	Logger Logger
+}
+
+func (f *HTTPFetcher) logger() Logger {
+	if f.Logger != nil {
+		return f.Logger
+	}
+	return CurrentLogger()
+}
+
+// ErrFileTooLarge is returned when a file is larger than the limit
+// of an HTTPFetcher or a LimitedLoader.
+var ErrFileTooLarge = errors.New("file is too large")
+
+// httpCacheEntry describes a response that is stored in a cache directory.
+// The body is stored in a separate file.
+type httpCacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+
+	body []byte
+}
+
+// Fetch reads a file, retrying failed requests.
+func (f *HTTPFetcher) Fetch(fileurl string) ([]byte, error) {
+	entry := f.readCacheEntry(fileurl)
+	backoff := f.Backoff
+	if backoff <= 0 {
+		backoff = DefaultFetchBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		f.logger().Debugf("fetching %s", fileurl)
+		bytes, retry, err := f.fetch(fileurl, entry)
+		if err == nil || !retry || attempt >= f.Retries {
+			return bytes, err
+		}
+		f.logger().Warnf("retrying %s in %s: %s", fileurl, backoff, err.Error())
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxFetchBackoff {
+			backoff = maxFetchBackoff
+		}
+	}
+}
+
+// fetch sends a single request and reports whether a failed request can be retried.
+func (f *HTTPFetcher) fetch(fileurl string, entry *httpCacheEntry) ([]byte, bool, error) {
+	request, err := http.NewRequest(http.MethodGet, fileurl, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if entry != nil {
+		if entry.ETag != "" {
+			request.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			request.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, true, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotModified && entry != nil {
+		if trace := currentTrace(); trace != nil {
+			trace.addHTTPCacheHit(fileurl)
+		}
+		f.logger().Debugf("using cached response for %s", fileurl)
+		if f.MaxBytes > 0 && int64(len(entry.body)) > f.MaxBytes {
+			return nil, false, ErrFileTooLarge
+		}
+		return entry.body, false, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, response.StatusCode >= 500, fmt.Errorf("Error downloading %s: %s", fileurl, response.Status)
+	}
+	body := io.Reader(response.Body)
+	// Responses are decompressed here if the transport didn't do it.
+	if response.Header.Get("Content-Encoding") == "gzip" && !response.Uncompressed {
+		reader, err := gzip.NewReader(response.Body)
+		if err != nil {
+			return nil, false, fmt.Errorf("Error downloading %s: %s", fileurl, err.Error())
+		}
+		defer reader.Close()
+		body = reader
+	}
+	if f.MaxBytes > 0 {
+		body = io.LimitReader(body, f.MaxBytes+1)
+	}
+	bytes, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, true, err
+	}
+	if f.MaxBytes > 0 && int64(len(bytes)) > f.MaxBytes {
+		return nil, false, ErrFileTooLarge
+	}
+	f.writeCacheEntry(&httpCacheEntry{
+		URL:          fileurl,
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+		body:         bytes,
+	})
+	return bytes, false, nil
+}
+
+// cachePath returns the name of the cache file for a URL. Bodies are
+// stored without an extension, entries with ".json".
+func (f *HTTPFetcher) cachePath(fileurl string) string {
+	sum := sha256.Sum256([]byte(fileurl))
+	return filepath.Join(f.CacheDir, hex.EncodeToString(sum[:]))
+}
+
+// readCacheEntry returns the stored response for a URL, or nil.
+func (f *HTTPFetcher) readCacheEntry(fileurl string) *httpCacheEntry {
+	if f.CacheDir == "" {
+		return nil
+	}
+	path := f.cachePath(fileurl)
+	bytes, err := ioutil.ReadFile(path + ".json")
+	if err != nil {
+		return nil
+	}
+	entry := &httpCacheEntry{}
+	if err := json.Unmarshal(bytes, entry); err != nil || entry.URL != fileurl {
+		return nil
+	}
+	if entry.body, err = ioutil.ReadFile(path); err != nil {
+		return nil
+	}
+	return entry
+}
+
+// writeCacheEntry stores a response that can be revalidated. Failures
+// are ignored because the cache is only an optimization.
+func (f *HTTPFetcher) writeCacheEntry(entry *httpCacheEntry) {
+	if f.CacheDir == "" || (entry.ETag == "" && entry.LastModified == "") {
+		return
+	}
+	if err := os.MkdirAll(f.CacheDir, 0755); err != nil {
+		return
+	}
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	path := f.cachePath(entry.URL)
+	if err := ioutil.WriteFile(path, entry.body, 0644); err != nil {
+		return
+	}
+	ioutil.WriteFile(path+".json", bytes, 0644)
+}
+
+// The file cache holds the contents of remote files that were read
+// with the current Fetcher.
+var (
+	fileCacheMutex  sync.Mutex
+	fileCache       = make(map[string][]byte)
+	fileCacheEnable = true
+)
+
+// fetchRemoteFile reads a remote file from the file cache or with the current Fetcher.
+func fetchRemoteFile(fileurl string, logger Logger) ([]byte, error) {
+	return fetchRemoteFileWith(fetcherWithLogger(currentFetcher(), logger), fileurl, logger)
+}
+
+// fetcherWithLogger returns a copy of an HTTPFetcher without a Logger that
+// logs to logger, or the Fetcher itself.
+func fetcherWithLogger(f Fetcher, logger Logger) Fetcher {
+	if h, ok := f.(*HTTPFetcher); ok && h.Logger == nil {
+		logged := *h
+		logged.Logger = logger
+		return &logged
+	}
+	return f
+}
+
+// fetchRemoteFileWith reads a remote file from the file cache or with a Fetcher.
+// Cache hits are logged to logger.
+func fetchRemoteFileWith(f Fetcher, fileurl string, logger Logger) ([]byte, error) {
+	if bytes, ok := cachedFile(fileurl); ok {
+		logger.Debugf("using cached %s", fileurl)
+		return bytes, nil
+	}
+	bytes, err := f.Fetch(fileurl)
+	if err != nil {
+		return nil, err
+	}
+	fileCacheMutex.Lock()
+	defer fileCacheMutex.Unlock()
+	if fileCacheEnable {
+		fileCache[fileurl] = bytes
+	}
+	return bytes, nil
+}
+
+// cachedFile returns the contents of a file in the file cache.
+func cachedFile(fileurl string) ([]byte, bool) {
+	fileCacheMutex.Lock()
+	defer fileCacheMutex.Unlock()
+	if !fileCacheEnable {
+		return nil, false
+	}
+	bytes, ok := fileCache[fileurl]
+	return bytes, ok
+}
+
+// isRemoteFile returns true if a filename is a URL.
+func isRemoteFile(filename string) bool {
+	u, err := url.Parse(filename)
+	return err == nil && u.Scheme != ""
+}
@@ -0,0 +1,157 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const schemaSource = "definitions:\n  Pet:\n    type: object\n"
+
+func TestHTTPFetcherCache(t *testing.T) {
+	var mutex sync.Mutex
+	var conditional, modified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if r.Header.Get("If-None-Match") == `"v1"` &&
+			r.Header.Get("If-Modified-Since") == "Mon, 02 Jan 2023 15:04:05 GMT" {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		modified++
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2023 15:04:05 GMT")
+		w.Write([]byte(schemaSource))
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "gnostic-fetch")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	fetcher := &HTTPFetcher{CacheDir: dir}
+	for i := 0; i < 2; i++ {
+		bytes, err := fetcher.Fetch(server.URL + "/schemas.yaml")
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if string(bytes) != schemaSource {
+			t.Errorf("unexpected contents: %s", bytes)
+		}
+	}
+	if modified != 1 || conditional != 1 {
+		t.Errorf("unexpected requests: %d full, %d conditional", modified, conditional)
+	}
+
+	// References are resolved with the current fetcher and cache hits are traced.
+	SetFetcher(fetcher)
+	defer SetFetcher(nil)
+	ClearCaches()
+	trace := StartTrace()
+	defer StopTrace()
+	ref := server.URL + "/schemas.yaml#/definitions/Pet"
+	node := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{NewScalarNodeForString("$ref"), NewScalarNodeForString(ref)}}
+	PrefetchReferences("openapi.yaml", node)
+	info, ok := GetInfoCache()[ref]
+	if !ok || info == nil {
+		t.Fatalf("%s was not prefetched", ref)
+	}
+	if kind, _ := StringForScalarNode(MapValueForKey(info, "type")); kind != "object" {
+		t.Errorf("unexpected target: %+v", info)
+	}
+	if _, err := ReadInfoForRef("openapi.yaml", ref); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(trace.Fetches) != 1 || !trace.Fetches[0].HTTPCached || trace.Fetches[0].CacheHits != 1 {
+		t.Errorf("unexpected fetches: %+v", trace.Fetches)
+	}
+	if modified != 1 || conditional != 2 {
+		t.Errorf("unexpected requests: %d full, %d conditional", modified, conditional)
+	}
+}
+
+func TestHTTPFetcherGzip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := Compress([]byte(schemaSource))
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(data)
+	}))
+	defer server.Close()
+	// The transport doesn't decompress responses to requests that it
+	// didn't ask to compress, so the fetcher decompresses them.
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	for _, fetcher := range []*HTTPFetcher{{}, {Client: client}} {
+		bytes, err := fetcher.Fetch(server.URL + "/schemas.yaml")
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if string(bytes) != schemaSource {
+			t.Errorf("Unexpected response %q", bytes)
+		}
+	}
+}
+
+func TestHTTPFetcherRetries(t *testing.T) {
+	var mutex sync.Mutex
+	var requests, failures int
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests++
+		if requests <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(schemaSource))
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		status   int
+		failures int
+		retries  int
+		requests int
+		ok       bool
+	}{
+		{http.StatusServiceUnavailable, 2, 2, 3, true},
+		{http.StatusServiceUnavailable, 2, 1, 2, false},
+		{http.StatusInternalServerError, 1, 3, 2, true},
+		{http.StatusNotFound, 1, 3, 1, false},
+	} {
+		mutex.Lock()
+		requests, failures, status = 0, test.failures, test.status
+		mutex.Unlock()
+		fetcher := &HTTPFetcher{Retries: test.retries, Backoff: time.Millisecond}
+		_, err := fetcher.Fetch(server.URL + "/schemas.yaml")
+		if (err == nil) != test.ok {
+			t.Errorf("%+v: unexpected result %v", test, err)
+		}
+		if requests != test.requests {
+			t.Errorf("%+v: %d requests", test, requests)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// The limits of ParseOptions that don't set them. They are far beyond
+// the needs of real API descriptions, but keep a Compiler from exhausting
+// memory with a crafted or accidentally enormous document.
+const (
+	// DefaultMaxRefDepth is the default MaxRefDepth.
+	DefaultMaxRefDepth = 32
+	// DefaultMaxFetchedBytes is the default MaxFetchedBytes, 256 MiB.
+	DefaultMaxFetchedBytes = 256 << 20
+	// DefaultMaxAliasExpansions is the default MaxAliasExpansions.
+	DefaultMaxAliasExpansions = 1000000
+	// DefaultMaxAliasDepth is the default MaxAliasDepth.
+	DefaultMaxAliasDepth = 64
+	// DefaultMaxNodes is the default MaxNodes.
+	DefaultMaxNodes = 10000000
+)
+
+// limitCodes are the codes of messages that report exceeded limits.
+var limitCodes = map[string]bool{
+	CodeTimeout:                     true,
+	CodeFetchLimitExceeded:          true,
+	CodeAliasExpansionLimitExceeded: true,
+	CodeAliasDepthLimitExceeded:     true,
+	CodeNodeLimitExceeded:           true,
+	CodeRefDepthLimitExceeded:       true,
+}
+
+// LimitExceeded returns true if an error reports that a Compiler exceeded
+// its Timeout or one of its limits. Such errors are returned in any mode.
+func LimitExceeded(err error) bool {
+	if err, ok := err.(*CodedError); ok {
+		return limitCodes[err.Code]
+	}
+	return false
+}
+
+// A LimitedLoader is a Loader that stops reading files that are larger
+// than a limit, so that they aren't held in memory. A Compiler with a
+// MaxFetchedBytes limit uses LoadLimited instead of Load.
+type LimitedLoader interface {
+	Loader
+	// LoadLimited reads a file, or returns ErrFileTooLarge if the file
+	// has more than limit bytes.
+	LoadLimited(filename string, limit int64) ([]byte, error)
+}
+
+// defaultLoader is the LimitedLoader that is DefaultLoader. Compilers
+// copy it with their Logger.
+type defaultLoader struct {
+	// logger receives the names of cached remote files. If it is nil,
+	// the Logger that was set with SetLogger is used.
+	logger Logger
+}
+
+// loggerOrDefault returns the Logger of a defaultLoader.
+func (l defaultLoader) loggerOrDefault() Logger {
+	if l.logger != nil {
+		return l.logger
+	}
+	return CurrentLogger()
+}
+
+// Load reads a file with ReadBytesForFile.
+func (l defaultLoader) Load(filename string) ([]byte, error) {
+	return traceBytesForFile(filename, l.loggerOrDefault())
+}
+
+// LoadLimited reads a file like ReadBytesForFile, but stops after limit
+// bytes. Remote files are read with a copy of the current Fetcher that has
+// the limit if it is an HTTPFetcher, and are checked after reading otherwise.
+func (l defaultLoader) LoadLimited(filename string, limit int64) ([]byte, error) {
+	trace := currentTrace()
+	if trace == nil {
+		return readBytesForFileLimited(filename, limit, l.loggerOrDefault())
+	}
+	start := time.Now()
+	bytes, err := readBytesForFileLimited(filename, limit, l.loggerOrDefault())
+	trace.addFetch(filename, bytes, time.Since(start), err)
+	return bytes, err
+}
+
+func readBytesForFileLimited(filename string, limit int64, logger Logger) ([]byte, error) {
+	var bytes []byte
+	var err error
+	if isRemoteFile(filename) {
+		f := fetcherWithLogger(currentFetcher(), logger)
+		if h, ok := f.(*HTTPFetcher); ok && (h.MaxBytes <= 0 || h.MaxBytes > limit) {
+			limited := *h
+			limited.MaxBytes = limit
+			f = &limited
+		}
+		bytes, err = fetchRemoteFileWith(f, filename, logger)
+	} else {
+		var file *os.File
+		if file, err = os.Open(filename); err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		bytes, err = ioutil.ReadAll(io.LimitReader(file, limit+1))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(bytes)) > limit {
+		return nil, ErrFileTooLarge
+	}
+	return bytes, nil
+}
+
+// maxNodeCount bounds node counts, which grow exponentially with the
+// nesting of aliases, so that they don't overflow.
+const maxNodeCount = int64(1) << 62
+
+// nodeCounts describe a node as it is after its aliases are expanded.
+type nodeCounts struct {
+	// nodes is the number of nodes, including the node itself.
+	nodes int64
+	// expansions is the number of nodes that are added by expanding aliases.
+	expansions int64
+	// depth is the nesting of aliases in the nodes that they refer to.
+	depth int
+	// counting is true while the node's children are counted.
+	counting bool
+}
+
+func (c *nodeCounts) add(nodes, expansions int64, depth int) {
+	c.nodes = saturatedSum(c.nodes, nodes)
+	c.expansions = saturatedSum(c.expansions, expansions)
+	if depth > c.depth {
+		c.depth = depth
+	}
+}
+
+func saturatedSum(a, b int64) int64 {
+	if a > maxNodeCount-b {
+		return maxNodeCount
+	}
+	return a + b
+}
+
+// countNodes counts the nodes of a node without expanding its aliases.
+// Counts are computed once for each node, so documents that would expand
+// to billions of nodes are counted quickly. As in ExpandAliases, aliases
+// of nodes that contain them count as empty mappings.
+func countNodes(node *yaml.Node, counts map[*yaml.Node]*nodeCounts) *nodeCounts {
+	if c, ok := counts[node]; ok {
+		return c
+	}
+	c := &nodeCounts{nodes: 1, counting: true}
+	counts[node] = c
+	for _, child := range node.Content {
+		if child.Kind != yaml.AliasNode {
+			n := countNodes(child, counts)
+			c.add(n.nodes, n.expansions, n.depth)
+		} else if n, ok := counts[child.Alias]; child.Alias == nil || ok && n.counting {
+			c.add(1, 1, 1)
+		} else {
+			n := countNodes(child.Alias, counts)
+			c.add(n.nodes, n.nodes, n.depth+1)
+		}
+	}
+	c.counting = false
+	return c
+}
+
+// checkNodes returns an error if a parsed file exceeds the alias and
+// node limits of a Compiler.
+func (c *Compiler) checkNodes(filename string, info *yaml.Node) error {
+	counts := countNodes(info, make(map[*yaml.Node]*nodeCounts))
+	if max := c.options.MaxAliasDepth; max > 0 && counts.depth > max {
+		return NewErrorWithCode(nil, CodeAliasDepthLimitExceeded, fmt.Sprintf("aliases in %s are nested more than %d deep", filename, max))
+	}
+	if max := c.options.MaxAliasExpansions; max > 0 && counts.expansions > max {
+		return NewErrorWithCode(nil, CodeAliasExpansionLimitExceeded, fmt.Sprintf("aliases in %s expand to more than %d nodes", filename, max))
+	}
+	if max := c.options.MaxNodes; max > 0 && counts.nodes > max {
+		return NewErrorWithCode(nil, CodeNodeLimitExceeded, fmt.Sprintf("%s exceeds the node limit of %d", filename, max))
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// laughs is a document whose aliases expand to 10^9 nodes.
+const laughs = `a: &a ["lol","lol","lol","lol","lol","lol","lol","lol","lol","lol"]
+b: &b [*a,*a,*a,*a,*a,*a,*a,*a,*a,*a]
+c: &c [*b,*b,*b,*b,*b,*b,*b,*b,*b,*b]
+d: &d [*c,*c,*c,*c,*c,*c,*c,*c,*c,*c]
+e: &e [*d,*d,*d,*d,*d,*d,*d,*d,*d,*d]
+f: &f [*e,*e,*e,*e,*e,*e,*e,*e,*e,*e]
+g: &g [*f,*f,*f,*f,*f,*f,*f,*f,*f,*f]
+h: &h [*g,*g,*g,*g,*g,*g,*g,*g,*g,*g]
+i: &i [*h,*h,*h,*h,*h,*h,*h,*h,*h,*h]
+`
+
+// nested is a document with aliases nested three deep.
+const nested = `a: &a {x: 1}
+b: &b {a: *a}
+c: &c {b: *b}
+d: {c: *c}
+`
+
+// errorCode returns the message code of an error.
+func errorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	return MessagesForError(err, "")[0].Code()
+}
+
+func TestCompilerLimits(t *testing.T) {
+	ClearInfoCache()
+	defer ClearInfoCache()
+	files := map[string]string{
+		"laughs.yaml": laughs,
+		"nested.yaml": nested,
+	}
+	for filename, text := range optionsFiles {
+		files[filename] = text
+	}
+	for _, test := range []struct {
+		name     string
+		filename string
+		options  ParseOptions
+		code     string
+	}{
+		{"default alias expansions", "laughs.yaml", ParseOptions{}, CodeAliasExpansionLimitExceeded},
+		{"alias expansions", "nested.yaml", ParseOptions{MaxAliasExpansions: 5}, CodeAliasExpansionLimitExceeded},
+		{"alias depth", "nested.yaml", ParseOptions{MaxAliasDepth: 2}, CodeAliasDepthLimitExceeded},
+		{"unlimited alias depth", "nested.yaml", ParseOptions{MaxAliasDepth: -1}, ""},
+		{"nodes", "nested.yaml", ParseOptions{MaxNodes: 20}, CodeNodeLimitExceeded},
+		{"unlimited nodes", "nested.yaml", ParseOptions{MaxNodes: -1}, ""},
+		{"fetched bytes", "api/openapi.yaml", ParseOptions{MaxFetchedBytes: 100}, CodeFetchLimitExceeded},
+		{"unlimited fetched bytes", "api/openapi.yaml", ParseOptions{MaxFetchedBytes: -1}, ""},
+		{"ref depth", "api/openapi.yaml", ParseOptions{MaxRefDepth: 1}, CodeRefDepthLimitExceeded},
+	} {
+		c := NewCompilerWithOptions(ParseOptions{
+			MaxRefDepth:        test.options.MaxRefDepth,
+			MaxFetchedBytes:    test.options.MaxFetchedBytes,
+			MaxAliasExpansions: test.options.MaxAliasExpansions,
+			MaxAliasDepth:      test.options.MaxAliasDepth,
+			MaxNodes:           test.options.MaxNodes,
+			Loader:             mapLoader(files, make(map[string]int)),
+		})
+		_, err := c.Compile(test.filename)
+		if code := errorCode(err); test.code == "" && err != nil {
+			t.Errorf("%s: %+v", test.name, err)
+		} else if code != test.code {
+			t.Errorf("%s: got %v (%s), want %s", test.name, err, code, test.code)
+		}
+	}
+}
+
+func TestCompilerLimitedLoader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnostic-limits")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "openapi.yaml")
+	if err := ioutil.WriteFile(filename, []byte(strings.Repeat("# padding\n", 50)+"openapi: 3.0.0\n"), 0644); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	c := NewCompilerWithOptions(ParseOptions{MaxFetchedBytes: 1000})
+	if _, err := c.ReadBytes(filename); err != nil {
+		t.Errorf("%+v", err)
+	}
+	// The limit is shared by all reads of a Compiler.
+	if _, err := c.ReadBytes(filename); !LimitExceeded(err) {
+		t.Errorf("got %v, want the limit to be exceeded", err)
+	}
+	c = NewCompilerWithOptions(ParseOptions{MaxFetchedBytes: 100})
+	if _, err := c.ReadBytes(filename); errorCode(err) != CodeFetchLimitExceeded {
+		t.Errorf("got %v, want the limit to be exceeded", err)
+	}
+}
+
+func TestHTTPFetcherMaxBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(schemaSource))
+	}))
+	defer server.Close()
+	fetcher := &HTTPFetcher{MaxBytes: int64(len(schemaSource))}
+	if _, err := fetcher.Fetch(server.URL + "/schemas.yaml"); err != nil {
+		t.Errorf("%+v", err)
+	}
+	fetcher.MaxBytes--
+	if _, err := fetcher.Fetch(server.URL + "/schemas.yaml"); err != ErrFileTooLarge {
+		t.Errorf("got %v, want ErrFileTooLarge", err)
+	}
+}
+
+func TestCompilerDecompress(t *testing.T) {
+	text := strings.Repeat("# padding\n", 50) + "openapi: 3.0.0\n"
+	data, err := Compress([]byte(text))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, test := range []struct {
+		name     string
+		maxBytes int64
+		exceeded bool
+	}{
+		{"default", 0, false},
+		{"limited", 100, true},
+		{"unlimited", -1, false},
+	} {
+		c := NewCompilerWithOptions(ParseOptions{MaxFetchedBytes: test.maxBytes})
+		contents, err := c.Decompress("openapi.yaml.gz", data)
+		if test.exceeded {
+			if err == nil || !strings.Contains(err.Error(), "expands to more than 100 bytes") {
+				t.Errorf("%s: got %v, want the limit to be exceeded", test.name, err)
+			}
+		} else if err != nil || string(contents) != text {
+			t.Errorf("%s: unexpected result %q, %v", test.name, contents, err)
+		}
+	}
+	// Files are only decompressed once.
+	twice, err := Compress(data)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, err := NewCompilerWithOptions(ParseOptions{}).ParseInfo("openapi.yaml.gz", twice); err == nil {
+		t.Errorf("a file that was compressed twice was decompressed twice")
+	}
+}
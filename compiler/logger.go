@@ -0,0 +1,106 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// A Logger receives the messages that gnostic logs while it reads files,
+// calls extension handlers and plugins, and generates code. Programs
+// that embed gnostic can set a Logger to redirect or correlate them.
+type Logger interface {
+	// Debugf logs details such as the files that are read.
+	Debugf(format string, args ...interface{})
+	// Infof logs progress.
+	Infof(format string, args ...interface{})
+	// Warnf logs problems that don't stop processing.
+	Warnf(format string, args ...interface{})
+}
+
+// A LogLevel is the lowest level of the messages that a StandardLogger writes.
+type LogLevel int
+
+// Log levels in increasing order of importance.
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	// LogLevelNone writes no messages.
+	LogLevelNone
+)
+
+// StandardLogger writes messages at or above Level with a log.Logger.
+// The default Logger is a StandardLogger that writes warnings with the
+// standard logger of the log package.
+type StandardLogger struct {
+	Level LogLevel
+	// Output writes the messages. If it is nil, the standard logger
+	// of the log package is used.
+	Output *log.Logger
+}
+
+// Debugf logs a message at LogLevelDebug.
+func (l *StandardLogger) Debugf(format string, args ...interface{}) {
+	l.logf(LogLevelDebug, format, args...)
+}
+
+// Infof logs a message at LogLevelInfo.
+func (l *StandardLogger) Infof(format string, args ...interface{}) {
+	l.logf(LogLevelInfo, format, args...)
+}
+
+// Warnf logs a message at LogLevelWarn.
+func (l *StandardLogger) Warnf(format string, args ...interface{}) {
+	l.logf(LogLevelWarn, format, args...)
+}
+
+func (l *StandardLogger) logf(level LogLevel, format string, args ...interface{}) {
+	if level < l.Level {
+		return
+	}
+	if l.Output != nil {
+		l.Output.Output(3, fmt.Sprintf(format, args...))
+	} else {
+		log.Output(3, fmt.Sprintf(format, args...))
+	}
+}
+
+var (
+	loggerMutex sync.Mutex
+	logger      Logger = &StandardLogger{Level: LogLevelWarn}
+)
+
+// SetLogger sets the Logger that is used when no other Logger is given,
+// such as by the extension handler driver and by Compilers and
+// HTTPFetchers without a Logger. If l is nil, the default Logger is
+// restored.
+func SetLogger(l Logger) {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	if l == nil {
+		l = &StandardLogger{Level: LogLevelWarn}
+	}
+	logger = l
+}
+
+// CurrentLogger returns the Logger that was set with SetLogger.
+func CurrentLogger() Logger {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	return logger
+}
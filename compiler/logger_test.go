@@ -0,0 +1,204 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// fakeLogger records messages with their levels.
+type fakeLogger struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+func (l *fakeLogger) add(level, format string, args ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.messages = append(l.messages, level+" "+fmt.Sprintf(format, args...))
+}
+
+func (l *fakeLogger) Debugf(format string, args ...interface{}) { l.add("debug", format, args...) }
+func (l *fakeLogger) Infof(format string, args ...interface{})  { l.add("info", format, args...) }
+func (l *fakeLogger) Warnf(format string, args ...interface{})  { l.add("warn", format, args...) }
+
+func (l *fakeLogger) expect(t *testing.T, expected ...string) {
+	t.Helper()
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if strings.Join(l.messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected messages:\n%s\nexpected:\n%s", strings.Join(l.messages, "\n"), strings.Join(expected, "\n"))
+	}
+	l.messages = nil
+}
+
+func TestLoggerFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(schemaSource))
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "gnostic-logger")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	logger := &fakeLogger{}
+	fetcher := &HTTPFetcher{CacheDir: dir, Logger: logger}
+	url := server.URL + "/schemas.yaml"
+	for i := 0; i < 2; i++ {
+		if _, err := fetcher.Fetch(url); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+	logger.expect(t,
+		"debug fetching "+url,
+		"debug fetching "+url,
+		"debug using cached response for "+url)
+}
+
+func TestLoggerCompiler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnostic-logger")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "schemas.yaml")
+	if err := ioutil.WriteFile(filename, []byte(schemaSource), 0644); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	logger := &fakeLogger{}
+	c := NewCompilerWithOptions(ParseOptions{Logger: logger})
+	for i := 0; i < 2; i++ {
+		if _, err := c.ReadInfo(filename); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+	logger.expect(t,
+		"debug reading "+filename,
+		"debug using cached "+filename)
+}
+
+func TestLoggerExtensions(t *testing.T) {
+	os.Setenv(testExtensionHandlerVariable, "1")
+	defer os.Unsetenv(testExtensionHandlerVariable)
+	logger := &fakeLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+
+	handlers := []ExtensionHandler{{Name: os.Args[0]}}
+	context := WithExtensionCache(NewContextWithExtensions("$root", nil, nil, &handlers))
+	defer ReleaseUserData(context)
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte("{limit: 10}"), &node); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if handled, _, err := CallExtension(context, node.Content[0], "x-ratelimit"); !handled || err != nil {
+			t.Fatalf("extension was not handled: %+v", err)
+		}
+	}
+	logger.expect(t,
+		"debug calling extension handler "+os.Args[0]+" for x-ratelimit",
+		"debug using cached result of extension handler "+os.Args[0]+" for x-ratelimit")
+}
+
+func TestLoggerContextExtensions(t *testing.T) {
+	os.Setenv(testExtensionHandlerVariable, "1")
+	defer os.Unsetenv(testExtensionHandlerVariable)
+	global := &fakeLogger{}
+	SetLogger(global)
+	defer SetLogger(nil)
+
+	logger := &fakeLogger{}
+	handlers := []ExtensionHandler{{Name: os.Args[0]}}
+	context := WithLogger(WithExtensionCache(NewContextWithExtensions("$root", nil, nil, &handlers)), logger)
+	defer ReleaseUserData(context)
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte("{limit: 10}"), &node); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	child := NewContextFromNode("limits", node.Content[0], context)
+	for i := 0; i < 2; i++ {
+		if handled, _, err := CallExtension(child, node.Content[0], "x-ratelimit"); !handled || err != nil {
+			t.Fatalf("extension was not handled: %+v", err)
+		}
+	}
+	logger.expect(t,
+		"debug calling extension handler "+os.Args[0]+" for x-ratelimit",
+		"debug using cached result of extension handler "+os.Args[0]+" for x-ratelimit")
+	global.expect(t)
+}
+
+func TestLoggerCompilerRemoteFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(schemaSource))
+	}))
+	defer server.Close()
+	global := &fakeLogger{}
+	SetLogger(global)
+	defer SetLogger(nil)
+
+	ClearCaches()
+	logger := &fakeLogger{}
+	c := NewCompilerWithOptions(ParseOptions{Logger: logger})
+	url := server.URL + "/schemas.yaml"
+	for i := 0; i < 2; i++ {
+		if _, err := c.ReadBytes(url); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+	logger.expect(t,
+		"debug reading "+url,
+		"debug fetching "+url,
+		"debug reading "+url,
+		"debug using cached "+url)
+	global.expect(t)
+}
+
+func TestStandardLogger(t *testing.T) {
+	var b bytes.Buffer
+	logger := &StandardLogger{Level: LogLevelInfo, Output: log.New(&b, "", 0)}
+	logger.Debugf("debug %d", 1)
+	logger.Infof("info %d", 2)
+	logger.Warnf("warn %d", 3)
+	if b.String() != "info 2\nwarn 3\n" {
+		t.Errorf("unexpected output %q", b.String())
+	}
+	b.Reset()
+	logger.Level = LogLevelNone
+	logger.Warnf("warn %d", 4)
+	if b.Len() != 0 {
+		t.Errorf("unexpected output %q", b.String())
+	}
+}
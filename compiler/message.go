@@ -0,0 +1,208 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// MessageKindError describes messages for errors that prevent compilation.
+	MessageKindError = "error"
+	// MessageKindWarning describes messages for problems that don't prevent compilation.
+	MessageKindWarning = "warning"
+)
+
+// Stable, machine-readable codes of the problems described by Messages.
+const (
+	// CodeUnknown describes problems that are not otherwise classified.
+	CodeUnknown = "UNKNOWN"
+	// CodeInvalidYAML describes text that can't be parsed as YAML or JSON.
+	CodeInvalidYAML = "INVALID_YAML"
+	// CodeUnsupportedEncoding describes text in an encoding other than UTF-8 or UTF-16.
+	CodeUnsupportedEncoding = "UNSUPPORTED_ENCODING"
+	// CodeUnknownVersion describes documents that aren't OpenAPI or Discovery documents.
+	CodeUnknownVersion = "UNKNOWN_VERSION"
+	// CodeFetchFailed describes files that could not be read.
+	CodeFetchFailed = "FETCH_FAILED"
+	// CodeMissingRequiredProperty describes objects without a required property.
+	CodeMissingRequiredProperty = "MISSING_REQUIRED_PROPERTY"
+	// CodeInvalidProperty describes properties that are not allowed in an object.
+	CodeInvalidProperty = "INVALID_PROPERTY"
+	// CodeUnexpectedValue describes values of the wrong type or format.
+	CodeUnexpectedValue = "UNEXPECTED_VALUE"
+	// CodeInvalidAlternative describes values that match none of the
+	// alternatives of a union, such as a schema or a reference.
+	CodeInvalidAlternative = "INVALID_ALTERNATIVE"
+	// CodeMissingRefTarget describes $refs whose targets can't be found.
+	CodeMissingRefTarget = "MISSING_REF_TARGET"
+	// CodeDuplicateOperationID describes operations that share an operationId.
+	CodeDuplicateOperationID = "DUPLICATE_OPERATION_ID"
+	// CodeDeprecatedWithoutDescription describes deprecated operations
+	// that don't explain their deprecation.
+	CodeDeprecatedWithoutDescription = "DEPRECATED_WITHOUT_DESCRIPTION"
+	// CodeUnresolvedExample describes examples that can't be resolved.
+	CodeUnresolvedExample = "UNRESOLVED_EXAMPLE"
+	// CodeDuplicateInlineComponent describes identical inline parameters,
+	// responses or headers that could be components.
+	CodeDuplicateInlineComponent = "DUPLICATE_INLINE_COMPONENT"
+	// CodeInvalidExample describes examples that don't conform to their schemas.
+	CodeInvalidExample = "INVALID_EXAMPLE"
+	// CodeGRPCMismatch describes operations that differ from the
+	// grpc-gateway bindings of their RPC methods.
+	CodeGRPCMismatch = "GRPC_MISMATCH"
+	// CodeTimeout describes files that could not be read in time.
+	CodeTimeout = "TIMEOUT"
+	// CodeFetchLimitExceeded describes files that exceed the limit of
+	// fetched bytes of a Compiler.
+	CodeFetchLimitExceeded = "FETCH_LIMIT_EXCEEDED"
+	// CodeAliasExpansionLimitExceeded describes files whose YAML aliases
+	// expand to too many nodes.
+	CodeAliasExpansionLimitExceeded = "ALIAS_EXPANSION_LIMIT_EXCEEDED"
+	// CodeAliasDepthLimitExceeded describes files whose YAML aliases are
+	// nested too deeply.
+	CodeAliasDepthLimitExceeded = "ALIAS_DEPTH_LIMIT_EXCEEDED"
+	// CodeNodeLimitExceeded describes files that have too many nodes.
+	CodeNodeLimitExceeded = "NODE_LIMIT_EXCEEDED"
+	// CodeRefDepthLimitExceeded describes chains of references to other
+	// files that are too long.
+	CodeRefDepthLimitExceeded = "REF_DEPTH_LIMIT_EXCEEDED"
+	// CodeInconsistentSchema describes schemas whose keywords contradict
+	// each other, such as defaults that aren't enum values.
+	CodeInconsistentSchema = "INCONSISTENT_SCHEMA"
+	// CodeInvalidServer describes servers with malformed URLs or URLs
+	// that don't match their variables.
+	CodeInvalidServer = "INVALID_SERVER"
+)
+
+// MessageCodes returns all known message codes.
+func MessageCodes() []string {
+	return []string{
+		CodeUnknown,
+		CodeInvalidYAML,
+		CodeUnsupportedEncoding,
+		CodeUnknownVersion,
+		CodeFetchFailed,
+		CodeMissingRequiredProperty,
+		CodeInvalidProperty,
+		CodeUnexpectedValue,
+		CodeInvalidAlternative,
+		CodeMissingRefTarget,
+		CodeDuplicateOperationID,
+		CodeDeprecatedWithoutDescription,
+		CodeUnresolvedExample,
+		CodeDuplicateInlineComponent,
+		CodeInvalidExample,
+		CodeGRPCMismatch,
+		CodeTimeout,
+		CodeFetchLimitExceeded,
+		CodeAliasExpansionLimitExceeded,
+		CodeAliasDepthLimitExceeded,
+		CodeNodeLimitExceeded,
+		CodeRefDepthLimitExceeded,
+		CodeInconsistentSchema,
+		CodeInvalidServer,
+	}
+}
+
+// Message is a structured description of a problem found by the compiler.
+type Message struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	Context string `json:"context"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+
+	code string
+}
+
+// Code returns a stable identifier of the problem that a Message
+// describes, one of the values returned by MessageCodes. Messages for a
+// CodedError have its code, others have CodeUnknown unless their codes
+// are set with SetCode.
+func (m *Message) Code() string {
+	if m.code != "" {
+		return m.code
+	}
+	return CodeUnknown
+}
+
+// SetCode sets the code of a Message.
+func (m *Message) SetCode(code string) {
+	m.code = code
+}
+
+// NewMessageFromError creates a Message that describes an Error in the named file.
+func NewMessageFromError(err *Error, file string) *Message {
+	m := &Message{Kind: MessageKindError, Message: err.Message, File: file}
+	if err.Context != nil {
+		m.Context = err.Context.Description()
+		m.Line = LineNumber(err.Context)
+	}
+	return m
+}
+
+// MessagesForError returns Messages for an error and, if the error is an
+// ErrorGroup, for all of the errors that it contains.
+func MessagesForError(err error, file string) []*Message {
+	switch err := err.(type) {
+	case nil:
+		return nil
+	case *ErrorGroup:
+		messages := make([]*Message, 0)
+		for _, e := range err.Errors {
+			messages = append(messages, MessagesForError(e, file)...)
+		}
+		return messages
+	case *Error:
+		return []*Message{NewMessageFromError(err, file)}
+	case *CodedError:
+		m := NewMessageFromError(err.Err, file)
+		m.SetCode(err.Code)
+		return []*Message{m}
+	default:
+		return []*Message{{Kind: MessageKindError, Message: err.Error(), File: file}}
+	}
+}
+
+// String returns a plain text description of a Message, such as
+// "error at line 3 in $root.info of api.yaml: is missing required property: version".
+func (m *Message) String() string {
+	s := m.Kind
+	if m.Line > 0 {
+		s += fmt.Sprintf(" at line %d", m.Line)
+	}
+	if m.Context != "" {
+		s += " in " + m.Context
+	}
+	if m.File != "" {
+		s += " of " + m.File
+	}
+	return s + ": " + m.Message
+}
+
+// ToJSON returns a description of a Message as a JSON object.
+func (m *Message) ToJSON() string {
+	bytes, err := json.Marshal(struct {
+		Kind string `json:"kind"`
+		Code string `json:"code"`
+		*Message
+	}{m.Kind, m.Code(), m})
+	if err != nil {
+		return "{}"
+	}
+	return string(bytes)
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMessageToJSON(t *testing.T) {
+	root := NewContext("$root", &yaml.Node{Line: 1}, nil)
+	info := NewContext("info", &yaml.Node{Line: 3}, root)
+	err := NewErrorGroupOrNil([]error{
+		NewErrorWithCode(info, CodeMissingRequiredProperty, "is missing required property: version"),
+		NewErrorWithCode(nil, CodeUnknownVersion, "unable to identify OpenAPI version"),
+	})
+	messages := MessagesForError(err, "api.yaml")
+	if len(messages) != 2 {
+		t.Fatalf("unexpected number of messages: %d (expected 2)", len(messages))
+	}
+	for i, expected := range []string{
+		`{"kind":"error","code":"MISSING_REQUIRED_PROPERTY","message":"is missing required property: version","context":"$root.info","file":"api.yaml","line":3}`,
+		`{"kind":"error","code":"UNKNOWN_VERSION","message":"unable to identify OpenAPI version","context":"","file":"api.yaml","line":0}`,
+	} {
+		if got := messages[i].ToJSON(); got != expected {
+			t.Errorf("unexpected JSON: %s (expected %s)", got, expected)
+		}
+	}
+}
+
+func TestMessageString(t *testing.T) {
+	root := NewContextWithExtensions("$root", &yaml.Node{Line: 1}, nil, nil)
+	components := NewContext("components", nil, root)
+	schema := NewContext("Pet", &yaml.Node{Line: 247}, NewContext("schemas", nil, components))
+	if line := LineNumber(components); line != 1 {
+		t.Errorf("unexpected line for a context without a node: %d (expected 1)", line)
+	}
+	for _, test := range []struct {
+		err      error
+		file     string
+		expected string
+	}{
+		{NewError(schema, "has unexpected type"), "api.yaml", "error at line 247 in $root.components.schemas.Pet of api.yaml: has unexpected type"},
+		{NewError(NewContext("schemas", nil, NewContext("components", nil, nil)), "is empty"), "", "error in components.schemas: is empty"},
+		{errors.New("unable to identify OpenAPI version"), "api.yaml", "error of api.yaml: unable to identify OpenAPI version"},
+	} {
+		if got := MessagesForError(test.err, test.file)[0].String(); got != test.expected {
+			t.Errorf("unexpected string: %s (expected %s)", got, test.expected)
+		}
+	}
+}
+
+func TestMessageCode(t *testing.T) {
+	context := NewContext("$root", &yaml.Node{Line: 1}, nil)
+	for _, test := range []struct {
+		err  error
+		code string
+	}{
+		{NewErrorWithCode(context, CodeMissingRequiredProperty, "is missing required property: version"), CodeMissingRequiredProperty},
+		{NewErrorWithCode(nil, CodeTimeout, "timed out after 1s reading api.yaml"), CodeTimeout},
+		// Codes are not inferred from the text of messages.
+		{NewError(context, "is missing required property: version"), CodeUnknown},
+		{errors.New("could not resolve #/definitions/Pet"), CodeUnknown},
+	} {
+		if code := MessagesForError(test.err, "api.yaml")[0].Code(); code != test.code {
+			t.Errorf("unexpected code for %q: %s (expected %s)", test.err.Error(), code, test.code)
+		}
+	}
+	m := &Message{Kind: MessageKindWarning, Message: "deprecated operation has no description"}
+	m.SetCode(CodeDeprecatedWithoutDescription)
+	if m.Code() != CodeDeprecatedWithoutDescription {
+		t.Errorf("unexpected code: %s", m.Code())
+	}
+	// All codes are registered once.
+	seen := make(map[string]bool)
+	for _, code := range MessageCodes() {
+		if seen[code] {
+			t.Errorf("duplicate code %s", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	parser := NewCompilerWithOptions(ParseOptions{MaxNodes: 3})
+	for _, test := range []struct {
+		name string
+		err  func() error
+		code string
+	}{
+		{"missing file", func() error { _, err := ReadBytesForFile("missing.yaml"); return err }, CodeFetchFailed},
+		{"invalid yaml", func() error { _, err := ParseYAML("api.yaml", []byte("a: b: c")); return err }, CodeInvalidYAML},
+		{"invalid text", func() error { _, err := ReadInfoFromBytes("api.yaml", []byte("a: [")); return err }, CodeInvalidYAML},
+		{"encoding", func() error { _, err := DecodeText("api.yaml", []byte{0xff, 0xfe, 0, 0}); return err }, CodeUnsupportedEncoding},
+		{"missing ref", func() error {
+			_, err := ReadInfoForRef("../examples/v2.0/yaml/petstore.yaml", "#/definitions/Missing")
+			return err
+		}, CodeMissingRefTarget},
+		{"node limit", func() error { _, err := parser.ParseInfo("api.yaml", []byte("[1, 2, 3, 4]")); return err }, CodeNodeLimitExceeded},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.err()
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if code := MessagesForError(err, "")[0].Code(); code != test.code {
+				t.Errorf("unexpected code for %q: %s (expected %s)", err.Error(), code, test.code)
+			}
+		})
+	}
+}
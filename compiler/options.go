@@ -0,0 +1,415 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// A Loader reads the bytes of local and remote files for a Compiler.
+type Loader interface {
+	Load(filename string) ([]byte, error)
+}
+
+// LoaderFunc adapts a function to the Loader interface.
+type LoaderFunc func(filename string) ([]byte, error)
+
+// Load calls f(filename).
+func (f LoaderFunc) Load(filename string) ([]byte, error) {
+	return f(filename)
+}
+
+// DefaultLoader reads files with ReadBytesForFile, so remote files are
+// read with the current Fetcher. It is a LimitedLoader.
+var DefaultLoader Loader = defaultLoader{}
+
+// A Cache holds the files that were read by Compilers, so that each file
+// is read and parsed once. A Cache can be shared by several Compilers.
+type Cache struct {
+	mutex sync.Mutex
+	infos map[string]*yaml.Node
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{infos: make(map[string]*yaml.Node)}
+}
+
+// Len returns the number of files in the cache.
+func (c *Cache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.infos)
+}
+
+// Clear removes all files from the cache.
+func (c *Cache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.infos = make(map[string]*yaml.Node)
+}
+
+func (c *Cache) get(filename string) (*yaml.Node, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	info, ok := c.infos[filename]
+	return info, ok
+}
+
+func (c *Cache) put(filename string, info *yaml.Node) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.infos[filename] = info
+}
+
+// ParseOptions configure a Compiler. A limit that is zero has its default
+// value, and a negative limit removes the limit.
+type ParseOptions struct {
+	// StrictMode makes errors reading the files that a document refers to
+	// fatal. Otherwise they are ignored by Compile and reported when the
+	// references are resolved, unless they report exceeded limits.
+	StrictMode bool
+	// MaxRefDepth limits the length of chains of references to other files.
+	MaxRefDepth int
+	// MaxFetchedBytes limits the total size of the files that a Compiler
+	// reads. Files that are found in the Cache don't count.
+	MaxFetchedBytes int64
+	// MaxAliasExpansions limits the number of nodes that expanding the
+	// YAML aliases of a file adds to it.
+	MaxAliasExpansions int64
+	// MaxAliasDepth limits the nesting of YAML aliases in the nodes that
+	// other aliases refer to.
+	MaxAliasDepth int
+	// MaxNodes limits the number of nodes of a file, with its aliases expanded.
+	MaxNodes int64
+	// Loader reads files. If it is nil, DefaultLoader is used.
+	Loader Loader
+	// Cache holds the files that were read. If it is nil, the Compiler
+	// has a cache of its own.
+	Cache *Cache
+	// Timeout limits the time spent reading files in each call of Compile,
+	// ReadInfo and ReadInfoForRef. Zero means no limit. Files that are
+	// being read when the time runs out are abandoned, not cancelled.
+	Timeout time.Duration
+	// Logger receives the names of the files that are read. If it is nil,
+	// the Logger that was set with SetLogger is used.
+	Logger Logger
+}
+
+// A Compiler reads API descriptions and the files that they refer to.
+// Unlike the functions of this package, which are configured with global
+// state, each Compiler has its own options.
+type Compiler struct {
+	options ParseOptions
+
+	mutex        sync.Mutex
+	fetchedBytes int64
+	// refs are the fragments that were read for $refs.
+	refs map[string]*yaml.Node
+}
+
+// NewCompilerWithOptions returns a Compiler that is configured with opts.
+func NewCompilerWithOptions(opts ParseOptions) *Compiler {
+	if opts.MaxRefDepth == 0 {
+		opts.MaxRefDepth = DefaultMaxRefDepth
+	}
+	if opts.MaxFetchedBytes == 0 {
+		opts.MaxFetchedBytes = DefaultMaxFetchedBytes
+	}
+	if opts.MaxAliasExpansions == 0 {
+		opts.MaxAliasExpansions = DefaultMaxAliasExpansions
+	}
+	if opts.MaxAliasDepth == 0 {
+		opts.MaxAliasDepth = DefaultMaxAliasDepth
+	}
+	if opts.MaxNodes == 0 {
+		opts.MaxNodes = DefaultMaxNodes
+	}
+	if opts.Loader == nil {
+		opts.Loader = DefaultLoader
+	}
+	if opts.Cache == nil {
+		opts.Cache = NewCache()
+	}
+	return &Compiler{options: opts, refs: make(map[string]*yaml.Node)}
+}
+
+// Options returns the options of a Compiler, with defaults filled in.
+func (c *Compiler) Options() ParseOptions {
+	return c.options
+}
+
+// Compile reads a file and the files that it refers to. It returns the
+// document node of the file, with aliases expanded as in ReadInfoForRef.
+// The fragments of other files that are referred to are kept by the
+// Compiler; AddToInfoCache makes them available to ResolveReferences methods.
+func (c *Compiler) Compile(filename string) (*yaml.Node, error) {
+	r := c.start()
+	info, err := r.readInfo(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.prefetchReferences(filename, info, make(map[string]bool), 1); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// ReadBytes reads the bytes of a file with the Loader.
+func (c *Compiler) ReadBytes(filename string) ([]byte, error) {
+	return c.start().load(filename)
+}
+
+// Decompress returns the contents of gzip-compressed data like the
+// Decompress function, but within the MaxFetchedBytes limit of the Compiler.
+func (c *Compiler) Decompress(filename string, data []byte) ([]byte, error) {
+	return decompress(filename, data, c.options.MaxFetchedBytes)
+}
+
+// ParseInfo parses the bytes of a file with ReadInfoFromBytes and returns
+// an error if the file exceeds the alias or node limits. Compressed files
+// are decompressed within the MaxFetchedBytes limit. Aliases are not
+// expanded.
+func (c *Compiler) ParseInfo(filename string, bytes []byte) (*yaml.Node, error) {
+	text, err := c.Decompress(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	return c.ParseText(filename, text)
+}
+
+// ParseText parses text that was already decompressed like ParseInfo.
+func (c *Compiler) ParseText(filename string, text []byte) (*yaml.Node, error) {
+	info, err := readInfoFromText(filename, text)
+	if err != nil || info == nil {
+		return info, err
+	}
+	if err := c.checkNodes(filename, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// ReadInfo reads a file and returns its document node, with aliases
+// expanded.
+func (c *Compiler) ReadInfo(filename string) (*yaml.Node, error) {
+	return c.start().readInfo(filename)
+}
+
+// ReadInfoForRef reads a file and returns the fragment needed to resolve
+// a $ref. Unlike the ReadInfoForRef function, it keeps the result in the
+// Compiler rather than in the info cache.
+func (c *Compiler) ReadInfoForRef(basefile string, ref string) (*yaml.Node, error) {
+	return c.start().readInfoForRef(basefile, ref)
+}
+
+// AddToInfoCache stores the fragments that the Compiler read for $refs in
+// the info cache under their $refs, so that ResolveReferences methods find
+// them instead of reading the files again, outside the limits of the
+// Compiler. Nothing is stored while the info cache is disabled.
+func (c *Compiler) AddToInfoCache() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for ref, info := range c.refs {
+		cacheInfo(ref, info)
+	}
+}
+
+// PrefetchReferences reads the files that are needed to resolve the $refs
+// in a node like the PrefetchReferences function, but within the limits
+// of the Compiler. Errors reading files are returned in StrictMode.
+func (c *Compiler) PrefetchReferences(root string, node *yaml.Node) error {
+	return c.start().prefetchReferences(root, node, make(map[string]bool), 1)
+}
+
+// logger returns the Logger of a Compiler.
+func (c *Compiler) logger() Logger {
+	if c.options.Logger != nil {
+		return c.options.Logger
+	}
+	return CurrentLogger()
+}
+
+// A compilation is a call of a Compiler method.
+type compilation struct {
+	*Compiler
+	deadline time.Time
+}
+
+func (c *Compiler) start() *compilation {
+	r := &compilation{Compiler: c}
+	if c.options.Timeout > 0 {
+		r.deadline = time.Now().Add(c.options.Timeout)
+	}
+	return r
+}
+
+// load reads a file with the Loader within the limit of fetched bytes.
+func (r *compilation) load(filename string) ([]byte, error) {
+	r.logger().Debugf("reading %s", filename)
+	limit := int64(-1)
+	if r.options.MaxFetchedBytes > 0 {
+		r.mutex.Lock()
+		limit = r.options.MaxFetchedBytes - r.fetchedBytes
+		r.mutex.Unlock()
+	}
+	loader := r.options.Loader
+	if d, ok := loader.(defaultLoader); ok && d.logger == nil {
+		d.logger = r.logger()
+		loader = d
+	}
+	load := loader.Load
+	if loader, ok := loader.(LimitedLoader); ok && limit >= 0 {
+		load = func(filename string) ([]byte, error) {
+			return loader.LoadLimited(filename, limit)
+		}
+	}
+	bytes, err := r.loadBeforeDeadline(filename, load)
+	if err == ErrFileTooLarge || err == nil && limit >= 0 && int64(len(bytes)) > limit {
+		return nil, NewErrorWithCode(nil, CodeFetchLimitExceeded, fmt.Sprintf("reading %s exceeds the limit of %d fetched bytes", filename, r.options.MaxFetchedBytes))
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.mutex.Lock()
+	r.fetchedBytes += int64(len(bytes))
+	r.mutex.Unlock()
+	return bytes, nil
+}
+
+// loadBeforeDeadline reads a file with a function before the deadline.
+func (r *compilation) loadBeforeDeadline(filename string, load func(string) ([]byte, error)) ([]byte, error) {
+	if r.deadline.IsZero() {
+		return load(filename)
+	}
+	remaining := time.Until(r.deadline)
+	if remaining <= 0 {
+		return nil, r.timeoutError(filename)
+	}
+	type result struct {
+		bytes []byte
+		err   error
+	}
+	results := make(chan result, 1)
+	go func() {
+		bytes, err := load(filename)
+		results <- result{bytes: bytes, err: err}
+	}()
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case result := <-results:
+		return result.bytes, result.err
+	case <-timer.C:
+		return nil, r.timeoutError(filename)
+	}
+}
+
+func (r *compilation) timeoutError(filename string) error {
+	return NewErrorWithCode(nil, CodeTimeout, fmt.Sprintf("timed out after %s reading %s", r.options.Timeout, filename))
+}
+
+// readInfo reads and parses a file, or gets it from the cache.
+func (r *compilation) readInfo(filename string) (*yaml.Node, error) {
+	if info, ok := r.options.Cache.get(filename); ok {
+		if trace := currentTrace(); trace != nil {
+			trace.addCacheHit(filename)
+		}
+		r.logger().Debugf("using cached %s", filename)
+		return info, nil
+	}
+	bytes, err := r.load(filename)
+	if err != nil {
+		return nil, err
+	}
+	info, err := r.ParseInfo(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil || info.Kind != yaml.DocumentNode || len(info.Content) == 0 {
+		return nil, NewError(nil, fmt.Sprintf("%s is empty", filename))
+	}
+	ExpandAliases(info)
+	r.options.Cache.put(filename, info)
+	return info, nil
+}
+
+func (r *compilation) readInfoForRef(basefile string, ref string) (*yaml.Node, error) {
+	info, err := r.readInfo(filenameForRef(basefile, ref))
+	if err != nil {
+		return nil, err
+	}
+	info = info.Content[0]
+	if parts := strings.SplitN(ref, "#", 2); len(parts) > 1 {
+		for i, key := range strings.Split(parts[1], "/") {
+			if i == 0 {
+				continue
+			}
+			info = MapValueForKey(info, key)
+			if info == nil {
+				return nil, NewErrorWithCode(nil, CodeMissingRefTarget, fmt.Sprintf("could not resolve %s", ref))
+			}
+		}
+	}
+	r.mutex.Lock()
+	r.refs[ref] = info
+	r.mutex.Unlock()
+	return info, nil
+}
+
+// prefetchReferences reads the files that are needed to resolve the $refs
+// in a node, as PrefetchReferences does, with references in the files that
+// are read at the next depth.
+func (r *compilation) prefetchReferences(root string, node *yaml.Node, visited map[string]bool, depth int) error {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			ref := node.Content[i+1].Value
+			if node.Content[i].Value != "$ref" || node.Content[i+1].Kind != yaml.ScalarNode || visited[ref] {
+				continue
+			}
+			visited[ref] = true
+			if filename := filenameForRef(root, ref); filename == root && !isRemoteFile(root) {
+				continue
+			}
+			if r.options.MaxRefDepth > 0 && depth > r.options.MaxRefDepth {
+				return NewErrorWithCode(nil, CodeRefDepthLimitExceeded, fmt.Sprintf("%s exceeds the maximum reference depth of %d", ref, r.options.MaxRefDepth))
+			}
+			info, err := r.readInfoForRef(root, ref)
+			if err != nil {
+				if r.options.StrictMode || LimitExceeded(err) {
+					return err
+				}
+				continue
+			}
+			if err := r.prefetchReferences(root, info, visited, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if err := r.prefetchReferences(root, child, visited, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
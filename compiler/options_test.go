@@ -0,0 +1,144 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mapLoader returns a Loader that reads files from a map and counts the
+// times that each file is read.
+func mapLoader(files map[string]string, counts map[string]int) Loader {
+	var mutex sync.Mutex
+	return LoaderFunc(func(filename string) ([]byte, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		counts[filename]++
+		text, ok := files[filename]
+		if !ok {
+			return nil, errors.New("no such file " + filename)
+		}
+		return []byte(text), nil
+	})
+}
+
+var optionsFiles = map[string]string{
+	"api/openapi.yaml":   "openapi: 3.0.0\npaths:\n  /pets:\n    $ref: 'paths.yaml#/pets'\n",
+	"api/paths.yaml":     "pets:\n  get:\n    responses:\n      '200':\n        $ref: 'responses.yaml#/Pets'\n",
+	"api/responses.yaml": "Pets:\n  description: pets\n",
+}
+
+func TestNewCompilerWithOptionsDefaults(t *testing.T) {
+	options := NewCompilerWithOptions(ParseOptions{}).Options()
+	if options.MaxRefDepth != DefaultMaxRefDepth {
+		t.Errorf("MaxRefDepth is %d, want %d", options.MaxRefDepth, DefaultMaxRefDepth)
+	}
+	if options.Loader == nil || options.Cache == nil {
+		t.Errorf("Loader and Cache were not set: %+v", options)
+	}
+}
+
+func TestCompilerCompile(t *testing.T) {
+	ClearInfoCache()
+	defer ClearInfoCache()
+	counts := make(map[string]int)
+	cache := NewCache()
+	var c *Compiler
+	for i := 0; i < 2; i++ {
+		c = NewCompilerWithOptions(ParseOptions{Loader: mapLoader(optionsFiles, counts), Cache: cache})
+		info, err := c.Compile("api/openapi.yaml")
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if v := MapValueForKey(info.Content[0], "openapi"); v == nil || v.Value != "3.0.0" {
+			t.Errorf("unexpected document %+v", info.Content[0])
+		}
+	}
+	for filename := range optionsFiles {
+		if counts[filename] != 1 {
+			t.Errorf("%s was read %d times, want 1", filename, counts[filename])
+		}
+	}
+	if cache.Len() != 3 {
+		t.Errorf("cache has %d files, want 3", cache.Len())
+	}
+	// Fragments are added to the info cache only on request.
+	if _, ok := GetInfoCache()["responses.yaml#/Pets"]; ok {
+		t.Fatalf("responses.yaml#/Pets was added to the info cache by Compile")
+	}
+	c.AddToInfoCache()
+	info, ok := GetInfoCache()["responses.yaml#/Pets"]
+	if !ok {
+		t.Fatalf("responses.yaml#/Pets is not in the info cache")
+	}
+	if v := MapValueForKey(info, "description"); v == nil || v.Value != "pets" {
+		t.Errorf("unexpected fragment %+v", info)
+	}
+}
+
+func TestCompilerMaxRefDepth(t *testing.T) {
+	ClearInfoCache()
+	defer ClearInfoCache()
+	for _, test := range []struct {
+		depth int
+		err   string
+	}{
+		{1, "responses.yaml#/Pets exceeds the maximum reference depth of 1"},
+		{2, ""},
+		{-1, ""},
+	} {
+		c := NewCompilerWithOptions(ParseOptions{MaxRefDepth: test.depth, Loader: mapLoader(optionsFiles, make(map[string]int))})
+		_, err := c.Compile("api/openapi.yaml")
+		if test.err == "" && err != nil {
+			t.Errorf("depth %d: %+v", test.depth, err)
+		} else if test.err != "" && (err == nil || err.Error() != test.err) {
+			t.Errorf("depth %d: got error %v, want %q", test.depth, err, test.err)
+		}
+	}
+}
+
+func TestCompilerStrictMode(t *testing.T) {
+	ClearInfoCache()
+	defer ClearInfoCache()
+	files := map[string]string{
+		"api/openapi.yaml": optionsFiles["api/openapi.yaml"],
+	}
+	c := NewCompilerWithOptions(ParseOptions{Loader: mapLoader(files, make(map[string]int))})
+	if _, err := c.Compile("api/openapi.yaml"); err != nil {
+		t.Errorf("%+v", err)
+	}
+	c = NewCompilerWithOptions(ParseOptions{StrictMode: true, Loader: mapLoader(files, make(map[string]int))})
+	if _, err := c.Compile("api/openapi.yaml"); err == nil || err.Error() != "no such file api/paths.yaml" {
+		t.Errorf("got error %v, want a missing file", err)
+	}
+}
+
+func TestCompilerTimeout(t *testing.T) {
+	release := make(chan bool)
+	defer close(release)
+	loader := LoaderFunc(func(filename string) ([]byte, error) {
+		<-release
+		return nil, nil
+	})
+	c := NewCompilerWithOptions(ParseOptions{Loader: loader, Timeout: 10 * time.Millisecond})
+	_, err := c.ReadInfo("openapi.yaml")
+	if err == nil || !strings.HasPrefix(err.Error(), "timed out after 10ms reading openapi.yaml") {
+		t.Errorf("got error %v, want a timeout", err)
+	}
+}
@@ -15,23 +15,66 @@
 package compiler
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/google/gnostic-models/compiler"
+	yaml "gopkg.in/yaml.v3"
 )
 
 // EnableFileCache turns on file caching.
-var EnableFileCache = compiler.EnableFileCache
+func EnableFileCache() {
+	compiler.EnableFileCache()
+	fileCacheMutex.Lock()
+	defer fileCacheMutex.Unlock()
+	fileCacheEnable = true
+}
+
+// The info cache of gnostic-models is guarded by a mutex that isn't
+// exported. The functions of this package that use the info cache directly
+// hold infoCacheMutex instead and honor the flag that DisableInfoCache and
+// EnableInfoCache set.
+var (
+	infoCacheMutex  sync.Mutex
+	infoCacheEnable = true
+)
 
 // EnableInfoCache turns on parsed info caching.
-var EnableInfoCache = compiler.EnableInfoCache
+func EnableInfoCache() {
+	compiler.EnableInfoCache()
+	infoCacheMutex.Lock()
+	defer infoCacheMutex.Unlock()
+	infoCacheEnable = true
+}
 
 // DisableFileCache turns off file caching.
-var DisableFileCache = compiler.DisableFileCache
+func DisableFileCache() {
+	compiler.DisableFileCache()
+	fileCacheMutex.Lock()
+	defer fileCacheMutex.Unlock()
+	fileCacheEnable = false
+}
 
 // DisableInfoCache turns off parsed info caching.
-var DisableInfoCache = compiler.DisableInfoCache
+func DisableInfoCache() {
+	compiler.DisableInfoCache()
+	infoCacheMutex.Lock()
+	defer infoCacheMutex.Unlock()
+	infoCacheEnable = false
+}
 
 // RemoveFromFileCache removes an entry from the file cache.
-var RemoveFromFileCache = compiler.RemoveFromFileCache
+func RemoveFromFileCache(fileurl string) {
+	compiler.RemoveFromFileCache(fileurl)
+	fileCacheMutex.Lock()
+	defer fileCacheMutex.Unlock()
+	delete(fileCache, fileurl)
+}
 
 // RemoveFromInfoCache removes an entry from the info cache.
 var RemoveFromInfoCache = compiler.RemoveFromInfoCache
@@ -40,22 +83,321 @@ var RemoveFromInfoCache = compiler.RemoveFromInfoCache
 var GetInfoCache = compiler.GetInfoCache
 
 // ClearFileCache clears the file cache.
-var ClearFileCache = compiler.ClearFileCache
+func ClearFileCache() {
+	compiler.ClearFileCache()
+	fileCacheMutex.Lock()
+	defer fileCacheMutex.Unlock()
+	fileCache = make(map[string][]byte)
+}
 
 // ClearInfoCache clears the info cache.
 var ClearInfoCache = compiler.ClearInfoCache
 
 // ClearCaches clears all caches.
-var ClearCaches = compiler.ClearCaches
+func ClearCaches() {
+	ClearFileCache()
+	ClearInfoCache()
+}
+
+// FetchFile gets a specified file from a remote location with the current Fetcher.
+func FetchFile(fileurl string) ([]byte, error) {
+	trace := currentTrace()
+	if trace == nil {
+		return fetchRemoteFile(fileurl, CurrentLogger())
+	}
+	start := time.Now()
+	bytes, err := fetchRemoteFile(fileurl, CurrentLogger())
+	trace.addFetch(fileurl, bytes, time.Since(start), err)
+	return bytes, err
+}
 
-// FetchFile gets a specified file from the local filesystem or a remote location.
-var FetchFile = compiler.FetchFile
+// readBytesForFile reads a remote file with the current Fetcher or a local file.
+func readBytesForFile(filename string, logger Logger) ([]byte, error) {
+	var bytes []byte
+	var err error
+	if isRemoteFile(filename) {
+		bytes, err = fetchRemoteFile(filename, logger)
+	} else {
+		bytes, err = compiler.ReadBytesForFile(filename)
+	}
+	if err != nil && err != ErrFileTooLarge {
+		if _, ok := err.(*CodedError); !ok {
+			err = NewErrorWithCode(nil, CodeFetchFailed, err.Error())
+		}
+	}
+	return bytes, err
+}
 
 // ReadBytesForFile reads the bytes of a file.
-var ReadBytesForFile = compiler.ReadBytesForFile
+func ReadBytesForFile(filename string) ([]byte, error) {
+	return traceBytesForFile(filename, CurrentLogger())
+}
+
+// traceBytesForFile reads the bytes of a file like ReadBytesForFile and
+// logs to logger.
+func traceBytesForFile(filename string, logger Logger) ([]byte, error) {
+	trace := currentTrace()
+	if trace == nil {
+		return readBytesForFile(filename, logger)
+	}
+	start := time.Now()
+	bytes, err := readBytesForFile(filename, logger)
+	trace.addFetch(filename, bytes, time.Since(start), err)
+	return bytes, err
+}
+
+// WriteFileAtomically writes data to a temporary file in the directory of
+// filename and renames it to filename, so that an interrupted write never
+// leaves a partial file behind.
+func WriteFileAtomically(filename string, data []byte) error {
+	file, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filename)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+// ReadInfoFromBytes unmarshals a file as a *yaml.Node. The bytes are
+// converted to UTF-8 with DecodeText.
+func ReadInfoFromBytes(filename string, bytes []byte) (*yaml.Node, error) {
+	bytes, err := Decompress(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	return readInfoFromText(filename, bytes)
+}
+
+// readInfoFromText unmarshals text that isn't compressed like
+// ReadInfoFromBytes.
+func readInfoFromText(filename string, text []byte) (*yaml.Node, error) {
+	text, err := decodeText(filename, text)
+	if err != nil {
+		return nil, err
+	}
+	info, err := compiler.ReadInfoFromBytes(filename, text)
+	if err != nil {
+		return nil, NewErrorWithCode(nil, CodeInvalidYAML, err.Error())
+	}
+	return info, nil
+}
 
-// ReadInfoFromBytes unmarshals a file as a *yaml.Node.
-var ReadInfoFromBytes = compiler.ReadInfoFromBytes
+// ParseYAML unmarshals YAML or JSON text as a *yaml.Node in the same way that
+// gnostic reads API descriptions, but without using the info cache. The result
+// is a document node. Errors are prefixed with the filename, if one is given,
+// e.g. "openapi.yaml: yaml: line 3: mapping values are not allowed in this context".
+func ParseYAML(filename string, bytes []byte) (*yaml.Node, error) {
+	bytes, err := DecodeText(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	var info yaml.Node
+	if err := yaml.Unmarshal(bytes, &info); err != nil {
+		message := err.Error()
+		if filename != "" {
+			message = filename + ": " + message
+		}
+		return nil, NewErrorWithCode(nil, CodeInvalidYAML, message)
+	}
+	return &info, nil
+}
 
 // ReadInfoForRef reads a file and return the fragment needed to resolve a $ref.
-var ReadInfoForRef = compiler.ReadInfoForRef
+// Remote files are read with the current Fetcher. Like compiler.ReadInfoForRef,
+// it stores the result in the info cache under the $ref, so that later calls
+// of compiler.ReadInfoForRef, such as those of ResolveReferences methods,
+// don't read the file again.
+func ReadInfoForRef(basefile string, ref string) (*yaml.Node, error) {
+	filename := filenameForRef(basefile, ref)
+	if info, ok := cachedInfo(ref); ok {
+		if trace := currentTrace(); trace != nil {
+			trace.addCacheHit(filename)
+		}
+		return info, nil
+	}
+	// Files that were already parsed, like the source of a compilation,
+	// are not read again.
+	info, ok := cachedInfo(filename)
+	if ok {
+		if trace := currentTrace(); trace != nil {
+			trace.addCacheHit(filename)
+		}
+	} else {
+		bytes, err := ReadBytesForFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		info, err = ReadInfoFromBytes(filename, bytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if info != nil && info.Kind == yaml.DocumentNode {
+		info = info.Content[0]
+	}
+	if info == nil {
+		return nil, NewErrorWithCode(nil, CodeMissingRefTarget, fmt.Sprintf("could not resolve %s", ref))
+	}
+	ExpandAliases(info)
+	if parts := strings.SplitN(ref, "#", 2); len(parts) > 1 {
+		for i, key := range strings.Split(parts[1], "/") {
+			if i == 0 {
+				continue
+			}
+			info = MapValueForKey(info, key)
+			if info == nil {
+				cacheInfo(ref, nil)
+				return nil, NewErrorWithCode(nil, CodeMissingRefTarget, fmt.Sprintf("could not resolve %s", ref))
+			}
+		}
+	}
+	cacheInfo(ref, info)
+	return info, nil
+}
+
+// cachedInfo returns the node that is stored in the info cache under a
+// key, if the info cache is enabled.
+func cachedInfo(key string) (*yaml.Node, bool) {
+	infoCacheMutex.Lock()
+	defer infoCacheMutex.Unlock()
+	if !infoCacheEnable {
+		return nil, false
+	}
+	info, ok := GetInfoCache()[key]
+	return info, ok
+}
+
+// cacheInfo stores a node in the info cache under a key, if the info
+// cache is enabled.
+func cacheInfo(key string, info *yaml.Node) {
+	infoCacheMutex.Lock()
+	defer infoCacheMutex.Unlock()
+	if infoCacheEnable {
+		GetInfoCache()[key] = info
+	}
+}
+
+// ExpandAliases replaces the YAML aliases in a node with copies of the
+// nodes that they refer to, removes anchors, and applies merge keys ("<<").
+// Aliases of nodes that contain them are replaced with empty mappings.
+func ExpandAliases(node *yaml.Node) {
+	expandAliases(node, make(map[*yaml.Node]bool))
+}
+
+func expandAliases(node *yaml.Node, expanding map[*yaml.Node]bool) {
+	if node == nil {
+		return
+	}
+	node.Anchor = ""
+	for i, child := range node.Content {
+		if child.Kind != yaml.AliasNode {
+			expandAliases(child, expanding)
+			continue
+		}
+		if child.Alias == nil || expanding[child.Alias] {
+			node.Content[i] = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: child.Line, Column: child.Column}
+			continue
+		}
+		expanding[child.Alias] = true
+		value := copyNode(child.Alias)
+		expandAliases(value, expanding)
+		delete(expanding, child.Alias)
+		node.Content[i] = value
+	}
+	if node.Kind == yaml.MappingNode {
+		mergeKeys(node)
+	}
+}
+
+// mergeKeys adds the pairs of the mappings that are the values of "<<"
+// keys to a mapping, unless the mapping already has their keys.
+func mergeKeys(node *yaml.Node) {
+	var merged []*yaml.Node
+	content := make([]*yaml.Node, 0, len(node.Content))
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Kind != yaml.ScalarNode || key.Value != "<<" || key.Style != 0 {
+			content = append(content, key, value)
+			continue
+		}
+		if value.Kind == yaml.SequenceNode {
+			merged = append(merged, value.Content...)
+		} else {
+			merged = append(merged, value)
+		}
+	}
+	if merged == nil {
+		return
+	}
+	for _, m := range merged {
+		if m.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(m.Content); j += 2 {
+			if MapValueForKey(&yaml.Node{Kind: yaml.MappingNode, Content: content}, m.Content[j].Value) == nil {
+				content = append(content, m.Content[j], m.Content[j+1])
+			}
+		}
+	}
+	node.Content = content
+}
+
+// copyNode returns a deep copy of a node.
+func copyNode(node *yaml.Node) *yaml.Node {
+	c := *node
+	if node.Content != nil {
+		c.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			c.Content[i] = copyNode(child)
+		}
+	}
+	return &c
+}
+
+// PrefetchReferences reads the files that are needed to resolve the $refs
+// in a node, following $refs in the files that are read. References to a
+// local root file are skipped.
+// References are resolved as they are by ResolveReferences methods, against
+// the file named by root. Afterwards, ResolveReferences methods find the
+// targets in the info cache, so that remote files are read with the current
+// Fetcher and all files are decoded with DecodeText. Errors are ignored
+// here and reported when the references are resolved.
+func PrefetchReferences(root string, node *yaml.Node) {
+	prefetchReferences(root, node, make(map[string]bool))
+}
+
+func prefetchReferences(root string, node *yaml.Node, visited map[string]bool) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			ref := node.Content[i+1].Value
+			if node.Content[i].Value != "$ref" || node.Content[i+1].Kind != yaml.ScalarNode || visited[ref] {
+				continue
+			}
+			visited[ref] = true
+			if filename := filenameForRef(root, ref); filename == root && !isRemoteFile(root) {
+				continue
+			}
+			if info, err := ReadInfoForRef(root, ref); err == nil {
+				prefetchReferences(root, info, visited)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		prefetchReferences(root, child, visited)
+	}
+}
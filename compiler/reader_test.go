@@ -0,0 +1,159 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseYAML(t *testing.T) {
+	info, err := ParseYAML("api.yaml", []byte("openapi: 3.0.0\ninfo:\n  title: Test\n"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if info.Kind != yaml.DocumentNode || len(info.Content) != 1 {
+		t.Fatalf("unexpected node: %+v", info)
+	}
+	if version, ok := StringForScalarNode(MapValueForKey(info.Content[0], "openapi")); !ok || version != "3.0.0" {
+		t.Errorf("unexpected version: %s", version)
+	}
+
+	// JSON is also accepted.
+	if _, err = ParseYAML("api.json", []byte(`{"swagger": "2.0"}`)); err != nil {
+		t.Errorf("%+v", err)
+	}
+
+	// Errors include the filename.
+	_, err = ParseYAML("api.yaml", []byte("info:\n  title: Test\n    version: 1.0\n"))
+	expected := "api.yaml: yaml: line 3: mapping values are not allowed in this context"
+	if err == nil || err.Error() != expected {
+		t.Errorf("unexpected error: %v (expected %s)", err, expected)
+	}
+}
+
+func TestExpandAliases(t *testing.T) {
+	info, err := ParseYAML("", []byte(`
+base: &base
+  type: object
+  title: Base
+derived:
+  <<: *base
+  title: Derived
+list: [*base, *base]
+loop: &loop
+  next: *loop
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	ExpandAliases(info)
+	bytes, err := yaml.Marshal(info)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := `base:
+    type: object
+    title: Base
+derived:
+    title: Derived
+    type: object
+list: [{type: object, title: Base}, {type: object, title: Base}]
+loop:
+    next:
+        next: {}
+`
+	if string(bytes) != expected {
+		t.Errorf("unexpected expansion:\n%s\nexpected:\n%s", string(bytes), expected)
+	}
+}
+
+func TestReadInfoForRefCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnostic-reader")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "schemas.yaml")
+	if err := ioutil.WriteFile(filename, []byte(schemaSource), 0644); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	ClearCaches()
+	defer ClearCaches()
+
+	// Concurrent calls share the info cache.
+	ref := filename + "#/definitions/Pet"
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := ReadInfoForRef("openapi.yaml", ref); err != nil {
+				t.Errorf("%+v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, ok := cachedInfo(ref); !ok {
+		t.Errorf("%s wasn't cached", ref)
+	}
+
+	// A disabled cache is neither read nor written.
+	DisableInfoCache()
+	defer EnableInfoCache()
+	ref = filename + "#/definitions"
+	if _, err := ReadInfoForRef("openapi.yaml", ref); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, ok := GetInfoCache()[ref]; ok {
+		t.Errorf("%s was cached while the info cache was disabled", ref)
+	}
+}
+
+func TestWriteFileAtomically(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gnostic-write")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "openapi.yaml")
+	for _, data := range []string{"openapi: 3.0.0\n", "openapi: 3.1.0\n"} {
+		if err := WriteFileAtomically(filename, []byte(data)); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		bytes, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if string(bytes) != data {
+			t.Errorf("unexpected contents: %q", bytes)
+		}
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("temporary files were left behind: %d files", len(files))
+	}
+	if err := WriteFileAtomically(filepath.Join(dir, "missing", "openapi.yaml"), nil); err == nil {
+		t.Errorf("expected an error for a missing directory")
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/gnostic-models/compiler"
+)
+
+// A CompilationTrace records the files that a compilation reads and
+// the time spent in each phase of the compilation.
+// Durations are serialized in nanoseconds.
+type CompilationTrace struct {
+	Fetches []*TraceFetch `json:"fetches"`
+	Phases  []*TracePhase `json:"phases"`
+
+	mutex sync.Mutex
+	// httpCacheHits are URLs that were read from an HTTP cache
+	// and have not yet been recorded by addFetch.
+	httpCacheHits map[string]bool
+}
+
+// TraceFetch describes a file or URL that was read during a compilation.
+type TraceFetch struct {
+	URL       string        `json:"url"`
+	Bytes     int           `json:"bytes"`
+	Duration  time.Duration `json:"duration"`
+	CacheHits int           `json:"cacheHits"`
+	// HTTPCached is true if the file was read from the cache directory of
+	// an HTTPFetcher after the server reported that it was not modified.
+	HTTPCached bool   `json:"httpCached,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// TracePhase describes the total time spent in a phase of a compilation.
+type TracePhase struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+var activeTrace *CompilationTrace
+var activeTraceMutex sync.Mutex
+
+// StartTrace starts collecting a new trace of file reads and extension
+// handler calls and returns it.
+func StartTrace() *CompilationTrace {
+	activeTraceMutex.Lock()
+	defer activeTraceMutex.Unlock()
+	activeTrace = &CompilationTrace{Fetches: make([]*TraceFetch, 0), Phases: make([]*TracePhase, 0)}
+	return activeTrace
+}
+
+// StopTrace stops collecting the active trace.
+func StopTrace() {
+	activeTraceMutex.Lock()
+	defer activeTraceMutex.Unlock()
+	activeTrace = nil
+}
+
+func currentTrace() *CompilationTrace {
+	activeTraceMutex.Lock()
+	defer activeTraceMutex.Unlock()
+	return activeTrace
+}
+
+// AddPhase adds time to the named phase.
+func (t *CompilationTrace) AddPhase(name string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, phase := range t.Phases {
+		if phase.Name == name {
+			phase.Duration += d
+			return
+		}
+	}
+	t.Phases = append(t.Phases, &TracePhase{Name: name, Duration: d})
+}
+
+// StartPhase starts timing the named phase and returns a function that ends it.
+func (t *CompilationTrace) StartPhase(name string) func() {
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		t.AddPhase(name, time.Since(start))
+	}
+}
+
+// Files returns the names of all files and URLs that were read.
+func (t *CompilationTrace) Files() []string {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	files := make([]string, 0, len(t.Fetches))
+	for _, fetch := range t.Fetches {
+		files = append(files, fetch.URL)
+	}
+	return files
+}
+
+// ToJSON returns a JSON representation of a trace.
+func (t *CompilationTrace) ToJSON() ([]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return json.MarshalIndent(t, "", "  ")
+}
+
+func (t *CompilationTrace) fetchForURL(url string) *TraceFetch {
+	for _, fetch := range t.Fetches {
+		if fetch.URL == url {
+			return fetch
+		}
+	}
+	return nil
+}
+
+// addFetch records a read of a file, or a cache hit if it was read before.
+func (t *CompilationTrace) addFetch(url string, bytes []byte, d time.Duration, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if fetch := t.fetchForURL(url); fetch != nil && fetch.Error == "" {
+		fetch.CacheHits++
+		return
+	}
+	fetch := &TraceFetch{URL: url, Bytes: len(bytes), Duration: d, HTTPCached: t.httpCacheHits[url]}
+	delete(t.httpCacheHits, url)
+	if err != nil {
+		fetch.Error = err.Error()
+	}
+	t.Fetches = append(t.Fetches, fetch)
+}
+
+// addHTTPCacheHit records that a file was read from an HTTP cache.
+// The fetch is marked when it is added.
+func (t *CompilationTrace) addHTTPCacheHit(url string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.httpCacheHits == nil {
+		t.httpCacheHits = make(map[string]bool)
+	}
+	t.httpCacheHits[url] = true
+}
+
+// addCacheHit records a cache hit for a file if it was read before and
+// returns false if it was not.
+func (t *CompilationTrace) addCacheHit(url string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if fetch := t.fetchForURL(url); fetch != nil {
+		fetch.CacheHits++
+		return true
+	}
+	return false
+}
+
+// AddCachedFiles records the files in the info cache that are not already
+// in the trace. This includes files that were read while resolving references
+// with ResolveReferences methods, which don't report durations or cache hits.
+func (t *CompilationTrace) AddCachedFiles() {
+	if t == nil {
+		return
+	}
+	filenames := make([]string, 0)
+	infoCacheMutex.Lock()
+	for key := range GetInfoCache() {
+		if key != "" && !strings.Contains(key, "#") {
+			filenames = append(filenames, key)
+		}
+	}
+	infoCacheMutex.Unlock()
+	sort.Strings(filenames)
+	for _, filename := range filenames {
+		t.mutex.Lock()
+		fetch := t.fetchForURL(filename)
+		t.mutex.Unlock()
+		if fetch != nil {
+			continue
+		}
+		// Keys of references without fragments are skipped because they are
+		// not resolved against their base files.
+		size := 0
+		if u, err := url.Parse(filename); err == nil && u.Scheme != "" {
+			// Remote files are in one of the file caches.
+			bytes, ok := cachedFile(filename)
+			if !ok {
+				var err error
+				if bytes, err = compiler.FetchFile(filename); err != nil {
+					continue
+				}
+			}
+			size = len(bytes)
+		} else if info, err := os.Stat(filename); err == nil && !info.IsDir() {
+			size = int(info.Size())
+		} else {
+			continue
+		}
+		t.mutex.Lock()
+		t.Fetches = append(t.Fetches, &TraceFetch{URL: filename, Bytes: size})
+		t.mutex.Unlock()
+	}
+}
+
+// filenameForRef returns the name of the file that contains a $ref target.
+func filenameForRef(basefile string, ref string) string {
+	parts := strings.Split(ref, "#")
+	if parts[0] == "" {
+		return basefile
+	}
+	if _, err := url.ParseRequestURI(parts[0]); err == nil {
+		return parts[0]
+	}
+	basedir, _ := filepath.Split(basefile)
+	return basedir + parts[0]
+}
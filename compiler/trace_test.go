@@ -0,0 +1,89 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompilationTrace(t *testing.T) {
+	ClearCaches()
+	trace := StartTrace()
+	defer StopTrace()
+
+	base := "../examples/v2.0/yaml/petstore-separate/spec/swagger.yaml"
+	bytes, err := ReadBytesForFile(base)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, ref := range []string{"Pet.yaml", "Pet.yaml#/properties", "#/info"} {
+		if _, err := ReadInfoForRef(base, ref); err != nil {
+			t.Fatalf("%s: %+v", ref, err)
+		}
+	}
+	if _, err := ReadBytesForFile("missing.yaml"); err == nil {
+		t.Fatalf("expected error for missing file")
+	}
+	trace.AddPhase("parse", time.Second)
+	trace.AddPhase("parse", time.Second)
+
+	expected := []string{base, "../examples/v2.0/yaml/petstore-separate/spec/Pet.yaml", "missing.yaml"}
+	if files := trace.Files(); !reflect.DeepEqual(files, expected) {
+		t.Fatalf("unexpected files: %+v (expected %+v)", files, expected)
+	}
+	if trace.Fetches[0].Bytes != len(bytes) || trace.Fetches[0].CacheHits != 1 {
+		t.Errorf("unexpected fetch: %+v", trace.Fetches[0])
+	}
+	if trace.Fetches[1].Bytes == 0 || trace.Fetches[1].CacheHits != 1 {
+		t.Errorf("unexpected fetch: %+v", trace.Fetches[1])
+	}
+	if trace.Fetches[2].Error == "" {
+		t.Errorf("expected error for fetch: %+v", trace.Fetches[2])
+	}
+	if len(trace.Phases) != 1 || trace.Phases[0].Duration != 2*time.Second {
+		t.Errorf("unexpected phases: %+v", trace.Phases)
+	}
+
+	// Reads are not recorded when no trace is active.
+	StopTrace()
+	ReadBytesForFile(base)
+	if len(trace.Fetches) != 3 {
+		t.Errorf("unexpected fetches after StopTrace: %d", len(trace.Fetches))
+	}
+}
+
+func TestReadInfoForRefUsesParsedSource(t *testing.T) {
+	ClearCaches()
+	trace := StartTrace()
+	defer StopTrace()
+
+	// The source is parsed from bytes and isn't read again for its own $refs.
+	source := "unread/openapi.yaml"
+	if _, err := ReadInfoFromBytes(source, []byte("info:\n  title: Unread\n")); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	info, err := ReadInfoForRef(source, "#/info")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if title := MapValueForKey(info, "title"); title == nil || title.Value != "Unread" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+	if len(trace.Fetches) != 0 {
+		t.Errorf("unexpected fetches: %+v", trace.Files())
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// EscapeJSONPointer escapes a reference token as described in RFC 6901,
+// so that "/pets" becomes "~1pets".
+func EscapeJSONPointer(token string) string {
+	return jsonPointerEscaper.Replace(token)
+}
+
+// VisitAll calls visitor for each value in a YAML document, parent values
+// before their children. The visitor receives the JSON Pointer of the
+// value, such as "/paths/~1pets/get", the key of the value in its mapping,
+// or its index in its sequence, and the value. The root is not visited.
+// Alias nodes are visited but not followed; use ExpandAliases first to
+// visit the values that they refer to.
+func VisitAll(node *yaml.Node, visitor func(path string, key string, value *yaml.Node)) {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	visitAll(node, "", visitor)
+}
+
+func visitAll(node *yaml.Node, path string, visitor func(path string, key string, value *yaml.Node)) {
+	if node == nil {
+		return
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			p := path + "/" + EscapeJSONPointer(key)
+			visitor(p, key, value)
+			visitAll(value, p, visitor)
+		}
+	case yaml.SequenceNode:
+		for i, value := range node.Content {
+			key := strconv.Itoa(i)
+			p := path + "/" + key
+			visitor(p, key, value)
+			visitAll(value, p, visitor)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compiler
+
+import (
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestVisitAll(t *testing.T) {
+	var node yaml.Node
+	source := `
+paths:
+  /pets:
+    get:
+      tags: [pets, animals]
+x-a~b: &anchor
+  c: 1
+x-alias: *anchor
+`
+	if err := yaml.Unmarshal([]byte(source), &node); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var visited []string
+	VisitAll(&node, func(path, key string, value *yaml.Node) {
+		visited = append(visited, path+" "+key+" "+value.Value)
+	})
+	expected := []string{
+		"/paths paths ",
+		"/paths/~1pets /pets ",
+		"/paths/~1pets/get get ",
+		"/paths/~1pets/get/tags tags ",
+		"/paths/~1pets/get/tags/0 0 pets",
+		"/paths/~1pets/get/tags/1 1 animals",
+		"/x-a~0b x-a~b ",
+		"/x-a~0b/c c 1",
+		"/x-alias x-alias anchor",
+	}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("unexpected visits:\n%q\nexpected:\n%q", visited, expected)
+	}
+
+	// Nil nodes and scalars have nothing to visit.
+	VisitAll(nil, func(path, key string, value *yaml.Node) {
+		t.Errorf("unexpected visit of %s", path)
+	})
+	VisitAll(&yaml.Node{Kind: yaml.ScalarNode, Value: "x"}, func(path, key string, value *yaml.Node) {
+		t.Errorf("unexpected visit of %s", path)
+	})
+}
+
+func TestEscapeJSONPointer(t *testing.T) {
+	for token, expected := range map[string]string{
+		"pets":     "pets",
+		"/pets":    "~1pets",
+		"a~b":      "a~0b",
+		"~1":       "~01",
+		"/a~/b~1/": "~1a~0~1b~01~1",
+	} {
+		if escaped := EscapeJSONPointer(token); escaped != expected {
+			t.Errorf("EscapeJSONPointer(%q) = %q, expected %q", token, escaped, expected)
+		}
+	}
+}
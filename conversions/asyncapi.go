@@ -0,0 +1,403 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+// openAPISchemaFormat is the AsyncAPI schema format of message payloads
+// that are OpenAPI v3.0 schemas.
+const openAPISchemaFormat = "application/vnd.oai.openapi;version=3.0.0"
+
+// AsyncAPIFromOpenAPIv3 converts an OpenAPI v3 document to an AsyncAPI 2.0
+// document, which it returns as YAML. Paths become channels, and the path
+// parameters of their operations become channel parameters. The request of
+// each operation becomes a message of the publish operation of its
+// channel, with the request body as payload and the header parameters as
+// headers, and each response with content becomes a message of the
+// subscribe operation. Payloads keep their OpenAPI schemas, which messages
+// declare with their schemaFormat, and schema components are copied so
+// that references to them remain valid. Servers become AsyncAPI servers
+// named server1, server2, and so on, with the protocols of their URLs.
+// It also returns warnings for parts of the document that aren't
+// converted, such as query parameters, security schemes and callbacks.
+func AsyncAPIFromOpenAPIv3(d *openapi3.Document) (*yaml.Node, []string, error) {
+	if d == nil {
+		return nil, nil, errors.New("no document to convert")
+	}
+	c := &asyncAPIConverter{document: d}
+	return c.asyncAPI(), c.warnings, nil
+}
+
+type asyncAPIConverter struct {
+	document *openapi3.Document
+	warnings []string
+}
+
+func (c *asyncAPIConverter) warn(pointer, format string, args ...interface{}) {
+	c.warnings = append(c.warnings, pointer+": "+fmt.Sprintf(format, args...))
+}
+
+// addPair appends a key and a value to a map.
+func addPair(node *yaml.Node, key string, value *yaml.Node) {
+	node.Content = append(node.Content, compiler.NewScalarNodeForString(key), value)
+}
+
+// addString appends a key and a string to a map if the string isn't empty.
+func addString(node *yaml.Node, key, value string) {
+	if value != "" {
+		addPair(node, key, compiler.NewScalarNodeForString(value))
+	}
+}
+
+func (c *asyncAPIConverter) asyncAPI() *yaml.Node {
+	d := c.document
+	root := compiler.NewMappingNode()
+	addString(root, "asyncapi", "2.0.0")
+	if d.Info != nil {
+		addPair(root, "info", d.Info.ToRawInfo())
+	}
+	if servers := c.servers(); len(servers.Content) > 0 {
+		addPair(root, "servers", servers)
+	}
+	addPair(root, "channels", c.channels())
+	if schemas := d.GetComponents().GetSchemas(); schemas != nil && len(schemas.AdditionalProperties) > 0 {
+		components := compiler.NewMappingNode()
+		addPair(components, "schemas", schemas.ToRawInfo())
+		addPair(root, "components", components)
+	}
+	if len(d.GetComponents().GetSecuritySchemes().GetAdditionalProperties()) > 0 {
+		c.warn("#/components/securitySchemes", "security schemes aren't converted")
+	}
+	return root
+}
+
+func (c *asyncAPIConverter) servers() *yaml.Node {
+	servers := compiler.NewMappingNode()
+	for i, server := range c.document.Servers {
+		s := compiler.NewMappingNode()
+		addString(s, "url", server.Url)
+		addString(s, "protocol", serverProtocol(server.Url))
+		addString(s, "description", server.Description)
+		if server.Variables != nil && len(server.Variables.AdditionalProperties) > 0 {
+			addPair(s, "variables", server.Variables.ToRawInfo())
+		}
+		addPair(servers, "server"+strconv.Itoa(i+1), s)
+	}
+	return servers
+}
+
+// serverProtocol returns the scheme of a server URL, or "http" for
+// relative URLs and URLs with variables in their schemes.
+func serverProtocol(url string) string {
+	if i := strings.Index(url, "://"); i > 0 && !strings.Contains(url[:i], "{") {
+		return strings.ToLower(url[:i])
+	}
+	return "http"
+}
+
+// An asyncAPIChannel collects the messages of the operations of a path.
+type asyncAPIChannel struct {
+	path           string
+	item           *openapi3.PathItem
+	operations     []string
+	parameters     *yaml.Node
+	parameterNames map[string]bool
+	requests       []*yaml.Node
+	responses      []*yaml.Node
+}
+
+func (c *asyncAPIConverter) channels() *yaml.Node {
+	var channels []*asyncAPIChannel
+	byPath := make(map[string]*asyncAPIChannel)
+	openapi3.ForEachOperation(c.document, func(path, method string, item *openapi3.PathItem, operation *openapi3.Operation) {
+		channel := byPath[path]
+		if channel == nil {
+			channel = &asyncAPIChannel{
+				path:           path,
+				item:           item,
+				parameters:     compiler.NewMappingNode(),
+				parameterNames: make(map[string]bool),
+			}
+			byPath[path] = channel
+			channels = append(channels, channel)
+		}
+		c.operation(channel, method, operation)
+	})
+	node := compiler.NewMappingNode()
+	for _, channel := range channels {
+		n := compiler.NewMappingNode()
+		description := channel.item.Description
+		if description == "" {
+			description = channel.item.Summary
+		}
+		addString(n, "description", description)
+		if len(channel.parameters.Content) > 0 {
+			addPair(n, "parameters", channel.parameters)
+		}
+		// Channels with a single HTTP operation name their operations after it.
+		publishID, subscribeID := "", ""
+		if len(channel.operations) == 1 {
+			publishID, subscribeID = channel.operations[0], channel.operations[0]+"Responses"
+		}
+		if len(channel.responses) > 0 {
+			addPair(n, "subscribe", asyncAPIOperation(subscribeID, channel.responses))
+		}
+		if len(channel.requests) > 0 {
+			addPair(n, "publish", asyncAPIOperation(publishID, channel.requests))
+		}
+		addPair(node, channel.path, n)
+	}
+	return node
+}
+
+// asyncAPIOperation returns an AsyncAPI operation with one or more messages.
+func asyncAPIOperation(operationID string, messages []*yaml.Node) *yaml.Node {
+	operation := compiler.NewMappingNode()
+	addString(operation, "operationId", operationID)
+	if len(messages) == 1 {
+		addPair(operation, "message", messages[0])
+		return operation
+	}
+	oneOf := compiler.NewSequenceNode()
+	oneOf.Content = messages
+	message := compiler.NewMappingNode()
+	addPair(message, "oneOf", oneOf)
+	addPair(operation, "message", message)
+	return operation
+}
+
+func (c *asyncAPIConverter) operation(channel *asyncAPIChannel, method string, operation *openapi3.Operation) {
+	pointer := "#/paths/" + compiler.EscapeJSONPointer(channel.path) + "/" + method
+	name := operation.OperationId
+	if name == "" {
+		name = operationName(method, channel.path)
+	}
+	channel.operations = append(channel.operations, name)
+	title := strings.ToUpper(method) + " " + channel.path
+
+	// Path parameters become channel parameters, and header parameters
+	// become message headers.
+	headers := newObjectSchema()
+	parameters := append(append([]*openapi3.ParameterOrReference{}, channel.item.Parameters...), operation.Parameters...)
+	for _, p := range parameters {
+		parameter := c.parameter(p)
+		if parameter == nil {
+			continue
+		}
+		switch parameter.In {
+		case "path":
+			if !channel.parameterNames[parameter.Name] {
+				channel.parameterNames[parameter.Name] = true
+				n := compiler.NewMappingNode()
+				addString(n, "description", parameter.Description)
+				if parameter.Schema != nil {
+					addPair(n, "schema", parameter.Schema.ToRawInfo())
+				}
+				addPair(channel.parameters, parameter.Name, n)
+			}
+		case "header":
+			headers.add(parameter.Name, parameter.Schema, parameter.Required)
+		default:
+			c.warn(pointer, "%s parameter %s isn't converted", parameter.In, parameter.Name)
+		}
+	}
+	if operation.Callbacks != nil && len(operation.Callbacks.AdditionalProperties) > 0 {
+		c.warn(pointer+"/callbacks", "callbacks aren't converted")
+	}
+
+	request := compiler.NewMappingNode()
+	addString(request, "name", name)
+	addString(request, "title", title)
+	addString(request, "summary", operation.Summary)
+	addString(request, "description", operation.Description)
+	if len(operation.Tags) > 0 {
+		tags := compiler.NewSequenceNode()
+		for _, tag := range operation.Tags {
+			t := compiler.NewMappingNode()
+			addString(t, "name", tag)
+			tags.Content = append(tags.Content, t)
+		}
+		addPair(request, "tags", tags)
+	}
+	if len(headers.properties.Content) > 0 {
+		addPair(request, "headers", headers.node())
+	}
+	if body := c.requestBody(operation.RequestBody); body != nil {
+		c.addPayload(request, body.Content, pointer+"/requestBody/content")
+	}
+	channel.requests = append(channel.requests, request)
+
+	for _, pair := range operation.GetResponses().GetResponseOrReference() {
+		c.addResponse(channel, name, title, pair.Name, pair.Value, pointer)
+	}
+	if response := operation.GetResponses().GetDefault(); response != nil {
+		c.addResponse(channel, name, title, "default", response, pointer)
+	}
+}
+
+func (c *asyncAPIConverter) addResponse(channel *asyncAPIChannel, name, title, status string, r *openapi3.ResponseOrReference, pointer string) {
+	response := c.response(r)
+	if response == nil || response.Content == nil || len(response.Content.AdditionalProperties) == 0 {
+		return
+	}
+	message := compiler.NewMappingNode()
+	addString(message, "name", name+strings.ToUpper(status[:1])+status[1:])
+	addString(message, "title", title+" "+status)
+	addString(message, "description", response.Description)
+	if response.Headers != nil {
+		headers := newObjectSchema()
+		for _, pair := range response.Headers.AdditionalProperties {
+			if header := c.header(pair.Value); header != nil {
+				headers.add(pair.Name, header.Schema, header.Required)
+			}
+		}
+		if len(headers.properties.Content) > 0 {
+			addPair(message, "headers", headers.node())
+		}
+	}
+	c.addPayload(message, response.Content, pointer+"/responses/"+status+"/content")
+	channel.responses = append(channel.responses, message)
+}
+
+// addPayload adds the content type, schema format and payload of a JSON
+// media type, or else of the first media type, to a message.
+func (c *asyncAPIConverter) addPayload(message *yaml.Node, content *openapi3.MediaTypes, pointer string) {
+	if content == nil || len(content.AdditionalProperties) == 0 {
+		return
+	}
+	selected := content.AdditionalProperties[0]
+	for _, pair := range content.AdditionalProperties {
+		if strings.Contains(pair.Name, "json") {
+			selected = pair
+			break
+		}
+	}
+	for _, pair := range content.AdditionalProperties {
+		if pair != selected {
+			c.warn(pointer+"/"+compiler.EscapeJSONPointer(pair.Name), "only the %s media type is converted", selected.Name)
+		}
+	}
+	addString(message, "contentType", selected.Name)
+	if schema := selected.Value.GetSchema(); schema != nil {
+		addString(message, "schemaFormat", openAPISchemaFormat)
+		addPair(message, "payload", schema.ToRawInfo())
+	}
+}
+
+func (c *asyncAPIConverter) parameter(p *openapi3.ParameterOrReference) *openapi3.Parameter {
+	if ref := p.GetReference().GetXRef(); ref != "" {
+		m, err := openapi3.Dereference(c.document, ref)
+		if err != nil {
+			c.warn(ref, "%s", err.Error())
+			return nil
+		}
+		parameter, _ := m.(*openapi3.Parameter)
+		return parameter
+	}
+	return p.GetParameter()
+}
+
+func (c *asyncAPIConverter) header(h *openapi3.HeaderOrReference) *openapi3.Header {
+	if ref := h.GetReference().GetXRef(); ref != "" {
+		m, err := openapi3.Dereference(c.document, ref)
+		if err != nil {
+			c.warn(ref, "%s", err.Error())
+			return nil
+		}
+		header, _ := m.(*openapi3.Header)
+		return header
+	}
+	return h.GetHeader()
+}
+
+func (c *asyncAPIConverter) requestBody(r *openapi3.RequestBodyOrReference) *openapi3.RequestBody {
+	if ref := r.GetReference().GetXRef(); ref != "" {
+		m, err := openapi3.Dereference(c.document, ref)
+		if err != nil {
+			c.warn(ref, "%s", err.Error())
+			return nil
+		}
+		body, _ := m.(*openapi3.RequestBody)
+		return body
+	}
+	return r.GetRequestBody()
+}
+
+func (c *asyncAPIConverter) response(r *openapi3.ResponseOrReference) *openapi3.Response {
+	if ref := r.GetReference().GetXRef(); ref != "" {
+		m, err := openapi3.Dereference(c.document, ref)
+		if err != nil {
+			c.warn(ref, "%s", err.Error())
+			return nil
+		}
+		response, _ := m.(*openapi3.Response)
+		return response
+	}
+	return r.GetResponse()
+}
+
+// An objectSchema is an object schema for headers that is being built.
+type objectSchema struct {
+	properties *yaml.Node
+	required   []string
+}
+
+func newObjectSchema() *objectSchema {
+	return &objectSchema{properties: compiler.NewMappingNode()}
+}
+
+func (s *objectSchema) add(name string, schema *openapi3.SchemaOrReference, required bool) {
+	value := compiler.NewMappingNode()
+	if schema != nil {
+		value = schema.ToRawInfo()
+	}
+	addPair(s.properties, name, value)
+	if required {
+		s.required = append(s.required, name)
+	}
+}
+
+func (s *objectSchema) node() *yaml.Node {
+	node := compiler.NewMappingNode()
+	addString(node, "type", "object")
+	addPair(node, "properties", s.properties)
+	if len(s.required) > 0 {
+		addPair(node, "required", compiler.NewSequenceNodeForStringArray(s.required))
+	}
+	return node
+}
+
+// operationName returns a name for an operation without an operationId,
+// such as "getPetsPetId" for GET /pets/{petId}.
+func operationName(method, path string) string {
+	name := strings.ToLower(method)
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		name += strings.ToUpper(word[:1]) + word[1:]
+	}
+	return name
+}
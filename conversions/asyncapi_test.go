@@ -0,0 +1,67 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+func TestAsyncAPIFromOpenAPIv3(t *testing.T) {
+	bytes, err := ioutil.ReadFile("testdata/events.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, err := openapi3.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	node, warnings, err := AsyncAPIFromOpenAPIv3(document)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	output, err := yaml.Marshal(node)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected, err := ioutil.ReadFile("testdata/events-asyncapi.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if string(output) != string(expected) {
+		ioutil.WriteFile("testdata/events-asyncapi.yaml.out", output, 0644)
+		t.Errorf("converted document differs from testdata/events-asyncapi.yaml")
+	} else {
+		os.Remove("testdata/events-asyncapi.yaml.out")
+	}
+	expectedWarnings := []string{
+		"#/paths/~1orders/post: query parameter dryRun isn't converted",
+		"#/paths/~1orders/post/callbacks: callbacks aren't converted",
+		"#/paths/~1orders/post/requestBody/content/application~1xml: only the application/json media type is converted",
+		"#/components/securitySchemes: security schemes aren't converted",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(expectedWarnings, "\n") {
+		t.Errorf("unexpected warnings:\n%s", strings.Join(warnings, "\n"))
+	}
+	if _, _, err := AsyncAPIFromOpenAPIv3(nil); err == nil {
+		t.Errorf("expected an error for a missing document")
+	}
+}
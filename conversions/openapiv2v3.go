@@ -0,0 +1,966 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+// ConversionOptions control the conversion of Swagger 2.0 documents to OpenAPI v3.
+type ConversionOptions struct {
+	// CookieExtension is the name of an extension, such as
+	// "x-cookie-parameter", that marks parameters as cookie parameters.
+	// Swagger 2.0 has no cookie parameters, so some descriptions use
+	// header parameters with extensions instead. Marked parameters become
+	// cookie parameters and the extension is removed. If empty, no
+	// parameters become cookie parameters.
+	CookieExtension string
+	// ExternalParameter returns the parameter that a reference to another
+	// file refers to, or nil if it is unknown. External body and form
+	// parameters become request bodies like local ones. If nil, external
+	// parameters are assumed to be non-body parameters.
+	ExternalParameter func(ref string) *openapi2.Parameter
+}
+
+// OpenAPIv3FromOpenAPIv2 converts a Swagger 2.0 document to an OpenAPI v3
+// document. It also returns warnings for parts of the document that
+// can't be represented exactly in OpenAPI v3 and were changed, such as
+// collection formats that aren't supported by parameter styles.
+func OpenAPIv3FromOpenAPIv2(source *openapi2.Document, options *ConversionOptions) (*openapi3.Document, []string, error) {
+	if source == nil {
+		return nil, nil, errors.New("no document to convert")
+	}
+	c := &v2Converter{source: source}
+	if options != nil {
+		c.options = *options
+	}
+	d := &openapi3.Document{Openapi: "3.0.3"}
+	// This copies the info, security requirements, tags, external docs, and extensions.
+	copyFields(source.ProtoReflect(), d.ProtoReflect())
+	d.Servers = c.servers()
+	d.Paths = c.paths()
+	d.Components = c.components()
+	return d, c.warnings, nil
+}
+
+// ConvertedRawInfo returns the YAML representation of a document that was
+// returned by OpenAPIv3FromOpenAPIv2. Use it instead of the ToRawInfo
+// method of the document: the OpenAPI v3 model omits "explode: false", but
+// form style parameters and encodings are exploded by default, so the
+// converted csv parameters would become multi parameters. ConvertedRawInfo
+// writes "explode: false" for them.
+func ConvertedRawInfo(d *openapi3.Document) *yaml.Node {
+	node := d.ToRawInfo()
+	for _, item := range mapValues(compiler.MapValueForKey(node, "paths")) {
+		addExplodeToParameters(compiler.MapValueForKey(item, "parameters"))
+		for _, method := range []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"} {
+			if operation := compiler.MapValueForKey(item, method); operation != nil {
+				addExplodeToParameters(compiler.MapValueForKey(operation, "parameters"))
+				addExplodeToEncodings(compiler.MapValueForKey(operation, "requestBody"))
+			}
+		}
+	}
+	components := compiler.MapValueForKey(node, "components")
+	for _, parameter := range mapValues(compiler.MapValueForKey(components, "parameters")) {
+		addExplode(parameter)
+	}
+	for _, body := range mapValues(compiler.MapValueForKey(components, "requestBodies")) {
+		addExplodeToEncodings(body)
+	}
+	return node
+}
+
+// mapValues returns the values of a mapping node.
+func mapValues(node *yaml.Node) []*yaml.Node {
+	var values []*yaml.Node
+	if node == nil || node.Kind != yaml.MappingNode {
+		return values
+	}
+	for i := 1; i < len(node.Content); i += 2 {
+		values = append(values, node.Content[i])
+	}
+	return values
+}
+
+func addExplodeToParameters(parameters *yaml.Node) {
+	if parameters == nil || parameters.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, parameter := range parameters.Content {
+		addExplode(parameter)
+	}
+}
+
+func addExplodeToEncodings(body *yaml.Node) {
+	for _, mediaType := range mapValues(compiler.MapValueForKey(body, "content")) {
+		for _, encoding := range mapValues(compiler.MapValueForKey(mediaType, "encoding")) {
+			addExplode(encoding)
+		}
+	}
+}
+
+// addExplode adds "explode: false" after the style of a form style
+// parameter or encoding that has no explode.
+func addExplode(node *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode || compiler.MapValueForKey(node, "explode") != nil {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "style" && node.Content[i+1].Value == "form" {
+			explode := []*yaml.Node{compiler.NewScalarNodeForString("explode"), compiler.NewScalarNodeForBool(false)}
+			node.Content = append(node.Content[:i+2], append(explode, node.Content[i+2:]...)...)
+			return
+		}
+	}
+}
+
+type v2Converter struct {
+	source   *openapi2.Document
+	options  ConversionOptions
+	warnings []string
+}
+
+func (c *v2Converter) warn(pointer, format string, args ...interface{}) {
+	c.warnings = append(c.warnings, pointer+": "+fmt.Sprintf(format, args...))
+}
+
+// sameShape lists the messages that are equivalent in both models.
+var sameShape = map[protoreflect.Name]bool{
+	"Any":                 true,
+	"NamedAny":            true,
+	"Info":                true,
+	"Contact":             true,
+	"License":             true,
+	"ExternalDocs":        true,
+	"Xml":                 true,
+	"Tag":                 true,
+	"SecurityRequirement": true,
+	"NamedStringArray":    true,
+	"StringArray":         true,
+}
+
+// copyFields copies fields of a Swagger 2.0 message to fields of an OpenAPI v3
+// message with the same names and types. Vendor extensions are copied to
+// specification extensions. Other fields are converted by the callers.
+func copyFields(src, dst protoreflect.Message) {
+	fields := dst.Descriptor().Fields()
+	src.Range(func(f protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := f.Name()
+		if name == "vendor_extension" {
+			name = "specification_extension"
+		}
+		g := fields.ByName(name)
+		if g == nil || g.Kind() != f.Kind() || g.IsList() != f.IsList() || g.IsMap() || f.IsMap() {
+			return true
+		}
+		if f.Kind() != protoreflect.MessageKind {
+			if f.IsList() {
+				list := dst.Mutable(g).List()
+				for i := 0; i < v.List().Len(); i++ {
+					list.Append(v.List().Get(i))
+				}
+			} else {
+				dst.Set(g, v)
+			}
+			return true
+		}
+		switch {
+		case f.Message().FullName() == g.Message().FullName():
+			dst.Set(g, protoreflect.ValueOfMessage(proto.Clone(v.Message().Interface()).ProtoReflect()))
+		case f.Message().Name() != g.Message().Name() || !sameShape[f.Message().Name()]:
+		case f.IsList():
+			list := dst.Mutable(g).List()
+			for i := 0; i < v.List().Len(); i++ {
+				element := list.NewElement()
+				copyFields(v.List().Get(i).Message(), element.Message())
+				list.Append(element)
+			}
+		default:
+			copyFields(v.Message(), dst.Mutable(g).Message())
+		}
+		return true
+	})
+}
+
+// convertRef converts references to Swagger 2.0 definitions, parameters and
+// responses into references to the corresponding OpenAPI v3 components.
+func convertRef(ref string) string {
+	for _, prefix := range []struct{ v2, v3 string }{
+		{"#/definitions/", "#/components/schemas/"},
+		{"#/parameters/", "#/components/parameters/"},
+		{"#/responses/", "#/components/responses/"},
+	} {
+		if i := strings.Index(ref, prefix.v2); i >= 0 {
+			return ref[:i] + prefix.v3 + ref[i+len(prefix.v2):]
+		}
+	}
+	return ref
+}
+
+func schemaReference(ref string) *openapi3.SchemaOrReference {
+	return &openapi3.SchemaOrReference{
+		Oneof: &openapi3.SchemaOrReference_Reference{Reference: &openapi3.Reference{XRef: convertRef(ref)}},
+	}
+}
+
+func schemaOrReference(schema *openapi3.Schema) *openapi3.SchemaOrReference {
+	return &openapi3.SchemaOrReference{Oneof: &openapi3.SchemaOrReference_Schema{Schema: schema}}
+}
+
+// servers returns a server for each scheme of the document.
+func (c *v2Converter) servers() []*openapi3.Server {
+	if c.source.Host == "" {
+		if c.source.BasePath == "" || c.source.BasePath == "/" {
+			return nil
+		}
+		return []*openapi3.Server{{Url: c.source.BasePath}}
+	}
+	schemes := c.source.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+	var servers []*openapi3.Server
+	for _, scheme := range schemes {
+		servers = append(servers, &openapi3.Server{Url: scheme + "://" + c.source.Host + c.source.BasePath})
+	}
+	return servers
+}
+
+func (c *v2Converter) paths() *openapi3.Paths {
+	paths := &openapi3.Paths{}
+	if c.source.Paths == nil {
+		return paths
+	}
+	copyFields(c.source.Paths.ProtoReflect(), paths.ProtoReflect())
+	for _, pair := range c.source.Paths.Path {
+		pointer := "#/paths/" + compiler.EscapeJSONPointer(pair.Name)
+		paths.Path = append(paths.Path, &openapi3.NamedPathItem{
+			Name:  pair.Name,
+			Value: c.pathItem(pair.Value, pointer),
+		})
+	}
+	return paths
+}
+
+func (c *v2Converter) pathItem(item *openapi2.PathItem, pointer string) *openapi3.PathItem {
+	result := &openapi3.PathItem{XRef: item.XRef}
+	copyFields(item.ProtoReflect(), result.ProtoReflect())
+	// Body and form parameters of path items are added to the request
+	// bodies of their operations.
+	var shared *requestParameters
+	result.Parameters, shared = c.parameters(item.Parameters, pointer+"/parameters")
+	for _, operation := range []struct {
+		method string
+		source *openapi2.Operation
+		target **openapi3.Operation
+	}{
+		{"get", item.Get, &result.Get},
+		{"put", item.Put, &result.Put},
+		{"post", item.Post, &result.Post},
+		{"delete", item.Delete, &result.Delete},
+		{"options", item.Options, &result.Options},
+		{"head", item.Head, &result.Head},
+		{"patch", item.Patch, &result.Patch},
+	} {
+		if operation.source != nil {
+			*operation.target = c.operation(operation.source, shared, pointer+"/"+operation.method)
+		}
+	}
+	return result
+}
+
+func (c *v2Converter) operation(operation *openapi2.Operation, shared *requestParameters, pointer string) *openapi3.Operation {
+	result := &openapi3.Operation{}
+	copyFields(operation.ProtoReflect(), result.ProtoReflect())
+	var request *requestParameters
+	result.Parameters, request = c.parameters(operation.Parameters, pointer+"/parameters")
+	request.merge(shared)
+	consumes := operation.Consumes
+	if len(consumes) == 0 {
+		consumes = c.source.Consumes
+	}
+	result.RequestBody = c.requestBody(request, consumes, pointer)
+	produces := operation.Produces
+	if len(produces) == 0 {
+		produces = c.source.Produces
+	}
+	result.Responses = c.responses(operation.Responses, produces, pointer+"/responses")
+	return result
+}
+
+// requestParameters are the parameters of an operation that become its request body.
+type requestParameters struct {
+	body    *openapi2.BodyParameter
+	bodyRef string
+	form    []*openapi2.FormDataParameterSubSchema
+}
+
+// merge adds the parameters of a path item that aren't overridden by an operation.
+func (r *requestParameters) merge(shared *requestParameters) {
+	if r.body == nil && r.bodyRef == "" {
+		r.body, r.bodyRef = shared.body, shared.bodyRef
+	}
+	for _, parameter := range shared.form {
+		overridden := false
+		for _, p := range r.form {
+			overridden = overridden || p.Name == parameter.Name
+		}
+		if !overridden {
+			r.form = append(r.form, parameter)
+		}
+	}
+}
+
+// parameters converts a list of parameters. Body and form parameters
+// are returned separately because they become request bodies.
+func (c *v2Converter) parameters(items []*openapi2.ParametersItem, pointer string) ([]*openapi3.ParameterOrReference, *requestParameters) {
+	var parameters []*openapi3.ParameterOrReference
+	request := &requestParameters{}
+	for i, item := range items {
+		itemPointer := fmt.Sprintf("%s/%d", pointer, i)
+		parameter := item.GetParameter()
+		if reference := item.GetJsonReference(); reference != nil {
+			parameter = c.parameterForRef(reference.XRef)
+			if parameter == nil {
+				// Unknown and external parameters are assumed to be non-body parameters.
+				parameters = append(parameters, &openapi3.ParameterOrReference{
+					Oneof: &openapi3.ParameterOrReference_Reference{Reference: &openapi3.Reference{XRef: convertRef(reference.XRef)}},
+				})
+				continue
+			}
+			if body := parameter.GetBodyParameter(); body != nil {
+				request.bodyRef = strings.Replace(reference.XRef, "#/parameters/", "#/components/requestBodies/", 1)
+				continue
+			}
+			if form := parameter.GetNonBodyParameter().GetFormDataParameterSubSchema(); form != nil {
+				// Form parameters can't be components, so they are copied.
+				request.form = append(request.form, form)
+				continue
+			}
+			parameters = append(parameters, &openapi3.ParameterOrReference{
+				Oneof: &openapi3.ParameterOrReference_Reference{Reference: &openapi3.Reference{XRef: convertRef(reference.XRef)}},
+			})
+			continue
+		}
+		if body := parameter.GetBodyParameter(); body != nil {
+			request.body = body
+			continue
+		}
+		if form := parameter.GetNonBodyParameter().GetFormDataParameterSubSchema(); form != nil {
+			request.form = append(request.form, form)
+			continue
+		}
+		if p := c.parameter(parameter.GetNonBodyParameter(), itemPointer); p != nil {
+			parameters = append(parameters, &openapi3.ParameterOrReference{
+				Oneof: &openapi3.ParameterOrReference_Parameter{Parameter: p},
+			})
+		}
+	}
+	return parameters, request
+}
+
+// parameterForRef returns the parameter definition of a reference, or nil
+// if the reference doesn't refer to a parameter of the document or to a
+// known external parameter.
+func (c *v2Converter) parameterForRef(ref string) *openapi2.Parameter {
+	if !strings.HasPrefix(ref, "#") {
+		if c.options.ExternalParameter == nil {
+			return nil
+		}
+		return c.options.ExternalParameter(ref)
+	}
+	if !strings.HasPrefix(ref, "#/parameters/") {
+		return nil
+	}
+	for _, pair := range c.source.GetParameters().GetAdditionalProperties() {
+		if pair.Name == strings.TrimPrefix(ref, "#/parameters/") {
+			return pair.Value
+		}
+	}
+	return nil
+}
+
+// nonBodyFields returns the message of a non-body parameter. All of them
+// have the same fields, except that only query and form parameters
+// have allowEmptyValue.
+func nonBodyFields(parameter *openapi2.NonBodyParameter) protoreflect.Message {
+	switch p := parameter.GetOneof().(type) {
+	case *openapi2.NonBodyParameter_HeaderParameterSubSchema:
+		return p.HeaderParameterSubSchema.ProtoReflect()
+	case *openapi2.NonBodyParameter_FormDataParameterSubSchema:
+		return p.FormDataParameterSubSchema.ProtoReflect()
+	case *openapi2.NonBodyParameter_QueryParameterSubSchema:
+		return p.QueryParameterSubSchema.ProtoReflect()
+	case *openapi2.NonBodyParameter_PathParameterSubSchema:
+		return p.PathParameterSubSchema.ProtoReflect()
+	}
+	return nil
+}
+
+func stringField(m protoreflect.Message, name protoreflect.Name) string {
+	if f := m.Descriptor().Fields().ByName(name); f != nil && f.Kind() == protoreflect.StringKind {
+		return m.Get(f).String()
+	}
+	return ""
+}
+
+func messageField(m protoreflect.Message, name protoreflect.Name) protoreflect.Message {
+	if f := m.Descriptor().Fields().ByName(name); f != nil && f.Kind() == protoreflect.MessageKind && m.Has(f) {
+		return m.Get(f).Message()
+	}
+	return nil
+}
+
+// parameter converts a query, header or path parameter.
+func (c *v2Converter) parameter(parameter *openapi2.NonBodyParameter, pointer string) *openapi3.Parameter {
+	m := nonBodyFields(parameter)
+	if m == nil {
+		return nil
+	}
+	result := &openapi3.Parameter{}
+	copyFields(m, result.ProtoReflect())
+	schema := c.primitiveSchema(m, pointer)
+	result.Schema = schemaOrReference(schema)
+	schema.Description, schema.SpecificationExtension = "", nil
+	result.SpecificationExtension = c.moveEnumExtensions(result.SpecificationExtension, schema, pointer)
+
+	if name := c.options.CookieExtension; name != "" {
+		for i, extension := range result.SpecificationExtension {
+			if extension.Name == name && isTrue(extension.Value) {
+				result.In = "cookie"
+				result.AllowEmptyValue = false
+				result.SpecificationExtension = append(result.SpecificationExtension[:i:i], result.SpecificationExtension[i+1:]...)
+				break
+			}
+		}
+	}
+	if result.In == "header" {
+		switch strings.ToLower(result.Name) {
+		case "accept", "content-type", "authorization":
+			c.warn(pointer, "OpenAPI v3 ignores header parameters named %s", result.Name)
+		}
+	}
+	if schema.Type == "array" {
+		c.setStyle(result, stringField(m, "collection_format"), pointer)
+	}
+	return result
+}
+
+// setStyle sets the style of an array parameter to the equivalent of its
+// collection format. Formats that aren't supported in the location of
+// the parameter are replaced with the default style of the location.
+//
+// The OpenAPI v3 model omits "explode: false", which is the default
+// for all styles but "form". ConvertedRawInfo writes it for form style
+// parameters that aren't exploded.
+func (c *v2Converter) setStyle(parameter *openapi3.Parameter, collectionFormat, pointer string) {
+	if collectionFormat == "" {
+		collectionFormat = "csv"
+	}
+	switch parameter.In {
+	case "query":
+		switch collectionFormat {
+		case "csv":
+			parameter.Style, parameter.Explode = "form", false
+		case "ssv":
+			parameter.Style = "spaceDelimited"
+		case "pipes":
+			parameter.Style = "pipeDelimited"
+		case "multi":
+			parameter.Style, parameter.Explode = "form", true
+		default:
+			c.warn(pointer, "collectionFormat %s isn't supported for query parameters, using csv", collectionFormat)
+			parameter.Style, parameter.Explode = "form", false
+		}
+	case "cookie":
+		if collectionFormat != "csv" {
+			c.warn(pointer, "collectionFormat %s isn't supported for cookie parameters, using csv", collectionFormat)
+		}
+		parameter.Style, parameter.Explode = "form", false
+	default:
+		// Path and header parameters only support the simple style.
+		if collectionFormat != "csv" {
+			c.warn(pointer, "collectionFormat %s isn't supported for %s parameters, using csv", collectionFormat, parameter.In)
+		}
+		parameter.Style, parameter.Explode = "simple", false
+	}
+}
+
+// isTrue returns true if an extension value is true.
+func isTrue(value *openapi3.Any) bool {
+	var b bool
+	return value != nil && yaml.Unmarshal([]byte(value.Yaml), &b) == nil && b
+}
+
+// primitiveSchema converts the type and validation fields that parameters,
+// headers and their items share into a schema.
+func (c *v2Converter) primitiveSchema(m protoreflect.Message, pointer string) *openapi3.Schema {
+	schema := &openapi3.Schema{}
+	copyFields(m, schema.ProtoReflect())
+	if schema.Type == "file" {
+		schema.Type, schema.Format = "string", "binary"
+	}
+	if items := messageField(m, "items"); items != nil {
+		itemsSchema := c.primitiveSchema(items, pointer+"/items")
+		c.renameEnumExtensions(itemsSchema, pointer+"/items")
+		schema.Items = &openapi3.ItemsItem{
+			SchemaOrReference: []*openapi3.SchemaOrReference{schemaOrReference(itemsSchema)},
+		}
+	}
+	if d := messageField(m, "default"); d != nil {
+		schema.Default = c.defaultValue(d.Interface().(*openapi2.Any), pointer+"/default")
+	}
+	return schema
+}
+
+// defaultValue converts a default value. OpenAPI v3 models only support
+// scalar defaults.
+func (c *v2Converter) defaultValue(value *openapi2.Any, pointer string) *openapi3.DefaultType {
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(value.GetYaml()), &v); err != nil {
+		return nil
+	}
+	d, ok := defaultType(v)
+	if !ok {
+		c.warn(pointer, "default values that aren't strings, numbers or booleans aren't supported")
+	}
+	return d
+}
+
+// defaultType converts a decoded default value. It returns false for values
+// that aren't supported.
+func defaultType(v interface{}) (*openapi3.DefaultType, bool) {
+	switch v := v.(type) {
+	case string:
+		return &openapi3.DefaultType{Oneof: &openapi3.DefaultType_String_{String_: v}}, true
+	case bool:
+		return &openapi3.DefaultType{Oneof: &openapi3.DefaultType_Boolean{Boolean: v}}, true
+	case int:
+		return &openapi3.DefaultType{Oneof: &openapi3.DefaultType_Number{Number: float64(v)}}, true
+	case float64:
+		return &openapi3.DefaultType{Oneof: &openapi3.DefaultType_Number{Number: v}}, true
+	case nil:
+		return nil, true
+	}
+	return nil, false
+}
+
+// schema converts a schema. Extensions named x-nullable become nullable.
+func (c *v2Converter) schema(s *openapi2.Schema, pointer string) *openapi3.SchemaOrReference {
+	if s.XRef != "" {
+		return schemaReference(s.XRef)
+	}
+	schema := &openapi3.Schema{}
+	copyFields(s.ProtoReflect(), schema.ProtoReflect())
+	if types := s.GetType().GetValue(); len(types) > 0 {
+		schema.Type = types[0]
+		if len(types) > 1 {
+			c.warn(pointer, "lists of types aren't supported, using %s", types[0])
+		}
+	}
+	if schema.Type == "file" {
+		schema.Type, schema.Format = "string", "binary"
+	}
+	if s.Default != nil {
+		schema.Default = c.defaultValue(s.Default, pointer+"/default")
+	}
+	if s.Discriminator != "" {
+		schema.Discriminator = &openapi3.Discriminator{PropertyName: s.Discriminator}
+	}
+	for i, item := range s.GetItems().GetSchema() {
+		if schema.Items == nil {
+			schema.Items = &openapi3.ItemsItem{}
+		}
+		schema.Items.SchemaOrReference = append(schema.Items.SchemaOrReference, c.schema(item, fmt.Sprintf("%s/items/%d", pointer, i)))
+	}
+	for i, member := range s.AllOf {
+		schema.AllOf = append(schema.AllOf, c.schema(member, fmt.Sprintf("%s/allOf/%d", pointer, i)))
+	}
+	if s.Properties != nil {
+		schema.Properties = &openapi3.Properties{}
+		for _, pair := range s.Properties.AdditionalProperties {
+			schema.Properties.AdditionalProperties = append(schema.Properties.AdditionalProperties, &openapi3.NamedSchemaOrReference{
+				Name:  pair.Name,
+				Value: c.schema(pair.Value, pointer+"/properties/"+pair.Name),
+			})
+		}
+	}
+	switch a := s.GetAdditionalProperties().GetOneof().(type) {
+	case *openapi2.AdditionalPropertiesItem_Schema:
+		schema.AdditionalProperties = &openapi3.AdditionalPropertiesItem{
+			Oneof: &openapi3.AdditionalPropertiesItem_SchemaOrReference{SchemaOrReference: c.schema(a.Schema, pointer+"/additionalProperties")},
+		}
+	case *openapi2.AdditionalPropertiesItem_Boolean:
+		schema.AdditionalProperties = &openapi3.AdditionalPropertiesItem{
+			Oneof: &openapi3.AdditionalPropertiesItem_Boolean{Boolean: a.Boolean},
+		}
+	}
+	for i, extension := range schema.SpecificationExtension {
+		if extension.Name == "x-nullable" {
+			schema.Nullable = isTrue(extension.Value)
+			schema.SpecificationExtension = append(schema.SpecificationExtension[:i:i], schema.SpecificationExtension[i+1:]...)
+			break
+		}
+	}
+	c.renameEnumExtensions(schema, pointer)
+	return schemaOrReference(schema)
+}
+
+// enumExtensionNames maps the names of the extensions that name and
+// describe enum values to the names that OpenAPI v3 code generators use.
+// NSwag writes the names of enum values as x-enumNames.
+var enumExtensionNames = map[string]string{
+	"x-enum-varnames":     "x-enum-varnames",
+	"x-enum-descriptions": "x-enum-descriptions",
+	"x-enumNames":         "x-enum-varnames",
+}
+
+// enumExtension returns an extension that names or describes the values
+// of the enum of a schema with the name that OpenAPI v3 code generators
+// use. Lists that don't have an entry for each value are reported.
+func (c *v2Converter) enumExtension(extension *openapi3.NamedAny, schema *openapi3.Schema, pointer string) (*openapi3.NamedAny, bool) {
+	name, ok := enumExtensionNames[extension.Name]
+	if !ok {
+		return nil, false
+	}
+	var values []interface{}
+	if extension.Value == nil || yaml.Unmarshal([]byte(extension.Value.Yaml), &values) != nil {
+		c.warn(pointer, "%s isn't a list", extension.Name)
+	} else if len(values) != len(schema.Enum) {
+		c.warn(pointer, "%s has %d entries for %d enum values", extension.Name, len(values), len(schema.Enum))
+	}
+	return &openapi3.NamedAny{Name: name, Value: extension.Value}, true
+}
+
+// renameEnumExtensions renames the extensions of a schema that name and
+// describe the values of its enum.
+func (c *v2Converter) renameEnumExtensions(schema *openapi3.Schema, pointer string) {
+	for i, extension := range schema.SpecificationExtension {
+		if renamed, ok := c.enumExtension(extension, schema, pointer); ok {
+			schema.SpecificationExtension[i] = renamed
+		}
+	}
+}
+
+// moveEnumExtensions moves the extensions that name and describe the
+// values of the enum of a parameter or header to its schema and returns
+// the remaining extensions.
+func (c *v2Converter) moveEnumExtensions(extensions []*openapi3.NamedAny, schema *openapi3.Schema, pointer string) []*openapi3.NamedAny {
+	remaining := extensions[:0:0]
+	for _, extension := range extensions {
+		if renamed, ok := c.enumExtension(extension, schema, pointer); ok {
+			schema.SpecificationExtension = append(schema.SpecificationExtension, renamed)
+		} else {
+			remaining = append(remaining, extension)
+		}
+	}
+	return remaining
+}
+
+// content returns content with the same schema for each media type.
+func content(mediaTypes []string, schema *openapi3.SchemaOrReference) *openapi3.MediaTypes {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{"application/json"}
+	}
+	result := &openapi3.MediaTypes{}
+	for _, mediaType := range mediaTypes {
+		result.AdditionalProperties = append(result.AdditionalProperties, &openapi3.NamedMediaType{
+			Name:  mediaType,
+			Value: &openapi3.MediaType{Schema: schema},
+		})
+	}
+	return result
+}
+
+// requestBody converts body or form parameters into a request body.
+func (c *v2Converter) requestBody(request *requestParameters, consumes []string, pointer string) *openapi3.RequestBodyOrReference {
+	switch {
+	case request.bodyRef != "":
+		return &openapi3.RequestBodyOrReference{
+			Oneof: &openapi3.RequestBodyOrReference_Reference{Reference: &openapi3.Reference{XRef: request.bodyRef}},
+		}
+	case request.body != nil:
+		return &openapi3.RequestBodyOrReference{
+			Oneof: &openapi3.RequestBodyOrReference_RequestBody{RequestBody: c.bodyParameter(request.body, consumes, pointer)},
+		}
+	case len(request.form) > 0:
+		return &openapi3.RequestBodyOrReference{
+			Oneof: &openapi3.RequestBodyOrReference_RequestBody{RequestBody: c.formParameters(request.form, consumes, pointer)},
+		}
+	}
+	return nil
+}
+
+func (c *v2Converter) bodyParameter(body *openapi2.BodyParameter, consumes []string, pointer string) *openapi3.RequestBody {
+	result := &openapi3.RequestBody{Description: body.Description, Required: body.Required}
+	copyFields(body.ProtoReflect(), result.ProtoReflect())
+	var schema *openapi3.SchemaOrReference
+	if body.Schema != nil {
+		schema = c.schema(body.Schema, pointer+"/requestBody")
+	}
+	result.Content = content(consumes, schema)
+	return result
+}
+
+// formParameters converts form parameters into a request body with an
+// object schema. Files are sent as multipart/form-data.
+func (c *v2Converter) formParameters(form []*openapi2.FormDataParameterSubSchema, consumes []string, pointer string) *openapi3.RequestBody {
+	mediaType := "application/x-www-form-urlencoded"
+	for _, parameter := range form {
+		if parameter.Type == "file" {
+			mediaType = "multipart/form-data"
+		}
+	}
+	for _, consumed := range consumes {
+		if consumed == "multipart/form-data" {
+			mediaType = consumed
+		}
+	}
+	schema := &openapi3.Schema{Type: "object", Properties: &openapi3.Properties{}}
+	encodings := &openapi3.Encodings{}
+	for _, parameter := range form {
+		property := c.primitiveSchema(parameter.ProtoReflect(), pointer+"/requestBody/"+parameter.Name)
+		c.renameEnumExtensions(property, pointer+"/requestBody/"+parameter.Name)
+		schema.Properties.AdditionalProperties = append(schema.Properties.AdditionalProperties, &openapi3.NamedSchemaOrReference{
+			Name:  parameter.Name,
+			Value: schemaOrReference(property),
+		})
+		if parameter.Required {
+			schema.Required = append(schema.Required, parameter.Name)
+		}
+		if property.Type != "array" {
+			continue
+		}
+		encoding := &openapi3.Encoding{Style: "form"}
+		switch parameter.CollectionFormat {
+		case "multi":
+			encoding.Explode = true
+		case "ssv":
+			encoding.Style = "spaceDelimited"
+		case "pipes":
+			encoding.Style = "pipeDelimited"
+		case "", "csv":
+		default:
+			c.warn(pointer, "collectionFormat %s isn't supported for form parameters, using csv", parameter.CollectionFormat)
+		}
+		encodings.AdditionalProperties = append(encodings.AdditionalProperties, &openapi3.NamedEncoding{Name: parameter.Name, Value: encoding})
+	}
+	result := &openapi3.RequestBody{Content: content([]string{mediaType}, schemaOrReference(schema))}
+	if len(encodings.AdditionalProperties) > 0 {
+		result.Content.AdditionalProperties[0].Value.Encoding = encodings
+	}
+	for _, parameter := range form {
+		result.Required = result.Required || parameter.Required
+	}
+	return result
+}
+
+func (c *v2Converter) responses(responses *openapi2.Responses, produces []string, pointer string) *openapi3.Responses {
+	result := &openapi3.Responses{}
+	if responses == nil {
+		return result
+	}
+	copyFields(responses.ProtoReflect(), result.ProtoReflect())
+	for _, pair := range responses.ResponseCode {
+		value := &openapi3.ResponseOrReference{}
+		if reference := pair.Value.GetJsonReference(); reference != nil {
+			value.Oneof = &openapi3.ResponseOrReference_Reference{Reference: &openapi3.Reference{XRef: convertRef(reference.XRef)}}
+		} else {
+			value.Oneof = &openapi3.ResponseOrReference_Response{Response: c.response(pair.Value.GetResponse(), produces, pointer+"/"+pair.Name)}
+		}
+		if pair.Name == "default" {
+			result.Default = value
+			continue
+		}
+		result.ResponseOrReference = append(result.ResponseOrReference, &openapi3.NamedResponseOrReference{Name: pair.Name, Value: value})
+	}
+	return result
+}
+
+func (c *v2Converter) response(response *openapi2.Response, produces []string, pointer string) *openapi3.Response {
+	result := &openapi3.Response{}
+	copyFields(response.ProtoReflect(), result.ProtoReflect())
+	switch s := response.GetSchema().GetOneof().(type) {
+	case *openapi2.SchemaItem_Schema:
+		result.Content = content(produces, c.schema(s.Schema, pointer+"/schema"))
+	case *openapi2.SchemaItem_FileSchema:
+		schema := &openapi3.Schema{Type: "string", Format: "binary", Description: s.FileSchema.Description}
+		result.Content = content(produces, schemaOrReference(schema))
+	}
+	for _, example := range response.GetExamples().GetAdditionalProperties() {
+		if result.Content == nil {
+			result.Content = &openapi3.MediaTypes{}
+		}
+		var mediaType *openapi3.MediaType
+		for _, pair := range result.Content.AdditionalProperties {
+			if pair.Name == example.Name {
+				mediaType = pair.Value
+			}
+		}
+		if mediaType == nil {
+			mediaType = &openapi3.MediaType{}
+			result.Content.AdditionalProperties = append(result.Content.AdditionalProperties, &openapi3.NamedMediaType{Name: example.Name, Value: mediaType})
+		}
+		mediaType.Example = &openapi3.Any{Yaml: example.Value.GetYaml()}
+	}
+	for _, pair := range response.GetHeaders().GetAdditionalProperties() {
+		if result.Headers == nil {
+			result.Headers = &openapi3.HeadersOrReferences{}
+		}
+		headerPointer := pointer + "/headers/" + pair.Name
+		header := &openapi3.Header{Description: pair.Value.Description}
+		schema := c.primitiveSchema(pair.Value.ProtoReflect(), headerPointer)
+		extensions := schema.SpecificationExtension
+		schema.Description, schema.SpecificationExtension = "", nil
+		header.SpecificationExtension = c.moveEnumExtensions(extensions, schema, headerPointer)
+		header.Schema = schemaOrReference(schema)
+		if schema.Type == "array" {
+			if format := pair.Value.CollectionFormat; format != "" && format != "csv" {
+				c.warn(headerPointer, "collectionFormat %s isn't supported for headers, using csv", format)
+			}
+			header.Style = "simple"
+		}
+		result.Headers.AdditionalProperties = append(result.Headers.AdditionalProperties, &openapi3.NamedHeaderOrReference{
+			Name:  pair.Name,
+			Value: &openapi3.HeaderOrReference{Oneof: &openapi3.HeaderOrReference_Header{Header: header}},
+		})
+	}
+	return result
+}
+
+func (c *v2Converter) components() *openapi3.Components {
+	components := &openapi3.Components{}
+	for _, pair := range c.source.GetDefinitions().GetAdditionalProperties() {
+		if components.Schemas == nil {
+			components.Schemas = &openapi3.SchemasOrReferences{}
+		}
+		components.Schemas.AdditionalProperties = append(components.Schemas.AdditionalProperties, &openapi3.NamedSchemaOrReference{
+			Name:  pair.Name,
+			Value: c.schema(pair.Value, "#/definitions/"+pair.Name),
+		})
+	}
+	for _, pair := range c.source.GetParameters().GetAdditionalProperties() {
+		pointer := "#/parameters/" + pair.Name
+		if body := pair.Value.GetBodyParameter(); body != nil {
+			if components.RequestBodies == nil {
+				components.RequestBodies = &openapi3.RequestBodiesOrReferences{}
+			}
+			components.RequestBodies.AdditionalProperties = append(components.RequestBodies.AdditionalProperties, &openapi3.NamedRequestBodyOrReference{
+				Name: pair.Name,
+				Value: &openapi3.RequestBodyOrReference{
+					Oneof: &openapi3.RequestBodyOrReference_RequestBody{RequestBody: c.bodyParameter(body, c.source.Consumes, pointer)},
+				},
+			})
+			continue
+		}
+		if pair.Value.GetNonBodyParameter().GetFormDataParameterSubSchema() != nil {
+			continue
+		}
+		parameter := c.parameter(pair.Value.GetNonBodyParameter(), pointer)
+		if parameter == nil {
+			continue
+		}
+		if components.Parameters == nil {
+			components.Parameters = &openapi3.ParametersOrReferences{}
+		}
+		components.Parameters.AdditionalProperties = append(components.Parameters.AdditionalProperties, &openapi3.NamedParameterOrReference{
+			Name:  pair.Name,
+			Value: &openapi3.ParameterOrReference{Oneof: &openapi3.ParameterOrReference_Parameter{Parameter: parameter}},
+		})
+	}
+	for _, pair := range c.source.GetResponses().GetAdditionalProperties() {
+		if components.Responses == nil {
+			components.Responses = &openapi3.ResponsesOrReferences{}
+		}
+		components.Responses.AdditionalProperties = append(components.Responses.AdditionalProperties, &openapi3.NamedResponseOrReference{
+			Name: pair.Name,
+			Value: &openapi3.ResponseOrReference{
+				Oneof: &openapi3.ResponseOrReference_Response{Response: c.response(pair.Value, c.source.Produces, "#/responses/"+pair.Name)},
+			},
+		})
+	}
+	for _, pair := range c.source.GetSecurityDefinitions().GetAdditionalProperties() {
+		if components.SecuritySchemes == nil {
+			components.SecuritySchemes = &openapi3.SecuritySchemesOrReferences{}
+		}
+		components.SecuritySchemes.AdditionalProperties = append(components.SecuritySchemes.AdditionalProperties, &openapi3.NamedSecuritySchemeOrReference{
+			Name: pair.Name,
+			Value: &openapi3.SecuritySchemeOrReference{
+				Oneof: &openapi3.SecuritySchemeOrReference_SecurityScheme{SecurityScheme: securityScheme(pair.Value)},
+			},
+		})
+	}
+	return components
+}
+
+// securityScheme converts a security definition. OAuth2 flows are renamed.
+func securityScheme(item *openapi2.SecurityDefinitionsItem) *openapi3.SecurityScheme {
+	var m protoreflect.Message
+	switch s := item.GetOneof().(type) {
+	case *openapi2.SecurityDefinitionsItem_BasicAuthenticationSecurity:
+		m = s.BasicAuthenticationSecurity.ProtoReflect()
+	case *openapi2.SecurityDefinitionsItem_ApiKeySecurity:
+		m = s.ApiKeySecurity.ProtoReflect()
+	case *openapi2.SecurityDefinitionsItem_Oauth2ImplicitSecurity:
+		m = s.Oauth2ImplicitSecurity.ProtoReflect()
+	case *openapi2.SecurityDefinitionsItem_Oauth2PasswordSecurity:
+		m = s.Oauth2PasswordSecurity.ProtoReflect()
+	case *openapi2.SecurityDefinitionsItem_Oauth2ApplicationSecurity:
+		m = s.Oauth2ApplicationSecurity.ProtoReflect()
+	case *openapi2.SecurityDefinitionsItem_Oauth2AccessCodeSecurity:
+		m = s.Oauth2AccessCodeSecurity.ProtoReflect()
+	default:
+		return &openapi3.SecurityScheme{}
+	}
+	scheme := &openapi3.SecurityScheme{}
+	copyFields(m, scheme.ProtoReflect())
+	switch scheme.Type {
+	case "basic":
+		scheme.Type, scheme.Scheme = "http", "basic"
+	case "oauth2":
+		flow := &openapi3.OauthFlow{
+			AuthorizationUrl: stringField(m, "authorization_url"),
+			TokenUrl:         stringField(m, "token_url"),
+			Scopes:           &openapi3.Strings{},
+		}
+		if scopes := messageField(m, "scopes"); scopes != nil {
+			for _, scope := range scopes.Interface().(*openapi2.Oauth2Scopes).AdditionalProperties {
+				flow.Scopes.AdditionalProperties = append(flow.Scopes.AdditionalProperties, &openapi3.NamedString{Name: scope.Name, Value: scope.Value})
+			}
+		}
+		scheme.Flows = &openapi3.OauthFlows{}
+		switch stringField(m, "flow") {
+		case "implicit":
+			scheme.Flows.Implicit = flow
+		case "password":
+			scheme.Flows.Password = flow
+		case "application":
+			scheme.Flows.ClientCredentials = flow
+		case "accessCode":
+			scheme.Flows.AuthorizationCode = flow
+		}
+	}
+	return scheme
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	openapi2 "github.com/google/gnostic/openapiv2"
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+func TestOpenAPIv3FromOpenAPIv2(t *testing.T) {
+	bytes, err := ioutil.ReadFile("testdata/parameters.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	source, err := openapi2.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, warnings, err := OpenAPIv3FromOpenAPIv2(source, &ConversionOptions{CookieExtension: "x-cookie-parameter"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	output, err := yaml.Marshal(ConvertedRawInfo(document))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected, err := ioutil.ReadFile("testdata/parameters-v3.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if string(output) != string(expected) {
+		ioutil.WriteFile("testdata/parameters-v3.yaml.out", output, 0644)
+		t.Errorf("converted document differs from testdata/parameters-v3.yaml")
+	} else {
+		os.Remove("testdata/parameters-v3.yaml.out")
+	}
+	// The converted document must be a valid OpenAPI v3 document.
+	if _, err := openapi3.ParseDocument(output); err != nil {
+		t.Errorf("%+v", err)
+	}
+	expectedWarnings := []string{
+		"#/paths/~1items~1{ids}/parameters/0: collectionFormat pipes isn't supported for path parameters, using csv",
+		"#/paths/~1items~1{ids}/get/parameters/1: collectionFormat tsv isn't supported for header parameters, using csv",
+		"#/paths/~1items~1{ids}/get/parameters/2: collectionFormat ssv isn't supported for header parameters, using csv",
+		"#/paths/~1items~1{ids}/get/parameters/3: OpenAPI v3 ignores header parameters named Accept",
+		"#/paths/~1items~1{ids}/get/parameters/5: collectionFormat pipes isn't supported for cookie parameters, using csv",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(expectedWarnings, "\n") {
+		t.Errorf("unexpected warnings:\n%s", strings.Join(warnings, "\n"))
+	}
+}
+
+func TestOpenAPIv3FromOpenAPIv2EnumExtensions(t *testing.T) {
+	bytes, err := ioutil.ReadFile("testdata/enums.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	source, err := openapi2.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, warnings, err := OpenAPIv3FromOpenAPIv2(source, nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	output, err := yaml.Marshal(ConvertedRawInfo(document))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected, err := ioutil.ReadFile("testdata/enums-v3.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if string(output) != string(expected) {
+		ioutil.WriteFile("testdata/enums-v3.yaml.out", output, 0644)
+		t.Errorf("converted document differs from testdata/enums-v3.yaml")
+	} else {
+		os.Remove("testdata/enums-v3.yaml.out")
+	}
+	expectedWarnings := []string{
+		"#/paths/~1orders/get/responses/200/headers/X-Priority: x-enum-descriptions has 1 entries for 2 enum values",
+		"#/definitions/Order/properties/channel: x-enum-varnames isn't a list",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(expectedWarnings, "\n") {
+		t.Errorf("unexpected warnings:\n%s", strings.Join(warnings, "\n"))
+	}
+}
+
+func TestOpenAPIv3FromOpenAPIv2WithoutCookies(t *testing.T) {
+	bytes, err := ioutil.ReadFile("testdata/parameters.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	source, err := openapi2.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, _, err := OpenAPIv3FromOpenAPIv2(source, nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// Without a cookie extension, marked parameters remain header parameters.
+	for _, p := range document.Paths.Path[0].Value.Get.Parameters {
+		if p.GetParameter().GetIn() == "cookie" {
+			t.Errorf("unexpected cookie parameter %s", p.GetParameter().GetName())
+		}
+	}
+}
+
+func TestOpenAPIv3FromOpenAPIv2CSVQueryArray(t *testing.T) {
+	source, err := openapi2.ParseDocument([]byte(`swagger: "2.0"
+info:
+  title: csv
+  version: "1.0"
+paths:
+  /items:
+    get:
+      parameters:
+        - name: ids
+          in: query
+          type: array
+          collectionFormat: csv
+          items:
+            type: string
+      responses:
+        "200":
+          description: OK
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, _, err := OpenAPIv3FromOpenAPIv2(source, nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	output, err := yaml.Marshal(ConvertedRawInfo(document))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !strings.Contains(string(output), "style: form\n                  explode: false\n") {
+		t.Errorf("csv query array was not written with explode: false:\n%s", output)
+	}
+}
@@ -0,0 +1,851 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	// Register the well-known types that generated files can import.
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+
+	openapiv3 "github.com/google/gnostic/openapiv3"
+)
+
+const (
+	annotationsFile = "google/api/annotations.proto"
+	emptyFile       = "google/protobuf/empty.proto"
+	fieldMaskFile   = "google/protobuf/field_mask.proto"
+	structFile      = "google/protobuf/struct.proto"
+	timestampFile   = "google/protobuf/timestamp.proto"
+)
+
+var pathVariable = regexp.MustCompile(`{([^{}]+)}`)
+
+// A protoGenerator builds a proto3 file from an OpenAPI v3 document.
+type protoGenerator struct {
+	document *openapiv3.Document
+	file     *descriptorpb.FileDescriptorProto
+	prefix   string // prefix of the full names of local types, ".package."
+
+	schemas map[string]*openapiv3.SchemaOrReference // component schemas by name
+	types   map[string]string                       // full type names of component schemas
+	enums   map[string]bool                         // component schemas that are enums
+	names   map[string]bool                         // names of top-level types and services
+	values  map[string]bool                         // names of top-level enum values
+	imports map[string]bool
+
+	// Comments for the proto source, keyed by descriptors.
+	comments map[proto.Message]string
+}
+
+// ProtoFromOpenAPIv3 converts an OpenAPI v3 document into a proto3 file with the given
+// name and package. It returns the proto source of the file and a
+// FileDescriptorSet that contains the file and all of its dependencies.
+func ProtoFromOpenAPIv3(document *openapiv3.Document, fileName, packageName string) ([]byte, *descriptorpb.FileDescriptorSet, error) {
+	g := newProtoGenerator(document, fileName, packageName)
+	file, err := g.generateFile()
+	if err != nil {
+		return nil, nil, err
+	}
+	set, err := fileDescriptorSet(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return g.printFile(document.GetInfo().GetTitle()), set, nil
+}
+
+// ProtoPackageName returns a valid proto package name for a name, such as the
+// title of an API, or "" if the name contains no letters.
+func ProtoPackageName(name string) string {
+	var parts []string
+	for _, part := range strings.Split(name, ".") {
+		part = strings.ToLower(strings.Join(words(part), "_"))
+		if part != "" && isLetter(rune(part[0])) {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// newProtoGenerator returns a generator for a file with the given name and package.
+func newProtoGenerator(document *openapiv3.Document, fileName, packageName string) *protoGenerator {
+	return &protoGenerator{
+		document: document,
+		file: &descriptorpb.FileDescriptorProto{
+			Name:    proto.String(fileName),
+			Package: proto.String(packageName),
+			Syntax:  proto.String("proto3"),
+		},
+		prefix:   "." + packageName + ".",
+		schemas:  make(map[string]*openapiv3.SchemaOrReference),
+		types:    make(map[string]string),
+		enums:    make(map[string]bool),
+		names:    make(map[string]bool),
+		values:   make(map[string]bool),
+		imports:  make(map[string]bool),
+		comments: make(map[proto.Message]string),
+	}
+}
+
+// generateFile converts the schemas of a document into messages and enums
+// and its operations into services. The result is validated by building
+// a protoreflect.FileDescriptor from it.
+func (g *protoGenerator) generateFile() (*descriptorpb.FileDescriptorProto, error) {
+	pairs := g.document.GetComponents().GetSchemas().GetAdditionalProperties()
+	// Names are assigned before types are built so that references
+	// can be resolved in any order.
+	for _, pair := range pairs {
+		g.schemas[pair.Name] = pair.Value
+		g.types[pair.Name] = g.prefix + g.uniqueName(typeName(pair.Name))
+		g.enums[pair.Name] = isEnum(pair.Value.GetSchema())
+	}
+	for _, pair := range pairs {
+		name := strings.TrimPrefix(g.types[pair.Name], g.prefix)
+		if reference := pair.Value.GetReference(); reference != nil {
+			// Aliases become messages with a single value field.
+			message := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+			g.addField(message, g.types[pair.Name], "value", pair.Value)
+			g.file.MessageType = append(g.file.MessageType, message)
+			continue
+		}
+		schema := pair.Value.GetSchema()
+		if g.enums[pair.Name] {
+			enum := g.buildEnum(name, schema, g.values)
+			g.file.EnumType = append(g.file.EnumType, enum)
+			continue
+		}
+		message := g.buildMessage(name, g.types[pair.Name], schema)
+		g.file.MessageType = append(g.file.MessageType, message)
+	}
+	g.buildServices()
+
+	for name := range g.imports {
+		g.file.Dependency = append(g.file.Dependency, name)
+	}
+	sort.Strings(g.file.Dependency)
+	if _, err := protodesc.NewFile(g.file, protoregistry.GlobalFiles); err != nil {
+		return nil, err
+	}
+	return g.file, nil
+}
+
+// fileDescriptorSet returns a set with a file and all of its dependencies,
+// ordered so that files follow their dependencies.
+func fileDescriptorSet(file *descriptorpb.FileDescriptorProto) (*descriptorpb.FileDescriptorSet, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	added := make(map[string]bool)
+	var add func(name string) error
+	add = func(name string) error {
+		if added[name] {
+			return nil
+		}
+		added[name] = true
+		d, err := protoregistry.GlobalFiles.FindFileByPath(name)
+		if err != nil {
+			return err
+		}
+		imports := d.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			if err := add(imports.Get(i).Path()); err != nil {
+				return err
+			}
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(d))
+		return nil
+	}
+	for _, name := range file.Dependency {
+		if err := add(name); err != nil {
+			return nil, err
+		}
+	}
+	set.File = append(set.File, file)
+	return set, nil
+}
+
+// uniqueName returns a name that isn't used by other top-level types.
+func (g *protoGenerator) uniqueName(name string) string {
+	return uniqueName(name, "", g.names)
+}
+
+// buildMessage builds a message from an object schema.
+func (g *protoGenerator) buildMessage(name, fullName string, schema *openapiv3.Schema) *descriptorpb.DescriptorProto {
+	message := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+	g.comments[message] = schema.Description
+	if members := refMembers(schema.OneOf); members != nil && len(g.properties(schema)) == 0 {
+		// A union of messages becomes a oneof.
+		g.addOneof(message, snakeName(name), members)
+		return message
+	}
+	for _, pair := range g.properties(schema) {
+		g.addField(message, fullName, pair.Name, pair.Value)
+	}
+	if len(message.Field) == 0 && !isObject(schema) {
+		// Other schemas become messages with a single value field.
+		g.addField(message, fullName, "value", &openapiv3.SchemaOrReference{
+			Oneof: &openapiv3.SchemaOrReference_Schema{Schema: schema},
+		})
+	}
+	return message
+}
+
+// properties returns the properties of a schema, including the properties
+// of the members of allOf combinators.
+func (g *protoGenerator) properties(schema *openapiv3.Schema) []*openapiv3.NamedSchemaOrReference {
+	var properties []*openapiv3.NamedSchemaOrReference
+	for _, member := range schema.AllOf {
+		if s := g.resolve(member); s != nil {
+			properties = append(properties, g.properties(s)...)
+		}
+	}
+	return append(properties, schema.GetProperties().GetAdditionalProperties()...)
+}
+
+// resolve returns the schema of a component schema reference or of an
+// inline schema.
+func (g *protoGenerator) resolve(s *openapiv3.SchemaOrReference) *openapiv3.Schema {
+	seen := make(map[string]bool)
+	for s != nil {
+		if schema := s.GetSchema(); schema != nil {
+			return schema
+		}
+		name := schemaName(s.GetReference().GetXRef())
+		if seen[name] {
+			return nil
+		}
+		seen[name] = true
+		s = g.schemas[name]
+	}
+	return nil
+}
+
+// addOneof adds a oneof with a field for each of a list of references.
+func (g *protoGenerator) addOneof(message *descriptorpb.DescriptorProto, name string, members []string) {
+	index := int32(len(message.OneofDecl))
+	message.OneofDecl = append(message.OneofDecl, &descriptorpb.OneofDescriptorProto{
+		Name: proto.String(uniqueName(name, "_", fieldNames(message))),
+	})
+	for _, member := range members {
+		field := g.newField(message, snakeName(member))
+		field.OneofIndex = proto.Int32(index)
+		g.setType(field, member)
+	}
+}
+
+// newField adds a field with a unique name and the next number to a message.
+func (g *protoGenerator) newField(message *descriptorpb.DescriptorProto, name string) *descriptorpb.FieldDescriptorProto {
+	name = uniqueName(name, "_", fieldNames(message))
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(int32(len(message.Field) + 1)),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		JsonName: proto.String(jsonName(name)),
+	}
+	message.Field = append(message.Field, field)
+	return field
+}
+
+// setType sets the type of a field to the type of a component schema.
+func (g *protoGenerator) setType(field *descriptorpb.FieldDescriptorProto, name string) {
+	field.TypeName = proto.String(g.types[name])
+	if g.enums[name] {
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+	} else {
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	}
+}
+
+// addField adds a field for a property to a message. Inline objects and
+// enums become nested types of the message and maps become map fields.
+func (g *protoGenerator) addField(message *descriptorpb.DescriptorProto, fullName, property string, s *openapiv3.SchemaOrReference) *descriptorpb.FieldDescriptorProto {
+	schema := s.GetSchema()
+	if members := refMembers(schema.GetOneOf()); members != nil {
+		g.addOneof(message, snakeName(property), members)
+		return nil
+	}
+	field := g.newField(message, snakeName(property))
+	field.JsonName = proto.String(property)
+	if schema != nil {
+		g.comments[field] = schema.Description
+	}
+	if schema.GetType() == "array" {
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		var items *openapiv3.SchemaOrReference
+		if list := schema.GetItems().GetSchemaOrReference(); len(list) > 0 {
+			items = list[0]
+		}
+		if items.GetSchema().GetType() == "array" {
+			// Lists of lists can't be repeated fields.
+			g.setWellKnownType(field, "ListValue", structFile)
+			return field
+		}
+		g.setFieldType(message, fullName, property, field, items)
+		return field
+	}
+	if isMap(schema) {
+		// protoc requires map entries to be named after their fields.
+		entryName := jsonName(field.GetName())
+		entryName = strings.ToUpper(entryName[:1]) + entryName[1:] + "Entry"
+		entry := &descriptorpb.DescriptorProto{
+			Name:    proto.String(entryName),
+			Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+		}
+		key := g.newField(entry, "key")
+		key.Type = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+		value := g.newField(entry, "value")
+		g.setFieldType(message, fullName, property, value, schema.AdditionalProperties.GetSchemaOrReference())
+		message.NestedType = append(message.NestedType, entry)
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+		field.TypeName = proto.String(fullName + "." + entryName)
+		return field
+	}
+	g.setFieldType(message, fullName, property, field, s)
+	return field
+}
+
+// setFieldType sets the type of a field that isn't repeated.
+func (g *protoGenerator) setFieldType(message *descriptorpb.DescriptorProto, fullName, property string, field *descriptorpb.FieldDescriptorProto, s *openapiv3.SchemaOrReference) {
+	if reference := s.GetReference(); reference != nil {
+		if name := schemaName(reference.XRef); g.types[name] != "" {
+			g.setType(field, name)
+		} else {
+			g.setWellKnownType(field, "Value", structFile)
+		}
+		return
+	}
+	schema := s.GetSchema()
+	if schema == nil {
+		g.setWellKnownType(field, "Value", structFile)
+		return
+	}
+	if len(schema.AllOf) == 1 && len(schema.GetProperties().GetAdditionalProperties()) == 0 && schema.AllOf[0].GetReference() != nil {
+		// allOf is often used to add a description to a reference.
+		g.setFieldType(message, fullName, property, field, schema.AllOf[0])
+		return
+	}
+	if isEnum(schema) {
+		name := uniqueName(typeName(property), "", nestedNames(message))
+		// Values of nested enums share a scope with the fields of the message.
+		message.EnumType = append(message.EnumType, g.buildEnum(name, schema, fieldNames(message)))
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+		field.TypeName = proto.String(fullName + "." + name)
+		return
+	}
+	if len(g.properties(schema)) > 0 || refMembers(schema.OneOf) != nil {
+		name := uniqueName(typeName(property), "", nestedNames(message))
+		message.NestedType = append(message.NestedType, g.buildMessage(name, fullName+"."+name, schema))
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+		field.TypeName = proto.String(fullName + "." + name)
+		return
+	}
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "byte", "bytes", "binary":
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_BYTES.Enum()
+		case "int64":
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()
+		case "uint64":
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_UINT64.Enum()
+		case "date-time":
+			g.setWellKnownType(field, "Timestamp", timestampFile)
+		case "field-mask":
+			g.setWellKnownType(field, "FieldMask", fieldMaskFile)
+		default:
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+		}
+	case "integer":
+		switch schema.Format {
+		case "int64":
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()
+		case "uint32":
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_UINT32.Enum()
+		case "uint64":
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_UINT64.Enum()
+		default:
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()
+		}
+	case "number":
+		if schema.Format == "float" {
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_FLOAT.Enum()
+		} else {
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_DOUBLE.Enum()
+		}
+	case "boolean":
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum()
+	case "object":
+		g.setWellKnownType(field, "Struct", structFile)
+	default:
+		g.setWellKnownType(field, "Value", structFile)
+	}
+}
+
+// setWellKnownType sets the type of a field to a message defined in a file
+// of the google.protobuf package.
+func (g *protoGenerator) setWellKnownType(field *descriptorpb.FieldDescriptorProto, name, file string) {
+	g.imports[file] = true
+	field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	field.TypeName = proto.String(".google.protobuf." + name)
+}
+
+// buildEnum builds an enum from a string schema with enum values. Values
+// that look like proto enum values, with an UNSPECIFIED zero value, are
+// kept. Other values are prefixed with the name of the enum and follow
+// a zero value named <ENUM>_UNSPECIFIED. scope holds the names of enum
+// values that are already used in the scope of the enum.
+func (g *protoGenerator) buildEnum(name string, schema *openapiv3.Schema, scope map[string]bool) *descriptorpb.EnumDescriptorProto {
+	enum := &descriptorpb.EnumDescriptorProto{Name: proto.String(name)}
+	g.comments[enum] = schema.Description
+	var values []string
+	for _, value := range schema.Enum {
+		values = append(values, strings.TrimSpace(value.Yaml))
+	}
+	prefix := upperSnakeName(name) + "_"
+	keep := len(values) > 0 && strings.HasSuffix(values[0], "UNSPECIFIED")
+	for _, value := range values {
+		keep = keep && isProtoIdentifier(value) && value == strings.ToUpper(value) && !scope[value]
+	}
+	if !keep {
+		names := []string{prefix + "UNSPECIFIED"}
+		for _, value := range values {
+			names = append(names, prefix+upperSnakeName(value))
+		}
+		values = names
+	}
+	for i, value := range values {
+		enum.Value = append(enum.Value, &descriptorpb.EnumValueDescriptorProto{
+			Name:   proto.String(uniqueName(value, "_", scope)),
+			Number: proto.Int32(int32(i)),
+		})
+	}
+	return enum
+}
+
+// buildServices adds a service for each tag of the operations of the
+// document. Operations without tags are added to a service that is named
+// after the title of the document.
+func (g *protoGenerator) buildServices() {
+	services := make(map[string]*descriptorpb.ServiceDescriptorProto)
+	methods := make(map[string]map[string]bool)
+	for _, pair := range g.document.GetPaths().GetPath() {
+		for _, operation := range operations(pair.Value) {
+			tag := g.document.GetInfo().GetTitle()
+			if len(operation.operation.Tags) > 0 {
+				tag = operation.operation.Tags[0]
+			}
+			service := services[tag]
+			if service == nil {
+				service = &descriptorpb.ServiceDescriptorProto{Name: proto.String(g.uniqueName(typeName(tag)))}
+				services[tag] = service
+				methods[tag] = make(map[string]bool)
+				g.file.Service = append(g.file.Service, service)
+			}
+			name := operation.operation.OperationId
+			name = strings.TrimPrefix(name, tag+"_")
+			if name == "" {
+				name = operation.method + " " + pair.Name
+			}
+			name = uniqueName(typeName(name), "", methods[tag])
+			service.Method = append(service.Method, g.buildMethod(name, pair, operation))
+		}
+	}
+}
+
+// buildMethod builds a method for an operation with an HTTP rule that
+// binds its path parameters, query parameters, and request body.
+func (g *protoGenerator) buildMethod(name string, pair *openapiv3.NamedPathItem, operation namedOperation) *descriptorpb.MethodDescriptorProto {
+	method := &descriptorpb.MethodDescriptorProto{Name: proto.String(name)}
+	g.comments[method] = operation.operation.Description
+	rule := &annotations.HttpRule{}
+
+	var parameters []*openapiv3.Parameter
+	for _, p := range append(append([]*openapiv3.ParameterOrReference{}, pair.Value.Parameters...), operation.operation.Parameters...) {
+		parameter := p.GetParameter()
+		if reference := p.GetReference(); reference != nil {
+			parameter = g.parameterForRef(reference.XRef)
+		}
+		if parameter != nil && (parameter.In == "path" || parameter.In == "query") {
+			parameters = append(parameters, parameter)
+		}
+	}
+	// Variables of the path template that aren't described by parameters
+	// are bound to string fields.
+	for _, match := range pathVariable.FindAllStringSubmatch(pair.Name, -1) {
+		declared := false
+		for _, parameter := range parameters {
+			declared = declared || (parameter.In == "path" && parameter.Name == match[1])
+		}
+		if !declared {
+			parameters = append(parameters, &openapiv3.Parameter{
+				Name:   match[1],
+				In:     "path",
+				Schema: &openapiv3.SchemaOrReference{Oneof: &openapiv3.SchemaOrReference_Schema{Schema: &openapiv3.Schema{Type: "string"}}},
+			})
+		}
+	}
+	body := g.requestBodySchema(operation.operation.RequestBody)
+
+	// The body schema is used as the request if it has a field for each parameter.
+	var input string
+	if name := schemaName(body.GetReference().GetXRef()); name != "" && g.types[name] != "" && !g.enums[name] {
+		input = g.types[name]
+		properties := make(map[string]bool)
+		if schema := g.resolve(body); schema != nil {
+			for _, property := range g.properties(schema) {
+				properties[property.Name] = true
+			}
+		}
+		for _, parameter := range parameters {
+			if !properties[parameter.Name] {
+				input = ""
+			}
+		}
+		if input != "" {
+			rule.Body = "*"
+		}
+	}
+	path := pair.Name
+	if input == "" {
+		requestName := g.uniqueName(name + "Request")
+		request := &descriptorpb.DescriptorProto{Name: proto.String(requestName)}
+		input = g.prefix + requestName
+		for _, parameter := range parameters {
+			field := g.addField(request, input, parameter.Name, parameter.Schema)
+			g.comments[field] = parameter.Description
+			path = strings.Replace(path, "{"+parameter.Name+"}", "{"+field.GetName()+"}", 1)
+		}
+		if body != nil {
+			bodyName := "body"
+			if name := schemaName(body.GetReference().GetXRef()); g.types[name] != "" {
+				bodyName = snakeName(name)
+			}
+			if field := g.addField(request, input, bodyName, body); field != nil {
+				rule.Body = field.GetName()
+			} else {
+				rule.Body = "*"
+			}
+		}
+		g.file.MessageType = append(g.file.MessageType, request)
+	}
+	method.InputType = proto.String(input)
+	method.OutputType = proto.String(g.responseType(name, operation.operation.Responses, rule))
+
+	switch operation.method {
+	case "GET":
+		rule.Pattern = &annotations.HttpRule_Get{Get: path}
+	case "PUT":
+		rule.Pattern = &annotations.HttpRule_Put{Put: path}
+	case "POST":
+		rule.Pattern = &annotations.HttpRule_Post{Post: path}
+	case "DELETE":
+		rule.Pattern = &annotations.HttpRule_Delete{Delete: path}
+	case "PATCH":
+		rule.Pattern = &annotations.HttpRule_Patch{Patch: path}
+	default:
+		rule.Pattern = &annotations.HttpRule_Custom{Custom: &annotations.CustomHttpPattern{Kind: operation.method, Path: path}}
+	}
+	method.Options = &descriptorpb.MethodOptions{}
+	proto.SetExtension(method.Options, annotations.E_Http, rule)
+	g.imports[annotationsFile] = true
+	return method
+}
+
+// responseType returns the type of the response of a method. It is the
+// message of the schema of the first successful response, a new message
+// for inline schemas, or google.protobuf.Empty for responses without content.
+func (g *protoGenerator) responseType(name string, responses *openapiv3.Responses, rule *annotations.HttpRule) string {
+	var response *openapiv3.Response
+	for _, pair := range responses.GetResponseOrReference() {
+		if strings.HasPrefix(pair.Name, "2") {
+			response = pair.Value.GetResponse()
+			if reference := pair.Value.GetReference(); reference != nil {
+				response = g.responseForRef(reference.XRef)
+			}
+			break
+		}
+	}
+	s := jsonSchema(response.GetContent())
+	if s == nil {
+		g.imports[emptyFile] = true
+		return ".google.protobuf.Empty"
+	}
+	if name := schemaName(s.GetReference().GetXRef()); g.types[name] != "" && !g.enums[name] {
+		return g.types[name]
+	}
+	responseName := g.uniqueName(name + "Response")
+	var message *descriptorpb.DescriptorProto
+	if schema := s.GetSchema(); schema != nil && (len(g.properties(schema)) > 0 || refMembers(schema.OneOf) != nil) {
+		message = g.buildMessage(responseName, g.prefix+responseName, schema)
+	} else {
+		// Other responses are the value field of a message.
+		message = &descriptorpb.DescriptorProto{Name: proto.String(responseName)}
+		if field := g.addField(message, g.prefix+responseName, "value", s); field != nil {
+			rule.ResponseBody = field.GetName()
+		}
+	}
+	g.file.MessageType = append(g.file.MessageType, message)
+	return g.prefix + responseName
+}
+
+func (g *protoGenerator) parameterForRef(ref string) *openapiv3.Parameter {
+	for _, pair := range g.document.GetComponents().GetParameters().GetAdditionalProperties() {
+		if "#/components/parameters/"+pair.Name == ref {
+			return pair.Value.GetParameter()
+		}
+	}
+	return nil
+}
+
+func (g *protoGenerator) responseForRef(ref string) *openapiv3.Response {
+	for _, pair := range g.document.GetComponents().GetResponses().GetAdditionalProperties() {
+		if "#/components/responses/"+pair.Name == ref {
+			return pair.Value.GetResponse()
+		}
+	}
+	return nil
+}
+
+// requestBodySchema returns the schema of the JSON content of a request body.
+func (g *protoGenerator) requestBodySchema(body *openapiv3.RequestBodyOrReference) *openapiv3.SchemaOrReference {
+	requestBody := body.GetRequestBody()
+	if reference := body.GetReference(); reference != nil {
+		for _, pair := range g.document.GetComponents().GetRequestBodies().GetAdditionalProperties() {
+			if "#/components/requestBodies/"+pair.Name == reference.XRef {
+				requestBody = pair.Value.GetRequestBody()
+			}
+		}
+	}
+	return jsonSchema(requestBody.GetContent())
+}
+
+// jsonSchema returns the schema of the JSON content of a request or
+// response, or of its first content if none is JSON.
+func jsonSchema(content *openapiv3.MediaTypes) *openapiv3.SchemaOrReference {
+	pairs := content.GetAdditionalProperties()
+	for _, pair := range pairs {
+		if strings.HasPrefix(pair.Name, "application/json") {
+			return pair.Value.GetSchema()
+		}
+	}
+	if len(pairs) > 0 {
+		return pairs[0].Value.GetSchema()
+	}
+	return nil
+}
+
+type namedOperation struct {
+	method    string
+	operation *openapiv3.Operation
+}
+
+// operations returns the operations of a path item.
+func operations(pathItem *openapiv3.PathItem) []namedOperation {
+	var result []namedOperation
+	for _, o := range []namedOperation{
+		{"GET", pathItem.GetGet()}, {"PUT", pathItem.GetPut()}, {"POST", pathItem.GetPost()},
+		{"DELETE", pathItem.GetDelete()}, {"OPTIONS", pathItem.GetOptions()}, {"HEAD", pathItem.GetHead()},
+		{"PATCH", pathItem.GetPatch()}, {"TRACE", pathItem.GetTrace()},
+	} {
+		if o.operation != nil {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+// refMembers returns the names of the component schemas of a oneOf
+// combinator, or nil if any member isn't a component schema reference.
+func refMembers(oneOf []*openapiv3.SchemaOrReference) []string {
+	var names []string
+	for _, member := range oneOf {
+		name := schemaName(member.GetReference().GetXRef())
+		if name == "" {
+			return nil
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// schemaName returns the name of the component schema of a reference.
+func schemaName(ref string) string {
+	if !strings.HasPrefix(ref, "#/components/schemas/") {
+		return ""
+	}
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+func isEnum(schema *openapiv3.Schema) bool {
+	return schema.GetType() == "string" && len(schema.GetEnum()) > 0
+}
+
+func isObject(schema *openapiv3.Schema) bool {
+	return schema.GetType() == "object" || len(schema.GetAllOf()) > 0
+}
+
+// isMap returns true if a schema describes a map with string keys.
+func isMap(schema *openapiv3.Schema) bool {
+	return schema.GetType() == "object" && len(schema.GetProperties().GetAdditionalProperties()) == 0 &&
+		schema.GetAdditionalProperties().GetSchemaOrReference() != nil
+}
+
+func fieldNames(message *descriptorpb.DescriptorProto) map[string]bool {
+	names := make(map[string]bool)
+	for _, field := range message.Field {
+		names[field.GetName()] = true
+	}
+	for _, oneof := range message.OneofDecl {
+		names[oneof.GetName()] = true
+	}
+	for _, enum := range message.EnumType {
+		for _, value := range enum.Value {
+			names[value.GetName()] = true
+		}
+	}
+	return names
+}
+
+func nestedNames(message *descriptorpb.DescriptorProto) map[string]bool {
+	names := make(map[string]bool)
+	for _, nested := range message.NestedType {
+		names[nested.GetName()] = true
+	}
+	for _, enum := range message.EnumType {
+		names[enum.GetName()] = true
+	}
+	return names
+}
+
+// uniqueName returns name, or name followed by a separator and a number
+// if name is used, and marks the result as used.
+func uniqueName(name, separator string, used map[string]bool) string {
+	result := name
+	for i := 2; used[result]; i++ {
+		result = fmt.Sprintf("%s%s%d", name, separator, i)
+	}
+	used[result] = true
+	return result
+}
+
+// words splits a name into words at characters that aren't letters or
+// digits and where lower case letters or digits are followed by upper case letters.
+func words(name string) []string {
+	var result []string
+	var word []rune
+	previous := rune(0)
+	for _, r := range name {
+		switch {
+		case !isLetter(r) && !isDigit(r):
+			if len(word) > 0 {
+				result = append(result, string(word))
+			}
+			word = nil
+		case isUpper(r) && (isLower(previous) || isDigit(previous)):
+			if len(word) > 0 {
+				result = append(result, string(word))
+			}
+			word = []rune{r}
+		default:
+			word = append(word, r)
+		}
+		previous = r
+	}
+	if len(word) > 0 {
+		result = append(result, string(word))
+	}
+	return result
+}
+
+// typeName returns a name for a message, enum, service, or method.
+// Underscores are kept so that names like Message_SubMessage, which
+// protoc-gen-openapi uses for nested messages, survive a round trip.
+func typeName(name string) string {
+	var result []string
+	for _, part := range strings.Split(name, "_") {
+		var word string
+		for _, w := range words(part) {
+			word += strings.ToUpper(w[:1]) + w[1:]
+		}
+		if word != "" {
+			result = append(result, word)
+		}
+	}
+	s := strings.Join(result, "_")
+	if s == "" || isDigit(rune(s[0])) {
+		s = "X" + s
+	}
+	return s
+}
+
+// snakeName returns a name for a field.
+func snakeName(name string) string {
+	s := strings.ToLower(strings.Join(words(name), "_"))
+	if s == "" || isDigit(rune(s[0])) {
+		s = "field_" + s
+	}
+	return s
+}
+
+// upperSnakeName returns a name for an enum value.
+func upperSnakeName(name string) string {
+	s := strings.ToUpper(strings.Join(words(name), "_"))
+	if s == "" || isDigit(rune(s[0])) {
+		s = "VALUE_" + s
+	}
+	return s
+}
+
+// jsonName returns the default JSON name of a field, as computed by protoc.
+func jsonName(name string) string {
+	var result []rune
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+		} else if upper {
+			result = append(result, []rune(strings.ToUpper(string(r)))...)
+			upper = false
+		} else {
+			result = append(result, r)
+		}
+	}
+	return string(result)
+}
+
+func isProtoIdentifier(name string) bool {
+	for i, r := range name {
+		if !isLetter(r) && !(i > 0 && (isDigit(r) || r == '_')) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+func isLetter(r rune) bool { return isUpper(r) || isLower(r) }
+func isUpper(r rune) bool  { return r >= 'A' && r <= 'Z' }
+func isLower(r rune) bool  { return r >= 'a' && r <= 'z' }
+func isDigit(r rune) bool  { return r >= '0' && r <= '9' }
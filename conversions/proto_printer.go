@@ -0,0 +1,241 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// A printer writes the source of a proto file.
+type printer struct {
+	strings.Builder
+	prefix   string
+	comments map[proto.Message]string
+	indent   string
+}
+
+// printFile returns the proto source of a file built by a generator.
+func (g *protoGenerator) printFile(title string) []byte {
+	p := &printer{prefix: g.prefix, comments: g.comments}
+	file := g.file
+	p.line("// Code generated by gnostic-proto. DO NOT EDIT.")
+	if title != "" {
+		p.line("// Source: " + title)
+	}
+	p.line("")
+	p.line(`syntax = "proto3";`)
+	p.line("")
+	p.line("package " + file.GetPackage() + ";")
+	if len(file.Dependency) > 0 {
+		p.line("")
+		for _, dependency := range file.Dependency {
+			p.line("import " + strconv.Quote(dependency) + ";")
+		}
+	}
+	for _, service := range file.Service {
+		p.line("")
+		p.printService(service)
+	}
+	for _, message := range file.MessageType {
+		p.line("")
+		p.printMessage(message)
+	}
+	for _, enum := range file.EnumType {
+		p.line("")
+		p.printEnum(enum)
+	}
+	return []byte(p.String())
+}
+
+func (p *printer) line(s string) {
+	if s == "" {
+		p.WriteString("\n")
+		return
+	}
+	p.WriteString(p.indent + s + "\n")
+}
+
+func (p *printer) printComment(m proto.Message) {
+	comment := strings.TrimSpace(p.comments[m])
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		p.line(strings.TrimRight("// "+line, " "))
+	}
+}
+
+func (p *printer) nest(f func()) {
+	p.indent += "  "
+	f()
+	p.indent = p.indent[2:]
+}
+
+func (p *printer) printService(service *descriptorpb.ServiceDescriptorProto) {
+	p.line("service " + service.GetName() + " {")
+	p.nest(func() {
+		for i, method := range service.Method {
+			if i > 0 {
+				p.line("")
+			}
+			p.printComment(method)
+			p.line(fmt.Sprintf("rpc %s(%s) returns (%s) {", method.GetName(), p.typeName(method.GetInputType()), p.typeName(method.GetOutputType())))
+			p.nest(func() {
+				rule := proto.GetExtension(method.Options, annotations.E_Http).(*annotations.HttpRule)
+				p.line("option (google.api.http) = {")
+				p.nest(func() {
+					switch pattern := rule.Pattern.(type) {
+					case *annotations.HttpRule_Get:
+						p.line("get: " + strconv.Quote(pattern.Get))
+					case *annotations.HttpRule_Put:
+						p.line("put: " + strconv.Quote(pattern.Put))
+					case *annotations.HttpRule_Post:
+						p.line("post: " + strconv.Quote(pattern.Post))
+					case *annotations.HttpRule_Delete:
+						p.line("delete: " + strconv.Quote(pattern.Delete))
+					case *annotations.HttpRule_Patch:
+						p.line("patch: " + strconv.Quote(pattern.Patch))
+					case *annotations.HttpRule_Custom:
+						p.line(fmt.Sprintf("custom: {kind: %s path: %s}", strconv.Quote(pattern.Custom.Kind), strconv.Quote(pattern.Custom.Path)))
+					}
+					if rule.Body != "" {
+						p.line("body: " + strconv.Quote(rule.Body))
+					}
+					if rule.ResponseBody != "" {
+						p.line("response_body: " + strconv.Quote(rule.ResponseBody))
+					}
+				})
+				p.line("};")
+			})
+			p.line("}")
+		}
+	})
+	p.line("}")
+}
+
+func (p *printer) printMessage(message *descriptorpb.DescriptorProto) {
+	p.printComment(message)
+	if len(message.Field) == 0 && len(message.NestedType) == 0 && len(message.EnumType) == 0 {
+		p.line("message " + message.GetName() + " {}")
+		return
+	}
+	p.line("message " + message.GetName() + " {")
+	p.nest(func() {
+		// Blocks and commented fields are separated by blank lines.
+		first, block := true, false
+		separate := func(b bool) {
+			if !first && (b || block) {
+				p.line("")
+			}
+			first, block = false, b
+		}
+		for _, nested := range message.NestedType {
+			if nested.GetOptions().GetMapEntry() {
+				continue
+			}
+			separate(true)
+			p.printMessage(nested)
+		}
+		for _, enum := range message.EnumType {
+			separate(true)
+			p.printEnum(enum)
+		}
+		printed := make(map[int32]bool)
+		for _, field := range message.Field {
+			if field.OneofIndex == nil {
+				separate(strings.TrimSpace(p.comments[field]) != "")
+				p.printField(message, field)
+				continue
+			}
+			index := field.GetOneofIndex()
+			if printed[index] {
+				continue
+			}
+			printed[index] = true
+			separate(true)
+			p.line("oneof " + message.OneofDecl[index].GetName() + " {")
+			p.nest(func() {
+				for _, member := range message.Field {
+					if member.OneofIndex != nil && member.GetOneofIndex() == index {
+						p.printField(message, member)
+					}
+				}
+			})
+			p.line("}")
+		}
+	})
+	p.line("}")
+}
+
+func (p *printer) printField(message *descriptorpb.DescriptorProto, field *descriptorpb.FieldDescriptorProto) {
+	p.printComment(field)
+	var t string
+	if entry := mapEntry(message, field); entry != nil {
+		t = fmt.Sprintf("map<%s, %s>", p.fieldType(entry.Field[0]), p.fieldType(entry.Field[1]))
+	} else {
+		t = p.fieldType(field)
+		if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+			t = "repeated " + t
+		}
+	}
+	s := fmt.Sprintf("%s %s = %d", t, field.GetName(), field.GetNumber())
+	if field.GetJsonName() != jsonName(field.GetName()) {
+		s += " [json_name = " + strconv.Quote(field.GetJsonName()) + "]"
+	}
+	p.line(s + ";")
+}
+
+// mapEntry returns the map entry type of a field, or nil if the field isn't a map.
+func mapEntry(message *descriptorpb.DescriptorProto, field *descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+	for _, nested := range message.NestedType {
+		if nested.GetOptions().GetMapEntry() && strings.HasSuffix(field.GetTypeName(), "."+nested.GetName()) {
+			return nested
+		}
+	}
+	return nil
+}
+
+func (p *printer) fieldType(field *descriptorpb.FieldDescriptorProto) string {
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		return p.typeName(field.GetTypeName())
+	}
+	return strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+}
+
+// typeName returns the name of a type relative to the package of the file.
+func (p *printer) typeName(name string) string {
+	if strings.HasPrefix(name, p.prefix) {
+		return strings.TrimPrefix(name, p.prefix)
+	}
+	return strings.TrimPrefix(name, ".")
+}
+
+func (p *printer) printEnum(enum *descriptorpb.EnumDescriptorProto) {
+	p.printComment(enum)
+	p.line("enum " + enum.GetName() + " {")
+	p.nest(func() {
+		for _, value := range enum.Value {
+			p.line(fmt.Sprintf("%s = %d;", value.GetName(), value.GetNumber()))
+		}
+	})
+	p.line("}")
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	openapiv3 "github.com/google/gnostic/openapiv3"
+)
+
+// generateProto returns the proto source and FileDescriptorSet for an OpenAPI description.
+func generateProto(t *testing.T, filename, packageName string) ([]byte, []byte) {
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, err := openapiv3.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	g := newProtoGenerator(document, packageName+".proto", packageName)
+	file, err := g.generateFile()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	set, err := fileDescriptorSet(file)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return g.printFile(document.GetInfo().GetTitle()), data
+}
+
+func TestProtoFromOpenAPIv3(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		pkg      string
+		expected string
+	}{
+		{"../examples/v3.0/yaml/bookstore.yaml", "bookstore", "testdata/proto/bookstore.proto"},
+		{"testdata/proto/types.yaml", "types", "testdata/proto/types.proto"},
+	} {
+		source, data := generateProto(t, test.input, test.pkg)
+		expected, err := ioutil.ReadFile(test.expected)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if string(source) != string(expected) {
+			t.Errorf("%s: generated source differs from %s:\n%s", test.input, test.expected, source)
+		}
+		// The descriptor set contains all dependencies of the file.
+		set := &descriptorpb.FileDescriptorSet{}
+		if err := proto.Unmarshal(data, set); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		files, err := protodesc.NewFiles(set)
+		if err != nil {
+			t.Fatalf("%s: %+v", test.input, err)
+		}
+		if _, err := files.FindFileByPath(test.pkg + ".proto"); err != nil {
+			t.Errorf("%s: %+v", test.input, err)
+		}
+	}
+}
+
+// TestProtoRoundTrip converts a proto file to OpenAPI with protoc-gen-openapi,
+// converts the result back to proto and converts that to OpenAPI again.
+// Both OpenAPI descriptions must be identical.
+func TestProtoRoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("protoc"); err != nil {
+		t.Skip("protoc is not installed")
+	}
+	dir, err := ioutil.TempDir("", "gnostic-proto")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(dir)
+	includes, err := filepath.Abs("../cmd/protoc-gen-openapi/examples")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	openapi := func(source, output string) []byte {
+		cmd := exec.Command("protoc", "-I", filepath.Dir(source), "-I", includes, filepath.Base(source),
+			"--openapi_out=Mlibrary.proto=example/library,naming=proto,enum_type=string:"+output)
+		cmd.Dir = filepath.Dir(source)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("protoc failed: %+v\n%s", err, out)
+		}
+		bytes, err := ioutil.ReadFile(filepath.Join(output, "openapi.yaml"))
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return bytes
+	}
+	source, err := filepath.Abs("testdata/proto/library.proto")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	first := openapi(source, dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "first.yaml"), first, 0644); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	generated, _ := generateProto(t, filepath.Join(dir, "first.yaml"), "library")
+	if err := ioutil.WriteFile(filepath.Join(dir, "library.proto"), generated, 0644); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	second := openapi(filepath.Join(dir, "library.proto"), dir)
+	if string(first) != string(second) {
+		t.Errorf("round trip changed the OpenAPI description:\n%s\n%s", first, generated)
+	}
+}
+
+func TestProtoNames(t *testing.T) {
+	for _, test := range []struct {
+		name, typeName, snakeName, upperSnakeName string
+	}{
+		{"shelf", "Shelf", "shelf", "SHELF"},
+		{"pageSize", "PageSize", "page_size", "PAGE_SIZE"},
+		{"Book_Bookmark", "Book_Bookmark", "book_bookmark", "BOOK_BOOKMARK"},
+		{"pet-id", "PetId", "pet_id", "PET_ID"},
+		{"@type", "Type", "type", "TYPE"},
+		{"2nd", "X2nd", "field_2nd", "VALUE_2ND"},
+		{"", "X", "field_", "VALUE_"},
+	} {
+		if got := typeName(test.name); got != test.typeName {
+			t.Errorf("typeName(%q) = %q, want %q", test.name, got, test.typeName)
+		}
+		if got := snakeName(test.name); got != test.snakeName {
+			t.Errorf("snakeName(%q) = %q, want %q", test.name, got, test.snakeName)
+		}
+		if got := upperSnakeName(test.name); got != test.upperSnakeName {
+			t.Errorf("upperSnakeName(%q) = %q, want %q", test.name, got, test.upperSnakeName)
+		}
+	}
+}
@@ -0,0 +1,972 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+// ramlMethods are the methods of RAML resources in the order of the
+// operations of OpenAPI v3 path items.
+var ramlMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// ramlBuiltinTypes are the schemas of the built-in RAML types.
+var ramlBuiltinTypes = map[string]func() *openapi3.Schema{
+	"any":           func() *openapi3.Schema { return &openapi3.Schema{} },
+	"string":        func() *openapi3.Schema { return &openapi3.Schema{Type: "string"} },
+	"number":        func() *openapi3.Schema { return &openapi3.Schema{Type: "number"} },
+	"integer":       func() *openapi3.Schema { return &openapi3.Schema{Type: "integer"} },
+	"boolean":       func() *openapi3.Schema { return &openapi3.Schema{Type: "boolean"} },
+	"object":        func() *openapi3.Schema { return &openapi3.Schema{Type: "object"} },
+	"array":         func() *openapi3.Schema { return &openapi3.Schema{Type: "array"} },
+	"date-only":     func() *openapi3.Schema { return &openapi3.Schema{Type: "string", Format: "date"} },
+	"datetime":      func() *openapi3.Schema { return &openapi3.Schema{Type: "string", Format: "date-time"} },
+	"datetime-only": func() *openapi3.Schema { return &openapi3.Schema{Type: "string"} },
+	"time-only":     func() *openapi3.Schema { return &openapi3.Schema{Type: "string"} },
+	"file":          func() *openapi3.Schema { return &openapi3.Schema{Type: "string", Format: "binary"} },
+	"nil":           func() *openapi3.Schema { return &openapi3.Schema{Nullable: true} },
+}
+
+// OpenAPIv3FromRAML converts a RAML 1.0 API definition to an OpenAPI v3
+// document. Types become component schemas, and the query parameters and
+// headers of traits become component parameters that are referenced by the
+// operations of the methods that use the traits. Resource types and traits
+// with parameters are applied to the resources and methods that use them,
+// since OpenAPI v3.0 has no path item components. It also returns warnings
+// for parts of the definition that aren't converted, such as libraries,
+// security schemes and includes.
+func OpenAPIv3FromRAML(bytes []byte) (*openapi3.Document, []string, error) {
+	header := strings.TrimSpace(strings.SplitN(string(bytes), "\n", 2)[0])
+	switch {
+	case header == "#%RAML 1.0":
+	case strings.HasPrefix(header, "#%RAML 1.0 "):
+		return nil, nil, fmt.Errorf("RAML 1.0 %s fragments aren't supported", strings.TrimPrefix(header, "#%RAML 1.0 "))
+	default:
+		return nil, nil, errors.New("not a RAML 1.0 API definition")
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(bytes, &node); err != nil {
+		return nil, nil, err
+	}
+	root := &node
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, errors.New("RAML API definitions must be maps")
+	}
+	c := &ramlConverter{
+		root:          root,
+		types:         map[string]bool{},
+		traits:        map[string]*yaml.Node{},
+		resourceTypes: map[string]*yaml.Node{},
+		traitRefs:     map[string][]string{},
+	}
+	c.removeIncludes()
+	return c.document(), c.warnings, nil
+}
+
+type ramlConverter struct {
+	root          *yaml.Node
+	mediaTypes    []string
+	types         map[string]bool
+	traits        map[string]*yaml.Node
+	resourceTypes map[string]*yaml.Node
+	// traitRefs are references to the component parameters of each trait.
+	traitRefs  map[string][]string
+	parameters *openapi3.ParametersOrReferences
+	warnings   []string
+}
+
+func (c *ramlConverter) warn(pointer, format string, args ...interface{}) {
+	c.warnings = append(c.warnings, pointer+": "+fmt.Sprintf(format, args...))
+}
+
+// removeIncludes replaces included files with null values, since the
+// files that they name aren't read.
+func (c *ramlConverter) removeIncludes() {
+	compiler.VisitAll(c.root, func(path, key string, value *yaml.Node) {
+		if value.Tag == "!include" {
+			c.warn("#"+path, "!include %s isn't supported", value.Value)
+			*value = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+		}
+	})
+}
+
+// ramlValue returns the value of a key of a map or nil.
+func ramlValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// ramlString returns the value of a key of a map if it is a scalar.
+func ramlString(node *yaml.Node, key string) string {
+	if value := ramlValue(node, key); value != nil && value.Kind == yaml.ScalarNode && value.Tag != "!!null" {
+		return value.Value
+	}
+	return ""
+}
+
+// ramlPairs calls f with the keys and values of a map.
+func ramlPairs(node *yaml.Node, f func(key string, value *yaml.Node)) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		f(node.Content[i].Value, node.Content[i+1])
+	}
+}
+
+// ramlStrings returns the values of a scalar or a list of scalars.
+func ramlStrings(node *yaml.Node) []string {
+	switch {
+	case node == nil:
+		return nil
+	case node.Kind == yaml.ScalarNode && node.Tag != "!!null":
+		return []string{node.Value}
+	case node.Kind == yaml.SequenceNode:
+		var values []string
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode {
+				values = append(values, item.Value)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func (c *ramlConverter) document() *openapi3.Document {
+	d := &openapi3.Document{
+		Openapi: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       ramlString(c.root, "title"),
+			Description: ramlString(c.root, "description"),
+			Version:     ramlString(c.root, "version"),
+		},
+	}
+	if d.Info.Version == "" {
+		c.warn("#/version", "no version, using 1.0.0")
+		d.Info.Version = "1.0.0"
+	}
+	for _, key := range []string{"uses", "securitySchemes", "securedBy", "annotationTypes"} {
+		if ramlValue(c.root, key) != nil {
+			c.warn("#/"+key, "%s aren't supported", key)
+		}
+	}
+	c.mediaTypes = ramlStrings(ramlValue(c.root, "mediaType"))
+	if len(c.mediaTypes) == 0 {
+		c.mediaTypes = []string{"application/json"}
+	}
+	d.Servers = c.servers()
+	types := ramlValue(c.root, "types")
+	if types == nil {
+		// Types were named schemas in RAML 0.8 and are still accepted.
+		types = ramlValue(c.root, "schemas")
+	}
+	ramlPairs(types, func(name string, _ *yaml.Node) { c.types[name] = true })
+	ramlPairs(ramlValue(c.root, "traits"), func(name string, value *yaml.Node) { c.traits[name] = value })
+	ramlPairs(ramlValue(c.root, "resourceTypes"), func(name string, value *yaml.Node) { c.resourceTypes[name] = value })
+	d.Components = &openapi3.Components{}
+	ramlPairs(types, func(name string, value *yaml.Node) {
+		if d.Components.Schemas == nil {
+			d.Components.Schemas = &openapi3.SchemasOrReferences{}
+		}
+		d.Components.Schemas.AdditionalProperties = append(d.Components.Schemas.AdditionalProperties, &openapi3.NamedSchemaOrReference{
+			Name:  name,
+			Value: c.schema(value, "#/types/"+name),
+		})
+	})
+	c.traitParameters()
+	d.Components.Parameters = c.parameters
+	d.Paths = &openapi3.Paths{}
+	c.resources(d.Paths, "", c.root, nil, "#")
+	return d
+}
+
+var uriParameterPattern = regexp.MustCompile(`{([^{}]+)}`)
+
+// servers returns a server for the base URI. The version is substituted
+// and other URI parameters become server variables.
+func (c *ramlConverter) servers() []*openapi3.Server {
+	baseURI := ramlString(c.root, "baseUri")
+	if baseURI == "" {
+		return nil
+	}
+	server := &openapi3.Server{Url: strings.Replace(baseURI, "{version}", ramlString(c.root, "version"), -1)}
+	parameters := ramlValue(c.root, "baseUriParameters")
+	for _, match := range uriParameterPattern.FindAllStringSubmatch(server.Url, -1) {
+		name := match[1]
+		parameter := ramlValue(parameters, name)
+		variable := &openapi3.ServerVariable{
+			Default:     ramlString(parameter, "default"),
+			Description: ramlString(parameter, "description"),
+			Enum:        ramlStrings(ramlValue(parameter, "enum")),
+		}
+		if variable.Default == "" && len(variable.Enum) > 0 {
+			variable.Default = variable.Enum[0]
+		}
+		if variable.Default == "" {
+			c.warn("#/baseUriParameters/"+name, "base URI parameters need a default value")
+		}
+		if server.Variables == nil {
+			server.Variables = &openapi3.ServerVariables{}
+		}
+		server.Variables.AdditionalProperties = append(server.Variables.AdditionalProperties, &openapi3.NamedServerVariable{
+			Name:  name,
+			Value: variable,
+		})
+	}
+	return []*openapi3.Server{server}
+}
+
+// traitParameters adds the query parameters and headers of traits without
+// parameters to the component parameters.
+func (c *ramlConverter) traitParameters() {
+	ramlPairs(ramlValue(c.root, "traits"), func(trait string, value *yaml.Node) {
+		if hasTemplateParameters(value) {
+			return
+		}
+		pointer := "#/traits/" + trait
+		for _, in := range []struct{ key, in string }{{"queryParameters", "query"}, {"headers", "header"}} {
+			ramlPairs(ramlValue(value, in.key), func(name string, value *yaml.Node) {
+				parameter := c.parameter(name, in.in, value, pointer+"/"+in.key+"/"+compiler.EscapeJSONPointer(name))
+				component := trait + "." + parameter.Name
+				if c.parameters == nil {
+					c.parameters = &openapi3.ParametersOrReferences{}
+				}
+				c.parameters.AdditionalProperties = append(c.parameters.AdditionalProperties, &openapi3.NamedParameterOrReference{
+					Name:  component,
+					Value: &openapi3.ParameterOrReference{Oneof: &openapi3.ParameterOrReference_Parameter{Parameter: parameter}},
+				})
+				c.traitRefs[trait] = append(c.traitRefs[trait], "#/components/parameters/"+component)
+			})
+		}
+	})
+}
+
+// resources adds path items for the nested resources of a resource.
+func (c *ramlConverter) resources(paths *openapi3.Paths, parentPath string, node *yaml.Node, uriParameters *yaml.Node, pointer string) {
+	ramlPairs(node, func(key string, value *yaml.Node) {
+		if !strings.HasPrefix(key, "/") {
+			return
+		}
+		c.resource(paths, parentPath+key, key, value, uriParameters, pointer+"/"+compiler.EscapeJSONPointer(key))
+	})
+}
+
+func (c *ramlConverter) resource(paths *openapi3.Paths, path, relativePath string, node *yaml.Node, uriParameters *yaml.Node, pointer string) {
+	node = c.applyResourceType(node, path, relativePath, pointer, map[string]bool{})
+	// URI parameters of parent resources are inherited.
+	merged := &yaml.Node{Kind: yaml.MappingNode}
+	if uriParameters != nil {
+		merged = mergeNodes(uriParameters, merged)
+	}
+	if own := ramlValue(node, "uriParameters"); own != nil {
+		merged = mergeNodes(merged, own)
+	}
+	uriParameters = merged
+	item := &openapi3.PathItem{
+		Summary:     ramlString(node, "displayName"),
+		Description: ramlString(node, "description"),
+	}
+	for _, match := range uriParameterPattern.FindAllStringSubmatch(path, -1) {
+		name := match[1]
+		declaration := ramlValue(uriParameters, name)
+		if declaration == nil {
+			declaration = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "string"}
+		}
+		parameter := c.parameter(name, "path", declaration, pointer+"/uriParameters/"+name)
+		item.Parameters = append(item.Parameters, &openapi3.ParameterOrReference{
+			Oneof: &openapi3.ParameterOrReference_Parameter{Parameter: parameter},
+		})
+	}
+	traits := ramlValue(node, "is")
+	operations := 0
+	for _, method := range ramlMethods {
+		value := ramlValue(node, method)
+		if value == nil {
+			continue
+		}
+		operation := c.operation(method, value, traits, path, relativePath, pointer+"/"+method)
+		switch method {
+		case "get":
+			item.Get = operation
+		case "put":
+			item.Put = operation
+		case "post":
+			item.Post = operation
+		case "delete":
+			item.Delete = operation
+		case "options":
+			item.Options = operation
+		case "head":
+			item.Head = operation
+		case "patch":
+			item.Patch = operation
+		}
+		operations++
+	}
+	if operations > 0 {
+		paths.Path = append(paths.Path, &openapi3.NamedPathItem{Name: path, Value: item})
+	}
+	c.resources(paths, path, node, uriParameters, pointer)
+}
+
+// applyResourceType merges the resource type of a resource into a copy of
+// the resource. Methods of the resource type that end with "?" are only
+// merged into methods of the resource.
+func (c *ramlConverter) applyResourceType(node *yaml.Node, path, relativePath, pointer string, seen map[string]bool) *yaml.Node {
+	name, parameters := templateReference(ramlValue(node, "type"))
+	if name == "" {
+		return node
+	}
+	template, ok := c.resourceTypes[name]
+	if !ok {
+		c.warn(pointer+"/type", "unknown resource type %s", name)
+		return node
+	}
+	if seen[name] {
+		c.warn(pointer+"/type", "resource type %s is recursive", name)
+		return node
+	}
+	seen[name] = true
+	parameters["resourcePath"] = path
+	parameters["resourcePathName"] = resourcePathName(relativePath)
+	template = c.substitute(template, parameters, "#/resourceTypes/"+name)
+	for i := 0; i+1 < len(template.Content); i += 2 {
+		key := template.Content[i]
+		method := strings.TrimSuffix(key.Value, "?")
+		if method == key.Value || !isRAMLMethod(method) {
+			continue
+		}
+		if ramlValue(node, method) != nil {
+			key.Value = method
+		} else {
+			template.Content = append(template.Content[:i], template.Content[i+2:]...)
+			i -= 2
+		}
+	}
+	// Resource types can have resource types of their own.
+	template = c.applyResourceType(template, path, relativePath, pointer, seen)
+	result := mergeNodes(template, node)
+	removeKey(result, "type")
+	return result
+}
+
+func isRAMLMethod(name string) bool {
+	for _, method := range ramlMethods {
+		if name == method {
+			return true
+		}
+	}
+	return false
+}
+
+// resourcePathName returns the last segment of a relative resource path
+// that isn't a URI parameter.
+func resourcePathName(relativePath string) string {
+	segments := strings.Split(strings.Trim(relativePath, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if !strings.HasPrefix(segments[i], "{") {
+			return segments[i]
+		}
+	}
+	return ""
+}
+
+// templateReference returns the name and parameters of a reference to a
+// resource type or trait, which is a name or a map from a name to its
+// parameters.
+func templateReference(node *yaml.Node) (string, map[string]string) {
+	parameters := map[string]string{}
+	switch {
+	case node == nil:
+		return "", parameters
+	case node.Kind == yaml.ScalarNode:
+		return node.Value, parameters
+	case node.Kind == yaml.MappingNode && len(node.Content) == 2:
+		ramlPairs(node.Content[1], func(key string, value *yaml.Node) {
+			if value.Kind == yaml.ScalarNode {
+				parameters[key] = value.Value
+			}
+		})
+		return node.Content[0].Value, parameters
+	}
+	return "", parameters
+}
+
+var templateParameterPattern = regexp.MustCompile(`<<\s*([A-Za-z0-9_]+)\s*((?:\|\s*![A-Za-z]+\s*)*)>>`)
+
+// hasTemplateParameters returns true if a resource type or trait has
+// parameters.
+func hasTemplateParameters(node *yaml.Node) bool {
+	found := false
+	compiler.VisitAll(node, func(path, key string, value *yaml.Node) {
+		found = found || strings.Contains(key, "<<") || (value.Kind == yaml.ScalarNode && strings.Contains(value.Value, "<<"))
+	})
+	return found
+}
+
+// substitute returns a copy of a resource type or trait with its
+// parameters replaced by their values.
+func (c *ramlConverter) substitute(node *yaml.Node, parameters map[string]string, pointer string) *yaml.Node {
+	replace := func(s string) string {
+		return templateParameterPattern.ReplaceAllStringFunc(s, func(match string) string {
+			groups := templateParameterPattern.FindStringSubmatch(match)
+			value, ok := parameters[groups[1]]
+			if !ok {
+				c.warn(pointer, "no value for parameter %s", groups[1])
+			}
+			for _, function := range strings.Split(groups[2], "|") {
+				if function = strings.TrimSpace(function); function != "" {
+					value = c.transform(value, function, pointer)
+				}
+			}
+			return value
+		})
+	}
+	var copyNode func(n *yaml.Node) *yaml.Node
+	copyNode = func(n *yaml.Node) *yaml.Node {
+		result := *n
+		if n.Kind == yaml.ScalarNode {
+			result.Value = replace(n.Value)
+		}
+		result.Content = nil
+		for _, child := range n.Content {
+			result.Content = append(result.Content, copyNode(child))
+		}
+		return &result
+	}
+	return copyNode(node)
+}
+
+// transform applies a RAML parameter function to a value.
+func (c *ramlConverter) transform(value, function, pointer string) string {
+	words := strings.FieldsFunc(value, func(r rune) bool { return r == '-' || r == '_' || r == ' ' })
+	switch function {
+	case "!singularize":
+		switch {
+		case strings.HasSuffix(value, "ies"):
+			return strings.TrimSuffix(value, "ies") + "y"
+		case strings.HasSuffix(value, "ss"):
+			return value
+		}
+		return strings.TrimSuffix(value, "s")
+	case "!pluralize":
+		switch {
+		case strings.HasSuffix(value, "y") && !strings.HasSuffix(value, "ay") && !strings.HasSuffix(value, "ey") && !strings.HasSuffix(value, "oy"):
+			return strings.TrimSuffix(value, "y") + "ies"
+		case strings.HasSuffix(value, "s"):
+			return value + "es"
+		}
+		return value + "s"
+	case "!uppercase":
+		return strings.ToUpper(value)
+	case "!lowercase":
+		return strings.ToLower(value)
+	case "!lowercamelcase", "!uppercamelcase":
+		for i, word := range words {
+			if i > 0 || function == "!uppercamelcase" {
+				words[i] = strings.ToUpper(word[:1]) + word[1:]
+			}
+		}
+		return strings.Join(words, "")
+	case "!lowerunderscorecase", "!upperunderscorecase", "!lowerhyphencase", "!upperhyphencase":
+		separator := "_"
+		if strings.HasSuffix(function, "hyphencase") {
+			separator = "-"
+		}
+		result := strings.Join(words, separator)
+		if strings.HasPrefix(function, "!upper") {
+			return strings.ToUpper(result)
+		}
+		return strings.ToLower(result)
+	}
+	c.warn(pointer, "unknown parameter function %s", function)
+	return value
+}
+
+// mergeNodes returns a copy of base with the values of override. Maps are
+// merged, null values are ignored and other values are replaced.
+func mergeNodes(base, override *yaml.Node) *yaml.Node {
+	switch {
+	case base == nil:
+		return override
+	case override.Tag == "!!null":
+		return base
+	case base.Kind != yaml.MappingNode || override.Kind != yaml.MappingNode:
+		return override
+	}
+	result := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(base.Content); i += 2 {
+		key, value := base.Content[i], base.Content[i+1]
+		other := ramlValue(override, key.Value)
+		switch {
+		case other == nil:
+		case key.Value == "is" && value.Kind == yaml.SequenceNode && other.Kind == yaml.SequenceNode:
+			// The traits of both are applied.
+			value = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: append(append([]*yaml.Node{}, value.Content...), other.Content...)}
+		default:
+			value = mergeNodes(value, other)
+		}
+		result.Content = append(result.Content, key, value)
+	}
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		if ramlValue(base, override.Content[i].Value) == nil {
+			result.Content = append(result.Content, override.Content[i], override.Content[i+1])
+		}
+	}
+	return result
+}
+
+// removeKey removes a key and its value from a map.
+func removeKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// withoutKeys returns a copy of a map without some of its keys.
+func withoutKeys(node *yaml.Node, keys ...string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return node
+	}
+	result := *node
+	result.Content = append([]*yaml.Node{}, node.Content...)
+	for _, key := range keys {
+		removeKey(&result, key)
+	}
+	return &result
+}
+
+// operation converts a method. Traits of the method and its resource
+// contribute references to their component parameters, or are merged into
+// the method if they have parameters.
+func (c *ramlConverter) operation(method string, node, resourceTraits *yaml.Node, path, relativePath, pointer string) *openapi3.Operation {
+	if node.Kind != yaml.MappingNode {
+		node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	var references []string
+	var traits []*yaml.Node
+	for _, is := range []*yaml.Node{resourceTraits, ramlValue(node, "is")} {
+		if is == nil {
+			continue
+		}
+		if is.Kind != yaml.SequenceNode {
+			is = &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{is}}
+		}
+		traits = append(traits, is.Content...)
+	}
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, reference := range traits {
+		name, parameters := templateReference(reference)
+		trait, ok := c.traits[name]
+		if !ok {
+			c.warn(pointer+"/is", "unknown trait %s", name)
+			continue
+		}
+		if refs, ok := c.traitRefs[name]; ok || !hasTemplateParameters(trait) {
+			references = append(references, refs...)
+			trait = withoutKeys(trait, "queryParameters", "headers")
+		} else {
+			parameters["methodName"] = method
+			parameters["resourcePath"] = path
+			parameters["resourcePathName"] = resourcePathName(relativePath)
+			trait = c.substitute(trait, parameters, "#/traits/"+name)
+		}
+		merged = mergeNodes(merged, withoutKeys(trait, "usage"))
+	}
+	node = mergeNodes(merged, node)
+	operation := &openapi3.Operation{
+		Summary:     ramlString(node, "displayName"),
+		Description: ramlString(node, "description"),
+	}
+	for _, ref := range references {
+		operation.Parameters = append(operation.Parameters, &openapi3.ParameterOrReference{
+			Oneof: &openapi3.ParameterOrReference_Reference{Reference: &openapi3.Reference{XRef: ref}},
+		})
+	}
+	for _, in := range []struct{ key, in string }{{"queryParameters", "query"}, {"headers", "header"}} {
+		ramlPairs(ramlValue(node, in.key), func(name string, value *yaml.Node) {
+			parameter := c.parameter(name, in.in, value, pointer+"/"+in.key+"/"+compiler.EscapeJSONPointer(name))
+			operation.Parameters = append(operation.Parameters, &openapi3.ParameterOrReference{
+				Oneof: &openapi3.ParameterOrReference_Parameter{Parameter: parameter},
+			})
+		})
+	}
+	for _, key := range []string{"queryString", "securedBy"} {
+		if ramlValue(node, key) != nil {
+			c.warn(pointer+"/"+key, "%s isn't supported", key)
+		}
+	}
+	if body := ramlValue(node, "body"); body != nil {
+		operation.RequestBody = &openapi3.RequestBodyOrReference{
+			Oneof: &openapi3.RequestBodyOrReference_RequestBody{RequestBody: &openapi3.RequestBody{
+				Content:  c.content(body, pointer+"/body"),
+				Required: true,
+			}},
+		}
+	}
+	operation.Responses = c.responses(ramlValue(node, "responses"), pointer+"/responses")
+	return operation
+}
+
+func (c *ramlConverter) responses(node *yaml.Node, pointer string) *openapi3.Responses {
+	responses := &openapi3.Responses{}
+	var codes []string
+	ramlPairs(node, func(code string, _ *yaml.Node) { codes = append(codes, code) })
+	sort.Strings(codes)
+	for _, code := range codes {
+		value := ramlValue(node, code)
+		responsePointer := pointer + "/" + code
+		response := &openapi3.Response{Description: ramlString(value, "description")}
+		if response.Description == "" {
+			status, _ := strconv.Atoi(code)
+			response.Description = http.StatusText(status)
+		}
+		ramlPairs(ramlValue(value, "headers"), func(name string, value *yaml.Node) {
+			parameter := c.parameter(name, "header", value, responsePointer+"/headers/"+compiler.EscapeJSONPointer(name))
+			if response.Headers == nil {
+				response.Headers = &openapi3.HeadersOrReferences{}
+			}
+			response.Headers.AdditionalProperties = append(response.Headers.AdditionalProperties, &openapi3.NamedHeaderOrReference{
+				Name: parameter.Name,
+				Value: &openapi3.HeaderOrReference{Oneof: &openapi3.HeaderOrReference_Header{Header: &openapi3.Header{
+					Description: parameter.Description,
+					Required:    parameter.Required,
+					Schema:      parameter.Schema,
+					Example:     parameter.Example,
+				}}},
+			})
+		})
+		if body := ramlValue(value, "body"); body != nil {
+			response.Content = c.content(body, responsePointer+"/body")
+		}
+		responses.ResponseOrReference = append(responses.ResponseOrReference, &openapi3.NamedResponseOrReference{
+			Name:  code,
+			Value: &openapi3.ResponseOrReference{Oneof: &openapi3.ResponseOrReference_Response{Response: response}},
+		})
+	}
+	if len(responses.ResponseOrReference) == 0 {
+		responses.Default = &openapi3.ResponseOrReference{
+			Oneof: &openapi3.ResponseOrReference_Response{Response: &openapi3.Response{Description: "Default response"}},
+		}
+	}
+	return responses
+}
+
+// content converts a body, which declares a type for each media type or
+// a type for the default media types.
+func (c *ramlConverter) content(node *yaml.Node, pointer string) *openapi3.MediaTypes {
+	content := &openapi3.MediaTypes{}
+	add := func(mediaType string, value *yaml.Node, pointer string) {
+		result := &openapi3.MediaType{Example: ramlExample(ramlValue(value, "example"))}
+		if ramlValue(value, "examples") != nil {
+			c.warn(pointer+"/examples", "multiple examples aren't supported")
+		}
+		if value != nil && value.Tag != "!!null" {
+			result.Schema = c.schema(withoutKeys(value, "example", "examples"), pointer)
+		}
+		content.AdditionalProperties = append(content.AdditionalProperties, &openapi3.NamedMediaType{Name: mediaType, Value: result})
+	}
+	perMediaType := false
+	ramlPairs(node, func(key string, _ *yaml.Node) { perMediaType = perMediaType || strings.Contains(key, "/") })
+	if perMediaType {
+		ramlPairs(node, func(mediaType string, value *yaml.Node) {
+			add(mediaType, value, pointer+"/"+compiler.EscapeJSONPointer(mediaType))
+		})
+		return content
+	}
+	for _, mediaType := range c.mediaTypes {
+		add(mediaType, node, pointer)
+	}
+	return content
+}
+
+// parameter converts a URI or query parameter or a header. Names that
+// end with "?" are optional, and other parameters are required unless
+// they are declared with "required: false".
+func (c *ramlConverter) parameter(name, in string, node *yaml.Node, pointer string) *openapi3.Parameter {
+	parameter := &openapi3.Parameter{
+		Name:        strings.TrimSuffix(name, "?"),
+		In:          in,
+		Description: ramlString(node, "description"),
+		Required:    !strings.HasSuffix(name, "?") && ramlString(node, "required") != "false",
+		Example:     ramlExample(ramlValue(node, "example")),
+	}
+	if in == "path" {
+		parameter.Required = true
+	}
+	parameter.Schema = c.schema(withoutKeys(node, "description", "required", "example", "displayName"), pointer)
+	return parameter
+}
+
+// ramlExample returns an example value.
+func ramlExample(node *yaml.Node) *openapi3.Any {
+	if node == nil {
+		return nil
+	}
+	if value := ramlValue(node, "value"); value != nil && ramlValue(node, "strict") != nil {
+		// This is an example with facets.
+		node = value
+	}
+	bytes, err := yaml.Marshal(node)
+	if err != nil {
+		return nil
+	}
+	return &openapi3.Any{Yaml: string(bytes)}
+}
+
+// schema converts a type declaration, which is a type expression or a map
+// of a type and its facets.
+func (c *ramlConverter) schema(node *yaml.Node, pointer string) *openapi3.SchemaOrReference {
+	if node == nil || node.Tag == "!!null" {
+		return schemaOrReference(&openapi3.Schema{Type: "string"})
+	}
+	if node.Kind == yaml.ScalarNode {
+		return c.typeExpression(node.Value, pointer)
+	}
+	if node.Kind == yaml.SequenceNode {
+		node = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Value: "type"}, node}}
+	}
+	if node.Kind != yaml.MappingNode {
+		c.warn(pointer, "invalid type declaration")
+		return schemaOrReference(&openapi3.Schema{})
+	}
+	typeNode := ramlValue(node, "type")
+	if typeNode == nil {
+		typeNode = ramlValue(node, "schema")
+	}
+	var base *openapi3.SchemaOrReference
+	switch {
+	case typeNode != nil && typeNode.Kind == yaml.SequenceNode:
+		// Types with several parent types are combinations of them.
+		schema := &openapi3.Schema{}
+		for i, parent := range typeNode.Content {
+			schema.AllOf = append(schema.AllOf, c.typeExpression(parent.Value, fmt.Sprintf("%s/type/%d", pointer, i)))
+		}
+		base = schemaOrReference(schema)
+	case typeNode != nil && typeNode.Kind == yaml.ScalarNode:
+		base = c.typeExpression(typeNode.Value, pointer+"/type")
+	case typeNode != nil && typeNode.Kind == yaml.MappingNode:
+		base = c.schema(typeNode, pointer+"/type")
+	case ramlValue(node, "properties") != nil:
+		base = schemaOrReference(&openapi3.Schema{Type: "object"})
+	case ramlValue(node, "items") != nil:
+		base = schemaOrReference(&openapi3.Schema{Type: "array"})
+	default:
+		base = schemaOrReference(&openapi3.Schema{Type: "string"})
+	}
+	facets := &openapi3.Schema{}
+	if !c.facets(facets, node, pointer) {
+		return base
+	}
+	schema := base.GetSchema()
+	if schema == nil || len(schema.AllOf) > 0 || len(schema.OneOf) > 0 {
+		// Facets of other types are added with allOf.
+		facets.AllOf = []*openapi3.SchemaOrReference{base}
+		return schemaOrReference(facets)
+	}
+	proto.Merge(schema, facets)
+	return base
+}
+
+// facets sets the facets of a type declaration and returns true if any
+// facets were set.
+func (c *ramlConverter) facets(schema *openapi3.Schema, node *yaml.Node, pointer string) bool {
+	ramlPairs(node, func(key string, value *yaml.Node) {
+		switch key {
+		case "type", "schema", "examples", "fileTypes", "facets", "xml":
+		case "displayName":
+			schema.Title = value.Value
+		case "description":
+			schema.Description = value.Value
+		case "pattern":
+			schema.Pattern = value.Value
+		case "format":
+			schema.Format = ramlFormat(value.Value)
+		case "minLength":
+			schema.MinLength, _ = strconv.ParseInt(value.Value, 10, 64)
+		case "maxLength":
+			schema.MaxLength, _ = strconv.ParseInt(value.Value, 10, 64)
+		case "minItems":
+			schema.MinItems, _ = strconv.ParseInt(value.Value, 10, 64)
+		case "maxItems":
+			schema.MaxItems, _ = strconv.ParseInt(value.Value, 10, 64)
+		case "minProperties":
+			schema.MinProperties, _ = strconv.ParseInt(value.Value, 10, 64)
+		case "maxProperties":
+			schema.MaxProperties, _ = strconv.ParseInt(value.Value, 10, 64)
+		case "minimum":
+			schema.Minimum, _ = strconv.ParseFloat(value.Value, 64)
+		case "maximum":
+			schema.Maximum, _ = strconv.ParseFloat(value.Value, 64)
+		case "multipleOf":
+			schema.MultipleOf, _ = strconv.ParseFloat(value.Value, 64)
+		case "uniqueItems":
+			schema.UniqueItems = value.Value == "true"
+		case "additionalProperties":
+			schema.AdditionalProperties = &openapi3.AdditionalPropertiesItem{
+				Oneof: &openapi3.AdditionalPropertiesItem_Boolean{Boolean: value.Value != "false"},
+			}
+		case "discriminator":
+			schema.Discriminator = &openapi3.Discriminator{PropertyName: value.Value}
+		case "discriminatorValue":
+			c.warn(pointer+"/discriminatorValue", "discriminator values aren't supported")
+		case "enum":
+			for _, item := range value.Content {
+				schema.Enum = append(schema.Enum, ramlExample(item))
+			}
+		case "example":
+			schema.Example = ramlExample(value)
+		case "default":
+			var v interface{}
+			if value.Decode(&v) == nil {
+				if d, ok := defaultType(v); ok {
+					schema.Default = d
+				} else {
+					c.warn(pointer+"/default", "default values that aren't strings, numbers or booleans aren't supported")
+				}
+			}
+		case "items":
+			schema.Items = &openapi3.ItemsItem{
+				SchemaOrReference: []*openapi3.SchemaOrReference{c.schema(value, pointer+"/items")},
+			}
+		case "properties":
+			schema.Properties = &openapi3.Properties{}
+			ramlPairs(value, func(name string, value *yaml.Node) {
+				propertyPointer := pointer + "/properties/" + compiler.EscapeJSONPointer(name)
+				if len(name) > 1 && strings.HasPrefix(name, "/") && strings.HasSuffix(name, "/") {
+					// Pattern properties become additional properties.
+					schema.AdditionalProperties = &openapi3.AdditionalPropertiesItem{
+						Oneof: &openapi3.AdditionalPropertiesItem_SchemaOrReference{SchemaOrReference: c.schema(value, propertyPointer)},
+					}
+					return
+				}
+				required := !strings.HasSuffix(name, "?") && ramlString(value, "required") != "false"
+				name = strings.TrimSuffix(name, "?")
+				schema.Properties.AdditionalProperties = append(schema.Properties.AdditionalProperties, &openapi3.NamedSchemaOrReference{
+					Name:  name,
+					Value: c.schema(withoutKeys(value, "required"), propertyPointer),
+				})
+				if required {
+					schema.Required = append(schema.Required, name)
+				}
+			})
+		default:
+			// Keys in parentheses are annotations.
+			if !strings.HasPrefix(key, "(") {
+				c.warn(pointer+"/"+key, "unknown facet %s", key)
+			}
+		}
+	})
+	return !proto.Equal(schema, &openapi3.Schema{})
+}
+
+// ramlFormat converts the formats of RAML numbers to OpenAPI formats.
+func ramlFormat(format string) string {
+	switch format {
+	case "int8", "int16", "int":
+		return "int32"
+	case "long":
+		return "int64"
+	}
+	return format
+}
+
+// typeExpression converts a RAML type expression, which names a type or
+// combines types into arrays and unions.
+func (c *ramlConverter) typeExpression(expression, pointer string) *openapi3.SchemaOrReference {
+	expression = strings.TrimSpace(expression)
+	if members := splitUnion(expression); len(members) > 1 {
+		schema := &openapi3.Schema{}
+		nullable := false
+		for _, member := range members {
+			if member == "nil" {
+				nullable = true
+				continue
+			}
+			schema.OneOf = append(schema.OneOf, c.typeExpression(member, pointer))
+		}
+		if len(schema.OneOf) == 1 {
+			if s := schema.OneOf[0].GetSchema(); s != nil && len(s.OneOf) == 0 {
+				s.Nullable = nullable
+				return schema.OneOf[0]
+			}
+			schema.AllOf, schema.OneOf = schema.OneOf, nil
+		}
+		schema.Nullable = nullable
+		return schemaOrReference(schema)
+	}
+	if strings.HasSuffix(expression, "[]") {
+		return schemaOrReference(&openapi3.Schema{
+			Type:  "array",
+			Items: &openapi3.ItemsItem{SchemaOrReference: []*openapi3.SchemaOrReference{c.typeExpression(strings.TrimSuffix(expression, "[]"), pointer)}},
+		})
+	}
+	if strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") {
+		return c.typeExpression(expression[1:len(expression)-1], pointer)
+	}
+	if builtin, ok := ramlBuiltinTypes[expression]; ok {
+		return schemaOrReference(builtin())
+	}
+	if !c.types[expression] {
+		c.warn(pointer, "unknown type %s", expression)
+	}
+	return schemaReference("#/components/schemas/" + expression)
+}
+
+// splitUnion splits a type expression into the members of a union.
+func splitUnion(expression string) []string {
+	var members []string
+	depth, start := 0, 0
+	for i, r := range expression {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '|':
+			if depth == 0 {
+				members = append(members, strings.TrimSpace(expression[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(members, strings.TrimSpace(expression[start:]))
+}
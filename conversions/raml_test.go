@@ -0,0 +1,80 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversions
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	openapi3 "github.com/google/gnostic/openapiv3"
+)
+
+func TestOpenAPIv3FromRAML(t *testing.T) {
+	bytes, err := ioutil.ReadFile("testdata/library.raml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, warnings, err := OpenAPIv3FromRAML(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	output, err := yaml.Marshal(document.ToRawInfo())
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected, err := ioutil.ReadFile("testdata/library-v3.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if string(output) != string(expected) {
+		ioutil.WriteFile("testdata/library-v3.yaml.out", output, 0644)
+		t.Errorf("converted document differs from testdata/library-v3.yaml")
+	} else {
+		os.Remove("testdata/library-v3.yaml.out")
+	}
+	// The converted document must be a valid OpenAPI v3 document.
+	if _, err := openapi3.ParseDocument(output); err != nil {
+		t.Errorf("%+v", err)
+	}
+	expectedWarnings := []string{
+		"#/types/Review: !include review.raml isn't supported",
+		"#/uses: uses aren't supported",
+		"#/~1books/~1{isbn}/delete/securedBy: securedBy isn't supported",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(expectedWarnings, "\n") {
+		t.Errorf("unexpected warnings:\n%s", strings.Join(warnings, "\n"))
+	}
+}
+
+func TestOpenAPIv3FromRAMLErrors(t *testing.T) {
+	for _, test := range []struct {
+		source string
+		err    string
+	}{
+		{"openapi: 3.0.3\n", "not a RAML 1.0 API definition"},
+		{"#%RAML 0.8\ntitle: Old\n", "not a RAML 1.0 API definition"},
+		{"#%RAML 1.0 Library\ntypes: {}\n", "RAML 1.0 Library fragments aren't supported"},
+		{"#%RAML 1.0\n- title\n", "RAML API definitions must be maps"},
+	} {
+		_, _, err := OpenAPIv3FromRAML([]byte(test.source))
+		if err == nil || err.Error() != test.err {
+			t.Errorf("expected error %q for %q, got %v", test.err, test.source, err)
+		}
+	}
+}
@@ -38,14 +38,16 @@ func NewAnnotations(in *yaml.Node, context *compiler.Context) (*Annotations, err
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"required"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"required"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// repeated string required = 1;
 		v1 := compiler.MapValueForKey(m, "required")
@@ -55,7 +57,7 @@ func NewAnnotations(in *yaml.Node, context *compiler.Context) (*Annotations, err
 				x.Required = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -78,14 +80,16 @@ func NewAuth(in *yaml.Node, context *compiler.Context) (*Auth, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"oauth2"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"oauth2"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// Oauth2 oauth2 = 1;
 		v1 := compiler.MapValueForKey(m, "oauth2")
@@ -107,20 +111,22 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"discoveryVersion", "kind"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"auth", "basePath", "baseUrl", "batchPath", "canonicalName", "description", "discoveryVersion", "documentationLink", "etag", "features", "fullyEncodeReservedExpansion", "icons", "id", "kind", "labels", "methods", "mtlsRootUrl", "name", "ownerDomain", "ownerName", "packagePath", "parameters", "protocol", "resources", "revision", "rootUrl", "schemas", "servicePath", "title", "version", "version_module"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"discoveryVersion", "kind"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"auth", "basePath", "baseUrl", "batchPath", "canonicalName", "description", "discoveryVersion", "documentationLink", "etag", "features", "fullyEncodeReservedExpansion", "icons", "id", "kind", "labels", "methods", "mtlsRootUrl", "name", "ownerDomain", "ownerName", "packagePath", "parameters", "protocol", "resources", "revision", "rootUrl", "schemas", "servicePath", "title", "version", "version_module"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string kind = 1;
 		v1 := compiler.MapValueForKey(m, "kind")
@@ -128,7 +134,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Kind, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for kind: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string discovery_version = 2;
@@ -137,7 +143,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.DiscoveryVersion, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for discoveryVersion: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string id = 3;
@@ -146,7 +152,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Id, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for id: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string name = 4;
@@ -155,7 +161,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Name, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string version = 5;
@@ -164,7 +170,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Version, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for version: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string revision = 6;
@@ -173,7 +179,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Revision, ok = compiler.StringForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for revision: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string title = 7;
@@ -182,7 +188,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Title, ok = compiler.StringForScalarNode(v7)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for title: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 8;
@@ -191,7 +197,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Description, ok = compiler.StringForScalarNode(v8)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Icons icons = 9;
@@ -209,7 +215,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.DocumentationLink, ok = compiler.StringForScalarNode(v10)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for documentationLink: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string labels = 11;
@@ -220,7 +226,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 				x.Labels = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for labels: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string protocol = 12;
@@ -229,7 +235,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Protocol, ok = compiler.StringForScalarNode(v12)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for protocol: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string base_url = 13;
@@ -238,7 +244,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.BaseUrl, ok = compiler.StringForScalarNode(v13)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for baseUrl: %s", compiler.Display(v13))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string base_path = 14;
@@ -247,7 +253,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.BasePath, ok = compiler.StringForScalarNode(v14)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for basePath: %s", compiler.Display(v14))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string root_url = 15;
@@ -256,7 +262,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.RootUrl, ok = compiler.StringForScalarNode(v15)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for rootUrl: %s", compiler.Display(v15))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string service_path = 16;
@@ -265,7 +271,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.ServicePath, ok = compiler.StringForScalarNode(v16)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for servicePath: %s", compiler.Display(v16))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string batch_path = 17;
@@ -274,7 +280,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.BatchPath, ok = compiler.StringForScalarNode(v17)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for batchPath: %s", compiler.Display(v17))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Parameters parameters = 18;
@@ -303,7 +309,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 				x.Features = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for features: %s", compiler.Display(v20))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Schemas schemas = 21;
@@ -339,7 +345,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Etag, ok = compiler.StringForScalarNode(v24)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for etag: %s", compiler.Display(v24))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string owner_domain = 25;
@@ -348,7 +354,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.OwnerDomain, ok = compiler.StringForScalarNode(v25)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for ownerDomain: %s", compiler.Display(v25))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string owner_name = 26;
@@ -357,7 +363,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.OwnerName, ok = compiler.StringForScalarNode(v26)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for ownerName: %s", compiler.Display(v26))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool version_module = 27;
@@ -366,7 +372,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.VersionModule, ok = compiler.BoolForScalarNode(v27)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for version_module: %s", compiler.Display(v27))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string canonical_name = 28;
@@ -375,7 +381,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.CanonicalName, ok = compiler.StringForScalarNode(v28)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for canonicalName: %s", compiler.Display(v28))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool fully_encode_reserved_expansion = 29;
@@ -384,7 +390,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.FullyEncodeReservedExpansion, ok = compiler.BoolForScalarNode(v29)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for fullyEncodeReservedExpansion: %s", compiler.Display(v29))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string package_path = 30;
@@ -393,7 +399,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.PackagePath, ok = compiler.StringForScalarNode(v30)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for packagePath: %s", compiler.Display(v30))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string mtls_root_url = 31;
@@ -402,7 +408,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.MtlsRootUrl, ok = compiler.StringForScalarNode(v31)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for mtlsRootUrl: %s", compiler.Display(v31))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -416,20 +422,22 @@ func NewIcons(in *yaml.Node, context *compiler.Context) (*Icons, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"x16", "x32"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"x16", "x32"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"x16", "x32"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"x16", "x32"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string x16 = 1;
 		v1 := compiler.MapValueForKey(m, "x16")
@@ -437,7 +445,7 @@ func NewIcons(in *yaml.Node, context *compiler.Context) (*Icons, error) {
 			x.X16, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for x16: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string x32 = 2;
@@ -446,7 +454,7 @@ func NewIcons(in *yaml.Node, context *compiler.Context) (*Icons, error) {
 			x.X32, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for x32: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -460,14 +468,16 @@ func NewMediaUpload(in *yaml.Node, context *compiler.Context) (*MediaUpload, err
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"accept", "maxSize", "protocols", "supportsSubscription"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"accept", "maxSize", "protocols", "supportsSubscription"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// repeated string accept = 1;
 		v1 := compiler.MapValueForKey(m, "accept")
@@ -477,7 +487,7 @@ func NewMediaUpload(in *yaml.Node, context *compiler.Context) (*MediaUpload, err
 				x.Accept = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for accept: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string max_size = 2;
@@ -486,7 +496,7 @@ func NewMediaUpload(in *yaml.Node, context *compiler.Context) (*MediaUpload, err
 			x.MaxSize, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for maxSize: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Protocols protocols = 3;
@@ -504,7 +514,7 @@ func NewMediaUpload(in *yaml.Node, context *compiler.Context) (*MediaUpload, err
 			x.SupportsSubscription, ok = compiler.BoolForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for supportsSubscription: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -518,14 +528,16 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"description", "etagRequired", "flatPath", "httpMethod", "id", "mediaUpload", "parameterOrder", "parameters", "path", "request", "response", "scopes", "streamingType", "supportsMediaDownload", "supportsMediaUpload", "supportsSubscription", "useMediaDownloadService"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"description", "etagRequired", "flatPath", "httpMethod", "id", "mediaUpload", "parameterOrder", "parameters", "path", "request", "response", "scopes", "streamingType", "supportsMediaDownload", "supportsMediaUpload", "supportsSubscription", "useMediaDownloadService"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string id = 1;
 		v1 := compiler.MapValueForKey(m, "id")
@@ -533,7 +545,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 			x.Id, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for id: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string path = 2;
@@ -542,7 +554,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 			x.Path, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for path: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string http_method = 3;
@@ -551,7 +563,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 			x.HttpMethod, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for httpMethod: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 4;
@@ -560,7 +572,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 			x.Description, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Parameters parameters = 5;
@@ -580,7 +592,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 				x.ParameterOrder = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for parameterOrder: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Request request = 7;
@@ -609,7 +621,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 				x.Scopes = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for scopes: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool supports_media_download = 10;
@@ -618,7 +630,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 			x.SupportsMediaDownload, ok = compiler.BoolForScalarNode(v10)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for supportsMediaDownload: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool supports_media_upload = 11;
@@ -627,7 +639,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 			x.SupportsMediaUpload, ok = compiler.BoolForScalarNode(v11)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for supportsMediaUpload: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool use_media_download_service = 12;
@@ -636,7 +648,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 			x.UseMediaDownloadService, ok = compiler.BoolForScalarNode(v12)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for useMediaDownloadService: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// MediaUpload media_upload = 13;
@@ -654,7 +666,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 			x.SupportsSubscription, ok = compiler.BoolForScalarNode(v14)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for supportsSubscription: %s", compiler.Display(v14))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string flat_path = 15;
@@ -663,7 +675,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 			x.FlatPath, ok = compiler.StringForScalarNode(v15)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for flatPath: %s", compiler.Display(v15))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool etag_required = 16;
@@ -672,7 +684,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 			x.EtagRequired, ok = compiler.BoolForScalarNode(v16)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for etagRequired: %s", compiler.Display(v16))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string streaming_type = 17;
@@ -681,7 +693,7 @@ func NewMethod(in *yaml.Node, context *compiler.Context) (*Method, error) {
 			x.StreamingType, ok = compiler.StringForScalarNode(v17)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for streamingType: %s", compiler.Display(v17))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -695,7 +707,7 @@ func NewMethods(in *yaml.Node, context *compiler.Context) (*Methods, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedMethod additional_properties = 1;
 		// MAP: Method
@@ -725,14 +737,16 @@ func NewNamedMethod(in *yaml.Node, context *compiler.Context) (*NamedMethod, err
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -740,7 +754,7 @@ func NewNamedMethod(in *yaml.Node, context *compiler.Context) (*NamedMethod, err
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Method value = 2;
@@ -763,14 +777,16 @@ func NewNamedParameter(in *yaml.Node, context *compiler.Context) (*NamedParamete
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -778,7 +794,7 @@ func NewNamedParameter(in *yaml.Node, context *compiler.Context) (*NamedParamete
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Parameter value = 2;
@@ -801,14 +817,16 @@ func NewNamedResource(in *yaml.Node, context *compiler.Context) (*NamedResource,
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -816,7 +834,7 @@ func NewNamedResource(in *yaml.Node, context *compiler.Context) (*NamedResource,
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Resource value = 2;
@@ -839,14 +857,16 @@ func NewNamedSchema(in *yaml.Node, context *compiler.Context) (*NamedSchema, err
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -854,7 +874,7 @@ func NewNamedSchema(in *yaml.Node, context *compiler.Context) (*NamedSchema, err
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Schema value = 2;
@@ -877,14 +897,16 @@ func NewNamedScope(in *yaml.Node, context *compiler.Context) (*NamedScope, error
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -892,7 +914,7 @@ func NewNamedScope(in *yaml.Node, context *compiler.Context) (*NamedScope, error
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Scope value = 2;
@@ -915,14 +937,16 @@ func NewOauth2(in *yaml.Node, context *compiler.Context) (*Oauth2, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"scopes"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"scopes"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// Scopes scopes = 1;
 		v1 := compiler.MapValueForKey(m, "scopes")
@@ -944,14 +968,16 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"$ref", "additionalProperties", "annotations", "default", "description", "enum", "enumDescriptions", "format", "id", "items", "location", "maximum", "minimum", "pattern", "properties", "repeated", "required", "type"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"$ref", "additionalProperties", "annotations", "default", "description", "enum", "enumDescriptions", "format", "id", "items", "location", "maximum", "minimum", "pattern", "properties", "repeated", "required", "type"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string id = 1;
 		v1 := compiler.MapValueForKey(m, "id")
@@ -959,7 +985,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Id, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for id: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string type = 2;
@@ -968,7 +994,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Type, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string _ref = 3;
@@ -977,7 +1003,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.XRef, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for $ref: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 4;
@@ -986,7 +1012,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Description, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string default = 5;
@@ -995,7 +1021,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Default, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for default: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool required = 6;
@@ -1004,7 +1030,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Required, ok = compiler.BoolForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string format = 7;
@@ -1013,7 +1039,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Format, ok = compiler.StringForScalarNode(v7)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for format: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string pattern = 8;
@@ -1022,7 +1048,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Pattern, ok = compiler.StringForScalarNode(v8)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for pattern: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string minimum = 9;
@@ -1031,7 +1057,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Minimum, ok = compiler.StringForScalarNode(v9)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for minimum: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string maximum = 10;
@@ -1040,7 +1066,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Maximum, ok = compiler.StringForScalarNode(v10)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for maximum: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string enum = 11;
@@ -1051,7 +1077,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 				x.Enum = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for enum: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string enum_descriptions = 12;
@@ -1062,7 +1088,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 				x.EnumDescriptions = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for enumDescriptions: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool repeated = 13;
@@ -1071,7 +1097,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Repeated, ok = compiler.BoolForScalarNode(v13)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for repeated: %s", compiler.Display(v13))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string location = 14;
@@ -1080,7 +1106,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Location, ok = compiler.StringForScalarNode(v14)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for location: %s", compiler.Display(v14))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Schemas properties = 15;
@@ -1130,7 +1156,7 @@ func NewParameters(in *yaml.Node, context *compiler.Context) (*Parameters, error
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedParameter additional_properties = 1;
 		// MAP: Parameter
@@ -1160,14 +1186,16 @@ func NewProtocols(in *yaml.Node, context *compiler.Context) (*Protocols, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"resumable", "simple"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"resumable", "simple"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// Simple simple = 1;
 		v1 := compiler.MapValueForKey(m, "simple")
@@ -1198,14 +1226,16 @@ func NewRequest(in *yaml.Node, context *compiler.Context) (*Request, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"$ref", "parameterName"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"$ref", "parameterName"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string _ref = 1;
 		v1 := compiler.MapValueForKey(m, "$ref")
@@ -1213,7 +1243,7 @@ func NewRequest(in *yaml.Node, context *compiler.Context) (*Request, error) {
 			x.XRef, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for $ref: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string parameter_name = 2;
@@ -1222,7 +1252,7 @@ func NewRequest(in *yaml.Node, context *compiler.Context) (*Request, error) {
 			x.ParameterName, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for parameterName: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -1236,14 +1266,16 @@ func NewResource(in *yaml.Node, context *compiler.Context) (*Resource, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"methods", "resources"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"methods", "resources"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// Methods methods = 1;
 		v1 := compiler.MapValueForKey(m, "methods")
@@ -1274,7 +1306,7 @@ func NewResources(in *yaml.Node, context *compiler.Context) (*Resources, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedResource additional_properties = 1;
 		// MAP: Resource
@@ -1304,14 +1336,16 @@ func NewResponse(in *yaml.Node, context *compiler.Context) (*Response, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"$ref"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"$ref"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string _ref = 1;
 		v1 := compiler.MapValueForKey(m, "$ref")
@@ -1319,7 +1353,7 @@ func NewResponse(in *yaml.Node, context *compiler.Context) (*Response, error) {
 			x.XRef, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for $ref: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -1333,14 +1367,16 @@ func NewResumable(in *yaml.Node, context *compiler.Context) (*Resumable, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"multipart", "path"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"multipart", "path"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// bool multipart = 1;
 		v1 := compiler.MapValueForKey(m, "multipart")
@@ -1348,7 +1384,7 @@ func NewResumable(in *yaml.Node, context *compiler.Context) (*Resumable, error)
 			x.Multipart, ok = compiler.BoolForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for multipart: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string path = 2;
@@ -1357,7 +1393,7 @@ func NewResumable(in *yaml.Node, context *compiler.Context) (*Resumable, error)
 			x.Path, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for path: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -1371,14 +1407,16 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"$ref", "additionalProperties", "annotations", "default", "description", "enum", "enumDescriptions", "format", "id", "items", "location", "maximum", "minimum", "pattern", "properties", "readOnly", "repeated", "required", "type"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"$ref", "additionalProperties", "annotations", "default", "description", "enum", "enumDescriptions", "format", "id", "items", "location", "maximum", "minimum", "pattern", "properties", "readOnly", "repeated", "required", "type"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string id = 1;
 		v1 := compiler.MapValueForKey(m, "id")
@@ -1386,7 +1424,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Id, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for id: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string type = 2;
@@ -1395,7 +1433,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Type, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -1404,7 +1442,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string default = 4;
@@ -1413,7 +1451,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Default, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for default: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool required = 5;
@@ -1422,7 +1460,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Required, ok = compiler.BoolForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string format = 6;
@@ -1431,7 +1469,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Format, ok = compiler.StringForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for format: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string pattern = 7;
@@ -1440,7 +1478,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Pattern, ok = compiler.StringForScalarNode(v7)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for pattern: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string minimum = 8;
@@ -1449,7 +1487,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Minimum, ok = compiler.StringForScalarNode(v8)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for minimum: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string maximum = 9;
@@ -1458,7 +1496,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Maximum, ok = compiler.StringForScalarNode(v9)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for maximum: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string enum = 10;
@@ -1469,7 +1507,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.Enum = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for enum: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string enum_descriptions = 11;
@@ -1480,7 +1518,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.EnumDescriptions = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for enumDescriptions: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool repeated = 12;
@@ -1489,7 +1527,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Repeated, ok = compiler.BoolForScalarNode(v12)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for repeated: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string location = 13;
@@ -1498,7 +1536,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Location, ok = compiler.StringForScalarNode(v13)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for location: %s", compiler.Display(v13))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Schemas properties = 14;
@@ -1534,7 +1572,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.XRef, ok = compiler.StringForScalarNode(v17)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for $ref: %s", compiler.Display(v17))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Annotations annotations = 18;
@@ -1552,7 +1590,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.ReadOnly, ok = compiler.BoolForScalarNode(v19)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for readOnly: %s", compiler.Display(v19))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -1566,7 +1604,7 @@ func NewSchemas(in *yaml.Node, context *compiler.Context) (*Schemas, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedSchema additional_properties = 1;
 		// MAP: Schema
@@ -1596,14 +1634,16 @@ func NewScope(in *yaml.Node, context *compiler.Context) (*Scope, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"description"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"description"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string description = 1;
 		v1 := compiler.MapValueForKey(m, "description")
@@ -1611,7 +1651,7 @@ func NewScope(in *yaml.Node, context *compiler.Context) (*Scope, error) {
 			x.Description, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -1625,7 +1665,7 @@ func NewScopes(in *yaml.Node, context *compiler.Context) (*Scopes, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedScope additional_properties = 1;
 		// MAP: Scope
@@ -1655,14 +1695,16 @@ func NewSimple(in *yaml.Node, context *compiler.Context) (*Simple, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"multipart", "path"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"multipart", "path"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// bool multipart = 1;
 		v1 := compiler.MapValueForKey(m, "multipart")
@@ -1670,7 +1712,7 @@ func NewSimple(in *yaml.Node, context *compiler.Context) (*Simple, error) {
 			x.Multipart, ok = compiler.BoolForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for multipart: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string path = 2;
@@ -1679,7 +1721,7 @@ func NewSimple(in *yaml.Node, context *compiler.Context) (*Simple, error) {
 			x.Path, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for path: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
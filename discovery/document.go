@@ -37,5 +37,5 @@ func ParseDocument(b []byte) (*Document, error) {
 	}
 
 	root := info.Content[0]
-	return NewDocument(root, compiler.NewContext("$root", root, nil))
+	return NewDocument(root, compiler.NewContextFromNode("$root", root, nil))
 }
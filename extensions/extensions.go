@@ -15,6 +15,7 @@
 package gnostic_extension_v1
 
 import (
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/types/known/anypb"
 	"io/ioutil"
 	"log"
@@ -25,8 +26,89 @@ import (
 
 type extensionHandler func(name string, yamlInput string) (bool, proto.Message, error)
 
+// HandlerFunc compiles the YAML value of an extension into a message.
+type HandlerFunc func(yamlInput string) (proto.Message, error)
+
+// Option sets a field of the responses of an extension handler.
+type Option func(response *ExtensionHandlerResponse)
+
+// Nondeterministic is an Option that declares that a handler may return
+// different responses for the same extension value, so that compilers
+// call it for every value instead of reusing its responses.
+func Nondeterministic(response *ExtensionHandlerResponse) {
+	SetNondeterministic(response, true)
+}
+
+// nondeterministicField is the number of the nondeterministic field of
+// ExtensionHandlerResponse. The Go types of the extension protocol are
+// defined in gnostic-models, which doesn't have this field, so it is
+// written and read as an unknown field.
+const nondeterministicField = 4
+
+// SetNondeterministic sets the nondeterministic field of a response.
+func SetNondeterministic(response *ExtensionHandlerResponse, nondeterministic bool) {
+	value := uint64(0)
+	if nondeterministic {
+		value = 1
+	}
+	unknown := response.ProtoReflect().GetUnknown()
+	unknown = protowire.AppendTag(unknown, nondeterministicField, protowire.VarintType)
+	unknown = protowire.AppendVarint(unknown, value)
+	response.ProtoReflect().SetUnknown(unknown)
+}
+
+// IsNondeterministic returns the nondeterministic field of a response.
+func IsNondeterministic(response *ExtensionHandlerResponse) bool {
+	nondeterministic := false
+	b := response.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		number, kind, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+		if number == nondeterministicField && kind == protowire.VarintType {
+			value, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return false
+			}
+			// As for any scalar field, the last value wins.
+			nondeterministic = value != 0
+			b = b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(number, kind, b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+	}
+	return nondeterministic
+}
+
+// ProcessExtensionMap implements the main program of an extension handler
+// that handles the extensions named by the keys of a map with the
+// corresponding HandlerFuncs. Extensions with other names are not handled.
+func ProcessExtensionMap(handlers map[string]HandlerFunc, options ...Option) {
+	Main(mapHandler(handlers), options...)
+}
+
+// mapHandler returns an extensionHandler that dispatches extensions to
+// the HandlerFuncs of a map.
+func mapHandler(handlers map[string]HandlerFunc) extensionHandler {
+	return func(name string, yamlInput string) (bool, proto.Message, error) {
+		handler, ok := handlers[name]
+		if !ok {
+			return false, nil, nil
+		}
+		message, err := handler(yamlInput)
+		return true, message, err
+	}
+}
+
 // Main implements the main program of an extension handler.
-func Main(handler extensionHandler) {
+// The options are applied to each response.
+func Main(handler extensionHandler, options ...Option) {
 	// unpack the request
 	data, err := ioutil.ReadAll(os.Stdin)
 	if err != nil {
@@ -59,6 +141,9 @@ func Main(handler extensionHandler) {
 			response.Errors = append(response.Errors, err.Error())
 		}
 	}
+	for _, option := range options {
+		option(response)
+	}
 	responseBytes, _ := proto.Marshal(response)
 	os.Stdout.Write(responseBytes)
 }
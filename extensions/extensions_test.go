@@ -15,9 +15,13 @@
 package gnostic_extension_v1
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestExtensionHandlerWithLibraryExample(t *testing.T) {
@@ -52,3 +56,47 @@ func TestExtensionHandlerWithLibraryExample(t *testing.T) {
 		os.Remove(outputFile)
 	}
 }
+
+func TestMapHandler(t *testing.T) {
+	handler := mapHandler(map[string]HandlerFunc{
+		"x-name": func(yamlInput string) (proto.Message, error) {
+			return &wrapperspb.StringValue{Value: yamlInput}, nil
+		},
+		"x-invalid": func(yamlInput string) (proto.Message, error) {
+			return nil, errors.New("invalid value")
+		},
+	})
+	handled, message, err := handler("x-name", "fido")
+	if !handled || err != nil || message.(*wrapperspb.StringValue).Value != "fido" {
+		t.Errorf("unexpected result for x-name: %t %v %v", handled, message, err)
+	}
+	if handled, _, err = handler("x-invalid", "fido"); !handled || err == nil {
+		t.Errorf("unexpected result for x-invalid: %t %v", handled, err)
+	}
+	if handled, message, err = handler("x-other", "fido"); handled || message != nil || err != nil {
+		t.Errorf("unexpected result for x-other: %t %v %v", handled, message, err)
+	}
+}
+
+func TestNondeterministic(t *testing.T) {
+	response := &ExtensionHandlerResponse{Handled: true}
+	if IsNondeterministic(response) {
+		t.Errorf("responses are deterministic by default")
+	}
+	Nondeterministic(response)
+	data, err := proto.Marshal(response)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	received := &ExtensionHandlerResponse{}
+	if err = proto.Unmarshal(data, received); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !received.Handled || !IsNondeterministic(received) {
+		t.Errorf("the nondeterministic field was not received: %+v", received)
+	}
+	SetNondeterministic(received, false)
+	if IsNondeterministic(received) {
+		t.Errorf("the nondeterministic field was not cleared")
+	}
+}
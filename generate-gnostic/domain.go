@@ -493,12 +493,12 @@ func (domain *Domain) buildTypeForDefinitionObject(
 	schema *jsonschema.Schema) *TypeModel {
 	typeModel := NewTypeModel()
 	typeModel.Name = typeName
+	if schema.Description != nil {
+		typeModel.Description = *schema.Description
+	}
 	if schema.IsEmpty() {
 		domain.buildDefaultAccessors(typeModel, schema)
 	} else {
-		if schema.Description != nil {
-			typeModel.Description = *schema.Description
-		}
 		domain.buildTypeProperties(typeModel, schema)
 		domain.buildTypeRequirements(typeModel, schema)
 		domain.buildPatternPropertyAccessors(typeModel, schema)
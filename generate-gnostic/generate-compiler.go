@@ -117,6 +117,23 @@ func (domain *Domain) GenerateCompiler(packageName string, license string, impor
 	return code.String()
 }
 
+// isOneOfAlternative returns true if a type is one of the possibilities of
+// a oneof wrapper. Wrappers use the key checks of their possibilities to
+// find the one that matches, so these checks can't be skipped.
+func (domain *Domain) isOneOfAlternative(typeName string) bool {
+	for _, typeModel := range domain.TypeModels {
+		if !typeModel.OneOfWrapper {
+			continue
+		}
+		for _, property := range typeModel.Properties {
+			if property.Type == typeName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func escapeSlashes(pattern string) string {
 	return strings.Replace(pattern, "\\", "\\\\", -1)
 }
@@ -157,12 +174,12 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 		code.Print("      x.Value = append(x.Value, value)")
 		code.Print("    } else {")
 		code.Print("      message := fmt.Sprintf(\"has unexpected value for string array element: %%+v (%%T)\", value, value)")
-		code.Print("      errors = append(errors, compiler.NewError(context, message))")
+		code.Print("      errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 		code.Print("    }")
 		code.Print("  }")
 		code.Print("default:")
 		code.Print("  message := fmt.Sprintf(\"has unexpected value for string array: %%+v (%%T)\", in, in)")
-		code.Print("  errors = append(errors, compiler.NewError(context, message))")
+		code.Print("  errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 		code.Print("}")
 	} else if typeModel.IsItemArray {
 		if domain.Version == "v2" {
@@ -170,7 +187,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 			code.Print("m, ok := compiler.UnpackMap(in)")
 			code.Print("if !ok {")
 			code.Print("  message := fmt.Sprintf(\"has unexpected value for item array: %%+v (%%T)\", in, in)")
-			code.Print("  errors = append(errors, compiler.NewError(context, message))")
+			code.Print("  errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 			code.Print("} else {")
 			code.Print("  x.Schema = make([]*Schema, 0)")
 			code.Print("  y, err := NewSchema(m, compiler.NewContext(\"<array>\", m, context))")
@@ -184,7 +201,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 			code.Print("m, ok := compiler.UnpackMap(in)")
 			code.Print("if !ok {")
 			code.Print("  message := fmt.Sprintf(\"has unexpected value for item array: %%+v (%%T)\", in, in)")
-			code.Print("  errors = append(errors, compiler.NewError(context, message))")
+			code.Print("  errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 			code.Print("} else {")
 			code.Print("  x.SchemaOrReference = make([]*SchemaOrReference, 0)")
 			code.Print("  y, err := NewSchemaOrReference(m, compiler.NewContext(\"<array>\", m, context))")
@@ -269,9 +286,15 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 			code.Print("m, ok := compiler.UnpackMap(in)")
 			code.Print("if !ok {")
 			code.Print("  message := fmt.Sprintf(\"has unexpected value: %%+v (%%T)\", in, in)")
-			code.Print("  errors = append(errors, compiler.NewError(context, message))")
+			code.Print("  errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 			code.Print("} else {")
 		}
+		// key checks can be disabled for types that don't need them to match oneofs
+		optionalChecks := (len(typeModel.Required) > 0 || !typeModel.Open) &&
+			!oneOfWrapper && !domain.isOneOfAlternative(typeName)
+		if optionalChecks {
+			code.Print("if compiler.ValidationEnabled(context) {")
+		}
 		if len(typeModel.Required) > 0 {
 			// verify that map includes all required keys
 			keyString := ""
@@ -288,7 +311,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 			code.Print("missingKeys := compiler.MissingKeysInMap(m, requiredKeys)")
 			code.Print("if len(missingKeys) > 0 {")
 			code.Print("  message := fmt.Sprintf(\"is missing required %%s: %%+v\", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, \", \"))")
-			code.Print("  errors = append(errors, compiler.NewError(context, message))")
+			code.Print("  errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))")
 			code.Print("}")
 		}
 
@@ -330,7 +353,10 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 			code.Print("invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)")
 			code.Print("if len(invalidKeys) > 0 {")
 			code.Print("  message := fmt.Sprintf(\"has invalid %%s: %%+v\", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, \", \"))")
-			code.Print("  errors = append(errors, compiler.NewError(context, message))")
+			code.Print("  errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))")
+			code.Print("}")
+		}
+		if optionalChecks {
 			code.Print("}")
 		}
 
@@ -420,7 +446,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 					code.Print("    x.%s = compiler.StringArrayForSequenceNode(v)", fieldName)
 					code.Print("  } else {")
 					code.Print("    message := fmt.Sprintf(\"has unexpected value for %s: %%s\", compiler.Display(v%d))", propertyName, fieldNumber)
-					code.Print("    errors = append(errors, compiler.NewError(context, message))")
+					code.Print("    errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 					code.Print("}")
 
 					if propertyModel.StringEnumValues != nil {
@@ -437,7 +463,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 						stringArrayLiteral += "}"
 						code.Print("if ok && !compiler.StringArrayContainsValues(%s, x.%s) {", stringArrayLiteral, fieldName)
 						code.Print("  message := fmt.Sprintf(\"has unexpected value for %s: %%s\", compiler.Display(v%d))", propertyName, fieldNumber)
-						code.Print("  errors = append(errors, compiler.NewError(context, message))")
+						code.Print("  errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 						code.Print("}")
 					}
 
@@ -448,7 +474,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 					code.Print("  x.%s, ok = compiler.StringForScalarNode(v%d)", fieldName, fieldNumber)
 					code.Print("  if !ok {")
 					code.Print("    message := fmt.Sprintf(\"has unexpected value for %s: %%s\", compiler.Display(v%d))", propertyName, fieldNumber)
-					code.Print("    errors = append(errors, compiler.NewError(context, message))")
+					code.Print("    errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 					code.Print("  }")
 
 					if propertyModel.StringEnumValues != nil {
@@ -466,7 +492,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 
 						code.Print("if ok && !compiler.StringArrayContainsValue(%s, x.%s) {", stringArrayLiteral, fieldName)
 						code.Print("  message := fmt.Sprintf(\"has unexpected value for %s: %%s\", compiler.Display(v%d))", propertyName, fieldNumber)
-						code.Print("  errors = append(errors, compiler.NewError(context, message))")
+						code.Print("  errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 						code.Print("}")
 					}
 					code.Print("}")
@@ -479,7 +505,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 				code.Print("    x.%s = v", fieldName)
 				code.Print("  } else {")
 				code.Print("    message := fmt.Sprintf(\"has unexpected value for %s: %%s\", compiler.Display(v%d))", propertyName, fieldNumber)
-				code.Print("    errors = append(errors, compiler.NewError(context, message))")
+				code.Print("    errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 				code.Print("  }")
 				code.Print("}")
 			} else if propertyType == "int64" {
@@ -490,7 +516,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 				code.Print("    x.%s = int64(t)", fieldName)
 				code.Print("  } else {")
 				code.Print("    message := fmt.Sprintf(\"has unexpected value for %s: %%s\", compiler.Display(v%d))", propertyName, fieldNumber)
-				code.Print("    errors = append(errors, compiler.NewError(context, message))")
+				code.Print("    errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 				code.Print("  }")
 				code.Print("}")
 			} else if propertyType == "bool" {
@@ -507,7 +533,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 					code.Print("  x.%s, ok = compiler.BoolForScalarNode(v%d)", fieldName, fieldNumber)
 					code.Print("  if !ok {")
 					code.Print("    message := fmt.Sprintf(\"has unexpected value for %s: %%s\", compiler.Display(v%d))", propertyName, fieldNumber)
-					code.Print("    errors = append(errors, compiler.NewError(context, message))")
+					code.Print("    errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))")
 					code.Print("  }")
 					code.Print("}")
 				}
@@ -584,7 +610,7 @@ func (domain *Domain) generateConstructorForType(code *printer.Code, typeName st
 			if generateMatchErrors {
 				code.Print("} else {")
 				code.Print("    message := fmt.Sprintf(\"contains an invalid %s\")", typeName)
-				code.Print("    err := compiler.NewError(context, message)")
+				code.Print("    err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)")
 				code.Print("    errors = []error{err}")
 			}
 			code.Print("}")
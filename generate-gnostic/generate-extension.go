@@ -51,43 +51,37 @@ var protoOptionsForExtensions = []ProtoOption{
 }
 
 const additionalCompilerCodeWithMain = "" +
-	"func handleExtension(extensionName string, yamlInput string) (bool, proto.Message, error) {\n" +
-	"      switch extensionName {\n" +
-	"      // All supported extensions\n" +
-	"      %s\n" +
-	"      default:\n" +
-	"        return false, nil, nil\n" +
-	"       }\n" +
-	"}\n" +
-	"\n" +
 	"func main() {\n" +
-	"	gnostic_extension_v1.Main(handleExtension)\n" +
+	"	gnostic_extension_v1.ProcessExtensionMap(map[string]gnostic_extension_v1.HandlerFunc{\n" +
+	"	// All supported extensions" +
+	"	%s\n" +
+	"	})\n" +
 	"}\n"
 
-const caseStringForObjectTypes = "\n" +
-	"case \"%s\":\n" +
+const handlerStringForObjectTypes = "\n" +
+	"\"%s\": func(yamlInput string) (proto.Message, error) {\n" +
 	"var info yaml.Node\n" +
 	"err := yaml.Unmarshal([]byte(yamlInput), &info)\n" +
 	"if err != nil {\n" +
-	"  return true, nil, err\n" +
+	"  return nil, err\n" +
 	"}\n" +
 	"info = *info.Content[0]\n" +
-	"newObject, err := %s.New%s(&info, compiler.NewContext(\"$root\", &info, nil))\n" +
-	"return true, newObject, err"
+	"return %s.New%s(&info, compiler.NewContext(\"$root\", &info, nil))\n" +
+	"},"
 
-const caseStringForWrapperTypes = "\n" +
-	"case \"%s\":\n" +
+const handlerStringForWrapperTypes = "\n" +
+	"\"%s\": func(yamlInput string) (proto.Message, error) {\n" +
 	"var info yaml.Node\n" +
 	"err := yaml.Unmarshal([]byte(yamlInput), &info)\n" +
 	"if err != nil {\n" +
-	"  return true, nil, err\n" +
+	"  return nil, err\n" +
 	"}\n" +
 	"v, ok := compiler.%sForScalarNode(&info)\n" +
 	"if !ok {\n" +
-	"	return true, nil, nil\n" +
+	"	return nil, nil\n" +
 	"}\n" +
-	"newObject := &wrapperspb.%s{Value: v}\n" +
-	"return true, newObject, nil"
+	"return &wrapperspb.%s{Value: v}, nil\n" +
+	"},"
 
 // generateMainFile generates the main program for an extension.
 func generateMainFile(packageName string, license string, codeBody string, imports []string) string {
@@ -299,23 +293,23 @@ func generateExtension(schemaFile string, outDir string) error {
 	sort.Strings(extensionNameKeys)
 
 	wrapperTypeIncluded := false
-	var cases string
+	var handlers string
 	for _, extensionName := range extensionNameKeys {
 		if extensionNameToMessageName[extensionName].optionalPrimitiveTypeInfo == nil {
-			cases += fmt.Sprintf(caseStringForObjectTypes,
+			handlers += fmt.Sprintf(handlerStringForObjectTypes,
 				extensionName,
 				goPackageName,
 				extensionNameToMessageName[extensionName].schemaName)
 		} else {
 			wrapperTypeIncluded = true
-			cases += fmt.Sprintf(caseStringForWrapperTypes,
+			handlers += fmt.Sprintf(handlerStringForWrapperTypes,
 				extensionName,
 				extensionNameToMessageName[extensionName].optionalPrimitiveTypeInfo.goTypeName,
 				extensionNameToMessageName[extensionName].optionalPrimitiveTypeInfo.wrapperProtoName)
 		}
 
 	}
-	extMainCode := fmt.Sprintf(additionalCompilerCodeWithMain, cases)
+	extMainCode := fmt.Sprintf(additionalCompilerCodeWithMain, handlers)
 	imports := []string{
 		"github.com/google/gnostic/extensions",
 		"github.com/google/gnostic/compiler",
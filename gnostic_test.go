@@ -15,6 +15,11 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"os/exec"
@@ -22,7 +27,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/gnostic/compiler"
 	"github.com/google/gnostic/lib"
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+	openapi_v3 "github.com/google/gnostic/openapiv3"
 )
 
 func isURL(path string) bool {
@@ -109,6 +117,16 @@ func TestSeparateJSON(t *testing.T) {
 		"testdata/v2.0/yaml/petstore-separate/spec/swagger.text") // yaml and json results should be identical
 }
 
+// Resolving references in allOf members keeps the sibling keywords of allOf.
+func TestAllOfSiblings(t *testing.T) {
+	testNormal(t,
+		"testdata/allof-siblings/swagger.yaml",
+		"testdata/allof-siblings/swagger-resolved.json")
+	testNormal(t,
+		"testdata/allof-siblings/openapi.yaml",
+		"testdata/allof-siblings/openapi-resolved.json")
+}
+
 func TestRemotePetstoreJSON(t *testing.T) {
 	testNormal(t,
 		"https://raw.githubusercontent.com/google/gnostic/master/examples/v2.0/json/petstore.json",
@@ -294,3 +312,1488 @@ func TestDiscoveryJSON(t *testing.T) {
 		"examples/discovery/discovery-v1.json",
 		"testdata/discovery/discovery-v1.text")
 }
+
+func TestConfigFile(t *testing.T) {
+	outputFile := "testdata/config/petstore.text"
+	os.Remove(outputFile)
+	// Options are read from the configuration file.
+	g := lib.NewGnostic([]string{"gnostic", "--config=testdata/config/gnostic.yaml"})
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/v3.0/petstore.text").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+	// Options on the command line take precedence.
+	g = lib.NewGnostic([]string{"gnostic", "--config=testdata/config/gnostic.yaml", "--text-out=petstore.text"})
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	if _, err := os.Stat(outputFile); err == nil {
+		t.Errorf("Unexpected output file %s", outputFile)
+	}
+	if err := exec.Command("diff", "petstore.text", "testdata/v3.0/petstore.text").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove("petstore.text")
+	os.Remove(outputFile)
+}
+
+func TestConfigFileBooleanOptions(t *testing.T) {
+	outputFile := "testdata/config/petstore.yaml"
+	defer os.Remove(outputFile)
+	compile := func(args ...string) string {
+		g := lib.NewGnostic(append([]string{"gnostic", "--config=testdata/config/anonymize.yaml"}, args...))
+		if err := g.Main(); err != nil {
+			t.Fatalf("Compile failed: %+v", err)
+		}
+		bytes, err := ioutil.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return string(bytes)
+	}
+	anonymized := compile()
+	if !strings.Contains(anonymized, "redacted") {
+		t.Errorf("the configuration file did not anonymize the document")
+	}
+	// Boolean options set by configuration files can be turned off.
+	if output := compile("--anonymize=false"); strings.Contains(output, "redacted") {
+		t.Errorf("--anonymize=false did not override the configuration file")
+	}
+	if output := compile("--anonymize=true"); output != anonymized {
+		t.Errorf("--anonymize=true changed the output")
+	}
+	g := lib.NewGnostic([]string{"gnostic", "--config=testdata/config/anonymize.yaml", "--anonymize=maybe"})
+	if err := g.Main(); err == nil || !strings.Contains(err.Error(), "invalid value for --anonymize: maybe") {
+		t.Errorf("unexpected error for an invalid boolean value: %v", err)
+	}
+}
+
+func TestConfigFileMarkdownDocs(t *testing.T) {
+	outputDir := "testdata/config/petstore-docs"
+	defer os.RemoveAll(outputDir)
+	g := lib.NewGnostic([]string{"gnostic", "--config=testdata/config/markdown-docs.yaml"})
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	if err := exec.Command("diff", "-r", outputDir, "testdata/docs/petstore").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+}
+
+func TestConfigFileErrors(t *testing.T) {
+	g := lib.NewGnostic([]string{"gnostic", "--config=testdata/config/invalid.yaml"})
+	err := g.Main()
+	if err == nil {
+		t.Fatalf("Expected errors for invalid configuration file")
+	}
+	expected, _ := ioutil.ReadFile("testdata/config/invalid.errors")
+	if err.Error()+"\n" != string(expected) {
+		t.Errorf("Unexpected errors:\n%s\nExpected:\n%s", err.Error(), expected)
+	}
+}
+
+func TestEncodings(t *testing.T) {
+	compile := func(args ...string) (string, error) {
+		outputFile := "encoding.yaml"
+		defer os.Remove(outputFile)
+		g := lib.NewGnostic(append([]string{"gnostic", "--errors-out=!", "--yaml-out=" + outputFile}, args...))
+		if err := g.Main(); err != nil {
+			return "", err
+		}
+		bytes, err := ioutil.ReadFile(outputFile)
+		return string(bytes), err
+	}
+	expected, err := compile("--resolve-refs", "testdata/encoding/openapi.yaml")
+	if err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	// The $ref target, pet.yaml, is encoded in UTF-16LE.
+	for _, test := range []struct {
+		input string
+		err   string
+	}{
+		{"testdata/encoding/openapi-bom.yaml", ""},
+		{"testdata/encoding/openapi-utf16le.yaml", ""},
+		{"testdata/encoding/openapi-utf16be.yaml", ""},
+		{"testdata/encoding/openapi-utf16be-nobom.yaml", ""},
+		{"testdata/encoding/openapi.yaml.gz", ""},
+		{"testdata/encoding/openapi-utf32le.yaml", "testdata/encoding/openapi-utf32le.yaml: unsupported encoding UTF-32LE"},
+		{"testdata/encoding/openapi-latin1.yaml", "testdata/encoding/openapi-latin1.yaml: unsupported encoding: the text is not valid UTF-8"},
+	} {
+		output, err := compile("--resolve-refs", test.input)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s: unexpected error %v (expected %s)", test.input, err, test.err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: compile failed: %+v", test.input, err)
+		} else if output != expected {
+			t.Errorf("%s: unexpected output:\n%s", test.input, output)
+		}
+	}
+	// Descriptions read from stdin are decoded in the same way.
+	stdin := os.Stdin
+	defer func() { os.Stdin = stdin }()
+	file, err := os.Open("testdata/encoding/openapi-utf16le.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer file.Close()
+	os.Stdin = file
+	if output, err := compile("-"); err != nil {
+		t.Errorf("stdin: compile failed: %+v", err)
+	} else if output != expected {
+		t.Errorf("stdin: unexpected output:\n%s", output)
+	}
+	// Compressed descriptions are recognized by their contents.
+	gzipped, err := os.Open("testdata/encoding/openapi.yaml.gz")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer gzipped.Close()
+	os.Stdin = gzipped
+	unresolved, err := compile("testdata/encoding/openapi.yaml")
+	if err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	if output, err := compile("-"); err != nil {
+		t.Errorf("gzipped stdin: compile failed: %+v", err)
+	} else if output != unresolved {
+		t.Errorf("gzipped stdin: unexpected output:\n%s", output)
+	}
+}
+
+func TestGzipOutput(t *testing.T) {
+	outputFile := "petstore.json.gz"
+	defer os.Remove(outputFile)
+	args := []string{"gnostic", "--json-out=" + outputFile, "examples/v3.0/yaml/petstore.yaml"}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	bytes, err := ioutil.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !compiler.IsGzipped(bytes) {
+		t.Fatalf("%s is not compressed", outputFile)
+	}
+	bytes, err = compiler.Decompress(outputFile, bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, err := openapi_v3.ParseDocument(bytes); err != nil {
+		t.Errorf("%s is not a valid description: %+v", outputFile, err)
+	}
+	// Compressed outputs can be read again.
+	args = []string{"gnostic", "--yaml-out=!", outputFile}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Errorf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+}
+
+func TestMockServer(t *testing.T) {
+	bytes, err := ioutil.ReadFile("examples/v2.0/yaml/petstore.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, err := openapi_v2.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// OpenAPI v2 documents are converted to v3.
+	handler, err := lib.NewMockHandler(document)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/v1/pets/12", nil))
+	expected := "[\n  {\n    \"id\": 0,\n    \"name\": \"string\",\n    \"tag\": \"string\"\n  }\n]\n"
+	if recorder.Code != 200 || recorder.Body.String() != expected {
+		t.Errorf("Unexpected response %d:\n%s", recorder.Code, recorder.Body.String())
+	}
+	// Ports are validated before the source is read.
+	g := lib.NewGnostic([]string{"gnostic", "generate-mock-server", "--input", "examples/v2.0/yaml/petstore.yaml", "--port", "http"})
+	if err := g.Main(); err == nil || err.Error() != "invalid port: http" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	// Configuration files set the address literally.
+	g = lib.NewGnostic([]string{"gnostic", "--config=testdata/config/mock-server.yaml"})
+	if err := g.Main(); err == nil || err.Error() != "listen tcp: address -1: invalid port" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestHeaderComment(t *testing.T) {
+	yamlFile := "header.yaml"
+	jsonFile := "header.json"
+	textFile := "header.text"
+	args := []string{
+		"gnostic",
+		"--header-comment-file=testdata/header/header.txt",
+		"--yaml-out=" + yamlFile,
+		"--json-out=" + jsonFile,
+		"--text-out=" + textFile,
+		"examples/v3.0/yaml/petstore.yaml"}
+	g := lib.NewGnostic(args)
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	comment := "# Copyright 2023 Example Corp.\n#\n# Generated by gnostic. DO NOT EDIT.\n"
+	for _, filename := range []string{yamlFile, textFile} {
+		bytes, _ := ioutil.ReadFile(filename)
+		if !strings.HasPrefix(string(bytes), comment+"openapi: ") {
+			t.Errorf("Missing header comment in %s:\n%s", filename, bytes)
+		}
+	}
+	bytes, _ := ioutil.ReadFile(jsonFile)
+	if !strings.HasPrefix(string(bytes), "{\n  \"x-comment\": \"Copyright 2023 Example Corp.\\n\\nGenerated by gnostic. DO NOT EDIT.\",\n") {
+		t.Errorf("Missing x-comment in %s:\n%s", jsonFile, bytes)
+	}
+	// The comment keeps the document valid.
+	if _, err := openapi_v3.ParseDocument(bytes); err != nil {
+		t.Errorf("Invalid document %s: %+v", jsonFile, err)
+	}
+	os.Remove(yamlFile)
+	os.Remove(jsonFile)
+	os.Remove(textFile)
+}
+
+func TestTraceOutput(t *testing.T) {
+	traceFile := "trace.json"
+	os.Remove(traceFile)
+	g := lib.NewGnostic([]string{
+		"gnostic",
+		"--trace-out=" + traceFile,
+		"--text-out=!",
+		"--resolve-refs",
+		"examples/v2.0/yaml/petstore-separate/spec/swagger.yaml"})
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	files := g.Trace().Files()
+	if len(files) != 5 || files[0] != "examples/v2.0/yaml/petstore-separate/spec/swagger.yaml" {
+		t.Errorf("Unexpected files in trace: %+v", files)
+	}
+	bytes, err := ioutil.ReadFile(traceFile)
+	if err != nil {
+		t.Fatalf("Missing trace: %+v", err)
+	}
+	for _, phase := range []string{"parse", "model", "references", "serialization"} {
+		if !strings.Contains(string(bytes), `"name": "`+phase+`"`) {
+			t.Errorf("Missing phase %s in trace:\n%s", phase, bytes)
+		}
+	}
+	os.Remove(traceFile)
+}
+
+func TestTraceDisabled(t *testing.T) {
+	g := lib.NewGnostic([]string{
+		"gnostic",
+		"--text-out=!",
+		"examples/v2.0/yaml/petstore.yaml"})
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	if g.Trace() != nil {
+		t.Errorf("Unexpected trace without --trace-out: %+v", g.Trace().Files())
+	}
+}
+
+func TestDeprecatedOperationWarnings(t *testing.T) {
+	bytes, err := ioutil.ReadFile("testdata/deprecated/openapi.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, err := openapi_v3.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	if !document.Paths.Path[0].Value.Get.Deprecated || document.Paths.Path[1].Value.Get.Deprecated {
+		t.Errorf("Deprecated operations were not parsed")
+	}
+	g := lib.NewGnostic([]string{
+		"gnostic",
+		"--text-out=!",
+		"testdata/deprecated/openapi.yaml"})
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	warnings := g.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("Unexpected warnings: %+v", warnings)
+	}
+	if warnings[0].Code != "DEPRECATED_WITHOUT_DESCRIPTION" ||
+		strings.Join(warnings[0].Keys, ".") != "paths./v1/pets.get" {
+		t.Errorf("Unexpected warning: %+v", warnings[0])
+	}
+}
+
+func TestSkipValidation(t *testing.T) {
+	outputFile := "petstore-badproperties.yaml"
+	args := []string{
+		"gnostic",
+		"--skip-validation",
+		"--yaml-out=" + outputFile,
+		"examples/errors/petstore-badproperties.yaml"}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/skip-validation/petstore-badproperties.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+	g := lib.NewGnostic([]string{
+		"gnostic",
+		"--skip-validation",
+		"--text-out=!",
+		"testdata/deprecated/openapi.yaml"})
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	if warnings := g.Warnings(); len(warnings) != 0 {
+		t.Errorf("Unexpected warnings: %+v", warnings)
+	}
+}
+
+func TestDuplicateOperationIDWarnings(t *testing.T) {
+	g := lib.NewGnostic([]string{
+		"gnostic",
+		"--text-out=!",
+		"testdata/duplicates/openapi.yaml"})
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	warnings := g.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("Unexpected warnings: %+v", warnings)
+	}
+	if warnings[0].Code != compiler.CodeDuplicateOperationID ||
+		warnings[0].Text != "operationId listPets is also used by GET /pets" ||
+		strings.Join(warnings[0].Keys, ".") != "paths./v2/pets.get.operationId" {
+		t.Errorf("Unexpected warning: %+v", warnings[0])
+	}
+}
+
+func TestSchemaConsistencyWarnings(t *testing.T) {
+	for input, expected := range map[string][]string{
+		"testdata/schema-consistency/openapi.yaml": {
+			`components.schemas.Pet.properties.kind.default: default "bird" is not one of the enum values`,
+			`components.schemas.Pet.properties.age.default: default "young" is not of type integer`,
+			`components.schemas.Pet.properties.name: minLength 8 is greater than maxLength 4`,
+			`components.parameters.Verbose.example: example 1 is not of type boolean`,
+			`paths./pets.get.parameters.0.schema: minimum 10 is greater than maximum 5`,
+			`paths./pets.get.parameters.0.example: example "ten" is not of type integer`,
+			`paths./pets.get.responses.200.content.application/json.schema: minItems 3 is greater than maxItems 2`,
+		},
+		"testdata/schema-consistency/swagger.yaml": {
+			`definitions.Pet.properties.name: minLength 8 is greater than maxLength 4`,
+			`paths./pets.get.parameters.0.default: default "ten" is not of type integer`,
+			`paths./pets.get.parameters.0: minimum 10 is greater than maximum 5`,
+			`paths./pets.get.parameters.1.items.default: default "bird" is not one of the enum values`,
+			`paths./pets.get.responses.200.schema: minItems 3 is greater than maxItems 2`,
+			`paths./pets.get.responses.200.headers.X-Count.default: default 1.5 is not of type integer`,
+		},
+	} {
+		g := lib.NewGnostic([]string{"gnostic", "--text-out=!", input})
+		if err := g.Main(); err != nil {
+			t.Fatalf("Compile failed: %+v", err)
+		}
+		var got []string
+		for _, warning := range g.Warnings() {
+			if warning.Code != compiler.CodeInconsistentSchema {
+				t.Errorf("Unexpected warning: %+v", warning)
+			}
+			got = append(got, strings.Join(warning.Keys, ".")+": "+warning.Text)
+		}
+		if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Unexpected warnings for %s:\n%s", input, strings.Join(got, "\n"))
+		}
+	}
+}
+
+func TestWarningProvenance(t *testing.T) {
+	// Warnings are printed on stderr.
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	g := lib.NewGnostic([]string{"gnostic", "--text-out=!", "testdata/provenance/openapi.yaml"})
+	err = g.Main()
+	os.Stderr = stderr
+	w.Close()
+	output, _ := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	expected := `warning in components.schemas.Pet.properties.kind.default of testdata/provenance/openapi.yaml: default "bird" is not one of the enum values (from pets/common.yaml#/components/schemas/Animal/properties/kind/default)
+warning in components.schemas.Owner.properties.name of testdata/provenance/openapi.yaml: minLength 8 is greater than maxLength 4
+`
+	if string(output) != expected {
+		t.Errorf("Unexpected warnings:\n%s", output)
+	}
+}
+
+func TestServerWarnings(t *testing.T) {
+	for input, expected := range map[string][]string{
+		"testdata/servers/valid.yaml": nil,
+		"testdata/servers/undeclared-variable.yaml": {
+			`servers.0.url: variable region is not declared`,
+		},
+		"testdata/servers/unused-variable.yaml": {
+			`paths./pets.servers.0.variables.region: variable region is not used in the URL`,
+		},
+		"testdata/servers/enum-default.yaml": {
+			`paths./pets.get.servers.0.variables.region.default: default "asia" is not one of the enum values`,
+		},
+		"testdata/servers/malformed-url.yaml": {
+			`servers.0.url: server URL contains whitespace`,
+			`servers.1.url: server URL has an unclosed { at offset 8`,
+			`servers.2.url: server URL has an unexpected } at offset 8`,
+			`servers.3.url: server URL has an empty variable name at offset 8`,
+			`servers.4.url: URL https://[::1/v1 is malformed`,
+		},
+		"testdata/servers/missing-scheme.yaml": {
+			`servers.0.url: absolute URL api.example.com/v1 has no scheme`,
+			`servers.1.url: absolute URL //api.example.com/v1 has no scheme`,
+			`servers.2.url: absolute URL localhost:8080 has no scheme`,
+			`servers.3.url: absolute URL https:///v1 has no host`,
+		},
+	} {
+		g := lib.NewGnostic([]string{"gnostic", "--text-out=!", input})
+		if err := g.Main(); err != nil {
+			t.Fatalf("Compile failed: %+v", err)
+		}
+		var got []string
+		for _, warning := range g.Warnings() {
+			if warning.Code != compiler.CodeInvalidServer {
+				t.Errorf("Unexpected warning: %+v", warning)
+			}
+			got = append(got, strings.Join(warning.Keys, ".")+": "+warning.Text)
+		}
+		if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Unexpected warnings for %s:\n%s", input, strings.Join(got, "\n"))
+		}
+	}
+}
+
+func TestLintBaseline(t *testing.T) {
+	baselineFile := "lint-baseline.json"
+	os.Remove(baselineFile)
+	run := func(args ...string) error {
+		return lib.NewGnostic(append([]string{"gnostic"}, args...)).Main()
+	}
+	// Without a baseline, the deprecated operations fail.
+	err := run("--fail-on=warning", "testdata/lint-baseline/openapi.yaml")
+	if err == nil || err.Error() != "2 new findings at level warning or above" {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+	if err := run("--fail-on=error", "testdata/lint-baseline/openapi.yaml"); err != nil {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+	// The baseline records the current findings.
+	if err := run("--lint-baseline="+baselineFile, "--lint-baseline-write", "testdata/lint-baseline/openapi.yaml"); err != nil {
+		t.Fatalf("Unable to write baseline: %+v", err)
+	}
+	bytes, err := ioutil.ReadFile(baselineFile)
+	if err != nil {
+		t.Fatalf("Missing baseline: %+v", err)
+	}
+	if !strings.Contains(string(bytes), `"path": "/paths/~1v1~1pets/get"`) ||
+		!strings.Contains(string(bytes), `"rule": "DEPRECATED_WITHOUT_DESCRIPTION"`) {
+		t.Errorf("Unexpected baseline:\n%s", bytes)
+	}
+	// Baselined findings don't fail, even if the document is reordered.
+	for _, source := range []string{"openapi.yaml", "reordered.yaml"} {
+		if err := run("--lint-baseline="+baselineFile, "--fail-on=warning", "testdata/lint-baseline/"+source); err != nil {
+			t.Errorf("Unexpected error for %s: %+v", source, err)
+		}
+	}
+	// New findings fail.
+	err = run("--lint-baseline="+baselineFile, "--fail-on=warning", "testdata/lint-baseline/changed.yaml")
+	if err == nil || err.Error() != "1 new finding at level warning or above" {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+	// Pruning removes the entries that no longer match findings.
+	if err := run("prune-baseline", "testdata/lint-baseline/changed.yaml", "--baseline", baselineFile); err != nil {
+		t.Fatalf("Unable to prune baseline: %+v", err)
+	}
+	bytes, _ = ioutil.ReadFile(baselineFile)
+	if strings.Count(string(bytes), `"rule":`) != 1 || strings.Contains(string(bytes), "owners") {
+		t.Errorf("Unexpected pruned baseline:\n%s", bytes)
+	}
+	if err := run("prune-baseline", "testdata/lint-baseline/changed.yaml"); err == nil {
+		t.Errorf("Missing error for prune-baseline without --baseline")
+	}
+	os.Remove(baselineFile)
+	// Configuration files can write baselines, which are resolved
+	// relative to the configuration file.
+	baselineFile = "testdata/config/lint-baseline.json"
+	defer os.Remove(baselineFile)
+	if err := run("--config=testdata/config/lint-baseline.yaml"); err != nil {
+		t.Fatalf("Unable to write baseline: %+v", err)
+	}
+	bytes, err = ioutil.ReadFile(baselineFile)
+	if err != nil {
+		t.Fatalf("Missing baseline: %+v", err)
+	}
+	if strings.Count(string(bytes), `"rule":`) != 2 {
+		t.Errorf("Unexpected baseline:\n%s", bytes)
+	}
+}
+
+func TestValidateExamples(t *testing.T) {
+	g := lib.NewGnostic([]string{"gnostic", "validate-examples", "testdata/validate-examples/openapi.yaml"})
+	err := g.Main()
+	if err == nil || err.Error() != "1 new finding at level error or above" {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+	warnings := g.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("Unexpected warnings: %+v", warnings)
+	}
+	if warnings[0].Code != compiler.CodeInvalidExample ||
+		warnings[0].Text != "/1/id: string is not of type integer" ||
+		strings.Join(warnings[0].Keys, ".") != "paths./pets.get.responses.200.content.application/json.example" {
+		t.Errorf("Unexpected warning: %+v", warnings[0])
+	}
+	g = lib.NewGnostic([]string{"gnostic", "validate-examples", "examples/v2.0/yaml/petstore.yaml"})
+	if err := g.Main(); err == nil {
+		t.Errorf("validate-examples accepted an OpenAPI v2 document")
+	}
+}
+
+func TestCheckGRPC(t *testing.T) {
+	args := []string{"gnostic", "check-grpc", "testdata/check-grpc/openapi.yaml", "--descriptors", "testdata/check-grpc/descriptors.pb"}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+	g := lib.NewGnostic([]string{"gnostic", "check-grpc", "testdata/check-grpc/mismatch.yaml", "--descriptors=testdata/check-grpc/descriptors.pb"})
+	err := g.Main()
+	if err == nil || err.Error() != "3 new findings at level error or above" {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+	got := make([]string, 0)
+	for _, warning := range g.Warnings() {
+		if warning.Code == compiler.CodeGRPCMismatch {
+			got = append(got, strings.Join(warning.Keys, ".")+": "+warning.Text)
+		}
+	}
+	want := []string{
+		"paths./v1/shelves/{shelf_id}/books.get.parameters.1: query parameter type doesn't match (openapi: query parameter page_size: string, grpc: field library.v1.ListBooksRequest.page_size: int32 (integer))",
+		"paths./v1/shelves/{shelf_id}/books.get.parameters.3: query parameter is not a field of the request (openapi: query parameter filter: string, grpc: library.v1.ListBooksRequest)",
+		"paths./v1/shelf/{shelf}.get: path template doesn't match (openapi: GET /v1/shelf/{shelf}, grpc: GET /v1/{name=shelves/*})",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected mismatches:\n%s", strings.Join(got, "\n"))
+	}
+	if err := lib.NewGnostic([]string{"gnostic", "check-grpc", "testdata/check-grpc/openapi.yaml"}).Main(); err == nil {
+		t.Errorf("Missing error for check-grpc without --descriptors")
+	}
+}
+
+func TestParseOptionsLimits(t *testing.T) {
+	for _, options := range []compiler.ParseOptions{
+		{MaxNodes: 10},
+		{MaxFetchedBytes: 100},
+	} {
+		g := lib.NewGnostic([]string{"gnostic", "examples/v3.0/yaml/petstore.yaml", "--errors-out=-"})
+		g.SetParseOptions(options)
+		if err := g.Main(); !compiler.LimitExceeded(err) {
+			t.Errorf("Unexpected error for %+v: %+v", options, err)
+		}
+	}
+	g := lib.NewGnostic([]string{"gnostic", "examples/v3.0/yaml/petstore.yaml", "--errors-out=-"})
+	g.SetParseOptions(compiler.ParseOptions{MaxNodes: -1, MaxFetchedBytes: -1})
+	if err := g.Main(); err != nil {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+}
+
+// testLogger records the messages of a compilation.
+type testLogger struct {
+	messages []string
+}
+
+func (l *testLogger) Debugf(format string, args ...interface{}) {
+	l.messages = append(l.messages, "debug "+fmt.Sprintf(format, args...))
+}
+
+func (l *testLogger) Infof(format string, args ...interface{}) {
+	l.messages = append(l.messages, "info "+fmt.Sprintf(format, args...))
+}
+
+func (l *testLogger) Warnf(format string, args ...interface{}) {
+	l.messages = append(l.messages, "warn "+fmt.Sprintf(format, args...))
+}
+
+func TestParseOptionsLogger(t *testing.T) {
+	logger := &testLogger{}
+	previous := compiler.CurrentLogger()
+	g := lib.NewGnostic([]string{"gnostic", "testdata/encoding/openapi.yaml", "--resolve-refs", "--yaml-out=!"})
+	g.SetParseOptions(compiler.ParseOptions{Logger: logger})
+	if err := g.Main(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	messages := strings.Join(logger.messages, "\n")
+	for _, want := range []string{
+		"debug reading testdata/encoding/openapi.yaml",
+		"debug reading testdata/encoding/pet.yaml",
+	} {
+		if !strings.Contains(messages, want) {
+			t.Errorf("missing %q in\n%s", want, messages)
+		}
+	}
+	if compiler.CurrentLogger() != previous {
+		t.Errorf("Main did not restore the logger")
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	outputFile := "anonymous.yaml"
+	args := []string{
+		"gnostic",
+		"anonymize",
+		"testdata/anonymize/openapi.yaml",
+		"--output",
+		outputFile}
+	g := lib.NewGnostic(args)
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/anonymize/anonymous.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+	g = lib.NewGnostic([]string{"gnostic", "anonymize", "examples/v2.0/yaml/petstore.yaml", "--output=" + outputFile})
+	if err := g.Main(); err == nil {
+		t.Errorf("Anonymize accepted an OpenAPI v2 document")
+	}
+	os.Remove(outputFile)
+}
+
+func TestOpenAPI2Proto(t *testing.T) {
+	outputFile := "bookstore.proto"
+	args := []string{
+		"gnostic",
+		"openapi2proto",
+		"--input", "examples/v3.0/yaml/bookstore.yaml",
+		"--output", outputFile,
+		"--package", "bookstore"}
+	g := lib.NewGnostic(args)
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "conversions/testdata/proto/bookstore.proto").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+	g = lib.NewGnostic([]string{"gnostic", "openapi2proto", "--input", "examples/v3.0/yaml/bookstore.yaml", "--output"})
+	if err := g.Main(); err == nil {
+		t.Errorf("openapi2proto accepted a missing output")
+	}
+}
+
+func TestGenerateClient(t *testing.T) {
+	outputDir := "bookstore-client"
+	args := []string{
+		"gnostic",
+		"generate-client",
+		"--language=go",
+		"--input", "examples/v3.0/yaml/bookstore.yaml",
+		"--output", outputDir,
+		"--package", "bookstore"}
+	g := lib.NewGnostic(args)
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	defer os.RemoveAll(outputDir)
+	bytes, err := ioutil.ReadFile(filepath.Join(outputDir, "client.go"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, s := range []string{"package bookstore\n", "func (c *Client) GetShelf(ctx context.Context, parameters *GetShelfParameters) (*GetShelfResult, error) {"} {
+		if !strings.Contains(string(bytes), s) {
+			t.Errorf("client.go doesn't contain %q", s)
+		}
+	}
+	g = lib.NewGnostic([]string{"gnostic", "generate-client", "--language=rust", "--input", "examples/v3.0/yaml/bookstore.yaml"})
+	if err := g.Main(); err == nil {
+		t.Errorf("generate-client accepted an unsupported language")
+	}
+}
+
+func TestCodeSamples(t *testing.T) {
+	outputFile := "petstore.yaml"
+	args := []string{
+		"gnostic",
+		"--code-samples=curl,go",
+		"--yaml-out=" + outputFile,
+		"examples/v3.0/yaml/petstore.yaml"}
+	g := lib.NewGnostic(args)
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/code-samples/petstore.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+}
+
+func TestDeps(t *testing.T) {
+	outputFile := "deps.txt"
+	for _, test := range []struct {
+		invocation string
+		expected   string
+	}{
+		{"schema=Pet:" + outputFile, "GET /pets\nGET /pets/{petId}\n"},
+		{outputFile, "Pet: GET /pets, GET /pets/{petId}\nPets: GET /pets, GET /pets/{petId}\nError: GET /pets, POST /pets, GET /pets/{petId}\n"},
+	} {
+		args := []string{"gnostic", "--deps-out=" + test.invocation, "examples/v3.0/yaml/petstore.yaml"}
+		if err := lib.NewGnostic(args).Main(); err != nil {
+			t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+		}
+		bytes, err := ioutil.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if string(bytes) != test.expected {
+			t.Errorf("Unexpected output for %s:\n%s", test.invocation, bytes)
+		}
+		os.Remove(outputFile)
+	}
+}
+
+func TestStats(t *testing.T) {
+	outputFile := "stats.csv"
+	for _, test := range []struct {
+		source   string
+		expected string
+	}{
+		{"examples/v2.0/yaml/petstore.yaml", "examples/v2.0/yaml/petstore.yaml,2,3,3,0,0\n"},
+		{"testdata/library-example-with-ext.json", "testdata/library-example-with-ext.json,1,1,2,9,0\n"},
+		{"testdata/deprecated/openapi.yaml", "testdata/deprecated/openapi.yaml,2,3,0,0,2\n"},
+	} {
+		args := []string{"gnostic", "stats", test.source, "--format=csv", "--output=" + outputFile}
+		if err := lib.NewGnostic(args).Main(); err != nil {
+			t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+		}
+		bytes, err := ioutil.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		expected := "file,paths,operations,schemas,extensions,deprecated_operations\n" + test.expected
+		if string(bytes) != expected {
+			t.Errorf("Unexpected output for %s:\n%s", test.source, bytes)
+		}
+		os.Remove(outputFile)
+	}
+}
+
+func TestExportExtensions(t *testing.T) {
+	outputFile := "x-owner.json"
+	args := []string{
+		"gnostic",
+		"export-extensions",
+		"--extension=x-owner",
+		"--input", "testdata/extensions/openapi.yaml",
+		"--output", outputFile}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/extensions/x-owner.json").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+}
+
+func TestGenerateDocs(t *testing.T) {
+	for _, name := range []string{"bookstore", "petstore"} {
+		outputDir := name + "-docs"
+		args := []string{
+			"gnostic",
+			"generate-docs",
+			"--format=markdown",
+			"--input", "examples/v3.0/yaml/" + name + ".yaml",
+			"--output", outputDir}
+		g := lib.NewGnostic(args)
+		if err := g.Main(); err != nil {
+			t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+		}
+		if err := exec.Command("diff", "-r", outputDir, "testdata/docs/"+name).Run(); err != nil {
+			t.Fatalf("Diff failed: %+v", err)
+		}
+		os.RemoveAll(outputDir)
+	}
+	g := lib.NewGnostic([]string{"gnostic", "generate-docs", "--format=html", "--input", "examples/v3.0/yaml/bookstore.yaml"})
+	if err := g.Main(); err == nil {
+		t.Errorf("generate-docs accepted an unsupported format")
+	}
+}
+
+func TestOutputHTML(t *testing.T) {
+	outputFile := "petstore.html"
+	args := []string{
+		"gnostic",
+		"--output-html",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--output", outputFile}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	defer os.Remove(outputFile)
+	if err := exec.Command("diff", outputFile, "testdata/docs/petstore.html").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	g := lib.NewGnostic([]string{"gnostic", "--output-html", "examples/v3.0/yaml/petstore.yaml", "--output"})
+	if err := g.Main(); err == nil {
+		t.Errorf("--output-html accepted a missing --output value")
+	}
+	// After the source, --output-html invokes a plugin named output-html.
+	g = lib.NewGnostic([]string{"gnostic", "examples/v3.0/yaml/petstore.yaml", "--output-html"})
+	if err := g.Main(); err == nil {
+		t.Errorf("--output-html was expanded after the source")
+	}
+}
+
+func TestOutputAsyncAPI(t *testing.T) {
+	outputFile := "petstore-asyncapi.yaml"
+	args := []string{
+		"gnostic",
+		"--output-asyncapi",
+		"examples/v3.0/yaml/petstore.yaml",
+		"--output", outputFile}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	defer os.Remove(outputFile)
+	if err := exec.Command("diff", outputFile, "testdata/asyncapi/petstore.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	// --asyncapi-out writes the same document.
+	args = []string{"gnostic", "examples/v3.0/yaml/petstore.yaml", "--asyncapi-out=" + outputFile}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/asyncapi/petstore.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+}
+
+func TestJSONOutStyle(t *testing.T) {
+	outputFile := "petstore-canonical.json"
+	defer os.Remove(outputFile)
+	// The yaml and json versions of a document have the same canonical form.
+	for _, inputFile := range []string{
+		"examples/v3.0/yaml/petstore.yaml",
+		"examples/v3.0/json/petstore.json",
+	} {
+		args := []string{"gnostic", inputFile, "--json-out-style=canonical", "--json-out=" + outputFile}
+		if err := lib.NewGnostic(args).Main(); err != nil {
+			t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+		}
+		if err := exec.Command("diff", outputFile, "testdata/json-style/petstore-canonical.json").Run(); err != nil {
+			t.Fatalf("Diff failed for %s: %+v", inputFile, err)
+		}
+	}
+	// The style can also be given as a parameter of --json-out.
+	args := []string{"gnostic", "examples/v3.0/yaml/petstore.yaml", "--json-out=json-style=compact:" + outputFile}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	bytes, err := ioutil.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !strings.HasPrefix(string(bytes), `{"openapi":"3.0","info":{`) || strings.Count(string(bytes), "\n") != 1 {
+		t.Errorf("Unexpected compact output: %s", bytes)
+	}
+	args = []string{"gnostic", "examples/v3.0/yaml/petstore.yaml", "--json-out-style=sorted", "--json-out=" + outputFile}
+	if err := lib.NewGnostic(args).Main(); err == nil {
+		t.Errorf("Expected an error for an unknown style")
+	}
+}
+
+func TestMarkdownTemplates(t *testing.T) {
+	templatesDir := "markdown-templates"
+	outputDir := "petstore-docs"
+	os.MkdirAll(templatesDir, 0755)
+	defer os.RemoveAll(templatesDir)
+	defer os.RemoveAll(outputDir)
+	if err := ioutil.WriteFile(filepath.Join(templatesDir, "index.tmpl"), []byte("{{.Title}} {{.Version}}\n"), 0644); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	args := []string{
+		"gnostic",
+		"--markdown-docs=" + outputDir,
+		"--markdown-templates=" + templatesDir,
+		"--markdown-depth=1",
+		"examples/v3.0/yaml/petstore.yaml"}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(outputDir, "README.md"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if string(data) != "OpenAPI Petstore 1.0.0\n" {
+		t.Errorf("Unexpected README.md: %q", data)
+	}
+	if err := exec.Command("diff", filepath.Join(outputDir, "pets.md"), "testdata/docs/petstore/pets.md").Run(); err != nil {
+		t.Errorf("Diff failed: %+v", err)
+	}
+	args[3] = "--markdown-depth=0"
+	if err := lib.NewGnostic(args).Main(); err == nil || !strings.Contains(err.Error(), "invalid value for --markdown-depth") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestVersionHeader(t *testing.T) {
+	outputFile := "petstore.yaml"
+	args := []string{
+		"gnostic",
+		"--version-header=X-API-Version",
+		"--version-header-description=Requested version of {{.Title}}, such as {{.Version}}.",
+		"--yaml-out=" + outputFile,
+		"examples/v3.0/yaml/petstore.yaml"}
+	g := lib.NewGnostic(args)
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/version-header/petstore.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+	args[2] = "--version-header-description={{.Unknown}}"
+	if err := lib.NewGnostic(args).Main(); err == nil || !strings.Contains(err.Error(), "invalid --version-header-description") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	os.Remove(outputFile)
+}
+
+func TestConfigFileVersionHeader(t *testing.T) {
+	outputFile := "testdata/config/petstore.yaml"
+	defer os.Remove(outputFile)
+	// The header name and description are copied literally, not
+	// resolved relative to the configuration file like paths.
+	g := lib.NewGnostic([]string{"gnostic", "--config=testdata/config/version-header.yaml"})
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/version-header/petstore.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+}
+
+func TestDedupeComponents(t *testing.T) {
+	outputFile := "deduped.yaml"
+	args := []string{
+		"gnostic",
+		"--dedupe-components",
+		"--dedupe-threshold=1",
+		"--yaml-out=" + outputFile,
+		"testdata/dedupe/openapi.yaml"}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/dedupe/deduped.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	// Deduplicating a deduplicated document changes nothing.
+	args[4] = "testdata/dedupe/deduped.yaml"
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/dedupe/deduped.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+}
+
+func TestDuplicateComponentWarnings(t *testing.T) {
+	g := lib.NewGnostic([]string{
+		"gnostic",
+		"--lint-duplicates",
+		"--text-out=!",
+		"testdata/dedupe/openapi.yaml"})
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	warnings := g.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("Unexpected warnings: %+v", warnings)
+	}
+	if warnings[0].Code != compiler.CodeDuplicateInlineComponent ||
+		warnings[0].Text != "response is defined inline 3 times and could be replaced with references to #/components/responses/NotFound" ||
+		strings.Join(warnings[0].Keys, ".") != "paths./books.get.responses.404" {
+		t.Errorf("Unexpected warning: %+v", warnings[0])
+	}
+}
+
+func TestResolveRefsSmart(t *testing.T) {
+	outputFile := "smart.yaml"
+	args := []string{
+		"gnostic",
+		"--resolve-refs=smart",
+		"--yaml-out=" + outputFile,
+		"testdata/resolve-smart/openapi.yaml"}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/resolve-smart/smart.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	// The output has no external references and compiles again.
+	args[len(args)-1] = outputFile
+	args[2] = "--yaml-out=resolved.yaml"
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", "resolved.yaml", "testdata/resolve-smart/smart.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+	os.Remove("resolved.yaml")
+	// Only OpenAPI v3 documents are supported.
+	args = []string{"gnostic", "--resolve-refs=smart", "--yaml-out=" + outputFile, "examples/v2.0/yaml/petstore.yaml"}
+	if err := lib.NewGnostic(args).Main(); err == nil {
+		t.Errorf("Expected an error for an OpenAPI v2 document")
+	}
+	os.Remove(outputFile)
+}
+
+func TestStripExtension(t *testing.T) {
+	outputFile := "stripped.yaml"
+	args := []string{
+		"gnostic",
+		"--strip-extension=x-internal",
+		"--strip-extensions-matching=^x-internal-",
+		"--yaml-out=" + outputFile,
+		"testdata/strip/openapi.yaml"}
+	g := lib.NewGnostic(args)
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/strip/stripped.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+}
+
+func TestImportComponents(t *testing.T) {
+	outputFile := "imported.yaml"
+	args := []string{
+		"gnostic",
+		"--import", "components=testdata/import/common.yaml,prefix=Common",
+		"--yaml-out=" + outputFile,
+		"testdata/import/openapi.yaml"}
+	g := lib.NewGnostic(args)
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/import/imported.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+
+	// Without a prefix, the Error schemas collide.
+	args = []string{
+		"gnostic",
+		"--import=components=testdata/import/common.yaml",
+		"--yaml-out=" + outputFile,
+		"testdata/import/openapi.yaml"}
+	err := lib.NewGnostic(args).Main()
+	if err == nil || !strings.Contains(err.Error(), "#/components/schemas/Error is already defined") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	os.Remove(outputFile)
+}
+
+func TestRenameComponents(t *testing.T) {
+	outputFile := "renamed.yaml"
+	args := []string{
+		"gnostic",
+		"rename",
+		"testdata/rename/openapi.yaml",
+		"--schema", "Pet=Animal",
+		"--schema", "Person=Owner",
+		"--parameter", "Limit=PageSize",
+		"--response=Error=Problem",
+		"--security-scheme", "apiKey=key",
+		"--yaml-out=" + outputFile}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/rename/renamed.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+
+	// Renames that chain are errors unless the later one comes first.
+	args = []string{
+		"gnostic",
+		"--rename", "schemas/Pet=Animal",
+		"--rename", "schemas/Animal=Beast",
+		"--yaml-out=" + outputFile,
+		"testdata/rename/openapi.yaml"}
+	err := lib.NewGnostic(args).Main()
+	if err == nil || !strings.Contains(err.Error(), "#/components/schemas/Animal is the result of an earlier rename") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	args = []string{
+		"gnostic",
+		"--rename", "schemas/Person=Owner",
+		"--rename", "schemas/Pet=Person",
+		"--yaml-out=" + outputFile,
+		"testdata/rename/openapi.yaml"}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Errorf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	os.Remove(outputFile)
+
+	for _, invalid := range []string{"--rename=Pet=Animal", "--rename=schemas/Pet"} {
+		err = lib.NewGnostic([]string{"gnostic", invalid, "--yaml-out=" + outputFile, "testdata/rename/openapi.yaml"}).Main()
+		if err == nil || !strings.Contains(err.Error(), "invalid value for --rename") {
+			t.Errorf("Unexpected error for %s: %v", invalid, err)
+		}
+	}
+	if err = lib.NewGnostic([]string{"gnostic", "rename", "testdata/rename/openapi.yaml", "--yaml-out=-"}).Main(); err == nil {
+		t.Errorf("rename accepted no renames")
+	}
+}
+
+func TestRegisterOutput(t *testing.T) {
+	// Registered outputs write a line with the title and a parameter.
+	err := lib.RegisterOutput("title", func(doc lib.Document, w io.Writer, params map[string]string) error {
+		document, ok := doc.(*openapi_v3.Document)
+		if !ok {
+			return errors.New("unsupported document")
+		}
+		_, err := fmt.Fprintf(w, "%s%s\n", document.Info.Title, params["suffix"])
+		return err
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if err := lib.RegisterOutput("title", func(lib.Document, io.Writer, map[string]string) error { return nil }); err == nil {
+		t.Errorf("RegisterOutput accepted a duplicate name")
+	}
+	for _, name := range []string{"yaml", "pb", "errors", "a:b"} {
+		if err := lib.RegisterOutput(name, func(lib.Document, io.Writer, map[string]string) error { return nil }); err == nil {
+			t.Errorf("RegisterOutput accepted %s", name)
+		}
+	}
+	outputFile := "petstore.title"
+	args := []string{
+		"gnostic",
+		"--title-out=suffix=.v1:" + outputFile,
+		"examples/v3.0/yaml/petstore.yaml"}
+	g := lib.NewGnostic(args)
+	if err := g.Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	bytes, _ := ioutil.ReadFile(outputFile)
+	if string(bytes) != "OpenAPI Petstore.v1\n" {
+		t.Errorf("Unexpected output: %s", bytes)
+	}
+	os.Remove(outputFile)
+	// Errors of outputs are returned.
+	outputFile = "petstore.v2.title"
+	args = []string{
+		"gnostic",
+		"--title-out=" + outputFile,
+		"examples/v2.0/yaml/petstore.yaml"}
+	g = lib.NewGnostic(args)
+	if err := g.Main(); err == nil || !strings.Contains(err.Error(), "unsupported document") {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+	os.Remove(outputFile)
+}
+
+func TestPartialOutputs(t *testing.T) {
+	outputFile := "petstore-partial.yaml"
+	statuses := func(g *lib.Gnostic) string {
+		result := make([]string, 0)
+		for _, status := range g.OutputStatuses() {
+			result = append(result, status.Name+"="+status.Status)
+		}
+		return strings.Join(result, ",")
+	}
+	// A plugin that can't be run fails, so by default nothing is written.
+	os.Remove(outputFile)
+	args := []string{
+		"gnostic",
+		"--yaml-out=" + outputFile,
+		"--missing-out=!",
+		"examples/v3.0/yaml/petstore.yaml"}
+	g := lib.NewGnostic(args)
+	if err := g.Main(); err == nil {
+		t.Errorf("Missing error for command %v", strings.Join(args, " "))
+	}
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		t.Errorf("Unexpected output %s", outputFile)
+	}
+	if s := statuses(g); s != "yaml=skipped,plugin missing=failed" {
+		t.Errorf("Unexpected statuses: %s", s)
+	}
+	// With --keep-partial, the outputs that succeeded are written.
+	g = lib.NewGnostic(append(args, "--keep-partial"))
+	if err := g.Main(); err == nil {
+		t.Errorf("Missing error for command %v --keep-partial", strings.Join(args, " "))
+	}
+	if _, err := os.Stat(outputFile); err != nil {
+		t.Errorf("Missing output %s: %+v", outputFile, err)
+	}
+	if s := statuses(g); s != "yaml=written,plugin missing=failed" {
+		t.Errorf("Unexpected statuses: %s", s)
+	}
+	os.Remove(outputFile)
+	// With --fail-fast, nothing runs after the first failure.
+	g = lib.NewGnostic(append(args, "--absent-out=!", "--fail-fast"))
+	err := g.Main()
+	if err == nil || strings.Contains(err.Error(), "\n") {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+	if s := statuses(g); s != "yaml=skipped,plugin missing=failed,plugin absent=skipped" {
+		t.Errorf("Unexpected statuses: %s", s)
+	}
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		t.Errorf("Unexpected output %s", outputFile)
+	}
+}
+
+func TestNDJSONOutput(t *testing.T) {
+	for _, version := range []string{"v2", "v3"} {
+		outputFile := "petstore.ndjson"
+		args := []string{
+			"gnostic",
+			"--ndjson-out=" + outputFile,
+			"examples/" + version + ".0/yaml/petstore.yaml"}
+		g := lib.NewGnostic(args)
+		if err := g.Main(); err != nil {
+			t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+		}
+		if err := exec.Command("diff", outputFile, "testdata/ndjson/petstore-"+version+".ndjson").Run(); err != nil {
+			t.Errorf("Diff failed for %s: %+v", version, err)
+		}
+		os.Remove(outputFile)
+	}
+}
+
+func TestImportRAML(t *testing.T) {
+	outputFile := "notes.yaml"
+	args := []string{
+		"gnostic",
+		"import-raml",
+		"--input", "testdata/raml/notes.raml",
+		"--output", outputFile}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/raml/notes.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+
+	// Files that end with .raml must be RAML 1.0 API definitions.
+	inputFile := filepath.Join(t.TempDir(), "notes.raml")
+	if err := ioutil.WriteFile(inputFile, []byte("title: Notes API\n"), 0644); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	args = []string{
+		"gnostic",
+		"import-raml",
+		"--input", inputFile,
+		"--output", outputFile}
+	err := lib.NewGnostic(args).Main()
+	if err == nil || err.Error() != "not a RAML 1.0 API definition" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	os.Remove(outputFile)
+}
+
+func TestConvertTree(t *testing.T) {
+	outputDir := t.TempDir()
+	args := []string{
+		"gnostic",
+		"convert",
+		"--from=2",
+		"--to=3",
+		"--in", "testdata/convert/apis",
+		"--out", outputDir}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Convert failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if output, err := exec.Command("diff", "-r", outputDir, "testdata/convert/apis-v3").CombinedOutput(); err != nil {
+		t.Fatalf("Diff failed: %+v\n%s", err, output)
+	}
+	args = []string{"gnostic", "convert", "--from=3", "--to=2", "--in=testdata/convert/apis", "--out=" + outputDir}
+	if err := lib.NewGnostic(args).Main(); err == nil || err.Error() != "only --from=2 --to=3 conversions are supported" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestConvertTreePartials(t *testing.T) {
+	results, err := lib.ConvertTree("testdata/convert/apis", t.TempDir())
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var got []string
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("Unable to convert %s: %+v", result.Path, result.Err)
+		}
+		kind := "document"
+		if result.Partial {
+			kind = "partial"
+		}
+		got = append(got, fmt.Sprintf("%s %s %d", result.Path, kind, len(result.Warnings)))
+	}
+	// The components that both documents use are converted once, the
+	// referenced Swagger 2.0 document is a partial, and files that
+	// documents don't reference aren't converted.
+	expected := []string{
+		"common/components.yaml partial 0",
+		"common/params.yaml partial 1",
+		"legacy/swagger.yaml partial 0",
+		"pets/pet.yaml partial 0",
+		"pets/swagger.yaml document 2",
+		"stores/store-item.yaml partial 0",
+		"stores/swagger.json document 0",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected results:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestConvertTreePartialUses(t *testing.T) {
+	// A partial that is used as a schema and as a parameter is converted
+	// as the kind of the first reference in the order of the fragments.
+	inputDir := t.TempDir()
+	files := map[string]string{
+		"a.yaml": `swagger: "2.0"
+info: {title: A, version: "1"}
+paths:
+  /a:
+    get:
+      parameters:
+        - $ref: 'shared.yaml#/Value'
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: 'shared.yaml#/Value'
+`,
+		"shared.yaml": `Value:
+  name: value
+  in: query
+  type: string
+`,
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(inputDir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+	results, err := lib.ConvertTree(inputDir, filepath.Join(inputDir, "v3"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(results) != 2 || results[1].Path != "shared.yaml" || !results[1].Partial ||
+		strings.Join(results[1].Warnings, "\n") != "#: #/Value is used as a parameter and as a schema" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+	// The output directory is skipped when the input is read again.
+	results, err = lib.ConvertTree(inputDir, filepath.Join(inputDir, "v3"))
+	if err != nil || len(results) != 2 {
+		t.Errorf("Unexpected results: %+v, %v", results, err)
+	}
+}
+
+func TestCheckCoverage(t *testing.T) {
+	outputFile := "uncovered.json"
+	args := []string{
+		"gnostic",
+		"check-coverage",
+		"--input", "testdata/coverage/openapi.yaml",
+		"--tests", "testdata/coverage/tests.json",
+		"--output", outputFile}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	defer os.Remove(outputFile)
+	if err := exec.Command("diff", outputFile, "testdata/coverage/uncovered.json").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	g := lib.NewGnostic([]string{"gnostic", "check-coverage", "--input", "testdata/coverage/openapi.yaml"})
+	if err := g.Main(); err == nil {
+		t.Errorf("check-coverage accepted a missing --tests option")
+	}
+}
+
+func TestFingerprints(t *testing.T) {
+	// The documents differ only in the order of keys and in the use of
+	// components, so their fingerprints are the same.
+	outputFile := "fingerprints.json"
+	for _, inputFile := range []string{"testdata/fingerprints/inline.yaml", "testdata/fingerprints/refs.yaml"} {
+		args := []string{"gnostic", inputFile, "--fingerprints-out=" + outputFile}
+		if err := lib.NewGnostic(args).Main(); err != nil {
+			t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+		}
+		if err := exec.Command("diff", outputFile, "testdata/fingerprints/fingerprints.json").Run(); err != nil {
+			t.Errorf("Diff failed for %s: %+v", inputFile, err)
+		}
+		os.Remove(outputFile)
+	}
+}
+
+func TestUncoveredOperationsV2(t *testing.T) {
+	bytes, err := ioutil.ReadFile("examples/v2.0/yaml/petstore.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, err := openapi_v2.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// Requests with and without the base path cover operations.
+	uncovered, err := lib.UncoveredOperations(document, []*lib.TestedRequest{
+		{Method: "GET", Path: "/v1/pets"},
+		{Method: "GET", Path: "/pets/1"},
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(uncovered) != 1 || uncovered[0].Method != "POST" || uncovered[0].Path != "/pets" || uncovered[0].OperationID != "createPets" {
+		t.Errorf("Unexpected uncovered operations: %+v", uncovered)
+	}
+}
+
+func TestInferMissingSchemas(t *testing.T) {
+	outputFile := "inferred.yaml"
+	args := []string{
+		"gnostic",
+		"--infer-missing-schemas",
+		"--yaml-out=" + outputFile,
+		"testdata/infer/openapi.yaml"}
+	if err := lib.NewGnostic(args).Main(); err != nil {
+		t.Fatalf("Compile failed for command %v: %+v", strings.Join(args, " "), err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/infer/inferred.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+	os.Remove(outputFile)
+	// The option can also be set in a configuration file.
+	outputFile = "testdata/config/inferred.yaml"
+	defer os.Remove(outputFile)
+	if err := lib.NewGnostic([]string{"gnostic", "--config=testdata/config/infer.yaml"}).Main(); err != nil {
+		t.Fatalf("Compile failed: %+v", err)
+	}
+	if err := exec.Command("diff", outputFile, "testdata/infer/inferred.yaml").Run(); err != nil {
+		t.Fatalf("Diff failed: %+v", err)
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/google/gnostic
 
-go 1.12
+go 1.16
 
 require (
 	github.com/docopt/docopt-go v0.0.0-20180111231733-ee0de3bc6815
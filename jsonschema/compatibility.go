@@ -0,0 +1,297 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"fmt"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// Mode selects the direction of a compatibility check.
+type Mode int
+
+const (
+	// Backward compatibility means that readers using the new schema
+	// accept all data that was valid under the old schema.
+	Backward Mode = iota
+	// Forward compatibility means that readers using the old schema
+	// accept all data that is valid under the new schema.
+	Forward
+	// Full compatibility is both backward and forward compatibility.
+	Full
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Backward:
+		return "backward"
+	case Forward:
+		return "forward"
+	case Full:
+		return "full"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// Incompatibility describes a change that breaks compatibility between schemas.
+type Incompatibility struct {
+	// Path is a JSON Pointer to the changed schema, e.g. "#/properties/name".
+	Path string
+	// Mode is the direction that is broken, either Backward or Forward.
+	Mode Mode
+	// Message describes the change.
+	Message string
+}
+
+func (i *Incompatibility) String() string {
+	return fmt.Sprintf("%s: %s (%s)", i.Path, i.Message, i.Mode)
+}
+
+// CheckCompatibility compares two versions of a schema and returns the
+// changes that break compatibility in the specified mode. It detects type
+// narrowing, properties that are removed from schemas that don't allow
+// additional properties, added required properties, tightened enums and consts, and
+// narrowed numeric ranges, including changes to exclusiveMinimum and
+// exclusiveMaximum. References are compared by value and are not
+// resolved.
+func CheckCompatibility(old, new *Schema, mode Mode) []Incompatibility {
+	results := make([]Incompatibility, 0)
+	if mode == Backward || mode == Full {
+		// New readers must accept old data.
+		results = append(results, checkReader(new, old, "#", Backward)...)
+	}
+	if mode == Forward || mode == Full {
+		// Old readers must accept new data.
+		results = append(results, checkReader(old, new, "#", Forward)...)
+	}
+	return results
+}
+
+// checkReader reports changes that cause instances that are valid under the
+// writer schema to be invalid under the reader schema. Messages describe each
+// change from the old schema to the new one, whichever of them is the reader.
+func checkReader(reader, writer *Schema, path string, mode Mode) []Incompatibility {
+	results := make([]Incompatibility, 0)
+	if reader == nil || writer == nil {
+		return results
+	}
+	report := func(format string, args ...interface{}) {
+		results = append(results, Incompatibility{Path: path, Mode: mode, Message: fmt.Sprintf(format, args...)})
+	}
+	// In backward mode the new schema reads data written with the old one,
+	// in forward mode the old schema reads data written with the new one.
+	old, new := writer, reader
+	changed := "removed"
+	if mode == Forward {
+		old, new = reader, writer
+		changed = "added"
+	}
+
+	if reader.Ref != nil || writer.Ref != nil {
+		if reader.Ref == nil || writer.Ref == nil || *reader.Ref != *writer.Ref {
+			report("reference changed from %s to %s", refDescription(old), refDescription(new))
+		}
+		return results
+	}
+
+	// Type narrowing.
+	if reader.Type != nil {
+		for _, t := range typeNames(writer) {
+			if !reader.TypeIs(t) && !(t == "integer" && reader.TypeIs("number")) {
+				report("type %s was %s", t, changed)
+			}
+		}
+	}
+
+	// Tightened enums.
+	if reader.Enumeration != nil {
+		if writer.Enumeration == nil {
+			report("enum was %s", oppositeChange(changed))
+		} else {
+			for _, value := range *writer.Enumeration {
+				if !enumContains(*reader.Enumeration, value) {
+					report("enum value %s was %s", enumValueDescription(value), changed)
+				}
+			}
+		}
+	}
+
+	if reader.Const != nil && (writer.Const == nil || !enumContains([]SchemaEnumValue{*reader.Const}, *writer.Const)) {
+		if writer.Const == nil {
+			report("const %s was %s", enumValueDescription(*reader.Const), oppositeChange(changed))
+		} else {
+			report("const changed from %s to %s", enumValueDescription(*old.Const), enumValueDescription(*new.Const))
+		}
+	}
+
+	// Narrowed numeric ranges.
+	if boundNarrowed(reader.Minimum, reader.ExclusiveMinimum, writer.Minimum, writer.ExclusiveMinimum, 1) {
+		report("%s", boundChange("minimum", old.Minimum, old.ExclusiveMinimum, new.Minimum, new.ExclusiveMinimum))
+	}
+	if boundNarrowed(reader.Maximum, reader.ExclusiveMaximum, writer.Maximum, writer.ExclusiveMaximum, -1) {
+		report("%s", boundChange("maximum", old.Maximum, old.ExclusiveMaximum, new.Maximum, new.ExclusiveMaximum))
+	}
+
+	// Added required properties.
+	if reader.Required != nil {
+		for _, name := range *reader.Required {
+			if writer.Required == nil || !stringsContain(*writer.Required, name) {
+				if mode == Forward {
+					report("property %s is no longer required", name)
+				} else {
+					report("property %s became required", name)
+				}
+			}
+		}
+	}
+
+	// Properties that are not allowed.
+	closed := reader.AdditionalProperties != nil &&
+		reader.AdditionalProperties.Boolean != nil &&
+		!*reader.AdditionalProperties.Boolean
+	if writer.Properties != nil {
+		for _, property := range *writer.Properties {
+			readerProperty := reader.PropertyWithName(property.Name)
+			propertyPath := path + "/properties/" + compiler.EscapeJSONPointer(property.Name)
+			if readerProperty != nil {
+				results = append(results, checkReader(readerProperty, property.Value, propertyPath, mode)...)
+			} else if closed {
+				results = append(results, Incompatibility{Path: propertyPath, Mode: mode, Message: "property was " + changed})
+			}
+		}
+	}
+
+	// Nested schemas.
+	if reader.Items != nil && writer.Items != nil && reader.Items.Schema != nil && writer.Items.Schema != nil {
+		results = append(results, checkReader(reader.Items.Schema, writer.Items.Schema, path+"/items", mode)...)
+	}
+	if reader.AdditionalProperties != nil && writer.AdditionalProperties != nil &&
+		reader.AdditionalProperties.Schema != nil && writer.AdditionalProperties.Schema != nil {
+		results = append(results, checkReader(reader.AdditionalProperties.Schema, writer.AdditionalProperties.Schema, path+"/additionalProperties", mode)...)
+	}
+	return results
+}
+
+func oppositeChange(changed string) string {
+	if changed == "added" {
+		return "removed"
+	}
+	return "added"
+}
+
+// boundNarrowed reports whether the reader's bound rejects values that the
+// writer's bound accepts. The sign is 1 for minimums and -1 for maximums.
+func boundNarrowed(reader *SchemaNumber, readerExclusive *bool, writer *SchemaNumber, writerExclusive *bool, sign float64) bool {
+	if reader == nil {
+		return false
+	}
+	if writer == nil {
+		return true
+	}
+	r, w := sign*reader.float(), sign*writer.float()
+	return r > w || (r == w && isTrue(readerExclusive) && !isTrue(writerExclusive))
+}
+
+// boundChange describes a change of a minimum or maximum from the old schema to the new one.
+func boundChange(name string, old *SchemaNumber, oldExclusive *bool, new *SchemaNumber, newExclusive *bool) string {
+	switch {
+	case old == nil:
+		return fmt.Sprintf("%s %s was added", name, boundDescription(new, newExclusive))
+	case new == nil:
+		return fmt.Sprintf("%s %s was removed", name, boundDescription(old, oldExclusive))
+	default:
+		return fmt.Sprintf("%s changed from %s to %s", name, boundDescription(old, oldExclusive), boundDescription(new, newExclusive))
+	}
+}
+
+func boundDescription(n *SchemaNumber, exclusive *bool) string {
+	if isTrue(exclusive) {
+		return n.description() + " (exclusive)"
+	}
+	return n.description()
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+// typeNames returns the types of a schema, or all types if none are specified.
+func typeNames(schema *Schema) []string {
+	if schema.Type == nil {
+		return []string{"array", "boolean", "integer", "null", "number", "object", "string"}
+	}
+	if schema.Type.String != nil {
+		return []string{*schema.Type.String}
+	}
+	if schema.Type.StringArray != nil {
+		return *schema.Type.StringArray
+	}
+	return nil
+}
+
+func refDescription(schema *Schema) string {
+	if schema.Ref == nil {
+		return "an inline schema"
+	}
+	return *schema.Ref
+}
+
+func enumContains(values []SchemaEnumValue, value SchemaEnumValue) bool {
+	for _, v := range values {
+		if (v.String != nil && value.String != nil && *v.String == *value.String) ||
+			(v.Bool != nil && value.Bool != nil && *v.Bool == *value.Bool) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumValueDescription(value SchemaEnumValue) string {
+	if value.String != nil {
+		return fmt.Sprintf("%q", *value.String)
+	}
+	if value.Bool != nil {
+		return fmt.Sprintf("%t", *value.Bool)
+	}
+	return "null"
+}
+
+func stringsContain(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *SchemaNumber) float() float64 {
+	if n.Integer != nil {
+		return float64(*n.Integer)
+	}
+	if n.Float != nil {
+		return *n.Float
+	}
+	return 0
+}
+
+func (n *SchemaNumber) description() string {
+	if n.Integer != nil {
+		return fmt.Sprintf("%d", *n.Integer)
+	}
+	return fmt.Sprintf("%g", n.float())
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"testing"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		backward []string
+		forward  []string
+	}{
+		{
+			name: "unchanged",
+			old:  "{type: object, properties: {name: {type: string}}}",
+			new:  "{type: object, properties: {name: {type: string}}}",
+		},
+		{
+			name:    "type widened",
+			old:     "{type: integer}",
+			new:     "{type: number}",
+			forward: []string{"#: type number was added"},
+		},
+		{
+			name:     "type narrowed",
+			old:      "{type: [string, \"null\"]}",
+			new:      "{type: string}",
+			backward: []string{"#: type null was removed"},
+		},
+		{
+			name:     "nested type changed",
+			old:      "{type: object, properties: {count: {type: string}}}",
+			new:      "{type: object, properties: {count: {type: integer}}}",
+			backward: []string{"#/properties/count: type string was removed"},
+			forward:  []string{"#/properties/count: type integer was added"},
+		},
+		{
+			name: "property removed from open schema",
+			old:  "{type: object, properties: {a: {type: string}, b: {type: string}}}",
+			new:  "{type: object, properties: {a: {type: string}}}",
+		},
+		{
+			name:     "property removed from closed schema",
+			old:      "{type: object, properties: {a: {type: string}, b: {type: string}}, additionalProperties: false}",
+			new:      "{type: object, properties: {a: {type: string}}, additionalProperties: false}",
+			backward: []string{"#/properties/b: property was removed"},
+		},
+		{
+			name:     "required property added",
+			old:      "{type: object, properties: {a: {type: string}}}",
+			new:      "{type: object, properties: {a: {type: string}}, required: [a]}",
+			backward: []string{"#: property a became required"},
+		},
+		{
+			name:     "enum tightened",
+			old:      "{type: string, enum: [RED, GREEN, BLUE]}",
+			new:      "{type: string, enum: [RED, GREEN]}",
+			backward: []string{"#: enum value \"BLUE\" was removed"},
+		},
+		{
+			name:     "enum added",
+			old:      "{type: string}",
+			new:      "{type: string, enum: [RED]}",
+			backward: []string{"#: enum was added"},
+		},
+		{
+			name:     "const added",
+			old:      "{type: string, enum: [RED, GREEN]}",
+			new:      "{type: string, enum: [RED, GREEN], const: RED}",
+			backward: []string{"#: const \"RED\" was added"},
+		},
+		{
+			name:     "range narrowed",
+			old:      "{type: integer, minimum: 0, maximum: 100}",
+			new:      "{type: integer, minimum: 1, maximum: 10}",
+			backward: []string{"#: minimum changed from 0 to 1", "#: maximum changed from 100 to 10"},
+		},
+		{
+			name:    "range widened",
+			old:     "{type: number, minimum: 0.5}",
+			new:     "{type: number}",
+			forward: []string{"#: minimum 0.5 was removed"},
+		},
+		{
+			name:    "required property removed",
+			old:     "{type: object, properties: {a: {type: string}}, required: [a]}",
+			new:     "{type: object, properties: {a: {type: string}}}",
+			forward: []string{"#: property a is no longer required"},
+		},
+		{
+			name:    "property added to closed schema",
+			old:     "{type: object, properties: {a: {type: string}}, additionalProperties: false}",
+			new:     "{type: object, properties: {a: {type: string}, b: {type: string}}, additionalProperties: false}",
+			forward: []string{"#/properties/b: property was added"},
+		},
+		{
+			name:    "enum widened",
+			old:     "{type: string, enum: [RED]}",
+			new:     "{type: string, enum: [RED, GREEN]}",
+			forward: []string{"#: enum value \"GREEN\" was added"},
+		},
+		{
+			name:     "const changed",
+			old:      "{type: string, const: RED}",
+			new:      "{type: string, const: GREEN}",
+			backward: []string{"#: const changed from \"RED\" to \"GREEN\""},
+			forward:  []string{"#: const changed from \"RED\" to \"GREEN\""},
+		},
+		{
+			name:     "bounds made exclusive",
+			old:      "{type: number, minimum: 0, maximum: 10}",
+			new:      "{type: number, minimum: 0, exclusiveMinimum: true, maximum: 10, exclusiveMaximum: true}",
+			backward: []string{"#: minimum changed from 0 to 0 (exclusive)", "#: maximum changed from 10 to 10 (exclusive)"},
+		},
+		{
+			name:    "bounds made inclusive",
+			old:     "{type: number, minimum: 0, exclusiveMinimum: true, maximum: 10, exclusiveMaximum: true}",
+			new:     "{type: number, minimum: 0, maximum: 10}",
+			forward: []string{"#: minimum changed from 0 (exclusive) to 0", "#: maximum changed from 10 (exclusive) to 10"},
+		},
+		{
+			name:     "exclusive maximum added",
+			old:      "{type: number, maximum: 10}",
+			new:      "{type: number, maximum: 9, exclusiveMaximum: true}",
+			backward: []string{"#: maximum changed from 10 to 9 (exclusive)"},
+		},
+		{
+			name:    "exclusive minimum removed",
+			old:     "{type: number, minimum: 1, exclusiveMinimum: true}",
+			new:     "{type: number}",
+			forward: []string{"#: minimum 1 (exclusive) was removed"},
+		},
+		{
+			name:     "array items narrowed",
+			old:      "{type: array, items: {type: number}}",
+			new:      "{type: array, items: {type: integer}}",
+			backward: []string{"#/items: type number was removed"},
+		},
+		{
+			name:     "reference changed",
+			old:      "{$ref: \"#/definitions/A\"}",
+			new:      "{$ref: \"#/definitions/B\"}",
+			backward: []string{"#: reference changed from #/definitions/A to #/definitions/B"},
+			forward:  []string{"#: reference changed from #/definitions/A to #/definitions/B"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			old := schemaFromString(t, test.old)
+			new := schemaFromString(t, test.new)
+			check := func(mode Mode, expected []string) {
+				results := CheckCompatibility(old, new, mode)
+				if len(results) != len(expected) {
+					t.Fatalf("%s: expected %d incompatibilities, got %+v", mode, len(expected), results)
+				}
+				for i, result := range results {
+					if got := result.Path + ": " + result.Message; got != expected[i] {
+						t.Errorf("%s: expected %q, got %q", mode, expected[i], got)
+					}
+				}
+			}
+			check(Backward, test.backward)
+			check(Forward, test.forward)
+			check(Full, append(append([]string{}, test.backward...), test.forward...))
+		})
+	}
+}
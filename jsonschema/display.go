@@ -42,6 +42,9 @@ func (schema *Schema) String() string {
 
 // Helper: Returns a string representation of a Schema indented by a specified string.
 func (schema *Schema) describeSchema(indent string) string {
+	if b, ok := schema.BooleanValue(); ok {
+		return indent + fmt.Sprintf("%+v\n", b)
+	}
 	result := ""
 	if schema.Schema != nil {
 		result += indent + "$schema: " + *(schema.Schema) + "\n"
@@ -187,6 +190,13 @@ func (schema *Schema) describeSchema(indent string) string {
 			}
 		}
 	}
+	if schema.Const != nil {
+		if schema.Const.String != nil {
+			result += indent + fmt.Sprintf("const: %+v\n", *schema.Const.String)
+		} else if schema.Const.Bool != nil {
+			result += indent + fmt.Sprintf("const: %+v\n", *schema.Const.Bool)
+		}
+	}
 	if schema.Type != nil {
 		result += indent + fmt.Sprintf("type: %+v\n", schema.Type.Description())
 	}
@@ -232,7 +242,13 @@ func (schema *Schema) describeSchema(indent string) string {
 	}
 	if schema.Default != nil {
 		result += indent + "default:\n"
-		result += indent + fmt.Sprintf("  %+v\n", *(schema.Default))
+		result += indent + fmt.Sprintf("  %+v\n", schema.Default.AsInterface())
+	}
+	if schema.Examples != nil {
+		result += indent + "examples:\n"
+		for _, value := range *(schema.Examples) {
+			result += indent + "  " + fmt.Sprintf("%+v\n", value.AsInterface())
+		}
 	}
 	if schema.Format != nil {
 		result += indent + "format: " + *(schema.Format) + "\n"
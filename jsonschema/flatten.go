@@ -0,0 +1,218 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"fmt"
+)
+
+// Flatten returns a copy of a schema in which each reference is replaced by
+// a copy of the schema that it refers to, so that the result is
+// self-contained. Local references ("#/definitions/Name") are resolved
+// against the schema itself and cross-file references with r, which reads
+// schemas that it hasn't loaded with its Fetch function, if it has one.
+// References in a schema from another file are resolved against that file.
+// r can be nil if the schema has only local references.
+//
+// Titles, descriptions, defaults, and examples next to a reference replace
+// those of the referenced schema; other keywords next to references are
+// ignored, as in draft-04. Copies of referenced schemas don't have ids,
+// $schema keywords, or definitions. Recursive references can't be
+// flattened and are errors. The schema itself is not modified.
+func (schema *Schema) Flatten(r *Resolver) (*Schema, error) {
+	f := &flattener{
+		resolver: r,
+		root:     schema,
+		active:   make(map[string]bool),
+	}
+	return f.flatten(schema, rootScope)
+}
+
+// rootScope is the scope of references in the schema that is flattened,
+// which doesn't need to be loaded into the Resolver.
+const rootScope = ""
+
+type flattener struct {
+	resolver *Resolver
+	root     *Schema
+	// active holds the references that are being replaced,
+	// by the name of their schema and their JSON Pointer.
+	active map[string]bool
+}
+
+// flatten copies a schema that appears in the named scope.
+func (f *flattener) flatten(schema *Schema, scope string) (*Schema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	if schema.Ref != nil {
+		return f.flattenReference(schema, scope)
+	}
+	result := *schema
+	var err error
+	if result.AdditionalItems, err = f.flattenSchemaOrBoolean(schema.AdditionalItems, scope); err != nil {
+		return nil, err
+	}
+	if schema.Items != nil {
+		items := &SchemaOrSchemaArray{}
+		if items.Schema, err = f.flatten(schema.Items.Schema, scope); err != nil {
+			return nil, err
+		}
+		if items.SchemaArray, err = f.flattenSchemaArray(schema.Items.SchemaArray, scope); err != nil {
+			return nil, err
+		}
+		result.Items = items
+	}
+	if result.AdditionalProperties, err = f.flattenSchemaOrBoolean(schema.AdditionalProperties, scope); err != nil {
+		return nil, err
+	}
+	if result.Properties, err = f.flattenNamedSchemaArray(schema.Properties, scope); err != nil {
+		return nil, err
+	}
+	if result.PatternProperties, err = f.flattenNamedSchemaArray(schema.PatternProperties, scope); err != nil {
+		return nil, err
+	}
+	if schema.Dependencies != nil {
+		dependencies := make([]*NamedSchemaOrStringArray, 0, len(*schema.Dependencies))
+		for _, pair := range *schema.Dependencies {
+			value := pair.Value
+			if value != nil && value.Schema != nil {
+				s, err := f.flatten(value.Schema, scope)
+				if err != nil {
+					return nil, err
+				}
+				value = &SchemaOrStringArray{Schema: s}
+			}
+			dependencies = append(dependencies, &NamedSchemaOrStringArray{Name: pair.Name, Value: value})
+		}
+		result.Dependencies = &dependencies
+	}
+	if result.AllOf, err = f.flattenSchemaArray(schema.AllOf, scope); err != nil {
+		return nil, err
+	}
+	if result.AnyOf, err = f.flattenSchemaArray(schema.AnyOf, scope); err != nil {
+		return nil, err
+	}
+	if result.OneOf, err = f.flattenSchemaArray(schema.OneOf, scope); err != nil {
+		return nil, err
+	}
+	if result.Not, err = f.flatten(schema.Not, scope); err != nil {
+		return nil, err
+	}
+	if result.Definitions, err = f.flattenNamedSchemaArray(schema.Definitions, scope); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// flattenReference returns a copy of the schema that a reference refers to.
+func (f *flattener) flattenReference(schema *Schema, scope string) (*Schema, error) {
+	ref := *schema.Ref
+	target, targetScope, err := f.resolve(scope, ref)
+	if err != nil {
+		return nil, err
+	}
+	_, pointer := splitReference(ref)
+	key := targetScope + "#" + pointer
+	if f.active[key] {
+		return nil, fmt.Errorf("recursive reference %s can't be flattened", ref)
+	}
+	f.active[key] = true
+	defer delete(f.active, key)
+	result, err := f.flatten(target, targetScope)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := result.BooleanValue(); ok {
+		return result, nil
+	}
+	result.Schema = nil
+	result.ID = nil
+	result.Definitions = nil
+	if schema.Title != nil {
+		result.Title = schema.Title
+	}
+	if schema.Description != nil {
+		result.Description = schema.Description
+	}
+	if schema.Default != nil {
+		result.Default = schema.Default
+	}
+	if schema.Examples != nil {
+		result.Examples = schema.Examples
+	}
+	return result, nil
+}
+
+// resolve returns the schema that a reference in the named scope refers to
+// and the scope of that schema.
+func (f *flattener) resolve(scope string, ref string) (*Schema, string, error) {
+	document, pointer := splitReference(ref)
+	if document == "" && scope == rootScope {
+		target, err := f.root.schemaForJSONPointer(pointer)
+		if err != nil {
+			return nil, "", fmt.Errorf("unresolved reference: %s (%s)", ref, err.Error())
+		}
+		return target, rootScope, nil
+	}
+	if f.resolver == nil {
+		return nil, "", fmt.Errorf("unresolved reference: %s (no resolver for references to other schemas)", ref)
+	}
+	if scope == rootScope {
+		scope = f.resolver.Base
+	}
+	return f.resolver.resolve(scope, ref)
+}
+
+func (f *flattener) flattenSchemaOrBoolean(value *SchemaOrBoolean, scope string) (*SchemaOrBoolean, error) {
+	if value == nil || value.Schema == nil {
+		return value, nil
+	}
+	s, err := f.flatten(value.Schema, scope)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaOrBoolean{Schema: s}, nil
+}
+
+func (f *flattener) flattenSchemaArray(array *[]*Schema, scope string) (*[]*Schema, error) {
+	if array == nil {
+		return nil, nil
+	}
+	result := make([]*Schema, 0, len(*array))
+	for _, s := range *array {
+		flattened, err := f.flatten(s, scope)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, flattened)
+	}
+	return &result, nil
+}
+
+func (f *flattener) flattenNamedSchemaArray(array *[]*NamedSchema, scope string) (*[]*NamedSchema, error) {
+	if array == nil {
+		return nil, nil
+	}
+	result := make([]*NamedSchema, 0, len(*array))
+	for _, pair := range *array {
+		flattened, err := f.flatten(pair.Value, scope)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &NamedSchema{Name: pair.Name, Value: flattened})
+	}
+	return &result, nil
+}
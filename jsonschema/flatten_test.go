@@ -0,0 +1,205 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func flattenedJSON(t *testing.T, schema *Schema) string {
+	t.Helper()
+	bytes, err := CanonicalJSON(schema)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return string(bytes)
+}
+
+func TestFlattenLocalReferences(t *testing.T) {
+	schema := schemaFromString(t, `
+type: object
+properties:
+  a: {$ref: "#/definitions/A"}
+  b: {$ref: "#/definitions/B", description: B}
+definitions:
+  A: {type: string}
+  B: {$ref: "#/definitions/A"}
+`)
+	flattened, err := schema.Flatten(nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := `{"definitions":{"A":{"type":"string"},"B":{"type":"string"}},` +
+		`"properties":{"a":{"type":"string"},"b":{"description":"B","type":"string"}},"type":"object"}`
+	if got := flattenedJSON(t, flattened); got != expected {
+		t.Errorf("Unexpected flattened schema:\n%s\nExpected:\n%s", got, expected)
+	}
+	if schema.PropertyWithName("a").Ref == nil {
+		t.Errorf("Expected the original schema to be unchanged")
+	}
+	if _, err := schemaFromString(t, `{$ref: "Other.json"}`).Flatten(nil); err == nil {
+		t.Errorf("Expected an error for a cross-file reference without a resolver")
+	}
+}
+
+func TestFlattenCrossFileReferences(t *testing.T) {
+	sources := map[string]string{
+		"schemas/Author.json": `
+type: object
+properties:
+  name: {$ref: "#/definitions/Name"}
+  tags: {type: array, items: {$ref: "common/Tag.json#/definitions/Tag"}}
+definitions:
+  Name: {type: string, title: Name}
+`,
+		"schemas/common/Tag.json": `
+definitions:
+  Tag: {type: string, pattern: "^[a-z]+$"}
+`,
+	}
+	fetched := make(map[string]int)
+	r := &Resolver{Base: "schemas/Message.json"}
+	r.Fetch = func(name string) (*Schema, error) {
+		fetched[name]++
+		source, ok := sources[name]
+		if !ok {
+			return nil, fmt.Errorf("%s not found", name)
+		}
+		return schemaFromString(t, source), nil
+	}
+	message := schemaFromString(t, `
+type: object
+properties:
+  author: {$ref: Author.json}
+  editor: {$ref: "Author.json", description: The editor.}
+  tag: {$ref: "common/Tag.json#/definitions/Tag"}
+`)
+	flattened, err := message.Flatten(r)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	author := `{"properties":{"name":{"title":"Name","type":"string"},` +
+		`"tags":{"items":{"pattern":"^[a-z]+$","type":"string"},"type":"array"}},"type":"object"}`
+	editor := strings.Replace(author, `{"properties"`, `{"description":"The editor.","properties"`, 1)
+	expected := `{"properties":{"author":` + author + `,"editor":` + editor +
+		`,"tag":{"pattern":"^[a-z]+$","type":"string"}},"type":"object"}`
+	if got := flattenedJSON(t, flattened); got != expected {
+		t.Errorf("Unexpected flattened schema:\n%s\nExpected:\n%s", got, expected)
+	}
+	// Each schema is fetched once, and the reference in Author.json is
+	// resolved against its name.
+	expectedFetches := map[string]int{
+		"schemas/Author.json":     1,
+		"schemas/common/Tag.json": 1,
+	}
+	if fmt.Sprintf("%v", fetched) != fmt.Sprintf("%v", expectedFetches) {
+		t.Errorf("Unexpected fetches: %v (expected %v)", fetched, expectedFetches)
+	}
+}
+
+func TestFlattenFilesWithSameBaseNames(t *testing.T) {
+	sources := map[string]string{
+		"api/common.json":     `{definitions: {A: {type: string}}}`,
+		"api/sub/types.json":  `{properties: {a: {$ref: "common.json#/definitions/A"}}}`,
+		"api/sub/common.json": `{definitions: {A: {type: integer}}}`,
+	}
+	var fetched []string
+	r := &Resolver{Base: "api/root.json"}
+	r.Fetch = func(name string) (*Schema, error) {
+		fetched = append(fetched, name)
+		source, ok := sources[name]
+		if !ok {
+			return nil, fmt.Errorf("%s not found", name)
+		}
+		return schemaFromString(t, source), nil
+	}
+	root := schemaFromString(t, `
+properties:
+  common: {$ref: "common.json#/definitions/A"}
+  sub: {$ref: "sub/types.json"}
+`)
+	flattened, err := root.Flatten(r)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// common.json in api/sub/types.json is api/sub/common.json,
+	// not the api/common.json that was loaded first.
+	expected := `{"properties":{"common":{"type":"string"},"sub":{"properties":{"a":{"type":"integer"}}}}}`
+	if got := flattenedJSON(t, flattened); got != expected {
+		t.Errorf("Unexpected flattened schema:\n%s\nExpected:\n%s", got, expected)
+	}
+	expectedFetches := "[api/common.json api/sub/types.json api/sub/common.json]"
+	if got := fmt.Sprintf("%v", fetched); got != expectedFetches {
+		t.Errorf("Unexpected fetches: %s (expected %s)", got, expectedFetches)
+	}
+}
+
+func TestFlattenRecursiveReferences(t *testing.T) {
+	schema := schemaFromString(t, `
+type: object
+properties:
+  child: {$ref: "#/definitions/Node"}
+definitions:
+  Node:
+    type: object
+    properties:
+      next: {$ref: "#/definitions/Node"}
+`)
+	if _, err := schema.Flatten(nil); err == nil || !strings.Contains(err.Error(), "recursive reference") {
+		t.Errorf("Expected a recursive reference error, got %v", err)
+	}
+}
+
+func TestFlattenFetchSchema(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		switch req.URL.Path {
+		case "/schemas/Point.json":
+			fmt.Fprint(w, `{"type": "object", "properties": {"x": {"$ref": "Coordinate.json"}, "y": {"$ref": "Coordinate.json"}}}`)
+		case "/schemas/Coordinate.json":
+			fmt.Fprint(w, `{"type": "number"}`)
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+	r := &Resolver{Fetch: FetchSchema}
+	line := schemaFromString(t, fmt.Sprintf(`
+id: %s/schemas/Line.json
+type: array
+items: {$ref: "Point.json"}
+`, server.URL))
+	r.LoadSchema(server.URL+"/schemas/Line.json", line)
+	flattened, err := line.Flatten(r)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := fmt.Sprintf(`{"id":"%s/schemas/Line.json","items":{"properties":`+
+		`{"x":{"type":"number"},"y":{"type":"number"}},"type":"object"},"type":"array"}`, server.URL)
+	if got := flattenedJSON(t, flattened); got != expected {
+		t.Errorf("Unexpected flattened schema:\n%s\nExpected:\n%s", got, expected)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if _, err := schemaFromString(t, `{$ref: "Missing.json"}`).Flatten(r); err == nil {
+		t.Errorf("Expected an error for a missing schema")
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InferOptions control the schemas that InferFromInstance infers.
+type InferOptions struct {
+	// IgnoreFormats disables the guessing of the formats of strings.
+	IgnoreFormats bool
+	// OptionalProperties makes the properties of objects optional.
+	// By default, the properties of an instance are required.
+	OptionalProperties bool
+}
+
+// InferFromInstance returns a schema that describes an instance, such as
+// an example payload. Objects have properties with the schemas of their
+// values, arrays have items that describe all of their elements, numbers
+// are integers if they are written without fractions or exponents, and
+// strings that look like dates, times, UUIDs, email addresses, or URIs
+// get the corresponding formats.
+func InferFromInstance(node *yaml.Node, opts InferOptions) *Schema {
+	if node == nil {
+		return &Schema{}
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return &Schema{}
+		}
+		return InferFromInstance(node.Content[0], opts)
+	case yaml.AliasNode:
+		return InferFromInstance(node.Alias, opts)
+	case yaml.MappingNode:
+		schema := (&Schema{}).WithType("object")
+		schema.Properties = &[]*NamedSchema{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name := node.Content[i].Value
+			schema.AddProperty(name, InferFromInstance(node.Content[i+1], opts))
+			if !opts.OptionalProperties {
+				schema.WithRequired(name)
+			}
+		}
+		return schema
+	case yaml.SequenceNode:
+		schema := (&Schema{}).WithType("array")
+		var items *Schema
+		for _, element := range node.Content {
+			items = mergeInferred(items, InferFromInstance(element, opts))
+		}
+		if items != nil {
+			schema.Items = NewSchemaOrSchemaArrayWithSchema(items)
+		}
+		return schema
+	}
+	switch node.ShortTag() {
+	case "!!null":
+		return (&Schema{}).WithType("null")
+	case "!!bool":
+		return (&Schema{}).WithType("boolean")
+	case "!!int":
+		return (&Schema{}).WithType("integer")
+	case "!!float":
+		return (&Schema{}).WithType("number")
+	case "!!binary":
+		return (&Schema{}).WithType("string").WithFormat("byte")
+	}
+	schema := (&Schema{}).WithType("string")
+	if !opts.IgnoreFormats {
+		if format := stringFormat(node.Value); format != "" {
+			schema.WithFormat(format)
+		}
+	}
+	return schema
+}
+
+// InferFromInstances returns a schema that describes several instances.
+// The schemas of objects have the properties of all instances and require
+// the properties that all instances have; the schemas of other values
+// have the types of all instances.
+func InferFromInstances(nodes []*yaml.Node, opts InferOptions) *Schema {
+	var schema *Schema
+	for _, node := range nodes {
+		schema = mergeInferred(schema, InferFromInstance(node, opts))
+	}
+	if schema == nil {
+		return &Schema{}
+	}
+	return schema
+}
+
+var (
+	uuidRegex  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	emailRegex = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	uriRegex   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://\S+$`)
+)
+
+// stringFormat returns the format of a string that looks like a value of
+// a well-known format, or "".
+func stringFormat(value string) string {
+	if _, err := time.Parse(time.RFC3339, value); err == nil {
+		return "date-time"
+	}
+	if _, err := time.Parse("2006-01-02", value); err == nil {
+		return "date"
+	}
+	if _, err := time.Parse("15:04:05", value); err == nil {
+		return "time"
+	}
+	switch {
+	case uuidRegex.MatchString(value):
+		return "uuid"
+	case emailRegex.MatchString(value):
+		return "email"
+	case uriRegex.MatchString(value):
+		return "uri"
+	}
+	return ""
+}
+
+// inferredTypes returns the types of an inferred schema.
+func inferredTypes(schema *Schema) []string {
+	if schema.Type == nil {
+		return nil
+	}
+	if schema.Type.String != nil {
+		return []string{*schema.Type.String}
+	}
+	if schema.Type.StringArray != nil {
+		return *schema.Type.StringArray
+	}
+	return nil
+}
+
+// mergeInferred returns a schema that describes the instances of two
+// inferred schemas. Integers and numbers merge to numbers, and other
+// different types merge to type arrays.
+func mergeInferred(a, b *Schema) *Schema {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	var types []string
+	seen := make(map[string]bool)
+	for _, t := range append(inferredTypes(a), inferredTypes(b)...) {
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	if seen["integer"] && seen["number"] {
+		types = removeString(types, "integer")
+	}
+	result := &Schema{}
+	switch len(types) {
+	case 0:
+	case 1:
+		result.Type = NewStringOrStringArrayWithString(types[0])
+	default:
+		result.Type = NewStringOrStringArrayWithStringArray(types)
+	}
+	if seen["object"] {
+		result.Properties = &[]*NamedSchema{}
+		for _, s := range []*Schema{a, b} {
+			if s.Properties == nil {
+				continue
+			}
+			for _, property := range *s.Properties {
+				merged := false
+				for _, p := range *result.Properties {
+					if p.Name == property.Name {
+						p.Value = mergeInferred(p.Value, property.Value)
+						merged = true
+					}
+				}
+				if !merged {
+					result.AddProperty(property.Name, property.Value)
+				}
+			}
+		}
+		// Properties are required if all objects require them.
+		switch {
+		case a.Properties != nil && b.Properties != nil:
+			for _, name := range stringsOrNil(a.Required) {
+				if containsString(stringsOrNil(b.Required), name) {
+					result.WithRequired(name)
+				}
+			}
+		case a.Properties != nil:
+			result.Required = a.Required
+		default:
+			result.Required = b.Required
+		}
+	}
+	if seen["array"] {
+		var items *Schema
+		for _, s := range []*Schema{a, b} {
+			if s.Items != nil && s.Items.Schema != nil {
+				items = mergeInferred(items, s.Items.Schema)
+			}
+		}
+		if items != nil {
+			result.Items = NewSchemaOrSchemaArrayWithSchema(items)
+		}
+	}
+	if seen["string"] {
+		// Formats are kept if all strings have the same format.
+		var formats []*string
+		for _, s := range []*Schema{a, b} {
+			if s.TypeIs("string") {
+				formats = append(formats, s.Format)
+			}
+		}
+		if len(formats) == 1 || (formats[0] != nil && formats[1] != nil && *formats[0] == *formats[1]) {
+			result.Format = formats[0]
+		}
+	}
+	return result
+}
+
+func stringsOrNil(values *[]string) []string {
+	if values == nil {
+		return nil
+	}
+	return *values
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(values []string, value string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
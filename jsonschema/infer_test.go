@@ -0,0 +1,178 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func inferTestNode(t *testing.T, text string) *yaml.Node {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(text), &node); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return &node
+}
+
+func TestInferFromInstance(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance string
+		opts     InferOptions
+		expected string
+	}{
+		{
+			name:     "object",
+			instance: `{"id": 7, "price": 1.5, "name": "Fido", "vaccinated": true, "owner": null}`,
+			expected: `type: object
+required:
+    - id
+    - price
+    - name
+    - vaccinated
+    - owner
+properties:
+    id:
+        type: integer
+    price:
+        type: number
+    name:
+        type: string
+    vaccinated:
+        type: boolean
+    owner:
+        type: "null"
+`,
+		},
+		{
+			name:     "optional properties",
+			instance: `{"id": 7}`,
+			opts:     InferOptions{OptionalProperties: true},
+			expected: `type: object
+properties:
+    id:
+        type: integer
+`,
+		},
+		{
+			name:     "array of numbers",
+			instance: `[1, 2.5, 3]`,
+			expected: `type: array
+items:
+    type: number
+`,
+		},
+		{
+			name:     "array of mixed values",
+			instance: `["a", 1, null]`,
+			expected: `type: array
+items:
+    type:
+        - string
+        - integer
+        - "null"
+`,
+		},
+		{
+			name:     "array of objects",
+			instance: `[{"id": 1, "tag": "a"}, {"id": 2}]`,
+			expected: `type: array
+items:
+    type: object
+    required:
+        - id
+    properties:
+        id:
+            type: integer
+        tag:
+            type: string
+`,
+		},
+		{
+			name:     "formats",
+			instance: `["2023-04-01T12:00:00Z", "2023-04-01", "0b8e6c44-9a4c-4c4f-8b1d-3a4c5a6b7c8d", "fido@example.com", "https://example.com/pets"]`,
+			expected: `type: array
+items:
+    type: string
+`,
+		},
+		{
+			name:     "date-time",
+			instance: `"2023-04-01T12:00:00Z"`,
+			expected: `type: string
+format: date-time
+`,
+		},
+		{
+			name:     "date",
+			instance: `"2023-04-01"`,
+			expected: `type: string
+format: date
+`,
+		},
+		{
+			name:     "uuid",
+			instance: `"0b8e6c44-9a4c-4c4f-8b1d-3a4c5a6b7c8d"`,
+			expected: `type: string
+format: uuid
+`,
+		},
+		{
+			name:     "ignored formats",
+			instance: `"0b8e6c44-9a4c-4c4f-8b1d-3a4c5a6b7c8d"`,
+			opts:     InferOptions{IgnoreFormats: true},
+			expected: `type: string
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema := InferFromInstance(inferTestNode(t, test.instance), test.opts)
+			if got := schema.YAMLString(); got != test.expected {
+				t.Errorf("unexpected schema:\n%s\nexpected:\n%s", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestInferFromInstances(t *testing.T) {
+	nodes := []*yaml.Node{
+		inferTestNode(t, `{"id": 1, "name": "Fido", "born": "2020-01-01"}`),
+		inferTestNode(t, `{"id": 2.5, "born": "2021-06-30", "tag": "dog"}`),
+	}
+	expected := `type: object
+required:
+    - id
+    - born
+properties:
+    id:
+        type: number
+    name:
+        type: string
+    born:
+        type: string
+        format: date
+    tag:
+        type: string
+`
+	if got := InferFromInstances(nodes, InferOptions{}).YAMLString(); got != expected {
+		t.Errorf("unexpected schema:\n%s\nexpected:\n%s", got, expected)
+	}
+	if got := InferFromInstances(nil, InferOptions{}).YAMLString(); got != "{}\n" {
+		t.Errorf("unexpected schema for no instances: %q", got)
+	}
+}
@@ -16,7 +16,11 @@
 // of JSON Schemas.
 package jsonschema
 
-import "gopkg.in/yaml.v3"
+import (
+	"math"
+
+	"gopkg.in/yaml.v3"
+)
 
 // The Schema struct models a JSON Schema and, because schemas are
 // defined hierarchically, contains many references to itself.
@@ -60,6 +64,7 @@ type Schema struct {
 
 	// 5.5.  Validation keywords for any instance type
 	Enumeration *[]SchemaEnumValue
+	Const       *SchemaEnumValue
 	Type        *StringOrStringArray
 	AllOf       *[]*Schema
 	AnyOf       *[]*Schema
@@ -71,9 +76,30 @@ type Schema struct {
 	Title       *string
 	Description *string
 	Default     *DefaultValue
+	Examples    *[]*DefaultValue // added in draft-06
 
 	// 7.  Semantic validation with "format"
 	Format *string
+
+	// boolean is set for the boolean schemas true and false,
+	// which are created with NewBooleanSchema.
+	boolean *bool
+}
+
+// NewBooleanSchema creates and returns a boolean schema, which accepts
+// every value if value is true and no value if value is false. Boolean
+// schemas are written as true or false, e.g. for "additionalProperties".
+func NewBooleanSchema(value bool) *Schema {
+	return &Schema{boolean: &value}
+}
+
+// BooleanValue returns the value of a boolean schema and true, or false
+// and false if the schema isn't a boolean schema.
+func (schema *Schema) BooleanValue() (value bool, ok bool) {
+	if schema.boolean == nil {
+		return false, false
+	}
+	return *schema.boolean, true
 }
 
 // These helper structs represent "combination" types that generally can
@@ -106,8 +132,12 @@ type SchemaOrBoolean struct {
 	Boolean *bool
 }
 
-// NewSchemaOrBooleanWithSchema creates and returns a new object
+// NewSchemaOrBooleanWithSchema creates and returns a new object.
+// Boolean schemas are stored as booleans.
 func NewSchemaOrBooleanWithSchema(s *Schema) *SchemaOrBoolean {
+	if b, ok := s.BooleanValue(); ok {
+		return NewSchemaOrBooleanWithBoolean(b)
+	}
 	result := &SchemaOrBoolean{}
 	result.Schema = s
 	return result
@@ -224,11 +254,177 @@ func (s *Schema) DefinitionWithName(name string) *Schema {
 	return namedSchemaArrayElementWithName(s.Definitions, name)
 }
 
+// CountProperties returns the number of properties of a Schema.
+func (s *Schema) CountProperties() int {
+	if s == nil || s.Properties == nil {
+		return 0
+	}
+	return len(*s.Properties)
+}
+
+// CountDefinitions returns the number of definitions of a Schema.
+func (s *Schema) CountDefinitions() int {
+	if s == nil || s.Definitions == nil {
+		return 0
+	}
+	return len(*s.Definitions)
+}
+
 // AddProperty adds a named property.
 func (s *Schema) AddProperty(name string, property *Schema) {
 	*s.Properties = append(*s.Properties, NewNamedSchema(name, property))
 }
 
+// RemoveProperty removes a named property and its name from the required
+// properties. It returns false if the property doesn't exist.
+func (s *Schema) RemoveProperty(name string) bool {
+	if s.Properties == nil {
+		return false
+	}
+	for i, pair := range *s.Properties {
+		if pair.Name != name {
+			continue
+		}
+		*s.Properties = append((*s.Properties)[:i], (*s.Properties)[i+1:]...)
+		if s.Required != nil {
+			required := make([]string, 0, len(*s.Required))
+			for _, r := range *s.Required {
+				if r != name {
+					required = append(required, r)
+				}
+			}
+			*s.Required = required
+		}
+		return true
+	}
+	return false
+}
+
+// AddDefinition adds a named definition, replacing any definition with
+// the same name.
+func (s *Schema) AddDefinition(name string, definition *Schema) {
+	if s.Definitions == nil {
+		s.Definitions = &[]*NamedSchema{}
+	}
+	for _, pair := range *s.Definitions {
+		if pair.Name == name {
+			pair.Value = definition
+			return
+		}
+	}
+	*s.Definitions = append(*s.Definitions, NewNamedSchema(name, definition))
+}
+
+// GetDefinition returns a named definition. It returns false if the
+// definition doesn't exist.
+func (s *Schema) GetDefinition(name string) (*Schema, bool) {
+	if s.Definitions == nil {
+		return nil, false
+	}
+	for _, pair := range *s.Definitions {
+		if pair.Name == name {
+			return pair.Value, true
+		}
+	}
+	return nil, false
+}
+
+// RemoveDefinition removes a named definition. It returns false if the
+// definition doesn't exist.
+func (s *Schema) RemoveDefinition(name string) bool {
+	if s.Definitions == nil {
+		return false
+	}
+	for i, pair := range *s.Definitions {
+		if pair.Name == name {
+			*s.Definitions = append((*s.Definitions)[:i], (*s.Definitions)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Build schemas with chains of calls, e.g.
+// (&Schema{}).WithType("string").WithFormat("date-time").WithTitle("Created At")
+
+// WithType sets the type of a Schema and returns the Schema.
+func (s *Schema) WithType(typeName string) *Schema {
+	s.Type = &StringOrStringArray{String: &typeName}
+	return s
+}
+
+// WithFormat sets the format of a Schema and returns the Schema.
+func (s *Schema) WithFormat(format string) *Schema {
+	s.Format = &format
+	return s
+}
+
+// WithTitle sets the title of a Schema and returns the Schema.
+func (s *Schema) WithTitle(title string) *Schema {
+	s.Title = &title
+	return s
+}
+
+// WithDescription sets the description of a Schema and returns the Schema.
+func (s *Schema) WithDescription(description string) *Schema {
+	s.Description = &description
+	return s
+}
+
+// WithRequired adds names to the required properties of a Schema and returns the Schema.
+func (s *Schema) WithRequired(names ...string) *Schema {
+	if s.Required == nil {
+		s.Required = &[]string{}
+	}
+	*s.Required = append(*s.Required, names...)
+	return s
+}
+
+// WithDefault sets the default value of a Schema and returns the Schema.
+// The value can be a string, bool, int, int64, float64, nil, or a *DefaultValue.
+// Values of other types are ignored.
+func (s *Schema) WithDefault(value interface{}) *Schema {
+	switch v := value.(type) {
+	case *DefaultValue:
+		s.Default = v
+	case nil:
+		s.Default = &DefaultValue{NullTag: true}
+	case string:
+		s.Default = &DefaultValue{StringValue: &v}
+	case bool:
+		s.Default = &DefaultValue{BooleanValue: &v}
+	case int:
+		i := int64(v)
+		s.Default = &DefaultValue{Int64Value: &i}
+	case int64:
+		s.Default = &DefaultValue{Int64Value: &v}
+	case float64:
+		s.Default = &DefaultValue{Float64Value: &v}
+	}
+	return s
+}
+
+// WithMinimum sets the minimum of a Schema and returns the Schema.
+func (s *Schema) WithMinimum(minimum float64) *Schema {
+	s.Minimum = newSchemaNumber(minimum)
+	return s
+}
+
+// WithMaximum sets the maximum of a Schema and returns the Schema.
+func (s *Schema) WithMaximum(maximum float64) *Schema {
+	s.Maximum = newSchemaNumber(maximum)
+	return s
+}
+
+// newSchemaNumber returns an integer SchemaNumber for whole numbers,
+// which matches the way that numbers are read.
+func newSchemaNumber(f float64) *SchemaNumber {
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return NewSchemaNumberWithInteger(int64(f))
+	}
+	return NewSchemaNumberWithFloat(f)
+}
+
 type DefaultValue struct {
 	StringValue  *string
 	BooleanValue *bool
@@ -237,3 +433,28 @@ type DefaultValue struct {
 	ArrayValue   []*yaml.Node
 	NullTag      bool
 }
+
+// AsInterface returns the value of a DefaultValue as a string, bool,
+// int64, float64, or []interface{} with the decoded items of an array.
+// It returns nil for the null value and for an empty DefaultValue.
+func (d *DefaultValue) AsInterface() interface{} {
+	switch {
+	case d == nil:
+		return nil
+	case d.StringValue != nil:
+		return *d.StringValue
+	case d.BooleanValue != nil:
+		return *d.BooleanValue
+	case d.Int64Value != nil:
+		return *d.Int64Value
+	case d.Float64Value != nil:
+		return *d.Float64Value
+	case d.ArrayValue != nil:
+		values := make([]interface{}, len(d.ArrayValue))
+		for i, node := range d.ArrayValue {
+			node.Decode(&values[i])
+		}
+		return values
+	}
+	return nil
+}
@@ -25,10 +25,19 @@ import (
 // The following methods perform operations on Schemas.
 //
 
-// IsEmpty returns true if no members of the Schema are specified.
+// IsEmpty returns true if the Schema accepts any value because it has
+// no type, $ref, combinators, or validation keywords. Annotations like
+// titles, descriptions, defaults, examples, and definitions don't
+// constrain values, and neither do additionalProperties and
+// additionalItems that are true or empty schemas. The boolean schema
+// true is empty and the boolean schema false is not.
 func (schema *Schema) IsEmpty() bool {
-	return (schema.Schema == nil) &&
-		(schema.ID == nil) &&
+	if b, ok := schema.BooleanValue(); ok {
+		return b
+	}
+	return (schema.Ref == nil) &&
+		(schema.Type == nil) &&
+		(schema.Format == nil) &&
 		(schema.MultipleOf == nil) &&
 		(schema.Maximum == nil) &&
 		(schema.ExclusiveMaximum == nil) &&
@@ -37,7 +46,7 @@ func (schema *Schema) IsEmpty() bool {
 		(schema.MaxLength == nil) &&
 		(schema.MinLength == nil) &&
 		(schema.Pattern == nil) &&
-		(schema.AdditionalItems == nil) &&
+		schema.AdditionalItems.acceptsAnything() &&
 		(schema.Items == nil) &&
 		(schema.MaxItems == nil) &&
 		(schema.MinItems == nil) &&
@@ -45,22 +54,46 @@ func (schema *Schema) IsEmpty() bool {
 		(schema.MaxProperties == nil) &&
 		(schema.MinProperties == nil) &&
 		(schema.Required == nil) &&
-		(schema.AdditionalProperties == nil) &&
+		schema.AdditionalProperties.acceptsAnything() &&
 		(schema.Properties == nil) &&
 		(schema.PatternProperties == nil) &&
 		(schema.Dependencies == nil) &&
 		(schema.Enumeration == nil) &&
-		(schema.Type == nil) &&
+		(schema.Const == nil) &&
 		(schema.AllOf == nil) &&
 		(schema.AnyOf == nil) &&
 		(schema.OneOf == nil) &&
-		(schema.Not == nil) &&
-		(schema.Definitions == nil) &&
-		(schema.Title == nil) &&
-		(schema.Description == nil) &&
-		(schema.Default == nil) &&
-		(schema.Format == nil) &&
-		(schema.Ref == nil)
+		(schema.Not == nil)
+}
+
+// acceptsAnything returns true if a SchemaOrBoolean is missing, true,
+// or an empty schema.
+func (s *SchemaOrBoolean) acceptsAnything() bool {
+	return s == nil ||
+		(s.Boolean != nil && *s.Boolean) ||
+		(s.Schema != nil && s.Schema.IsEmpty())
+}
+
+// HasConstraints returns true if the Schema constrains values beyond
+// their type and $ref. Optimizers can skip validation of schemas
+// without constraints.
+func (schema *Schema) HasConstraints() bool {
+	return (schema.Minimum != nil) ||
+		(schema.Maximum != nil) ||
+		(schema.MinLength != nil) ||
+		(schema.MaxLength != nil) ||
+		(schema.Pattern != nil) ||
+		(schema.Enumeration != nil) ||
+		(schema.Const != nil) ||
+		(schema.Required != nil) ||
+		(schema.MinItems != nil) ||
+		(schema.MaxItems != nil) ||
+		(schema.MinProperties != nil) ||
+		(schema.MaxProperties != nil) ||
+		(schema.AllOf != nil) ||
+		(schema.AnyOf != nil) ||
+		(schema.OneOf != nil) ||
+		(schema.Not != nil)
 }
 
 // IsEqual returns true if two schemas are equal.
@@ -221,6 +254,9 @@ func (schema *Schema) CopyProperties(source *Schema) {
 	if source.Enumeration != nil {
 		schema.Enumeration = source.Enumeration
 	}
+	if source.Const != nil {
+		schema.Const = source.Const
+	}
 	if source.Type != nil {
 		schema.Type = source.Type
 	}
@@ -248,6 +284,9 @@ func (schema *Schema) CopyProperties(source *Schema) {
 	if source.Default != nil {
 		schema.Default = source.Default
 	}
+	if source.Examples != nil {
+		schema.Examples = source.Examples
+	}
 	if source.Format != nil {
 		schema.Format = source.Format
 	}
@@ -273,6 +312,30 @@ func (schema *Schema) TypeIs(typeName string) bool {
 	return false
 }
 
+// InferType returns the type of a Schema. If the Schema doesn't specify
+// a single type, a Schema with properties is an "object" and a Schema with
+// items is an "array". InferType returns "" if the type can't be inferred.
+func (schema *Schema) InferType() string {
+	if schema.Type != nil {
+		if schema.Type.String != nil {
+			return *schema.Type.String
+		}
+		if schema.Type.StringArray != nil && len(*schema.Type.StringArray) == 1 {
+			return (*schema.Type.StringArray)[0]
+		}
+		return ""
+	}
+	hasProperties := schema.Properties != nil
+	hasItems := schema.Items != nil
+	if hasProperties && !hasItems {
+		return "object"
+	}
+	if hasItems && !hasProperties {
+		return "array"
+	}
+	return ""
+}
+
 // ResolveRefs resolves "$ref" elements in a Schema and its children.
 // But if a reference refers to an object type, is inside a oneOf, or contains a oneOf,
 // the reference is kept and we expect downstream tools to separately model these
@@ -0,0 +1,241 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestInferType(t *testing.T) {
+	tests := []struct {
+		schema   string
+		expected string
+	}{
+		{`{"type": "string"}`, "string"},
+		{`{"type": ["integer"]}`, "integer"},
+		{`{"type": ["string", "null"]}`, ""},
+		{`{"type": "array", "properties": {"a": {}}}`, "array"},
+		{`{"properties": {"a": {"type": "string"}}}`, "object"},
+		{`{"items": {"type": "string"}}`, "array"},
+		{`{"properties": {}, "items": {}}`, ""},
+		{`{"minimum": 1}`, ""},
+	}
+	for _, test := range tests {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(test.schema), &node); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		schema := NewSchemaFromObject(&node)
+		if got := schema.InferType(); got != test.expected {
+			t.Errorf("InferType() of %s returned %q (expected %q)", test.schema, got, test.expected)
+		}
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	tests := []struct {
+		schema   string
+		expected bool
+	}{
+		{`{}`, true},
+		{`{"title": "Anything", "description": "Any value.", "default": 1, "readOnly": true}`, true},
+		{`{"definitions": {"a": {"type": "string"}}}`, true},
+		{`{"additionalProperties": true, "additionalItems": {}}`, true},
+		{`{"additionalProperties": false}`, false},
+		{`{"additionalProperties": {"type": "string"}}`, false},
+		{`{"type": "object"}`, false},
+		{`{"$ref": "#/definitions/a"}`, false},
+		{`{"properties": {}}`, false},
+		{`{"allOf": [{}]}`, false},
+		{`{"not": {}}`, false},
+		{`{"minimum": 1}`, false},
+		{`{"maxLength": 1}`, false},
+		{`{"minItems": 1}`, false},
+		{`{"enum": ["a"]}`, false},
+		{`{"format": "date"}`, false},
+	}
+	for _, test := range tests {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(test.schema), &node); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		schema := NewSchemaFromObject(&node)
+		if got := schema.IsEmpty(); got != test.expected {
+			t.Errorf("IsEmpty() of %s returned %t (expected %t)", test.schema, got, test.expected)
+		}
+	}
+}
+
+func TestSchemaBuilders(t *testing.T) {
+	schema := (&Schema{}).
+		WithType("object").
+		WithTitle("Event").
+		WithDescription("An event.").
+		WithRequired("createdAt").
+		WithRequired("count")
+	schema.Properties = &[]*NamedSchema{}
+	schema.AddProperty("createdAt", (&Schema{}).WithType("string").WithFormat("date-time").WithTitle("Created At"))
+	schema.AddProperty("count", (&Schema{}).WithType("integer").WithMinimum(0).WithMaximum(10).WithDefault(1))
+	schema.AddProperty("ratio", (&Schema{}).WithType("number").WithMaximum(0.5).WithDefault(0.25))
+	expected := `{
+  "title": "Event",
+  "type": "object",
+  "description": "An event.",
+  "required": [
+    "createdAt",
+    "count"
+  ],
+  "properties": {
+    "createdAt": {
+      "title": "Created At",
+      "type": "string",
+      "format": "date-time"
+    },
+    "count": {
+      "type": "integer",
+      "maximum": 10,
+      "minimum": 0,
+      "default": 1
+    },
+    "ratio": {
+      "type": "number",
+      "maximum": 0.5,
+      "default": 0.25
+    }
+  }
+}
+`
+	if got := schema.JSONString(); got != expected {
+		t.Errorf("unexpected schema:\n%s\n(expected)\n%s", got, expected)
+	}
+}
+
+func TestDefaultValueAsInterface(t *testing.T) {
+	tests := []struct {
+		schema   string
+		expected string
+	}{
+		{`{"default": "red"}`, "string red"},
+		{`{"default": true}`, "bool true"},
+		{`{"default": 3}`, "int64 3"},
+		{`{"default": 0.5}`, "float64 0.5"},
+		{`{"default": [1, "a"]}`, "[]interface {} [1 a]"},
+		{`{"default": null}`, "<nil> <nil>"},
+		{`{}`, "<nil> <nil>"},
+	}
+	for _, test := range tests {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(test.schema), &node); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		value := NewSchemaFromObject(&node).Default.AsInterface()
+		if got := fmt.Sprintf("%T %v", value, value); got != test.expected {
+			t.Errorf("AsInterface() of %s: got %q, expected %q", test.schema, got, test.expected)
+		}
+	}
+}
+
+func TestRemoveProperty(t *testing.T) {
+	schema := schemaFromString(t, `
+type: object
+required: [name, size]
+properties:
+  name:
+    type: string
+  size:
+    type: integer
+  color:
+    type: string
+`)
+	if !schema.RemoveProperty("size") {
+		t.Errorf("RemoveProperty returned false for an existing property")
+	}
+	if schema.RemoveProperty("size") || schema.RemoveProperty("weight") {
+		t.Errorf("RemoveProperty returned true for a missing property")
+	}
+	names := make([]string, 0)
+	for _, pair := range *schema.Properties {
+		names = append(names, pair.Name)
+	}
+	if got := strings.Join(names, ","); got != "name,color" {
+		t.Errorf("Unexpected properties: %s", got)
+	}
+	if got := strings.Join(*schema.Required, ","); got != "name" {
+		t.Errorf("Unexpected required properties: %s", got)
+	}
+	if (&Schema{}).RemoveProperty("name") {
+		t.Errorf("RemoveProperty returned true for a schema without properties")
+	}
+}
+
+func TestDefinitions(t *testing.T) {
+	schema := &Schema{}
+	if _, ok := schema.GetDefinition("shape"); ok {
+		t.Errorf("GetDefinition found a definition in a schema without definitions")
+	}
+	if schema.RemoveDefinition("shape") {
+		t.Errorf("RemoveDefinition returned true for a schema without definitions")
+	}
+	schema.AddDefinition("shape", (&Schema{}).WithType("string"))
+	schema.AddDefinition("size", (&Schema{}).WithType("integer"))
+	schema.AddDefinition("shape", (&Schema{}).WithType("object"))
+	if schema.CountDefinitions() != 2 {
+		t.Fatalf("Unexpected number of definitions: %d", schema.CountDefinitions())
+	}
+	if shape, ok := schema.GetDefinition("shape"); !ok || !shape.TypeIs("object") {
+		t.Errorf("AddDefinition didn't replace the shape definition")
+	}
+	if !schema.RemoveDefinition("shape") {
+		t.Errorf("RemoveDefinition returned false for an existing definition")
+	}
+	if schema.RemoveDefinition("shape") {
+		t.Errorf("RemoveDefinition returned true for a missing definition")
+	}
+	if _, ok := schema.GetDefinition("size"); !ok || schema.CountDefinitions() != 1 {
+		t.Errorf("RemoveDefinition removed the wrong definition")
+	}
+}
+
+func TestCountPropertiesAndDefinitions(t *testing.T) {
+	var missing *Schema
+	if missing.CountProperties() != 0 || missing.CountDefinitions() != 0 {
+		t.Errorf("Unexpected counts for a nil schema")
+	}
+	schema := &Schema{}
+	if schema.CountProperties() != 0 || schema.CountDefinitions() != 0 {
+		t.Errorf("Unexpected counts for an empty schema")
+	}
+	schema = schemaFromString(t, `
+type: object
+properties:
+  name:
+    type: string
+  size:
+    type: integer
+definitions:
+  color:
+    type: string
+`)
+	if schema.CountProperties() != 2 {
+		t.Errorf("Unexpected number of properties: %d", schema.CountProperties())
+	}
+	if schema.CountDefinitions() != 1 {
+		t.Errorf("Unexpected number of definitions: %d", schema.CountDefinitions())
+	}
+}
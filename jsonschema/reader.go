@@ -123,6 +123,8 @@ func NewSchemaFromObject(jsonData *yaml.Node) *Schema {
 
 			case "enum":
 				schema.Enumeration = schema.arrayOfEnumValuesValue(v)
+			case "const":
+				schema.Const = schema.enumValueValue(v)
 
 			case "type":
 				schema.Type = schema.stringOrStringArrayValue(v)
@@ -134,7 +136,7 @@ func NewSchemaFromObject(jsonData *yaml.Node) *Schema {
 				schema.OneOf = schema.arrayOfSchemasValue(v)
 			case "not":
 				schema.Not = NewSchemaFromObject(v)
-			case "definitions":
+			case "definitions", "$defs":
 				schema.Definitions = schema.mapOfSchemasValue(v)
 
 			case "title":
@@ -144,11 +146,17 @@ func NewSchemaFromObject(jsonData *yaml.Node) *Schema {
 
 			case "default":
 				schema.Default = schema.defaultValue(v)
+			case "examples":
+				schema.Examples = schema.arrayOfDefaultValuesValue(v)
 
 			case "format":
 				schema.Format = schema.stringValue(v)
 			case "$ref":
 				schema.Ref = schema.stringValue(v)
+			case "readOnly":
+				schema.ReadOnly = schema.boolValue(v)
+			case "writeOnly":
+				schema.WriteOnly = schema.boolValue(v)
 			default:
 				fmt.Printf("UNSUPPORTED (%s)\n", k)
 			}
@@ -163,6 +171,13 @@ func NewSchemaFromObject(jsonData *yaml.Node) *Schema {
 		}
 		return schema
 
+	case yaml.ScalarNode:
+		// Boolean schemas accept every value or no value.
+		if b, err := strconv.ParseBool(jsonData.Value); err == nil && jsonData.Tag == "!!bool" {
+			return NewBooleanSchema(b)
+		}
+		fmt.Printf("schemaValue: unexpected node %+v\n", jsonData)
+
 	default:
 		fmt.Printf("schemaValue: unexpected node %+v\n", jsonData)
 	}
@@ -397,19 +412,8 @@ func (schema *Schema) arrayOfEnumValuesValue(v *yaml.Node) *[]SchemaEnumValue {
 	switch v.Kind {
 	case yaml.SequenceNode:
 		for _, v2 := range v.Content {
-			switch v2.Kind {
-			case yaml.ScalarNode:
-				switch v2.Tag {
-				case "!!str":
-					a = append(a, SchemaEnumValue{String: &v2.Value})
-				case "!!bool":
-					v3, _ := strconv.ParseBool(v2.Value)
-					a = append(a, SchemaEnumValue{Bool: &v3})
-				default:
-					fmt.Printf("arrayOfEnumValuesValue: unexpected type %s\n", v2.Tag)
-				}
-			default:
-				fmt.Printf("arrayOfEnumValuesValue: unexpected node %+v\n", v2)
+			if value := schema.enumValueValue(v2); value != nil {
+				a = append(a, *value)
 			}
 		}
 	default:
@@ -418,6 +422,41 @@ func (schema *Schema) arrayOfEnumValuesValue(v *yaml.Node) *[]SchemaEnumValue {
 	return &a
 }
 
+// Gets an array of values like the value of "default" from an interface{} value if possible.
+func (schema *Schema) arrayOfDefaultValuesValue(v *yaml.Node) *[]*DefaultValue {
+	a := make([]*DefaultValue, 0)
+	switch v.Kind {
+	case yaml.SequenceNode:
+		for _, v2 := range v.Content {
+			if value := schema.defaultValue(v2); value != nil {
+				a = append(a, value)
+			}
+		}
+	default:
+		fmt.Printf("arrayOfDefaultValuesValue: unexpected node %+v\n", v)
+	}
+	return &a
+}
+
+// Gets an enum value from an interface{} value if possible.
+func (schema *Schema) enumValueValue(v *yaml.Node) *SchemaEnumValue {
+	switch v.Kind {
+	case yaml.ScalarNode:
+		switch v.Tag {
+		case "!!str":
+			return &SchemaEnumValue{String: &v.Value}
+		case "!!bool":
+			v2, _ := strconv.ParseBool(v.Value)
+			return &SchemaEnumValue{Bool: &v2}
+		default:
+			fmt.Printf("enumValueValue: unexpected type %s\n", v.Tag)
+		}
+	default:
+		fmt.Printf("enumValueValue: unexpected node %+v\n", v)
+	}
+	return nil
+}
+
 // Gets a map of schemas or string arrays from an interface{} value if possible.
 func (schema *Schema) mapOfSchemasOrStringArraysValue(v *yaml.Node) *[]*NamedSchemaOrStringArray {
 	m := make([]*NamedSchemaOrStringArray, 0)
@@ -0,0 +1,326 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// A Resolver resolves references in a set of schemas that are loaded by name,
+// such as the files of a multi-file schema set.
+// The zero value is an empty Resolver that is ready to use.
+type Resolver struct {
+	// Base names the schema that local references ("#/...") are resolved
+	// against. If it is empty, the first loaded schema is used.
+	Base string
+
+	// Fetch, if set, is called to read schemas that references refer to but
+	// that haven't been loaded, such as remote schemas. It is called with the
+	// name in the reference resolved against the name or URL id of the schema
+	// that contains the reference. Fetched schemas are loaded into the
+	// Resolver and failures are remembered, so each name is fetched at most
+	// once. FetchSchema reads files and URLs.
+	Fetch func(name string) (*Schema, error)
+
+	names   []string
+	schemas map[string]*Schema
+	failed  map[string]error
+}
+
+// FetchSchema reads a schema from a file or URL in the same way that
+// gnostic reads API descriptions. It can be used as the Fetch function of
+// a Resolver.
+func FetchSchema(name string) (*Schema, error) {
+	bytes, err := compiler.ReadBytesForFile(name)
+	if err != nil {
+		return nil, err
+	}
+	node, err := compiler.ParseYAML(name, bytes)
+	if err != nil {
+		return nil, err
+	}
+	if len(node.Content) == 0 {
+		return nil, fmt.Errorf("%s is empty", name)
+	}
+	return NewSchemaFromObject(node), nil
+}
+
+// LoadSchema adds a schema to the set of schemas that references can refer to.
+// Schemas can be referenced by name, relative to the schema that refers to
+// them, and by their id, if they have one. Names that match no schema are
+// also matched against the base names (e.g. "Other.json" for
+// "schemas/Other.json") of the loaded schemas, if only one schema matches
+// and the Resolver has no Fetch function.
+func (r *Resolver) LoadSchema(name string, schema *Schema) {
+	if r.schemas == nil {
+		r.schemas = make(map[string]*Schema)
+	}
+	if _, ok := r.schemas[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.schemas[name] = schema
+}
+
+// Resolve returns the schema that a reference refers to.
+// Local references ("#/definitions/Name") are resolved against the base schema
+// and cross-file references ("Other.json#/definitions/Name") against the
+// loaded schema with the named file.
+func (r *Resolver) Resolve(ref string) (*Schema, error) {
+	return r.ResolveFrom(r.Base, ref)
+}
+
+// ResolveFrom resolves a reference that appears in the named schema.
+func (r *Resolver) ResolveFrom(base string, ref string) (*Schema, error) {
+	result, _, err := r.resolve(base, ref)
+	return result, err
+}
+
+// resolve resolves a reference that appears in the named schema and also
+// returns the name of the schema that contains the result, which is the
+// base of the references in the result.
+func (r *Resolver) resolve(base string, ref string) (*Schema, string, error) {
+	document, pointer := splitReference(ref)
+	name, schema, err := r.schemaForDocument(base, document)
+	if err != nil {
+		return nil, "", fmt.Errorf("unresolved reference: %s (%s)", ref, err.Error())
+	}
+	result, err := schema.schemaForJSONPointer(pointer)
+	if err != nil {
+		return nil, "", fmt.Errorf("unresolved reference: %s (%s)", ref, err.Error())
+	}
+	return result, name, nil
+}
+
+// schemaForDocument returns the schema that the document part of a
+// reference in the named schema refers to, and the name of that schema.
+// The document is resolved against the name or URL id of the referring
+// schema before it is looked up as given, so that references to files with
+// the same base name in different directories refer to different schemas.
+func (r *Resolver) schemaForDocument(base string, document string) (string, *Schema, error) {
+	if base == "" {
+		base = r.Base
+	}
+	if document == "" {
+		if name, schema := r.schemaNamed(base); schema != nil {
+			return name, schema, nil
+		}
+		return "", nil, fmt.Errorf("unknown schema %q", base)
+	}
+	resolved := resolveName(r.baseURI(base), document)
+	if name, schema := r.schemaNamed(resolved); schema != nil {
+		return name, schema, nil
+	}
+	if r.Fetch != nil {
+		// Other schemas with the name or base name might be different files.
+		return r.fetch(resolved)
+	}
+	if name, schema := r.schemaNamed(document); schema != nil {
+		return name, schema, nil
+	}
+	matches := r.namesWithBase(document)
+	switch len(matches) {
+	case 0:
+		return "", nil, fmt.Errorf("unknown schema %q", document)
+	case 1:
+		return matches[0], r.schemas[matches[0]], nil
+	default:
+		return "", nil, fmt.Errorf("ambiguous schema %q (matches %s)", document, strings.Join(matches, ", "))
+	}
+}
+
+// baseURI returns the name that references in the named schema are
+// resolved against, which is its id if that is a URL.
+func (r *Resolver) baseURI(base string) string {
+	name, schema := r.schemaNamed(base)
+	if schema == nil {
+		return base
+	}
+	if schema.ID != nil && isURL(*schema.ID) {
+		return strings.TrimSuffix(*schema.ID, "#")
+	}
+	return name
+}
+
+// fetch reads and loads the schema with a resolved name.
+func (r *Resolver) fetch(name string) (string, *Schema, error) {
+	if err, ok := r.failed[name]; ok {
+		return name, nil, err
+	}
+	schema, err := r.Fetch(name)
+	if err != nil {
+		if r.failed == nil {
+			r.failed = make(map[string]error)
+		}
+		r.failed[name] = err
+		return name, nil, err
+	}
+	r.LoadSchema(name, schema)
+	return name, schema, nil
+}
+
+// splitReference splits a reference into the name of a schema and a
+// JSON Pointer. Either can be empty.
+func splitReference(ref string) (document string, pointer string) {
+	if i := strings.Index(ref, "#"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// resolveName resolves the name of a schema, such as "Other.json",
+// against the name or URL of the schema that refers to it.
+func resolveName(base string, name string) string {
+	if base == "" || isURL(name) || path.IsAbs(name) {
+		return name
+	}
+	if isURL(base) {
+		if b, err := url.Parse(base); err == nil {
+			if n, err := url.Parse(name); err == nil {
+				return b.ResolveReference(n).String()
+			}
+		}
+	}
+	return path.Join(path.Dir(base), name)
+}
+
+func isURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
+// schemaNamed returns the loaded schema with a name or id, and the name
+// that it was loaded with. An empty name selects the first loaded schema.
+func (r *Resolver) schemaNamed(name string) (string, *Schema) {
+	if name == "" {
+		if len(r.names) == 0 {
+			return "", nil
+		}
+		return r.names[0], r.schemas[r.names[0]]
+	}
+	if schema, ok := r.schemas[name]; ok {
+		return name, schema
+	}
+	for _, n := range r.names {
+		schema := r.schemas[n]
+		if schema.ID != nil && strings.TrimSuffix(*schema.ID, "#") == strings.TrimSuffix(name, "#") {
+			return n, schema
+		}
+	}
+	return "", nil
+}
+
+// namesWithBase returns the names of the loaded schemas that have the
+// same base name as name.
+func (r *Resolver) namesWithBase(name string) []string {
+	var matches []string
+	for _, n := range r.names {
+		if path.Base(n) == path.Base(name) {
+			matches = append(matches, n)
+		}
+	}
+	return matches
+}
+
+// schemaForJSONPointer returns the subschema that a JSON Pointer refers to.
+func (schema *Schema) schemaForJSONPointer(pointer string) (*Schema, error) {
+	if unescaped, err := url.PathUnescape(pointer); err == nil {
+		pointer = unescaped
+	}
+	if pointer == "" || pointer == "/" {
+		return schema, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(tokens[i])
+	}
+	result := schema
+	for i := 0; i < len(tokens); i++ {
+		var next *Schema
+		token := tokens[i]
+		// The value of this keyword may be selected by the next token.
+		argument := func() (string, bool) {
+			if i+1 < len(tokens) {
+				i++
+				return tokens[i], true
+			}
+			return "", false
+		}
+		switch token {
+		case "definitions", "$defs":
+			if name, ok := argument(); ok {
+				next = namedSchemaArrayElementWithName(result.Definitions, name)
+			}
+		case "properties":
+			if name, ok := argument(); ok {
+				next = namedSchemaArrayElementWithName(result.Properties, name)
+			}
+		case "patternProperties":
+			if name, ok := argument(); ok {
+				next = namedSchemaArrayElementWithName(result.PatternProperties, name)
+			}
+		case "allOf":
+			if index, ok := argument(); ok {
+				next = schemaArrayElementWithIndex(result.AllOf, index)
+			}
+		case "anyOf":
+			if index, ok := argument(); ok {
+				next = schemaArrayElementWithIndex(result.AnyOf, index)
+			}
+		case "oneOf":
+			if index, ok := argument(); ok {
+				next = schemaArrayElementWithIndex(result.OneOf, index)
+			}
+		case "items":
+			if result.Items != nil {
+				if result.Items.Schema != nil {
+					next = result.Items.Schema
+				} else if index, ok := argument(); ok {
+					next = schemaArrayElementWithIndex(result.Items.SchemaArray, index)
+				}
+			}
+		case "additionalItems":
+			if result.AdditionalItems != nil {
+				next = result.AdditionalItems.Schema
+			}
+		case "additionalProperties":
+			if result.AdditionalProperties != nil {
+				next = result.AdditionalProperties.Schema
+			}
+		case "not":
+			next = result.Not
+		}
+		if next == nil {
+			return nil, fmt.Errorf("no schema at %q", "/"+strings.Join(tokens[:i+1], "/"))
+		}
+		result = next
+	}
+	return result, nil
+}
+
+func schemaArrayElementWithIndex(array *[]*Schema, index string) *Schema {
+	i, err := strconv.Atoi(index)
+	if array == nil || err != nil || i < 0 || i >= len(*array) {
+		return nil
+	}
+	return (*array)[i]
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func schemaFromString(t *testing.T, s string) *Schema {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(s), &node); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	return NewSchemaFromObject(&node)
+}
+
+func TestResolver(t *testing.T) {
+	message := schemaFromString(t, `
+id: http://example.com/schemas/Message.json
+type: object
+properties:
+  author:
+    $ref: Author.json
+  tags:
+    type: array
+    items:
+      $ref: "#/definitions/Tag"
+definitions:
+  Tag:
+    type: string
+    title: Tag
+`)
+	author := schemaFromString(t, `
+type: object
+properties:
+  name:
+    type: string
+    title: Name
+  a/b:
+    oneOf:
+      - type: "null"
+      - type: integer
+        title: Weird
+`)
+	var r Resolver
+	r.LoadSchema("schemas/Message.json", message)
+	r.LoadSchema("schemas/Author.json", author)
+
+	for _, tt := range []struct {
+		ref   string
+		title string
+	}{
+		{ref: "#/definitions/Tag", title: "Tag"},
+		{ref: "Author.json#/properties/name", title: "Name"},
+		{ref: "schemas/Author.json#/properties/a~1b/oneOf/1", title: "Weird"},
+		{ref: "http://example.com/schemas/Message.json#/properties/tags/items", title: ""},
+	} {
+		s, err := r.Resolve(tt.ref)
+		if err != nil {
+			t.Errorf("%s: %s", tt.ref, err.Error())
+			continue
+		}
+		title := ""
+		if s.Title != nil {
+			title = *s.Title
+		}
+		if title != tt.title {
+			t.Errorf("%s: unexpected title %q (expected %q)", tt.ref, title, tt.title)
+		}
+	}
+	if s, err := r.Resolve("Author.json"); err != nil || s != author {
+		t.Errorf("Author.json: expected the Author schema")
+	}
+	for _, ref := range []string{"Missing.json#/definitions/X", "#/definitions/Missing", "Author.json#/properties/name/items"} {
+		if _, err := r.Resolve(ref); err == nil {
+			t.Errorf("%s: expected an error", ref)
+		}
+	}
+}
+
+func TestResolverSameBaseNames(t *testing.T) {
+	var r Resolver
+	r.LoadSchema("api/types.json", schemaFromString(t, `{properties: {a: {$ref: "common.json#/definitions/A"}}}`))
+	r.LoadSchema("api/common.json", schemaFromString(t, `{definitions: {A: {title: api}}}`))
+	r.LoadSchema("api/sub/common.json", schemaFromString(t, `{definitions: {A: {title: sub}}}`))
+	r.LoadSchema("api/sub/types.json", schemaFromString(t, `{properties: {a: {$ref: "common.json#/definitions/A"}}}`))
+
+	for base, title := range map[string]string{
+		"api/types.json":     "api",
+		"api/sub/types.json": "sub",
+	} {
+		s, err := r.ResolveFrom(base, "common.json#/definitions/A")
+		if err != nil {
+			t.Errorf("%s: %s", base, err.Error())
+		} else if s.Title == nil || *s.Title != title {
+			t.Errorf("%s: expected the definition in the %s schema, got %+v", base, title, s.Title)
+		}
+	}
+	if s, err := r.ResolveFrom("api/types.json", "sub/common.json#/definitions/A"); err != nil || *s.Title != "sub" {
+		t.Errorf("expected sub/common.json to be resolved against api/types.json")
+	}
+	// Names that are only matched by base name must be unique.
+	if _, err := r.ResolveFrom("other/types.json", "common.json#/definitions/A"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected an ambiguous reference error, got %v", err)
+	}
+}
@@ -0,0 +1,456 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+//
+// VALIDATION
+// The following methods validate values against Schemas.
+//
+
+// A ValidationError describes a value that doesn't conform to a schema.
+type ValidationError struct {
+	// Pointer is the JSON Pointer of the value in the validated instance.
+	Pointer string
+	// Message describes how the value violates the schema.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Pointer == "" {
+		return e.Message
+	}
+	return e.Pointer + ": " + e.Message
+}
+
+// maxValidationDepth limits the references that are followed to validate
+// a value, so that recursive schemas can't loop forever.
+const maxValidationDepth = 64
+
+// Validate checks that an instance, a parsed JSON or YAML value, conforms
+// to the schema and returns a description of each violation. Local
+// references ("#/definitions/Name") are resolved against the schema.
+// Formats aren't checked.
+func (schema *Schema) Validate(instance *yaml.Node) []*ValidationError {
+	v := &validator{root: schema, errors: make([]*ValidationError, 0)}
+	v.validate(schema, instance, "", 0)
+	return v.errors
+}
+
+type validator struct {
+	root   *Schema
+	errors []*ValidationError
+}
+
+func (v *validator) fail(pointer string, format string, args ...interface{}) {
+	v.errors = append(v.errors, &ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches returns true if a value conforms to a schema without recording
+// any errors.
+func (v *validator) matches(schema *Schema, node *yaml.Node, depth int) bool {
+	w := &validator{root: v.root}
+	w.validate(schema, node, "", depth)
+	return len(w.errors) == 0
+}
+
+func (v *validator) validate(schema *Schema, node *yaml.Node, pointer string, depth int) {
+	node = valueNode(node)
+	if schema == nil || node == nil {
+		return
+	}
+	if b, ok := schema.BooleanValue(); ok {
+		if !b {
+			v.fail(pointer, "no value is allowed")
+		}
+		return
+	}
+	if schema.Ref != nil {
+		if depth >= maxValidationDepth {
+			v.fail(pointer, "too many nested references")
+			return
+		}
+		ref := *schema.Ref
+		if !strings.HasPrefix(ref, "#") {
+			v.fail(pointer, "unsupported reference %s", ref)
+			return
+		}
+		target, err := v.root.schemaForJSONPointer(ref[1:])
+		if err != nil {
+			v.fail(pointer, "unresolved reference %s", ref)
+			return
+		}
+		// Keywords next to $ref are ignored.
+		v.validate(target, node, pointer, depth+1)
+		return
+	}
+	if schema.Type != nil && !matchesType(schema.Type, node) {
+		v.fail(pointer, "%s is not of type %s", kindOfNode(node), schema.Type.Description())
+		return
+	}
+	if schema.Enumeration != nil {
+		found := false
+		for _, value := range *schema.Enumeration {
+			if equalsEnumValue(node, &value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(pointer, "value is not one of the allowed values")
+		}
+	}
+	if schema.Const != nil && !equalsEnumValue(node, schema.Const) {
+		v.fail(pointer, "value is not the constant value")
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		v.validateScalar(schema, node, pointer)
+	case yaml.SequenceNode:
+		v.validateArray(schema, node, pointer, depth)
+	case yaml.MappingNode:
+		v.validateObject(schema, node, pointer, depth)
+	}
+	if schema.AllOf != nil {
+		for _, s := range *schema.AllOf {
+			v.validate(s, node, pointer, depth)
+		}
+	}
+	if schema.AnyOf != nil {
+		matched := false
+		for _, s := range *schema.AnyOf {
+			if v.matches(s, node, depth) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(pointer, "value matches none of the schemas of anyOf")
+		}
+	}
+	if schema.OneOf != nil {
+		count := 0
+		for _, s := range *schema.OneOf {
+			if v.matches(s, node, depth) {
+				count++
+			}
+		}
+		if count != 1 {
+			v.fail(pointer, "value matches %d of the schemas of oneOf instead of exactly one", count)
+		}
+	}
+	if schema.Not != nil && v.matches(schema.Not, node, depth) {
+		v.fail(pointer, "value matches the schema of not")
+	}
+}
+
+func (v *validator) validateScalar(schema *Schema, node *yaml.Node, pointer string) {
+	if number, ok := numberValue(node); ok {
+		if schema.MultipleOf != nil {
+			if m := schemaNumberValue(schema.MultipleOf); m != 0 {
+				if q := number / m; math.Abs(q-math.Round(q)) > 1e-9 {
+					v.fail(pointer, "%v is not a multiple of %v", number, m)
+				}
+			}
+		}
+		if schema.Maximum != nil {
+			max := schemaNumberValue(schema.Maximum)
+			if schema.ExclusiveMaximum != nil && *schema.ExclusiveMaximum && number >= max {
+				v.fail(pointer, "%v is not less than %v", number, max)
+			} else if number > max {
+				v.fail(pointer, "%v is greater than %v", number, max)
+			}
+		}
+		if schema.Minimum != nil {
+			min := schemaNumberValue(schema.Minimum)
+			if schema.ExclusiveMinimum != nil && *schema.ExclusiveMinimum && number <= min {
+				v.fail(pointer, "%v is not greater than %v", number, min)
+			} else if number < min {
+				v.fail(pointer, "%v is less than %v", number, min)
+			}
+		}
+	}
+	if !isStringNode(node) {
+		return
+	}
+	length := int64(utf8.RuneCountInString(node.Value))
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		v.fail(pointer, "string is longer than %d characters", *schema.MaxLength)
+	}
+	if schema.MinLength != nil && length < *schema.MinLength {
+		v.fail(pointer, "string is shorter than %d characters", *schema.MinLength)
+	}
+	if schema.Pattern != nil {
+		if re, err := regexp.Compile(*schema.Pattern); err != nil {
+			v.fail(pointer, "invalid pattern %s", *schema.Pattern)
+		} else if !re.MatchString(node.Value) {
+			v.fail(pointer, "string does not match pattern %s", *schema.Pattern)
+		}
+	}
+}
+
+func (v *validator) validateArray(schema *Schema, node *yaml.Node, pointer string, depth int) {
+	count := int64(len(node.Content))
+	if schema.MaxItems != nil && count > *schema.MaxItems {
+		v.fail(pointer, "array has more than %d items", *schema.MaxItems)
+	}
+	if schema.MinItems != nil && count < *schema.MinItems {
+		v.fail(pointer, "array has fewer than %d items", *schema.MinItems)
+	}
+	if schema.UniqueItems != nil && *schema.UniqueItems {
+		seen := make([]interface{}, 0, len(node.Content))
+		for i, item := range node.Content {
+			value := comparableValue(item)
+			for _, other := range seen {
+				if reflect.DeepEqual(value, other) {
+					v.fail(pointer+"/"+strconv.Itoa(i), "item is not unique")
+					break
+				}
+			}
+			seen = append(seen, value)
+		}
+	}
+	if schema.Items == nil {
+		return
+	}
+	for i, item := range node.Content {
+		itemPointer := pointer + "/" + strconv.Itoa(i)
+		if schema.Items.Schema != nil {
+			v.validate(schema.Items.Schema, item, itemPointer, depth)
+		} else if schema.Items.SchemaArray != nil {
+			if items := *schema.Items.SchemaArray; i < len(items) {
+				v.validate(items[i], item, itemPointer, depth)
+			} else if a := schema.AdditionalItems; a != nil {
+				if a.Boolean != nil && !*a.Boolean {
+					v.fail(itemPointer, "additional items are not allowed")
+				} else if a.Schema != nil {
+					v.validate(a.Schema, item, itemPointer, depth)
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) validateObject(schema *Schema, node *yaml.Node, pointer string, depth int) {
+	count := int64(len(node.Content) / 2)
+	if schema.MaxProperties != nil && count > *schema.MaxProperties {
+		v.fail(pointer, "object has more than %d properties", *schema.MaxProperties)
+	}
+	if schema.MinProperties != nil && count < *schema.MinProperties {
+		v.fail(pointer, "object has fewer than %d properties", *schema.MinProperties)
+	}
+	has := func(name string) bool {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == name {
+				return true
+			}
+		}
+		return false
+	}
+	if schema.Required != nil {
+		for _, name := range *schema.Required {
+			if !has(name) {
+				v.fail(pointer, "missing required property %s", name)
+			}
+		}
+	}
+	if schema.Dependencies != nil {
+		for _, dependency := range *schema.Dependencies {
+			if !has(dependency.Name) || dependency.Value == nil {
+				continue
+			}
+			if dependency.Value.StringArray != nil {
+				for _, name := range *dependency.Value.StringArray {
+					if !has(name) {
+						v.fail(pointer, "property %s requires property %s", dependency.Name, name)
+					}
+				}
+			} else if dependency.Value.Schema != nil {
+				v.validate(dependency.Value.Schema, node, pointer, depth)
+			}
+		}
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name := node.Content[i].Value
+		value := node.Content[i+1]
+		propertyPointer := pointer + "/" + compiler.EscapeJSONPointer(name)
+		matched := false
+		if schema.Properties != nil {
+			if s := namedSchemaArrayElementWithName(schema.Properties, name); s != nil {
+				v.validate(s, value, propertyPointer, depth)
+				matched = true
+			}
+		}
+		if schema.PatternProperties != nil {
+			for _, pattern := range *schema.PatternProperties {
+				if re, err := regexp.Compile(pattern.Name); err == nil && re.MatchString(name) {
+					v.validate(pattern.Value, value, propertyPointer, depth)
+					matched = true
+				}
+			}
+		}
+		if matched || schema.AdditionalProperties == nil {
+			continue
+		}
+		if a := schema.AdditionalProperties; a.Boolean != nil && !*a.Boolean {
+			v.fail(propertyPointer, "additional property %s is not allowed", name)
+		} else if a.Schema != nil {
+			v.validate(a.Schema, value, propertyPointer, depth)
+		}
+	}
+}
+
+// valueNode returns the node that holds a value, skipping documents and
+// aliases.
+func valueNode(node *yaml.Node) *yaml.Node {
+	for node != nil {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			if len(node.Content) == 0 {
+				return nil
+			}
+			node = node.Content[0]
+		case yaml.AliasNode:
+			node = node.Alias
+		default:
+			return node
+		}
+	}
+	return nil
+}
+
+// kindOfNode returns the JSON type of a value.
+func kindOfNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	}
+	return "string"
+}
+
+func isStringNode(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && kindOfNode(node) == "string"
+}
+
+func matchesType(t *StringOrStringArray, node *yaml.Node) bool {
+	types := make([]string, 0)
+	if t.String != nil {
+		types = append(types, *t.String)
+	}
+	if t.StringArray != nil {
+		types = append(types, *t.StringArray...)
+	}
+	kind := kindOfNode(node)
+	for _, name := range types {
+		switch {
+		case name == kind:
+			return true
+		case name == "number" && kind == "integer":
+			return true
+		case name == "integer" && kind == "number":
+			if number, ok := numberValue(node); ok && number == math.Trunc(number) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// numberValue returns the value of a numeric scalar.
+func numberValue(node *yaml.Node) (float64, bool) {
+	if kind := kindOfNode(node); node.Kind != yaml.ScalarNode || (kind != "integer" && kind != "number") {
+		return 0, false
+	}
+	var number float64
+	if err := node.Decode(&number); err != nil {
+		return 0, false
+	}
+	return number, true
+}
+
+func schemaNumberValue(n *SchemaNumber) float64 {
+	if n.Integer != nil {
+		return float64(*n.Integer)
+	}
+	if n.Float != nil {
+		return *n.Float
+	}
+	return 0
+}
+
+func equalsEnumValue(node *yaml.Node, value *SchemaEnumValue) bool {
+	if value.String != nil {
+		return isStringNode(node) && node.Value == *value.String
+	}
+	if value.Bool != nil {
+		var b bool
+		return kindOfNode(node) == "boolean" && node.Decode(&b) == nil && b == *value.Bool
+	}
+	return false
+}
+
+// comparableValue returns a value that is deeply equal to the values of
+// equal nodes. Numbers are compared as float64 values.
+func comparableValue(node *yaml.Node) interface{} {
+	node = valueNode(node)
+	if node == nil {
+		return nil
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		m := make(map[string]interface{})
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			m[node.Content[i].Value] = comparableValue(node.Content[i+1])
+		}
+		return m
+	case yaml.SequenceNode:
+		a := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			a = append(a, comparableValue(item))
+		}
+		return a
+	}
+	if number, ok := numberValue(node); ok {
+		return number
+	}
+	var value interface{}
+	node.Decode(&value)
+	return value
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidate(t *testing.T) {
+	schema := schemaFromString(t, `
+type: object
+required: [name, tags]
+properties:
+  name:
+    type: string
+    minLength: 2
+    pattern: "^[a-z]+$"
+  age:
+    type: integer
+    minimum: 0
+    exclusiveMinimum: true
+  kind:
+    enum: [cat, dog]
+  tags:
+    type: array
+    maxItems: 2
+    uniqueItems: true
+    items:
+      type: string
+  owner:
+    $ref: "#/definitions/Owner"
+additionalProperties: false
+definitions:
+  Owner:
+    type: [object, "null"]
+    properties:
+      id:
+        type: number
+        multipleOf: 0.5
+`)
+	for _, tt := range []struct {
+		instance string
+		errors   []string
+	}{
+		{
+			instance: `{name: rex, age: 3, kind: dog, tags: [a], owner: {id: 1.5}}`,
+			errors:   []string{},
+		},
+		{
+			instance: `{name: rex, age: 3.0, tags: [], owner: null}`,
+			errors:   []string{},
+		},
+		{
+			instance: `[rex]`,
+			errors:   []string{": array is not of type object"},
+		},
+		{
+			instance: `{name: R, age: 0, kind: cow, tags: [a, a, b], owner: {id: 1.2}, color: red}`,
+			errors: []string{
+				"/name: string is shorter than 2 characters",
+				"/name: string does not match pattern ^[a-z]+$",
+				"/age: 0 is not greater than 0",
+				"/kind: value is not one of the allowed values",
+				"/tags: array has more than 2 items",
+				"/tags/1: item is not unique",
+				"/owner/id: 1.2 is not a multiple of 0.5",
+				"/color: additional property color is not allowed",
+			},
+		},
+		{
+			instance: `{age: 2.5, tags: [1]}`,
+			errors: []string{
+				": missing required property name",
+				"/age: number is not of type integer",
+				"/tags/0: integer is not of type string",
+			},
+		},
+	} {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(tt.instance), &node); err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+		errors := make([]string, 0)
+		for _, err := range schema.Validate(&node) {
+			errors = append(errors, err.Pointer+": "+err.Message)
+		}
+		if strings.Join(errors, "\n") != strings.Join(tt.errors, "\n") {
+			t.Errorf("Unexpected errors for %s:\n%s\nExpected:\n%s", tt.instance, strings.Join(errors, "\n"), strings.Join(tt.errors, "\n"))
+		}
+	}
+}
+
+func TestValidateCombinators(t *testing.T) {
+	schema := schemaFromString(t, `
+oneOf:
+  - type: string
+  - type: integer
+  - type: number
+not:
+  enum: ["forbidden"]
+`)
+	for instance, expected := range map[string]string{
+		`text`:      "",
+		`forbidden`: ": value matches the schema of not",
+		`1.5`:       "",
+		`1`:         ": value matches 2 of the schemas of oneOf instead of exactly one",
+		`true`:      ": value matches 0 of the schemas of oneOf instead of exactly one",
+	} {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(instance), &node); err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+		errors := make([]string, 0)
+		for _, err := range schema.Validate(&node) {
+			errors = append(errors, err.Pointer+": "+err.Message)
+		}
+		if got := strings.Join(errors, "\n"); got != expected {
+			t.Errorf("Unexpected errors for %s: %s (expected %s)", instance, got, expected)
+		}
+	}
+	if errors := NewBooleanSchema(false).Validate(&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "x"}); len(errors) != 1 {
+		t.Errorf("Expected the boolean schema false to reject values, got %+v", errors)
+	}
+}
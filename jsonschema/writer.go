@@ -16,8 +16,10 @@ package jsonschema
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/google/gnostic/jsonwriter"
 	"gopkg.in/yaml.v3"
 )
 
@@ -63,13 +65,15 @@ func renderSequenceNode(node *yaml.Node, indent string) (result string) {
 		item := node.Content[i]
 		switch item.Kind {
 		case yaml.ScalarNode:
-			if item.Tag == "!!bool" {
+			if item.Tag == "!!bool" || item.Tag == "!!int" || item.Tag == "!!float" || item.Tag == "!!null" {
 				result += innerIndent + item.Value
 			} else {
 				result += innerIndent + "\"" + item.Value + "\""
 			}
 		case yaml.MappingNode:
 			result += innerIndent + renderMappingNode(item, innerIndent) + ""
+		case yaml.SequenceNode:
+			result += innerIndent + renderSequenceNode(item, innerIndent)
 		default:
 			result += innerIndent + fmt.Sprintf("???ArrayItem(%+v)", item)
 		}
@@ -120,9 +124,9 @@ func (object *SchemaNumber) nodeValue() *yaml.Node {
 	}
 }
 
-func (object *SchemaOrBoolean) nodeValue() *yaml.Node {
+func (object *SchemaOrBoolean) nodeValue(d *dialect) *yaml.Node {
 	if object.Schema != nil {
-		return object.Schema.nodeValue()
+		return object.Schema.nodeValue(d)
 	} else if object.Boolean != nil {
 		return nodeForBoolean(*object.Boolean)
 	} else {
@@ -138,10 +142,10 @@ func nodeForStringArray(array []string) *yaml.Node {
 	return nodeForSequence(content)
 }
 
-func nodeForSchemaArray(array []*Schema) *yaml.Node {
+func nodeForSchemaArray(array []*Schema, d *dialect) *yaml.Node {
 	content := make([]*yaml.Node, 0)
 	for _, item := range array {
-		content = append(content, item.nodeValue())
+		content = append(content, item.nodeValue(d))
 	}
 	return nodeForSequence(content)
 }
@@ -156,9 +160,9 @@ func (object *StringOrStringArray) nodeValue() *yaml.Node {
 	}
 }
 
-func (object *SchemaOrStringArray) nodeValue() *yaml.Node {
+func (object *SchemaOrStringArray) nodeValue(d *dialect) *yaml.Node {
 	if object.Schema != nil {
-		return object.Schema.nodeValue()
+		return object.Schema.nodeValue(d)
 	} else if object.StringArray != nil {
 		return nodeForStringArray(*(object.StringArray))
 	} else {
@@ -166,11 +170,11 @@ func (object *SchemaOrStringArray) nodeValue() *yaml.Node {
 	}
 }
 
-func (object *SchemaOrSchemaArray) nodeValue() *yaml.Node {
+func (object *SchemaOrSchemaArray) nodeValue(d *dialect) *yaml.Node {
 	if object.Schema != nil {
-		return object.Schema.nodeValue()
+		return object.Schema.nodeValue(d)
 	} else if object.SchemaArray != nil {
-		return nodeForSchemaArray(*(object.SchemaArray))
+		return nodeForSchemaArray(*(object.SchemaArray), d)
 	} else {
 		return nil
 	}
@@ -186,18 +190,46 @@ func (object *SchemaEnumValue) nodeValue() *yaml.Node {
 	}
 }
 
-func nodeForNamedSchemaArray(array *[]*NamedSchema) *yaml.Node {
+func (object *DefaultValue) nodeValue() *yaml.Node {
+	if object.StringValue != nil {
+		return nodeForString(*object.StringValue)
+	} else if object.BooleanValue != nil {
+		return nodeForBoolean(*object.BooleanValue)
+	} else if object.Int64Value != nil {
+		return nodeForInt64(*object.Int64Value)
+	} else if object.Float64Value != nil {
+		return nodeForFloat64(*object.Float64Value)
+	} else if object.ArrayValue != nil {
+		return nodeForSequence(object.ArrayValue)
+	} else if object.NullTag {
+		return nodeForNull()
+	} else {
+		return nil
+	}
+}
+
+func nodeForDefaultValueArray(array *[]*DefaultValue) *yaml.Node {
+	content := make([]*yaml.Node, 0)
+	for _, item := range *array {
+		if value := item.nodeValue(); value != nil {
+			content = append(content, value)
+		}
+	}
+	return nodeForSequence(content)
+}
+
+func nodeForNamedSchemaArray(array *[]*NamedSchema, d *dialect) *yaml.Node {
 	content := make([]*yaml.Node, 0)
 	for _, pair := range *(array) {
-		content = appendPair(content, pair.Name, pair.Value.nodeValue())
+		content = appendPair(content, pair.Name, pair.Value.nodeValue(d))
 	}
 	return nodeForMapping(content)
 }
 
-func nodeForNamedSchemaOrStringArray(array *[]*NamedSchemaOrStringArray) *yaml.Node {
+func nodeForNamedSchemaOrStringArray(array *[]*NamedSchemaOrStringArray, d *dialect) *yaml.Node {
 	content := make([]*yaml.Node, 0)
 	for _, pair := range *(array) {
-		content = appendPair(content, pair.Name, pair.Value.nodeValue())
+		content = appendPair(content, pair.Name, pair.Value.nodeValue(d))
 	}
 	return nodeForMapping(content)
 }
@@ -205,7 +237,9 @@ func nodeForNamedSchemaOrStringArray(array *[]*NamedSchemaOrStringArray) *yaml.N
 func nodeForSchemaEnumArray(array *[]SchemaEnumValue) *yaml.Node {
 	content := make([]*yaml.Node, 0)
 	for _, item := range *array {
-		content = append(content, item.nodeValue())
+		if value := item.nodeValue(); value != nil {
+			content = append(content, value)
+		}
 	}
 	return nodeForSequence(content)
 }
@@ -256,52 +290,101 @@ func nodeForInt64(value int64) *yaml.Node {
 	}
 }
 
+// nodeForFloat64 writes the shortest decimal that reads back as value,
+// keeping a ".0" on whole numbers so that they still resolve as floats.
 func nodeForFloat64(value float64) *yaml.Node {
+	s := strconv.FormatFloat(value, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eIN") {
+		s += ".0"
+	}
 	return &yaml.Node{
 		Kind:  yaml.ScalarNode,
 		Tag:   "!!float",
-		Value: fmt.Sprintf("%f", value),
+		Value: s,
 	}
 }
 
 func appendPair(nodes []*yaml.Node, name string, value *yaml.Node) []*yaml.Node {
+	if value == nil {
+		// Skip values that are set but empty, like SchemaNumbers without numbers.
+		return nodes
+	}
 	nodes = append(nodes, nodeForString(name))
 	nodes = append(nodes, value)
 	return nodes
 }
 
-func (schema *Schema) nodeValue() *yaml.Node {
+// dialect holds the keywords that are spelled differently in different
+// JSON Schema drafts. The schema structures are draft-neutral; the draft
+// named by a root schema's $schema only affects how they are written.
+type dialect struct {
+	id          string // "id" in draft-04, "$id" in later drafts
+	definitions string // "definitions" before 2019-09, "$defs" after
+	prefixItems bool   // 2020-12 writes tuple items as "prefixItems"
+}
+
+// dialectForVersion returns the dialect of a $schema value.
+func dialectForVersion(version string) *dialect {
+	switch strings.TrimSuffix(version, "#") {
+	case "http://json-schema.org/draft-04/schema", "":
+		return &dialect{id: "id", definitions: "definitions"}
+	case "https://json-schema.org/draft/2019-09/schema":
+		return &dialect{id: "$id", definitions: "$defs"}
+	case "https://json-schema.org/draft/2020-12/schema":
+		return &dialect{id: "$id", definitions: "$defs", prefixItems: true}
+	default:
+		return &dialect{id: "$id", definitions: "definitions"}
+	}
+}
+
+// dialect returns the dialect used to write a root schema.
+func (schema *Schema) dialect() *dialect {
+	if schema.Schema == nil {
+		return dialectForVersion("")
+	}
+	return dialectForVersion(*schema.Schema)
+}
+
+// ref rewrites local references to definitions to use the dialect's keyword.
+func (d *dialect) ref(ref string) string {
+	for _, keyword := range []string{"definitions", "$defs"} {
+		if prefix := "#/" + keyword + "/"; keyword != d.definitions && strings.HasPrefix(ref, prefix) {
+			return "#/" + d.definitions + "/" + strings.TrimPrefix(ref, prefix)
+		}
+	}
+	return ref
+}
+
+func (schema *Schema) nodeValue(d *dialect) *yaml.Node {
+	if b, ok := schema.BooleanValue(); ok {
+		return nodeForBoolean(b)
+	}
 	n := &yaml.Node{Kind: yaml.MappingNode}
 	content := make([]*yaml.Node, 0)
 	if schema.Title != nil {
 		content = appendPair(content, "title", nodeForString(*schema.Title))
 	}
 	if schema.ID != nil {
-		switch strings.TrimSuffix(*schema.Schema, "#") {
-		case "http://json-schema.org/draft-04/schema":
-			fallthrough
-		case "#":
-			fallthrough
-		case "":
-			content = appendPair(content, "id", nodeForString(*schema.ID))
-		default:
-			content = appendPair(content, "$id", nodeForString(*schema.ID))
-		}
+		content = appendPair(content, d.id, nodeForString(*schema.ID))
 	}
 	if schema.Schema != nil {
 		content = appendPair(content, "$schema", nodeForString(*schema.Schema))
 	}
-	if schema.ReadOnly != nil && *schema.ReadOnly {
+	if schema.ReadOnly != nil {
 		content = appendPair(content, "readOnly", nodeForBoolean(*schema.ReadOnly))
 	}
-	if schema.WriteOnly != nil && *schema.WriteOnly {
+	if schema.WriteOnly != nil {
 		content = appendPair(content, "writeOnly", nodeForBoolean(*schema.WriteOnly))
 	}
 	if schema.Type != nil {
 		content = appendPair(content, "type", schema.Type.nodeValue())
 	}
 	if schema.Items != nil {
-		content = appendPair(content, "items", schema.Items.nodeValue())
+		if d.prefixItems && schema.Items.SchemaArray != nil {
+			content = appendPair(content, "prefixItems", schema.Items.nodeValue(d))
+		} else {
+			content = appendPair(content, "items", schema.Items.nodeValue(d))
+		}
 	}
 	if schema.Description != nil {
 		content = appendPair(content, "description", nodeForString(*schema.Description))
@@ -310,19 +393,19 @@ func (schema *Schema) nodeValue() *yaml.Node {
 		content = appendPair(content, "required", nodeForStringArray(*schema.Required))
 	}
 	if schema.AdditionalProperties != nil {
-		content = appendPair(content, "additionalProperties", schema.AdditionalProperties.nodeValue())
+		content = appendPair(content, "additionalProperties", schema.AdditionalProperties.nodeValue(d))
 	}
 	if schema.PatternProperties != nil {
-		content = appendPair(content, "patternProperties", nodeForNamedSchemaArray(schema.PatternProperties))
+		content = appendPair(content, "patternProperties", nodeForNamedSchemaArray(schema.PatternProperties, d))
 	}
 	if schema.Properties != nil {
-		content = appendPair(content, "properties", nodeForNamedSchemaArray(schema.Properties))
+		content = appendPair(content, "properties", nodeForNamedSchemaArray(schema.Properties, d))
 	}
 	if schema.Dependencies != nil {
-		content = appendPair(content, "dependencies", nodeForNamedSchemaOrStringArray(schema.Dependencies))
+		content = appendPair(content, "dependencies", nodeForNamedSchemaOrStringArray(schema.Dependencies, d))
 	}
 	if schema.Ref != nil {
-		content = appendPair(content, "$ref", nodeForString(*schema.Ref))
+		content = appendPair(content, "$ref", nodeForString(d.ref(*schema.Ref)))
 	}
 	if schema.MultipleOf != nil {
 		content = appendPair(content, "multipleOf", schema.MultipleOf.nodeValue())
@@ -349,7 +432,11 @@ func (schema *Schema) nodeValue() *yaml.Node {
 		content = appendPair(content, "pattern", nodeForString(*schema.Pattern))
 	}
 	if schema.AdditionalItems != nil {
-		content = appendPair(content, "additionalItems", schema.AdditionalItems.nodeValue())
+		if d.prefixItems {
+			content = appendPair(content, "items", schema.AdditionalItems.nodeValue(d))
+		} else {
+			content = appendPair(content, "additionalItems", schema.AdditionalItems.nodeValue(d))
+		}
 	}
 	if schema.MaxItems != nil {
 		content = appendPair(content, "maxItems", nodeForInt64(*schema.MaxItems))
@@ -369,36 +456,32 @@ func (schema *Schema) nodeValue() *yaml.Node {
 	if schema.Enumeration != nil {
 		content = appendPair(content, "enum", nodeForSchemaEnumArray(schema.Enumeration))
 	}
+	if schema.Const != nil {
+		content = appendPair(content, "const", schema.Const.nodeValue())
+	}
 	if schema.AllOf != nil {
-		content = appendPair(content, "allOf", nodeForSchemaArray(*schema.AllOf))
+		content = appendPair(content, "allOf", nodeForSchemaArray(*schema.AllOf, d))
 	}
 	if schema.AnyOf != nil {
-		content = appendPair(content, "anyOf", nodeForSchemaArray(*schema.AnyOf))
+		content = appendPair(content, "anyOf", nodeForSchemaArray(*schema.AnyOf, d))
 	}
 	if schema.OneOf != nil {
-		content = appendPair(content, "oneOf", nodeForSchemaArray(*schema.OneOf))
+		content = appendPair(content, "oneOf", nodeForSchemaArray(*schema.OneOf, d))
 	}
 	if schema.Not != nil {
-		content = appendPair(content, "not", schema.Not.nodeValue())
+		content = appendPair(content, "not", schema.Not.nodeValue(d))
 	}
 	if schema.Definitions != nil {
-		content = appendPair(content, "definitions", nodeForNamedSchemaArray(schema.Definitions))
+		content = appendPair(content, d.definitions, nodeForNamedSchemaArray(schema.Definitions, d))
 	}
 	if schema.Default != nil {
-		if schema.Default.StringValue != nil {
-			content = appendPair(content, "default", nodeForString(*schema.Default.StringValue))
-		} else if schema.Default.BooleanValue != nil {
-			content = appendPair(content, "default", nodeForBoolean(*schema.Default.BooleanValue))
-		} else if schema.Default.Int64Value != nil {
-			content = appendPair(content, "default", nodeForInt64(*schema.Default.Int64Value))
-		} else if schema.Default.Float64Value != nil {
-			content = appendPair(content, "default", nodeForFloat64(*schema.Default.Float64Value))
-		} else if schema.Default.ArrayValue != nil {
-			content = appendPair(content, "default", nodeForSequence(schema.Default.ArrayValue))
-		} else if schema.Default.NullTag {
-			content = appendPair(content, "default", nodeForNull())
+		if value := schema.Default.nodeValue(); value != nil {
+			content = appendPair(content, "default", value)
 		}
 	}
+	if schema.Examples != nil {
+		content = appendPair(content, "examples", nodeForDefaultValueArray(schema.Examples))
+	}
 	if schema.Format != nil {
 		content = appendPair(content, "format", nodeForString(*schema.Format))
 	}
@@ -408,6 +491,28 @@ func (schema *Schema) nodeValue() *yaml.Node {
 
 // JSONString returns a json representation of a schema.
 func (schema *Schema) JSONString() string {
-	node := schema.nodeValue()
+	node := schema.nodeValue(schema.dialect())
 	return Render(node)
 }
+
+// CompactJSON returns a json representation of a schema without
+// insignificant whitespace. Properties keep their declared order.
+func CompactJSON(s *Schema) ([]byte, error) {
+	return jsonwriter.MarshalStyle(s.nodeValue(s.dialect()), jsonwriter.Compact)
+}
+
+// CanonicalJSON returns the canonical json representation of a schema,
+// with sorted keys and normalized strings and numbers, which is suitable
+// for signing, hashing and caching (see jsonwriter.Canonical).
+func CanonicalJSON(s *Schema) ([]byte, error) {
+	return jsonwriter.MarshalStyle(s.nodeValue(s.dialect()), jsonwriter.Canonical)
+}
+
+// YAMLString returns a yaml representation of a schema.
+func (schema *Schema) YAMLString() string {
+	bytes, err := yaml.Marshal(schema.nodeValue(schema.dialect()))
+	if err != nil {
+		return ""
+	}
+	return string(bytes)
+}
@@ -0,0 +1,302 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConst(t *testing.T) {
+	schema := schemaFromString(t, `
+type: object
+properties:
+  kind:
+    type: string
+    const: circle
+  visible:
+    const: true
+`)
+	kind := schema.PropertyWithName("kind")
+	if kind.Const == nil || kind.Const.String == nil || *kind.Const.String != "circle" {
+		t.Fatalf("Expected const value \"circle\", got %+v", kind.Const)
+	}
+	visible := schema.PropertyWithName("visible")
+	if visible.Const == nil || visible.Const.Bool == nil || !*visible.Const.Bool {
+		t.Fatalf("Expected const value true, got %+v", visible.Const)
+	}
+	if kind.IsEmpty() || !kind.HasConstraints() {
+		t.Errorf("Expected const to be a constraint")
+	}
+	expected := `{
+  "type": "object",
+  "properties": {
+    "kind": {
+      "type": "string",
+      "const": "circle"
+    },
+    "visible": {
+      "const": true
+    }
+  }
+}
+`
+	if got := schema.JSONString(); got != expected {
+		t.Errorf("Unexpected JSON:\n%s\nExpected:\n%s", got, expected)
+	}
+}
+
+func TestWriterEmitsAllFields(t *testing.T) {
+	// Every field of Schema is set in this schema.
+	source := `
+$schema: http://json-schema.org/draft-04/schema#
+id: http://example.com/schemas/Everything.json
+$ref: "#/definitions/other"
+readOnly: true
+writeOnly: false
+multipleOf: 0.5
+maximum: 10
+exclusiveMaximum: true
+minimum: 1
+exclusiveMinimum: false
+maxLength: 8
+minLength: 2
+pattern: "^[a-z]+$"
+additionalItems: false
+items:
+  type: string
+maxItems: 4
+minItems: 1
+uniqueItems: true
+maxProperties: 3
+minProperties: 1
+required: [name]
+additionalProperties:
+  type: integer
+properties:
+  name:
+    type: string
+patternProperties:
+  "^x-":
+    type: string
+dependencies:
+  name: [id]
+enum: [a, b]
+const: a
+type: [string, "null"]
+allOf:
+  - title: all
+anyOf:
+  - title: any
+oneOf:
+  - title: one
+not:
+  type: boolean
+definitions:
+  other:
+    type: object
+title: Everything
+description: A schema with every keyword.
+default: [1, 2.5, [x]]
+examples: [x, true, 42, 2.5, null]
+format: name
+`
+	nilFields := func(schema *Schema) []string {
+		names := make([]string, 0)
+		fields := reflect.ValueOf(*schema)
+		for i := 0; i < fields.NumField(); i++ {
+			// Unexported fields aren't keywords.
+			if fields.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if fields.Field(i).IsNil() {
+				names = append(names, fields.Type().Field(i).Name)
+			}
+		}
+		return names
+	}
+	schema := schemaFromString(t, source)
+	if names := nilFields(schema); len(names) > 0 {
+		t.Fatalf("Test schema doesn't set %v", names)
+	}
+	for name, serialized := range map[string]string{
+		"json": schema.JSONString(),
+		"yaml": schema.YAMLString(),
+	} {
+		got := schemaFromString(t, serialized)
+		if names := nilFields(got); len(names) > 0 {
+			t.Errorf("Missing %v in %s output:\n%s", names, name, serialized)
+		}
+		if got.JSONString() != schema.JSONString() {
+			t.Errorf("Unexpected values in %s output:\n%s", name, serialized)
+		}
+	}
+}
+
+func TestWriterDialects(t *testing.T) {
+	source := `
+type: object
+properties:
+  shape:
+    $ref: "#/definitions/Shape"
+  pair:
+    type: array
+    items:
+      - type: string
+      - type: integer
+    additionalItems: false
+definitions:
+  Shape:
+    type: string
+`
+	for _, tt := range []struct {
+		version  string
+		expected []string
+	}{
+		{
+			version:  "http://json-schema.org/draft-04/schema#",
+			expected: []string{`"id":`, `"definitions":`, `"#/definitions/Shape"`, `"additionalItems":`},
+		},
+		{
+			version:  "http://json-schema.org/draft-07/schema#",
+			expected: []string{`"$id":`, `"definitions":`, `"#/definitions/Shape"`, `"additionalItems":`},
+		},
+		{
+			version:  "https://json-schema.org/draft/2019-09/schema",
+			expected: []string{`"$id":`, `"$defs":`, `"#/$defs/Shape"`, `"additionalItems":`},
+		},
+		{
+			version:  "https://json-schema.org/draft/2020-12/schema",
+			expected: []string{`"$id":`, `"$defs":`, `"#/$defs/Shape"`, `"prefixItems":`},
+		},
+	} {
+		schema := schemaFromString(t, source)
+		id := "http://example.com/schemas/Pair.json"
+		version := tt.version
+		schema.ID = &id
+		schema.Schema = &version
+		serialized := schema.JSONString()
+		for _, keyword := range tt.expected {
+			if !strings.Contains(serialized, keyword) {
+				t.Errorf("Expected %s in %s output:\n%s", keyword, tt.version, serialized)
+			}
+		}
+		// The serialized schema reads back into the same structure.
+		if got := schemaFromString(t, serialized); got.Definitions == nil || got.PropertyWithName("shape") == nil {
+			t.Errorf("Unable to read %s output:\n%s", tt.version, serialized)
+		}
+	}
+}
+
+func TestBooleanSchemas(t *testing.T) {
+	schema := (&Schema{}).WithType("object")
+	schema.AdditionalProperties = NewSchemaOrBooleanWithSchema(NewBooleanSchema(false))
+	schema.Properties = &[]*NamedSchema{}
+	schema.AddProperty("anything", NewBooleanSchema(true))
+	schema.AddProperty("nothing", NewBooleanSchema(false))
+	expected := `{
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "anything": true,
+    "nothing": false
+  }
+}
+`
+	serialized := schema.JSONString()
+	if serialized != expected {
+		t.Errorf("Unexpected JSON:\n%s\nExpected:\n%s", serialized, expected)
+	}
+	// The serialized schema reads back into the same structure.
+	got := schemaFromString(t, serialized)
+	if got.AdditionalProperties == nil || got.AdditionalProperties.Boolean == nil || *got.AdditionalProperties.Boolean {
+		t.Errorf("Expected additionalProperties to be false, got %+v", got.AdditionalProperties)
+	}
+	if b, ok := got.PropertyWithName("anything").BooleanValue(); !ok || !b {
+		t.Errorf("Expected property anything to be the boolean schema true")
+	}
+	if b, ok := got.PropertyWithName("nothing").BooleanValue(); !ok || b {
+		t.Errorf("Expected property nothing to be the boolean schema false")
+	}
+	if !got.PropertyWithName("anything").IsEmpty() || got.PropertyWithName("nothing").IsEmpty() {
+		t.Errorf("Expected only the boolean schema true to be empty")
+	}
+	if got.IsEmpty() {
+		t.Errorf("Expected a schema with additionalProperties false not to be empty")
+	}
+	if again := got.JSONString(); again != serialized {
+		t.Errorf("Unexpected JSON after a round trip:\n%s\nExpected:\n%s", again, serialized)
+	}
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	a := schemaFromString(t, `
+type: object
+properties:
+  ratio:
+    type: number
+    maximum: 1.0
+    minimum: -0.0
+    multipleOf: 1e-7
+  name:
+    type: string
+    description: "tab\tand \"quotes\""
+`)
+	b := schemaFromString(t, `
+properties:
+  name: {description: "tab\tand \"quotes\"", type: string}
+  ratio: {multipleOf: 0.0000001, minimum: 0, maximum: 1, type: number}
+type: object
+`)
+	expected := `{"properties":{"name":{"description":"tab\tand \"quotes\"","type":"string"},` +
+		`"ratio":{"maximum":1,"minimum":0,"multipleOf":1e-7,"type":"number"}},"type":"object"}`
+	for _, schema := range []*Schema{a, b} {
+		canonical, err := CanonicalJSON(schema)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if string(canonical) != expected {
+			t.Errorf("Unexpected canonical JSON:\n%s\nExpected:\n%s", canonical, expected)
+		}
+	}
+	compact, err := CompactJSON(a)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expectedCompact := `{"type":"object","properties":{"ratio":{"type":"number","multipleOf":1e-07,` +
+		`"maximum":1.0,"minimum":-0.0},"name":{"type":"string","description":"tab\tand \"quotes\""}}}` + "\n"
+	if string(compact) != expectedCompact {
+		t.Errorf("Unexpected compact JSON:\n%s\nExpected:\n%s", compact, expectedCompact)
+	}
+}
+
+func TestWriterKeepsExampleTypes(t *testing.T) {
+	schema := schemaFromString(t, `{type: number, examples: ["42", 42, 2.5, true, null]}`)
+	expected := `{
+  "type": "number",
+  "examples": [
+    "42",
+    42,
+    2.5,
+    true,
+    null
+  ]
+}
+`
+	if got := schema.JSONString(); got != expected {
+		t.Errorf("Unexpected output:\n%s\nExpected:\n%s", got, expected)
+	}
+}
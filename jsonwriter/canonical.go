@@ -0,0 +1,254 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonwriter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Canonical output follows the JSON Canonicalization Scheme (RFC 8785):
+// no whitespace, object members sorted by the UTF-16 code units of their
+// names, minimal string escaping and ECMAScript number formatting.
+//
+// YAML scalars are resolved with the YAML 1.2 core schema before they are
+// written, so "1.0", "1.", "10e-1" and "1" all become 1, and "-0.0" becomes
+// 0. Floats are written with the shortest digits that round-trip through a
+// float64, using exponent notation only below 1e-6 or at or above 1e21.
+// Integers are written as exact decimals; unlike RFC 8785, integers outside
+// the range that a float64 represents exactly are not rounded. Infinities,
+// NaN and invalid UTF-8 are errors because JSON can't represent them.
+
+func marshalCanonical(in *yaml.Node) ([]byte, error) {
+	var b bytes.Buffer
+	if err := writeCanonical(&b, in); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func writeCanonical(b *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			b.WriteString(null)
+			return nil
+		}
+		return writeCanonical(b, node.Content[0])
+	case yaml.AliasNode:
+		return writeCanonical(b, node.Alias)
+	case yaml.MappingNode:
+		return writeCanonicalMap(b, node)
+	case yaml.SequenceNode:
+		b.WriteString("[")
+		for i, value := range node.Content {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			if err := writeCanonical(b, value); err != nil {
+				return err
+			}
+		}
+		b.WriteString("]")
+		return nil
+	case yaml.ScalarNode:
+		return writeCanonicalScalar(b, node)
+	default:
+		return errors.New("invalid type passed to Marshal")
+	}
+}
+
+type canonicalMember struct {
+	key   []uint16
+	name  string
+	value *yaml.Node
+}
+
+func writeCanonicalMap(b *bytes.Buffer, node *yaml.Node) error {
+	members := make([]canonicalMember, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if key.Kind == yaml.AliasNode {
+			key = key.Alias
+		}
+		if key.Kind != yaml.ScalarNode {
+			return fmt.Errorf("invalid key for map: %+v", key)
+		}
+		members = append(members, canonicalMember{
+			key:   utf16.Encode([]rune(key.Value)),
+			name:  key.Value,
+			value: node.Content[i+1],
+		})
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		return lessUTF16(members[i].key, members[j].key)
+	})
+	b.WriteString("{")
+	for i, member := range members {
+		if i > 0 {
+			if member.name == members[i-1].name {
+				return fmt.Errorf("duplicate key %q", member.name)
+			}
+			b.WriteString(",")
+		}
+		if err := writeCanonicalString(b, member.name); err != nil {
+			return err
+		}
+		b.WriteString(":")
+		if err := writeCanonical(b, member.value); err != nil {
+			return err
+		}
+	}
+	b.WriteString("}")
+	return nil
+}
+
+func lessUTF16(a, b []uint16) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func writeCanonicalScalar(b *bytes.Buffer, node *yaml.Node) error {
+	switch node.ShortTag() {
+	case "!!null":
+		b.WriteString(null)
+	case "!!bool":
+		switch strings.ToLower(node.Value) {
+		case "true":
+			b.WriteString("true")
+		case "false":
+			b.WriteString("false")
+		default:
+			return fmt.Errorf("invalid bool %q", node.Value)
+		}
+	case "!!int":
+		i, ok := new(big.Int).SetString(strings.Replace(node.Value, "_", "", -1), 0)
+		if !ok {
+			return fmt.Errorf("invalid int %q", node.Value)
+		}
+		b.WriteString(i.String())
+	case "!!float":
+		f, err := strconv.ParseFloat(strings.Replace(node.Value, "_", "", -1), 64)
+		if err != nil {
+			return fmt.Errorf("invalid float %q", node.Value)
+		}
+		s, err := formatCanonicalFloat(f)
+		if err != nil {
+			return err
+		}
+		b.WriteString(s)
+	default:
+		return writeCanonicalString(b, node.Value)
+	}
+	return nil
+}
+
+// formatCanonicalFloat formats a float64 the way ECMAScript's
+// Number.prototype.toString does, which is the number format of RFC 8785.
+func formatCanonicalFloat(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("%v can't be represented in JSON", f)
+	}
+	if f == 0 {
+		// This also maps negative zero to 0.
+		return "0", nil
+	}
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+	// Shortest round-trip digits as "d.ddde±x".
+	e := strconv.FormatFloat(f, 'e', -1, 64)
+	mark := strings.IndexByte(e, 'e')
+	digits := strings.Replace(e[:mark], ".", "", 1)
+	exponent, err := strconv.Atoi(e[mark+1:])
+	if err != nil {
+		return "", err
+	}
+	// The value is 0.digits × 10^n.
+	k, n := len(digits), exponent+1
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k), nil
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:], nil
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits, nil
+	}
+	mantissa := digits[:1]
+	if k > 1 {
+		mantissa += "." + digits[1:]
+	}
+	exponentSign := "+"
+	if n-1 < 0 {
+		exponentSign = "-"
+	}
+	return fmt.Sprintf("%s%se%s%d", sign, mantissa, exponentSign, abs(n-1)), nil
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+func writeCanonicalString(b *bytes.Buffer, s string) error {
+	if !utf8.ValidString(s) {
+		return fmt.Errorf("invalid UTF-8 in string %q", s)
+	}
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonwriter_test
+
+import (
+	"testing"
+
+	"github.com/google/gnostic/jsonwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+func marshalYAML(t *testing.T, source string, style jsonwriter.Style) (string, error) {
+	t.Helper()
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(source), &node); err != nil {
+		t.Fatalf("%s: %+v", source, err)
+	}
+	b, err := jsonwriter.MarshalStyle(&node, style)
+	return string(b), err
+}
+
+func TestCanonicalNumbers(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"1":                       "1",
+		"1.0":                     "1",
+		"1.":                      "1",
+		"10e-1":                   "1",
+		"-0":                      "0",
+		"-0.0":                    "0",
+		"0.0":                     "0",
+		"+12":                     "12",
+		"0x1F":                    "31",
+		"0o17":                    "15",
+		"1.5":                     "1.5",
+		"-2.50":                   "-2.5",
+		"1e3":                     "1000",
+		"1E+3":                    "1000",
+		"1e20":                    "100000000000000000000",
+		"1e21":                    "1e+21",
+		"1.5e21":                  "1.5e+21",
+		"0.000001":                "0.000001",
+		"1e-6":                    "0.000001",
+		"1e-7":                    "1e-7",
+		"-1.25e-10":               "-1.25e-10",
+		"0.1":                     "0.1",
+		"333333333.3333333333333": "333333333.3333333",
+		"9007199254740993":        "9007199254740993",
+		"1.7976931348623157e308":  "1.7976931348623157e+308",
+		"5e-324":                  "5e-324",
+	}
+	for source, expected := range tests {
+		got, err := marshalYAML(t, source, jsonwriter.Canonical)
+		if err != nil {
+			t.Errorf("%s: %+v", source, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("%s: expected %s, got %s", source, expected, got)
+		}
+	}
+}
+
+func TestCanonicalErrors(t *testing.T) {
+	t.Parallel()
+	for _, source := range []string{".inf", "-.inf", ".nan", "{a: 1, a: 2}"} {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(source), &node); err != nil {
+			// yaml.v3 may reject some inputs (such as duplicate keys) itself.
+			continue
+		}
+		if _, err := jsonwriter.MarshalStyle(&node, jsonwriter.Canonical); err == nil {
+			t.Errorf("%s: expected error", source)
+		}
+	}
+	invalid := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "\xff"}
+	if _, err := jsonwriter.MarshalStyle(invalid, jsonwriter.Canonical); err == nil {
+		t.Errorf("expected error for invalid UTF-8")
+	}
+}
+
+// Member names are sorted by UTF-16 code units, so U+1F600 (a surrogate
+// pair starting with 0xD83D) sorts before U+FB33.
+func TestCanonicalDocument(t *testing.T) {
+	t.Parallel()
+	source := `
+z: [true, null, "2", 3.0]
+a: {y: 1, "€": 2, "\U0001F600": 3, "\uFB33": 4, x: "line\nbreak\ttab\u0001\"quote\"\\ é"}
+b: &anchor {k: v}
+c: *anchor
+"10": ten
+`
+	expected := `{"10":"ten","a":{"x":"line\nbreak\ttab\u0001\"quote\"\\ é","y":1,"€":2,"😀":3,"דּ":4},"b":{"k":"v"},"c":{"k":"v"},"z":[true,null,"2",3]}`
+	got, err := marshalYAML(t, source, jsonwriter.Canonical)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestStyles(t *testing.T) {
+	t.Parallel()
+	source := "b: [1, {c: 1.0}]\na: {}\n"
+	tests := map[jsonwriter.Style]string{
+		jsonwriter.Pretty:    "{\n  \"b\": [\n    1,\n    {\n      \"c\": 1.0\n    }\n  ],\n  \"a\": {\n  }\n}\n",
+		jsonwriter.Compact:   "{\"b\":[1,{\"c\":1.0}],\"a\":{}}\n",
+		jsonwriter.Canonical: "{\"a\":{},\"b\":[1,{\"c\":1}]}",
+	}
+	for style, expected := range tests {
+		got, err := marshalYAML(t, source, style)
+		if err != nil {
+			t.Errorf("%s: %+v", style, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("%s: expected\n%q\ngot\n%q", style, expected, got)
+		}
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	t.Parallel()
+	for _, style := range []jsonwriter.Style{jsonwriter.Pretty, jsonwriter.Compact, jsonwriter.Canonical} {
+		parsed, err := jsonwriter.ParseStyle(style.String())
+		if err != nil || parsed != style {
+			t.Errorf("%s: got %s, %v", style, parsed, err)
+		}
+	}
+	if _, err := jsonwriter.ParseStyle("sorted"); err == nil {
+		t.Errorf("expected error for unknown style")
+	}
+}
@@ -28,8 +28,50 @@ const (
 	null        = "null"
 )
 
+// Style selects the layout of JSON written by MarshalStyle.
+type Style int
+
+const (
+	// Pretty writes one value per line with two-space indentation.
+	Pretty Style = iota
+	// Compact writes the same values as Pretty without any whitespace.
+	Compact
+	// Canonical writes compact JSON with sorted keys and normalized
+	// strings and numbers, following RFC 8785 (see canonical.go).
+	Canonical
+)
+
+// String returns the name of a style as accepted by ParseStyle.
+func (s Style) String() string {
+	switch s {
+	case Pretty:
+		return "pretty"
+	case Compact:
+		return "compact"
+	case Canonical:
+		return "canonical"
+	default:
+		return fmt.Sprintf("Style(%d)", int(s))
+	}
+}
+
+// ParseStyle returns the style with the specified name.
+func ParseStyle(name string) (Style, error) {
+	switch name {
+	case "pretty", "":
+		return Pretty, nil
+	case "compact":
+		return Compact, nil
+	case "canonical":
+		return Canonical, nil
+	default:
+		return Pretty, fmt.Errorf("unknown JSON style %q (expected pretty, compact or canonical)", name)
+	}
+}
+
 type writer struct {
-	b bytes.Buffer
+	b       bytes.Buffer
+	compact bool
 }
 
 func (w *writer) bytes() []byte {
@@ -40,6 +82,13 @@ func (w *writer) writeString(s string) {
 	w.b.Write([]byte(s))
 }
 
+// writeSpace writes whitespace that is only present in pretty output.
+func (w *writer) writeSpace(s string) {
+	if !w.compact {
+		w.writeString(s)
+	}
+}
+
 func (w *writer) writeMap(node *yaml.Node, indent string) {
 	if node.Kind == yaml.DocumentNode {
 		w.writeMap(node.Content[0], indent)
@@ -49,12 +98,15 @@ func (w *writer) writeMap(node *yaml.Node, indent string) {
 		w.writeString(fmt.Sprintf("invalid node for map: %+v", node))
 		return
 	}
-	w.writeString("{\n")
+	w.writeString("{")
+	w.writeSpace("\n")
 	innerIndent := indent + indentation
 	for i := 0; i < len(node.Content); i += 2 {
 		// first print the key
 		key := node.Content[i].Value
-		w.writeString(fmt.Sprintf("%s\"%+v\": ", innerIndent, key))
+		w.writeSpace(innerIndent)
+		w.writeString(fmt.Sprintf("\"%+v\":", key))
+		w.writeSpace(" ")
 		// then the value
 		value := node.Content[i+1]
 		switch value.Kind {
@@ -68,9 +120,9 @@ func (w *writer) writeMap(node *yaml.Node, indent string) {
 		if i < len(node.Content)-2 {
 			w.writeString(",")
 		}
-		w.writeString("\n")
+		w.writeSpace("\n")
 	}
-	w.writeString(indent)
+	w.writeSpace(indent)
 	w.writeString("}")
 }
 
@@ -98,10 +150,11 @@ func (w *writer) writeSequence(node *yaml.Node, indent string) {
 		w.writeString(fmt.Sprintf("invalid node for sequence: %+v", node))
 		return
 	}
-	w.writeString("[\n")
+	w.writeString("[")
+	w.writeSpace("\n")
 	innerIndent := indent + indentation
 	for i, value := range node.Content {
-		w.writeString(innerIndent)
+		w.writeSpace(innerIndent)
 		switch value.Kind {
 		case yaml.MappingNode:
 			w.writeMap(value, innerIndent)
@@ -113,15 +166,25 @@ func (w *writer) writeSequence(node *yaml.Node, indent string) {
 		if i < len(node.Content)-1 {
 			w.writeString(",")
 		}
-		w.writeString("\n")
+		w.writeSpace("\n")
 	}
-	w.writeString(indent)
+	w.writeSpace(indent)
 	w.writeString("]")
 }
 
 // Marshal writes a yaml.Node as JSON
 func Marshal(in *yaml.Node) (out []byte, err error) {
-	var w writer
+	return MarshalStyle(in, Pretty)
+}
+
+// MarshalStyle writes a yaml.Node as JSON in the specified style.
+// Pretty and Compact output end with a newline; Canonical output does not,
+// so that it can be hashed or compared byte-for-byte.
+func MarshalStyle(in *yaml.Node, style Style) (out []byte, err error) {
+	if style == Canonical {
+		return marshalCanonical(in)
+	}
+	w := writer{compact: style == Compact}
 
 	switch in.Kind {
 	case yaml.DocumentNode:
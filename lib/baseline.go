@@ -0,0 +1,225 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/gnostic/compiler"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// A lint baseline records known findings, the warnings of gnostic and the
+// messages of plugins, so that only new findings fail a run.
+type lintBaseline struct {
+	Findings []*baselineEntry `json:"findings"`
+}
+
+// A baselineEntry identifies a finding by its rule, its normalized path,
+// and a hash of its message.
+type baselineEntry struct {
+	Rule    string `json:"rule"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *baselineEntry) key() string {
+	return e.Rule + "\x00" + e.Path + "\x00" + e.Message
+}
+
+// baselineListKeys are the keys of lists whose items are identified by
+// their positions. Positions change when unrelated items are added or
+// reordered, so they are replaced with * in baseline paths.
+var baselineListKeys = map[string]bool{
+	"parameters": true,
+	"servers":    true,
+	"tags":       true,
+	"security":   true,
+	"allOf":      true,
+	"anyOf":      true,
+	"oneOf":      true,
+	"enum":       true,
+	"required":   true,
+}
+
+// baselinePath returns the JSON Pointer of a finding with list positions
+// replaced with *.
+func baselinePath(keys []string) string {
+	path := ""
+	for i, key := range keys {
+		if _, err := strconv.Atoi(key); err == nil && i > 0 && baselineListKeys[keys[i-1]] {
+			key = "*"
+		}
+		path += "/" + compiler.EscapeJSONPointer(key)
+	}
+	return path
+}
+
+func baselineEntryForFinding(finding *plugins.Message) *baselineEntry {
+	sum := sha256.Sum256([]byte(finding.Text))
+	return &baselineEntry{
+		Rule:    finding.Code,
+		Path:    baselinePath(finding.Keys),
+		Message: hex.EncodeToString(sum[:8]),
+	}
+}
+
+func newLintBaseline(findings []*plugins.Message) *lintBaseline {
+	b := &lintBaseline{Findings: make([]*baselineEntry, 0, len(findings))}
+	for _, finding := range findings {
+		b.Findings = append(b.Findings, baselineEntryForFinding(finding))
+	}
+	return b
+}
+
+func readLintBaseline(filename string) (*lintBaseline, error) {
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	b := &lintBaseline{}
+	if err = json.Unmarshal(bytes, b); err != nil {
+		return nil, fmt.Errorf("invalid lint baseline %s: %s", filename, err.Error())
+	}
+	return b, nil
+}
+
+// write writes a baseline with its entries sorted, so that baselines of
+// the same findings are identical.
+func (b *lintBaseline) write(filename string) error {
+	sort.SliceStable(b.Findings, func(i, j int) bool {
+		return b.Findings[i].key() < b.Findings[j].key()
+	})
+	bytes, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return compiler.WriteFileAtomically(filename, append(bytes, '\n'))
+}
+
+// match returns whether each finding is in the baseline and the entries
+// of the baseline that match no finding. Each entry matches one finding.
+func (b *lintBaseline) match(findings []*plugins.Message) ([]bool, []*baselineEntry) {
+	counts := make(map[string]int)
+	for _, entry := range b.Findings {
+		counts[entry.key()]++
+	}
+	baselined := make([]bool, len(findings))
+	for i, finding := range findings {
+		key := baselineEntryForFinding(finding).key()
+		if counts[key] > 0 {
+			counts[key]--
+			baselined[i] = true
+		}
+	}
+	stale := make([]*baselineEntry, 0)
+	for _, entry := range b.Findings {
+		if counts[entry.key()] > 0 {
+			counts[entry.key()]--
+			stale = append(stale, entry)
+		}
+	}
+	return baselined, stale
+}
+
+// applyLintBaseline returns whether each finding is in the lint baseline.
+// With --lint-baseline-write, the findings are written to the baseline and
+// are all baselined. With --lint-baseline-prune, the entries that match no
+// finding are removed from the baseline.
+func (g *Gnostic) applyLintBaseline(findings []*plugins.Message) ([]bool, error) {
+	if g.lintBaselinePath == "" {
+		return make([]bool, len(findings)), nil
+	}
+	if g.lintBaselineWrite {
+		baselined := make([]bool, len(findings))
+		for i := range baselined {
+			baselined[i] = true
+		}
+		return baselined, newLintBaseline(findings).write(g.lintBaselinePath)
+	}
+	b, err := readLintBaseline(g.lintBaselinePath)
+	if err != nil {
+		return nil, err
+	}
+	baselined, stale := b.match(findings)
+	if g.lintBaselinePrune && len(stale) > 0 {
+		isStale := make(map[*baselineEntry]bool)
+		for _, entry := range stale {
+			isStale[entry] = true
+		}
+		kept := make([]*baselineEntry, 0, len(b.Findings)-len(stale))
+		for _, entry := range b.Findings {
+			if !isStale[entry] {
+				kept = append(kept, entry)
+			}
+		}
+		b.Findings = kept
+		if err = b.write(g.lintBaselinePath); err != nil {
+			return nil, err
+		}
+	}
+	return baselined, nil
+}
+
+// baselinedSuffix returns the suffix of printed findings that are in the
+// lint baseline.
+func baselinedSuffix(baselined bool) string {
+	if baselined {
+		return " (baselined)"
+	}
+	return ""
+}
+
+// parseFailOnLevel returns the message level named by a --fail-on value.
+func parseFailOnLevel(value string) (plugins.Message_Level, error) {
+	level, ok := plugins.Message_Level_value[strings.ToUpper(value)]
+	if !ok || level == int32(plugins.Message_UNKNOWN) {
+		return plugins.Message_UNKNOWN, NewUsageError("invalid value for --fail-on: " + value)
+	}
+	return plugins.Message_Level(level), nil
+}
+
+// checkFindings returns an error if findings that aren't baselined are at
+// or above the --fail-on level.
+func (g *Gnostic) checkFindings(findings []*plugins.Message, baselined []bool) error {
+	if g.failOn == "" {
+		return nil
+	}
+	level, err := parseFailOnLevel(g.failOn)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for i, finding := range findings {
+		if !baselined[i] && finding.Level >= level {
+			count++
+		}
+	}
+	switch count {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("1 new finding at level %s or above", strings.ToLower(level.String()))
+	default:
+		return fmt.Errorf("%d new findings at level %s or above", count, strings.ToLower(level.String()))
+	}
+}
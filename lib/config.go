@@ -0,0 +1,487 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	"github.com/google/gnostic/jsonschema"
+)
+
+// configFileName is the name of the configuration file that is
+// discovered in the directory of the source.
+const configFileName = "gnostic.yaml"
+
+// configSchemaSource is a JSON Schema that describes configuration files.
+const configSchemaSource = `
+type: object
+additionalProperties: false
+properties:
+  source:
+    type: string
+  outputs:
+    type: object
+    additionalProperties: false
+    properties:
+      pb:
+        type: string
+      text:
+        type: string
+      json:
+        type: string
+      yaml:
+        type: string
+      errors:
+        type: string
+      messages:
+        type: string
+      markdown-docs:
+        type: string
+  plugins:
+    type: array
+    items:
+      type: object
+      additionalProperties: false
+      required: [name]
+      properties:
+        name:
+          type: string
+        output:
+          type: string
+        parameters:
+          type: object
+          additionalProperties:
+            type: string
+  header-comment-file:
+    type: string
+  extensions:
+    type: array
+    items:
+      type: string
+  resolve-refs:
+    type: boolean
+  fetch-external-examples:
+    type: boolean
+  fetch-cache-dir:
+    type: string
+  fetch-retries:
+    type: integer
+    minimum: 0
+  time-plugins:
+    type: boolean
+  no-surface:
+    type: boolean
+  simplify-unions:
+    type: boolean
+  infer-missing-schemas:
+    type: boolean
+  annotate-sources:
+    type: boolean
+  code-samples:
+    type: array
+    items:
+      type: string
+  version-header:
+    type: string
+  version-header-description:
+    type: string
+  dedupe-components:
+    type: boolean
+  lint-duplicates:
+    type: boolean
+  validate-examples:
+    type: boolean
+  check-grpc:
+    type: string
+  lint-baseline:
+    type: string
+  lint-baseline-write:
+    type: boolean
+  lint-baseline-prune:
+    type: boolean
+  fail-on:
+    type: string
+  dedupe-threshold:
+    type: integer
+    minimum: 1
+  dedupe-ignore-descriptions:
+    type: boolean
+  anonymize:
+    type: boolean
+  imports:
+    type: array
+    items:
+      type: object
+      additionalProperties: false
+      required: [components]
+      properties:
+        components:
+          type: string
+        prefix:
+          type: string
+  strip-extension:
+    type: string
+  strip-extensions-matching:
+    type: string
+  json-out-style:
+    type: string
+  json-errors:
+    type: boolean
+  skip-validation:
+    type: boolean
+  keep-partial:
+    type: boolean
+  fail-fast:
+    type: boolean
+  verbose:
+    type: boolean
+  quiet:
+    type: boolean
+  mock-server:
+    type: string
+`
+
+// config holds the contents of a configuration file.
+type config struct {
+	Source  string `yaml:"source"`
+	Outputs struct {
+		Pb           string `yaml:"pb"`
+		Text         string `yaml:"text"`
+		JSON         string `yaml:"json"`
+		YAML         string `yaml:"yaml"`
+		Errors       string `yaml:"errors"`
+		Messages     string `yaml:"messages"`
+		MarkdownDocs string `yaml:"markdown-docs"`
+	} `yaml:"outputs"`
+	Plugins []struct {
+		Name       string            `yaml:"name"`
+		Output     string            `yaml:"output"`
+		Parameters map[string]string `yaml:"parameters"`
+	} `yaml:"plugins"`
+	HeaderCommentFile     string   `yaml:"header-comment-file"`
+	Extensions            []string `yaml:"extensions"`
+	ResolveRefs           bool     `yaml:"resolve-refs"`
+	FetchExternalExamples bool     `yaml:"fetch-external-examples"`
+	FetchCacheDir         string   `yaml:"fetch-cache-dir"`
+	FetchRetries          *int     `yaml:"fetch-retries"`
+	TimePlugins           bool     `yaml:"time-plugins"`
+	NoSurface             bool     `yaml:"no-surface"`
+	SimplifyUnions        bool     `yaml:"simplify-unions"`
+	InferMissingSchemas   bool     `yaml:"infer-missing-schemas"`
+	AnnotateSources       bool     `yaml:"annotate-sources"`
+	CodeSamples           []string `yaml:"code-samples"`
+	VersionHeader         string   `yaml:"version-header"`
+	VersionDescription    string   `yaml:"version-header-description"`
+	DedupeComponents      bool     `yaml:"dedupe-components"`
+	LintDuplicates        bool     `yaml:"lint-duplicates"`
+	ValidateExamples      bool     `yaml:"validate-examples"`
+	CheckGRPC             string   `yaml:"check-grpc"`
+	LintBaseline          string   `yaml:"lint-baseline"`
+	LintBaselineWrite     bool     `yaml:"lint-baseline-write"`
+	LintBaselinePrune     bool     `yaml:"lint-baseline-prune"`
+	FailOn                string   `yaml:"fail-on"`
+	DedupeThreshold       *int     `yaml:"dedupe-threshold"`
+	DedupeDescriptions    bool     `yaml:"dedupe-ignore-descriptions"`
+	Anonymize             bool     `yaml:"anonymize"`
+	Imports               []struct {
+		Components string `yaml:"components"`
+		Prefix     string `yaml:"prefix"`
+	} `yaml:"imports"`
+	StripExtension          string `yaml:"strip-extension"`
+	StripExtensionsMatching string `yaml:"strip-extensions-matching"`
+	JSONOutStyle            string `yaml:"json-out-style"`
+	JSONErrors              bool   `yaml:"json-errors"`
+	SkipValidation          bool   `yaml:"skip-validation"`
+	KeepPartial             bool   `yaml:"keep-partial"`
+	FailFast                bool   `yaml:"fail-fast"`
+	Verbose                 bool   `yaml:"verbose"`
+	Quiet                   bool   `yaml:"quiet"`
+	MockServer              string `yaml:"mock-server"`
+}
+
+// readConfig reads and validates a configuration file.
+func readConfig(filename string) (*config, error) {
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	node, err := compiler.ParseYAML(filename, bytes)
+	if err != nil {
+		return nil, err
+	}
+	if len(node.Content) == 0 {
+		return &config{}, nil
+	}
+	var schemaNode yaml.Node
+	if err = yaml.Unmarshal([]byte(configSchemaSource), &schemaNode); err != nil {
+		return nil, err
+	}
+	schema := jsonschema.NewSchemaFromObject(&schemaNode)
+	errors := validateConfigNode(schema, node.Content[0], "")
+	for i, err := range errors {
+		errors[i] = fmt.Errorf("%s: %s", filename, err.Error())
+	}
+	if err = compiler.NewErrorGroupOrNil(errors); err != nil {
+		return nil, err
+	}
+	c := &config{}
+	if err = node.Decode(c); err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err.Error())
+	}
+	return c, nil
+}
+
+// validateConfigNode checks a node against the subset of JSON Schema
+// that is used to describe configuration files.
+func validateConfigNode(schema *jsonschema.Schema, node *yaml.Node, path string) []error {
+	errors := make([]error, 0)
+	location := path
+	if location == "" {
+		location = "configuration"
+	}
+	if schema.Type != nil && schema.Type.String != nil {
+		kind := *schema.Type.String
+		valid := true
+		switch kind {
+		case "object":
+			valid = node.Kind == yaml.MappingNode
+		case "array":
+			valid = node.Kind == yaml.SequenceNode
+		case "string":
+			valid = node.Kind == yaml.ScalarNode && node.Tag != "!!null"
+		case "boolean":
+			valid = node.Kind == yaml.ScalarNode && node.Tag == "!!bool"
+		case "integer":
+			valid = node.Kind == yaml.ScalarNode && node.Tag == "!!int"
+		}
+		if !valid {
+			return append(errors, fmt.Errorf("%s: expected %s", location, kind))
+		}
+	}
+	if schema.Minimum != nil && schema.Minimum.Integer != nil && node.Tag == "!!int" {
+		if value, err := strconv.ParseInt(node.Value, 10, 64); err == nil && value < *schema.Minimum.Integer {
+			return append(errors, fmt.Errorf("%s: must be at least %d", location, *schema.Minimum.Integer))
+		}
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		present := make(map[string]bool)
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			value := node.Content[i+1]
+			present[key] = true
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			if property := schema.PropertyWithName(key); property != nil {
+				errors = append(errors, validateConfigNode(property, value, keyPath)...)
+			} else if schema.AdditionalProperties != nil {
+				if schema.AdditionalProperties.Schema != nil {
+					errors = append(errors, validateConfigNode(schema.AdditionalProperties.Schema, value, keyPath)...)
+				} else if b := schema.AdditionalProperties.Boolean; b != nil && !*b {
+					errors = append(errors, fmt.Errorf("%s: unknown key", keyPath))
+				}
+			}
+		}
+		if schema.Required != nil {
+			for _, name := range *schema.Required {
+				if !present[name] {
+					errors = append(errors, fmt.Errorf("%s: missing required key %s", location, name))
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for i, item := range node.Content {
+				errors = append(errors, validateConfigNode(schema.Items.Schema, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return errors
+}
+
+// applyConfig sets options from a configuration file.
+// Options that were set on the command line take precedence.
+func (g *Gnostic) applyConfig(c *config, filename string) {
+	// Paths in configuration files are relative to the file.
+	dir := filepath.Dir(filename)
+	resolve := func(path string) string {
+		if path == "" || path == "!" || path == "-" || path == "=" ||
+			filepath.IsAbs(path) || isURL(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	}
+	set := func(option *string, value string) {
+		if *option == "" {
+			*option = resolve(value)
+		}
+	}
+	setOutput := func(name string, value string) {
+		if value != "" && !g.hasOutputCall(name) {
+			g.setOutputCall(name, resolve(value))
+		}
+	}
+	set(&g.sourceName, c.Source)
+	setOutput("pb", c.Outputs.Pb)
+	setOutput("text", c.Outputs.Text)
+	setOutput("json", c.Outputs.JSON)
+	setOutput("yaml", c.Outputs.YAML)
+	set(&g.errorOutputPath, c.Outputs.Errors)
+	set(&g.messageOutputPath, c.Outputs.Messages)
+	set(&g.markdownDocsDir, c.Outputs.MarkdownDocs)
+	set(&g.headerCommentPath, c.HeaderCommentFile)
+	set(&g.fetchCacheDir, c.FetchCacheDir)
+	set(&g.lintBaselinePath, c.LintBaseline)
+	set(&g.grpcDescriptorsPath, c.CheckGRPC)
+	if g.failOn == "" {
+		g.failOn = c.FailOn
+	}
+	if g.jsonOutStyle == "" {
+		g.jsonOutStyle = c.JSONOutStyle
+	}
+	if len(g.componentImports) == 0 {
+		for _, i := range c.Imports {
+			g.componentImports = append(g.componentImports, &componentImport{Path: resolve(i.Components), Prefix: i.Prefix})
+		}
+	}
+	if g.fetchRetries < 0 && c.FetchRetries != nil {
+		g.fetchRetries = *c.FetchRetries
+	}
+
+	for _, plugin := range c.Plugins {
+		if g.hasPluginCall(plugin.Name) {
+			continue
+		}
+		output := plugin.Output
+		if output == "" {
+			output = "!"
+		}
+		invocation := resolve(output)
+		if len(plugin.Parameters) > 0 {
+			keys := make([]string, 0, len(plugin.Parameters))
+			for k := range plugin.Parameters {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			pairs := make([]string, len(keys))
+			for i, k := range keys {
+				pairs[i] = k + "=" + plugin.Parameters[k]
+			}
+			invocation = strings.Join(pairs, ",") + ":" + invocation
+		}
+		g.pluginCalls = append(g.pluginCalls, &pluginCall{Name: plugin.Name, Invocation: invocation})
+	}
+	for _, name := range c.Extensions {
+		handler := compiler.ExtensionHandler{Name: extensionPrefix + strings.TrimPrefix(name, "x-")}
+		found := false
+		for _, h := range g.extensionHandlers {
+			found = found || h.Name == handler.Name
+		}
+		if !found {
+			g.extensionHandlers = append(g.extensionHandlers, handler)
+		}
+	}
+
+	// Boolean options that were set on the command line, to true or
+	// false, take precedence.
+	options := g.booleanOptions()
+	for name, value := range map[string]bool{
+		"resolve-refs":               c.ResolveRefs,
+		"fetch-external-examples":    c.FetchExternalExamples,
+		"time-plugins":               c.TimePlugins,
+		"no-surface":                 c.NoSurface,
+		"simplify-unions":            c.SimplifyUnions,
+		"infer-missing-schemas":      c.InferMissingSchemas,
+		"annotate-sources":           c.AnnotateSources,
+		"dedupe-components":          c.DedupeComponents,
+		"lint-duplicates":            c.LintDuplicates,
+		"lint-baseline-write":        c.LintBaselineWrite,
+		"lint-baseline-prune":        c.LintBaselinePrune,
+		"validate-examples":          c.ValidateExamples,
+		"dedupe-ignore-descriptions": c.DedupeDescriptions,
+		"anonymize":                  c.Anonymize,
+		"json-errors":                c.JSONErrors,
+		"skip-validation":            c.SkipValidation,
+		"keep-partial":               c.KeepPartial,
+		"fail-fast":                  c.FailFast,
+		"verbose":                    c.Verbose,
+		"quiet":                      c.Quiet,
+	} {
+		if !g.explicitOptions[name] {
+			*options[name] = *options[name] || value
+		}
+	}
+	if g.dedupeThreshold < 0 && c.DedupeThreshold != nil {
+		g.dedupeThreshold = *c.DedupeThreshold
+	}
+	if len(g.codeSamples) == 0 {
+		g.codeSamples = c.CodeSamples
+	}
+	if g.versionHeader == "" {
+		g.versionHeader = c.VersionHeader
+	}
+	if g.versionDescription == "" {
+		g.versionDescription = c.VersionDescription
+	}
+	if g.stripMarker == "" {
+		g.stripMarker = c.StripExtension
+	}
+	if g.stripPattern == "" {
+		g.stripPattern = c.StripExtensionsMatching
+	}
+	if g.mockServerAddress == "" {
+		g.mockServerAddress = c.MockServer
+	}
+}
+
+func (g *Gnostic) hasPluginCall(name string) bool {
+	for _, p := range g.pluginCalls {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readConfigFile reads the configuration file named with --config or,
+// if none was named, a gnostic.yaml file in the directory of the source.
+func (g *Gnostic) readConfigFile() error {
+	filename := g.configPath
+	if filename == "" {
+		if g.sourceName == "" || g.sourceName == "-" || isURL(g.sourceName) {
+			return nil
+		}
+		filename = filepath.Join(filepath.Dir(g.sourceName), configFileName)
+		if !isFile(filename) {
+			return nil
+		}
+	}
+	c, err := readConfig(filename)
+	if err != nil {
+		return err
+	}
+	g.applyConfig(c, filename)
+	return nil
+}
@@ -0,0 +1,551 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	"github.com/google/gnostic/conversions"
+	"github.com/google/gnostic/jsonwriter"
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+)
+
+// A ConvertedFile describes a file that ConvertTree converted.
+type ConvertedFile struct {
+	// Path is the slash-separated path of the file relative to the input
+	// and output directories.
+	Path string
+	// Partial is true for files that are referenced by other files.
+	Partial bool
+	// Warnings describe parts of the file that were changed or couldn't
+	// be converted.
+	Warnings []string
+	// Err is the error that prevented the conversion of the file.
+	Err error
+}
+
+// ConvertTree converts the Swagger 2.0 documents in the directory in to
+// OpenAPI v3 and writes them to the same paths in the directory out.
+// Documents are the files with a "swagger: 2.0" field that no other file
+// references. The files that documents reference, directly or through
+// other files, are partials and are converted too. References to other
+// files are rewritten to the converted locations, for example from
+// "common.yaml#/definitions/Error" to "common.yaml#/components/schemas/Error".
+//
+// Partials with definitions, parameters, responses or paths become files
+// with the corresponding components and paths. Other partials are a
+// schema, a parameter, a response or a path item, or a map of them, and
+// what they are is inferred from where they are referenced. They are
+// converted in place, so references to them don't change.
+func ConvertTree(in, out string) ([]*ConvertedFile, error) {
+	t := &tree{files: make(map[string]*treeFile)}
+	if err := t.read(in, out); err != nil {
+		return nil, err
+	}
+	t.link()
+	var results []*ConvertedFile
+	for _, name := range t.names() {
+		f := t.files[name]
+		if !f.root && !f.partial {
+			continue
+		}
+		result := &ConvertedFile{Path: f.path, Partial: f.partial, Warnings: f.warnings}
+		node, warnings, err := t.convert(f)
+		result.Warnings = append(result.Warnings, warnings...)
+		if err == nil {
+			err = writeTreeFile(filepath.Join(out, filepath.FromSlash(f.path)), node)
+		}
+		result.Err = err
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// writeConvertSummary writes the converted files and their warnings.
+func writeConvertSummary(w io.Writer, results []*ConvertedFile) error {
+	documents, partials, warnings, failures := 0, 0, 0, 0
+	for _, result := range results {
+		kind := "document"
+		if result.Partial {
+			kind = "partial"
+			partials++
+		} else {
+			documents++
+		}
+		status := "converted"
+		if result.Err != nil {
+			status = "failed: " + strings.Replace(result.Err.Error(), "\n", " ", -1)
+			failures++
+		}
+		if _, err := fmt.Fprintf(w, "%s (%s): %s\n", result.Path, kind, status); err != nil {
+			return err
+		}
+		for _, warning := range result.Warnings {
+			if _, err := fmt.Fprintf(w, "  %s\n", warning); err != nil {
+				return err
+			}
+		}
+		warnings += len(result.Warnings)
+	}
+	_, err := fmt.Fprintf(w, "%d documents, %d partials, %d warnings, %d failures\n", documents, partials, warnings, failures)
+	return err
+}
+
+// convertTree runs ConvertTree for the convert command.
+func (g *Gnostic) convertTree() error {
+	results, err := ConvertTree(g.convertIn, g.convertOut)
+	if err != nil {
+		return err
+	}
+	if err := writeConvertSummary(os.Stdout, results); err != nil {
+		return err
+	}
+	failures := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failures++
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d of %d files couldn't be converted", failures, len(results))
+	}
+	return nil
+}
+
+// The kinds of values that partials contain.
+const (
+	schemaKind    = "schema"
+	parameterKind = "parameter"
+	responseKind  = "response"
+	pathItemKind  = "path item"
+)
+
+type tree struct {
+	files map[string]*treeFile
+}
+
+type treeFile struct {
+	path     string
+	node     *yaml.Node
+	swagger  bool
+	root     bool
+	partial  bool
+	refs     []*treeRef
+	warnings []string
+	// uses are the kinds of the references to the partial, by fragment.
+	uses map[string]string
+}
+
+// A treeRef is a reference to another file.
+type treeRef struct {
+	// keys locate the value with the reference in its file.
+	keys     []string
+	target   string
+	fragment string
+}
+
+// read parses the JSON and YAML files in the directory in, except those
+// in the directory out.
+func (t *tree) read(in, out string) error {
+	absOut, _ := filepath.Abs(out)
+	return filepath.Walk(in, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if abs, _ := filepath.Abs(name); abs == absOut && name != in {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		rel, err := filepath.Rel(in, name)
+		if err != nil {
+			return err
+		}
+		bytes, err := ioutil.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		var node yaml.Node
+		if yaml.Unmarshal(bytes, &node) != nil || len(node.Content) == 0 {
+			// Files that aren't YAML can't be documents or partials.
+			return nil
+		}
+		f := &treeFile{path: filepath.ToSlash(rel), node: node.Content[0], uses: make(map[string]string)}
+		if f.node.Kind == yaml.MappingNode {
+			if version := compiler.MapValueForKey(f.node, "swagger"); version != nil && version.Value == "2.0" {
+				f.swagger = true
+			}
+		}
+		t.files[f.path] = f
+		return nil
+	})
+}
+
+func (t *tree) names() []string {
+	names := make([]string, 0, len(t.files))
+	for name := range t.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// link finds the references between files, the documents, and the
+// partials that documents use.
+func (t *tree) link() {
+	referenced := make(map[string]bool)
+	for _, name := range t.names() {
+		f := t.files[name]
+		compiler.VisitAll(f.node, func(pointer, key string, value *yaml.Node) {
+			if key != "$ref" || value.Kind != yaml.ScalarNode {
+				return
+			}
+			parts := strings.SplitN(value.Value, "#", 2)
+			if parts[0] == "" || strings.Contains(parts[0], "://") {
+				return
+			}
+			ref := &treeRef{
+				keys:   pointerKeys(strings.TrimSuffix(pointer, "/$ref")),
+				target: path.Join(path.Dir(f.path), parts[0]),
+			}
+			if len(parts) > 1 {
+				ref.fragment = parts[1]
+			}
+			f.refs = append(f.refs, ref)
+			if ref.target != f.path {
+				referenced[ref.target] = true
+			}
+		})
+	}
+	var queue []*treeFile
+	for _, name := range t.names() {
+		if f := t.files[name]; f.swagger && !referenced[name] {
+			f.root = true
+			queue = append(queue, f)
+		}
+	}
+	// Partials are the files that documents reach through references.
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+		for _, ref := range f.refs {
+			target, ok := t.files[ref.target]
+			switch {
+			case strings.HasPrefix(ref.target, "../"):
+				f.warn(ref.keys, "%s is outside of the input directory and isn't converted", ref.target)
+				continue
+			case !ok:
+				f.warn(ref.keys, "%s doesn't exist or isn't a JSON or YAML file", ref.target)
+				continue
+			case target == f:
+				continue
+			}
+			kind := refKind(ref.keys)
+			if use, ok := target.uses[ref.fragment]; ok && use != kind {
+				target.warn(nil, "#%s is used as a %s and as a %s", ref.fragment, use, kind)
+			} else if !ok {
+				target.uses[ref.fragment] = kind
+			}
+			if !target.partial {
+				target.partial = true
+				queue = append(queue, target)
+			}
+		}
+	}
+}
+
+func (f *treeFile) warn(keys []string, format string, args ...interface{}) {
+	pointer := "#"
+	for _, key := range keys {
+		pointer += "/" + compiler.EscapeJSONPointer(key)
+	}
+	f.warnings = append(f.warnings, pointer+": "+fmt.Sprintf(format, args...))
+}
+
+// pointerKeys returns the unescaped keys of a JSON Pointer.
+func pointerKeys(pointer string) []string {
+	if pointer == "" || pointer == "/" {
+		return nil
+	}
+	keys := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, key := range keys {
+		keys[i] = strings.Replace(strings.Replace(key, "~1", "/", -1), "~0", "~", -1)
+	}
+	return keys
+}
+
+// refKind returns the kind of value that a reference at keys refers to.
+// References in lists of parameters and maps of responses refer to
+// parameters and responses, references of path items to path items,
+// and all other references to schemas.
+func refKind(keys []string) string {
+	n := len(keys)
+	switch {
+	case n >= 2 && keys[n-2] == "parameters" && (n == 2 || keys[n-3] != "properties"):
+		return parameterKind
+	case n >= 2 && keys[n-2] == "responses" && (n == 2 || keys[n-3] != "properties"):
+		return responseKind
+	case n == 2 && keys[0] == "paths":
+		return pathItemKind
+	}
+	return schemaKind
+}
+
+// nodeAtPointer returns the value of a JSON Pointer in a node, or nil.
+func nodeAtPointer(node *yaml.Node, pointer string) *yaml.Node {
+	for _, key := range pointerKeys(pointer) {
+		switch {
+		case node == nil:
+			return nil
+		case node.Kind == yaml.MappingNode:
+			node = compiler.MapValueForKey(node, key)
+		case node.Kind == yaml.SequenceNode:
+			var i int
+			if _, err := fmt.Sscanf(key, "%d", &i); err != nil || i < 0 || i >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[i]
+		default:
+			return nil
+		}
+	}
+	return node
+}
+
+// sections are the keys of Swagger 2.0 documents that partials can have.
+var sections = map[string]string{
+	schemaKind:    "definitions",
+	parameterKind: "parameters",
+	responseKind:  "responses",
+	pathItemKind:  "paths",
+}
+
+// hasSections returns true if a file has any keys of a Swagger 2.0
+// document that contain reusable values. Files that are referenced as a
+// whole, such as path items with parameters, are values even if they
+// have these keys.
+func (f *treeFile) hasSections() bool {
+	for _, key := range []string{"swagger", "definitions", "parameters", "responses", "paths"} {
+		if compiler.MapValueForKey(f.node, key) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// convert returns the converted contents of a document or partial.
+func (t *tree) convert(f *treeFile) (*yaml.Node, []string, error) {
+	_, whole := f.uses[""]
+	if f.node.Kind == yaml.MappingNode && (f.root || (!whole && f.hasSections())) {
+		return t.convertSections(f)
+	}
+	var fragments []string
+	for fragment := range f.uses {
+		fragments = append(fragments, fragment)
+	}
+	sort.Strings(fragments)
+	kind := ""
+	entries := false
+	for _, fragment := range fragments {
+		if kind == "" {
+			kind = f.uses[fragment]
+		}
+		switch len(pointerKeys(fragment)) {
+		case 0:
+		case 1:
+			entries = true
+		default:
+			return f.node, []string{fmt.Sprintf("#%s: references to nested values aren't supported, the file is copied", fragment)}, nil
+		}
+	}
+	switch {
+	case whole && entries:
+		return f.node, []string{"#: references to the file and its entries aren't supported, the file is copied"}, nil
+	case whole:
+		return t.convertValues(f, kind, []string{"partial"}, []*yaml.Node{f.node}, false)
+	case f.node.Kind != yaml.MappingNode:
+		return f.node, []string{"#: the file isn't a map, the file is copied"}, nil
+	}
+	var names []string
+	var values []*yaml.Node
+	for i := 0; i+1 < len(f.node.Content); i += 2 {
+		names = append(names, f.node.Content[i].Value)
+		values = append(values, f.node.Content[i+1])
+	}
+	return t.convertValues(f, kind, names, values, true)
+}
+
+// document parses a Swagger 2.0 document and converts it to OpenAPI v3.
+// External parameters are read from the files of the tree.
+func (t *tree) document(f *treeFile, node *yaml.Node) (*yaml.Node, []string, error) {
+	source, err := openapi_v2.NewDocument(node, compiler.NewContext("$root", node, nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	options := &conversions.ConversionOptions{
+		ExternalParameter: func(ref string) *openapi_v2.Parameter {
+			parts := strings.SplitN(ref, "#", 2)
+			target, ok := t.files[path.Join(path.Dir(f.path), parts[0])]
+			if !ok {
+				return nil
+			}
+			fragment := ""
+			if len(parts) > 1 {
+				fragment = parts[1]
+			}
+			value := nodeAtPointer(target.node, fragment)
+			if value == nil {
+				return nil
+			}
+			parameter, err := openapi_v2.NewParameter(value, compiler.NewContext("$root", value, nil))
+			if err != nil {
+				return nil
+			}
+			return parameter
+		},
+	}
+	converted, warnings, err := conversions.OpenAPIv3FromOpenAPIv2(source, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conversions.ConvertedRawInfo(converted), warnings, nil
+}
+
+// convertSections converts a document, or a partial with the keys of a
+// document. Partials keep only their components and paths.
+func (t *tree) convertSections(f *treeFile) (*yaml.Node, []string, error) {
+	node := f.node
+	if !f.swagger {
+		node = compiler.NewMappingNode()
+		node.Content = append(node.Content,
+			compiler.NewScalarNodeForString("swagger"), compiler.NewScalarNodeForString("2.0"),
+			compiler.NewScalarNodeForString("info"), infoNode(f.path))
+		if compiler.MapValueForKey(f.node, "paths") == nil {
+			node.Content = append(node.Content, compiler.NewScalarNodeForString("paths"), compiler.NewMappingNode())
+		}
+		node.Content = append(node.Content, f.node.Content...)
+	}
+	converted, warnings, err := t.document(f, node)
+	if err != nil || f.swagger {
+		return converted, warnings, err
+	}
+	result := compiler.NewMappingNode()
+	for _, key := range []string{"paths", "components"} {
+		if key == "paths" && compiler.MapValueForKey(f.node, "paths") == nil {
+			continue
+		}
+		if value := compiler.MapValueForKey(converted, key); value != nil {
+			result.Content = append(result.Content, compiler.NewScalarNodeForString(key), value)
+		}
+	}
+	return result, warnings, nil
+}
+
+// convertValues converts values of a kind by converting a document with
+// the values in the corresponding section. It returns the value, or a map
+// of the values if asMap is true.
+func (t *tree) convertValues(f *treeFile, kind string, names []string, values []*yaml.Node, asMap bool) (*yaml.Node, []string, error) {
+	section := compiler.NewMappingNode()
+	for i, name := range names {
+		if kind == pathItemKind {
+			name = "/" + name
+		}
+		section.Content = append(section.Content, compiler.NewScalarNodeForString(name), values[i])
+	}
+	node := compiler.NewMappingNode()
+	node.Content = append(node.Content,
+		compiler.NewScalarNodeForString("swagger"), compiler.NewScalarNodeForString("2.0"),
+		compiler.NewScalarNodeForString("info"), infoNode(f.path))
+	if kind != pathItemKind {
+		node.Content = append(node.Content, compiler.NewScalarNodeForString("paths"), compiler.NewMappingNode())
+	}
+	node.Content = append(node.Content, compiler.NewScalarNodeForString(sections[kind]), section)
+	converted, warnings, err := t.document(f, node)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := compiler.NewMappingNode()
+	for i, name := range names {
+		var value *yaml.Node
+		switch kind {
+		case schemaKind:
+			value = nodeAtPointer(converted, "/components/schemas/"+name)
+		case parameterKind:
+			value = nodeAtPointer(converted, "/components/parameters/"+name)
+			if value == nil {
+				// Body parameters become request bodies.
+				value = nodeAtPointer(converted, "/components/requestBodies/"+name)
+			}
+		case responseKind:
+			value = nodeAtPointer(converted, "/components/responses/"+name)
+		case pathItemKind:
+			value = compiler.MapValueForKey(compiler.MapValueForKey(converted, "paths"), "/"+name)
+		}
+		if value == nil {
+			// Form parameters are copied into the operations that use them.
+			warnings = append(warnings, fmt.Sprintf("#/%s: form parameters are copied into the operations that use them and aren't converted", name))
+			value = values[i]
+		}
+		if !asMap {
+			return value, warnings, nil
+		}
+		result.Content = append(result.Content, compiler.NewScalarNodeForString(name), value)
+	}
+	return result, warnings, nil
+}
+
+// infoNode returns the info of the documents that partials are converted in.
+func infoNode(title string) *yaml.Node {
+	info := compiler.NewMappingNode()
+	info.Content = append(info.Content,
+		compiler.NewScalarNodeForString("title"), compiler.NewScalarNodeForString(title),
+		compiler.NewScalarNodeForString("version"), compiler.NewScalarNodeForString("1"))
+	return info
+}
+
+// writeTreeFile writes a converted file in JSON if its name ends with
+// .json and in YAML otherwise.
+func writeTreeFile(name string, node *yaml.Node) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	var bytes []byte
+	var err error
+	if strings.ToLower(filepath.Ext(name)) == ".json" {
+		bytes, err = jsonwriter.Marshal(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{node}})
+	} else {
+		bytes, err = yaml.Marshal(node)
+	}
+	if err != nil {
+		return err
+	}
+	return compiler.WriteFileAtomically(name, bytes)
+}
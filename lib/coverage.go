@@ -0,0 +1,185 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+)
+
+// The coverage output lists the operations of an OpenAPI document that
+// no request of a test run exercised. The "tests" parameter names a JSON
+// file with the requests of the run, e.g.
+//
+//	[{"method": "GET", "path": "/v1/pets/7"}, {"method": "POST", "path": "/v1/pets"}]
+//
+// The paths of requests can be concrete paths or the path templates of
+// the document, with or without the base path of the API (the basePath
+// of OpenAPI v2 documents or the paths of the URLs of the servers of
+// OpenAPI v3 documents). Query strings are ignored. The uncovered
+// operations are written as a JSON array sorted by path.
+
+// TestedRequest is a request of a test run.
+type TestedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// UncoveredOperation is an operation that no request of a test run exercised.
+type UncoveredOperation struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationID string `json:"operationId,omitempty"`
+}
+
+// UncoveredOperations returns the operations of an OpenAPI v2 or v3
+// document that none of the requests exercised, sorted by path.
+// Operations of the same path are in the order of the path item.
+func UncoveredOperations(doc Document, requests []*TestedRequest) ([]*UncoveredOperation, error) {
+	var operations []*UncoveredOperation
+	var basePaths []string
+	switch document := doc.(type) {
+	case *openapi_v2.Document:
+		openapi_v2.ForEachOperation(document, func(path, method string, item *openapi_v2.PathItem, operation *openapi_v2.Operation) {
+			operations = append(operations, &UncoveredOperation{Method: strings.ToUpper(method), Path: path, OperationID: operation.OperationId})
+		})
+		basePaths = append(basePaths, document.BasePath)
+	case *openapi_v3.Document:
+		openapi_v3.ForEachOperation(document, func(path, method string, item *openapi_v3.PathItem, operation *openapi_v3.Operation) {
+			operations = append(operations, &UncoveredOperation{Method: strings.ToUpper(method), Path: path, OperationID: operation.OperationId})
+		})
+		for _, server := range document.Servers {
+			basePaths = append(basePaths, urlPath(server.Url))
+		}
+	default:
+		return nil, errors.New("coverage can only be checked for OpenAPI documents")
+	}
+	prefix := basePathPattern(basePaths)
+	uncovered := make([]*UncoveredOperation, 0)
+	for _, operation := range operations {
+		pattern := regexp.MustCompile("^" + prefix + pathTemplatePattern(operation.Path) + "/?$")
+		covered := false
+		for _, request := range requests {
+			if strings.EqualFold(request.Method, operation.Method) && pattern.MatchString(requestPath(request.Path)) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			uncovered = append(uncovered, operation)
+		}
+	}
+	sort.SliceStable(uncovered, func(i, j int) bool {
+		return uncovered[i].Path < uncovered[j].Path
+	})
+	return uncovered, nil
+}
+
+// pathParameterRegex matches the parameters of path templates.
+var pathParameterRegex = regexp.MustCompile(`{[^}]*}`)
+
+// pathTemplatePattern returns a regular expression that matches the paths
+// of a path template. Parameters match a path segment or themselves.
+// Trailing slashes are removed, so that callers can make them optional.
+func pathTemplatePattern(template string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range pathParameterRegex.FindAllStringIndex(template, -1) {
+		b.WriteString(regexp.QuoteMeta(template[last:match[0]]))
+		b.WriteString(`[^/]+`)
+		last = match[1]
+	}
+	b.WriteString(regexp.QuoteMeta(strings.TrimSuffix(template[last:], "/")))
+	return b.String()
+}
+
+// basePathPattern returns a regular expression that optionally matches
+// one of the base paths of an API.
+func basePathPattern(basePaths []string) string {
+	alternatives := make([]string, 0)
+	for _, basePath := range basePaths {
+		if basePath = strings.TrimSuffix(basePath, "/"); basePath != "" {
+			alternatives = append(alternatives, pathTemplatePattern(basePath))
+		}
+	}
+	if len(alternatives) == 0 {
+		return ""
+	}
+	return "(?:" + strings.Join(alternatives, "|") + ")?"
+}
+
+// urlPath returns the path of a URL, which may have variables.
+func urlPath(url string) string {
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+		if j := strings.Index(url, "/"); j >= 0 {
+			return url[j:]
+		}
+		return ""
+	}
+	return url
+}
+
+// requestPath returns the path of a request without its query string.
+func requestPath(path string) string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	return urlPath(path)
+}
+
+// readTestedRequests reads the requests of a test run from a JSON file.
+func readTestedRequests(filename string) ([]*TestedRequest, error) {
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var requests []*TestedRequest
+	if err := json.Unmarshal(bytes, &requests); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return requests, nil
+}
+
+// Write the operations that a test run didn't exercise.
+func writeCoverage(doc Document, w io.Writer, params map[string]string) error {
+	tests := params["tests"]
+	if tests == "" {
+		return errors.New("the coverage output requires a tests parameter")
+	}
+	requests, err := readTestedRequests(tests)
+	if err != nil {
+		return err
+	}
+	uncovered, err := UncoveredOperations(doc, requests)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(uncovered, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+)
+
+// The deps output lists the operations that use component schemas (see
+// openapi_v3.UsageIndex). With a "schema" parameter, it writes one line for
+// each operation that uses the schema. Otherwise it writes one line for
+// each schema with the schema name and the operations that use it.
+
+// Write the operations that use component schemas.
+func writeDeps(doc Document, w io.Writer, params map[string]string) error {
+	document, ok := doc.(*openapi_v3.Document)
+	if !ok {
+		return errors.New("dependencies can only be listed for OpenAPI v3 documents")
+	}
+	index := openapi_v3.BuildUsageIndex(document)
+	if name, ok := params["schema"]; ok {
+		for _, operation := range index.OperationsUsingSchema(name) {
+			if _, err := fmt.Fprintln(w, operation); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, pair := range document.GetComponents().GetSchemas().GetAdditionalProperties() {
+		operations := index.OperationsUsingSchema(pair.Name)
+		if _, err := fmt.Fprintf(w, "%s: %s\n", pair.Name, strings.Join(operations, ", ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
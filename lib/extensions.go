@@ -0,0 +1,80 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	"github.com/google/gnostic/jsonwriter"
+)
+
+// The extensions output writes the values of an extension, which is named
+// by the "extension" parameter, as a JSON array of objects with the JSON
+// Pointer of each occurrence of the extension and its value.
+
+// extensionValue is an element of the extensions output.
+type extensionValue struct {
+	Pointer string          `json:"pointer"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// Write the values of an extension.
+func writeExtensions(doc Document, w io.Writer, params map[string]string) error {
+	name := params["extension"]
+	if name == "" {
+		return errors.New("the extensions output requires an extension parameter")
+	}
+	rawInfo, err := rawInfoForDocument(doc)
+	if err != nil {
+		return err
+	}
+	values := make([]*extensionValue, 0)
+	if err := collectExtensionValues(rawInfo, name, &values); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// collectExtensionValues appends the values of an extension in a node and
+// its descendants to values. Values of the extension in other values of
+// the extension are not collected separately.
+func collectExtensionValues(node *yaml.Node, name string, values *[]*extensionValue) error {
+	var err error
+	collected := ""
+	compiler.VisitAll(node, func(path, key string, value *yaml.Node) {
+		if err != nil || key != name || (collected != "" && strings.HasPrefix(path, collected+"/")) {
+			return
+		}
+		var b []byte
+		if b, err = jsonwriter.Marshal(value); err != nil {
+			return
+		}
+		*values = append(*values, &extensionValue{Pointer: path, Value: json.RawMessage(bytes.TrimSpace(b))})
+		collected = path
+	})
+	return err
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+)
+
+// The fingerprints output lists the schema fingerprints of the request
+// bodies and responses of the operations of an OpenAPI v3 document as a
+// JSON array sorted by path and method. Fingerprints don't change when
+// a schema is restructured without a change of meaning, so contract
+// tests can compare them to detect changes of payloads.
+func writeFingerprints(doc Document, w io.Writer, params map[string]string) error {
+	document, ok := doc.(*openapi_v3.Document)
+	if !ok {
+		return errors.New("fingerprints can only be computed for OpenAPI v3 documents")
+	}
+	b, err := json.MarshalIndent(openapi_v3.Fingerprints(document), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
@@ -16,22 +16,27 @@ package lib
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"io"
-	"log"
+	"io/ioutil"
+	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"gopkg.in/yaml.v3"
 
 	"github.com/google/gnostic/compiler"
+	"github.com/google/gnostic/conversions"
 	discovery_v1 "github.com/google/gnostic/discovery"
 	"github.com/google/gnostic/jsonwriter"
 	openapi_v2 "github.com/google/gnostic/openapiv2"
@@ -100,13 +105,20 @@ const (
 	extensionPrefix = "gnostic-x-"
 )
 
+// An outputCall runs an output that was registered with RegisterOutput.
+type outputCall struct {
+	Name       string
+	Invocation string
+}
+
 type pluginCall struct {
 	Name       string
 	Invocation string
 }
 
-// Invokes a plugin.
-func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceName string, timePlugins bool, excludeSurface bool) ([]*plugins.Message, error) {
+// Invokes a plugin and returns its response and the location of its
+// output. The files of the response are written by the caller.
+func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceName string, timePlugins bool, excludeSurface bool, logger compiler.Logger) (*plugins.Response, string, error) {
 	if p.Name != "" {
 		request := &plugins.Request{}
 
@@ -126,7 +138,7 @@ func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceNam
 		//
 		invocationRegex := regexp.MustCompile(`^([\w-_\/\.]+=[\w-_\/\.]+(,[\w-_\/\.]+=[\w-_\/\.]+)*:)?[^,:=]+$`)
 		if !invocationRegex.Match([]byte(p.Invocation)) {
-			return nil, fmt.Errorf("Invalid invocation of %s: %s", executableName, invocation)
+			return nil, "", fmt.Errorf("Invalid invocation of %s: %s", executableName, invocation)
 		}
 
 		invocationParts := strings.Split(p.Invocation, ":")
@@ -183,6 +195,7 @@ func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceNam
 
 		requestBytes, _ := proto.Marshal(request)
 
+		logger.Debugf("running plugin %s", executableName)
 		cmd := exec.Command(executableName, "-plugin")
 		cmd.Stdin = bytes.NewReader(requestBytes)
 		cmd.Stderr = os.Stderr
@@ -193,7 +206,7 @@ func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceNam
 			fmt.Printf("> %s (%s)\n", executableName, pluginElapsedTime)
 		}
 		if err != nil {
-			return nil, err
+			return nil, outputLocation, err
 		}
 		response := &plugins.Response{}
 		err = proto.Unmarshal(output, response)
@@ -201,14 +214,14 @@ func (p *pluginCall) perform(document proto.Message, sourceFormat int, sourceNam
 			// Gnostic expects plugins to only write the
 			// response message to stdout. Be sure that
 			// any logging messages are written to stderr only.
-			return nil, errors.New("invalid plugin response (plugins must write log messages to stderr, not stdout)")
+			return nil, outputLocation, errors.New("invalid plugin response (plugins must write log messages to stderr, not stdout)")
 		}
-
-		err = plugins.HandleResponse(response, outputLocation)
-
-		return response.Messages, err
+		if response.Errors != nil {
+			return response, outputLocation, fmt.Errorf("Plugin error: %+v", response.Errors)
+		}
+		return response, outputLocation, nil
 	}
-	return nil, nil
+	return nil, "", nil
 }
 
 func isFile(path string) bool {
@@ -241,88 +254,313 @@ func isURL(path string) bool {
 
 // Write bytes to a named file.
 // Certain names have special meaning:
-//   ! writes nothing
-//   - writes to stdout
-//   = writes to stderr
+//
+//	! writes nothing
+//	- writes to stdout
+//	= writes to stderr
+//
 // If a directory name is given, the file is written there with
 // a name derived from the source and extension arguments.
-func writeFile(name string, bytes []byte, source string, extension string) {
-	var writer io.Writer
+// Files whose names end with .gz are compressed with gzip.
+// Files are written to a temporary file that replaces the named file
+// when it is complete, so a failed write leaves any previous file in place.
+func (g *Gnostic) writeFile(name string, bytes []byte, source string, extension string) error {
+	var filename string
 	if name == "!" {
-		return
+		return nil
 	} else if name == "-" {
-		writer = os.Stdout
+		_, err := os.Stdout.Write(bytes)
+		return err
 	} else if name == "=" {
-		writer = os.Stderr
+		_, err := os.Stderr.Write(bytes)
+		return err
+	} else if isDirectory(name) && source == "-" {
+		filename = name + "/stdin." + extension
 	} else if isDirectory(name) && !isURL(source) {
-		base := source
+		base := trimGzipExtension(source)
 		// Remove the original source extension.
 		base = base[0 : len(base)-len(filepath.Ext(base))]
 		// Build the path that puts the result in the passed-in directory.
-		filename := name + "/" + base + "." + extension
+		filename = name + "/" + base + "." + extension
 		// Make sure that the necessary output directory exists
 		err := os.MkdirAll(filepath.Dir(filename), os.ModePerm)
 		if err != nil {
-			log.Printf("error creating %s: %s", filepath.Dir(filename), err.Error())
+			g.logger().Warnf("error creating %s: %s", filepath.Dir(filename), err.Error())
 		}
-		// Write the file
-		file, _ := os.Create(filename)
-		defer file.Close()
-		writer = file
 	} else if isDirectory(name) {
-		base := filepath.Base(source)
+		base := filepath.Base(trimGzipExtension(source))
 		// Remove the original source extension.
 		base = base[0 : len(base)-len(filepath.Ext(base))]
 		// Build the path that puts the result in the passed-in directory.
-		filename := name + "/" + base + "." + extension
-		file, _ := os.Create(filename)
-		defer file.Close()
-		writer = file
+		filename = name + "/" + base + "." + extension
 	} else {
-		file, _ := os.Create(name)
-		defer file.Close()
-		writer = file
+		filename = name
+	}
+	if strings.HasSuffix(filename, ".gz") {
+		compressed, err := compiler.Compress(bytes)
+		if err != nil {
+			return err
+		}
+		bytes = compressed
+	}
+	return compiler.WriteFileAtomically(filename, bytes)
+}
+
+// trimGzipExtension removes a .gz extension from the name of a file.
+func trimGzipExtension(name string) string {
+	if strings.HasSuffix(strings.ToLower(name), ".gz") {
+		return name[:len(name)-len(".gz")]
 	}
-	writer.Write(bytes)
+	return name
 }
 
 // The Gnostic structure holds global state information for gnostic.
 type Gnostic struct {
-	args              []string
-	usage             string
-	sourceName        string
-	binaryOutputPath  string
-	textOutputPath    string
-	yamlOutputPath    string
-	jsonOutputPath    string
-	errorOutputPath   string
-	messageOutputPath string
-	resolveReferences bool
-	pluginCalls       []*pluginCall
-	extensionHandlers []compiler.ExtensionHandler
-	sourceFormat      int
-	timePlugins       bool
-	excludeSurface    bool
+	args                  []string
+	usage                 string
+	configPath            string
+	sourceName            string
+	sourceHash            string
+	errorOutputPath       string
+	messageOutputPath     string
+	traceOutputPath       string
+	headerCommentPath     string
+	headerComment         string
+	jsonOutStyle          string
+	resolveReferences     bool
+	smartReferences       bool
+	promotedAliases       int
+	fetchExternalExamples bool
+	fetchCacheDir         string
+	fetchRetries          int
+	outputCalls           []*outputCall
+	pluginCalls           []*pluginCall
+	extensionHandlers     []compiler.ExtensionHandler
+	sourceFormat          int
+	timePlugins           bool
+	excludeSurface        bool
+	simplifyUnions        bool
+	inferMissingSchemas   bool
+	codeSamples           []string
+	versionHeader         string
+	versionDescription    string
+	annotateSources       bool
+	dedupeComponents      bool
+	lintDuplicates        bool
+	lintBaselinePath      string
+	lintBaselineWrite     bool
+	lintBaselinePrune     bool
+	failOn                string
+	validateExamples      bool
+	grpcDescriptorsPath   string
+	dedupeThreshold       int
+	dedupeDescriptions    bool
+	anonymize             bool
+	mockServerAddress     string
+	markdownDocsDir       string
+	convertIn             string
+	convertOut            string
+	markdownTemplatesDir  string
+	markdownDepth         int
+	componentImports      []*componentImport
+	componentRenames      []*openapi_v3.ComponentRename
+	stripMarker           string
+	stripPattern          string
+	jsonErrors            bool
+	skipValidation        bool
+	keepPartial           bool
+	failFast              bool
+	verbose               bool
+	quiet                 bool
+	explicitOptions       map[string]bool
+	parseOptions          compiler.ParseOptions
+	compiler              *compiler.Compiler
+	trace                 *compiler.CompilationTrace
+	warnings              []*plugins.Message
+	outputStatuses        []*OutputStatus
 }
 
 // NewGnostic initializes a structure to store global application state.
 func NewGnostic(args []string) *Gnostic {
-	g := &Gnostic{args: args}
+	g := &Gnostic{args: args, fetchRetries: -1, dedupeThreshold: -1}
 	// Option fields initialize to their default values.
 	g.usage = `
 Usage: gnostic SOURCE [OPTIONS]
-  SOURCE is the filename or URL of an API description.
+       gnostic anonymize SOURCE [--output=PATH] [OPTIONS]
+       gnostic generate-mock-server --input SOURCE [--port PORT] [OPTIONS]
+       gnostic openapi2proto --input SOURCE [--output PATH] [--package NAME] [OPTIONS]
+       gnostic deps SOURCE [--schema=NAME] [OPTIONS]
+       gnostic stats SOURCE [--format=text|csv] [--output=PATH] [OPTIONS]
+       gnostic export-extensions --extension=NAME --input SOURCE [--output PATH] [OPTIONS]
+       gnostic generate-docs --format=markdown --input SOURCE [--output DIR] [OPTIONS]
+       gnostic prune-baseline SOURCE --baseline=PATH [OPTIONS]
+       gnostic validate-examples SOURCE [OPTIONS]
+       gnostic check-grpc SOURCE --descriptors=PATH [OPTIONS]
+       gnostic generate-client --language=go --input SOURCE [--output DIR] [--package NAME] [OPTIONS]
+       gnostic --output-html SOURCE [--output PATH] [OPTIONS]
+       gnostic --output-asyncapi SOURCE [--output PATH] [OPTIONS]
+       gnostic rename SOURCE --schema FROM=TO [--parameter FROM=TO]
+                      [--response FROM=TO] [--security-scheme FROM=TO] [OPTIONS]
+       gnostic import-raml --input SOURCE [--output PATH] [OPTIONS]
+       gnostic convert --from=2 --to=3 --in=DIR --out=DIR
+       gnostic check-coverage --input SOURCE --tests PATH [--output PATH] [OPTIONS]
+  SOURCE is the filename or URL of an API description, or - to read
+  a JSON or YAML description from stdin. UTF-8 byte order marks are
+  ignored and UTF-16 text is converted to UTF-8. Gzip-compressed
+  sources and referenced files are decompressed, and outputs written
+  to PATHs that end with .gz, e.g. spec.json.gz, are compressed.
+  RAML 1.0 API definitions, which start with #%RAML 1.0 and are usually
+  in files that end with .raml, are converted to OpenAPI v3 documents.
+  The anonymize command writes an anonymized copy of SOURCE (see
+  --anonymize) to PATH, in json if PATH ends with .json or .json.gz
+  and in yaml otherwise. Without --output, it writes yaml to stdout.
+  The generate-mock-server command serves mock responses for SOURCE
+  (see --mock-server) on PORT, by default 8080.
+  The openapi2proto command writes a proto3 description of SOURCE (see
+  --openapi2proto-out) to PATH, or to stdout without --output.
+  The deps command writes the operations that use the component schema
+  NAME, or all component schemas, to stdout (see --deps-out).
+  The stats command writes the counts of paths, operations, schemas,
+  extensions, and deprecated operations of SOURCE (see --stats-out) to
+  PATH, or to stdout without --output.
+  The export-extensions command writes the values of the extension NAME
+  (see --extensions-out) to PATH, or to stdout without --output.
+  The generate-docs command writes Markdown documentation of SOURCE to
+  DIR, by default docs (see --markdown-docs).
+  The prune-baseline command removes the entries of the lint baseline
+  PATH that no longer match findings of SOURCE (see --lint-baseline).
+  The validate-examples command reports the examples of SOURCE that
+  don't conform to their schemas (see --validate-examples) and fails
+  if there are any.
+  The check-grpc command reports the operations of SOURCE that don't
+  match the grpc-gateway bindings of the RPC methods in the descriptor
+  set PATH (see --check-grpc) and fails if there are any.
+  The generate-client command writes a Go client for SOURCE to
+  DIR/client.go, by default client/client.go, with the gnostic-go-client
+  plugin (see --PLUGIN-out). The package defaults to the name of DIR.
+  The rename command renames components of SOURCE and rewrites the
+  references to them (see --rename). Use --yaml-out or --json-out to
+  write the result.
+  The import-raml command converts the RAML 1.0 API definition SOURCE
+  to an OpenAPI v3 document and writes it to PATH, in json if PATH ends
+  with .json or .json.gz and in yaml otherwise. Without --output, it
+  writes yaml to stdout. RAML types become component schemas, the query
+  parameters and headers of traits become component parameters, and
+  resource types and traits with parameters are applied to the resources
+  and methods that use them. Parts that aren't converted are logged.
+  The convert command converts the Swagger 2.0 documents in the input
+  DIR and the files that they reference to OpenAPI v3 (see --convert-in)
+  and writes a summary of the converted files and their warnings.
+  The check-coverage command writes the operations of SOURCE that none
+  of the requests in the JSON file given with --tests exercised (see
+  --coverage-out) to PATH, or to stdout without --output.
+  With --output-html, gnostic writes an HTML reference of SOURCE (see
+  --html-out) to PATH, or to stdout without --output.
+  With --output-asyncapi, gnostic writes an AsyncAPI 2.0 version of SOURCE
+  (see --asyncapi-out) to PATH, or to stdout without --output.
 Options:
+  --config=PATH       Read options from the specified configuration file.
+                      If no file is given, a gnostic.yaml file in the
+                      directory of SOURCE is used if present. Options on
+                      the command line take precedence. Boolean options
+                      that a configuration file sets can be turned off
+                      with --OPTION=false.
   --pb-out=PATH       Write a binary proto to the specified location.
   --text-out=PATH     Write a text proto to the specified location.
   --json-out=PATH     Write a json API description to the specified location.
+  --json-out-style=pretty|compact|canonical
+                      Write json API descriptions indented (the default),
+                      without whitespace, or in the canonical form of
+                      RFC 8785 with sorted keys and normalized strings and
+                      numbers, which is suitable for signing and caching.
+                      Whole floats like 1.0 and -0.0 are written as 1 and
+                      0, and exponents are only used below 1e-6 or at or
+                      above 1e21. Also --json-out=json-style=STYLE:PATH.
   --yaml-out=PATH     Write a yaml API description to the specified location.
+  --ndjson-out=PATH   Write one JSON record per line to the specified location:
+                      a record with the title, version, and source hash of
+                      the document, then a record for each operation with
+                      its parameters, tags, deprecation status, and the
+                      fingerprints of its request and response schemas,
+                      sorted by path and method.
+  --openapi2proto-out=[package=NAME:]PATH
+                      Write a proto3 service definition of an OpenAPI
+                      document to the specified location. Each operation
+                      becomes a method with a google.api.http binding and
+                      request and response schemas become messages. The
+                      package defaults to the title of the API.
+  --deps-out=[schema=NAME:]PATH
+                      Write the operations that use a component schema of
+                      an OpenAPI v3 document, directly or through other
+                      components, to the specified location, one per
+                      line. Without a schema, write a line for each
+                      schema with the operations that use it.
+  --stats-out=[format=text|csv:]PATH
+                      Write the numbers of paths, operations, schemas,
+                      extensions, and deprecated operations of an OpenAPI
+                      document to the specified location. The csv format
+                      is a header row and a row that begins with SOURCE.
+  --extensions-out=extension=NAME:PATH
+                      Write the values of the extension NAME, wherever it
+                      occurs in the document, to the specified location
+                      as a JSON array of objects with the JSON Pointer of
+                      each occurrence and its value.
+  --html-out=PATH     Write a single-page HTML reference of an OpenAPI
+                      document to the specified location. The page has
+                      the same content as --markdown-docs and includes
+                      its style sheet and script, so it can be viewed
+                      offline.
+  --coverage-out=tests=PATH:PATH
+                      Write the operations of an OpenAPI document that
+                      weren't exercised by the requests of a test run to
+                      the specified location as a JSON array sorted by
+                      path. The tests file is a JSON array of objects with
+                      the method and path of each request. Paths can be
+                      concrete or templates of the document, with or
+                      without the base path of the API.
+  --fingerprints-out=PATH
+                      Write the fingerprints of the request and response
+                      schemas of the operations of an OpenAPI v3 document
+                      to the specified location as a JSON array sorted by
+                      path and method. Fingerprints are hashes of schemas
+                      with references to schema components inlined, so
+                      they don't change when keys are reordered or when
+                      schemas move to or from components.
+  --asyncapi-out=PATH Write an AsyncAPI 2.0 version of an OpenAPI
+                      document in yaml to the specified location. Paths
+                      become channels, requests become messages of publish
+                      operations, and responses with content become
+                      messages of subscribe operations. Parts that aren't
+                      converted, such as query parameters, are logged.
   --errors-out=PATH   Write compilation errors to the specified location.
+  --header-comment-file=PATH
+                      Prepend the contents of the specified file to text
+                      and yaml outputs as a comment. In json outputs of
+                      OpenAPI documents, it is written to an x-comment key
+                      of the root object.
+  --json-errors       Write compilation errors as JSON objects, one per line.
+  --skip-validation   Build the model of a trusted description without
+                      checking it for missing required fields, unexpected
+                      keys and other problems of semantic validation.
+                      Parts of the description that can't be compiled,
+                      such as values of the wrong type, are left out of
+                      the model.
+  --keep-partial      When some outputs or plugins fail, write the results
+                      of the ones that succeeded. By default, nothing is
+                      written unless all of them succeed.
+  --fail-fast         Stop at the first output or plugin that fails
+                      without running the ones that follow it.
+  --trace-out=PATH    Write a JSON trace of the files that were read and the
+                      time spent in each compilation phase.
   --messages-out=PATH Write messages generated by plugins to the specified
-                      location. Messages from all plugin invocations are
-                      written to a single common file.
+                      location. Messages from all plugin invocations and
+                      warnings from gnostic are written to a single common
+                      file. Without this option, warnings are printed on
+                      stderr.
   --PLUGIN-out=PATH   Run the plugin named gnostic-PLUGIN and write results
-                      to the specified location.
+                      to the specified location. Outputs that programs
+                      register with lib.RegisterOutput take precedence
+                      over plugins with the same name.
   --PLUGIN            Run the plugin named gnostic-PLUGIN but don't write any
                       results. Used for plugins that return messages only.
                       PLUGIN must not match any other gnostic option.
@@ -330,8 +568,161 @@ Options:
                       to process OpenAPI specification extensions.
   --resolve-refs      Explicitly resolve $ref references.
                       This could have problems with recursive definitions.
+  --resolve-refs=smart
+                      Resolve schema references without copying schemas
+                      (OpenAPI v3 only). Schemas that are used once are
+                      inlined, and schemas in other files and YAML aliases
+                      of schemas that are used more than once become
+                      components. A summary with the change in size of
+                      the document is printed on stderr.
+  --fetch-external-examples
+                      Fetch the externalValue targets of examples and store
+                      their contents in x-gnostic-external-value extensions
+                      (OpenAPI v3 only). Implies resolution of example
+                      references.
+  --fetch-cache-dir=DIR
+                      Store remote files in DIR with their ETag and
+                      Last-Modified headers and revalidate them with
+                      conditional requests instead of downloading them
+                      again.
+  --fetch-retries=N   Retry requests for remote files up to N times after
+                      network errors and 5xx responses, with exponential
+                      backoff. The default is 0.
   --time-plugins      Report plugin runtimes.
+  --verbose           Report extension handler cache statistics and log
+                      the files that are read and fetched, extension
+                      handler calls, and plugin invocations.
+  --quiet             Don't log warnings.
   --no-surface        Exclude surface model from calls to plugins.
+  --simplify-unions   Collapse oneOf/anyOf unions of scalar types into
+                      single schemas (OpenAPI v3 only). Original variants
+                      are recorded in x-gnostic-simplified extensions.
+  --infer-missing-schemas
+                      Infer the schemas of media types that have examples
+                      but no schemas (OpenAPI v3 only). Inferred schemas
+                      are marked with x-gnostic-inferred extensions.
+  --annotate-sources  Record the source and original JSON pointer of each
+                      component in an x-gnostic-source extension
+                      (OpenAPI v3 only). Existing annotations are kept.
+                      Printed warnings and plugin messages about annotated
+                      components include their original locations.
+  --code-samples=LANGUAGES
+                      Add sample requests in a comma-separated list of
+                      languages (curl, go) to each operation in an
+                      x-codeSamples extension (OpenAPI v3 only).
+                      Operations that have code samples are skipped.
+  --dedupe-components Replace inline parameters, responses and headers that
+                      are structurally identical and appear more than
+                      --dedupe-threshold times with references to
+                      components named after them (OpenAPI v3 only).
+  --lint-duplicates   Warn about the inline values that --dedupe-components
+                      would replace (OpenAPI v3 only).
+  --validate-examples Report examples of parameters, request bodies and
+                      responses that don't conform to their schemas as
+                      errors at the JSON Pointer of the value that fails
+                      (OpenAPI v3 only).
+  --check-grpc=PATH   Report operations that don't match the google.api.http
+                      bindings of the RPC methods in the FileDescriptorSet
+                      PATH, as written by protoc --include_imports
+                      --descriptor_set_out, as errors. Operations are paired
+                      with methods by x-grpc-method extensions or by their
+                      operationIds, and their path templates, path and
+                      query parameters, and request bodies are compared
+                      (OpenAPI v3 only).
+  --lint-baseline=PATH
+                      Read known findings, the warnings of gnostic and the
+                      messages of plugins, from the JSON file PATH. Findings
+                      in the baseline are reported as baselined and don't
+                      fail --fail-on. Findings are identified by their rule,
+                      their path with list positions replaced by *, and a
+                      hash of their message.
+  --lint-baseline-write
+                      Write the current findings to the --lint-baseline file.
+  --lint-baseline-prune
+                      Remove the entries of the --lint-baseline file that
+                      match no current finding.
+  --fail-on=LEVEL     Fail if there are findings at LEVEL (info, warning,
+                      error or fatal) or above that aren't in the lint
+                      baseline.
+  --dedupe-threshold=N
+                      Set the number of identical inline copies that are
+                      allowed before they are duplicates. The default is 2.
+  --dedupe-ignore-descriptions
+                      Consider inline copies that differ only in their
+                      descriptions as identical.
+  --version-header=NAME
+                      Add a required string header parameter with the
+                      specified name, such as X-API-Version, to each
+                      operation that doesn't have it (OpenAPI v3 only).
+  --version-header-description=TEMPLATE
+                      Set the description of the version header parameter.
+                      The template can refer to the {{.Name}} of the header
+                      and the {{.Title}} and {{.Version}} of the API. The
+                      default is "The version of the API."
+  --import components=PATH[,prefix=PREFIX]
+                      Add all components of the schema library at PATH, an
+                      OpenAPI v3 description or a file with only a
+                      components object, to the document (OpenAPI v3
+                      only). With a prefix, imported components are
+                      renamed to PREFIXName and their references are
+                      updated. Components that are already defined are
+                      errors, unless there is no prefix and they are
+                      identical. Can be repeated.
+  --rename SECTION/FROM=TO
+                      Rename the component FROM of a components section,
+                      such as schemas, parameters, responses, or
+                      securitySchemes, to TO and rewrite the references
+                      to it, discriminator mappings, link operation
+                      references, and security requirements (OpenAPI v3
+                      only). Can be repeated; renames are applied in
+                      order, so renames that chain, like A=B and B=C,
+                      are errors unless the later one comes first.
+  --strip-extension=NAME
+                      Remove path items, operations, parameters, responses,
+                      schemas, and properties that carry the extension NAME
+                      with a truthy value, such as x-internal: true, and
+                      the components that were only used by them
+                      (OpenAPI v3 only). Schemas that are still referenced
+                      are reported as errors.
+  --strip-extensions-matching=REGEXP
+                      Remove specification extensions whose names match
+                      REGEXP (OpenAPI v3 only).
+  --anonymize         Replace descriptions, examples, string defaults, and
+                      the values of specification extensions with
+                      placeholders before writing outputs, so that
+                      documents can be shared (OpenAPI v3 only).
+  --markdown-docs=DIR Write Markdown documentation of an OpenAPI document
+                      to the specified directory: a README.md file, a
+                      file for each tag (or for each path of operations
+                      without tags) with tables of the parameters,
+                      request bodies, and responses of its operations,
+                      their properties and examples, and a schemas.md
+                      file with the schema components.
+  --convert-in=DIR    Convert the Swagger 2.0 documents in the specified
+                      directory and its subdirectories to OpenAPI v3
+                      instead of compiling SOURCE. Documents are files
+                      with a swagger field that no other file references.
+                      The files that they reference are partials and are
+                      converted too: partials with definitions,
+                      parameters, responses, or paths become files with
+                      components and paths, and other partials are
+                      converted in place. References to other files are
+                      rewritten to the converted locations.
+  --convert-out=DIR   Write the files of --convert-in to the same paths
+                      in the specified directory.
+  --markdown-templates=DIR
+                      Replace the templates of --markdown-docs with the
+                      files NAME.tmpl in DIR, e.g. index.tmpl, tag.tmpl,
+                      or schemas.tmpl (Go text/template syntax).
+  --markdown-depth=N  List nested properties in the tables of
+                      --markdown-docs up to N levels deep (default 3).
+  --mock-server=ADDRESS
+                      After writing outputs, serve mock responses for the
+                      operations of the document on ADDRESS, e.g. :8080,
+                      until gnostic is stopped. Responses use the examples
+                      of the first 2xx or default response of each
+                      operation, or values generated from its schemas.
+                      OpenAPI v2 documents are converted to v3 first.
   --help              Print usage information and exit.
 `
 	// Initialize internal structures.
@@ -345,6 +736,57 @@ func (g *Gnostic) Usage() string {
 	return g.usage
 }
 
+// booleanOptions maps the names of boolean options to their fields.
+func (g *Gnostic) booleanOptions() map[string]*bool {
+	return map[string]*bool{
+		"resolve-refs":               &g.resolveReferences,
+		"fetch-external-examples":    &g.fetchExternalExamples,
+		"verbose":                    &g.verbose,
+		"quiet":                      &g.quiet,
+		"time-plugins":               &g.timePlugins,
+		"no-surface":                 &g.excludeSurface,
+		"simplify-unions":            &g.simplifyUnions,
+		"infer-missing-schemas":      &g.inferMissingSchemas,
+		"json-errors":                &g.jsonErrors,
+		"skip-validation":            &g.skipValidation,
+		"keep-partial":               &g.keepPartial,
+		"fail-fast":                  &g.failFast,
+		"annotate-sources":           &g.annotateSources,
+		"dedupe-components":          &g.dedupeComponents,
+		"lint-duplicates":            &g.lintDuplicates,
+		"lint-baseline-write":        &g.lintBaselineWrite,
+		"lint-baseline-prune":        &g.lintBaselinePrune,
+		"validate-examples":          &g.validateExamples,
+		"dedupe-ignore-descriptions": &g.dedupeDescriptions,
+		"anonymize":                  &g.anonymize,
+	}
+}
+
+// setBooleanOption sets a boolean option that is written as "--NAME",
+// "--NAME=true" or "--NAME=false", and records that it was set on the
+// command line, so that configuration files don't override it. It
+// returns false if the argument isn't a boolean option.
+func (g *Gnostic) setBooleanOption(arg string) (bool, error) {
+	name, value := strings.TrimPrefix(arg, "--"), "true"
+	if i := strings.Index(name, "="); i >= 0 {
+		name, value = name[:i], name[i+1:]
+	}
+	option, ok := g.booleanOptions()[name]
+	if !ok || !strings.HasPrefix(arg, "--") {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return true, NewUsageError(fmt.Sprintf("invalid value for --%s: %s", name, value))
+	}
+	*option = b
+	if g.explicitOptions == nil {
+		g.explicitOptions = make(map[string]bool)
+	}
+	g.explicitOptions[name] = true
+	return true, nil
+}
+
 // Parse command-line options.
 func (g *Gnostic) readOptions() error {
 	// plugin processing matches patterns of the form "--PLUGIN-out=PATH" and "--PLUGIN_out=PATH"
@@ -353,28 +795,98 @@ func (g *Gnostic) readOptions() error {
 	// extension processing matches patterns of the form "--x-EXTENSION"
 	extensionRegex := regexp.MustCompile("--x-(.+)")
 
-	for i, arg := range g.args {
+	args, err := expandCommand(g.args)
+	if err != nil {
+		return err
+	}
+	args = joinOptionValues(args, "--import", "--rename")
+	for i, arg := range args {
 		if i == 0 {
 			continue // skip the tool name
 		}
 		var m [][]byte
-		if m = pluginRegex.FindSubmatch([]byte(arg)); m != nil {
+		if strings.HasPrefix(arg, "--config=") {
+			g.configPath = strings.TrimPrefix(arg, "--config=")
+		} else if strings.HasPrefix(arg, "--strip-extension=") {
+			g.stripMarker = strings.TrimPrefix(arg, "--strip-extension=")
+		} else if strings.HasPrefix(arg, "--strip-extensions-matching=") {
+			g.stripPattern = strings.TrimPrefix(arg, "--strip-extensions-matching=")
+		} else if strings.HasPrefix(arg, "--code-samples=") {
+			g.codeSamples = strings.Split(strings.TrimPrefix(arg, "--code-samples="), ",")
+		} else if strings.HasPrefix(arg, "--version-header=") {
+			g.versionHeader = strings.TrimPrefix(arg, "--version-header=")
+		} else if strings.HasPrefix(arg, "--version-header-description=") {
+			g.versionDescription = strings.TrimPrefix(arg, "--version-header-description=")
+		} else if strings.HasPrefix(arg, "--import=") {
+			componentImport, err := parseComponentImport(strings.TrimPrefix(arg, "--import="))
+			if err != nil {
+				return err
+			}
+			g.componentImports = append(g.componentImports, componentImport)
+		} else if strings.HasPrefix(arg, "--rename=") {
+			rename, err := parseComponentRename(strings.TrimPrefix(arg, "--rename="))
+			if err != nil {
+				return err
+			}
+			g.componentRenames = append(g.componentRenames, rename)
+		} else if strings.HasPrefix(arg, "--markdown-docs=") {
+			g.markdownDocsDir = strings.TrimPrefix(arg, "--markdown-docs=")
+		} else if strings.HasPrefix(arg, "--convert-in=") {
+			g.convertIn = strings.TrimPrefix(arg, "--convert-in=")
+		} else if strings.HasPrefix(arg, "--convert-out=") {
+			g.convertOut = strings.TrimPrefix(arg, "--convert-out=")
+		} else if strings.HasPrefix(arg, "--markdown-templates=") {
+			g.markdownTemplatesDir = strings.TrimPrefix(arg, "--markdown-templates=")
+		} else if strings.HasPrefix(arg, "--markdown-depth=") {
+			value := strings.TrimPrefix(arg, "--markdown-depth=")
+			depth, err := strconv.Atoi(value)
+			if err != nil || depth < 1 {
+				return NewUsageError(fmt.Sprintf("invalid value for --markdown-depth: %s", value))
+			}
+			g.markdownDepth = depth
+		} else if strings.HasPrefix(arg, "--mock-server=") {
+			g.mockServerAddress = strings.TrimPrefix(arg, "--mock-server=")
+		} else if strings.HasPrefix(arg, "--fetch-cache-dir=") {
+			g.fetchCacheDir = strings.TrimPrefix(arg, "--fetch-cache-dir=")
+		} else if strings.HasPrefix(arg, "--fetch-retries=") {
+			value := strings.TrimPrefix(arg, "--fetch-retries=")
+			retries, err := strconv.Atoi(value)
+			if err != nil || retries < 0 {
+				return NewUsageError(fmt.Sprintf("invalid value for --fetch-retries: %s", value))
+			}
+			g.fetchRetries = retries
+		} else if strings.HasPrefix(arg, "--dedupe-threshold=") {
+			value := strings.TrimPrefix(arg, "--dedupe-threshold=")
+			threshold, err := strconv.Atoi(value)
+			if err != nil || threshold < 1 {
+				return NewUsageError(fmt.Sprintf("invalid value for --dedupe-threshold: %s", value))
+			}
+			g.dedupeThreshold = threshold
+		} else if strings.HasPrefix(arg, "--lint-baseline=") {
+			g.lintBaselinePath = strings.TrimPrefix(arg, "--lint-baseline=")
+		} else if strings.HasPrefix(arg, "--check-grpc=") {
+			g.grpcDescriptorsPath = strings.TrimPrefix(arg, "--check-grpc=")
+		} else if strings.HasPrefix(arg, "--fail-on=") {
+			g.failOn = strings.TrimPrefix(arg, "--fail-on=")
+		} else if strings.HasPrefix(arg, "--json-out-style=") {
+			g.jsonOutStyle = strings.TrimPrefix(arg, "--json-out-style=")
+		} else if strings.HasPrefix(arg, "--header-comment-file=") {
+			g.headerCommentPath = strings.TrimPrefix(arg, "--header-comment-file=")
+		} else if m = pluginRegex.FindSubmatch([]byte(arg)); m != nil {
 			pluginName := string(m[1])
 			invocation := string(m[2])
 			switch pluginName {
-			case "pb":
-				g.binaryOutputPath = invocation
-			case "text":
-				g.textOutputPath = invocation
-			case "json":
-				g.jsonOutputPath = invocation
-			case "yaml":
-				g.yamlOutputPath = invocation
 			case "errors":
 				g.errorOutputPath = invocation
 			case "messages":
 				g.messageOutputPath = invocation
+			case "trace":
+				g.traceOutputPath = invocation
 			default:
+				if lookupOutput(pluginName) != nil {
+					g.setOutputCall(pluginName, invocation)
+					break
+				}
 				p := &pluginCall{Name: pluginName, Invocation: invocation}
 				g.pluginCalls = append(g.pluginCalls, p)
 			}
@@ -382,18 +894,19 @@ func (g *Gnostic) readOptions() error {
 			extensionName := string(m[1])
 			extensionHandler := compiler.ExtensionHandler{Name: extensionPrefix + extensionName}
 			g.extensionHandlers = append(g.extensionHandlers, extensionHandler)
-		} else if arg == "--resolve-refs" {
+		} else if arg == "--resolve-refs=smart" {
 			g.resolveReferences = true
-		} else if arg == "--time-plugins" {
-			g.timePlugins = true
-		} else if arg == "--no-surface" {
-			g.excludeSurface = true
+			g.smartReferences = true
+		} else if ok, err := g.setBooleanOption(arg); ok || err != nil {
+			if err != nil {
+				return err
+			}
 		} else if len(arg) > 2 && arg[0] == '-' && arg[1] == '-' {
 			// try letting the option specify a plugin with no output files (or unwanted output files)
 			// this is useful for calling plugins like linters that only return messages
 			p := &pluginCall{Name: arg[2:len(arg)], Invocation: "!"}
 			g.pluginCalls = append(g.pluginCalls, p)
-		} else if arg[0] == '-' {
+		} else if arg[0] == '-' && arg != "-" {
 			return NewUsageError(fmt.Sprintf("unknown option: %s", arg))
 		} else {
 			g.sourceName = arg
@@ -402,17 +915,413 @@ func (g *Gnostic) readOptions() error {
 	return nil
 }
 
+// joinOptionValues rewrites options that are followed by their values,
+// such as "--import VALUE", as "--import=VALUE".
+func joinOptionValues(args []string, options ...string) []string {
+	joined := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		for _, option := range options {
+			if arg == option && i+1 < len(args) {
+				arg = option + "=" + args[i+1]
+				i++
+				break
+			}
+		}
+		joined = append(joined, arg)
+	}
+	return joined
+}
+
+// A componentImport names a schema library whose components are added
+// to the document with --import.
+type componentImport struct {
+	Path   string
+	Prefix string
+}
+
+// parseComponentImport reads the value of an --import option, such as
+// "components=common.yaml,prefix=Common".
+func parseComponentImport(value string) (*componentImport, error) {
+	c := &componentImport{}
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		switch {
+		case len(kv) == 2 && kv[0] == "components":
+			c.Path = kv[1]
+		case len(kv) == 2 && kv[0] == "prefix":
+			c.Prefix = kv[1]
+		default:
+			return nil, NewUsageError(fmt.Sprintf("invalid value for --import: %s", value))
+		}
+	}
+	if c.Path == "" {
+		return nil, NewUsageError(fmt.Sprintf("missing components in --import: %s", value))
+	}
+	return c, nil
+}
+
+// parseComponentRename reads the value of a --rename option, such as
+// "schemas/Pet=Animal".
+func parseComponentRename(value string) (*openapi_v3.ComponentRename, error) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return nil, NewUsageError(fmt.Sprintf("invalid value for --rename: %s", value))
+	}
+	names := strings.SplitN(parts[1], "=", 2)
+	if parts[0] == "" || len(names) != 2 || names[0] == "" || names[1] == "" {
+		return nil, NewUsageError(fmt.Sprintf("invalid value for --rename: %s", value))
+	}
+	return &openapi_v3.ComponentRename{Section: parts[0], From: names[0], To: names[1]}, nil
+}
+
+// Add the components of schema libraries to a document.
+func (g *Gnostic) importComponents(message proto.Message) error {
+	document, ok := message.(*openapi_v3.Document)
+	if !ok {
+		return errors.New("--import is only supported for OpenAPI v3 documents")
+	}
+	for _, c := range g.componentImports {
+		bytes, err := compiler.ReadBytesForFile(c.Path)
+		if err != nil {
+			return err
+		}
+		components, err := openapi_v3.ParseComponents(bytes)
+		if err != nil {
+			return fmt.Errorf("%s: %s", c.Path, err.Error())
+		}
+		if _, err = openapi_v3.ImportComponents(document, components, c.Prefix); err != nil {
+			return fmt.Errorf("%s: %s", c.Path, err.Error())
+		}
+	}
+	return nil
+}
+
+// expandCommand rewrites the arguments of commands as options.
+// "gnostic anonymize SOURCE --output=PATH" is equivalent to
+// "gnostic SOURCE --anonymize --yaml-out=PATH" (--json-out for json files)
+// "gnostic generate-mock-server --input SOURCE --port PORT" is
+// equivalent to "gnostic SOURCE --mock-server=:PORT" and
+// "gnostic openapi2proto --input SOURCE --output PATH --package NAME" is
+// equivalent to "gnostic SOURCE --openapi2proto-out=package=NAME:PATH" and
+// "gnostic deps SOURCE --schema=NAME" is equivalent to
+// "gnostic SOURCE --deps-out=schema=NAME:-" and
+// "gnostic stats SOURCE --format=csv --output=PATH" is equivalent to
+// "gnostic SOURCE --stats-out=format=csv:PATH" and
+// "gnostic export-extensions --extension=NAME --input SOURCE --output PATH"
+// is equivalent to "gnostic SOURCE --extensions-out=extension=NAME:PATH" and
+// "gnostic generate-docs --format=markdown --input SOURCE --output DIR"
+// is equivalent to "gnostic SOURCE --markdown-docs=DIR" and
+// "gnostic prune-baseline SOURCE --baseline=PATH" is equivalent to
+// "gnostic SOURCE --lint-baseline=PATH --lint-baseline-prune" and
+// "gnostic validate-examples SOURCE" is equivalent to
+// "gnostic SOURCE --validate-examples --fail-on=error" and
+// "gnostic check-grpc SOURCE --descriptors=PATH" is equivalent to
+// "gnostic SOURCE --check-grpc=PATH --fail-on=error" and
+// "gnostic generate-client --language=go --input SOURCE --output DIR" is
+// equivalent to "gnostic SOURCE --go-client-out=DIR" and
+// "gnostic rename SOURCE --schema FROM=TO" is equivalent to
+// "gnostic SOURCE --rename=schemas/FROM=TO" and
+// "gnostic --output-html SOURCE --output PATH" is equivalent to
+// "gnostic SOURCE --html-out=PATH" and
+// "gnostic --output-asyncapi SOURCE --output PATH" is equivalent to
+// "gnostic SOURCE --asyncapi-out=PATH" and
+// "gnostic import-raml --input SOURCE --output PATH" is equivalent to
+// "gnostic SOURCE --yaml-out=PATH" (--json-out for json files) and
+// "gnostic convert --from=2 --to=3 --in=DIR --out=DIR" is equivalent to
+// "gnostic --convert-in=DIR --convert-out=DIR" and
+// "gnostic check-coverage --input SOURCE --tests PATH --output PATH" is
+// equivalent to "gnostic SOURCE --coverage-out=tests=PATH:PATH".
+func expandCommand(args []string) ([]string, error) {
+	if len(args) < 2 {
+		return args, nil
+	}
+	if args[1] == "rename" {
+		return expandRenameCommand(args)
+	}
+	if command, ok := subcommands[args[1]]; ok {
+		return expandSubcommand(args, command)
+	}
+	return args, nil
+}
+
+// subcommand describes a command that expandCommand rewrites as options.
+type subcommand struct {
+	// options maps the names of the options of the command to their
+	// default values. The value of an "input" option is the source.
+	options map[string]string
+	// expand returns the options that replace the options of the command.
+	expand func(values map[string]string) ([]string, error)
+}
+
+// subcommands are the commands that expandCommand rewrites with
+// expandSubcommand. The options of rename can be repeated, so it is
+// expanded separately.
+var subcommands = map[string]*subcommand{
+	"anonymize": {
+		options: map[string]string{"output": "-"},
+		expand: func(values map[string]string) ([]string, error) {
+			return []string{"--anonymize", documentOutputOption(values["output"])}, nil
+		},
+	},
+	"generate-mock-server": {
+		options: map[string]string{"input": "", "port": "8080"},
+		expand: func(values map[string]string) ([]string, error) {
+			port := values["port"]
+			if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+				return nil, NewUsageError("invalid port: " + port)
+			}
+			return []string{"--mock-server=:" + port}, nil
+		},
+	},
+	"openapi2proto": {
+		options: map[string]string{"input": "", "output": "-", "package": ""},
+		expand: func(values map[string]string) ([]string, error) {
+			return []string{"--openapi2proto-out=" + packageOutput(values)}, nil
+		},
+	},
+	"deps": {
+		options: map[string]string{"schema": ""},
+		expand: func(values map[string]string) ([]string, error) {
+			if values["schema"] == "" {
+				return []string{"--deps-out=-"}, nil
+			}
+			return []string{"--deps-out=schema=" + values["schema"] + ":-"}, nil
+		},
+	},
+	"stats": {
+		options: map[string]string{"output": "-", "format": "text"},
+		expand: func(values map[string]string) ([]string, error) {
+			format := values["format"]
+			if format != "text" && format != "csv" {
+				return nil, NewUsageError("unsupported stats format: " + format)
+			}
+			return []string{"--stats-out=format=" + format + ":" + values["output"]}, nil
+		},
+	},
+	"export-extensions": {
+		options: map[string]string{"input": "", "output": "-", "extension": ""},
+		expand: func(values map[string]string) ([]string, error) {
+			if values["extension"] == "" {
+				return nil, NewUsageError("missing --extension")
+			}
+			return []string{"--extensions-out=extension=" + values["extension"] + ":" + values["output"]}, nil
+		},
+	},
+	"generate-docs": {
+		options: map[string]string{"input": "", "output": "docs", "format": "markdown"},
+		expand: func(values map[string]string) ([]string, error) {
+			if values["format"] != "markdown" {
+				return nil, NewUsageError("unsupported documentation format: " + values["format"])
+			}
+			return []string{"--markdown-docs=" + values["output"]}, nil
+		},
+	},
+	"prune-baseline": {
+		options: map[string]string{"baseline": ""},
+		expand: func(values map[string]string) ([]string, error) {
+			if values["baseline"] == "" {
+				return nil, NewUsageError("missing --baseline")
+			}
+			return []string{"--lint-baseline=" + values["baseline"], "--lint-baseline-prune"}, nil
+		},
+	},
+	"validate-examples": {
+		expand: func(values map[string]string) ([]string, error) {
+			return []string{"--validate-examples", "--fail-on=error"}, nil
+		},
+	},
+	"check-grpc": {
+		options: map[string]string{"descriptors": ""},
+		expand: func(values map[string]string) ([]string, error) {
+			if values["descriptors"] == "" {
+				return nil, NewUsageError("missing --descriptors")
+			}
+			return []string{"--check-grpc=" + values["descriptors"], "--fail-on=error"}, nil
+		},
+	},
+	"generate-client": {
+		options: map[string]string{"input": "", "output": "client", "language": "go", "package": ""},
+		expand: func(values map[string]string) ([]string, error) {
+			if values["language"] != "go" {
+				return nil, NewUsageError("unsupported client language: " + values["language"])
+			}
+			return []string{"--go-client-out=" + packageOutput(values)}, nil
+		},
+	},
+	"--output-html": {
+		options: map[string]string{"output": "-"},
+		expand: func(values map[string]string) ([]string, error) {
+			return []string{"--html-out=" + values["output"]}, nil
+		},
+	},
+	"--output-asyncapi": {
+		options: map[string]string{"output": "-"},
+		expand: func(values map[string]string) ([]string, error) {
+			return []string{"--asyncapi-out=" + values["output"]}, nil
+		},
+	},
+	"import-raml": {
+		options: map[string]string{"input": "", "output": "-"},
+		expand: func(values map[string]string) ([]string, error) {
+			return []string{documentOutputOption(values["output"])}, nil
+		},
+	},
+	"convert": {
+		options: map[string]string{"from": "", "to": "", "in": "", "out": ""},
+		expand: func(values map[string]string) ([]string, error) {
+			if values["from"] != "2" || values["to"] != "3" {
+				return nil, NewUsageError("only --from=2 --to=3 conversions are supported")
+			}
+			for _, name := range []string{"in", "out"} {
+				if values[name] == "" {
+					return nil, NewUsageError("missing --" + name)
+				}
+			}
+			return []string{"--convert-in=" + values["in"], "--convert-out=" + values["out"]}, nil
+		},
+	},
+	"check-coverage": {
+		options: map[string]string{"input": "", "output": "-", "tests": ""},
+		expand: func(values map[string]string) ([]string, error) {
+			if values["tests"] == "" {
+				return nil, NewUsageError("missing --tests")
+			}
+			return []string{"--coverage-out=tests=" + values["tests"] + ":" + values["output"]}, nil
+		},
+	},
+}
+
+// expandSubcommand rewrites the arguments of a subcommand. Its options
+// can be written as "--NAME VALUE" or "--NAME=VALUE", and other arguments
+// are kept as they are.
+func expandSubcommand(args []string, command *subcommand) ([]string, error) {
+	expanded := []string{args[0]}
+	values := make(map[string]string)
+	for name, value := range command.options {
+		values[name] = value
+	}
+	for i := 2; i < len(args); i++ {
+		arg := args[i]
+		option, value := arg, ""
+		if j := strings.Index(arg, "="); j > 0 {
+			option, value = arg[:j], arg[j+1:]
+		}
+		name := strings.TrimPrefix(option, "--")
+		if _, ok := command.options[name]; !ok || name == option {
+			expanded = append(expanded, arg)
+			continue
+		}
+		if option == arg {
+			if i+1 == len(args) {
+				return nil, NewUsageError("missing value for " + arg)
+			}
+			value = args[i+1]
+			i++
+		}
+		if name == "input" {
+			expanded = append(expanded, value)
+		} else {
+			values[name] = value
+		}
+	}
+	options, err := command.expand(values)
+	if err != nil {
+		return nil, err
+	}
+	return append(expanded, options...), nil
+}
+
+// documentOutputOption returns the option that writes a document to a
+// path, as json if the path has a .json extension and as yaml otherwise.
+func documentOutputOption(output string) string {
+	if strings.ToLower(filepath.Ext(trimGzipExtension(output))) == ".json" {
+		return "--json-out=" + output
+	}
+	return "--yaml-out=" + output
+}
+
+// packageOutput returns the output path of a subcommand, with the
+// package name of a --package option if there is one.
+func packageOutput(values map[string]string) string {
+	if values["package"] != "" {
+		return "package=" + values["package"] + ":" + values["output"]
+	}
+	return values["output"]
+}
+
+// renameSections are the components sections of the options of the
+// rename command.
+var renameSections = map[string]string{
+	"--schema":          "schemas",
+	"--parameter":       "parameters",
+	"--response":        "responses",
+	"--security-scheme": "securitySchemes",
+}
+
+func expandRenameCommand(args []string) ([]string, error) {
+	expanded := []string{args[0]}
+	renames := 0
+	for i := 2; i < len(args); i++ {
+		arg := args[i]
+		option, value := arg, ""
+		if j := strings.Index(arg, "="); j > 0 {
+			option, value = arg[:j], arg[j+1:]
+		}
+		section, ok := renameSections[option]
+		switch {
+		case !ok:
+			expanded = append(expanded, arg)
+			continue
+		case option == arg && i+1 < len(args):
+			value = args[i+1]
+			i++
+		case option == arg:
+			return nil, NewUsageError("missing value for " + arg)
+		}
+		expanded = append(expanded, "--rename="+section+"/"+value)
+		renames++
+	}
+	if renames == 0 {
+		return nil, NewUsageError("missing --schema, --parameter, --response or --security-scheme")
+	}
+	return expanded, nil
+}
+
 // Validate command-line options.
 func (g *Gnostic) validateOptions() error {
-	if g.binaryOutputPath == "" &&
-		g.textOutputPath == "" &&
-		g.yamlOutputPath == "" &&
-		g.jsonOutputPath == "" &&
+	if g.convertIn != "" || g.convertOut != "" {
+		if g.convertIn == "" || g.convertOut == "" {
+			return NewUsageError("--convert-in and --convert-out must be used together")
+		}
+		if g.sourceName != "" {
+			return NewUsageError("--convert-in can't be used with SOURCE")
+		}
+		return nil
+	}
+	if len(g.outputCalls) == 0 &&
+		g.mockServerAddress == "" &&
+		g.markdownDocsDir == "" &&
 		g.errorOutputPath == "" &&
 		g.messageOutputPath == "" &&
+		g.lintBaselinePath == "" &&
+		g.failOn == "" &&
+		!g.validateExamples &&
+		g.grpcDescriptorsPath == "" &&
 		len(g.pluginCalls) == 0 {
 		return NewUsageError("missing output directives")
 	}
+	if (g.lintBaselineWrite || g.lintBaselinePrune) && g.lintBaselinePath == "" {
+		return NewUsageError("--lint-baseline-write and --lint-baseline-prune require --lint-baseline")
+	}
+	if g.failOn != "" {
+		if _, err := parseFailOnLevel(g.failOn); err != nil {
+			return err
+		}
+	}
+	if _, err := jsonwriter.ParseStyle(g.jsonOutStyle); err != nil {
+		return NewUsageError(fmt.Sprintf("invalid value for --json-out-style: %s", g.jsonOutStyle))
+	}
 	if g.sourceName == "" {
 		return NewUsageError("no input specified")
 	}
@@ -425,39 +1334,63 @@ func (g *Gnostic) validateOptions() error {
 
 // Generate an error message to be written to stderr or a file.
 func (g *Gnostic) errorBytes(err error) []byte {
+	if g.jsonErrors {
+		var buffer bytes.Buffer
+		for _, message := range compiler.MessagesForError(err, g.sourceName) {
+			buffer.WriteString(message.ToJSON() + "\n")
+		}
+		return buffer.Bytes()
+	}
 	return []byte("Errors reading " + g.sourceName + "\n" + err.Error())
 }
 
 // Read an OpenAPI description from YAML or JSON.
+// readOpenAPIText reads an API description from text that was already
+// decompressed.
 func (g *Gnostic) readOpenAPIText(bytes []byte) (message proto.Message, err error) {
-	info, err := compiler.ReadInfoFromBytes(g.sourceName, bytes)
+	endPhase := g.trace.StartPhase("parse")
+	info, err := g.sourceCompiler().ParseText(g.sourceName, bytes)
+	endPhase()
 	if err != nil {
 		return nil, err
 	}
 	// Determine the OpenAPI version.
 	g.sourceFormat = getOpenAPIVersionFromInfo(info)
 	if g.sourceFormat == SourceFormatUnknown {
-		return nil, errors.New("unable to identify OpenAPI version")
+		return nil, compiler.NewErrorWithCode(nil, compiler.CodeUnknownVersion, "unable to identify OpenAPI version")
+	}
+	// Aliases of schemas are replaced by references before the remaining
+	// aliases are expanded, so that schemas aren't copied.
+	if g.smartReferences && g.sourceFormat == SourceFormatOpenAPI3 {
+		g.promotedAliases = openapi_v3.PromoteSchemaAliases(info.Content[0])
 	}
+	compiler.ExpandAliases(info)
 	// Compile to the proto model.
+	defer g.trace.StartPhase("model")()
+	root := info.Content[0]
+	context := g.rootContext(root)
+	defer compiler.ReleaseUserData(context)
+	if g.verbose && len(g.extensionHandlers) > 0 {
+		defer func() {
+			stats := compiler.GetExtensionCacheStats(context)
+			fmt.Fprintf(os.Stderr, "> extension handler calls: %d, cache hits: %d\n", stats.Misses, stats.Hits)
+		}()
+	}
 	if g.sourceFormat == SourceFormatOpenAPI2 {
-		root := info.Content[0]
-		document, err := openapi_v2.NewDocument(root, compiler.NewContextWithExtensions("$root", root, nil, &g.extensionHandlers))
-		if err != nil {
+		document, err := openapi_v2.NewDocument(root, context)
+		if err != nil && !g.skipValidation {
 			return nil, err
 		}
 		message = document
 	} else if g.sourceFormat == SourceFormatOpenAPI3 {
-		root := info.Content[0]
-		document, err := openapi_v3.NewDocument(root, compiler.NewContextWithExtensions("$root", root, nil, &g.extensionHandlers))
-		if err != nil {
+		document, err := openapi_v3.NewDocument(root, context)
+		if err != nil && !g.skipValidation {
 			return nil, err
 		}
 		message = document
 	} else {
-		root := info.Content[0]
-		document, err := discovery_v1.NewDocument(root, compiler.NewContextWithExtensions("$root", root, nil, &g.extensionHandlers))
-		if err != nil {
+		document, err := discovery_v1.NewDocument(root, context)
+		if err != nil && !g.skipValidation {
 			return nil, err
 		}
 		message = document
@@ -466,9 +1399,27 @@ func (g *Gnostic) readOpenAPIText(bytes []byte) (message proto.Message, err erro
 }
 
 func (g *Gnostic) ReadOpenAPIText(bytes []byte) (message proto.Message, err error) {
+	bytes, err = g.sourceCompiler().Decompress(g.sourceName, bytes)
+	if err != nil {
+		return nil, err
+	}
 	return g.readOpenAPIText(bytes)
 }
 
+// Read a RAML 1.0 API definition and convert it to OpenAPI v3.
+func (g *Gnostic) readRAML(bytes []byte) (proto.Message, error) {
+	defer g.trace.StartPhase("model")()
+	document, warnings, err := conversions.OpenAPIv3FromRAML(bytes)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range warnings {
+		g.logger().Warnf("%s", warning)
+	}
+	g.sourceFormat = SourceFormatOpenAPI3
+	return document, nil
+}
+
 // Read an OpenAPI binary file.
 func (g *Gnostic) readOpenAPIBinary(data []byte) (message proto.Message, err error) {
 	// try to read an OpenAPI v3 document
@@ -495,139 +1446,331 @@ func (g *Gnostic) readOpenAPIBinary(data []byte) (message proto.Message, err err
 	return nil, err
 }
 
-// Write a binary pb representation.
-func (g *Gnostic) writeBinaryOutput(message proto.Message) error {
-	protoBytes, err := proto.Marshal(message)
+// Read the header comment that is added to generated outputs.
+func (g *Gnostic) readHeaderComment() error {
+	if g.headerCommentPath == "" {
+		return nil
+	}
+	bytes, err := ioutil.ReadFile(g.headerCommentPath)
 	if err != nil {
-		writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
-	} else {
-		writeFile(g.binaryOutputPath, protoBytes, g.sourceName, "pb")
+		return err
 	}
-	return err
+	g.headerComment = strings.TrimRight(string(bytes), "\n")
+	return nil
 }
 
-// Write a text pb representation.
-func (g *Gnostic) writeTextOutput(message proto.Message) {
-	bytes := []byte(proto.MarshalTextString(message))
-	writeFile(g.textOutputPath, bytes, g.sourceName, "text")
+// commentLines formats text as a block of line comments.
+func commentLines(text string, prefix string) string {
+	if text == "" {
+		return ""
+	}
+	var buffer bytes.Buffer
+	for _, line := range strings.Split(text, "\n") {
+		buffer.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+	}
+	return buffer.String()
 }
 
-// Write JSON/YAML OpenAPI representations.
-func (g *Gnostic) writeJSONYAMLOutput(message proto.Message) {
-	// Convert the OpenAPI document into an exportable MapSlice.
-	var rawInfo *yaml.Node
-	if g.sourceFormat == SourceFormatOpenAPI2 {
-		document := message.(*openapi_v2.Document)
-		rawInfo = document.ToRawInfo()
-	} else if g.sourceFormat == SourceFormatOpenAPI3 {
-		document := message.(*openapi_v3.Document)
-		rawInfo = document.ToRawInfo()
-	} else if g.sourceFormat == SourceFormatDiscovery {
-		document := message.(*discovery_v1.Document)
-		rawInfo = document.ToRawInfo()
+// commentKey is the key of the header comment in JSON outputs. OpenAPI
+// documents allow keys that start with "x-" at their roots.
+const commentKey = "x-comment"
+
+// withCommentKey returns a copy of a document node with a comment key
+// at the start of its root mapping.
+func withCommentKey(node *yaml.Node, comment string) *yaml.Node {
+	if comment == "" {
+		return node
 	}
-	if rawInfo.Kind != yaml.DocumentNode {
-		rawInfo = &yaml.Node{
-			Kind:    yaml.DocumentNode,
-			Content: []*yaml.Node{rawInfo},
-		}
+	root := node
+	if root.Kind == yaml.DocumentNode && len(root.Content) == 1 {
+		root = root.Content[0]
 	}
-	// Optionally write description in yaml format.
-	if g.yamlOutputPath != "" {
-		if rawInfo != nil {
-			bytes, err := yaml.Marshal(rawInfo)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error generating yaml output %s\n", err.Error())
-				fmt.Fprintf(os.Stderr, "info %+v", rawInfo)
-			}
-			writeFile(g.yamlOutputPath, bytes, g.sourceName, "yaml")
-		} else {
-			fmt.Fprintf(os.Stderr, "No yaml output available.\n")
-		}
+	if root.Kind != yaml.MappingNode {
+		return node
 	}
-	// Optionally write description in json format.
-	if g.jsonOutputPath != "" {
-		if rawInfo != nil {
-			rawInfo := &yaml.Node{
-				Kind:    yaml.DocumentNode,
-				Content: []*yaml.Node{rawInfo},
-			}
-			bytes, err := jsonwriter.Marshal(rawInfo)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error generating json output %s\n", err.Error())
-			}
-			writeFile(g.jsonOutputPath, bytes, g.sourceName, "json")
-		} else {
-			fmt.Fprintf(os.Stderr, "No json output available.\n")
-		}
+	mapping := *root
+	mapping.Content = append([]*yaml.Node{
+		compiler.NewScalarNodeForString(commentKey),
+		compiler.NewScalarNodeForString(comment),
+	}, root.Content...)
+	return &mapping
+}
+
+// Write a JSON trace of the compilation.
+func (g *Gnostic) writeTraceOutput() {
+	bytes, err := g.trace.ToJSON()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating trace output %s\n", err.Error())
+		return
 	}
+	g.writeFile(g.traceOutputPath, append(bytes, '\n'), g.sourceName, "trace.json")
+}
+
+// SetParseOptions sets the options of the compiler that reads the source
+// and the files that it refers to. Programs that compile untrusted
+// documents can use them to limit the time and memory that Main uses.
+func (g *Gnostic) SetParseOptions(opts compiler.ParseOptions) {
+	g.parseOptions = opts
+	g.compiler = nil
+}
+
+// logger returns the Logger of the parse options, or a standard Logger
+// at the level of --verbose or --quiet. Without either option, the Logger
+// that was set with compiler.SetLogger is used.
+func (g *Gnostic) logger() compiler.Logger {
+	switch {
+	case g.parseOptions.Logger != nil:
+		return g.parseOptions.Logger
+	case g.verbose:
+		return &compiler.StandardLogger{Level: compiler.LogLevelDebug}
+	case g.quiet:
+		return &compiler.StandardLogger{Level: compiler.LogLevelNone}
+	}
+	return compiler.CurrentLogger()
+}
+
+// rootContext returns the context for compiling a document, with the
+// extension handlers, the Logger and the validation setting of the parse
+// options and an extension cache for the compilation. Callers release
+// it with compiler.ReleaseUserData.
+func (g *Gnostic) rootContext(root *yaml.Node) *compiler.Context {
+	context := compiler.WithExtensionCache(compiler.NewContextWithExtensions("$root", root, nil, &g.extensionHandlers))
+	if g.skipValidation {
+		context = compiler.WithValidation(context, false)
+	}
+	return compiler.WithLogger(context, g.logger())
+}
+
+// prefetchReferences reads the files that the source refers to with the
+// source compiler and adds them to the info cache for ResolveReferences.
+func (g *Gnostic) prefetchReferences(info *yaml.Node) error {
+	if err := g.sourceCompiler().PrefetchReferences(g.sourceName, info); err != nil {
+		return err
+	}
+	g.sourceCompiler().AddToInfoCache()
+	return nil
+}
+
+// sourceCompiler returns the compiler that reads the source.
+func (g *Gnostic) sourceCompiler() *compiler.Compiler {
+	if g.compiler == nil {
+		g.compiler = compiler.NewCompilerWithOptions(g.parseOptions)
+	}
+	return g.compiler
+}
+
+// Trace returns the trace of the most recent call to Main, or nil if
+// it was called without --trace-out.
+// The files that were read are available with Trace().Files().
+func (g *Gnostic) Trace() *compiler.CompilationTrace {
+	return g.trace
+}
+
+// Warnings returns the warnings for the most recently compiled document.
+func (g *Gnostic) Warnings() []*plugins.Message {
+	return g.warnings
 }
 
 // Write messages.
 func (g *Gnostic) writeMessagesOutput(message proto.Message) error {
 	protoBytes, err := proto.Marshal(message)
 	if err != nil {
-		writeFile(g.messageOutputPath, g.errorBytes(err), g.sourceName, "errors")
+		g.writeFile(g.messageOutputPath, g.errorBytes(err), g.sourceName, "errors")
 	} else {
-		writeFile(g.messageOutputPath, protoBytes, g.sourceName, "messages.pb")
+		g.writeFile(g.messageOutputPath, protoBytes, g.sourceName, "messages.pb")
 	}
 	return err
 }
 
 // Perform all actions specified in the command-line options.
 func (g *Gnostic) performActions(message proto.Message) (err error) {
+	// Optionally add components from schema libraries.
+	if len(g.componentImports) > 0 {
+		if err = g.importComponents(message); err != nil {
+			return err
+		}
+	}
+	// Optionally rename components.
+	if len(g.componentRenames) > 0 {
+		document, ok := message.(*openapi_v3.Document)
+		if !ok {
+			return errors.New("--rename is only supported for OpenAPI v3 documents")
+		}
+		if err = openapi_v3.RenameComponents(document, g.componentRenames); err != nil {
+			return err
+		}
+	}
 	// Optionally resolve internal references.
 	if g.resolveReferences {
+		endPhase := g.trace.StartPhase("references")
+		// Remote files are read in advance with the current fetcher.
+		if g.smartReferences && g.sourceFormat != SourceFormatOpenAPI3 {
+			endPhase()
+			return errors.New("--resolve-refs=smart is only supported for OpenAPI v3 documents")
+		}
 		if g.sourceFormat == SourceFormatOpenAPI2 {
 			document := message.(*openapi_v2.Document)
-			_, err = document.ResolveReferences(g.sourceName)
+			if err = g.prefetchReferences(document.ToRawInfo()); err == nil {
+				_, err = document.ResolveReferences(g.sourceName)
+			}
 		} else if g.sourceFormat == SourceFormatOpenAPI3 {
 			document := message.(*openapi_v3.Document)
-			_, err = document.ResolveReferences(g.sourceName)
+			if err = g.prefetchReferences(document.ToRawInfo()); err == nil {
+				_, err = document.ResolveReferences(g.sourceName)
+			}
+			if err == nil && g.smartReferences {
+				var report *openapi_v3.ResolveReport
+				if report, err = openapi_v3.ResolveReferencesSmart(document, g.sourceName); err == nil {
+					report.Components += g.promotedAliases
+					fmt.Fprintf(os.Stderr, "%s: %s\n", g.sourceName, report)
+				}
+			}
 		}
+		endPhase()
+		g.trace.AddCachedFiles()
 		if err != nil {
 			return err
 		}
 	}
-	// Optionally write proto in binary format.
-	if g.binaryOutputPath != "" {
-		err = g.writeBinaryOutput(message)
+	// Optionally resolve example references and fetch external example values.
+	var exampleWarnings []*openapi_v3.ExampleWarning
+	if (g.resolveReferences || g.fetchExternalExamples) && g.sourceFormat == SourceFormatOpenAPI3 {
+		endPhase := g.trace.StartPhase("references")
+		exampleWarnings = openapi_v3.ResolveExamples(message.(*openapi_v3.Document), g.sourceName, g.fetchExternalExamples)
+		endPhase()
+	}
+	// Optionally remove marked elements and extensions.
+	if g.stripMarker != "" || g.stripPattern != "" {
+		if g.sourceFormat != SourceFormatOpenAPI3 {
+			return errors.New("--strip-extension and --strip-extensions-matching are only supported for OpenAPI v3 documents")
+		}
+		document := message.(*openapi_v3.Document)
+		if g.stripMarker != "" {
+			if err = openapi_v3.StripMarked(document, g.stripMarker); err != nil {
+				return err
+			}
+		}
+		if g.stripPattern != "" {
+			pattern, err := regexp.Compile(g.stripPattern)
+			if err != nil {
+				return err
+			}
+			openapi_v3.StripExtensions(document, pattern)
+		}
+	}
+	// Optionally record the source of each component.
+	if g.annotateSources && g.sourceFormat == SourceFormatOpenAPI3 {
+		openapi_v3.AnnotateSources(message.(*openapi_v3.Document), g.sourceName)
+	}
+	// Optionally simplify unions of scalar types.
+	if g.simplifyUnions && g.sourceFormat == SourceFormatOpenAPI3 {
+		openapi_v3.SimplifyUnions(message.(*openapi_v3.Document))
+	}
+	// Optionally infer missing schemas from examples.
+	if g.inferMissingSchemas {
+		if g.sourceFormat != SourceFormatOpenAPI3 {
+			return errors.New("--infer-missing-schemas is only supported for OpenAPI v3 documents")
+		}
+		openapi_v3.InferMissingSchemas(message.(*openapi_v3.Document))
+	}
+	// Optionally add code samples to operations.
+	if len(g.codeSamples) > 0 {
+		if g.sourceFormat != SourceFormatOpenAPI3 {
+			return errors.New("--code-samples is only supported for OpenAPI v3 documents")
+		}
+		if _, err = openapi_v3.AddCodeSamples(message.(*openapi_v3.Document), g.codeSamples); err != nil {
+			return err
+		}
+	}
+	// Optionally add a version header parameter to operations.
+	if g.versionHeader != "" {
+		if g.sourceFormat != SourceFormatOpenAPI3 {
+			return errors.New("--version-header is only supported for OpenAPI v3 documents")
+		}
+		document := message.(*openapi_v3.Document)
+		description, err := g.versionHeaderText(document)
 		if err != nil {
 			return err
 		}
+		openapi_v3.AddHeaderParameter(document, g.versionHeader, description)
 	}
-	// Optionally write proto in text format.
-	if g.textOutputPath != "" {
-		g.writeTextOutput(message)
+	// Optionally replace duplicated inline values with components.
+	if g.dedupeComponents {
+		if g.sourceFormat != SourceFormatOpenAPI3 {
+			return errors.New("--dedupe-components is only supported for OpenAPI v3 documents")
+		}
+		openapi_v3.DedupeComponents(message.(*openapi_v3.Document), g.duplicateThreshold(), g.dedupeDescriptions)
 	}
-	// Optionally write document in yaml and/or json formats.
-	if g.yamlOutputPath != "" || g.jsonOutputPath != "" {
-		g.writeJSONYAMLOutput(message)
+	// Optionally anonymize the document. This is done after all other
+	// changes so that text they add to the document is anonymized too.
+	if g.anonymize {
+		if g.sourceFormat != SourceFormatOpenAPI3 {
+			return errors.New("--anonymize is only supported for OpenAPI v3 documents")
+		}
+		openapi_v3.Anonymize(message.(*openapi_v3.Document))
+	}
+	// Check the document for problems that don't prevent compilation.
+	if !g.skipValidation {
+		g.warnings = append(warningsForDocument(message), warningsForExamples(exampleWarnings)...)
+	}
+	// Optionally check that examples conform to their schemas.
+	if g.validateExamples {
+		if g.sourceFormat != SourceFormatOpenAPI3 {
+			return errors.New("--validate-examples is only supported for OpenAPI v3 documents")
+		}
+		exampleErrors := openapi_v3.ValidateExamples(message.(*openapi_v3.Document))
+		g.warnings = append(g.warnings, warningsForInvalidExamples(exampleErrors)...)
 	}
-	// Call all specified plugins.
-	messages := make([]*plugins.Message, 0)
-	errors := make([]error, 0)
-	for _, p := range g.pluginCalls {
-		pluginMessages, err := p.perform(message, g.sourceFormat, g.sourceName, g.timePlugins, g.excludeSurface)
+	// Optionally compare the operations with grpc-gateway bindings.
+	if g.grpcDescriptorsPath != "" {
+		if g.sourceFormat != SourceFormatOpenAPI3 {
+			return errors.New("--check-grpc is only supported for OpenAPI v3 documents")
+		}
+		set, err := readDescriptorSet(g.grpcDescriptorsPath)
 		if err != nil {
-			// we don't exit or fail here so that we run all plugins even when some have errors
-			errors = append(errors, err)
+			return err
+		}
+		mismatches, err := CheckGRPCBindings(message.(*openapi_v3.Document), set)
+		if err != nil {
+			return err
 		}
-		messages = append(messages, pluginMessages...)
+		g.warnings = append(g.warnings, warningsForGRPCMismatches(mismatches)...)
+	}
+	if g.lintDuplicates && g.sourceFormat == SourceFormatOpenAPI3 {
+		duplicates := openapi_v3.FindDuplicateComponents(message.(*openapi_v3.Document), g.duplicateThreshold(), g.dedupeDescriptions)
+		g.warnings = append(g.warnings, warningsForDuplicates(duplicates)...)
+	}
+	// Compute all outputs and plugins before writing any of them.
+	messages, errors := g.runOutputStages(message)
+	if len(errors) > 0 || g.verbose {
+		g.writeOutputSummary(os.Stderr)
+	}
+	// Compare the warnings and plugin messages with the lint baseline.
+	findings := append(append([]*plugins.Message{}, g.warnings...), messages...)
+	baselined, err := g.applyLintBaseline(findings)
+	if err != nil {
+		return err
 	}
 	if g.messageOutputPath != "" {
-		err = g.writeMessagesOutput(&plugins.Messages{Messages: messages})
+		err = g.writeMessagesOutput(&plugins.Messages{Messages: findings})
 		if err != nil {
 			return err
 		}
 	} else {
+		// Print any warnings on stderr so that they don't mix with outputs written to stdout.
+		for i, warning := range g.warnings {
+			fmt.Fprintln(os.Stderr, warningString(warning, g.sourceName)+sourceSuffix(message, warning.Keys)+baselinedSuffix(baselined[i]))
+		}
 		// Print any messages from the plugins
 		if len(messages) > 0 {
-			for _, message := range messages {
-				fmt.Printf("%+v\n", message)
+			for i, m := range messages {
+				fmt.Printf("%+v%s%s\n", m, sourceSuffix(message, m.Keys), baselinedSuffix(baselined[len(g.warnings)+i]))
 			}
 		}
 	}
+	if err = g.checkFindings(findings, baselined); err != nil {
+		errors = append(errors, err)
+	}
 	return compiler.NewErrorGroupOrNil(errors)
 }
 
@@ -648,42 +1791,208 @@ func (g *Gnostic) Main() error {
 	if err != nil {
 		return err
 	}
+	err = g.readConfigFile()
+	if err != nil {
+		return err
+	}
 	err = g.validateOptions()
 	if err != nil {
 		return err
 	}
+	err = g.readHeaderComment()
+	if err != nil {
+		return err
+	}
+	// Messages of the compiler, extension handlers and plugins go to the
+	// Logger of the parse options or to a standard Logger.
+	logger := g.logger()
+	g.parseOptions.Logger = logger
+	if g.convertIn != "" {
+		return g.convertTree()
+	}
+	// Read remote files with a cache directory or retries if requested.
+	if g.fetchCacheDir != "" || g.fetchRetries > 0 {
+		compiler.SetFetcher(&compiler.HTTPFetcher{CacheDir: g.fetchCacheDir, Retries: g.fetchRetries, Logger: logger})
+		defer compiler.SetFetcher(nil)
+	}
+	g.compiler = compiler.NewCompilerWithOptions(g.parseOptions)
+	// Trace the files that are read and the time spent in each phase.
+	g.trace = nil
+	if g.traceOutputPath != "" {
+		g.trace = compiler.StartTrace()
+		defer compiler.StopTrace()
+		defer g.writeTraceOutput()
+	}
 	// Read the OpenAPI source.
-	bytes, err := compiler.ReadBytesForFile(g.sourceName)
+	var bytes []byte
+	if g.sourceName == "-" {
+		bytes, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		bytes, err = g.compiler.ReadBytes(g.sourceName)
+	}
+	if err == nil {
+		// Sources are decompressed if they start with the gzip magic number.
+		bytes, err = g.sourceCompiler().Decompress(g.sourceName, bytes)
+	}
 	if err != nil {
-		writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
+		g.writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
 		return err
 	}
-	extension := strings.ToLower(filepath.Ext(g.sourceName))
+	sum := sha256.Sum256(bytes)
+	g.sourceHash = hex.EncodeToString(sum[:])
+	extension := strings.ToLower(filepath.Ext(trimGzipExtension(g.sourceName)))
 	var message proto.Message
-	if extension == ".json" || extension == ".yaml" {
+	if extension == ".raml" || strings.HasPrefix(string(bytes), "#%RAML") {
+		// Convert RAML definitions to OpenAPI v3.
+		message, err = g.readRAML(bytes)
+		if err != nil {
+			g.writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
+			return err
+		}
+	} else if extension == ".json" || extension == ".yaml" || g.sourceName == "-" {
 		// Try to read the source as JSON/YAML.
 		message, err = g.readOpenAPIText(bytes)
 		if err != nil {
-			writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
+			g.writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
 			return err
 		}
 	} else if extension == ".pb" {
 		// Try to read the source as a binary protocol buffer.
 		message, err = g.readOpenAPIBinary(bytes)
 		if err != nil {
-			writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
+			g.writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
 			return err
 		}
 	} else {
-		err = errors.New("unknown file extension. 'json', 'yaml', and 'pb' are accepted")
-		writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
+		err = errors.New("unknown file extension. 'json', 'yaml', 'raml', and 'pb' are accepted")
+		g.writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
 		return err
 	}
 	// Perform actions specified by command options.
 	err = g.performActions(message)
 	if err != nil {
-		writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
+		g.writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
 		return err
 	}
+	if g.markdownDocsDir != "" {
+		if err = g.writeMarkdownDocs(message); err != nil {
+			g.writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
+			return err
+		}
+	}
+	if g.mockServerAddress != "" {
+		return g.serveMocks(message)
+	}
 	return nil
 }
+
+// defaultVersionHeaderDescription is the description of version header
+// parameters if no --version-header-description is given.
+const defaultVersionHeaderDescription = "The version of the API."
+
+// duplicateThreshold returns the number of identical inline values that
+// are allowed before they are reported or replaced as duplicates.
+func (g *Gnostic) duplicateThreshold() int {
+	if g.dedupeThreshold < 0 {
+		return 2
+	}
+	return g.dedupeThreshold
+}
+
+// versionHeaderText expands the description template of the version header.
+func (g *Gnostic) versionHeaderText(document *openapi_v3.Document) (string, error) {
+	text := g.versionDescription
+	if text == "" {
+		text = defaultVersionHeaderDescription
+	}
+	t, err := template.New("version-header-description").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid --version-header-description: %s", err.Error())
+	}
+	var buffer bytes.Buffer
+	err = t.Execute(&buffer, struct {
+		Name    string
+		Title   string
+		Version string
+	}{
+		Name:    g.versionHeader,
+		Title:   document.GetInfo().GetTitle(),
+		Version: document.GetInfo().GetVersion(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("invalid --version-header-description: %s", err.Error())
+	}
+	return buffer.String(), nil
+}
+
+// Write Markdown documentation files to a directory.
+func (g *Gnostic) writeMarkdownDocs(message proto.Message) error {
+	var document *openapi_v3.Document
+	switch d := message.(type) {
+	case *openapi_v3.Document:
+		document = d
+	case *openapi_v2.Document:
+		converted, _, err := conversions.OpenAPIv3FromOpenAPIv2(d, nil)
+		if err != nil {
+			return err
+		}
+		document = converted
+	default:
+		return errors.New("documentation can only be generated for OpenAPI documents")
+	}
+	if err := os.MkdirAll(g.markdownDocsDir, 0755); err != nil {
+		return err
+	}
+	opts := &openapi_v3.MarkdownOptions{Depth: g.markdownDepth}
+	if g.markdownTemplatesDir != "" {
+		paths, err := filepath.Glob(filepath.Join(g.markdownTemplatesDir, "*.tmpl"))
+		if err != nil {
+			return err
+		}
+		opts.Templates = make(map[string]string)
+		for _, path := range paths {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			opts.Templates[strings.TrimSuffix(filepath.Base(path), ".tmpl")] = string(data)
+		}
+	}
+	files, err := openapi_v3.GenerateMarkdownWithOptions(document, opts)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := ioutil.WriteFile(filepath.Join(g.markdownDocsDir, file.Name), file.Data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewMockHandler returns an http.Handler that serves mock responses for a
+// document (see --mock-server). OpenAPI v2 documents are converted to v3.
+func NewMockHandler(doc Document) (http.Handler, error) {
+	switch document := doc.(type) {
+	case *openapi_v3.Document:
+		return openapi_v3.NewMockHandler(document), nil
+	case *openapi_v2.Document:
+		converted, _, err := conversions.OpenAPIv3FromOpenAPIv2(document, nil)
+		if err != nil {
+			return nil, err
+		}
+		return openapi_v3.NewMockHandler(converted), nil
+	}
+	return nil, errors.New("mock servers are only supported for OpenAPI documents")
+}
+
+// Serve mock responses until the server fails.
+func (g *Gnostic) serveMocks(message proto.Message) error {
+	handler, err := NewMockHandler(message)
+	if err != nil {
+		g.writeFile(g.errorOutputPath, g.errorBytes(err), g.sourceName, "errors")
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Serving mock responses for %s on %s\n", g.sourceName, g.mockServerAddress)
+	return http.ListenAndServe(g.mockServerAddress, handler)
+}
@@ -0,0 +1,527 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/google/gnostic/compiler"
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+)
+
+// grpcMethodExtension names the RPC method of an operation, e.g.
+// "library.v1.Library.GetShelf", when its operationId doesn't.
+const grpcMethodExtension = "x-grpc-method"
+
+// A GRPCMismatch describes a difference between an operation of an OpenAPI
+// document and the grpc-gateway binding of an RPC method.
+type GRPCMismatch struct {
+	// Keys locate the operation, parameter or request body in the document.
+	Keys []string
+	// Method is the full name of the RPC method, or empty if no method
+	// is paired with the operation.
+	Method string
+	// Text describes the mismatch.
+	Text string
+	// OpenAPI and GRPC are the definitions of both sides.
+	OpenAPI string
+	GRPC    string
+}
+
+func (m *GRPCMismatch) String() string {
+	return fmt.Sprintf("%s: %s (openapi: %s, grpc: %s)", strings.Join(m.Keys, "."), m.Text, m.OpenAPI, m.GRPC)
+}
+
+// grpcBinding is an HTTP rule of an RPC method.
+type grpcBinding struct {
+	method  protoreflect.MethodDescriptor
+	rule    *annotations.HttpRule
+	verb    string
+	path    string
+	matched bool
+}
+
+func (b *grpcBinding) String() string {
+	return b.verb + " " + b.path + " (" + string(b.method.FullName()) + ")"
+}
+
+// CheckGRPCBindings compares the operations of an OpenAPI v3 document
+// with the google.api.http bindings of the RPC methods of a descriptor set
+// that grpc-gateway serves. Operations are paired with methods by their
+// x-grpc-method extensions, or by operationIds that are either full
+// method names or the Service_Method names of protoc-gen-openapi. Paired
+// operations must have the path template, path and query parameters and
+// request body of one of the bindings of their methods, and each binding
+// must have an operation.
+func CheckGRPCBindings(d *openapi_v3.Document, set *descriptorpb.FileDescriptorSet) ([]*GRPCMismatch, error) {
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, err
+	}
+	bindings := make(map[string][]*grpcBinding)
+	all := make([]*grpcBinding, 0)
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				method := methods.Get(j)
+				rule, ok := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+				if !ok || rule == nil {
+					continue
+				}
+				for _, r := range append([]*annotations.HttpRule{rule}, rule.AdditionalBindings...) {
+					verb, path := httpRulePattern(r)
+					if verb == "" {
+						continue
+					}
+					b := &grpcBinding{method: method, rule: r, verb: verb, path: path}
+					for _, name := range []string{
+						string(method.FullName()),
+						string(method.Parent().Name()) + "_" + string(method.Name()),
+					} {
+						bindings[name] = append(bindings[name], b)
+					}
+					all = append(all, b)
+				}
+			}
+		}
+		return true
+	})
+	mismatches := make([]*GRPCMismatch, 0)
+	for _, operation := range openapi_v3.OperationBindings(d) {
+		name := operation.Operation.OperationId
+		var extension string
+		if err := openapi_v3.GetExtensionInto(operation.Operation, grpcMethodExtension, &extension); err == nil && extension != "" {
+			name = strings.Replace(strings.TrimPrefix(extension, "/"), "/", ".", -1)
+		}
+		candidates := bindings[name]
+		if len(candidates) == 0 {
+			mismatches = append(mismatches, &GRPCMismatch{
+				Keys:    operation.Keys,
+				Text:    "operation has no RPC method",
+				OpenAPI: operationString(operation),
+				GRPC:    "no method named " + name,
+			})
+			continue
+		}
+		var binding *grpcBinding
+		for _, b := range candidates {
+			if b.verb == operation.Method && grpcTemplateKey(b.path) == openAPITemplateKey(operation.Path) {
+				binding = b
+				break
+			}
+		}
+		if binding == nil {
+			descriptions := make([]string, 0, len(candidates))
+			for _, b := range candidates {
+				descriptions = append(descriptions, b.verb+" "+b.path)
+				// Bindings of the same method are reported here.
+				if b.verb == operation.Method {
+					b.matched = true
+				}
+			}
+			mismatches = append(mismatches, &GRPCMismatch{
+				Keys:    operation.Keys,
+				Method:  string(candidates[0].method.FullName()),
+				Text:    "path template doesn't match",
+				OpenAPI: operation.Method + " " + operation.Path,
+				GRPC:    strings.Join(descriptions, ", "),
+			})
+			continue
+		}
+		binding.matched = true
+		mismatches = append(mismatches, compareGRPCBinding(operation, binding)...)
+	}
+	for _, b := range all {
+		if !b.matched {
+			mismatches = append(mismatches, &GRPCMismatch{
+				Keys:    []string{"paths"},
+				Method:  string(b.method.FullName()),
+				Text:    "binding has no operation",
+				OpenAPI: "no operation for " + b.verb + " " + b.path,
+				GRPC:    b.String(),
+			})
+		}
+	}
+	return mismatches, nil
+}
+
+// httpRulePattern returns the HTTP method and the path template of a rule,
+// or empty strings for custom methods.
+func httpRulePattern(rule *annotations.HttpRule) (string, string) {
+	switch pattern := rule.Pattern.(type) {
+	case *annotations.HttpRule_Get:
+		return "GET", pattern.Get
+	case *annotations.HttpRule_Put:
+		return "PUT", pattern.Put
+	case *annotations.HttpRule_Post:
+		return "POST", pattern.Post
+	case *annotations.HttpRule_Delete:
+		return "DELETE", pattern.Delete
+	case *annotations.HttpRule_Patch:
+		return "PATCH", pattern.Patch
+	}
+	return "", ""
+}
+
+func operationString(operation *openapi_v3.OperationBinding) string {
+	s := operation.Method + " " + operation.Path
+	if operation.Operation.OperationId != "" {
+		s += " (" + operation.Operation.OperationId + ")"
+	}
+	return s
+}
+
+// A templateVariable is a variable of a grpc-gateway path template, e.g.
+// {name=shelves/*}, with the positions of its wildcard segments.
+type templateVariable struct {
+	field     string
+	simple    bool
+	positions []int
+}
+
+// parseGRPCTemplate returns the segments of a grpc-gateway path template,
+// with variables expanded to their patterns, and the variables. A custom
+// verb is part of the last segment.
+func parseGRPCTemplate(template string) ([]string, []*templateVariable) {
+	segments := make([]string, 0)
+	variables := make([]*templateVariable, 0)
+	rest := strings.TrimPrefix(template, "/")
+	verb := ""
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "}") && i > strings.LastIndex(rest, "/") {
+		rest, verb = rest[:i], rest[i:]
+	}
+	for rest != "" {
+		if rest[0] == '{' {
+			end := strings.Index(rest, "}")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			parts := strings.SplitN(rest[1:end], "=", 2)
+			v := &templateVariable{field: parts[0], simple: len(parts) == 1 || parts[1] == "*"}
+			pattern := "*"
+			if len(parts) == 2 {
+				pattern = parts[1]
+			}
+			for _, segment := range strings.Split(pattern, "/") {
+				if segment == "*" || segment == "**" {
+					v.positions = append(v.positions, len(segments))
+					segment = "*"
+				}
+				segments = append(segments, segment)
+			}
+			variables = append(variables, v)
+			rest = rest[end+1:]
+		} else if end := strings.Index(rest, "/"); end >= 0 {
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		} else {
+			segments = append(segments, rest)
+			rest = ""
+		}
+		rest = strings.TrimPrefix(rest, "/")
+	}
+	if len(segments) > 0 {
+		segments[len(segments)-1] += verb
+	}
+	return segments, variables
+}
+
+// parseOpenAPITemplate returns the segments of an OpenAPI path template,
+// with parameters replaced by *, and the names of the parameters by
+// position.
+func parseOpenAPITemplate(template string) ([]string, map[int]string) {
+	segments := strings.Split(strings.TrimPrefix(template, "/"), "/")
+	names := make(map[int]string)
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") {
+			if end := strings.Index(segment, "}"); end > 0 {
+				names[i] = segment[1:end]
+				segments[i] = "*" + segment[end+1:]
+			}
+		}
+	}
+	return segments, names
+}
+
+func grpcTemplateKey(template string) string {
+	segments, _ := parseGRPCTemplate(template)
+	return "/" + strings.Join(segments, "/")
+}
+
+func openAPITemplateKey(template string) string {
+	segments, _ := parseOpenAPITemplate(template)
+	return "/" + strings.Join(segments, "/")
+}
+
+// compareGRPCBinding compares the parameters and the request body of an
+// operation with the binding that has its path template.
+func compareGRPCBinding(operation *openapi_v3.OperationBinding, binding *grpcBinding) []*GRPCMismatch {
+	mismatches := make([]*GRPCMismatch, 0)
+	method := string(binding.method.FullName())
+	input := binding.method.Input()
+	mismatch := func(keys []string, text, openapi, grpc string) {
+		mismatches = append(mismatches, &GRPCMismatch{Keys: keys, Method: method, Text: text, OpenAPI: openapi, GRPC: grpc})
+	}
+	parameters := make(map[string]*openapi_v3.ParameterBinding)
+	for _, p := range operation.PathParameters {
+		parameters[p.Name] = p
+	}
+	_, variables := parseGRPCTemplate(binding.path)
+	_, names := parseOpenAPITemplate(operation.Path)
+	bound := make(map[string]bool)
+	for _, v := range variables {
+		field := fieldForPath(input, v.field)
+		bound[v.field] = true
+		if field == nil {
+			mismatch(operation.Keys, "path variable is not a field of the request", operation.Path, fmt.Sprintf("{%s} of %s", v.field, input.FullName()))
+			continue
+		}
+		for _, position := range v.positions {
+			p := parameters[names[position]]
+			if p == nil {
+				continue
+			}
+			if v.simple && !fieldPathMatches(input, v.field, p.Name) {
+				mismatch(p.Keys, "path parameter doesn't name its field", "path parameter "+p.Name, fmt.Sprintf("{%s} of %s", v.field, input.FullName()))
+			}
+			// Variables with patterns bind parts of resource names, which are strings.
+			types := []string{"string"}
+			if v.simple {
+				types = schemaTypesForField(field)
+			}
+			if !parameterTypeMatches(p, types) {
+				mismatch(p.Keys, "path parameter type doesn't match", parameterString("path", p), fieldString(field, types))
+			}
+		}
+	}
+	body := binding.rule.Body
+	if body != "" && body != "*" {
+		bound[body] = true
+	}
+	for _, p := range operation.QueryParameters {
+		if body == "*" {
+			mismatch(p.Keys, "query parameter is bound to the request body", parameterString("query", p), fmt.Sprintf("body: \"*\" of %s", input.FullName()))
+			continue
+		}
+		field := fieldForPath(input, p.Name)
+		if field == nil {
+			mismatch(p.Keys, "query parameter is not a field of the request", parameterString("query", p), string(input.FullName()))
+			continue
+		}
+		for name := range bound {
+			if fieldPathMatches(input, name, p.Name) || strings.HasPrefix(p.Name, name+".") {
+				mismatch(p.Keys, "query parameter is bound to the path or the request body", parameterString("query", p), fmt.Sprintf("%s of %s", name, input.FullName()))
+			}
+		}
+		if types := schemaTypesForField(field); !parameterTypeMatches(p, types) {
+			mismatch(p.Keys, "query parameter type doesn't match", parameterString("query", p), fieldString(field, types))
+		}
+	}
+	if input.FullName() == "google.api.HttpBody" {
+		return mismatches
+	}
+	switch {
+	case body == "" && operation.Body != nil:
+		mismatch(operation.Body.Keys, "request body isn't bound", bodyString(operation.Body), "no body")
+	case body != "" && operation.Body == nil:
+		mismatch(appendKeys(operation.Keys, "requestBody"), "request body is missing", "no request body", fmt.Sprintf("body: %q of %s", body, input.FullName()))
+	case body == "*":
+		if !bodyMatchesMessage(operation.Body, input) {
+			mismatch(operation.Body.Keys, "request body schema doesn't match", bodyString(operation.Body), string(input.FullName()))
+		}
+	case body != "":
+		field := fieldForPath(input, body)
+		switch {
+		case field == nil:
+			mismatch(operation.Body.Keys, "body field is not a field of the request", bodyString(operation.Body), fmt.Sprintf("body: %q of %s", body, input.FullName()))
+		case field.Kind() == protoreflect.MessageKind && !field.IsList() && !field.IsMap():
+			if !bodyMatchesMessage(operation.Body, field.Message()) {
+				mismatch(operation.Body.Keys, "request body schema doesn't match", bodyString(operation.Body), fieldString(field, nil))
+			}
+		default:
+			types := schemaTypesForField(field)
+			if operation.Body.Schema != "" || !containsString(types, operation.Body.Type) {
+				mismatch(operation.Body.Keys, "request body schema doesn't match", bodyString(operation.Body), fieldString(field, types))
+			}
+		}
+	}
+	return mismatches
+}
+
+// fieldForPath returns the field of a message at a path of proto or JSON
+// field names separated by dots, or nil if there is none.
+func fieldForPath(message protoreflect.MessageDescriptor, path string) protoreflect.FieldDescriptor {
+	var field protoreflect.FieldDescriptor
+	for _, name := range strings.Split(path, ".") {
+		if message == nil {
+			return nil
+		}
+		fields := message.Fields()
+		if field = fields.ByName(protoreflect.Name(name)); field == nil {
+			if field = fields.ByJSONName(name); field == nil {
+				return nil
+			}
+		}
+		message = field.Message()
+	}
+	return field
+}
+
+// fieldPathMatches reports whether two paths name the same field.
+func fieldPathMatches(message protoreflect.MessageDescriptor, a, b string) bool {
+	if a == b {
+		return true
+	}
+	x, y := fieldForPath(message, a), fieldForPath(message, b)
+	return x != nil && x == y && strings.Count(a, ".") == strings.Count(b, ".")
+}
+
+// schemaTypesForField returns the schema types that protoc-gen-openapi
+// and the protobuf JSON mapping use for a field, or nil for messages that
+// have no scalar representation.
+func schemaTypesForField(field protoreflect.FieldDescriptor) []string {
+	if field.IsList() {
+		return []string{"array"}
+	}
+	if field.IsMap() {
+		return []string{"object"}
+	}
+	return schemaTypesForKind(field)
+}
+
+func schemaTypesForKind(field protoreflect.FieldDescriptor) []string {
+	switch field.Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return []string{"string"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Uint32Kind,
+		protoreflect.Sfixed32Kind, protoreflect.Fixed32Kind:
+		return []string{"integer"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Uint64Kind,
+		protoreflect.Sfixed64Kind, protoreflect.Fixed64Kind:
+		return []string{"string", "integer"}
+	case protoreflect.EnumKind:
+		return []string{"string", "integer"}
+	case protoreflect.BoolKind:
+		return []string{"boolean"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return []string{"number"}
+	case protoreflect.MessageKind:
+		switch field.Message().FullName() {
+		case "google.protobuf.Timestamp", "google.protobuf.Duration", "google.protobuf.FieldMask":
+			return []string{"string"}
+		case "google.protobuf.BoolValue", "google.protobuf.BytesValue", "google.protobuf.Int32Value",
+			"google.protobuf.UInt32Value", "google.protobuf.StringValue", "google.protobuf.Int64Value",
+			"google.protobuf.UInt64Value", "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+			return schemaTypesForKind(field.Message().Fields().ByName("value"))
+		}
+	}
+	return nil
+}
+
+// parameterTypeMatches reports whether the schema of a parameter has one
+// of the types of a field. Parameters with referenced schemas and fields
+// without scalar types always match.
+func parameterTypeMatches(p *openapi_v3.ParameterBinding, types []string) bool {
+	return p.Type == "" || types == nil || containsString(types, p.Type)
+}
+
+// bodyMatchesMessage reports whether a request body has the schema of a
+// message. Component schemas are named after messages by their names or
+// their full names.
+func bodyMatchesMessage(body *openapi_v3.BodyBinding, message protoreflect.MessageDescriptor) bool {
+	if body.Schema == "" {
+		return body.Type == "" || body.Type == "object"
+	}
+	full := string(message.FullName())
+	local := strings.TrimPrefix(full, string(message.ParentFile().Package())+".")
+	return body.Schema == full || body.Schema == string(message.Name()) ||
+		body.Schema == local || body.Schema == strings.Replace(local, ".", "_", -1)
+}
+
+func parameterString(in string, p *openapi_v3.ParameterBinding) string {
+	s := in + " parameter " + p.Name
+	if p.Type != "" {
+		s += ": " + p.Type
+		if p.Format != "" {
+			s += " (" + p.Format + ")"
+		}
+	}
+	return s
+}
+
+func bodyString(body *openapi_v3.BodyBinding) string {
+	if body.Schema != "" {
+		return "schema " + body.Schema
+	}
+	return "schema of type " + body.Type
+}
+
+func fieldString(field protoreflect.FieldDescriptor, types []string) string {
+	s := "field " + string(field.FullName()) + ": "
+	if field.IsList() {
+		s += "repeated "
+	}
+	if field.Kind() == protoreflect.MessageKind {
+		s += string(field.Message().FullName())
+	} else if field.Kind() == protoreflect.EnumKind {
+		s += string(field.Enum().FullName())
+	} else {
+		s += field.Kind().String()
+	}
+	if len(types) > 0 {
+		s += " (" + strings.Join(types, " or ") + ")"
+	}
+	return s
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func appendKeys(keys []string, more ...string) []string {
+	return append(append(make([]string, 0, len(keys)+len(more)), keys...), more...)
+}
+
+// readDescriptorSet reads a binary FileDescriptorSet, as written by
+// protoc --descriptor_set_out.
+func readDescriptorSet(filename string) (*descriptorpb.FileDescriptorSet, error) {
+	bytes, err := compiler.ReadBytesForFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(bytes, set); err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err.Error())
+	}
+	if len(set.File) == 0 {
+		return nil, errors.New(filename + ": no file descriptors")
+	}
+	return set, nil
+}
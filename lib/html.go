@@ -0,0 +1,47 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"errors"
+	"io"
+
+	"github.com/google/gnostic/conversions"
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+)
+
+// Write a single-page HTML reference. OpenAPI v2 documents are converted to v3.
+func writeHTML(doc Document, w io.Writer, params map[string]string) error {
+	var document *openapi_v3.Document
+	switch d := doc.(type) {
+	case *openapi_v3.Document:
+		document = d
+	case *openapi_v2.Document:
+		converted, _, err := conversions.OpenAPIv3FromOpenAPIv2(d, nil)
+		if err != nil {
+			return err
+		}
+		document = converted
+	default:
+		return errors.New("HTML references can only be generated for OpenAPI documents")
+	}
+	page, err := openapi_v3.GenerateHTML(document)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(page)
+	return err
+}
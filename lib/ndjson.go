@@ -0,0 +1,338 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+)
+
+// The ndjson output writes one JSON record per line: a document record
+// followed by one record for each operation, sorted by path and method.
+// Schemas are summarized by fingerprints, the canonical hashes of their
+// YAML representations (see compiler.CanonicalHash). References are
+// not resolved, so a schema reference is fingerprinted as written.
+
+// ndjsonDocument is the first record of the ndjson output.
+type ndjsonDocument struct {
+	Kind       string `json:"kind"`
+	Format     string `json:"format"`
+	Title      string `json:"title,omitempty"`
+	Version    string `json:"version,omitempty"`
+	Source     string `json:"source,omitempty"`
+	SourceHash string `json:"sourceHash,omitempty"`
+	Operations int    `json:"operations"`
+}
+
+// ndjsonOperation describes an operation.
+type ndjsonOperation struct {
+	Kind        string            `json:"kind"`
+	Path        string            `json:"path"`
+	Method      string            `json:"method"`
+	OperationID string            `json:"operationId,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Deprecated  bool              `json:"deprecated"`
+	Parameters  []ndjsonParameter `json:"parameters,omitempty"`
+	// RequestBody maps media types to schema fingerprints.
+	RequestBody map[string]string `json:"requestBody,omitempty"`
+	// Responses map status codes to media types and schema fingerprints.
+	Responses map[string]map[string]string `json:"responses,omitempty"`
+}
+
+// ndjsonParameter summarizes a parameter. Parameters that refer to
+// components of the document are described by their components.
+type ndjsonParameter struct {
+	Name     string `json:"name,omitempty"`
+	In       string `json:"in,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Schema   string `json:"schema,omitempty"`
+	Ref      string `json:"$ref,omitempty"`
+}
+
+// Write one JSON record per operation.
+func writeNDJSON(doc Document, w io.Writer, params map[string]string) error {
+	var header ndjsonDocument
+	var operations []*ndjsonOperation
+	switch document := doc.(type) {
+	case *openapi_v2.Document:
+		header = ndjsonDocument{
+			Format:  "openapi.v2",
+			Title:   document.GetInfo().GetTitle(),
+			Version: document.GetInfo().GetVersion(),
+		}
+		operations = ndjsonOperationsV2(document)
+	case *openapi_v3.Document:
+		header = ndjsonDocument{
+			Format:  "openapi.v3",
+			Title:   document.GetInfo().GetTitle(),
+			Version: document.GetInfo().GetVersion(),
+		}
+		operations = ndjsonOperationsV3(document)
+	default:
+		return errors.New("ndjson output is only supported for OpenAPI documents")
+	}
+	sort.SliceStable(operations, func(i, j int) bool {
+		if operations[i].Path != operations[j].Path {
+			return operations[i].Path < operations[j].Path
+		}
+		return operations[i].Method < operations[j].Method
+	})
+	header.Kind = "document"
+	header.Source = params[sourceParameter]
+	header.SourceHash = params[sourceHashParameter]
+	header.Operations = len(operations)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(header); err != nil {
+		return err
+	}
+	for _, operation := range operations {
+		if err := encoder.Encode(operation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fingerprint returns the canonical hash of a node, or "" for nil nodes.
+func fingerprint(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	return compiler.CanonicalHash(node)
+}
+
+// withoutKeys returns a copy of a mapping node without some of its keys.
+func withoutKeys(node *yaml.Node, keys ...string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return node
+	}
+	result := *node
+	result.Content = nil
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		skip := false
+		for _, key := range keys {
+			skip = skip || node.Content[i].Value == key
+		}
+		if !skip {
+			result.Content = append(result.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+	return &result
+}
+
+// mergeParameters returns the parameters of a path item followed by the
+// parameters of an operation. Operation parameters replace path item
+// parameters with the same name and location.
+func mergeParameters(pathParameters, operationParameters []ndjsonParameter) []ndjsonParameter {
+	var parameters []ndjsonParameter
+	for _, p := range pathParameters {
+		overridden := false
+		for _, o := range operationParameters {
+			overridden = overridden || (p.Ref == "" && o.Name == p.Name && o.In == p.In)
+		}
+		if !overridden {
+			parameters = append(parameters, p)
+		}
+	}
+	return append(parameters, operationParameters...)
+}
+
+func ndjsonOperationsV3(document *openapi_v3.Document) []*ndjsonOperation {
+	components := document.GetComponents()
+	parameter := func(p *openapi_v3.ParameterOrReference) ndjsonParameter {
+		if ref := p.GetReference().GetXRef(); ref != "" {
+			for _, pair := range components.GetParameters().GetAdditionalProperties() {
+				if ref == "#/components/parameters/"+pair.Name && pair.Value.GetParameter() != nil {
+					p = pair.Value
+				}
+			}
+			if p.GetParameter() == nil {
+				return ndjsonParameter{Ref: ref}
+			}
+		}
+		parameter := p.GetParameter()
+		result := ndjsonParameter{Name: parameter.Name, In: parameter.In, Required: parameter.Required}
+		if parameter.Schema != nil {
+			result.Schema = fingerprint(parameter.Schema.ToRawInfo())
+		}
+		return result
+	}
+	parameters := func(list []*openapi_v3.ParameterOrReference) []ndjsonParameter {
+		var result []ndjsonParameter
+		for _, p := range list {
+			result = append(result, parameter(p))
+		}
+		return result
+	}
+	content := func(mediaTypes *openapi_v3.MediaTypes) map[string]string {
+		result := make(map[string]string)
+		for _, pair := range mediaTypes.GetAdditionalProperties() {
+			if schema := pair.Value.GetSchema(); schema != nil {
+				result[pair.Name] = fingerprint(schema.ToRawInfo())
+			} else {
+				result[pair.Name] = ""
+			}
+		}
+		return result
+	}
+	var operations []*ndjsonOperation
+	openapi_v3.ForEachOperation(document, func(path, method string, item *openapi_v3.PathItem, operation *openapi_v3.Operation) {
+		record := &ndjsonOperation{
+			Kind:        "operation",
+			Path:        path,
+			Method:      strings.ToUpper(method),
+			OperationID: operation.OperationId,
+			Tags:        operation.Tags,
+			Deprecated:  operation.Deprecated,
+			Parameters:  mergeParameters(parameters(item.Parameters), parameters(operation.Parameters)),
+		}
+		requestBody := operation.GetRequestBody()
+		if ref := requestBody.GetReference().GetXRef(); ref != "" {
+			for _, pair := range components.GetRequestBodies().GetAdditionalProperties() {
+				if ref == "#/components/requestBodies/"+pair.Name {
+					requestBody = pair.Value
+				}
+			}
+		}
+		if body := requestBody.GetRequestBody(); body != nil {
+			record.RequestBody = content(body.Content)
+		}
+		response := func(r *openapi_v3.ResponseOrReference) map[string]string {
+			if ref := r.GetReference().GetXRef(); ref != "" {
+				for _, pair := range components.GetResponses().GetAdditionalProperties() {
+					if ref == "#/components/responses/"+pair.Name {
+						r = pair.Value
+					}
+				}
+			}
+			return content(r.GetResponse().GetContent())
+		}
+		if responses := operation.GetResponses(); responses != nil {
+			record.Responses = make(map[string]map[string]string)
+			if responses.Default != nil {
+				record.Responses["default"] = response(responses.Default)
+			}
+			for _, pair := range responses.ResponseOrReference {
+				record.Responses[pair.Name] = response(pair.Value)
+			}
+		}
+		operations = append(operations, record)
+	})
+	return operations
+}
+
+func ndjsonOperationsV2(document *openapi_v2.Document) []*ndjsonOperation {
+	parameter := func(p *openapi_v2.ParametersItem) ndjsonParameter {
+		if ref := p.GetJsonReference().GetXRef(); ref != "" {
+			var resolved *openapi_v2.Parameter
+			for _, pair := range document.GetParameters().GetAdditionalProperties() {
+				if ref == "#/parameters/"+pair.Name {
+					resolved = pair.Value
+				}
+			}
+			if resolved == nil {
+				return ndjsonParameter{Ref: ref}
+			}
+			p = &openapi_v2.ParametersItem{Oneof: &openapi_v2.ParametersItem_Parameter{Parameter: resolved}}
+		}
+		if body := p.GetParameter().GetBodyParameter(); body != nil {
+			return ndjsonParameter{Name: body.Name, In: "body", Required: body.Required, Schema: fingerprint(body.Schema.ToRawInfo())}
+		}
+		node := p.ToRawInfo()
+		name, _ := compiler.StringForScalarNode(compiler.MapValueForKey(node, "name"))
+		in, _ := compiler.StringForScalarNode(compiler.MapValueForKey(node, "in"))
+		required, _ := compiler.BoolForScalarNode(compiler.MapValueForKey(node, "required"))
+		return ndjsonParameter{
+			Name:     name,
+			In:       in,
+			Required: required,
+			Schema:   fingerprint(withoutKeys(node, "name", "in", "required", "description", "allowEmptyValue")),
+		}
+	}
+	parameters := func(list []*openapi_v2.ParametersItem) []ndjsonParameter {
+		var result []ndjsonParameter
+		for _, p := range list {
+			result = append(result, parameter(p))
+		}
+		return result
+	}
+	var operations []*ndjsonOperation
+	openapi_v2.ForEachOperation(document, func(path, method string, item *openapi_v2.PathItem, operation *openapi_v2.Operation) {
+		record := &ndjsonOperation{
+			Kind:        "operation",
+			Path:        path,
+			Method:      strings.ToUpper(method),
+			OperationID: operation.OperationId,
+			Tags:        operation.Tags,
+			Deprecated:  operation.Deprecated,
+			Parameters:  mergeParameters(parameters(item.Parameters), parameters(operation.Parameters)),
+		}
+		consumes := operation.Consumes
+		if len(consumes) == 0 {
+			consumes = document.Consumes
+		}
+		if len(consumes) == 0 {
+			consumes = []string{"application/json"}
+		}
+		for _, p := range record.Parameters {
+			if p.In != "body" {
+				continue
+			}
+			record.RequestBody = make(map[string]string)
+			for _, mediaType := range consumes {
+				record.RequestBody[mediaType] = p.Schema
+			}
+		}
+		produces := operation.Produces
+		if len(produces) == 0 {
+			produces = document.Produces
+		}
+		if len(produces) == 0 {
+			produces = []string{"application/json"}
+		}
+		if responses := operation.GetResponses(); responses != nil {
+			record.Responses = make(map[string]map[string]string)
+			for _, pair := range responses.ResponseCode {
+				response := pair.Value.GetResponse()
+				if ref := pair.Value.GetJsonReference().GetXRef(); ref != "" {
+					for _, named := range document.GetResponses().GetAdditionalProperties() {
+						if ref == "#/responses/"+named.Name {
+							response = named.Value
+						}
+					}
+				}
+				content := make(map[string]string)
+				if schema := response.GetSchema(); schema != nil {
+					for _, mediaType := range produces {
+						content[mediaType] = fingerprint(schema.ToRawInfo())
+					}
+				}
+				record.Responses[pair.Name] = content
+			}
+		}
+		operations = append(operations, record)
+	})
+	return operations
+}
@@ -0,0 +1,310 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	discovery_v1 "github.com/google/gnostic/discovery"
+	"github.com/google/gnostic/jsonwriter"
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+)
+
+// Document is a compiled API description: an *openapi_v2.Document,
+// an *openapi_v3.Document, or a *discovery_v1.Document.
+type Document = proto.Message
+
+// An OutputFunc writes a document to w. The parameters are the key=value
+// pairs of the invocation, as in --NAME-out=key=value,key=value:PATH.
+// Gnostic also passes the name of the source in the "source" parameter,
+// the hex-encoded SHA-256 hash of its contents in "source-hash" and,
+// if a header comment was given with --header-comment-file, the comment
+// in "header-comment" and, if a style was given with --json-out-style,
+// the style in "json-style".
+type OutputFunc func(doc Document, w io.Writer, params map[string]string) error
+
+// Names of the parameters that gnostic passes to outputs.
+const (
+	sourceParameter        = "source"
+	sourceHashParameter    = "source-hash"
+	headerCommentParameter = "header-comment"
+	jsonStyleParameter     = "json-style"
+)
+
+type output struct {
+	name    string
+	fn      OutputFunc
+	builtin bool
+}
+
+var (
+	outputsMutex sync.Mutex
+	outputs      []*output
+)
+
+// reservedOutputNames are options of the form --NAME-out that don't
+// serialize documents.
+var reservedOutputNames = map[string]bool{
+	"errors":   true,
+	"messages": true,
+	"trace":    true,
+}
+
+func init() {
+	registerOutput("pb", writeBinary, true)
+	registerOutput("text", writeText, true)
+	registerOutput("yaml", writeYAML, true)
+	registerOutput("json", writeJSON, true)
+	registerOutput("ndjson", writeNDJSON, true)
+	registerOutput("openapi2proto", writeProto, true)
+	registerOutput("deps", writeDeps, true)
+	registerOutput("stats", writeStats, true)
+	registerOutput("extensions", writeExtensions, true)
+	registerOutput("html", writeHTML, true)
+	registerOutput("coverage", writeCoverage, true)
+	registerOutput("fingerprints", writeFingerprints, true)
+	registerOutput("asyncapi", writeAsyncAPI, true)
+}
+
+// RegisterOutput registers a serializer that is run in-process with
+// --NAME-out=PATH. The output is written to PATH, or to a file named
+// after the source with the extension NAME if PATH is a directory.
+// Registered outputs take precedence over plugins with the same name.
+// It is an error to register a name that is already used by a built-in
+// option or another output.
+func RegisterOutput(name string, fn OutputFunc) error {
+	return registerOutput(name, fn, false)
+}
+
+func registerOutput(name string, fn OutputFunc, builtin bool) error {
+	if name == "" || strings.ContainsAny(name, ",:=") {
+		return fmt.Errorf("invalid output name %q", name)
+	}
+	if fn == nil {
+		return fmt.Errorf("output %s has no serializer", name)
+	}
+	outputsMutex.Lock()
+	defer outputsMutex.Unlock()
+	if reservedOutputNames[name] {
+		return fmt.Errorf("output %s conflicts with a built-in option", name)
+	}
+	for _, o := range outputs {
+		if o.name != name {
+			continue
+		}
+		if o.builtin {
+			return fmt.Errorf("output %s conflicts with a built-in output", name)
+		}
+		return fmt.Errorf("output %s is already registered", name)
+	}
+	outputs = append(outputs, &output{name: name, fn: fn, builtin: builtin})
+	return nil
+}
+
+// lookupOutput returns the serializer of a registered output, or nil.
+func lookupOutput(name string) OutputFunc {
+	outputsMutex.Lock()
+	defer outputsMutex.Unlock()
+	for _, o := range outputs {
+		if o.name == name {
+			return o.fn
+		}
+	}
+	return nil
+}
+
+// registeredOutputNames returns the names of all outputs in order of registration.
+func registeredOutputNames() []string {
+	outputsMutex.Lock()
+	defer outputsMutex.Unlock()
+	names := make([]string, len(outputs))
+	for i, o := range outputs {
+		names[i] = o.name
+	}
+	return names
+}
+
+// outputParametersRegex matches invocations that begin with key=value pairs.
+var outputParametersRegex = regexp.MustCompile(`^([\w\-/.]+=[\w\-/.]+(,[\w\-/.]+=[\w\-/.]+)*):(.+)$`)
+
+// splitOutputInvocation separates the parameters of an output invocation
+// from its path. Invocations without parameters are paths.
+func splitOutputInvocation(invocation string) (map[string]string, string) {
+	params := make(map[string]string)
+	m := outputParametersRegex.FindStringSubmatch(invocation)
+	if m == nil {
+		return params, invocation
+	}
+	for _, pair := range strings.Split(m[1], ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		params[kv[0]] = kv[1]
+	}
+	return params, m[3]
+}
+
+// setOutputCall sets the invocation of an output. Later options
+// replace earlier ones.
+func (g *Gnostic) setOutputCall(name, invocation string) {
+	for _, o := range g.outputCalls {
+		if o.Name == name {
+			o.Invocation = invocation
+			return
+		}
+	}
+	g.outputCalls = append(g.outputCalls, &outputCall{Name: name, Invocation: invocation})
+}
+
+func (g *Gnostic) hasOutputCall(name string) bool {
+	for _, o := range g.outputCalls {
+		if o.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// computeOutput runs a registered output and returns its result and the
+// location where it should be written.
+func (g *Gnostic) computeOutput(name, invocation string, message proto.Message) ([]byte, string, error) {
+	fn := lookupOutput(name)
+	params, path := splitOutputInvocation(invocation)
+	if fn == nil {
+		return nil, path, fmt.Errorf("unknown output %s", name)
+	}
+	for key, value := range map[string]string{
+		sourceParameter:        g.sourceName,
+		sourceHashParameter:    g.sourceHash,
+		headerCommentParameter: g.headerComment,
+		jsonStyleParameter:     g.jsonOutStyle,
+	} {
+		if _, ok := params[key]; !ok && value != "" {
+			params[key] = value
+		}
+	}
+	buffer := &outputBuffer{logger: g.logger()}
+	if err := fn(message, buffer, params); err != nil {
+		return nil, path, fmt.Errorf("%s output: %s", name, err.Error())
+	}
+	return buffer.Bytes(), path, nil
+}
+
+// An outputBuffer collects the bytes of an output. Built-in outputs log
+// warnings to its Logger.
+type outputBuffer struct {
+	bytes.Buffer
+	logger compiler.Logger
+}
+
+// outputLogger returns the Logger of an output writer, or the Logger that
+// was set with compiler.SetLogger if the writer has none.
+func outputLogger(w io.Writer) compiler.Logger {
+	if b, ok := w.(*outputBuffer); ok && b.logger != nil {
+		return b.logger
+	}
+	return compiler.CurrentLogger()
+}
+
+// Write a binary pb representation.
+func writeBinary(doc Document, w io.Writer, params map[string]string) error {
+	protoBytes, err := proto.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(protoBytes)
+	return err
+}
+
+// Write a text pb representation.
+func writeText(doc Document, w io.Writer, params map[string]string) error {
+	_, err := io.WriteString(w, commentLines(params[headerCommentParameter], "# ")+proto.MarshalTextString(doc))
+	return err
+}
+
+// Write a yaml API description.
+func writeYAML(doc Document, w io.Writer, params map[string]string) error {
+	rawInfo, err := rawInfoForDocument(doc)
+	if err != nil {
+		return err
+	}
+	bytes, err := yaml.Marshal(rawInfo)
+	if err != nil {
+		return err
+	}
+	if _, err = io.WriteString(w, commentLines(params[headerCommentParameter], "# ")); err != nil {
+		return err
+	}
+	_, err = w.Write(bytes)
+	return err
+}
+
+// Write a json API description.
+func writeJSON(doc Document, w io.Writer, params map[string]string) error {
+	style, err := jsonwriter.ParseStyle(params[jsonStyleParameter])
+	if err != nil {
+		return err
+	}
+	rawInfo, err := rawInfoForDocument(doc)
+	if err != nil {
+		return err
+	}
+	// Discovery documents have no extensions, so they get no comment.
+	switch doc.(type) {
+	case *openapi_v2.Document, *openapi_v3.Document:
+		rawInfo = &yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{withCommentKey(rawInfo, params[headerCommentParameter])},
+		}
+	}
+	bytes, err := jsonwriter.MarshalStyle(rawInfo, style)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bytes)
+	return err
+}
+
+// rawInfoForDocument converts a document into a yaml node.
+func rawInfoForDocument(doc Document) (*yaml.Node, error) {
+	var rawInfo *yaml.Node
+	switch document := doc.(type) {
+	case *openapi_v2.Document:
+		rawInfo = document.ToRawInfo()
+	case *openapi_v3.Document:
+		rawInfo = document.ToRawInfo()
+	case *discovery_v1.Document:
+		rawInfo = document.ToRawInfo()
+	}
+	if rawInfo == nil {
+		return nil, errors.New("no yaml available for this document")
+	}
+	if rawInfo.Kind != yaml.DocumentNode {
+		rawInfo = &yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{rawInfo},
+		}
+	}
+	return rawInfo, nil
+}
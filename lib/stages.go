@@ -0,0 +1,185 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// The statuses of outputs and plugins.
+const (
+	// OutputWritten is the status of an output that was written.
+	OutputWritten = "written"
+	// OutputFailed is the status of an output that failed to compute or
+	// to be written.
+	OutputFailed = "failed"
+	// OutputSkipped is the status of an output that was not written because
+	// another output failed or that was not run because of --fail-fast.
+	OutputSkipped = "skipped"
+)
+
+// OutputStatus describes what happened to an output or a plugin.
+type OutputStatus struct {
+	// Name is the name of the output or "plugin NAME" for a plugin.
+	Name string
+	// Path is the location of the output.
+	Path string
+	// Status is OutputWritten, OutputFailed or OutputSkipped.
+	Status string
+	// Err is the error of a failed output.
+	Err error
+}
+
+func (s *OutputStatus) String() string {
+	result := s.Name
+	if s.Path != "" {
+		result += " (" + s.Path + ")"
+	}
+	result += ": " + s.Status
+	if s.Err != nil {
+		result += ": " + s.Err.Error()
+	}
+	return result
+}
+
+// OutputStatuses returns the status of each output and plugin of the most
+// recently compiled document.
+func (g *Gnostic) OutputStatuses() []*OutputStatus {
+	return g.outputStatuses
+}
+
+// An outputStage is an output or a plugin whose result is computed in
+// memory and written once all stages have been computed.
+type outputStage struct {
+	status *OutputStatus
+	// phase is the trace phase of the computation of the stage.
+	phase string
+	// compute computes the result of the stage and returns a function that
+	// writes it.
+	compute func() (write func() error, err error)
+	write   func() error
+}
+
+// outputStages returns the stages of the registered outputs, in the order
+// in which they were registered, followed by the stages of the plugins.
+// The messages of the plugins are appended to messages.
+func (g *Gnostic) outputStages(message proto.Message, messages *[]*plugins.Message) []*outputStage {
+	stages := make([]*outputStage, 0)
+	for _, name := range registeredOutputNames() {
+		for _, o := range g.outputCalls {
+			if o.Name != name {
+				continue
+			}
+			o := o
+			_, path := splitOutputInvocation(o.Invocation)
+			stages = append(stages, &outputStage{
+				status: &OutputStatus{Name: o.Name, Path: path},
+				phase:  "serialization",
+				compute: func() (func() error, error) {
+					bytes, path, err := g.computeOutput(o.Name, o.Invocation, message)
+					if err != nil {
+						return nil, err
+					}
+					return func() error {
+						return g.writeFile(path, bytes, g.sourceName, o.Name)
+					}, nil
+				},
+			})
+		}
+	}
+	for _, p := range g.pluginCalls {
+		p := p
+		stages = append(stages, &outputStage{
+			status: &OutputStatus{Name: "plugin " + p.Name},
+			phase:  "plugins",
+			compute: func() (func() error, error) {
+				response, outputLocation, err := p.perform(message, g.sourceFormat, g.sourceName, g.timePlugins, g.excludeSurface, g.logger())
+				if response != nil {
+					*messages = append(*messages, response.Messages...)
+				}
+				if err != nil {
+					return nil, err
+				}
+				return func() error {
+					return plugins.HandleResponse(response, outputLocation)
+				}, nil
+			},
+		})
+	}
+	return stages
+}
+
+// runOutputStages computes the results of all outputs and plugins and then
+// writes them. Unless --keep-partial is set, nothing is written if any of
+// them fails. With --fail-fast, no stage is computed after the first one
+// that fails. It returns the messages of the plugins and the errors of the
+// stages that failed.
+func (g *Gnostic) runOutputStages(message proto.Message) ([]*plugins.Message, []error) {
+	messages := make([]*plugins.Message, 0)
+	errors := make([]error, 0)
+	stages := g.outputStages(message, &messages)
+	for _, s := range stages {
+		if len(errors) > 0 && g.failFast {
+			break
+		}
+		endPhase := g.trace.StartPhase(s.phase)
+		write, err := s.compute()
+		endPhase()
+		if err != nil {
+			s.status.Status = OutputFailed
+			s.status.Err = err
+			errors = append(errors, err)
+			continue
+		}
+		s.write = write
+	}
+	failed := len(errors) > 0
+	endPhase := g.trace.StartPhase("serialization")
+	for _, s := range stages {
+		if s.status.Status == OutputFailed {
+			continue
+		}
+		if s.write == nil || (failed && !g.keepPartial) {
+			s.status.Status = OutputSkipped
+			continue
+		}
+		if err := s.write(); err != nil {
+			s.status.Status = OutputFailed
+			s.status.Err = err
+			errors = append(errors, err)
+			continue
+		}
+		s.status.Status = OutputWritten
+	}
+	endPhase()
+	g.outputStatuses = make([]*OutputStatus, 0)
+	for _, s := range stages {
+		g.outputStatuses = append(g.outputStatuses, s.status)
+	}
+	return messages, errors
+}
+
+// writeOutputSummary writes the status of each output and plugin.
+func (g *Gnostic) writeOutputSummary(w io.Writer) {
+	fmt.Fprintln(w, "Outputs:")
+	for _, status := range g.outputStatuses {
+		fmt.Fprintf(w, "  %s\n", status)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+)
+
+// The stats output writes counts that measure the surface of an API: its
+// paths, operations, schemas (component schemas or definitions), vendor
+// extensions, and deprecated operations. With a "format=csv" parameter, it
+// writes a header row and a row for the source, so that the counts of
+// successive versions of an API can be collected in a spreadsheet or a
+// time-series database. Otherwise it writes one "name: count" line for
+// each count.
+
+// Stats are the counts of the stats output.
+type Stats struct {
+	Paths                int
+	Operations           int
+	Schemas              int
+	Extensions           int
+	DeprecatedOperations int
+}
+
+// statsColumns are the columns of the csv format of the stats output.
+var statsColumns = []string{"file", "paths", "operations", "schemas", "extensions", "deprecated_operations"}
+
+// ComputeStats returns the counts of an OpenAPI v2 or v3 document.
+func ComputeStats(doc Document) (*Stats, error) {
+	stats := &Stats{}
+	switch document := doc.(type) {
+	case *openapi_v2.Document:
+		stats.Paths = len(document.GetPaths().GetPath())
+		openapi_v2.ForEachOperation(document, func(path, method string, item *openapi_v2.PathItem, operation *openapi_v2.Operation) {
+			stats.Operations++
+			if operation.Deprecated {
+				stats.DeprecatedOperations++
+			}
+		})
+		stats.Schemas = len(document.GetDefinitions().GetAdditionalProperties())
+	case *openapi_v3.Document:
+		stats.Paths = len(document.GetPaths().GetPath())
+		openapi_v3.ForEachOperation(document, func(path, method string, item *openapi_v3.PathItem, operation *openapi_v3.Operation) {
+			stats.Operations++
+			if operation.Deprecated {
+				stats.DeprecatedOperations++
+			}
+		})
+		stats.Schemas = len(document.GetComponents().GetSchemas().GetAdditionalProperties())
+	default:
+		return nil, errors.New("statistics can only be computed for OpenAPI documents")
+	}
+	stats.Extensions = countExtensions(proto.MessageReflect(doc))
+	return stats, nil
+}
+
+// countExtensions returns the number of vendor extensions of a message and
+// of the messages that it contains.
+func countExtensions(m protoreflect.Message) int {
+	count := 0
+	m.Range(func(field protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case field.Name() == "specification_extension" || field.Name() == "vendor_extension":
+			count += v.List().Len()
+		case field.Kind() != protoreflect.MessageKind:
+		case field.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				count += countExtensions(list.Get(i).Message())
+			}
+		case !field.IsMap():
+			count += countExtensions(v.Message())
+		}
+		return true
+	})
+	return count
+}
+
+// Write the counts of a document.
+func writeStats(doc Document, w io.Writer, params map[string]string) error {
+	stats, err := ComputeStats(doc)
+	if err != nil {
+		return err
+	}
+	values := []int{stats.Paths, stats.Operations, stats.Schemas, stats.Extensions, stats.DeprecatedOperations}
+	switch format := params["format"]; format {
+	case "csv":
+		row := []string{params[sourceParameter]}
+		for _, value := range values {
+			row = append(row, strconv.Itoa(value))
+		}
+		writer := csv.NewWriter(w)
+		if err := writer.WriteAll([][]string{statsColumns, row}); err != nil {
+			return err
+		}
+	case "", "text":
+		for i, value := range values {
+			if _, err := fmt.Fprintf(w, "%s: %d\n", statsColumns[i+1], value); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported stats format: %s", format)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/google/gnostic/compiler"
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// warningsForDocument returns warnings about problems in a document that
+// don't prevent compilation.
+func warningsForDocument(message proto.Message) []*plugins.Message {
+	warnings := make([]*plugins.Message, 0)
+	operationIds := make(map[string][]string)
+	check := func(path, method string, deprecated bool, description, operationID string) {
+		if deprecated && description == "" {
+			warnings = append(warnings, deprecatedWithoutDescription(path, method))
+		}
+		if operationID == "" {
+			return
+		}
+		if first, ok := operationIds[operationID]; ok {
+			warnings = append(warnings, duplicateOperationID(path, method, operationID, first))
+		} else {
+			operationIds[operationID] = []string{path, method}
+		}
+	}
+	switch document := message.(type) {
+	case *openapi_v2.Document:
+		openapi_v2.ForEachOperation(document, func(path, method string, _ *openapi_v2.PathItem, o *openapi_v2.Operation) {
+			check(path, method, o.Deprecated, o.Description, o.OperationId)
+		})
+		for _, problem := range openapi_v2.CheckSchemas(document) {
+			warnings = append(warnings, inconsistentSchema(problem.Keys, problem.Message))
+		}
+	case *openapi_v3.Document:
+		openapi_v3.ForEachOperation(document, func(path, method string, _ *openapi_v3.PathItem, o *openapi_v3.Operation) {
+			check(path, method, o.Deprecated, o.Description, o.OperationId)
+		})
+		for _, problem := range openapi_v3.CheckSchemas(document) {
+			warnings = append(warnings, inconsistentSchema(problem.Keys, problem.Message))
+		}
+		for _, problem := range openapi_v3.CheckServers(document) {
+			warnings = append(warnings, invalidServer(problem.Keys, problem.Message))
+		}
+	}
+	return warnings
+}
+
+// deprecatedWithoutDescription warns about a deprecated operation that
+// doesn't explain its deprecation.
+func deprecatedWithoutDescription(path, method string) *plugins.Message {
+	return &plugins.Message{
+		Level: plugins.Message_WARNING,
+		Code:  compiler.CodeDeprecatedWithoutDescription,
+		Text:  "deprecated operation has no description",
+		Keys:  []string{"paths", path, method},
+	}
+}
+
+// inconsistentSchema warns about schema keywords that contradict each other.
+func inconsistentSchema(keys []string, text string) *plugins.Message {
+	return &plugins.Message{
+		Level: plugins.Message_WARNING,
+		Code:  compiler.CodeInconsistentSchema,
+		Text:  text,
+		Keys:  keys,
+	}
+}
+
+// invalidServer warns about a server with a malformed URL or a URL that
+// doesn't match its variables.
+func invalidServer(keys []string, text string) *plugins.Message {
+	return &plugins.Message{
+		Level: plugins.Message_WARNING,
+		Code:  compiler.CodeInvalidServer,
+		Text:  text,
+		Keys:  keys,
+	}
+}
+
+// duplicateOperationID warns about an operation whose operationId is
+// also used by an earlier operation.
+func duplicateOperationID(path, method, operationID string, first []string) *plugins.Message {
+	return &plugins.Message{
+		Level: plugins.Message_WARNING,
+		Code:  compiler.CodeDuplicateOperationID,
+		Text:  fmt.Sprintf("operationId %s is also used by %s %s", operationID, strings.ToUpper(first[1]), first[0]),
+		Keys:  []string{"paths", path, method, "operationId"},
+	}
+}
+
+// warningsForDuplicates returns warnings for inline values that could be
+// replaced by references to components.
+func warningsForDuplicates(duplicates []*openapi_v3.DuplicateComponent) []*plugins.Message {
+	warnings := make([]*plugins.Message, 0, len(duplicates))
+	for _, d := range duplicates {
+		warnings = append(warnings, &plugins.Message{
+			Level: plugins.Message_WARNING,
+			Code:  compiler.CodeDuplicateInlineComponent,
+			Text:  fmt.Sprintf("%s is defined inline %d times and could be replaced with references to %s", strings.TrimSuffix(d.Section, "s"), d.Count, d.Ref()),
+			Keys:  d.Keys,
+		})
+	}
+	return warnings
+}
+
+// warningsForExamples returns warnings for examples that couldn't be resolved.
+func warningsForExamples(exampleWarnings []*openapi_v3.ExampleWarning) []*plugins.Message {
+	warnings := make([]*plugins.Message, 0, len(exampleWarnings))
+	for _, w := range exampleWarnings {
+		warnings = append(warnings, &plugins.Message{
+			Level: plugins.Message_WARNING,
+			Code:  compiler.CodeUnresolvedExample,
+			Text:  w.Message,
+			Keys:  w.Keys,
+		})
+	}
+	return warnings
+}
+
+// warningsForInvalidExamples returns errors for examples that don't
+// conform to their schemas.
+func warningsForInvalidExamples(exampleErrors []*openapi_v3.ExampleError) []*plugins.Message {
+	warnings := make([]*plugins.Message, 0, len(exampleErrors))
+	for _, e := range exampleErrors {
+		text := e.Message
+		if e.Pointer != "" {
+			text = e.Pointer + ": " + e.Message
+		}
+		warnings = append(warnings, &plugins.Message{
+			Level: plugins.Message_ERROR,
+			Code:  compiler.CodeInvalidExample,
+			Text:  text,
+			Keys:  e.Keys,
+		})
+	}
+	return warnings
+}
+
+// warningsForGRPCMismatches returns errors for the mismatches between a
+// document and grpc-gateway bindings.
+func warningsForGRPCMismatches(mismatches []*GRPCMismatch) []*plugins.Message {
+	warnings := make([]*plugins.Message, 0, len(mismatches))
+	for _, m := range mismatches {
+		warnings = append(warnings, &plugins.Message{
+			Level: plugins.Message_ERROR,
+			Code:  compiler.CodeGRPCMismatch,
+			Text:  fmt.Sprintf("%s (openapi: %s, grpc: %s)", m.Text, m.OpenAPI, m.GRPC),
+			Keys:  m.Keys,
+		})
+	}
+	return warnings
+}
+
+// sourceSuffix returns the suffix of printed findings in components that
+// record where they were originally defined with x-gnostic-source
+// extensions, so that findings can be attributed to their owners.
+func sourceSuffix(message proto.Message, keys []string) string {
+	if document, ok := message.(*openapi_v3.Document); ok {
+		if source := openapi_v3.SourceForKeys(document, keys); source != "" {
+			return " (from " + source + ")"
+		}
+	}
+	return ""
+}
+
+// warningString returns a plain text description of a warning.
+func warningString(warning *plugins.Message, file string) string {
+	kind := compiler.MessageKindWarning
+	if warning.Level >= plugins.Message_ERROR {
+		kind = compiler.MessageKindError
+	}
+	m := &compiler.Message{
+		Kind:    kind,
+		Message: warning.Text,
+		Context: strings.Join(warning.Keys, "."),
+		File:    file,
+	}
+	return m.String()
+}
@@ -26,6 +26,7 @@ import (
 )
 
 func checkPathsV2(document *openapiv2.Document, messages []*plugins.Message) []*plugins.Message {
+	paths := make([]string, 0, len(document.Paths.Path))
 	for _, pair := range document.Paths.Path {
 		messages = append(messages,
 			&plugins.Message{
@@ -33,11 +34,13 @@ func checkPathsV2(document *openapiv2.Document, messages []*plugins.Message) []*
 				Code:  "PATH",
 				Text:  pair.Name,
 				Keys:  []string{"paths", pair.Name}})
+		paths = append(paths, pair.Name)
 	}
-	return messages
+	return append(messages, checkPathTemplates(paths)...)
 }
 
 func checkPathsV3(document *openapiv3.Document, messages []*plugins.Message) []*plugins.Message {
+	paths := make([]string, 0, len(document.Paths.Path))
 	for _, pair := range document.Paths.Path {
 		messages = append(messages,
 			&plugins.Message{
@@ -45,8 +48,9 @@ func checkPathsV3(document *openapiv3.Document, messages []*plugins.Message) []*
 				Code:  "PATH",
 				Text:  pair.Name,
 				Keys:  []string{"paths", pair.Name}})
+		paths = append(paths, pair.Name)
 	}
-	return messages
+	return append(messages, checkPathTemplates(paths)...)
 }
 
 func main() {
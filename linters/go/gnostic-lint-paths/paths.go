@@ -0,0 +1,101 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	plugins "github.com/google/gnostic/plugins"
+)
+
+var parameterPattern = regexp.MustCompile(`{[^}]*}`)
+
+// A pathSegment is a part of a path template between slashes.
+// Segments that contain parameters, e.g. "{id}" or "{name}.{ext}",
+// have the names of the parameters in parameters.
+type pathSegment struct {
+	text       string
+	parameters []string
+}
+
+// pathTemplate is a parsed path template, e.g. "/pets/{petId}".
+type pathTemplate struct {
+	path          string
+	segments      []pathSegment
+	trailingSlash bool
+}
+
+// parsePathTemplate splits a path template into segments.
+func parsePathTemplate(path string) *pathTemplate {
+	t := &pathTemplate{path: path}
+	trimmed := strings.Trim(path, "/")
+	t.trailingSlash = len(trimmed) > 0 && strings.HasSuffix(path, "/")
+	if trimmed == "" {
+		return t
+	}
+	for _, text := range strings.Split(trimmed, "/") {
+		segment := pathSegment{text: text}
+		for _, match := range parameterPattern.FindAllString(text, -1) {
+			segment.parameters = append(segment.parameters, match[1:len(match)-1])
+		}
+		t.segments = append(t.segments, segment)
+	}
+	return t
+}
+
+// erased returns the template with parameter names removed. Templates with
+// the same erased form match the same request paths.
+func (t *pathTemplate) erased() string {
+	parts := make([]string, len(t.segments))
+	for i, segment := range t.segments {
+		parts[i] = parameterPattern.ReplaceAllString(segment.text, "{}")
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// checkPathTemplates reports paths that can't be distinguished from earlier
+// paths, either because they differ only in parameter names or only by a
+// trailing slash.
+func checkPathTemplates(paths []string) []*plugins.Message {
+	messages := make([]*plugins.Message, 0)
+	templates := make([]*pathTemplate, 0, len(paths))
+	for _, path := range paths {
+		t := parsePathTemplate(path)
+		for _, other := range templates {
+			if t.erased() != other.erased() {
+				continue
+			}
+			if t.trailingSlash == other.trailingSlash {
+				messages = append(messages, &plugins.Message{
+					Level: plugins.Message_ERROR,
+					Code:  "PATH_CONFLICT",
+					Text:  fmt.Sprintf("paths %s and %s differ only in parameter names", other.path, t.path),
+					Keys:  []string{"paths", t.path},
+				})
+			} else {
+				messages = append(messages, &plugins.Message{
+					Level: plugins.Message_WARNING,
+					Code:  "PATH_TRAILING_SLASH",
+					Text:  fmt.Sprintf("paths %s and %s differ only by a trailing slash", other.path, t.path),
+					Keys:  []string{"paths", t.path},
+				})
+			}
+		}
+		templates = append(templates, t)
+	}
+	return messages
+}
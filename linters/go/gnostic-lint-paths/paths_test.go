@@ -0,0 +1,52 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	plugins "github.com/google/gnostic/plugins"
+)
+
+func TestCheckPathTemplates(t *testing.T) {
+	messages := checkPathTemplates([]string{
+		"/pets",
+		"/pets/{id}",
+		"/pets/{petId}",
+		"/pets/",
+		"/pets/mine",
+		"/files/{name}.{ext}",
+		"/files/{file}.{type}/",
+		"/",
+	})
+	expected := []struct {
+		level plugins.Message_Level
+		code  string
+		text  string
+	}{
+		{plugins.Message_ERROR, "PATH_CONFLICT", "paths /pets/{id} and /pets/{petId} differ only in parameter names"},
+		{plugins.Message_WARNING, "PATH_TRAILING_SLASH", "paths /pets and /pets/ differ only by a trailing slash"},
+		{plugins.Message_WARNING, "PATH_TRAILING_SLASH", "paths /files/{name}.{ext} and /files/{file}.{type}/ differ only by a trailing slash"},
+	}
+	if len(messages) != len(expected) {
+		t.Fatalf("unexpected messages: %+v", messages)
+	}
+	for i, e := range expected {
+		m := messages[i]
+		if m.Level != e.level || m.Code != e.code || m.Text != e.text {
+			t.Errorf("unexpected message: %+v (expected %s %s)", m, e.code, e.text)
+		}
+	}
+}
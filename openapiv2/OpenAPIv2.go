@@ -61,7 +61,7 @@ func NewAdditionalPropertiesItem(in *yaml.Node, context *compiler.Context) (*Add
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid AdditionalPropertiesItem")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -83,20 +83,20 @@ func NewApiKeySecurity(in *yaml.Node, context *compiler.Context) (*ApiKeySecurit
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"in", "name", "type"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"description", "in", "name", "type"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string type = 1;
 		v1 := compiler.MapValueForKey(m, "type")
@@ -104,13 +104,13 @@ func NewApiKeySecurity(in *yaml.Node, context *compiler.Context) (*ApiKeySecurit
 			x.Type, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [apiKey]
 			if ok && !compiler.StringArrayContainsValue([]string{"apiKey"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string name = 2;
@@ -119,7 +119,7 @@ func NewApiKeySecurity(in *yaml.Node, context *compiler.Context) (*ApiKeySecurit
 			x.Name, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string in = 3;
@@ -128,13 +128,13 @@ func NewApiKeySecurity(in *yaml.Node, context *compiler.Context) (*ApiKeySecurit
 			x.In, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [header query]
 			if ok && !compiler.StringArrayContainsValue([]string{"header", "query"}, x.In) {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 4;
@@ -143,7 +143,7 @@ func NewApiKeySecurity(in *yaml.Node, context *compiler.Context) (*ApiKeySecurit
 			x.Description, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 5;
@@ -188,20 +188,20 @@ func NewBasicAuthenticationSecurity(in *yaml.Node, context *compiler.Context) (*
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"type"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"description", "type"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string type = 1;
 		v1 := compiler.MapValueForKey(m, "type")
@@ -209,13 +209,13 @@ func NewBasicAuthenticationSecurity(in *yaml.Node, context *compiler.Context) (*
 			x.Type, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [basic]
 			if ok && !compiler.StringArrayContainsValue([]string{"basic"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 2;
@@ -224,7 +224,7 @@ func NewBasicAuthenticationSecurity(in *yaml.Node, context *compiler.Context) (*
 			x.Description, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 3;
@@ -269,20 +269,20 @@ func NewBodyParameter(in *yaml.Node, context *compiler.Context) (*BodyParameter,
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"in", "name", "schema"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"description", "in", "name", "required", "schema"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string description = 1;
 		v1 := compiler.MapValueForKey(m, "description")
@@ -290,7 +290,7 @@ func NewBodyParameter(in *yaml.Node, context *compiler.Context) (*BodyParameter,
 			x.Description, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string name = 2;
@@ -299,7 +299,7 @@ func NewBodyParameter(in *yaml.Node, context *compiler.Context) (*BodyParameter,
 			x.Name, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string in = 3;
@@ -308,13 +308,13 @@ func NewBodyParameter(in *yaml.Node, context *compiler.Context) (*BodyParameter,
 			x.In, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [body]
 			if ok && !compiler.StringArrayContainsValue([]string{"body"}, x.In) {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool required = 4;
@@ -323,7 +323,7 @@ func NewBodyParameter(in *yaml.Node, context *compiler.Context) (*BodyParameter,
 			x.Required, ok = compiler.BoolForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Schema schema = 5;
@@ -377,14 +377,16 @@ func NewContact(in *yaml.Node, context *compiler.Context) (*Contact, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"email", "name", "url"}
-		allowedPatterns := []*regexp.Regexp{pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"email", "name", "url"}
+			allowedPatterns := []*regexp.Regexp{pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -392,7 +394,7 @@ func NewContact(in *yaml.Node, context *compiler.Context) (*Contact, error) {
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string url = 2;
@@ -401,7 +403,7 @@ func NewContact(in *yaml.Node, context *compiler.Context) (*Contact, error) {
 			x.Url, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for url: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string email = 3;
@@ -410,7 +412,7 @@ func NewContact(in *yaml.Node, context *compiler.Context) (*Contact, error) {
 			x.Email, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for email: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 4;
@@ -455,7 +457,7 @@ func NewDefault(in *yaml.Node, context *compiler.Context) (*Default, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedAny additional_properties = 1;
 		// MAP: Any
@@ -497,7 +499,7 @@ func NewDefinitions(in *yaml.Node, context *compiler.Context) (*Definitions, err
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedSchema additional_properties = 1;
 		// MAP: Schema
@@ -527,20 +529,22 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"info", "paths", "swagger"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"basePath", "consumes", "definitions", "externalDocs", "host", "info", "parameters", "paths", "produces", "responses", "schemes", "security", "securityDefinitions", "swagger", "tags"}
-		allowedPatterns := []*regexp.Regexp{pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"info", "paths", "swagger"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"basePath", "consumes", "definitions", "externalDocs", "host", "info", "parameters", "paths", "produces", "responses", "schemes", "security", "securityDefinitions", "swagger", "tags"}
+			allowedPatterns := []*regexp.Regexp{pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string swagger = 1;
 		v1 := compiler.MapValueForKey(m, "swagger")
@@ -548,13 +552,13 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Swagger, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for swagger: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [2.0]
 			if ok && !compiler.StringArrayContainsValue([]string{"2.0"}, x.Swagger) {
 				message := fmt.Sprintf("has unexpected value for swagger: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Info info = 2;
@@ -572,7 +576,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Host, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for host: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string base_path = 4;
@@ -581,7 +585,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.BasePath, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for basePath: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string schemes = 5;
@@ -592,13 +596,13 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 				x.Schemes = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for schemes: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [http https ws wss]
 			if ok && !compiler.StringArrayContainsValues([]string{"http", "https", "ws", "wss"}, x.Schemes) {
 				message := fmt.Sprintf("has unexpected value for schemes: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string consumes = 6;
@@ -609,7 +613,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 				x.Consumes = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for consumes: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string produces = 7;
@@ -620,7 +624,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 				x.Produces = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for produces: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Paths paths = 8;
@@ -751,7 +755,7 @@ func NewExamples(in *yaml.Node, context *compiler.Context) (*Examples, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedAny additional_properties = 1;
 		// MAP: Any
@@ -793,20 +797,22 @@ func NewExternalDocs(in *yaml.Node, context *compiler.Context) (*ExternalDocs, e
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"url"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"description", "url"}
-		allowedPatterns := []*regexp.Regexp{pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"url"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"description", "url"}
+			allowedPatterns := []*regexp.Regexp{pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string description = 1;
 		v1 := compiler.MapValueForKey(m, "description")
@@ -814,7 +820,7 @@ func NewExternalDocs(in *yaml.Node, context *compiler.Context) (*ExternalDocs, e
 			x.Description, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string url = 2;
@@ -823,7 +829,7 @@ func NewExternalDocs(in *yaml.Node, context *compiler.Context) (*ExternalDocs, e
 			x.Url, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for url: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 3;
@@ -868,20 +874,20 @@ func NewFileSchema(in *yaml.Node, context *compiler.Context) (*FileSchema, error
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"type"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"default", "description", "example", "externalDocs", "format", "readOnly", "required", "title", "type"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string format = 1;
 		v1 := compiler.MapValueForKey(m, "format")
@@ -889,7 +895,7 @@ func NewFileSchema(in *yaml.Node, context *compiler.Context) (*FileSchema, error
 			x.Format, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for format: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string title = 2;
@@ -898,7 +904,7 @@ func NewFileSchema(in *yaml.Node, context *compiler.Context) (*FileSchema, error
 			x.Title, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for title: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -907,7 +913,7 @@ func NewFileSchema(in *yaml.Node, context *compiler.Context) (*FileSchema, error
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Any default = 4;
@@ -927,7 +933,7 @@ func NewFileSchema(in *yaml.Node, context *compiler.Context) (*FileSchema, error
 				x.Required = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string type = 6;
@@ -936,13 +942,13 @@ func NewFileSchema(in *yaml.Node, context *compiler.Context) (*FileSchema, error
 			x.Type, ok = compiler.StringForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [file]
 			if ok && !compiler.StringArrayContainsValue([]string{"file"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool read_only = 7;
@@ -951,7 +957,7 @@ func NewFileSchema(in *yaml.Node, context *compiler.Context) (*FileSchema, error
 			x.ReadOnly, ok = compiler.BoolForScalarNode(v7)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for readOnly: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// ExternalDocs external_docs = 8;
@@ -1014,14 +1020,14 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		allowedKeys := []string{"allowEmptyValue", "collectionFormat", "default", "description", "enum", "exclusiveMaximum", "exclusiveMinimum", "format", "in", "items", "maxItems", "maxLength", "maximum", "minItems", "minLength", "minimum", "multipleOf", "name", "pattern", "required", "type", "uniqueItems"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// bool required = 1;
 		v1 := compiler.MapValueForKey(m, "required")
@@ -1029,7 +1035,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.Required, ok = compiler.BoolForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string in = 2;
@@ -1038,13 +1044,13 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.In, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [formData]
 			if ok && !compiler.StringArrayContainsValue([]string{"formData"}, x.In) {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -1053,7 +1059,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string name = 4;
@@ -1062,7 +1068,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.Name, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool allow_empty_value = 5;
@@ -1071,7 +1077,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.AllowEmptyValue, ok = compiler.BoolForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for allowEmptyValue: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string type = 6;
@@ -1080,13 +1086,13 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.Type, ok = compiler.StringForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [string number boolean integer array file]
 			if ok && !compiler.StringArrayContainsValue([]string{"string", "number", "boolean", "integer", "array", "file"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string format = 7;
@@ -1095,7 +1101,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.Format, ok = compiler.StringForScalarNode(v7)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for format: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// PrimitivesItems items = 8;
@@ -1113,13 +1119,13 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.CollectionFormat, ok = compiler.StringForScalarNode(v9)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [csv ssv tsv pipes multi]
 			if ok && !compiler.StringArrayContainsValue([]string{"csv", "ssv", "tsv", "pipes", "multi"}, x.CollectionFormat) {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Any default = 10;
@@ -1139,7 +1145,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 				x.Maximum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for maximum: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_maximum = 12;
@@ -1148,7 +1154,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.ExclusiveMaximum, ok = compiler.BoolForScalarNode(v12)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMaximum: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// float minimum = 13;
@@ -1159,7 +1165,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 				x.Minimum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for minimum: %s", compiler.Display(v13))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_minimum = 14;
@@ -1168,7 +1174,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.ExclusiveMinimum, ok = compiler.BoolForScalarNode(v14)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMinimum: %s", compiler.Display(v14))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_length = 15;
@@ -1179,7 +1185,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 				x.MaxLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxLength: %s", compiler.Display(v15))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_length = 16;
@@ -1190,7 +1196,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 				x.MinLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minLength: %s", compiler.Display(v16))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string pattern = 17;
@@ -1199,7 +1205,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.Pattern, ok = compiler.StringForScalarNode(v17)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for pattern: %s", compiler.Display(v17))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_items = 18;
@@ -1210,7 +1216,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 				x.MaxItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxItems: %s", compiler.Display(v18))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_items = 19;
@@ -1221,7 +1227,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 				x.MinItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minItems: %s", compiler.Display(v19))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool unique_items = 20;
@@ -1230,7 +1236,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 			x.UniqueItems, ok = compiler.BoolForScalarNode(v20)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for uniqueItems: %s", compiler.Display(v20))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated Any enum = 21;
@@ -1257,7 +1263,7 @@ func NewFormDataParameterSubSchema(in *yaml.Node, context *compiler.Context) (*F
 				x.MultipleOf = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for multipleOf: %s", compiler.Display(v22))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 23;
@@ -1302,20 +1308,22 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"type"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"collectionFormat", "default", "description", "enum", "exclusiveMaximum", "exclusiveMinimum", "format", "items", "maxItems", "maxLength", "maximum", "minItems", "minLength", "minimum", "multipleOf", "pattern", "type", "uniqueItems"}
-		allowedPatterns := []*regexp.Regexp{pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"type"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"collectionFormat", "default", "description", "enum", "exclusiveMaximum", "exclusiveMinimum", "format", "items", "maxItems", "maxLength", "maximum", "minItems", "minLength", "minimum", "multipleOf", "pattern", "type", "uniqueItems"}
+			allowedPatterns := []*regexp.Regexp{pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string type = 1;
 		v1 := compiler.MapValueForKey(m, "type")
@@ -1323,13 +1331,13 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.Type, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [string number integer boolean array]
 			if ok && !compiler.StringArrayContainsValue([]string{"string", "number", "integer", "boolean", "array"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string format = 2;
@@ -1338,7 +1346,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.Format, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for format: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// PrimitivesItems items = 3;
@@ -1356,13 +1364,13 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.CollectionFormat, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [csv ssv tsv pipes]
 			if ok && !compiler.StringArrayContainsValue([]string{"csv", "ssv", "tsv", "pipes"}, x.CollectionFormat) {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Any default = 5;
@@ -1382,7 +1390,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 				x.Maximum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for maximum: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_maximum = 7;
@@ -1391,7 +1399,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.ExclusiveMaximum, ok = compiler.BoolForScalarNode(v7)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMaximum: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// float minimum = 8;
@@ -1402,7 +1410,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 				x.Minimum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for minimum: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_minimum = 9;
@@ -1411,7 +1419,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.ExclusiveMinimum, ok = compiler.BoolForScalarNode(v9)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMinimum: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_length = 10;
@@ -1422,7 +1430,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 				x.MaxLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxLength: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_length = 11;
@@ -1433,7 +1441,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 				x.MinLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minLength: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string pattern = 12;
@@ -1442,7 +1450,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.Pattern, ok = compiler.StringForScalarNode(v12)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for pattern: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_items = 13;
@@ -1453,7 +1461,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 				x.MaxItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxItems: %s", compiler.Display(v13))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_items = 14;
@@ -1464,7 +1472,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 				x.MinItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minItems: %s", compiler.Display(v14))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool unique_items = 15;
@@ -1473,7 +1481,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.UniqueItems, ok = compiler.BoolForScalarNode(v15)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for uniqueItems: %s", compiler.Display(v15))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated Any enum = 16;
@@ -1500,7 +1508,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 				x.MultipleOf = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for multipleOf: %s", compiler.Display(v17))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 18;
@@ -1509,7 +1517,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.Description, ok = compiler.StringForScalarNode(v18)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v18))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 19;
@@ -1554,14 +1562,14 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		allowedKeys := []string{"collectionFormat", "default", "description", "enum", "exclusiveMaximum", "exclusiveMinimum", "format", "in", "items", "maxItems", "maxLength", "maximum", "minItems", "minLength", "minimum", "multipleOf", "name", "pattern", "required", "type", "uniqueItems"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// bool required = 1;
 		v1 := compiler.MapValueForKey(m, "required")
@@ -1569,7 +1577,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 			x.Required, ok = compiler.BoolForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string in = 2;
@@ -1578,13 +1586,13 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 			x.In, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [header]
 			if ok && !compiler.StringArrayContainsValue([]string{"header"}, x.In) {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -1593,7 +1601,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string name = 4;
@@ -1602,7 +1610,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 			x.Name, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string type = 5;
@@ -1611,13 +1619,13 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 			x.Type, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [string number boolean integer array]
 			if ok && !compiler.StringArrayContainsValue([]string{"string", "number", "boolean", "integer", "array"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string format = 6;
@@ -1626,7 +1634,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 			x.Format, ok = compiler.StringForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for format: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// PrimitivesItems items = 7;
@@ -1644,13 +1652,13 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 			x.CollectionFormat, ok = compiler.StringForScalarNode(v8)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [csv ssv tsv pipes]
 			if ok && !compiler.StringArrayContainsValue([]string{"csv", "ssv", "tsv", "pipes"}, x.CollectionFormat) {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Any default = 9;
@@ -1670,7 +1678,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 				x.Maximum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for maximum: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_maximum = 11;
@@ -1679,7 +1687,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 			x.ExclusiveMaximum, ok = compiler.BoolForScalarNode(v11)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMaximum: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// float minimum = 12;
@@ -1690,7 +1698,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 				x.Minimum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for minimum: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_minimum = 13;
@@ -1699,7 +1707,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 			x.ExclusiveMinimum, ok = compiler.BoolForScalarNode(v13)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMinimum: %s", compiler.Display(v13))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_length = 14;
@@ -1710,7 +1718,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 				x.MaxLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxLength: %s", compiler.Display(v14))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_length = 15;
@@ -1721,7 +1729,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 				x.MinLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minLength: %s", compiler.Display(v15))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string pattern = 16;
@@ -1730,7 +1738,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 			x.Pattern, ok = compiler.StringForScalarNode(v16)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for pattern: %s", compiler.Display(v16))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_items = 17;
@@ -1741,7 +1749,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 				x.MaxItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxItems: %s", compiler.Display(v17))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_items = 18;
@@ -1752,7 +1760,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 				x.MinItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minItems: %s", compiler.Display(v18))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool unique_items = 19;
@@ -1761,7 +1769,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 			x.UniqueItems, ok = compiler.BoolForScalarNode(v19)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for uniqueItems: %s", compiler.Display(v19))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated Any enum = 20;
@@ -1788,7 +1796,7 @@ func NewHeaderParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Hea
 				x.MultipleOf = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for multipleOf: %s", compiler.Display(v21))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 22;
@@ -1833,7 +1841,7 @@ func NewHeaders(in *yaml.Node, context *compiler.Context) (*Headers, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedHeader additional_properties = 1;
 		// MAP: Header
@@ -1863,20 +1871,22 @@ func NewInfo(in *yaml.Node, context *compiler.Context) (*Info, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"title", "version"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"contact", "description", "license", "termsOfService", "title", "version"}
-		allowedPatterns := []*regexp.Regexp{pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"title", "version"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"contact", "description", "license", "termsOfService", "title", "version"}
+			allowedPatterns := []*regexp.Regexp{pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string title = 1;
 		v1 := compiler.MapValueForKey(m, "title")
@@ -1884,7 +1894,7 @@ func NewInfo(in *yaml.Node, context *compiler.Context) (*Info, error) {
 			x.Title, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for title: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string version = 2;
@@ -1893,7 +1903,7 @@ func NewInfo(in *yaml.Node, context *compiler.Context) (*Info, error) {
 			x.Version, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for version: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -1902,7 +1912,7 @@ func NewInfo(in *yaml.Node, context *compiler.Context) (*Info, error) {
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string terms_of_service = 4;
@@ -1911,7 +1921,7 @@ func NewInfo(in *yaml.Node, context *compiler.Context) (*Info, error) {
 			x.TermsOfService, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for termsOfService: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Contact contact = 5;
@@ -1974,7 +1984,7 @@ func NewItemsItem(in *yaml.Node, context *compiler.Context) (*ItemsItem, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value for item array: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		x.Schema = make([]*Schema, 0)
 		y, err := NewSchema(m, compiler.NewContext("<array>", m, context))
@@ -1993,13 +2003,13 @@ func NewJsonReference(in *yaml.Node, context *compiler.Context) (*JsonReference,
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"$ref"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		// string _ref = 1;
 		v1 := compiler.MapValueForKey(m, "$ref")
@@ -2007,7 +2017,7 @@ func NewJsonReference(in *yaml.Node, context *compiler.Context) (*JsonReference,
 			x.XRef, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for $ref: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 2;
@@ -2016,7 +2026,7 @@ func NewJsonReference(in *yaml.Node, context *compiler.Context) (*JsonReference,
 			x.Description, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -2030,20 +2040,22 @@ func NewLicense(in *yaml.Node, context *compiler.Context) (*License, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"name"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"name", "url"}
-		allowedPatterns := []*regexp.Regexp{pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"name"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"name", "url"}
+			allowedPatterns := []*regexp.Regexp{pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2051,7 +2063,7 @@ func NewLicense(in *yaml.Node, context *compiler.Context) (*License, error) {
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string url = 2;
@@ -2060,7 +2072,7 @@ func NewLicense(in *yaml.Node, context *compiler.Context) (*License, error) {
 			x.Url, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for url: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 3;
@@ -2105,14 +2117,16 @@ func NewNamedAny(in *yaml.Node, context *compiler.Context) (*NamedAny, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2120,7 +2134,7 @@ func NewNamedAny(in *yaml.Node, context *compiler.Context) (*NamedAny, error) {
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Any value = 2;
@@ -2143,14 +2157,16 @@ func NewNamedHeader(in *yaml.Node, context *compiler.Context) (*NamedHeader, err
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2158,7 +2174,7 @@ func NewNamedHeader(in *yaml.Node, context *compiler.Context) (*NamedHeader, err
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Header value = 2;
@@ -2181,14 +2197,16 @@ func NewNamedParameter(in *yaml.Node, context *compiler.Context) (*NamedParamete
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2196,7 +2214,7 @@ func NewNamedParameter(in *yaml.Node, context *compiler.Context) (*NamedParamete
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Parameter value = 2;
@@ -2219,14 +2237,16 @@ func NewNamedPathItem(in *yaml.Node, context *compiler.Context) (*NamedPathItem,
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2234,7 +2254,7 @@ func NewNamedPathItem(in *yaml.Node, context *compiler.Context) (*NamedPathItem,
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// PathItem value = 2;
@@ -2257,14 +2277,16 @@ func NewNamedResponse(in *yaml.Node, context *compiler.Context) (*NamedResponse,
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2272,7 +2294,7 @@ func NewNamedResponse(in *yaml.Node, context *compiler.Context) (*NamedResponse,
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Response value = 2;
@@ -2295,14 +2317,16 @@ func NewNamedResponseValue(in *yaml.Node, context *compiler.Context) (*NamedResp
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2310,7 +2334,7 @@ func NewNamedResponseValue(in *yaml.Node, context *compiler.Context) (*NamedResp
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// ResponseValue value = 2;
@@ -2333,14 +2357,16 @@ func NewNamedSchema(in *yaml.Node, context *compiler.Context) (*NamedSchema, err
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2348,7 +2374,7 @@ func NewNamedSchema(in *yaml.Node, context *compiler.Context) (*NamedSchema, err
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Schema value = 2;
@@ -2371,14 +2397,16 @@ func NewNamedSecurityDefinitionsItem(in *yaml.Node, context *compiler.Context) (
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2386,7 +2414,7 @@ func NewNamedSecurityDefinitionsItem(in *yaml.Node, context *compiler.Context) (
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// SecurityDefinitionsItem value = 2;
@@ -2409,14 +2437,16 @@ func NewNamedString(in *yaml.Node, context *compiler.Context) (*NamedString, err
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2424,7 +2454,7 @@ func NewNamedString(in *yaml.Node, context *compiler.Context) (*NamedString, err
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string value = 2;
@@ -2433,7 +2463,7 @@ func NewNamedString(in *yaml.Node, context *compiler.Context) (*NamedString, err
 			x.Value, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for value: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -2447,14 +2477,16 @@ func NewNamedStringArray(in *yaml.Node, context *compiler.Context) (*NamedString
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2462,7 +2494,7 @@ func NewNamedStringArray(in *yaml.Node, context *compiler.Context) (*NamedString
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// StringArray value = 2;
@@ -2486,13 +2518,13 @@ func NewNonBodyParameter(in *yaml.Node, context *compiler.Context) (*NonBodyPara
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"in", "name", "type"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		// HeaderParameterSubSchema header_parameter_sub_schema = 1;
 		{
@@ -2544,7 +2576,7 @@ func NewNonBodyParameter(in *yaml.Node, context *compiler.Context) (*NonBodyPara
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid NonBodyParameter")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -2557,20 +2589,20 @@ func NewOauth2AccessCodeSecurity(in *yaml.Node, context *compiler.Context) (*Oau
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"authorizationUrl", "flow", "tokenUrl", "type"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"authorizationUrl", "description", "flow", "scopes", "tokenUrl", "type"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string type = 1;
 		v1 := compiler.MapValueForKey(m, "type")
@@ -2578,13 +2610,13 @@ func NewOauth2AccessCodeSecurity(in *yaml.Node, context *compiler.Context) (*Oau
 			x.Type, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [oauth2]
 			if ok && !compiler.StringArrayContainsValue([]string{"oauth2"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string flow = 2;
@@ -2593,13 +2625,13 @@ func NewOauth2AccessCodeSecurity(in *yaml.Node, context *compiler.Context) (*Oau
 			x.Flow, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for flow: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [accessCode]
 			if ok && !compiler.StringArrayContainsValue([]string{"accessCode"}, x.Flow) {
 				message := fmt.Sprintf("has unexpected value for flow: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Oauth2Scopes scopes = 3;
@@ -2617,7 +2649,7 @@ func NewOauth2AccessCodeSecurity(in *yaml.Node, context *compiler.Context) (*Oau
 			x.AuthorizationUrl, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for authorizationUrl: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string token_url = 5;
@@ -2626,7 +2658,7 @@ func NewOauth2AccessCodeSecurity(in *yaml.Node, context *compiler.Context) (*Oau
 			x.TokenUrl, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for tokenUrl: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 6;
@@ -2635,7 +2667,7 @@ func NewOauth2AccessCodeSecurity(in *yaml.Node, context *compiler.Context) (*Oau
 			x.Description, ok = compiler.StringForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 7;
@@ -2680,20 +2712,20 @@ func NewOauth2ApplicationSecurity(in *yaml.Node, context *compiler.Context) (*Oa
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"flow", "tokenUrl", "type"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"description", "flow", "scopes", "tokenUrl", "type"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string type = 1;
 		v1 := compiler.MapValueForKey(m, "type")
@@ -2701,13 +2733,13 @@ func NewOauth2ApplicationSecurity(in *yaml.Node, context *compiler.Context) (*Oa
 			x.Type, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [oauth2]
 			if ok && !compiler.StringArrayContainsValue([]string{"oauth2"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string flow = 2;
@@ -2716,13 +2748,13 @@ func NewOauth2ApplicationSecurity(in *yaml.Node, context *compiler.Context) (*Oa
 			x.Flow, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for flow: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [application]
 			if ok && !compiler.StringArrayContainsValue([]string{"application"}, x.Flow) {
 				message := fmt.Sprintf("has unexpected value for flow: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Oauth2Scopes scopes = 3;
@@ -2740,7 +2772,7 @@ func NewOauth2ApplicationSecurity(in *yaml.Node, context *compiler.Context) (*Oa
 			x.TokenUrl, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for tokenUrl: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 5;
@@ -2749,7 +2781,7 @@ func NewOauth2ApplicationSecurity(in *yaml.Node, context *compiler.Context) (*Oa
 			x.Description, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 6;
@@ -2794,20 +2826,20 @@ func NewOauth2ImplicitSecurity(in *yaml.Node, context *compiler.Context) (*Oauth
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"authorizationUrl", "flow", "type"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"authorizationUrl", "description", "flow", "scopes", "type"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string type = 1;
 		v1 := compiler.MapValueForKey(m, "type")
@@ -2815,13 +2847,13 @@ func NewOauth2ImplicitSecurity(in *yaml.Node, context *compiler.Context) (*Oauth
 			x.Type, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [oauth2]
 			if ok && !compiler.StringArrayContainsValue([]string{"oauth2"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string flow = 2;
@@ -2830,13 +2862,13 @@ func NewOauth2ImplicitSecurity(in *yaml.Node, context *compiler.Context) (*Oauth
 			x.Flow, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for flow: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [implicit]
 			if ok && !compiler.StringArrayContainsValue([]string{"implicit"}, x.Flow) {
 				message := fmt.Sprintf("has unexpected value for flow: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Oauth2Scopes scopes = 3;
@@ -2854,7 +2886,7 @@ func NewOauth2ImplicitSecurity(in *yaml.Node, context *compiler.Context) (*Oauth
 			x.AuthorizationUrl, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for authorizationUrl: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 5;
@@ -2863,7 +2895,7 @@ func NewOauth2ImplicitSecurity(in *yaml.Node, context *compiler.Context) (*Oauth
 			x.Description, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 6;
@@ -2908,20 +2940,20 @@ func NewOauth2PasswordSecurity(in *yaml.Node, context *compiler.Context) (*Oauth
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"flow", "tokenUrl", "type"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"description", "flow", "scopes", "tokenUrl", "type"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string type = 1;
 		v1 := compiler.MapValueForKey(m, "type")
@@ -2929,13 +2961,13 @@ func NewOauth2PasswordSecurity(in *yaml.Node, context *compiler.Context) (*Oauth
 			x.Type, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [oauth2]
 			if ok && !compiler.StringArrayContainsValue([]string{"oauth2"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string flow = 2;
@@ -2944,13 +2976,13 @@ func NewOauth2PasswordSecurity(in *yaml.Node, context *compiler.Context) (*Oauth
 			x.Flow, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for flow: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [password]
 			if ok && !compiler.StringArrayContainsValue([]string{"password"}, x.Flow) {
 				message := fmt.Sprintf("has unexpected value for flow: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Oauth2Scopes scopes = 3;
@@ -2968,7 +3000,7 @@ func NewOauth2PasswordSecurity(in *yaml.Node, context *compiler.Context) (*Oauth
 			x.TokenUrl, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for tokenUrl: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 5;
@@ -2977,7 +3009,7 @@ func NewOauth2PasswordSecurity(in *yaml.Node, context *compiler.Context) (*Oauth
 			x.Description, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 6;
@@ -3022,7 +3054,7 @@ func NewOauth2Scopes(in *yaml.Node, context *compiler.Context) (*Oauth2Scopes, e
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedString additional_properties = 1;
 		// MAP: string
@@ -3048,20 +3080,22 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"responses"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"consumes", "deprecated", "description", "externalDocs", "operationId", "parameters", "produces", "responses", "schemes", "security", "summary", "tags"}
-		allowedPatterns := []*regexp.Regexp{pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"responses"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"consumes", "deprecated", "description", "externalDocs", "operationId", "parameters", "produces", "responses", "schemes", "security", "summary", "tags"}
+			allowedPatterns := []*regexp.Regexp{pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// repeated string tags = 1;
 		v1 := compiler.MapValueForKey(m, "tags")
@@ -3071,7 +3105,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 				x.Tags = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for tags: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string summary = 2;
@@ -3080,7 +3114,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 			x.Summary, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for summary: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -3089,7 +3123,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// ExternalDocs external_docs = 4;
@@ -3107,7 +3141,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 			x.OperationId, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for operationId: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string produces = 6;
@@ -3118,7 +3152,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 				x.Produces = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for produces: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string consumes = 7;
@@ -3129,7 +3163,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 				x.Consumes = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for consumes: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated ParametersItem parameters = 8;
@@ -3165,13 +3199,13 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 				x.Schemes = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for schemes: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [http https ws wss]
 			if ok && !compiler.StringArrayContainsValues([]string{"http", "https", "ws", "wss"}, x.Schemes) {
 				message := fmt.Sprintf("has unexpected value for schemes: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool deprecated = 11;
@@ -3180,7 +3214,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 			x.Deprecated, ok = compiler.BoolForScalarNode(v11)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for deprecated: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated SecurityRequirement security = 12;
@@ -3272,7 +3306,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid Parameter")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -3285,7 +3319,7 @@ func NewParameterDefinitions(in *yaml.Node, context *compiler.Context) (*Paramet
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedParameter additional_properties = 1;
 		// MAP: Parameter
@@ -3346,7 +3380,7 @@ func NewParametersItem(in *yaml.Node, context *compiler.Context) (*ParametersIte
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid ParametersItem")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -3359,14 +3393,16 @@ func NewPathItem(in *yaml.Node, context *compiler.Context) (*PathItem, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"$ref", "delete", "get", "head", "options", "parameters", "patch", "post", "put"}
-		allowedPatterns := []*regexp.Regexp{pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"$ref", "delete", "get", "head", "options", "parameters", "patch", "post", "put"}
+			allowedPatterns := []*regexp.Regexp{pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string _ref = 1;
 		v1 := compiler.MapValueForKey(m, "$ref")
@@ -3374,7 +3410,7 @@ func NewPathItem(in *yaml.Node, context *compiler.Context) (*PathItem, error) {
 			x.XRef, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for $ref: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Operation get = 2;
@@ -3498,20 +3534,20 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"required"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"collectionFormat", "default", "description", "enum", "exclusiveMaximum", "exclusiveMinimum", "format", "in", "items", "maxItems", "maxLength", "maximum", "minItems", "minLength", "minimum", "multipleOf", "name", "pattern", "required", "type", "uniqueItems"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// bool required = 1;
 		v1 := compiler.MapValueForKey(m, "required")
@@ -3519,7 +3555,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 			x.Required, ok = compiler.BoolForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string in = 2;
@@ -3528,13 +3564,13 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 			x.In, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [path]
 			if ok && !compiler.StringArrayContainsValue([]string{"path"}, x.In) {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -3543,7 +3579,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string name = 4;
@@ -3552,7 +3588,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 			x.Name, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string type = 5;
@@ -3561,13 +3597,13 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 			x.Type, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [string number boolean integer array]
 			if ok && !compiler.StringArrayContainsValue([]string{"string", "number", "boolean", "integer", "array"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string format = 6;
@@ -3576,7 +3612,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 			x.Format, ok = compiler.StringForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for format: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// PrimitivesItems items = 7;
@@ -3594,13 +3630,13 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 			x.CollectionFormat, ok = compiler.StringForScalarNode(v8)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [csv ssv tsv pipes]
 			if ok && !compiler.StringArrayContainsValue([]string{"csv", "ssv", "tsv", "pipes"}, x.CollectionFormat) {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Any default = 9;
@@ -3620,7 +3656,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 				x.Maximum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for maximum: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_maximum = 11;
@@ -3629,7 +3665,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 			x.ExclusiveMaximum, ok = compiler.BoolForScalarNode(v11)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMaximum: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// float minimum = 12;
@@ -3640,7 +3676,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 				x.Minimum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for minimum: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_minimum = 13;
@@ -3649,7 +3685,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 			x.ExclusiveMinimum, ok = compiler.BoolForScalarNode(v13)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMinimum: %s", compiler.Display(v13))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_length = 14;
@@ -3660,7 +3696,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 				x.MaxLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxLength: %s", compiler.Display(v14))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_length = 15;
@@ -3671,7 +3707,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 				x.MinLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minLength: %s", compiler.Display(v15))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string pattern = 16;
@@ -3680,7 +3716,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 			x.Pattern, ok = compiler.StringForScalarNode(v16)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for pattern: %s", compiler.Display(v16))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_items = 17;
@@ -3691,7 +3727,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 				x.MaxItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxItems: %s", compiler.Display(v17))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_items = 18;
@@ -3702,7 +3738,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 				x.MinItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minItems: %s", compiler.Display(v18))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool unique_items = 19;
@@ -3711,7 +3747,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 			x.UniqueItems, ok = compiler.BoolForScalarNode(v19)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for uniqueItems: %s", compiler.Display(v19))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated Any enum = 20;
@@ -3738,7 +3774,7 @@ func NewPathParameterSubSchema(in *yaml.Node, context *compiler.Context) (*PathP
 				x.MultipleOf = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for multipleOf: %s", compiler.Display(v21))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 22;
@@ -3783,14 +3819,16 @@ func NewPaths(in *yaml.Node, context *compiler.Context) (*Paths, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{}
-		allowedPatterns := []*regexp.Regexp{pattern0, pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{}
+			allowedPatterns := []*regexp.Regexp{pattern0, pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// repeated NamedAny vendor_extension = 1;
 		// MAP: Any ^x-
@@ -3853,14 +3891,16 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"collectionFormat", "default", "enum", "exclusiveMaximum", "exclusiveMinimum", "format", "items", "maxItems", "maxLength", "maximum", "minItems", "minLength", "minimum", "multipleOf", "pattern", "type", "uniqueItems"}
-		allowedPatterns := []*regexp.Regexp{pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"collectionFormat", "default", "enum", "exclusiveMaximum", "exclusiveMinimum", "format", "items", "maxItems", "maxLength", "maximum", "minItems", "minLength", "minimum", "multipleOf", "pattern", "type", "uniqueItems"}
+			allowedPatterns := []*regexp.Regexp{pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string type = 1;
 		v1 := compiler.MapValueForKey(m, "type")
@@ -3868,13 +3908,13 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 			x.Type, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [string number integer boolean array]
 			if ok && !compiler.StringArrayContainsValue([]string{"string", "number", "integer", "boolean", "array"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string format = 2;
@@ -3883,7 +3923,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 			x.Format, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for format: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// PrimitivesItems items = 3;
@@ -3901,13 +3941,13 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 			x.CollectionFormat, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [csv ssv tsv pipes]
 			if ok && !compiler.StringArrayContainsValue([]string{"csv", "ssv", "tsv", "pipes"}, x.CollectionFormat) {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Any default = 5;
@@ -3927,7 +3967,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 				x.Maximum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for maximum: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_maximum = 7;
@@ -3936,7 +3976,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 			x.ExclusiveMaximum, ok = compiler.BoolForScalarNode(v7)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMaximum: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// float minimum = 8;
@@ -3947,7 +3987,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 				x.Minimum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for minimum: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_minimum = 9;
@@ -3956,7 +3996,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 			x.ExclusiveMinimum, ok = compiler.BoolForScalarNode(v9)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMinimum: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_length = 10;
@@ -3967,7 +4007,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 				x.MaxLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxLength: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_length = 11;
@@ -3978,7 +4018,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 				x.MinLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minLength: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string pattern = 12;
@@ -3987,7 +4027,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 			x.Pattern, ok = compiler.StringForScalarNode(v12)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for pattern: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_items = 13;
@@ -3998,7 +4038,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 				x.MaxItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxItems: %s", compiler.Display(v13))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_items = 14;
@@ -4009,7 +4049,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 				x.MinItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minItems: %s", compiler.Display(v14))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool unique_items = 15;
@@ -4018,7 +4058,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 			x.UniqueItems, ok = compiler.BoolForScalarNode(v15)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for uniqueItems: %s", compiler.Display(v15))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated Any enum = 16;
@@ -4045,7 +4085,7 @@ func NewPrimitivesItems(in *yaml.Node, context *compiler.Context) (*PrimitivesIt
 				x.MultipleOf = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for multipleOf: %s", compiler.Display(v17))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 18;
@@ -4090,7 +4130,7 @@ func NewProperties(in *yaml.Node, context *compiler.Context) (*Properties, error
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedSchema additional_properties = 1;
 		// MAP: Schema
@@ -4120,14 +4160,14 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		allowedKeys := []string{"allowEmptyValue", "collectionFormat", "default", "description", "enum", "exclusiveMaximum", "exclusiveMinimum", "format", "in", "items", "maxItems", "maxLength", "maximum", "minItems", "minLength", "minimum", "multipleOf", "name", "pattern", "required", "type", "uniqueItems"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// bool required = 1;
 		v1 := compiler.MapValueForKey(m, "required")
@@ -4135,7 +4175,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.Required, ok = compiler.BoolForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string in = 2;
@@ -4144,13 +4184,13 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.In, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [query]
 			if ok && !compiler.StringArrayContainsValue([]string{"query"}, x.In) {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -4159,7 +4199,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string name = 4;
@@ -4168,7 +4208,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.Name, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool allow_empty_value = 5;
@@ -4177,7 +4217,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.AllowEmptyValue, ok = compiler.BoolForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for allowEmptyValue: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string type = 6;
@@ -4186,13 +4226,13 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.Type, ok = compiler.StringForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [string number boolean integer array]
 			if ok && !compiler.StringArrayContainsValue([]string{"string", "number", "boolean", "integer", "array"}, x.Type) {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string format = 7;
@@ -4201,7 +4241,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.Format, ok = compiler.StringForScalarNode(v7)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for format: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// PrimitivesItems items = 8;
@@ -4219,13 +4259,13 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.CollectionFormat, ok = compiler.StringForScalarNode(v9)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 			// check for valid enum values
 			// [csv ssv tsv pipes multi]
 			if ok && !compiler.StringArrayContainsValue([]string{"csv", "ssv", "tsv", "pipes", "multi"}, x.CollectionFormat) {
 				message := fmt.Sprintf("has unexpected value for collectionFormat: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Any default = 10;
@@ -4245,7 +4285,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 				x.Maximum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for maximum: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_maximum = 12;
@@ -4254,7 +4294,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.ExclusiveMaximum, ok = compiler.BoolForScalarNode(v12)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMaximum: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// float minimum = 13;
@@ -4265,7 +4305,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 				x.Minimum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for minimum: %s", compiler.Display(v13))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_minimum = 14;
@@ -4274,7 +4314,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.ExclusiveMinimum, ok = compiler.BoolForScalarNode(v14)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMinimum: %s", compiler.Display(v14))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_length = 15;
@@ -4285,7 +4325,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 				x.MaxLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxLength: %s", compiler.Display(v15))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_length = 16;
@@ -4296,7 +4336,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 				x.MinLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minLength: %s", compiler.Display(v16))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string pattern = 17;
@@ -4305,7 +4345,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.Pattern, ok = compiler.StringForScalarNode(v17)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for pattern: %s", compiler.Display(v17))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_items = 18;
@@ -4316,7 +4356,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 				x.MaxItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxItems: %s", compiler.Display(v18))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_items = 19;
@@ -4327,7 +4367,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 				x.MinItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minItems: %s", compiler.Display(v19))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool unique_items = 20;
@@ -4336,7 +4376,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 			x.UniqueItems, ok = compiler.BoolForScalarNode(v20)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for uniqueItems: %s", compiler.Display(v20))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated Any enum = 21;
@@ -4363,7 +4403,7 @@ func NewQueryParameterSubSchema(in *yaml.Node, context *compiler.Context) (*Quer
 				x.MultipleOf = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for multipleOf: %s", compiler.Display(v22))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 23;
@@ -4408,20 +4448,20 @@ func NewResponse(in *yaml.Node, context *compiler.Context) (*Response, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"description"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"description", "examples", "headers", "schema"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string description = 1;
 		v1 := compiler.MapValueForKey(m, "description")
@@ -4429,7 +4469,7 @@ func NewResponse(in *yaml.Node, context *compiler.Context) (*Response, error) {
 			x.Description, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// SchemaItem schema = 2;
@@ -4501,7 +4541,7 @@ func NewResponseDefinitions(in *yaml.Node, context *compiler.Context) (*Response
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedResponse additional_properties = 1;
 		// MAP: Response
@@ -4562,7 +4602,7 @@ func NewResponseValue(in *yaml.Node, context *compiler.Context) (*ResponseValue,
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid ResponseValue")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -4575,14 +4615,16 @@ func NewResponses(in *yaml.Node, context *compiler.Context) (*Responses, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{}
-		allowedPatterns := []*regexp.Regexp{pattern2, pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{}
+			allowedPatterns := []*regexp.Regexp{pattern2, pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// repeated NamedResponseValue response_code = 1;
 		// MAP: ResponseValue ^([0-9]{3})$|^(default)$
@@ -4645,14 +4687,14 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		allowedKeys := []string{"$ref", "additionalProperties", "allOf", "default", "description", "discriminator", "enum", "example", "exclusiveMaximum", "exclusiveMinimum", "externalDocs", "format", "items", "maxItems", "maxLength", "maxProperties", "maximum", "minItems", "minLength", "minProperties", "minimum", "multipleOf", "pattern", "properties", "readOnly", "required", "title", "type", "uniqueItems", "xml"}
 		allowedPatterns := []*regexp.Regexp{pattern0}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string _ref = 1;
 		v1 := compiler.MapValueForKey(m, "$ref")
@@ -4660,7 +4702,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.XRef, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for $ref: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string format = 2;
@@ -4669,7 +4711,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Format, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for format: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string title = 3;
@@ -4678,7 +4720,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Title, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for title: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 4;
@@ -4687,7 +4729,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Description, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Any default = 5;
@@ -4707,7 +4749,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MultipleOf = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for multipleOf: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// float maximum = 7;
@@ -4718,7 +4760,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.Maximum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for maximum: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_maximum = 8;
@@ -4727,7 +4769,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.ExclusiveMaximum, ok = compiler.BoolForScalarNode(v8)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMaximum: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// float minimum = 9;
@@ -4738,7 +4780,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.Minimum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for minimum: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_minimum = 10;
@@ -4747,7 +4789,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.ExclusiveMinimum, ok = compiler.BoolForScalarNode(v10)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMinimum: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_length = 11;
@@ -4758,7 +4800,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MaxLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxLength: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_length = 12;
@@ -4769,7 +4811,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MinLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minLength: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string pattern = 13;
@@ -4778,7 +4820,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Pattern, ok = compiler.StringForScalarNode(v13)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for pattern: %s", compiler.Display(v13))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_items = 14;
@@ -4789,7 +4831,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MaxItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxItems: %s", compiler.Display(v14))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_items = 15;
@@ -4800,7 +4842,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MinItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minItems: %s", compiler.Display(v15))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool unique_items = 16;
@@ -4809,7 +4851,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.UniqueItems, ok = compiler.BoolForScalarNode(v16)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for uniqueItems: %s", compiler.Display(v16))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_properties = 17;
@@ -4820,7 +4862,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MaxProperties = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxProperties: %s", compiler.Display(v17))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_properties = 18;
@@ -4831,7 +4873,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MinProperties = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minProperties: %s", compiler.Display(v18))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string required = 19;
@@ -4842,7 +4884,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.Required = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v19))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated Any enum = 20;
@@ -4919,7 +4961,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Discriminator, ok = compiler.StringForScalarNode(v26)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for discriminator: %s", compiler.Display(v26))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool read_only = 27;
@@ -4928,7 +4970,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.ReadOnly, ok = compiler.BoolForScalarNode(v27)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for readOnly: %s", compiler.Display(v27))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Xml xml = 28;
@@ -5031,7 +5073,7 @@ func NewSchemaItem(in *yaml.Node, context *compiler.Context) (*SchemaItem, error
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid SchemaItem")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -5044,7 +5086,7 @@ func NewSecurityDefinitions(in *yaml.Node, context *compiler.Context) (*Security
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedSecurityDefinitionsItem additional_properties = 1;
 		// MAP: SecurityDefinitionsItem
@@ -5161,7 +5203,7 @@ func NewSecurityDefinitionsItem(in *yaml.Node, context *compiler.Context) (*Secu
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid SecurityDefinitionsItem")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -5174,7 +5216,7 @@ func NewSecurityRequirement(in *yaml.Node, context *compiler.Context) (*Security
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedStringArray additional_properties = 1;
 		// MAP: StringArray
@@ -5216,20 +5258,22 @@ func NewTag(in *yaml.Node, context *compiler.Context) (*Tag, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"name"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"description", "externalDocs", "name"}
-		allowedPatterns := []*regexp.Regexp{pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"name"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"description", "externalDocs", "name"}
+			allowedPatterns := []*regexp.Regexp{pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -5237,7 +5281,7 @@ func NewTag(in *yaml.Node, context *compiler.Context) (*Tag, error) {
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 2;
@@ -5246,7 +5290,7 @@ func NewTag(in *yaml.Node, context *compiler.Context) (*Tag, error) {
 			x.Description, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// ExternalDocs external_docs = 3;
@@ -5311,12 +5355,12 @@ func NewTypeItem(in *yaml.Node, context *compiler.Context) (*TypeItem, error) {
 				x.Value = append(x.Value, value)
 			} else {
 				message := fmt.Sprintf("has unexpected value for string array element: %+v (%T)", value, value)
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	default:
 		message := fmt.Sprintf("has unexpected value for string array: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
 }
@@ -5328,7 +5372,7 @@ func NewVendorExtension(in *yaml.Node, context *compiler.Context) (*VendorExtens
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedAny additional_properties = 1;
 		// MAP: Any
@@ -5370,14 +5414,16 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"attribute", "name", "namespace", "prefix", "wrapped"}
-		allowedPatterns := []*regexp.Regexp{pattern0}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"attribute", "name", "namespace", "prefix", "wrapped"}
+			allowedPatterns := []*regexp.Regexp{pattern0}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -5385,7 +5431,7 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string namespace = 2;
@@ -5394,7 +5440,7 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 			x.Namespace, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for namespace: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string prefix = 3;
@@ -5403,7 +5449,7 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 			x.Prefix, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for prefix: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool attribute = 4;
@@ -5412,7 +5458,7 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 			x.Attribute, ok = compiler.BoolForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for attribute: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool wrapped = 5;
@@ -5421,7 +5467,7 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 			x.Wrapped, ok = compiler.BoolForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for wrapped: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny vendor_extension = 6;
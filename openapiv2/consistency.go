@@ -0,0 +1,255 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A SchemaProblem describes schema keywords that contradict each other,
+// such as a default that isn't one of the enum values.
+type SchemaProblem struct {
+	// Keys locate the schema, parameter or header in the document,
+	// e.g. ["definitions", "Pet", "properties", "age"].
+	Keys    []string
+	Message string
+}
+
+func (p *SchemaProblem) String() string {
+	return strings.Join(p.Keys, ".") + ": " + p.Message
+}
+
+// CheckSchemas checks the schemas, parameters and headers of a document
+// for keywords that contradict each other: defaults that aren't enum
+// values or don't have the type of their schema, and minimums that are
+// greater than maximums. Maximums of zero are ignored because they can't
+// be told apart from missing maximums.
+func CheckSchemas(d *Document) []*SchemaProblem {
+	c := &schemaChecker{problems: make([]*SchemaProblem, 0)}
+	for _, pair := range d.GetDefinitions().GetAdditionalProperties() {
+		c.checkSchema(pair.Value, []string{"definitions", pair.Name})
+	}
+	for _, pair := range d.GetParameters().GetAdditionalProperties() {
+		c.checkParameter(pair.Value, []string{"parameters", pair.Name})
+	}
+	for _, pair := range d.GetResponses().GetAdditionalProperties() {
+		c.checkResponse(pair.Value, []string{"responses", pair.Name})
+	}
+	for _, pair := range d.GetPaths().GetPath() {
+		for i, parameter := range pair.Value.GetParameters() {
+			c.checkParameter(parameter.GetParameter(), []string{"paths", pair.Name, "parameters", strconv.Itoa(i)})
+		}
+	}
+	ForEachOperation(d, func(path, method string, _ *PathItem, operation *Operation) {
+		for i, parameter := range operation.Parameters {
+			c.checkParameter(parameter.GetParameter(), []string{"paths", path, method, "parameters", strconv.Itoa(i)})
+		}
+		for _, pair := range operation.GetResponses().GetResponseCode() {
+			c.checkResponse(pair.Value.GetResponse(), []string{"paths", path, method, "responses", pair.Name})
+		}
+	})
+	return c.problems
+}
+
+// constrained values have the keywords that are checked. Schemas,
+// parameters, headers and their items have them.
+type constrained interface {
+	GetDefault() *Any
+	GetEnum() []*Any
+	GetMinimum() float64
+	GetMaximum() float64
+	GetMinLength() int64
+	GetMaxLength() int64
+	GetMinItems() int64
+	GetMaxItems() int64
+}
+
+type schemaChecker struct {
+	problems []*SchemaProblem
+}
+
+func (c *schemaChecker) fail(keys []string, format string, args ...interface{}) {
+	c.problems = append(c.problems, &SchemaProblem{Keys: keys, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *schemaChecker) checkParameter(parameter *Parameter, keys []string) {
+	if body := parameter.GetBodyParameter(); body != nil {
+		c.checkSchema(body.Schema, appendKeys(keys, "schema"))
+	}
+	if p := parameter.GetNonBodyParameter().GetHeaderParameterSubSchema(); p != nil {
+		c.checkValues(p, []string{p.Type}, keys)
+		c.checkItems(p.Items, appendKeys(keys, "items"))
+	}
+	if p := parameter.GetNonBodyParameter().GetFormDataParameterSubSchema(); p != nil {
+		c.checkValues(p, []string{p.Type}, keys)
+		c.checkItems(p.Items, appendKeys(keys, "items"))
+	}
+	if p := parameter.GetNonBodyParameter().GetQueryParameterSubSchema(); p != nil {
+		c.checkValues(p, []string{p.Type}, keys)
+		c.checkItems(p.Items, appendKeys(keys, "items"))
+	}
+	if p := parameter.GetNonBodyParameter().GetPathParameterSubSchema(); p != nil {
+		c.checkValues(p, []string{p.Type}, keys)
+		c.checkItems(p.Items, appendKeys(keys, "items"))
+	}
+}
+
+func (c *schemaChecker) checkResponse(response *Response, keys []string) {
+	if response == nil {
+		return
+	}
+	c.checkSchema(response.GetSchema().GetSchema(), appendKeys(keys, "schema"))
+	for _, pair := range response.GetHeaders().GetAdditionalProperties() {
+		headerKeys := appendKeys(keys, "headers", pair.Name)
+		c.checkValues(pair.Value, []string{pair.Value.GetType()}, headerKeys)
+		c.checkItems(pair.Value.GetItems(), appendKeys(headerKeys, "items"))
+	}
+}
+
+func (c *schemaChecker) checkItems(items *PrimitivesItems, keys []string) {
+	if items == nil {
+		return
+	}
+	c.checkValues(items, []string{items.Type}, keys)
+	c.checkItems(items.Items, appendKeys(keys, "items"))
+}
+
+func (c *schemaChecker) checkSchema(schema *Schema, keys []string) {
+	if schema == nil {
+		return
+	}
+	c.checkValues(schema, schema.GetType().GetValue(), keys)
+	for _, pair := range schema.GetProperties().GetAdditionalProperties() {
+		c.checkSchema(pair.Value, appendKeys(keys, "properties", pair.Name))
+	}
+	for _, item := range schema.GetItems().GetSchema() {
+		c.checkSchema(item, appendKeys(keys, "items"))
+	}
+	c.checkSchema(schema.GetAdditionalProperties().GetSchema(), appendKeys(keys, "additionalProperties"))
+	for i, member := range schema.AllOf {
+		c.checkSchema(member, appendKeys(keys, "allOf", strconv.Itoa(i)))
+	}
+}
+
+// checkValues checks the default, enum and bounds of a value with types.
+func (c *schemaChecker) checkValues(v constrained, types []string, keys []string) {
+	if v.GetDefault() != nil {
+		value, ok := anyJSON(v.GetDefault())
+		if !ok {
+			value = strings.TrimSpace(v.GetDefault().GetYaml())
+		}
+		if node := anyNode(v.GetDefault()); node != nil && !nodeHasTypes(node, types) {
+			c.fail(appendKeys(keys, "default"), "default %s is not of type %s", value, strings.Join(types, " or "))
+		}
+		if len(v.GetEnum()) > 0 && !enumContains(v.GetEnum(), v.GetDefault()) {
+			c.fail(appendKeys(keys, "default"), "default %s is not one of the enum values", value)
+		}
+	}
+	if v.GetMaximum() != 0 && v.GetMinimum() > v.GetMaximum() {
+		c.fail(keys, "minimum %v is greater than maximum %v", v.GetMinimum(), v.GetMaximum())
+	}
+	if v.GetMaxLength() != 0 && v.GetMinLength() > v.GetMaxLength() {
+		c.fail(keys, "minLength %d is greater than maxLength %d", v.GetMinLength(), v.GetMaxLength())
+	}
+	if v.GetMaxItems() != 0 && v.GetMinItems() > v.GetMaxItems() {
+		c.fail(keys, "minItems %d is greater than maxItems %d", v.GetMinItems(), v.GetMaxItems())
+	}
+}
+
+// appendKeys returns a copy of keys with more keys appended.
+func appendKeys(keys []string, more ...string) []string {
+	result := make([]string, 0, len(keys)+len(more))
+	return append(append(result, keys...), more...)
+}
+
+// enumContains returns true if one of the values of an enum equals a value.
+func enumContains(enum []*Any, value *Any) bool {
+	want, ok := anyJSON(value)
+	if !ok {
+		return true
+	}
+	for _, e := range enum {
+		if got, ok := anyJSON(e); ok && got == want {
+			return true
+		}
+	}
+	return false
+}
+
+// anyJSON returns the JSON of a value. Integers and floats with the same
+// values have the same JSON.
+func anyJSON(a *Any) (string, bool) {
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(a.GetYaml()), &v); err != nil {
+		return "", false
+	}
+	b, err := json.Marshal(v)
+	return string(b), err == nil
+}
+
+// anyNode returns the YAML node of a value, or nil if it can't be parsed.
+func anyNode(a *Any) *yaml.Node {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(a.GetYaml()), &node); err != nil || len(node.Content) == 0 {
+		return nil
+	}
+	return node.Content[0]
+}
+
+// nodeHasTypes returns true if a YAML node has one of a list of types,
+// or if the list is empty.
+func nodeHasTypes(node *yaml.Node, types []string) bool {
+	tag := node.ShortTag()
+	for _, t := range types {
+		switch t {
+		case "string":
+			if tag == "!!str" || tag == "!!timestamp" || tag == "!!binary" {
+				return true
+			}
+		case "integer":
+			if tag == "!!int" {
+				return true
+			}
+		case "number":
+			if tag == "!!int" || tag == "!!float" {
+				return true
+			}
+		case "boolean":
+			if tag == "!!bool" {
+				return true
+			}
+		case "array":
+			if node.Kind == yaml.SequenceNode {
+				return true
+			}
+		case "object":
+			if node.Kind == yaml.MappingNode {
+				return true
+			}
+		case "null":
+			if tag == "!!null" {
+				return true
+			}
+		case "", "file":
+			return true
+		}
+	}
+	return len(types) == 0
+}
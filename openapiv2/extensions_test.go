@@ -0,0 +1,49 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v2
+
+import (
+	"testing"
+)
+
+func TestExtensions(t *testing.T) {
+	d, err := ParseDocument([]byte(`swagger: "2.0"
+info:
+  title: Extensions
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      x-owner: pets
+      responses:
+        "200":
+          description: ok
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	operation := d.Paths.Path[0].Value.Get
+	var owner string
+	if err := GetExtensionInto(operation, "x-owner", &owner); err != nil || owner != "pets" {
+		t.Errorf("Unexpected value of x-owner: %q (%v)", owner, err)
+	}
+	if err := SetExtension(d, "x-generated", map[string]int{"version": 2}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	node, ok := GetExtension(d, "x-generated")
+	if !ok || len(node.Content) != 2 || node.Content[1].Value != "2" {
+		t.Errorf("Unexpected value of x-generated: %+v", node)
+	}
+}
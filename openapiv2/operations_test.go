@@ -0,0 +1,43 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v2
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestForEachOperation(t *testing.T) {
+	bytes, err := ioutil.ReadFile("../examples/v2.0/yaml/petstore.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	d, err := ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var operations []string
+	ForEachOperation(d, func(path, method string, item *PathItem, operation *Operation) {
+		operations = append(operations, method+" "+path+" "+operation.OperationId)
+	})
+	expected := "get /pets listPets, post /pets createPets, get /pets/{petId} showPetById"
+	if got := strings.Join(operations, ", "); got != expected {
+		t.Errorf("unexpected operations: %s (expected %s)", got, expected)
+	}
+	ForEachOperation(nil, func(path, method string, item *PathItem, operation *Operation) {
+		t.Errorf("unexpected operation in a nil document")
+	})
+}
@@ -61,7 +61,7 @@ func NewAdditionalPropertiesItem(in *yaml.Node, context *compiler.Context) (*Add
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid AdditionalPropertiesItem")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -114,7 +114,7 @@ func NewAnyOrExpression(in *yaml.Node, context *compiler.Context) (*AnyOrExpress
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid AnyOrExpression")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -127,14 +127,14 @@ func NewCallback(in *yaml.Node, context *compiler.Context) (*Callback, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		allowedKeys := []string{}
 		allowedPatterns := []*regexp.Regexp{pattern0, pattern1}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// repeated NamedPathItem path = 1;
 		// MAP: PathItem ^
@@ -228,7 +228,7 @@ func NewCallbackOrReference(in *yaml.Node, context *compiler.Context) (*Callback
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid CallbackOrReference")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -241,7 +241,7 @@ func NewCallbacksOrReferences(in *yaml.Node, context *compiler.Context) (*Callba
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedCallbackOrReference additional_properties = 1;
 		// MAP: CallbackOrReference
@@ -271,14 +271,16 @@ func NewComponents(in *yaml.Node, context *compiler.Context) (*Components, error
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"callbacks", "examples", "headers", "links", "parameters", "requestBodies", "responses", "schemas", "securitySchemes"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"callbacks", "examples", "headers", "links", "parameters", "requestBodies", "responses", "schemas", "securitySchemes"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// SchemasOrReferences schemas = 1;
 		v1 := compiler.MapValueForKey(m, "schemas")
@@ -403,14 +405,16 @@ func NewContact(in *yaml.Node, context *compiler.Context) (*Contact, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"email", "name", "url"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"email", "name", "url"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -418,7 +422,7 @@ func NewContact(in *yaml.Node, context *compiler.Context) (*Contact, error) {
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string url = 2;
@@ -427,7 +431,7 @@ func NewContact(in *yaml.Node, context *compiler.Context) (*Contact, error) {
 			x.Url, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for url: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string email = 3;
@@ -436,7 +440,7 @@ func NewContact(in *yaml.Node, context *compiler.Context) (*Contact, error) {
 			x.Email, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for email: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny specification_extension = 4;
@@ -511,20 +515,22 @@ func NewDiscriminator(in *yaml.Node, context *compiler.Context) (*Discriminator,
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"propertyName"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"mapping", "propertyName"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"propertyName"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"mapping", "propertyName"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string property_name = 1;
 		v1 := compiler.MapValueForKey(m, "propertyName")
@@ -532,7 +538,7 @@ func NewDiscriminator(in *yaml.Node, context *compiler.Context) (*Discriminator,
 			x.PropertyName, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for propertyName: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Strings mapping = 2;
@@ -586,20 +592,22 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"info", "openapi", "paths"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"components", "externalDocs", "info", "openapi", "paths", "security", "servers", "tags"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"info", "openapi", "paths"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"components", "externalDocs", "info", "openapi", "paths", "security", "servers", "tags"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string openapi = 1;
 		v1 := compiler.MapValueForKey(m, "openapi")
@@ -607,7 +615,7 @@ func NewDocument(in *yaml.Node, context *compiler.Context) (*Document, error) {
 			x.Openapi, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for openapi: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Info info = 2;
@@ -736,14 +744,16 @@ func NewEncoding(in *yaml.Node, context *compiler.Context) (*Encoding, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"allowReserved", "contentType", "explode", "headers", "style"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"allowReserved", "contentType", "explode", "headers", "style"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string content_type = 1;
 		v1 := compiler.MapValueForKey(m, "contentType")
@@ -751,7 +761,7 @@ func NewEncoding(in *yaml.Node, context *compiler.Context) (*Encoding, error) {
 			x.ContentType, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for contentType: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// HeadersOrReferences headers = 2;
@@ -769,7 +779,7 @@ func NewEncoding(in *yaml.Node, context *compiler.Context) (*Encoding, error) {
 			x.Style, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for style: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool explode = 4;
@@ -778,7 +788,7 @@ func NewEncoding(in *yaml.Node, context *compiler.Context) (*Encoding, error) {
 			x.Explode, ok = compiler.BoolForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for explode: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool allow_reserved = 5;
@@ -787,7 +797,7 @@ func NewEncoding(in *yaml.Node, context *compiler.Context) (*Encoding, error) {
 			x.AllowReserved, ok = compiler.BoolForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for allowReserved: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny specification_extension = 6;
@@ -832,7 +842,7 @@ func NewEncodings(in *yaml.Node, context *compiler.Context) (*Encodings, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedEncoding additional_properties = 1;
 		// MAP: Encoding
@@ -862,14 +872,14 @@ func NewExample(in *yaml.Node, context *compiler.Context) (*Example, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		allowedKeys := []string{"description", "externalValue", "summary", "value"}
 		allowedPatterns := []*regexp.Regexp{pattern1}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string summary = 1;
 		v1 := compiler.MapValueForKey(m, "summary")
@@ -877,7 +887,7 @@ func NewExample(in *yaml.Node, context *compiler.Context) (*Example, error) {
 			x.Summary, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for summary: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 2;
@@ -886,7 +896,7 @@ func NewExample(in *yaml.Node, context *compiler.Context) (*Example, error) {
 			x.Description, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Any value = 3;
@@ -904,7 +914,7 @@ func NewExample(in *yaml.Node, context *compiler.Context) (*Example, error) {
 			x.ExternalValue, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for externalValue: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny specification_extension = 5;
@@ -980,7 +990,7 @@ func NewExampleOrReference(in *yaml.Node, context *compiler.Context) (*ExampleOr
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid ExampleOrReference")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -993,7 +1003,7 @@ func NewExamplesOrReferences(in *yaml.Node, context *compiler.Context) (*Example
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedExampleOrReference additional_properties = 1;
 		// MAP: ExampleOrReference
@@ -1023,7 +1033,7 @@ func NewExpression(in *yaml.Node, context *compiler.Context) (*Expression, error
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedAny additional_properties = 1;
 		// MAP: Any
@@ -1065,20 +1075,22 @@ func NewExternalDocs(in *yaml.Node, context *compiler.Context) (*ExternalDocs, e
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"url"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"description", "url"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"url"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"description", "url"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string description = 1;
 		v1 := compiler.MapValueForKey(m, "description")
@@ -1086,7 +1098,7 @@ func NewExternalDocs(in *yaml.Node, context *compiler.Context) (*ExternalDocs, e
 			x.Description, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string url = 2;
@@ -1095,7 +1107,7 @@ func NewExternalDocs(in *yaml.Node, context *compiler.Context) (*ExternalDocs, e
 			x.Url, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for url: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny specification_extension = 3;
@@ -1140,14 +1152,14 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		allowedKeys := []string{"allowEmptyValue", "allowReserved", "content", "deprecated", "description", "example", "examples", "explode", "required", "schema", "style"}
 		allowedPatterns := []*regexp.Regexp{pattern1}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string description = 1;
 		v1 := compiler.MapValueForKey(m, "description")
@@ -1155,7 +1167,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.Description, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool required = 2;
@@ -1164,7 +1176,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.Required, ok = compiler.BoolForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool deprecated = 3;
@@ -1173,7 +1185,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.Deprecated, ok = compiler.BoolForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for deprecated: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool allow_empty_value = 4;
@@ -1182,7 +1194,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.AllowEmptyValue, ok = compiler.BoolForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for allowEmptyValue: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string style = 5;
@@ -1191,7 +1203,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.Style, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for style: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool explode = 6;
@@ -1200,7 +1212,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.Explode, ok = compiler.BoolForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for explode: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool allow_reserved = 7;
@@ -1209,7 +1221,7 @@ func NewHeader(in *yaml.Node, context *compiler.Context) (*Header, error) {
 			x.AllowReserved, ok = compiler.BoolForScalarNode(v7)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for allowReserved: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// SchemaOrReference schema = 8;
@@ -1321,7 +1333,7 @@ func NewHeaderOrReference(in *yaml.Node, context *compiler.Context) (*HeaderOrRe
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid HeaderOrReference")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -1334,7 +1346,7 @@ func NewHeadersOrReferences(in *yaml.Node, context *compiler.Context) (*HeadersO
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedHeaderOrReference additional_properties = 1;
 		// MAP: HeaderOrReference
@@ -1364,20 +1376,22 @@ func NewInfo(in *yaml.Node, context *compiler.Context) (*Info, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"title", "version"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"contact", "description", "license", "summary", "termsOfService", "title", "version"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"title", "version"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"contact", "description", "license", "summary", "termsOfService", "title", "version"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string title = 1;
 		v1 := compiler.MapValueForKey(m, "title")
@@ -1385,7 +1399,7 @@ func NewInfo(in *yaml.Node, context *compiler.Context) (*Info, error) {
 			x.Title, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for title: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 2;
@@ -1394,7 +1408,7 @@ func NewInfo(in *yaml.Node, context *compiler.Context) (*Info, error) {
 			x.Description, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string terms_of_service = 3;
@@ -1403,7 +1417,7 @@ func NewInfo(in *yaml.Node, context *compiler.Context) (*Info, error) {
 			x.TermsOfService, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for termsOfService: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Contact contact = 4;
@@ -1430,7 +1444,7 @@ func NewInfo(in *yaml.Node, context *compiler.Context) (*Info, error) {
 			x.Version, ok = compiler.StringForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for version: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string summary = 7;
@@ -1439,7 +1453,7 @@ func NewInfo(in *yaml.Node, context *compiler.Context) (*Info, error) {
 			x.Summary, ok = compiler.StringForScalarNode(v7)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for summary: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny specification_extension = 8;
@@ -1484,7 +1498,7 @@ func NewItemsItem(in *yaml.Node, context *compiler.Context) (*ItemsItem, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value for item array: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		x.SchemaOrReference = make([]*SchemaOrReference, 0)
 		y, err := NewSchemaOrReference(m, compiler.NewContext("<array>", m, context))
@@ -1503,20 +1517,22 @@ func NewLicense(in *yaml.Node, context *compiler.Context) (*License, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"name"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"name", "url"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"name"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"name", "url"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -1524,7 +1540,7 @@ func NewLicense(in *yaml.Node, context *compiler.Context) (*License, error) {
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string url = 2;
@@ -1533,7 +1549,7 @@ func NewLicense(in *yaml.Node, context *compiler.Context) (*License, error) {
 			x.Url, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for url: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny specification_extension = 3;
@@ -1578,14 +1594,14 @@ func NewLink(in *yaml.Node, context *compiler.Context) (*Link, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		allowedKeys := []string{"description", "operationId", "operationRef", "parameters", "requestBody", "server"}
 		allowedPatterns := []*regexp.Regexp{pattern1}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string operation_ref = 1;
 		v1 := compiler.MapValueForKey(m, "operationRef")
@@ -1593,7 +1609,7 @@ func NewLink(in *yaml.Node, context *compiler.Context) (*Link, error) {
 			x.OperationRef, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for operationRef: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string operation_id = 2;
@@ -1602,7 +1618,7 @@ func NewLink(in *yaml.Node, context *compiler.Context) (*Link, error) {
 			x.OperationId, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for operationId: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// AnyOrExpression parameters = 3;
@@ -1629,7 +1645,7 @@ func NewLink(in *yaml.Node, context *compiler.Context) (*Link, error) {
 			x.Description, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Server server = 6;
@@ -1714,7 +1730,7 @@ func NewLinkOrReference(in *yaml.Node, context *compiler.Context) (*LinkOrRefere
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid LinkOrReference")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -1727,7 +1743,7 @@ func NewLinksOrReferences(in *yaml.Node, context *compiler.Context) (*LinksOrRef
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedLinkOrReference additional_properties = 1;
 		// MAP: LinkOrReference
@@ -1757,14 +1773,16 @@ func NewMediaType(in *yaml.Node, context *compiler.Context) (*MediaType, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"encoding", "example", "examples", "schema"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"encoding", "example", "examples", "schema"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// SchemaOrReference schema = 1;
 		v1 := compiler.MapValueForKey(m, "schema")
@@ -1844,7 +1862,7 @@ func NewMediaTypes(in *yaml.Node, context *compiler.Context) (*MediaTypes, error
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedMediaType additional_properties = 1;
 		// MAP: MediaType
@@ -1874,14 +1892,16 @@ func NewNamedAny(in *yaml.Node, context *compiler.Context) (*NamedAny, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -1889,7 +1909,7 @@ func NewNamedAny(in *yaml.Node, context *compiler.Context) (*NamedAny, error) {
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Any value = 2;
@@ -1912,14 +1932,16 @@ func NewNamedCallbackOrReference(in *yaml.Node, context *compiler.Context) (*Nam
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -1927,7 +1949,7 @@ func NewNamedCallbackOrReference(in *yaml.Node, context *compiler.Context) (*Nam
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// CallbackOrReference value = 2;
@@ -1950,14 +1972,16 @@ func NewNamedEncoding(in *yaml.Node, context *compiler.Context) (*NamedEncoding,
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -1965,7 +1989,7 @@ func NewNamedEncoding(in *yaml.Node, context *compiler.Context) (*NamedEncoding,
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Encoding value = 2;
@@ -1988,14 +2012,16 @@ func NewNamedExampleOrReference(in *yaml.Node, context *compiler.Context) (*Name
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2003,7 +2029,7 @@ func NewNamedExampleOrReference(in *yaml.Node, context *compiler.Context) (*Name
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// ExampleOrReference value = 2;
@@ -2026,14 +2052,16 @@ func NewNamedHeaderOrReference(in *yaml.Node, context *compiler.Context) (*Named
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2041,7 +2069,7 @@ func NewNamedHeaderOrReference(in *yaml.Node, context *compiler.Context) (*Named
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// HeaderOrReference value = 2;
@@ -2064,14 +2092,16 @@ func NewNamedLinkOrReference(in *yaml.Node, context *compiler.Context) (*NamedLi
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2079,7 +2109,7 @@ func NewNamedLinkOrReference(in *yaml.Node, context *compiler.Context) (*NamedLi
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// LinkOrReference value = 2;
@@ -2102,14 +2132,16 @@ func NewNamedMediaType(in *yaml.Node, context *compiler.Context) (*NamedMediaTyp
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2117,7 +2149,7 @@ func NewNamedMediaType(in *yaml.Node, context *compiler.Context) (*NamedMediaTyp
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// MediaType value = 2;
@@ -2140,14 +2172,16 @@ func NewNamedParameterOrReference(in *yaml.Node, context *compiler.Context) (*Na
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2155,7 +2189,7 @@ func NewNamedParameterOrReference(in *yaml.Node, context *compiler.Context) (*Na
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// ParameterOrReference value = 2;
@@ -2178,14 +2212,16 @@ func NewNamedPathItem(in *yaml.Node, context *compiler.Context) (*NamedPathItem,
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2193,7 +2229,7 @@ func NewNamedPathItem(in *yaml.Node, context *compiler.Context) (*NamedPathItem,
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// PathItem value = 2;
@@ -2216,14 +2252,16 @@ func NewNamedRequestBodyOrReference(in *yaml.Node, context *compiler.Context) (*
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2231,7 +2269,7 @@ func NewNamedRequestBodyOrReference(in *yaml.Node, context *compiler.Context) (*
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// RequestBodyOrReference value = 2;
@@ -2254,14 +2292,16 @@ func NewNamedResponseOrReference(in *yaml.Node, context *compiler.Context) (*Nam
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2269,7 +2309,7 @@ func NewNamedResponseOrReference(in *yaml.Node, context *compiler.Context) (*Nam
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// ResponseOrReference value = 2;
@@ -2292,14 +2332,16 @@ func NewNamedSchemaOrReference(in *yaml.Node, context *compiler.Context) (*Named
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2307,7 +2349,7 @@ func NewNamedSchemaOrReference(in *yaml.Node, context *compiler.Context) (*Named
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// SchemaOrReference value = 2;
@@ -2330,14 +2372,16 @@ func NewNamedSecuritySchemeOrReference(in *yaml.Node, context *compiler.Context)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2345,7 +2389,7 @@ func NewNamedSecuritySchemeOrReference(in *yaml.Node, context *compiler.Context)
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// SecuritySchemeOrReference value = 2;
@@ -2368,14 +2412,16 @@ func NewNamedServerVariable(in *yaml.Node, context *compiler.Context) (*NamedSer
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2383,7 +2429,7 @@ func NewNamedServerVariable(in *yaml.Node, context *compiler.Context) (*NamedSer
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// ServerVariable value = 2;
@@ -2406,14 +2452,16 @@ func NewNamedString(in *yaml.Node, context *compiler.Context) (*NamedString, err
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2421,7 +2469,7 @@ func NewNamedString(in *yaml.Node, context *compiler.Context) (*NamedString, err
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string value = 2;
@@ -2430,7 +2478,7 @@ func NewNamedString(in *yaml.Node, context *compiler.Context) (*NamedString, err
 			x.Value, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for value: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -2444,14 +2492,16 @@ func NewNamedStringArray(in *yaml.Node, context *compiler.Context) (*NamedString
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"name", "value"}
-		var allowedPatterns []*regexp.Regexp
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"name", "value"}
+			var allowedPatterns []*regexp.Regexp
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2459,7 +2509,7 @@ func NewNamedStringArray(in *yaml.Node, context *compiler.Context) (*NamedString
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// StringArray value = 2;
@@ -2482,14 +2532,16 @@ func NewOauthFlow(in *yaml.Node, context *compiler.Context) (*OauthFlow, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"authorizationUrl", "refreshUrl", "scopes", "tokenUrl"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"authorizationUrl", "refreshUrl", "scopes", "tokenUrl"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string authorization_url = 1;
 		v1 := compiler.MapValueForKey(m, "authorizationUrl")
@@ -2497,7 +2549,7 @@ func NewOauthFlow(in *yaml.Node, context *compiler.Context) (*OauthFlow, error)
 			x.AuthorizationUrl, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for authorizationUrl: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string token_url = 2;
@@ -2506,7 +2558,7 @@ func NewOauthFlow(in *yaml.Node, context *compiler.Context) (*OauthFlow, error)
 			x.TokenUrl, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for tokenUrl: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string refresh_url = 3;
@@ -2515,7 +2567,7 @@ func NewOauthFlow(in *yaml.Node, context *compiler.Context) (*OauthFlow, error)
 			x.RefreshUrl, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for refreshUrl: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Strings scopes = 4;
@@ -2569,14 +2621,16 @@ func NewOauthFlows(in *yaml.Node, context *compiler.Context) (*OauthFlows, error
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"authorizationCode", "clientCredentials", "implicit", "password"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"authorizationCode", "clientCredentials", "implicit", "password"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// OauthFlow implicit = 1;
 		v1 := compiler.MapValueForKey(m, "implicit")
@@ -2656,7 +2710,7 @@ func NewObject(in *yaml.Node, context *compiler.Context) (*Object, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedAny additional_properties = 1;
 		// MAP: Any
@@ -2698,20 +2752,22 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"responses"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"callbacks", "deprecated", "description", "externalDocs", "operationId", "parameters", "requestBody", "responses", "security", "servers", "summary", "tags"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"responses"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"callbacks", "deprecated", "description", "externalDocs", "operationId", "parameters", "requestBody", "responses", "security", "servers", "summary", "tags"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// repeated string tags = 1;
 		v1 := compiler.MapValueForKey(m, "tags")
@@ -2721,7 +2777,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 				x.Tags = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for tags: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string summary = 2;
@@ -2730,7 +2786,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 			x.Summary, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for summary: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -2739,7 +2795,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// ExternalDocs external_docs = 4;
@@ -2757,7 +2813,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 			x.OperationId, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for operationId: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated ParameterOrReference parameters = 6;
@@ -2809,7 +2865,7 @@ func NewOperation(in *yaml.Node, context *compiler.Context) (*Operation, error)
 			x.Deprecated, ok = compiler.BoolForScalarNode(v10)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for deprecated: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated SecurityRequirement security = 11;
@@ -2886,20 +2942,20 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"in", "name"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"allowEmptyValue", "allowReserved", "content", "deprecated", "description", "example", "examples", "explode", "in", "name", "required", "schema", "style"}
 		allowedPatterns := []*regexp.Regexp{pattern1}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -2907,7 +2963,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string in = 2;
@@ -2916,7 +2972,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.In, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -2925,7 +2981,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool required = 4;
@@ -2934,7 +2990,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Required, ok = compiler.BoolForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool deprecated = 5;
@@ -2943,7 +2999,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Deprecated, ok = compiler.BoolForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for deprecated: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool allow_empty_value = 6;
@@ -2952,7 +3008,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.AllowEmptyValue, ok = compiler.BoolForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for allowEmptyValue: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string style = 7;
@@ -2961,7 +3017,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Style, ok = compiler.StringForScalarNode(v7)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for style: %s", compiler.Display(v7))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool explode = 8;
@@ -2970,7 +3026,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.Explode, ok = compiler.BoolForScalarNode(v8)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for explode: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool allow_reserved = 9;
@@ -2979,7 +3035,7 @@ func NewParameter(in *yaml.Node, context *compiler.Context) (*Parameter, error)
 			x.AllowReserved, ok = compiler.BoolForScalarNode(v9)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for allowReserved: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// SchemaOrReference schema = 10;
@@ -3091,7 +3147,7 @@ func NewParameterOrReference(in *yaml.Node, context *compiler.Context) (*Paramet
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid ParameterOrReference")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -3104,7 +3160,7 @@ func NewParametersOrReferences(in *yaml.Node, context *compiler.Context) (*Param
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedParameterOrReference additional_properties = 1;
 		// MAP: ParameterOrReference
@@ -3134,14 +3190,16 @@ func NewPathItem(in *yaml.Node, context *compiler.Context) (*PathItem, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"$ref", "delete", "description", "get", "head", "options", "parameters", "patch", "post", "put", "servers", "summary", "trace"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"$ref", "delete", "description", "get", "head", "options", "parameters", "patch", "post", "put", "servers", "summary", "trace"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string _ref = 1;
 		v1 := compiler.MapValueForKey(m, "$ref")
@@ -3149,7 +3207,7 @@ func NewPathItem(in *yaml.Node, context *compiler.Context) (*PathItem, error) {
 			x.XRef, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for $ref: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string summary = 2;
@@ -3158,7 +3216,7 @@ func NewPathItem(in *yaml.Node, context *compiler.Context) (*PathItem, error) {
 			x.Summary, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for summary: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -3167,7 +3225,7 @@ func NewPathItem(in *yaml.Node, context *compiler.Context) (*PathItem, error) {
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Operation get = 4;
@@ -3316,14 +3374,16 @@ func NewPaths(in *yaml.Node, context *compiler.Context) (*Paths, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{}
-		allowedPatterns := []*regexp.Regexp{pattern2, pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{}
+			allowedPatterns := []*regexp.Regexp{pattern2, pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// repeated NamedPathItem path = 1;
 		// MAP: PathItem ^/
@@ -3386,7 +3446,7 @@ func NewProperties(in *yaml.Node, context *compiler.Context) (*Properties, error
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedSchemaOrReference additional_properties = 1;
 		// MAP: SchemaOrReference
@@ -3416,13 +3476,13 @@ func NewReference(in *yaml.Node, context *compiler.Context) (*Reference, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"$ref"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		// string _ref = 1;
 		v1 := compiler.MapValueForKey(m, "$ref")
@@ -3430,7 +3490,7 @@ func NewReference(in *yaml.Node, context *compiler.Context) (*Reference, error)
 			x.XRef, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for $ref: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string summary = 2;
@@ -3439,7 +3499,7 @@ func NewReference(in *yaml.Node, context *compiler.Context) (*Reference, error)
 			x.Summary, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for summary: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -3448,7 +3508,7 @@ func NewReference(in *yaml.Node, context *compiler.Context) (*Reference, error)
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 	}
@@ -3462,7 +3522,7 @@ func NewRequestBodiesOrReferences(in *yaml.Node, context *compiler.Context) (*Re
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedRequestBodyOrReference additional_properties = 1;
 		// MAP: RequestBodyOrReference
@@ -3492,20 +3552,20 @@ func NewRequestBody(in *yaml.Node, context *compiler.Context) (*RequestBody, err
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"content"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"content", "description", "required"}
 		allowedPatterns := []*regexp.Regexp{pattern1}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string description = 1;
 		v1 := compiler.MapValueForKey(m, "description")
@@ -3513,7 +3573,7 @@ func NewRequestBody(in *yaml.Node, context *compiler.Context) (*RequestBody, err
 			x.Description, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// MediaTypes content = 2;
@@ -3531,7 +3591,7 @@ func NewRequestBody(in *yaml.Node, context *compiler.Context) (*RequestBody, err
 			x.Required, ok = compiler.BoolForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny specification_extension = 4;
@@ -3607,7 +3667,7 @@ func NewRequestBodyOrReference(in *yaml.Node, context *compiler.Context) (*Reque
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid RequestBodyOrReference")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -3620,20 +3680,20 @@ func NewResponse(in *yaml.Node, context *compiler.Context) (*Response, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"description"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"content", "description", "headers", "links"}
 		allowedPatterns := []*regexp.Regexp{pattern1}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string description = 1;
 		v1 := compiler.MapValueForKey(m, "description")
@@ -3641,7 +3701,7 @@ func NewResponse(in *yaml.Node, context *compiler.Context) (*Response, error) {
 			x.Description, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// HeadersOrReferences headers = 2;
@@ -3744,7 +3804,7 @@ func NewResponseOrReference(in *yaml.Node, context *compiler.Context) (*Response
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid ResponseOrReference")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -3757,14 +3817,16 @@ func NewResponses(in *yaml.Node, context *compiler.Context) (*Responses, error)
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"default"}
-		allowedPatterns := []*regexp.Regexp{pattern3, pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"default"}
+			allowedPatterns := []*regexp.Regexp{pattern3, pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// ResponseOrReference default = 1;
 		v1 := compiler.MapValueForKey(m, "default")
@@ -3836,7 +3898,7 @@ func NewResponsesOrReferences(in *yaml.Node, context *compiler.Context) (*Respon
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedResponseOrReference additional_properties = 1;
 		// MAP: ResponseOrReference
@@ -3866,14 +3928,14 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		allowedKeys := []string{"additionalProperties", "allOf", "anyOf", "default", "deprecated", "description", "discriminator", "enum", "example", "exclusiveMaximum", "exclusiveMinimum", "externalDocs", "format", "items", "maxItems", "maxLength", "maxProperties", "maximum", "minItems", "minLength", "minProperties", "minimum", "multipleOf", "not", "nullable", "oneOf", "pattern", "properties", "readOnly", "required", "title", "type", "uniqueItems", "writeOnly", "xml"}
 		allowedPatterns := []*regexp.Regexp{pattern1}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// bool nullable = 1;
 		v1 := compiler.MapValueForKey(m, "nullable")
@@ -3881,7 +3943,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Nullable, ok = compiler.BoolForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for nullable: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Discriminator discriminator = 2;
@@ -3899,7 +3961,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.ReadOnly, ok = compiler.BoolForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for readOnly: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool write_only = 4;
@@ -3908,7 +3970,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.WriteOnly, ok = compiler.BoolForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for writeOnly: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// Xml xml = 5;
@@ -3944,7 +4006,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Deprecated, ok = compiler.BoolForScalarNode(v8)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for deprecated: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string title = 9;
@@ -3953,7 +4015,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Title, ok = compiler.StringForScalarNode(v9)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for title: %s", compiler.Display(v9))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// float multiple_of = 10;
@@ -3964,7 +4026,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MultipleOf = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for multipleOf: %s", compiler.Display(v10))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// float maximum = 11;
@@ -3975,7 +4037,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.Maximum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for maximum: %s", compiler.Display(v11))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_maximum = 12;
@@ -3984,7 +4046,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.ExclusiveMaximum, ok = compiler.BoolForScalarNode(v12)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMaximum: %s", compiler.Display(v12))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// float minimum = 13;
@@ -3995,7 +4057,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.Minimum = v
 			} else {
 				message := fmt.Sprintf("has unexpected value for minimum: %s", compiler.Display(v13))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool exclusive_minimum = 14;
@@ -4004,7 +4066,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.ExclusiveMinimum, ok = compiler.BoolForScalarNode(v14)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for exclusiveMinimum: %s", compiler.Display(v14))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_length = 15;
@@ -4015,7 +4077,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MaxLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxLength: %s", compiler.Display(v15))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_length = 16;
@@ -4026,7 +4088,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MinLength = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minLength: %s", compiler.Display(v16))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string pattern = 17;
@@ -4035,7 +4097,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Pattern, ok = compiler.StringForScalarNode(v17)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for pattern: %s", compiler.Display(v17))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_items = 18;
@@ -4046,7 +4108,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MaxItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxItems: %s", compiler.Display(v18))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_items = 19;
@@ -4057,7 +4119,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MinItems = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minItems: %s", compiler.Display(v19))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool unique_items = 20;
@@ -4066,7 +4128,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.UniqueItems, ok = compiler.BoolForScalarNode(v20)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for uniqueItems: %s", compiler.Display(v20))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 max_properties = 21;
@@ -4077,7 +4139,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MaxProperties = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for maxProperties: %s", compiler.Display(v21))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// int64 min_properties = 22;
@@ -4088,7 +4150,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.MinProperties = int64(t)
 			} else {
 				message := fmt.Sprintf("has unexpected value for minProperties: %s", compiler.Display(v22))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated string required = 23;
@@ -4099,7 +4161,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 				x.Required = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for required: %s", compiler.Display(v23))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated Any enum = 24;
@@ -4124,7 +4186,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Type, ok = compiler.StringForScalarNode(v25)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v25))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated SchemaOrReference all_of = 26;
@@ -4226,7 +4288,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Description, ok = compiler.StringForScalarNode(v34)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v34))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string format = 35;
@@ -4235,7 +4297,7 @@ func NewSchema(in *yaml.Node, context *compiler.Context) (*Schema, error) {
 			x.Format, ok = compiler.StringForScalarNode(v35)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for format: %s", compiler.Display(v35))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny specification_extension = 36;
@@ -4311,7 +4373,7 @@ func NewSchemaOrReference(in *yaml.Node, context *compiler.Context) (*SchemaOrRe
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid SchemaOrReference")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -4324,7 +4386,7 @@ func NewSchemasOrReferences(in *yaml.Node, context *compiler.Context) (*SchemasO
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedSchemaOrReference additional_properties = 1;
 		// MAP: SchemaOrReference
@@ -4354,7 +4416,7 @@ func NewSecurityRequirement(in *yaml.Node, context *compiler.Context) (*Security
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedStringArray additional_properties = 1;
 		// MAP: StringArray
@@ -4384,20 +4446,20 @@ func NewSecurityScheme(in *yaml.Node, context *compiler.Context) (*SecuritySchem
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		requiredKeys := []string{"type"}
 		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
 		if len(missingKeys) > 0 {
 			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
 		}
 		allowedKeys := []string{"bearerFormat", "description", "flows", "in", "name", "openIdConnectUrl", "scheme", "type"}
 		allowedPatterns := []*regexp.Regexp{pattern1}
 		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
 		if len(invalidKeys) > 0 {
 			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+			errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
 		}
 		// string type = 1;
 		v1 := compiler.MapValueForKey(m, "type")
@@ -4405,7 +4467,7 @@ func NewSecurityScheme(in *yaml.Node, context *compiler.Context) (*SecuritySchem
 			x.Type, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for type: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 2;
@@ -4414,7 +4476,7 @@ func NewSecurityScheme(in *yaml.Node, context *compiler.Context) (*SecuritySchem
 			x.Description, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string name = 3;
@@ -4423,7 +4485,7 @@ func NewSecurityScheme(in *yaml.Node, context *compiler.Context) (*SecuritySchem
 			x.Name, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string in = 4;
@@ -4432,7 +4494,7 @@ func NewSecurityScheme(in *yaml.Node, context *compiler.Context) (*SecuritySchem
 			x.In, ok = compiler.StringForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for in: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string scheme = 5;
@@ -4441,7 +4503,7 @@ func NewSecurityScheme(in *yaml.Node, context *compiler.Context) (*SecuritySchem
 			x.Scheme, ok = compiler.StringForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for scheme: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string bearer_format = 6;
@@ -4450,7 +4512,7 @@ func NewSecurityScheme(in *yaml.Node, context *compiler.Context) (*SecuritySchem
 			x.BearerFormat, ok = compiler.StringForScalarNode(v6)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for bearerFormat: %s", compiler.Display(v6))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// OauthFlows flows = 7;
@@ -4468,7 +4530,7 @@ func NewSecurityScheme(in *yaml.Node, context *compiler.Context) (*SecuritySchem
 			x.OpenIdConnectUrl, ok = compiler.StringForScalarNode(v8)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for openIdConnectUrl: %s", compiler.Display(v8))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny specification_extension = 9;
@@ -4544,7 +4606,7 @@ func NewSecuritySchemeOrReference(in *yaml.Node, context *compiler.Context) (*Se
 		errors = make([]error, 0)
 	} else {
 		message := fmt.Sprintf("contains an invalid SecuritySchemeOrReference")
-		err := compiler.NewError(context, message)
+		err := compiler.NewErrorWithCode(context, compiler.CodeInvalidAlternative, message)
 		errors = []error{err}
 	}
 	return x, compiler.NewErrorGroupOrNil(errors)
@@ -4557,7 +4619,7 @@ func NewSecuritySchemesOrReferences(in *yaml.Node, context *compiler.Context) (*
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedSecuritySchemeOrReference additional_properties = 1;
 		// MAP: SecuritySchemeOrReference
@@ -4587,20 +4649,22 @@ func NewServer(in *yaml.Node, context *compiler.Context) (*Server, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"url"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"description", "url", "variables"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"url"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"description", "url", "variables"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string url = 1;
 		v1 := compiler.MapValueForKey(m, "url")
@@ -4608,7 +4672,7 @@ func NewServer(in *yaml.Node, context *compiler.Context) (*Server, error) {
 			x.Url, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for url: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 2;
@@ -4617,7 +4681,7 @@ func NewServer(in *yaml.Node, context *compiler.Context) (*Server, error) {
 			x.Description, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// ServerVariables variables = 3;
@@ -4671,20 +4735,22 @@ func NewServerVariable(in *yaml.Node, context *compiler.Context) (*ServerVariabl
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"default"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"default", "description", "enum"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"default"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"default", "description", "enum"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// repeated string enum = 1;
 		v1 := compiler.MapValueForKey(m, "enum")
@@ -4694,7 +4760,7 @@ func NewServerVariable(in *yaml.Node, context *compiler.Context) (*ServerVariabl
 				x.Enum = compiler.StringArrayForSequenceNode(v)
 			} else {
 				message := fmt.Sprintf("has unexpected value for enum: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string default = 2;
@@ -4703,7 +4769,7 @@ func NewServerVariable(in *yaml.Node, context *compiler.Context) (*ServerVariabl
 			x.Default, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for default: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 3;
@@ -4712,7 +4778,7 @@ func NewServerVariable(in *yaml.Node, context *compiler.Context) (*ServerVariabl
 			x.Description, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny specification_extension = 4;
@@ -4757,7 +4823,7 @@ func NewServerVariables(in *yaml.Node, context *compiler.Context) (*ServerVariab
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedServerVariable additional_properties = 1;
 		// MAP: ServerVariable
@@ -4829,7 +4895,7 @@ func NewStrings(in *yaml.Node, context *compiler.Context) (*Strings, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
 		// repeated NamedString additional_properties = 1;
 		// MAP: string
@@ -4855,20 +4921,22 @@ func NewTag(in *yaml.Node, context *compiler.Context) (*Tag, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		requiredKeys := []string{"name"}
-		missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
-		if len(missingKeys) > 0 {
-			message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
-		}
-		allowedKeys := []string{"description", "externalDocs", "name"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			requiredKeys := []string{"name"}
+			missingKeys := compiler.MissingKeysInMap(m, requiredKeys)
+			if len(missingKeys) > 0 {
+				message := fmt.Sprintf("is missing required %s: %+v", compiler.PluralProperties(len(missingKeys)), strings.Join(missingKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeMissingRequiredProperty, message))
+			}
+			allowedKeys := []string{"description", "externalDocs", "name"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -4876,7 +4944,7 @@ func NewTag(in *yaml.Node, context *compiler.Context) (*Tag, error) {
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string description = 2;
@@ -4885,7 +4953,7 @@ func NewTag(in *yaml.Node, context *compiler.Context) (*Tag, error) {
 			x.Description, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for description: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// ExternalDocs external_docs = 3;
@@ -4939,14 +5007,16 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 	m, ok := compiler.UnpackMap(in)
 	if !ok {
 		message := fmt.Sprintf("has unexpected value: %+v (%T)", in, in)
-		errors = append(errors, compiler.NewError(context, message))
+		errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 	} else {
-		allowedKeys := []string{"attribute", "name", "namespace", "prefix", "wrapped"}
-		allowedPatterns := []*regexp.Regexp{pattern1}
-		invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
-		if len(invalidKeys) > 0 {
-			message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
-			errors = append(errors, compiler.NewError(context, message))
+		if compiler.ValidationEnabled(context) {
+			allowedKeys := []string{"attribute", "name", "namespace", "prefix", "wrapped"}
+			allowedPatterns := []*regexp.Regexp{pattern1}
+			invalidKeys := compiler.InvalidKeysInMap(m, allowedKeys, allowedPatterns)
+			if len(invalidKeys) > 0 {
+				message := fmt.Sprintf("has invalid %s: %+v", compiler.PluralProperties(len(invalidKeys)), strings.Join(invalidKeys, ", "))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeInvalidProperty, message))
+			}
 		}
 		// string name = 1;
 		v1 := compiler.MapValueForKey(m, "name")
@@ -4954,7 +5024,7 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 			x.Name, ok = compiler.StringForScalarNode(v1)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for name: %s", compiler.Display(v1))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string namespace = 2;
@@ -4963,7 +5033,7 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 			x.Namespace, ok = compiler.StringForScalarNode(v2)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for namespace: %s", compiler.Display(v2))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// string prefix = 3;
@@ -4972,7 +5042,7 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 			x.Prefix, ok = compiler.StringForScalarNode(v3)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for prefix: %s", compiler.Display(v3))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool attribute = 4;
@@ -4981,7 +5051,7 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 			x.Attribute, ok = compiler.BoolForScalarNode(v4)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for attribute: %s", compiler.Display(v4))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// bool wrapped = 5;
@@ -4990,7 +5060,7 @@ func NewXml(in *yaml.Node, context *compiler.Context) (*Xml, error) {
 			x.Wrapped, ok = compiler.BoolForScalarNode(v5)
 			if !ok {
 				message := fmt.Sprintf("has unexpected value for wrapped: %s", compiler.Display(v5))
-				errors = append(errors, compiler.NewError(context, message))
+				errors = append(errors, compiler.NewErrorWithCode(context, compiler.CodeUnexpectedValue, message))
 			}
 		}
 		// repeated NamedAny specification_extension = 6;
@@ -0,0 +1,85 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// AnonymizedText is the placeholder for values removed by Anonymize.
+const AnonymizedText = "redacted"
+
+// Anonymize replaces text that may contain internal details or personal
+// data with placeholders, so that a document can be shared. It replaces
+// descriptions, example values, the values of specification extensions,
+// and string defaults of schemas and server variables. Defaults that are
+// restricted by an enum are kept, since the enum values remain in the
+// document. It returns the number of values that were replaced.
+func Anonymize(d *Document) int {
+	if d == nil {
+		return 0
+	}
+	return anonymizeMessage(d.ProtoReflect())
+}
+
+func anonymizeMessage(m protoreflect.Message) int {
+	count := 0
+	switch v := m.Interface().(type) {
+	case *Schema:
+		if v.Default.GetString_() != "" && len(v.Enum) == 0 {
+			v.Default = &DefaultType{Oneof: &DefaultType_String_{String_: AnonymizedText}}
+			count++
+		}
+	case *ServerVariable:
+		if v.Default != "" && len(v.Enum) == 0 {
+			v.Default = AnonymizedText
+			count++
+		}
+	}
+	if f := m.Descriptor().Fields().ByName("description"); f != nil && f.Kind() == protoreflect.StringKind {
+		if m.Get(f).String() != "" {
+			m.Set(f, protoreflect.ValueOfString(AnonymizedText))
+			count++
+		}
+	}
+	_, isExample := m.Interface().(*Example)
+	m.Range(func(f protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if f.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		if f.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				if extension, ok := list.Get(i).Message().Interface().(*NamedAny); ok && f.Name() == "specification_extension" {
+					extension.Value = &Any{Yaml: AnonymizedText + "\n"}
+					count++
+				} else {
+					count += anonymizeMessage(list.Get(i).Message())
+				}
+			}
+		} else if any, ok := v.Message().Interface().(*Any); ok {
+			// Example values are replaced, other values such as enums
+			// and link parameters are kept.
+			if f.Name() == "example" || (isExample && f.Name() == "value") {
+				any.Value, any.Yaml = nil, AnonymizedText+"\n"
+				count++
+			}
+		} else {
+			count += anonymizeMessage(v.Message())
+		}
+		return true
+	})
+	return count
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"testing"
+)
+
+const anonymizeDocument = `
+openapi: 3.0.0
+info:
+  title: Things
+  description: Internal.
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Color:
+      type: string
+      enum: [red, green]
+      default: red
+      x-owner: someone@example.com
+    Name:
+      type: string
+      default: Someone
+      example: Someone
+  links:
+    self:
+      operationId: getThing
+      parameters:
+        id: $response.body#/id
+`
+
+func TestAnonymize(t *testing.T) {
+	d, err := ParseDocument([]byte(anonymizeDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if count := Anonymize(d); count != 4 {
+		t.Errorf("Anonymize replaced %d values, want 4", count)
+	}
+	if d.Info.Description != AnonymizedText || d.Info.Title != "Things" {
+		t.Errorf("Unexpected info: %+v", d.Info)
+	}
+	color := schemaNamed(t, d, "Color")
+	if color.Default.GetString_() != "red" || len(color.Enum) != 2 || color.Enum[0].Yaml != "red\n" {
+		t.Errorf("Enum or default of Color was changed: %+v", color)
+	}
+	if color.SpecificationExtension[0].Value.Yaml != AnonymizedText+"\n" {
+		t.Errorf("Extension of Color was not replaced: %+v", color.SpecificationExtension[0])
+	}
+	name := schemaNamed(t, d, "Name")
+	if name.Default.GetString_() != AnonymizedText || name.Example.Yaml != AnonymizedText+"\n" {
+		t.Errorf("Default or example of Name was not replaced: %+v", name)
+	}
+	link := d.Components.Links.AdditionalProperties[0].Value.GetLink()
+	if link.Parameters.GetAny().GetYaml() == AnonymizedText+"\n" ||
+		link.Parameters.GetExpression().GetAdditionalProperties()[0].GetValue().GetYaml() == AnonymizedText+"\n" {
+		t.Errorf("Link parameter was replaced: %+v", link.Parameters)
+	}
+}
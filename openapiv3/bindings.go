@@ -0,0 +1,180 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"strconv"
+	"strings"
+)
+
+// An OperationBinding describes how an operation binds the parts of HTTP
+// requests: the variables of its path template, its query parameters and
+// its request body.
+type OperationBinding struct {
+	// Method is the uppercase HTTP method of the operation.
+	Method string
+	// Path is the path template of the operation, e.g. "/v1/shelves/{shelf}".
+	Path      string
+	Operation *Operation
+	// Keys locate the operation in the document.
+	Keys            []string
+	PathParameters  []*ParameterBinding
+	QueryParameters []*ParameterBinding
+	// Body is nil if the operation has no request body.
+	Body *BodyBinding
+}
+
+// A ParameterBinding describes a path or query parameter of an operation.
+type ParameterBinding struct {
+	Name string
+	// Type and Format are those of the schema of the parameter. Type is
+	// empty if the schema is a reference.
+	Type   string
+	Format string
+	// ItemType is the type of the items of array parameters.
+	ItemType string
+	// Keys locate the parameter in the document.
+	Keys []string
+}
+
+// A BodyBinding describes the JSON request body of an operation.
+type BodyBinding struct {
+	// Schema is the name of the component schema of the body, or empty
+	// if the schema is inline.
+	Schema string
+	// Type is the type of an inline schema.
+	Type string
+	// Keys locate the request body in the document.
+	Keys []string
+}
+
+// OperationBindings returns the bindings of the operations of a document
+// in the order of ForEachOperation. Parameters of path items are inherited
+// by their operations unless the operations override them, and references
+// to parameter and request body components are followed.
+func OperationBindings(d *Document) []*OperationBinding {
+	bindings := make([]*OperationBinding, 0)
+	ForEachOperation(d, func(path, method string, item *PathItem, operation *Operation) {
+		b := &OperationBinding{
+			Method:    strings.ToUpper(method),
+			Path:      path,
+			Operation: operation,
+			Keys:      []string{"paths", path, method},
+		}
+		parameters := make([]*ParameterBinding, 0)
+		locations := make([]string, 0)
+		add := func(p *ParameterOrReference, keys []string) {
+			parameter := parameterForReference(d, p)
+			if parameter == nil {
+				return
+			}
+			binding := &ParameterBinding{Name: parameter.Name, Keys: keys}
+			if schema := parameter.Schema.GetSchema(); schema != nil {
+				binding.Type = schema.Type
+				binding.Format = schema.Format
+				if items := schema.GetItems().GetSchemaOrReference(); len(items) > 0 {
+					binding.ItemType = items[0].GetSchema().GetType()
+				}
+			}
+			for i, existing := range parameters {
+				if existing.Name == binding.Name && locations[i] == parameter.In {
+					parameters[i] = binding
+					return
+				}
+			}
+			parameters = append(parameters, binding)
+			locations = append(locations, parameter.In)
+		}
+		for i, p := range item.Parameters {
+			add(p, []string{"paths", path, "parameters", strconv.Itoa(i)})
+		}
+		for i, p := range operation.Parameters {
+			add(p, appendKeys(b.Keys, "parameters", strconv.Itoa(i)))
+		}
+		for i, parameter := range parameters {
+			switch locations[i] {
+			case "path":
+				b.PathParameters = append(b.PathParameters, parameter)
+			case "query":
+				b.QueryParameters = append(b.QueryParameters, parameter)
+			}
+		}
+		if body := requestBodyForReference(d, operation.RequestBody); body != nil {
+			for _, pair := range body.GetContent().GetAdditionalProperties() {
+				if pair.Name != "application/json" {
+					continue
+				}
+				b.Body = &BodyBinding{Keys: appendKeys(b.Keys, "requestBody", "content", pair.Name, "schema")}
+				if ref := pair.Value.GetSchema().GetReference(); ref != nil {
+					b.Body.Schema = schemaNameForRef(ref.XRef)
+				} else {
+					b.Body.Type = pair.Value.GetSchema().GetSchema().GetType()
+				}
+			}
+		}
+		bindings = append(bindings, b)
+	})
+	return bindings
+}
+
+// maxComponentReferences limits the references that are followed to find
+// a component, so that circular references can't loop forever.
+const maxComponentReferences = 32
+
+// parameterForReference returns a parameter or the parameter component
+// that it refers to, or nil if the reference can't be resolved.
+func parameterForReference(d *Document, p *ParameterOrReference) *Parameter {
+	for i := 0; i < maxComponentReferences && p != nil; i++ {
+		if parameter := p.GetParameter(); parameter != nil {
+			return parameter
+		}
+		name, ok := localComponentName(p.GetReference().GetXRef(), "parameters")
+		p = nil
+		for _, pair := range d.GetComponents().GetParameters().GetAdditionalProperties() {
+			if ok && pair.Name == name {
+				p = pair.Value
+			}
+		}
+	}
+	return nil
+}
+
+// requestBodyForReference returns a request body or the request body
+// component that it refers to, or nil if the reference can't be resolved.
+func requestBodyForReference(d *Document, r *RequestBodyOrReference) *RequestBody {
+	for i := 0; i < maxComponentReferences && r != nil; i++ {
+		if body := r.GetRequestBody(); body != nil {
+			return body
+		}
+		name, ok := localComponentName(r.GetReference().GetXRef(), "requestBodies")
+		r = nil
+		for _, pair := range d.GetComponents().GetRequestBodies().GetAdditionalProperties() {
+			if ok && pair.Name == name {
+				r = pair.Value
+			}
+		}
+	}
+	return nil
+}
+
+// localComponentName returns the name of the component of a section that
+// a local reference refers to.
+func localComponentName(ref, section string) (string, bool) {
+	prefix := "#/components/" + section + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", false
+	}
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(strings.TrimPrefix(ref, prefix)), true
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"testing"
+)
+
+const bindingsDocument = `
+openapi: 3.0.0
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /shelves/{shelf}/books:
+    parameters:
+    - name: shelf
+      in: path
+      required: true
+      schema:
+        type: string
+    - $ref: '#/components/parameters/PageSize'
+    post:
+      parameters:
+      - name: shelf
+        in: path
+        required: true
+        schema:
+          type: integer
+      requestBody:
+        $ref: '#/components/requestBodies/Book'
+      responses:
+        '200':
+          description: OK
+components:
+  parameters:
+    PageSize:
+      name: page_size
+      in: query
+      schema:
+        type: array
+        items:
+          type: integer
+  requestBodies:
+    Book:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Book'
+`
+
+func TestOperationBindings(t *testing.T) {
+	d, err := ParseDocument([]byte(bindingsDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	bindings := OperationBindings(d)
+	if len(bindings) != 1 {
+		t.Fatalf("Unexpected bindings: %+v", bindings)
+	}
+	b := bindings[0]
+	if b.Method != "POST" || b.Path != "/shelves/{shelf}/books" {
+		t.Errorf("Unexpected operation: %s %s", b.Method, b.Path)
+	}
+	if len(b.PathParameters) != 1 || b.PathParameters[0].Type != "integer" {
+		t.Errorf("Path parameter wasn't overridden: %+v", b.PathParameters)
+	}
+	if len(b.QueryParameters) != 1 || b.QueryParameters[0].Name != "page_size" ||
+		b.QueryParameters[0].Type != "array" || b.QueryParameters[0].ItemType != "integer" {
+		t.Errorf("Unexpected query parameters: %+v", b.QueryParameters)
+	}
+	if b.Body == nil || b.Body.Schema != "Book" {
+		t.Errorf("Unexpected body: %+v", b.Body)
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// CodeSamplesExtensionName is the name of the extension that holds the
+// code samples of an operation, as read by documentation renderers
+// like Redoc.
+const CodeSamplesExtensionName = "x-codeSamples"
+
+// CodeSampleLanguages are the languages that AddCodeSamples supports.
+var CodeSampleLanguages = []string{"curl", "go"}
+
+// A codeSample is a request written in a language.
+type codeSample struct {
+	Lang   string
+	Label  string
+	Source string
+}
+
+// AddCodeSamples adds an x-codeSamples extension with a sample request in
+// each of the given languages to each operation of a document. Requests
+// are sent to the first server of the document with placeholders for
+// path parameters and, if the operation has a request body, an example
+// body that is generated like the bodies of mock responses (see
+// NewMockHandler) but without readOnly properties and with writeOnly
+// properties. Multipart
+// request bodies are sent as parts with the example values of the
+// properties of their schemas; parts with content types other than text
+// and JSON are sent as files that are named like their properties.
+// Operations that already have code samples are skipped. It returns the
+// number of operations that were changed.
+func AddCodeSamples(d *Document, languages []string) (int, error) {
+	for _, language := range languages {
+		if !isCodeSampleLanguage(language) {
+			return 0, fmt.Errorf("unsupported code sample language %q, expected one of %s", language, strings.Join(CodeSampleLanguages, ", "))
+		}
+	}
+	base := "http://localhost"
+	if servers := d.GetServers(); len(servers) > 0 {
+		if u := strings.TrimSuffix(serverURL(servers[0]), "/"); strings.Contains(u, "://") {
+			base = u
+		} else {
+			base += u
+		}
+	}
+	h := &mockHandler{document: d, request: true}
+	count := 0
+	ForEachOperation(d, func(path, method string, item *PathItem, operation *Operation) {
+		if _, ok := GetExtension(operation, CodeSamplesExtensionName); ok {
+			return
+		}
+		if _, ok := GetExtension(operation, "x-code-samples"); ok {
+			return
+		}
+		request := &sampleRequest{
+			method: strings.ToUpper(method),
+			url:    base + pathParameterRegex.ReplaceAllStringFunc(path, placeholder),
+		}
+		if content := h.requestBody(operation.GetRequestBody()).GetContent().GetAdditionalProperties(); len(content) > 0 {
+			request.contentType = content[0].Name
+			if IsMultipart(content[0].Name) {
+				request.parts = h.sampleParts(content[0].Value)
+			} else {
+				request.body = sampleBody(content[0].Name, h.mediaTypeValue(content[0].Value))
+			}
+		}
+		samples := compiler.NewSequenceNode()
+		for _, language := range languages {
+			var sample codeSample
+			switch language {
+			case "curl":
+				sample = codeSample{Lang: "Shell", Label: "curl", Source: request.curl()}
+			case "go":
+				sample = codeSample{Lang: "Go", Label: "Go", Source: request.golang()}
+			}
+			node := compiler.NewMappingNode()
+			node.Content = append(node.Content,
+				compiler.NewScalarNodeForString("lang"), compiler.NewScalarNodeForString(sample.Lang),
+				compiler.NewScalarNodeForString("label"), compiler.NewScalarNodeForString(sample.Label),
+				compiler.NewScalarNodeForString("source"), compiler.NewScalarNodeForString(sample.Source))
+			samples.Content = append(samples.Content, node)
+		}
+		SetExtension(operation, CodeSamplesExtensionName, samples)
+		count++
+	})
+	return count, nil
+}
+
+func isCodeSampleLanguage(language string) bool {
+	for _, l := range CodeSampleLanguages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholder replaces a path parameter like {petId} with <petId>.
+func placeholder(parameter string) string {
+	return "<" + strings.Trim(parameter, "{}") + ">"
+}
+
+// requestBody resolves references to request body components.
+func (h *mockHandler) requestBody(r *RequestBodyOrReference) *RequestBody {
+	seen := make(map[string]bool)
+	for r != nil && r.GetRequestBody() == nil {
+		ref := r.GetReference().GetXRef()
+		if seen[ref] {
+			return nil
+		}
+		seen[ref] = true
+		r = nil
+		for _, pair := range h.document.GetComponents().GetRequestBodies().GetAdditionalProperties() {
+			if ref == "#/components/requestBodies/"+escapeJSONPointer(pair.Name) {
+				r = pair.Value
+			}
+		}
+	}
+	return r.GetRequestBody()
+}
+
+// sampleBody serializes the example value of a request body. Strings
+// are sent as they are unless the media type is JSON.
+func sampleBody(mediaType string, value interface{}) string {
+	if s, ok := value.(string); ok && !strings.Contains(mediaType, "json") {
+		return s
+	}
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(bytes)
+}
+
+// sampleParts returns the parts of a multipart request body. Arrays are
+// sent as repeated parts.
+func (h *mockHandler) sampleParts(mediaType *MediaType) []*samplePart {
+	var parts []*samplePart
+	for _, p := range MultipartParts(h.document, mediaType) {
+		schema := dereferenceSchema(h.document, p.Schema)
+		if schema.GetReadOnly() {
+			continue
+		}
+		contentType := strings.TrimSpace(strings.Split(p.ContentType, ",")[0])
+		values := []interface{}{h.schemaValue(p.Schema, make(map[string]bool))}
+		if items, ok := values[0].([]interface{}); ok && schema.GetType() == "array" {
+			values = items
+		}
+		for _, value := range values {
+			part := &samplePart{name: p.Name, contentType: contentType}
+			if contentType != "text/plain" && !strings.Contains(contentType, "json") {
+				part.filename = p.Name
+			} else {
+				part.content = sampleBody(contentType, value)
+			}
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// A sampleRequest is the request that code samples send.
+type sampleRequest struct {
+	method      string
+	url         string
+	contentType string
+	body        string
+	parts       []*samplePart // the parts of multipart bodies
+}
+
+// A samplePart is a part of a multipart request body. Parts with file
+// names are sent with the contents of those files.
+type samplePart struct {
+	name        string
+	contentType string
+	filename    string
+	content     string
+}
+
+// explicitContentType returns the content type of a part if it has to be
+// sent with the part. Text parts and parts with media ranges like image/*
+// are sent without content types.
+func (p *samplePart) explicitContentType() string {
+	if p.contentType == "text/plain" || strings.Contains(p.contentType, "*") {
+		return ""
+	}
+	return p.contentType
+}
+
+// curlForm returns the value of a curl -F option that sends a part.
+func (p *samplePart) curlForm() string {
+	value := p.content
+	if p.filename != "" {
+		value = "@" + p.filename
+	} else if strings.Contains(value, ";") || strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "@") || strings.HasPrefix(value, "<") {
+		// curl reads values with these characters as options or files
+		// unless they are quoted.
+		value = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+	}
+	if contentType := p.explicitContentType(); contentType != "" {
+		value += ";type=" + contentType
+	}
+	return p.name + "=" + value
+}
+
+// curl returns a curl command that sends a request.
+func (r *sampleRequest) curl() string {
+	lines := []string{"curl -X " + r.method + " " + shellQuote(r.url)}
+	switch {
+	case IsMultipart(r.contentType):
+		// curl sets the content type with the boundary of the parts.
+		for _, part := range r.parts {
+			lines = append(lines, "  -F "+shellQuote(part.curlForm()))
+		}
+	case r.contentType != "":
+		lines = append(lines,
+			"  -H "+shellQuote("Content-Type: "+r.contentType),
+			"  -d "+shellQuote(r.body))
+	}
+	return strings.Join(lines, " \\\n")
+}
+
+// shellQuote quotes a string for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// golang returns a Go program that sends a request with net/http.
+func (r *sampleRequest) golang() string {
+	if IsMultipart(r.contentType) {
+		return r.golangMultipart()
+	}
+	var b strings.Builder
+	b.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n")
+	if r.contentType != "" {
+		b.WriteString("\t\"strings\"\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+	body := "nil"
+	if r.contentType != "" {
+		fmt.Fprintf(&b, "\tbody := strings.NewReader(%s)\n", goQuote(r.body))
+		body = "body"
+	}
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%q, %q, %s)\n", r.method, r.url, body)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	if r.contentType != "" {
+		fmt.Fprintf(&b, "\treq.Header.Set(\"Content-Type\", %q)\n", r.contentType)
+	}
+	writeGoResponseHandling(&b)
+	return b.String()
+}
+
+// golangMultipart returns a Go program that sends a request with a
+// multipart body that is written with mime/multipart.
+func (r *sampleRequest) golangMultipart() string {
+	var b strings.Builder
+	files := false
+	for _, part := range r.parts {
+		files = files || part.filename != ""
+	}
+	b.WriteString("package main\n\nimport (\n\t\"bytes\"\n\t\"fmt\"\n\t\"io\"\n\t\"mime/multipart\"\n\t\"net/http\"\n\t\"net/textproto\"\n")
+	if files {
+		b.WriteString("\t\"os\"\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+	b.WriteString("\tbody := &bytes.Buffer{}\n")
+	b.WriteString("\tform := multipart.NewWriter(body)\n")
+	for _, part := range r.parts {
+		disposition := fmt.Sprintf("form-data; name=%q", part.name)
+		if part.filename != "" {
+			disposition += fmt.Sprintf("; filename=%q", part.filename)
+		}
+		b.WriteString("\tif part, err := form.CreatePart(textproto.MIMEHeader{\n")
+		fmt.Fprintf(&b, "\t\t\"Content-Disposition\": {%s},\n", goQuote(disposition))
+		if contentType := part.explicitContentType(); contentType != "" {
+			fmt.Fprintf(&b, "\t\t\"Content-Type\":        {%q},\n", contentType)
+		}
+		b.WriteString("\t}); err != nil {\n\t\tpanic(err)\n")
+		if part.filename != "" {
+			fmt.Fprintf(&b, "\t} else if data, err := os.ReadFile(%q); err != nil {\n\t\tpanic(err)\n", part.filename)
+			b.WriteString("\t} else {\n\t\tpart.Write(data)\n\t}\n")
+		} else {
+			fmt.Fprintf(&b, "\t} else {\n\t\tpart.Write([]byte(%s))\n\t}\n", goQuote(part.content))
+		}
+	}
+	b.WriteString("\tif err := form.Close(); err != nil {\n\t\tpanic(err)\n\t}\n")
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%q, %q, body)\n", r.method, r.url)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\treq.Header.Set(\"Content-Type\", form.FormDataContentType())\n")
+	writeGoResponseHandling(&b)
+	return b.String()
+}
+
+// writeGoResponseHandling writes the end of a Go program that sends a
+// request and prints the response.
+func writeGoResponseHandling(b *strings.Builder) {
+	b.WriteString("\tres, err := http.DefaultClient.Do(req)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tdefer res.Body.Close()\n")
+	b.WriteString("\tdata, err := io.ReadAll(res.Body)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tfmt.Println(res.Status, string(data))\n")
+	b.WriteString("}\n")
+}
+
+// goQuote returns a raw string literal if possible and an interpreted one otherwise.
+func goQuote(s string) string {
+	if strings.Contains(s, "`") || strings.Contains(s, "\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
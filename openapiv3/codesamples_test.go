@@ -0,0 +1,235 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const codeSamplesDocument = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+servers:
+- url: https://{host}/v1
+  variables:
+    host:
+      default: pets.example.com
+paths:
+  /pets/{petId}:
+    put:
+      requestBody:
+        $ref: '#/components/requestBodies/Pet'
+      responses:
+        '204':
+          description: Updated.
+    delete:
+      x-codeSamples:
+      - lang: Shell
+        source: rm pet
+      responses:
+        '204':
+          description: Deleted.
+components:
+  requestBodies:
+    Pet:
+      content:
+        application/json:
+          schema:
+            type: object
+            properties:
+              id:
+                type: string
+                readOnly: true
+              name:
+                type: string
+                example: Rex's
+              password:
+                type: string
+                writeOnly: true
+                example: secret
+`
+
+func TestAddCodeSamples(t *testing.T) {
+	d, err := ParseDocument([]byte(codeSamplesDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, err := AddCodeSamples(d, []string{"curl", "cobol"}); err == nil {
+		t.Errorf("AddCodeSamples accepted an unsupported language")
+	}
+	count, err := AddCodeSamples(d, []string{"curl", "go"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if count != 1 {
+		t.Errorf("AddCodeSamples changed %d operations, want 1", count)
+	}
+	item := d.Paths.Path[0].Value
+	if n := len(item.Delete.SpecificationExtension); n != 1 {
+		t.Errorf("Existing code samples were changed: %d extensions", n)
+	}
+	extensions := item.Put.SpecificationExtension
+	if len(extensions) != 1 || extensions[0].Name != CodeSamplesExtensionName {
+		t.Fatalf("Unexpected extensions: %+v", extensions)
+	}
+	var samples []map[string]string
+	if err := yaml.Unmarshal([]byte(extensions[0].Value.Yaml), &samples); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(samples) != 2 || samples[0]["lang"] != "Shell" || samples[1]["lang"] != "Go" {
+		t.Fatalf("Unexpected samples: %+v", samples)
+	}
+	curl := `curl -X PUT 'https://pets.example.com/v1/pets/<petId>' \
+  -H 'Content-Type: application/json' \
+  -d '{"name":"Rex'\''s","password":"secret"}'`
+	if samples[0]["source"] != curl {
+		t.Errorf("Unexpected curl sample:\n%s", samples[0]["source"])
+	}
+	for _, line := range []string{
+		"body := strings.NewReader(`{\"name\":\"Rex's\",\"password\":\"secret\"}`)",
+		`req, err := http.NewRequest("PUT", "https://pets.example.com/v1/pets/<petId>", body)`,
+		`req.Header.Set("Content-Type", "application/json")`,
+	} {
+		if !strings.Contains(samples[1]["source"], line) {
+			t.Errorf("Go sample doesn't contain %s:\n%s", line, samples[1]["source"])
+		}
+	}
+}
+
+const multipartCodeSamplesDocument = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{petId}/photo:
+    put:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                photo:
+                  type: string
+                  format: binary
+                caption:
+                  type: string
+                  example: Rex; asleep
+            encoding:
+              photo:
+                contentType: image/png, image/jpeg
+      responses:
+        '204':
+          description: Uploaded.
+  /pets:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                pet:
+                  $ref: '#/components/schemas/Pet'
+                documents:
+                  type: array
+                  items:
+                    type: string
+                    format: binary
+                tags:
+                  type: array
+                  items:
+                    type: string
+                    example: dog
+            encoding:
+              documents:
+                contentType: image/*
+      responses:
+        '201':
+          description: Created.
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+          example: Rex
+`
+
+func TestAddMultipartCodeSamples(t *testing.T) {
+	d, err := ParseDocument([]byte(multipartCodeSamplesDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, err := AddCodeSamples(d, []string{"curl", "go"}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for i, test := range []struct {
+		curl string
+		go_  []string
+	}{
+		{
+			curl: `curl -X POST 'http://localhost/pets' \
+  -F 'pet={"name":"Rex"};type=application/json' \
+  -F 'documents=@documents' \
+  -F 'tags=dog'`,
+			go_: []string{
+				"\"Content-Disposition\": {`form-data; name=\"pet\"`},\n\t\t\"Content-Type\":        {\"application/json\"},",
+				"part.Write([]byte(`{\"name\":\"Rex\"}`))",
+				"\"Content-Disposition\": {`form-data; name=\"documents\"; filename=\"documents\"`},\n\t}); err != nil {",
+				`os.ReadFile("documents")`,
+				`req.Header.Set("Content-Type", form.FormDataContentType())`,
+			},
+		},
+		{
+			curl: `curl -X PUT 'http://localhost/pets/<petId>/photo' \
+  -F 'photo=@photo;type=image/png' \
+  -F 'caption="Rex; asleep"'`,
+			go_: []string{
+				"\"Content-Disposition\": {`form-data; name=\"photo\"; filename=\"photo\"`},\n\t\t\"Content-Type\":        {\"image/png\"},",
+				"part.Write([]byte(`Rex; asleep`))",
+			},
+		},
+	} {
+		extensions := d.Paths.Path[1-i].Value
+		operation := extensions.Post
+		if operation == nil {
+			operation = extensions.Put
+		}
+		value, ok := GetExtension(operation, CodeSamplesExtensionName)
+		if !ok {
+			t.Fatalf("Missing code samples")
+		}
+		var samples []map[string]string
+		if err := value.Decode(&samples); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if samples[0]["source"] != test.curl {
+			t.Errorf("Unexpected curl sample:\n%s", samples[0]["source"])
+		}
+		for _, line := range test.go_ {
+			if !strings.Contains(samples[1]["source"], line) {
+				t.Errorf("Go sample doesn't contain %s:\n%s", line, samples[1]["source"])
+			}
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A SchemaProblem describes schema keywords that contradict each other,
+// such as a default that isn't one of the enum values.
+type SchemaProblem struct {
+	// Keys locate the schema or parameter in the document,
+	// e.g. ["components", "schemas", "Pet", "properties", "age"].
+	Keys    []string
+	Message string
+}
+
+func (p *SchemaProblem) String() string {
+	return strings.Join(p.Keys, ".") + ": " + p.Message
+}
+
+// CheckSchemas checks the schemas of a document for keywords that
+// contradict each other: defaults that aren't enum values or don't have
+// the type of their schema, parameter examples that don't have the type
+// of their schema, and minimums that are greater than maximums. Unlike
+// ValidateExamples, it doesn't validate values against whole schemas.
+// Maximums of zero are ignored because they can't be told apart from
+// missing maximums.
+func CheckSchemas(d *Document) []*SchemaProblem {
+	c := &schemaChecker{document: d, problems: make([]*SchemaProblem, 0)}
+	if components := d.GetComponents(); components != nil {
+		for _, pair := range components.GetSchemas().GetAdditionalProperties() {
+			c.checkSchemaOrReference(pair.Value, []string{"components", "schemas", pair.Name})
+		}
+		for _, pair := range components.GetParameters().GetAdditionalProperties() {
+			c.checkParameter(pair.Value.GetParameter(), []string{"components", "parameters", pair.Name})
+		}
+		for _, pair := range components.GetRequestBodies().GetAdditionalProperties() {
+			c.checkMediaTypes(pair.Value.GetRequestBody().GetContent(), []string{"components", "requestBodies", pair.Name, "content"})
+		}
+		for _, pair := range components.GetResponses().GetAdditionalProperties() {
+			c.checkResponse(pair.Value.GetResponse(), []string{"components", "responses", pair.Name})
+		}
+		for _, pair := range components.GetHeaders().GetAdditionalProperties() {
+			c.checkSchemaOrReference(pair.Value.GetHeader().GetSchema(), []string{"components", "headers", pair.Name, "schema"})
+		}
+	}
+	for _, pair := range d.GetPaths().GetPath() {
+		for i, parameter := range pair.Value.GetParameters() {
+			c.checkParameter(parameter.GetParameter(), []string{"paths", pair.Name, "parameters", strconv.Itoa(i)})
+		}
+	}
+	ForEachOperation(d, func(path, method string, _ *PathItem, operation *Operation) {
+		keys := []string{"paths", path, method}
+		for i, parameter := range operation.Parameters {
+			c.checkParameter(parameter.GetParameter(), appendKeys(keys, "parameters", strconv.Itoa(i)))
+		}
+		c.checkMediaTypes(operation.GetRequestBody().GetRequestBody().GetContent(), appendKeys(keys, "requestBody", "content"))
+		for _, pair := range operation.GetResponses().GetResponseOrReference() {
+			c.checkResponse(pair.Value.GetResponse(), appendKeys(keys, "responses", pair.Name))
+		}
+		c.checkResponse(operation.GetResponses().GetDefault().GetResponse(), appendKeys(keys, "responses", "default"))
+	})
+	return c.problems
+}
+
+type schemaChecker struct {
+	document *Document
+	problems []*SchemaProblem
+}
+
+func (c *schemaChecker) fail(keys []string, format string, args ...interface{}) {
+	c.problems = append(c.problems, &SchemaProblem{Keys: keys, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *schemaChecker) checkParameter(parameter *Parameter, keys []string) {
+	if parameter == nil {
+		return
+	}
+	c.checkSchemaOrReference(parameter.Schema, appendKeys(keys, "schema"))
+	c.checkMediaTypes(parameter.Content, appendKeys(keys, "content"))
+	schema := dereferenceSchema(c.document, parameter.Schema)
+	if parameter.Example == nil || schema == nil || schema.Type == "" {
+		return
+	}
+	if node := anyNode(parameter.Example); node != nil && !nodeHasType(node, schema.Type, schema.Nullable) {
+		c.fail(appendKeys(keys, "example"), "example %s is not of type %s", anyJSON(parameter.Example), schema.Type)
+	}
+}
+
+func (c *schemaChecker) checkMediaTypes(content *MediaTypes, keys []string) {
+	for _, pair := range content.GetAdditionalProperties() {
+		c.checkSchemaOrReference(pair.Value.GetSchema(), appendKeys(keys, pair.Name, "schema"))
+	}
+}
+
+func (c *schemaChecker) checkResponse(response *Response, keys []string) {
+	if response == nil {
+		return
+	}
+	c.checkMediaTypes(response.Content, appendKeys(keys, "content"))
+	for _, pair := range response.GetHeaders().GetAdditionalProperties() {
+		c.checkSchemaOrReference(pair.Value.GetHeader().GetSchema(), appendKeys(keys, "headers", pair.Name, "schema"))
+	}
+}
+
+// checkSchemaOrReference checks an inline schema and the schemas that it
+// contains. References are checked where their components are defined.
+func (c *schemaChecker) checkSchemaOrReference(s *SchemaOrReference, keys []string) {
+	if schema := s.GetSchema(); schema != nil {
+		c.checkSchema(schema, keys)
+	}
+}
+
+func (c *schemaChecker) checkSchema(schema *Schema, keys []string) {
+	if schema.Default != nil {
+		value := defaultValue(schema.Default)
+		if schema.Type != "" && !valueHasType(value, schema.Type) {
+			c.fail(appendKeys(keys, "default"), "default %s is not of type %s", jsonString(value), schema.Type)
+		}
+		if len(schema.Enum) > 0 && !enumContains(schema.Enum, value) {
+			c.fail(appendKeys(keys, "default"), "default %s is not one of the enum values", jsonString(value))
+		}
+	}
+	if schema.Maximum != 0 && schema.Minimum > schema.Maximum {
+		c.fail(keys, "minimum %v is greater than maximum %v", schema.Minimum, schema.Maximum)
+	}
+	if schema.MaxLength != 0 && schema.MinLength > schema.MaxLength {
+		c.fail(keys, "minLength %d is greater than maxLength %d", schema.MinLength, schema.MaxLength)
+	}
+	if schema.MaxItems != 0 && schema.MinItems > schema.MaxItems {
+		c.fail(keys, "minItems %d is greater than maxItems %d", schema.MinItems, schema.MaxItems)
+	}
+	for _, pair := range schema.GetProperties().GetAdditionalProperties() {
+		c.checkSchemaOrReference(pair.Value, appendKeys(keys, "properties", pair.Name))
+	}
+	for _, item := range schema.GetItems().GetSchemaOrReference() {
+		c.checkSchemaOrReference(item, appendKeys(keys, "items"))
+	}
+	c.checkSchemaOrReference(schema.GetAdditionalProperties().GetSchemaOrReference(), appendKeys(keys, "additionalProperties"))
+	for i, member := range schema.AllOf {
+		c.checkSchemaOrReference(member, appendKeys(keys, "allOf", strconv.Itoa(i)))
+	}
+	for i, member := range schema.AnyOf {
+		c.checkSchemaOrReference(member, appendKeys(keys, "anyOf", strconv.Itoa(i)))
+	}
+	for i, member := range schema.OneOf {
+		c.checkSchemaOrReference(member, appendKeys(keys, "oneOf", strconv.Itoa(i)))
+	}
+	if schema.Not != nil {
+		c.checkSchema(schema.Not, appendKeys(keys, "not"))
+	}
+}
+
+// defaultValue returns the value of a default as a number, a boolean or
+// a string.
+func defaultValue(d *DefaultType) interface{} {
+	switch v := d.Oneof.(type) {
+	case *DefaultType_Number:
+		return v.Number
+	case *DefaultType_Boolean:
+		return v.Boolean
+	case *DefaultType_String_:
+		return v.String_
+	}
+	return nil
+}
+
+// valueHasType returns true if a default value has a schema type.
+func valueHasType(value interface{}, t string) bool {
+	switch v := value.(type) {
+	case float64:
+		return t == "number" || (t == "integer" && v == math.Trunc(v))
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	}
+	return true
+}
+
+// enumContains returns true if one of the values of an enum equals a value.
+func enumContains(enum []*Any, value interface{}) bool {
+	want := jsonString(value)
+	for _, e := range enum {
+		if anyJSON(e) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// anyJSON returns the JSON of a value.
+func anyJSON(a *Any) string {
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(a.GetYaml()), &v); err != nil {
+		return strings.TrimSpace(a.GetYaml())
+	}
+	return jsonString(v)
+}
+
+// jsonString returns the JSON of a value. Integers and floats with the
+// same values have the same JSON.
+func jsonString(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// anyNode returns the YAML node of a value, or nil if it can't be parsed.
+func anyNode(a *Any) *yaml.Node {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(a.GetYaml()), &node); err != nil || len(node.Content) == 0 {
+		return nil
+	}
+	return node.Content[0]
+}
+
+// nodeHasType returns true if a YAML node has a schema type. Nulls have
+// the types of nullable schemas.
+func nodeHasType(node *yaml.Node, t string, nullable bool) bool {
+	switch tag := node.ShortTag(); {
+	case tag == "!!null":
+		return nullable
+	case t == "string":
+		return tag == "!!str" || tag == "!!timestamp" || tag == "!!binary"
+	case t == "integer":
+		return tag == "!!int"
+	case t == "number":
+		return tag == "!!int" || tag == "!!float"
+	case t == "boolean":
+		return tag == "!!bool"
+	case t == "array":
+		return node.Kind == yaml.SequenceNode
+	case t == "object":
+		return node.Kind == yaml.MappingNode
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import "testing"
+
+func TestCheckSchemas(t *testing.T) {
+	d, err := ParseDocument([]byte(`
+openapi: 3.0.0
+info:
+  title: Consistent
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Size:
+      type: integer
+      enum: [1, 2]
+      default: 2.0
+      minimum: 1
+      maximum: 2
+    Color:
+      type: string
+      enum: [red, green]
+      default: green
+  parameters:
+    Since:
+      name: since
+      in: query
+      schema:
+        type: string
+        format: date
+      example: 2023-01-01
+    Tag:
+      name: tag
+      in: query
+      schema:
+        type: string
+        nullable: true
+      example: null
+    Size:
+      name: size
+      in: query
+      schema:
+        $ref: '#/components/schemas/Size'
+      example: large
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	problems := CheckSchemas(d)
+	if len(problems) != 1 || problems[0].String() != `components.parameters.Size.example: example "large" is not of type integer` {
+		t.Errorf("Unexpected problems: %v", problems)
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DuplicateComponent describes inline parameters, responses or headers
+// that are structurally identical and could be replaced by references
+// to a single component.
+type DuplicateComponent struct {
+	// Section is the components section of the component:
+	// "parameters", "responses" or "headers".
+	Section string
+	// Name is the name of the component.
+	Name string
+	// Count is the number of inline copies.
+	Count int
+	// Keys is the location of the first inline copy.
+	Keys []string
+}
+
+// Ref returns the reference to the component.
+func (c *DuplicateComponent) Ref() string {
+	return "#/components/" + c.Section + "/" + escapeJSONPointer(c.Name)
+}
+
+// FindDuplicateComponents returns the groups of structurally identical
+// inline parameters, responses and headers of a document that appear more
+// than threshold times. If ignoreDescriptions is true, copies that differ
+// only in their descriptions are identical. The names of the duplicates
+// are the names that DedupeComponents would give their components.
+func FindDuplicateComponents(d *Document, threshold int, ignoreDescriptions bool) []*DuplicateComponent {
+	return dedupeComponents(proto.Clone(d).(*Document), threshold, ignoreDescriptions)
+}
+
+// DedupeComponents replaces the groups of structurally identical inline
+// parameters, responses and headers of a document that appear more than
+// threshold times with references to components. Components are named
+// after the first copy of each group: parameters and headers use their
+// names, responses use their status codes. Existing components that are
+// identical to a group are reused. If ignoreDescriptions is true, copies
+// that differ only in their descriptions are identical and the component
+// uses the description of the first copy. Because the copies are replaced
+// by references, running DedupeComponents again changes nothing.
+// It returns the components that were used.
+func DedupeComponents(d *Document, threshold int, ignoreDescriptions bool) []*DuplicateComponent {
+	return dedupeComponents(d, threshold, ignoreDescriptions)
+}
+
+// inlineComponent is an inline value that could be a component.
+type inlineComponent struct {
+	keys  []string
+	name  string
+	value proto.Message
+	// replace replaces the inline value with a reference.
+	replace func(ref string)
+}
+
+func dedupeComponents(d *Document, threshold int, ignoreDescriptions bool) []*DuplicateComponent {
+	duplicates := make([]*DuplicateComponent, 0)
+	// Headers come first so that responses are compared with the references
+	// that replace their headers.
+	for _, section := range []string{"headers", "parameters", "responses"} {
+		var inline []*inlineComponent
+		switch section {
+		case "headers":
+			inline = inlineHeaders(d)
+		case "parameters":
+			inline = inlineParameters(d)
+		case "responses":
+			inline = inlineResponses(d)
+		}
+		var groups [][]*inlineComponent
+		index := make(map[string]int)
+		for _, c := range inline {
+			key := structuralKey(c.value, ignoreDescriptions)
+			if i, ok := index[key]; ok {
+				groups[i] = append(groups[i], c)
+			} else {
+				index[key] = len(groups)
+				groups = append(groups, []*inlineComponent{c})
+			}
+		}
+		for _, group := range groups {
+			if len(group) <= threshold {
+				continue
+			}
+			if d.Components == nil {
+				d.Components = &Components{}
+			}
+			name := addComponent(d.Components, section, group[0], ignoreDescriptions)
+			duplicate := &DuplicateComponent{
+				Section: section,
+				Name:    name,
+				Count:   len(group),
+				Keys:    group[0].keys,
+			}
+			for _, c := range group {
+				c.replace(duplicate.Ref())
+			}
+			duplicates = append(duplicates, duplicate)
+		}
+	}
+	return duplicates
+}
+
+// inlineParameters returns the inline parameters of path items and operations.
+func inlineParameters(d *Document) []*inlineComponent {
+	inline := make([]*inlineComponent, 0)
+	add := func(keys []string, parameters []*ParameterOrReference) {
+		for i, p := range parameters {
+			parameter := p.GetParameter()
+			if parameter == nil {
+				continue
+			}
+			p := p
+			inline = append(inline, &inlineComponent{
+				keys:  append(append([]string{}, keys...), "parameters", strconv.Itoa(i)),
+				name:  parameter.Name,
+				value: parameter,
+				replace: func(ref string) {
+					p.Oneof = &ParameterOrReference_Reference{Reference: &Reference{XRef: ref}}
+				},
+			})
+		}
+	}
+	for _, pair := range d.GetPaths().GetPath() {
+		add([]string{"paths", pair.Name}, pair.GetValue().GetParameters())
+	}
+	ForEachOperation(d, func(path, method string, item *PathItem, operation *Operation) {
+		add([]string{"paths", path, method}, operation.Parameters)
+	})
+	return inline
+}
+
+// inlineResponses returns the inline responses of operations.
+func inlineResponses(d *Document) []*inlineComponent {
+	inline := make([]*inlineComponent, 0)
+	add := func(keys []string, code string, r *ResponseOrReference) {
+		response := r.GetResponse()
+		if response == nil {
+			return
+		}
+		inline = append(inline, &inlineComponent{
+			keys:  append(append([]string{}, keys...), code),
+			name:  responseComponentName(code),
+			value: response,
+			replace: func(ref string) {
+				r.Oneof = &ResponseOrReference_Reference{Reference: &Reference{XRef: ref}}
+			},
+		})
+	}
+	ForEachOperation(d, func(path, method string, item *PathItem, operation *Operation) {
+		keys := []string{"paths", path, method, "responses"}
+		for _, pair := range operation.GetResponses().GetResponseOrReference() {
+			add(keys, pair.Name, pair.Value)
+		}
+		if operation.GetResponses().GetDefault() != nil {
+			add(keys, "default", operation.Responses.Default)
+		}
+	})
+	return inline
+}
+
+// inlineHeaders returns the inline headers of the inline responses of
+// operations and of response components.
+func inlineHeaders(d *Document) []*inlineComponent {
+	inline := make([]*inlineComponent, 0)
+	add := func(keys []string, r *ResponseOrReference) {
+		for _, pair := range r.GetResponse().GetHeaders().GetAdditionalProperties() {
+			header := pair.GetValue().GetHeader()
+			if header == nil {
+				continue
+			}
+			h := pair.Value
+			inline = append(inline, &inlineComponent{
+				keys:  append(append([]string{}, keys...), "headers", pair.Name),
+				name:  pair.Name,
+				value: header,
+				replace: func(ref string) {
+					h.Oneof = &HeaderOrReference_Reference{Reference: &Reference{XRef: ref}}
+				},
+			})
+		}
+	}
+	ForEachOperation(d, func(path, method string, item *PathItem, operation *Operation) {
+		keys := []string{"paths", path, method, "responses"}
+		for _, pair := range operation.GetResponses().GetResponseOrReference() {
+			add(append(keys, pair.Name), pair.Value)
+		}
+		if operation.GetResponses().GetDefault() != nil {
+			add(append(keys, "default"), operation.Responses.Default)
+		}
+	})
+	for _, pair := range d.GetComponents().GetResponses().GetAdditionalProperties() {
+		add([]string{"components", "responses", pair.Name}, pair.Value)
+	}
+	return inline
+}
+
+// responseComponentName returns the name of a response component for a
+// status code, e.g. "NotFound" for "404".
+func responseComponentName(code string) string {
+	if code == "default" {
+		return "Default"
+	}
+	if status, err := strconv.Atoi(code); err == nil && http.StatusText(status) != "" {
+		return strings.Replace(strings.Replace(http.StatusText(status), " ", "", -1), "-", "", -1)
+	}
+	return "Response" + code
+}
+
+var invalidComponentNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9\.\-_]`)
+
+// addComponent adds the value of an inline component to a components
+// section unless an identical component exists. It returns the name of the
+// component that has the value.
+func addComponent(components *Components, section string, c *inlineComponent, ignoreDescriptions bool) string {
+	field := components.ProtoReflect().Descriptor().Fields().ByJSONName(section)
+	target := components.ProtoReflect().Mutable(field).Message()
+	pairs := target.Mutable(target.Descriptor().Fields().ByName("additional_properties")).List()
+	base := invalidComponentNameCharacters.ReplaceAllString(c.name, "_")
+	if base == "" {
+		base = strings.TrimSuffix(section, "s")
+	}
+	key := structuralKey(c.value, ignoreDescriptions)
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s%d", base, i)
+		}
+		existing := findPair(pairs, name)
+		if existing == nil {
+			pair := pairs.NewElement().Message()
+			pair.Set(pair.Descriptor().Fields().ByName("name"), protoreflect.ValueOfString(name))
+			value := pair.Mutable(pair.Descriptor().Fields().ByName("value")).Message()
+			oneof := value.Descriptor().Oneofs().Get(0)
+			for j := 0; j < oneof.Fields().Len(); j++ {
+				if f := oneof.Fields().Get(j); f.Message() == c.value.ProtoReflect().Descriptor() {
+					value.Set(f, protoreflect.ValueOfMessage(proto.Clone(c.value).ProtoReflect()))
+				}
+			}
+			pairs.Append(protoreflect.ValueOfMessage(pair))
+			return name
+		}
+		value := pairValue(existing)
+		if which := value.WhichOneof(value.Descriptor().Oneofs().Get(0)); which != nil {
+			if v := value.Get(which).Message().Interface(); structuralKey(v, ignoreDescriptions) == key {
+				return name
+			}
+		}
+	}
+}
+
+// structuralKey returns a string that is equal for structurally identical
+// messages. If ignoreDescriptions is true, descriptions are ignored.
+func structuralKey(m proto.Message, ignoreDescriptions bool) string {
+	if ignoreDescriptions {
+		m = proto.Clone(m)
+		clearDescriptions(m.ProtoReflect())
+	}
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	return string(b)
+}
+
+// clearDescriptions clears the description fields of a message and of the
+// messages that it contains.
+func clearDescriptions(m protoreflect.Message) {
+	m.Range(func(field protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case field.Name() == "description" && field.Kind() == protoreflect.StringKind:
+			m.Clear(field)
+		case field.Kind() != protoreflect.MessageKind:
+		case field.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				clearDescriptions(list.Get(i).Message())
+			}
+		case !field.IsMap():
+			clearDescriptions(v.Message())
+		}
+		return true
+	})
+}
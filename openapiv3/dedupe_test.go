@@ -0,0 +1,147 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"strings"
+	"testing"
+)
+
+const dedupeDocument = `
+openapi: 3.0.0
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  /books:
+    get:
+      parameters:
+      - name: pageSize
+        in: query
+        description: The maximum number of books to return.
+        schema:
+          type: integer
+      responses:
+        '200':
+          description: Books.
+          headers:
+            X-Rate-Limit:
+              schema:
+                type: integer
+        '404':
+          description: Not found.
+  /shelves:
+    get:
+      parameters:
+      - name: pageSize
+        in: query
+        description: The maximum number of shelves to return.
+        schema:
+          type: integer
+      responses:
+        '200':
+          description: Shelves.
+          headers:
+            X-Rate-Limit:
+              schema:
+                type: integer
+        '404':
+          description: Not found.
+  /authors:
+    get:
+      parameters:
+      - name: pageSize
+        in: query
+        description: The maximum number of books to return.
+        schema:
+          type: integer
+      responses:
+        '404':
+          description: Not found.
+components:
+  responses:
+    NotFound:
+      description: Not found.
+`
+
+func TestFindDuplicateComponents(t *testing.T) {
+	d, err := ParseDocument([]byte(dedupeDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	duplicates := FindDuplicateComponents(d, 1, false)
+	refs := make([]string, 0)
+	for _, duplicate := range duplicates {
+		refs = append(refs, duplicate.Ref())
+	}
+	if got, want := strings.Join(refs, " "), "#/components/headers/X-Rate-Limit #/components/parameters/pageSize #/components/responses/NotFound"; got != want {
+		t.Errorf("Unexpected duplicates: %s, want %s", got, want)
+	}
+	if d.Components.Headers != nil || d.Paths.Path[0].Value.Get.Parameters[0].GetParameter() == nil {
+		t.Errorf("FindDuplicateComponents changed the document")
+	}
+	if duplicates[2].Count != 3 || strings.Join(duplicates[2].Keys, ".") != "paths./books.get.responses.404" {
+		t.Errorf("Unexpected duplicate: %+v", duplicates[2])
+	}
+	if n := len(FindDuplicateComponents(d, 3, false)); n != 0 {
+		t.Errorf("Found %d duplicates above the threshold, want 0", n)
+	}
+}
+
+func TestDedupeComponents(t *testing.T) {
+	d, err := ParseDocument([]byte(dedupeDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	duplicates := DedupeComponents(d, 1, true)
+	if len(duplicates) != 4 {
+		t.Fatalf("Unexpected duplicates: %+v", duplicates)
+	}
+	// Parameters that differ only in their descriptions are identical.
+	if duplicates[1].Count != 3 {
+		t.Errorf("Unexpected parameter duplicates: %+v", duplicates[1])
+	}
+	parameters := d.Components.Parameters.AdditionalProperties
+	if len(parameters) != 1 || parameters[0].Name != "pageSize" ||
+		parameters[0].Value.GetParameter().Description != "The maximum number of books to return." {
+		t.Errorf("Unexpected parameter components: %+v", parameters)
+	}
+	for _, pair := range d.Paths.Path {
+		if ref := pair.Value.Get.Parameters[0].GetReference().GetXRef(); ref != "#/components/parameters/pageSize" {
+			t.Errorf("Unexpected parameter in %s: %+v", pair.Name, pair.Value.Get.Parameters[0])
+		}
+	}
+	// The existing NotFound response is reused and an OK response is added.
+	if n := len(d.Components.Responses.AdditionalProperties); n != 2 {
+		t.Errorf("Unexpected response components: %+v", d.Components.Responses.AdditionalProperties)
+	}
+	headers := d.Components.Headers.AdditionalProperties
+	if len(headers) != 1 || headers[0].Name != "X-Rate-Limit" {
+		t.Errorf("Unexpected header components: %+v", headers)
+	}
+	// The 200 responses differ only in their descriptions and now refer to
+	// the same header, so they are replaced too.
+	if ref := d.Paths.Path[1].Value.Get.Responses.ResponseOrReference[0].Value.GetReference().GetXRef(); ref != "#/components/responses/OK" {
+		t.Errorf("Unexpected response reference: %s", ref)
+	}
+	// Deduplication is idempotent.
+	before, _ := d.YAMLValue("")
+	if duplicates := DedupeComponents(d, 1, true); len(duplicates) != 0 {
+		t.Errorf("Unexpected duplicates after deduplication: %+v", duplicates)
+	}
+	if after, _ := d.YAMLValue(""); string(after) != string(before) {
+		t.Errorf("Deduplication changed a deduplicated document:\n%s", after)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Dereference returns the component that a local reference such as
+// "#/components/schemas/Pet" refers to: a *Schema, *Parameter, *Response,
+// *RequestBody, *Header, *Example, *Link, *SecurityScheme or *Callback.
+// References between components are followed. References to other
+// files, to missing components, and circular references are errors.
+func Dereference(d *Document, ref string) (proto.Message, error) {
+	seen := make(map[string]bool)
+	for {
+		if seen[ref] {
+			return nil, fmt.Errorf("circular reference %s", ref)
+		}
+		seen[ref] = true
+		parts := strings.SplitN(strings.TrimPrefix(ref, "#/components/"), "/", 2)
+		if !strings.HasPrefix(ref, "#/components/") || len(parts) != 2 {
+			return nil, fmt.Errorf("%s is not a reference to a component", ref)
+		}
+		field := (&Components{}).ProtoReflect().Descriptor().Fields().ByJSONName(parts[0])
+		if field == nil || field.IsList() || field.Message() == nil || d.GetComponents() == nil {
+			return nil, fmt.Errorf("could not resolve %s", ref)
+		}
+		section := d.GetComponents().ProtoReflect().Get(field).Message()
+		pair := findPair(componentPairs(section), strings.NewReplacer("~1", "/", "~0", "~").Replace(parts[1]))
+		if pair == nil {
+			return nil, fmt.Errorf("could not resolve %s", ref)
+		}
+		value := pairValue(pair)
+		which := value.WhichOneof(value.Descriptor().Oneofs().Get(0))
+		if which == nil {
+			return nil, fmt.Errorf("could not resolve %s", ref)
+		}
+		m := value.Get(which).Message().Interface()
+		if r, ok := m.(*Reference); ok {
+			ref = r.XRef
+			continue
+		}
+		return m, nil
+	}
+}
+
+// dereferenceSchema returns a schema or the schema component that it
+// refers to, or nil if the reference can't be resolved.
+func dereferenceSchema(d *Document, s *SchemaOrReference) *Schema {
+	if ref := s.GetReference().GetXRef(); ref != "" {
+		schema, _ := Dereference(d, ref)
+		s, _ := schema.(*Schema)
+		return s
+	}
+	return s.GetSchema()
+}
+
+// dereferenceParameter returns a parameter or the parameter component that
+// it refers to, or nil if the reference can't be resolved.
+func dereferenceParameter(d *Document, p *ParameterOrReference) *Parameter {
+	if ref := p.GetReference().GetXRef(); ref != "" {
+		parameter, _ := Dereference(d, ref)
+		p, _ := parameter.(*Parameter)
+		return p
+	}
+	return p.GetParameter()
+}
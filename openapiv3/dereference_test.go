@@ -0,0 +1,81 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"strings"
+	"testing"
+)
+
+const dereferenceDocument = `
+openapi: 3.0.0
+info:
+  title: Dereference
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+    Alias:
+      $ref: '#/components/schemas/Pet'
+    a~b/c:
+      type: string
+    Loop:
+      $ref: '#/components/schemas/Loop'
+  parameters:
+    Limit:
+      name: limit
+      in: query
+`
+
+func TestDereference(t *testing.T) {
+	d, err := ParseDocument([]byte(dereferenceDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for ref, want := range map[string]string{
+		"#/components/schemas/Pet":      "object",
+		"#/components/schemas/Alias":    "object",
+		"#/components/schemas/a~0b~1c":  "string",
+		"#/components/parameters/Limit": "limit",
+	} {
+		m, err := Dereference(d, ref)
+		if err != nil {
+			t.Errorf("%s: %+v", ref, err)
+			continue
+		}
+		got := ""
+		switch v := m.(type) {
+		case *Schema:
+			got = v.Type
+		case *Parameter:
+			got = v.Name
+		}
+		if got != want {
+			t.Errorf("%s: got %q, want %q", ref, got, want)
+		}
+	}
+	for ref, message := range map[string]string{
+		"#/components/schemas/Loop":    "circular reference",
+		"#/components/schemas/Missing": "could not resolve",
+		"#/components/unknown/Pet":     "could not resolve",
+		"other.yaml#/components/Pet":   "not a reference to a component",
+	} {
+		if _, err := Dereference(d, ref); err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("%s: unexpected error %v", ref, err)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// ExternalValueExtensionName is the name of the extension that stores
+// the content of an example's externalValue after it has been fetched.
+const ExternalValueExtensionName = "x-gnostic-external-value"
+
+// An ExampleWarning describes an example that could not be resolved.
+type ExampleWarning struct {
+	// Keys locate the example in the document,
+	// e.g. ["paths", "/pets", "get", "responses", "200", ...].
+	Keys    []string
+	Message string
+}
+
+func (w *ExampleWarning) String() string {
+	return strings.Join(w.Keys, ".") + ": " + w.Message
+}
+
+// ResolveExamples replaces example references with the examples that they
+// refer to. References to components.examples are resolved in the document
+// and other references are read relative to base, the location of the
+// document. If fetchExternal is true, the targets of externalValue URLs
+// are also read and their contents are stored in an
+// x-gnostic-external-value extension of the example, where
+// ExampleValue finds them. Examples that can't be resolved are left
+// unchanged and are described in the returned warnings.
+func ResolveExamples(d *Document, base string, fetchExternal bool) []*ExampleWarning {
+	r := &exampleResolver{document: d, base: base, fetchExternal: fetchExternal}
+	if d == nil {
+		return r.warnings
+	}
+	if c := d.Components; c != nil {
+		if c.Examples != nil {
+			r.resolveExamples(c.Examples, []string{"components", "examples"}, true)
+		}
+		if c.Parameters != nil {
+			for _, pair := range c.Parameters.AdditionalProperties {
+				r.resolveParameter(pair.Value.GetParameter(), []string{"components", "parameters", pair.Name})
+			}
+		}
+		if c.Headers != nil {
+			r.resolveHeaders(c.Headers, []string{"components", "headers"})
+		}
+		if c.RequestBodies != nil {
+			for _, pair := range c.RequestBodies.AdditionalProperties {
+				if requestBody := pair.Value.GetRequestBody(); requestBody != nil {
+					r.resolveMediaTypes(requestBody.Content, []string{"components", "requestBodies", pair.Name, "content"})
+				}
+			}
+		}
+		if c.Responses != nil {
+			for _, pair := range c.Responses.AdditionalProperties {
+				r.resolveResponse(pair.Value.GetResponse(), []string{"components", "responses", pair.Name})
+			}
+		}
+	}
+	if d.Paths != nil {
+		for _, pair := range d.Paths.Path {
+			r.resolvePathItem(pair.Value, []string{"paths", pair.Name})
+		}
+	}
+	return r.warnings
+}
+
+// ExampleValue returns the value of an example. For examples with an
+// externalValue, this is the content stored by ResolveExamples, if any.
+func ExampleValue(example *Example) *Any {
+	if example == nil {
+		return nil
+	}
+	if example.Value != nil {
+		return example.Value
+	}
+	for _, extension := range example.SpecificationExtension {
+		if extension.Name == ExternalValueExtensionName {
+			return extension.Value
+		}
+	}
+	return nil
+}
+
+type exampleResolver struct {
+	document      *Document
+	base          string
+	fetchExternal bool
+	warnings      []*ExampleWarning
+}
+
+func (r *exampleResolver) warn(keys []string, format string, args ...interface{}) {
+	r.warnings = append(r.warnings, &ExampleWarning{Keys: keys, Message: fmt.Sprintf(format, args...)})
+}
+
+// appendKeys returns a new slice so that callers can't share backing arrays.
+func appendKeys(keys []string, more ...string) []string {
+	result := make([]string, 0, len(keys)+len(more))
+	return append(append(result, keys...), more...)
+}
+
+func (r *exampleResolver) resolvePathItem(pathItem *PathItem, keys []string) {
+	if pathItem == nil {
+		return
+	}
+	for i, parameter := range pathItem.Parameters {
+		r.resolveParameter(parameter.GetParameter(), appendKeys(keys, "parameters", fmt.Sprintf("%d", i)))
+	}
+	for _, o := range []struct {
+		method    string
+		operation *Operation
+	}{
+		{"get", pathItem.Get}, {"put", pathItem.Put}, {"post", pathItem.Post}, {"delete", pathItem.Delete},
+		{"options", pathItem.Options}, {"head", pathItem.Head}, {"patch", pathItem.Patch}, {"trace", pathItem.Trace},
+	} {
+		if o.operation != nil {
+			r.resolveOperation(o.operation, appendKeys(keys, o.method))
+		}
+	}
+}
+
+func (r *exampleResolver) resolveOperation(operation *Operation, keys []string) {
+	for i, parameter := range operation.Parameters {
+		r.resolveParameter(parameter.GetParameter(), appendKeys(keys, "parameters", fmt.Sprintf("%d", i)))
+	}
+	if requestBody := operation.RequestBody.GetRequestBody(); requestBody != nil {
+		r.resolveMediaTypes(requestBody.Content, appendKeys(keys, "requestBody", "content"))
+	}
+	if operation.Responses != nil {
+		r.resolveResponse(operation.Responses.Default.GetResponse(), appendKeys(keys, "responses", "default"))
+		for _, pair := range operation.Responses.ResponseOrReference {
+			r.resolveResponse(pair.Value.GetResponse(), appendKeys(keys, "responses", pair.Name))
+		}
+	}
+}
+
+func (r *exampleResolver) resolveParameter(parameter *Parameter, keys []string) {
+	if parameter == nil {
+		return
+	}
+	if parameter.Examples != nil {
+		r.resolveExamples(parameter.Examples, appendKeys(keys, "examples"), false)
+	}
+	r.resolveMediaTypes(parameter.Content, appendKeys(keys, "content"))
+}
+
+func (r *exampleResolver) resolveHeaders(headers *HeadersOrReferences, keys []string) {
+	for _, pair := range headers.AdditionalProperties {
+		if header := pair.Value.GetHeader(); header != nil {
+			if header.Examples != nil {
+				r.resolveExamples(header.Examples, appendKeys(keys, pair.Name, "examples"), false)
+			}
+			r.resolveMediaTypes(header.Content, appendKeys(keys, pair.Name, "content"))
+		}
+	}
+}
+
+func (r *exampleResolver) resolveResponse(response *Response, keys []string) {
+	if response == nil {
+		return
+	}
+	if response.Headers != nil {
+		r.resolveHeaders(response.Headers, appendKeys(keys, "headers"))
+	}
+	r.resolveMediaTypes(response.Content, appendKeys(keys, "content"))
+}
+
+func (r *exampleResolver) resolveMediaTypes(content *MediaTypes, keys []string) {
+	if content == nil {
+		return
+	}
+	for _, pair := range content.AdditionalProperties {
+		if pair.Value != nil && pair.Value.Examples != nil {
+			r.resolveExamples(pair.Value.Examples, appendKeys(keys, pair.Name, "examples"), false)
+		}
+	}
+}
+
+// resolveExamples resolves a map of examples. The external values of
+// examples in components are fetched where they are defined; the external
+// values of other examples are fetched unless they came from components.
+func (r *exampleResolver) resolveExamples(examples *ExamplesOrReferences, keys []string, isComponent bool) {
+	for _, pair := range examples.AdditionalProperties {
+		exampleKeys := appendKeys(keys, pair.Name)
+		if pair.Value == nil {
+			continue
+		}
+		fromComponents := false
+		if ref := pair.Value.GetReference(); ref != nil {
+			example, err := r.exampleForRef(ref.XRef)
+			if err != nil {
+				r.warn(exampleKeys, "unable to resolve %s: %s", ref.XRef, err.Error())
+				continue
+			}
+			pair.Value.Oneof = &ExampleOrReference_Example{Example: example}
+			fromComponents = strings.HasPrefix(ref.XRef, "#/components/examples/")
+		}
+		if r.fetchExternal && (isComponent || !fromComponents) {
+			r.fetchExternalValue(pair.Value.GetExample(), exampleKeys)
+		}
+	}
+}
+
+// exampleForRef returns a copy of the example that a reference refers to.
+func (r *exampleResolver) exampleForRef(ref string) (*Example, error) {
+	const prefix = "#/components/examples/"
+	seen := make(map[string]bool)
+	for strings.HasPrefix(ref, prefix) {
+		if seen[ref] {
+			return nil, fmt.Errorf("circular reference")
+		}
+		seen[ref] = true
+		var value *ExampleOrReference
+		if c := r.document.Components; c != nil && c.Examples != nil {
+			name := strings.NewReplacer("~1", "/", "~0", "~").Replace(strings.TrimPrefix(ref, prefix))
+			for _, pair := range c.Examples.AdditionalProperties {
+				if pair.Name == name {
+					value = pair.Value
+				}
+			}
+		}
+		if value == nil {
+			return nil, fmt.Errorf("example not found")
+		}
+		if example := value.GetExample(); example != nil {
+			return proto.Clone(example).(*Example), nil
+		}
+		ref = value.GetReference().GetXRef()
+	}
+	if strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("not an example")
+	}
+	info, err := compiler.ReadInfoForRef(r.base, ref)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("example not found")
+	}
+	if info.Kind == yaml.DocumentNode && len(info.Content) > 0 {
+		info = info.Content[0]
+	}
+	return NewExample(info, compiler.NewContextFromNode("$root", info, nil))
+}
+
+// fetchExternalValue reads the target of an example's externalValue
+// and stores its content in an extension of the example.
+func (r *exampleResolver) fetchExternalValue(example *Example, keys []string) {
+	if example == nil || example.ExternalValue == "" || ExampleValue(example) != nil {
+		return
+	}
+	location := example.ExternalValue
+	if u, err := url.Parse(location); err != nil || u.Scheme == "" {
+		if !filepath.IsAbs(location) {
+			location = filepath.Join(filepath.Dir(r.base), location)
+		}
+	}
+	bytes, err := compiler.ReadBytesForFile(location)
+	if err != nil {
+		r.warn(keys, "unable to fetch %s: %s", example.ExternalValue, err.Error())
+		return
+	}
+	content := string(bytes)
+	var node yaml.Node
+	if yaml.Unmarshal(bytes, &node) != nil {
+		// Content that isn't YAML or JSON is stored as a string.
+		content = string(compiler.Marshal(compiler.NewScalarNodeForString(content)))
+	}
+	example.SpecificationExtension = append(example.SpecificationExtension, &NamedAny{
+		Name:  ExternalValueExtensionName,
+		Value: &Any{Yaml: content},
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestResolveExamples(t *testing.T) {
+	filename := "../testdata/examples/openapi.yaml"
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("unable to read file %s", filename)
+	}
+	d, err := ParseDocument(b)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	warnings := ResolveExamples(d, filename, true)
+
+	examples := d.Paths.Path[0].Value.Get.Responses.ResponseOrReference[0].Value.GetResponse().
+		Content.AdditionalProperties[0].Value.Examples.AdditionalProperties
+	cats := examples[0].Value.GetExample()
+	if cats == nil || cats.Summary != "Cats" {
+		t.Fatalf("unresolved example: %+v", examples[0].Value)
+	}
+	if value := ExampleValue(cats); value == nil || !strings.Contains(value.Yaml, "Tom") {
+		t.Errorf("external value was not fetched: %+v", value)
+	}
+	dogs := examples[1].Value.GetExample()
+	if dogs == nil || dogs.Summary != "Dogs" || !strings.Contains(ExampleValue(dogs).GetYaml(), "Rex") {
+		t.Errorf("unresolved example: %+v", examples[1].Value)
+	}
+
+	expected := []string{
+		"paths./pets.get.responses.200.content.application/json.examples.birds: unable to resolve #/components/examples/birds: example not found",
+		"paths./pets.get.responses.200.content.application/json.examples.fish: unable to fetch missing.json: ",
+	}
+	if len(warnings) != len(expected) {
+		t.Fatalf("unexpected warnings: %+v", warnings)
+	}
+	for i, w := range warnings {
+		if !strings.HasPrefix(w.String(), expected[i]) {
+			t.Errorf("unexpected warning: %s (expected %s...)", w, expected[i])
+		}
+	}
+}
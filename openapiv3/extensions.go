@@ -0,0 +1,83 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// Extensible is implemented by the messages that carry SpecificationExtension
+// values, such as *Document, *Operation and *Schema.
+type Extensible interface {
+	proto.Message
+	GetSpecificationExtension() []*NamedAny
+}
+
+// GetExtension returns the value of the extension with the specified name,
+// and whether the message has the extension.
+func GetExtension(obj Extensible, name string) (*yaml.Node, bool) {
+	for _, extension := range obj.GetSpecificationExtension() {
+		if extension.Name != name {
+			continue
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(extension.Value.GetYaml()), &node); err != nil || len(node.Content) == 0 {
+			return nil, false
+		}
+		return node.Content[0], true
+	}
+	return nil, false
+}
+
+// GetExtensionInto unmarshals the value of the extension with the specified
+// name into out, which is a pointer as for yaml.Unmarshal. It returns an
+// error if the message doesn't have the extension.
+func GetExtensionInto(obj Extensible, name string, out interface{}) error {
+	node, ok := GetExtension(obj, name)
+	if !ok {
+		return fmt.Errorf("no %s extension", name)
+	}
+	return node.Decode(out)
+}
+
+// SetExtension sets the extension with the specified name to a value,
+// which is a *yaml.Node or is marshalled as for yaml.Marshal. An existing
+// extension keeps its position; a new one is added after the others.
+func SetExtension(obj Extensible, name string, value interface{}) error {
+	node, ok := value.(*yaml.Node)
+	if !ok {
+		node = &yaml.Node{}
+		if err := node.Encode(value); err != nil {
+			return err
+		}
+	}
+	v := &Any{Yaml: string(compiler.Marshal(node))}
+	for _, extension := range obj.GetSpecificationExtension() {
+		if extension.Name == name {
+			extension.Value = v
+			return nil
+		}
+	}
+	m := obj.ProtoReflect()
+	extensions := m.Mutable(m.Descriptor().Fields().ByName("specification_extension")).List()
+	extensions.Append(protoreflect.ValueOfMessage((&NamedAny{Name: name, Value: v}).ProtoReflect()))
+	return nil
+}
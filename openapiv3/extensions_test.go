@@ -0,0 +1,81 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"testing"
+)
+
+func TestExtensions(t *testing.T) {
+	d, err := ParseDocument([]byte(`openapi: 3.0.0
+info:
+  title: Extensions
+  version: 1.0.0
+  x-owner:
+    team: pets
+    size: 3
+  x-internal: true
+paths: {}
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	node, ok := GetExtension(d.Info, "x-internal")
+	if !ok || node.Value != "true" {
+		t.Errorf("Unexpected value of x-internal: %+v", node)
+	}
+	if _, ok := GetExtension(d.Info, "x-missing"); ok {
+		t.Errorf("Found a missing extension")
+	}
+	var owner struct {
+		Team string `yaml:"team"`
+		Size int    `yaml:"size"`
+	}
+	if err := GetExtensionInto(d.Info, "x-owner", &owner); err != nil || owner.Team != "pets" || owner.Size != 3 {
+		t.Errorf("Unexpected value of x-owner: %+v (%v)", owner, err)
+	}
+	if err := GetExtensionInto(d.Info, "x-missing", &owner); err == nil {
+		t.Errorf("Expected an error for a missing extension")
+	}
+
+	// Existing extensions keep their positions; new ones are added at the end.
+	owner.Team = "cats"
+	if err := SetExtension(d.Info, "x-owner", owner); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if err := SetExtension(d.Info, "x-tags", []string{"a", "b"}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	extensions := d.Info.SpecificationExtension
+	if len(extensions) != 3 || extensions[0].Name != "x-owner" || extensions[2].Name != "x-tags" {
+		t.Fatalf("Unexpected extensions: %+v", extensions)
+	}
+	if extensions[0].Value.Yaml != "team: cats\nsize: 3\n" {
+		t.Errorf("Unexpected yaml for x-owner: %q", extensions[0].Value.Yaml)
+	}
+	var tags []string
+	if err := GetExtensionInto(d.Info, "x-tags", &tags); err != nil || len(tags) != 2 || tags[1] != "b" {
+		t.Errorf("Unexpected value of x-tags: %v (%v)", tags, err)
+	}
+
+	// Extensions can be set on messages that have none.
+	operation := &Operation{}
+	if err := SetExtension(operation, "x-internal", true); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !isMarked(operation, "x-internal") {
+		t.Errorf("Extension was not set: %+v", operation.SpecificationExtension)
+	}
+}
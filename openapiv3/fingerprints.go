@@ -0,0 +1,217 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// OperationFingerprints holds the fingerprints of the schemas of an
+// operation's request body and responses, keyed by media type and,
+// for responses, by status code.
+type OperationFingerprints struct {
+	Path        string                       `json:"path"`
+	Method      string                       `json:"method"`
+	RequestBody map[string]string            `json:"requestBody,omitempty"`
+	Responses   map[string]map[string]string `json:"responses,omitempty"`
+}
+
+// Fingerprints returns the schema fingerprints of all operations of a
+// document, sorted by path and method.
+func Fingerprints(d *Document) []*OperationFingerprints {
+	fingerprints := []*OperationFingerprints{}
+	ForEachOperation(d, func(path, method string, item *PathItem, operation *Operation) {
+		f := &OperationFingerprints{Path: path, Method: method}
+		if requestBody := requestBodyOf(d, operation); requestBody != nil {
+			f.RequestBody = mediaTypeFingerprints(d, requestBody.Content)
+		}
+		for status, response := range responsesOf(d, operation) {
+			if content := mediaTypeFingerprints(d, response.Content); content != nil {
+				if f.Responses == nil {
+					f.Responses = make(map[string]map[string]string)
+				}
+				f.Responses[status] = content
+			}
+		}
+		fingerprints = append(fingerprints, f)
+	})
+	sort.SliceStable(fingerprints, func(i, j int) bool {
+		if fingerprints[i].Path != fingerprints[j].Path {
+			return fingerprints[i].Path < fingerprints[j].Path
+		}
+		return fingerprints[i].Method < fingerprints[j].Method
+	})
+	return fingerprints
+}
+
+// SchemaFingerprint returns the fingerprint of the request body schema
+// (kind "request") or of the schema of the response with a status code
+// (kind "response") of the operation with a path and method. If there
+// are several media types, the schema of "application/json" is used,
+// or else that of the first one. Fingerprints are hashes of schemas
+// with local schema references inlined, so they don't change when keys
+// are reordered or when schemas are moved to or from components.
+func SchemaFingerprint(d *Document, path, method, kind, status string) (string, error) {
+	var operation *Operation
+	ForEachOperation(d, func(p, m string, item *PathItem, o *Operation) {
+		if p == path && m == strings.ToLower(method) {
+			operation = o
+		}
+	})
+	if operation == nil {
+		return "", fmt.Errorf("no operation %s %s", strings.ToUpper(method), path)
+	}
+	var content *MediaTypes
+	switch kind {
+	case "request":
+		requestBody := requestBodyOf(d, operation)
+		if requestBody == nil {
+			return "", fmt.Errorf("%s %s has no request body", strings.ToUpper(method), path)
+		}
+		content = requestBody.Content
+	case "response":
+		response, ok := responsesOf(d, operation)[status]
+		if !ok {
+			return "", fmt.Errorf("%s %s has no %s response", strings.ToUpper(method), path, status)
+		}
+		content = response.Content
+	default:
+		return "", fmt.Errorf("unknown kind %q, expected \"request\" or \"response\"", kind)
+	}
+	var schema *SchemaOrReference
+	if content != nil {
+		for _, pair := range content.AdditionalProperties {
+			if pair.Value.GetSchema() != nil && (schema == nil || pair.Name == "application/json") {
+				schema = pair.Value.Schema
+			}
+		}
+	}
+	if schema == nil {
+		return "", fmt.Errorf("%s %s has no %s schema", strings.ToUpper(method), path, kind)
+	}
+	return schemaFingerprint(d, schema), nil
+}
+
+// requestBodyOf returns the request body of an operation, following a
+// reference to a request body component.
+func requestBodyOf(d *Document, operation *Operation) *RequestBody {
+	if ref := operation.RequestBody.GetReference().GetXRef(); ref != "" {
+		requestBody, _ := Dereference(d, ref)
+		r, _ := requestBody.(*RequestBody)
+		return r
+	}
+	return operation.RequestBody.GetRequestBody()
+}
+
+// responsesOf returns the responses of an operation by status code,
+// following references to response components.
+func responsesOf(d *Document, operation *Operation) map[string]*Response {
+	responses := make(map[string]*Response)
+	add := func(status string, r *ResponseOrReference) {
+		response := r.GetResponse()
+		if ref := r.GetReference().GetXRef(); ref != "" {
+			m, _ := Dereference(d, ref)
+			response, _ = m.(*Response)
+		}
+		if response != nil {
+			responses[status] = response
+		}
+	}
+	if operation.Responses == nil {
+		return responses
+	}
+	if operation.Responses.Default != nil {
+		add("default", operation.Responses.Default)
+	}
+	for _, pair := range operation.Responses.ResponseOrReference {
+		add(pair.Name, pair.Value)
+	}
+	return responses
+}
+
+// mediaTypeFingerprints returns the schema fingerprints of the media
+// types of some content, or nil if none of them has a schema.
+func mediaTypeFingerprints(d *Document, content *MediaTypes) map[string]string {
+	var fingerprints map[string]string
+	for _, pair := range content.GetAdditionalProperties() {
+		if schema := pair.Value.GetSchema(); schema != nil {
+			if fingerprints == nil {
+				fingerprints = make(map[string]string)
+			}
+			fingerprints[pair.Name] = schemaFingerprint(d, schema)
+		}
+	}
+	return fingerprints
+}
+
+// schemaFingerprint returns the canonical hash of a schema with its
+// local schema references inlined.
+func schemaFingerprint(d *Document, schema *SchemaOrReference) string {
+	return compiler.CanonicalHash(inlineSchemaReferences(d, schema.ToRawInfo(), make(map[string]bool)))
+}
+
+// inlineSchemaReferences returns a copy of a schema node in which
+// references to schema components are replaced by the components.
+// References that can't be resolved and references back to a schema
+// that is being inlined are kept as written.
+func inlineSchemaReferences(d *Document, node *yaml.Node, active map[string]bool) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		if ref := referenceOf(node); ref != "" {
+			if !strings.HasPrefix(ref, "#/components/schemas/") || active[ref] {
+				return node
+			}
+			m, err := Dereference(d, ref)
+			schema, ok := m.(*Schema)
+			if err != nil || !ok {
+				return node
+			}
+			active[ref] = true
+			inlined := inlineSchemaReferences(d, schema.ToRawInfo(), active)
+			delete(active, ref)
+			return inlined
+		}
+		fallthrough
+	case yaml.SequenceNode:
+		result := *node
+		result.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			if node.Kind == yaml.MappingNode && i%2 == 0 {
+				result.Content[i] = child
+			} else {
+				result.Content[i] = inlineSchemaReferences(d, child, active)
+			}
+		}
+		return &result
+	}
+	return node
+}
+
+// referenceOf returns the value of the $ref key of a mapping node, or
+// "" if it has none.
+func referenceOf(node *yaml.Node) string {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "$ref" && node.Content[i+1].Kind == yaml.ScalarNode {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"strings"
+	"testing"
+)
+
+const inlineFingerprintsDocument = `
+openapi: 3.0.0
+info:
+  title: Fingerprints
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                tags:
+                  type: array
+                  items:
+                    type: string
+      responses:
+        '200':
+          description: A pet.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+                  name:
+                    type: string
+`
+
+// refFingerprintsDocument is inlineFingerprintsDocument with its schemas
+// moved to components and its keys reordered.
+const refFingerprintsDocument = `
+openapi: 3.0.0
+info:
+  version: 1.0.0
+  title: Fingerprints
+components:
+  schemas:
+    NewPet:
+      properties:
+        tags:
+          $ref: '#/components/schemas/Tags'
+        name:
+          type: string
+      required: [name]
+      type: object
+    Tags:
+      items:
+        type: string
+      type: array
+    Pet:
+      properties:
+        name:
+          type: string
+        id:
+          type: integer
+      type: object
+  requestBodies:
+    NewPet:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/NewPet'
+  responses:
+    Pet:
+      description: A pet.
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pet'
+paths:
+  /pets:
+    post:
+      responses:
+        '200':
+          $ref: '#/components/responses/Pet'
+      requestBody:
+        $ref: '#/components/requestBodies/NewPet'
+`
+
+func TestSchemaFingerprint(t *testing.T) {
+	inline, err := ParseDocument([]byte(inlineFingerprintsDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	ref, err := ParseDocument([]byte(refFingerprintsDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	changed, err := ParseDocument([]byte(strings.Replace(inlineFingerprintsDocument, "type: integer", "type: string", 1)))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, kind := range []string{"request", "response"} {
+		want, err := SchemaFingerprint(inline, "/pets", "POST", kind, "200")
+		if err != nil {
+			t.Fatalf("%s: %+v", kind, err)
+		}
+		got, err := SchemaFingerprint(ref, "/pets", "post", kind, "200")
+		if err != nil {
+			t.Fatalf("%s: %+v", kind, err)
+		}
+		if got != want {
+			t.Errorf("%s: fingerprints of equivalent schemas differ: %s, %s", kind, got, want)
+		}
+		other, err := SchemaFingerprint(changed, "/pets", "post", kind, "200")
+		if err != nil {
+			t.Fatalf("%s: %+v", kind, err)
+		}
+		if (other == want) != (kind == "request") {
+			t.Errorf("%s: unexpected fingerprint of the changed document: %s", kind, other)
+		}
+	}
+	for _, c := range []struct{ path, method, kind, status, message string }{
+		{"/pets", "get", "request", "", "no operation GET /pets"},
+		{"/pets", "post", "response", "404", "POST /pets has no 404 response"},
+		{"/pets", "post", "body", "", `unknown kind "body", expected "request" or "response"`},
+	} {
+		if _, err := SchemaFingerprint(inline, c.path, c.method, c.kind, c.status); err == nil || err.Error() != c.message {
+			t.Errorf("Unexpected error for %s %s %s: %v", c.method, c.kind, c.status, err)
+		}
+	}
+}
+
+func TestSchemaFingerprintOfRecursiveSchema(t *testing.T) {
+	d, err := ParseDocument([]byte(`
+openapi: 3.0.0
+info:
+  title: Fingerprints
+  version: 1.0.0
+paths:
+  /nodes:
+    get:
+      responses:
+        default:
+          description: A tree.
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Node'
+components:
+  schemas:
+    Node:
+      type: object
+      properties:
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, err := SchemaFingerprint(d, "/nodes", "get", "response", "default"); err != nil {
+		t.Errorf("%+v", err)
+	}
+	fingerprints := Fingerprints(d)
+	if len(fingerprints) != 1 || len(fingerprints[0].Responses["default"]) != 1 || fingerprints[0].RequestBody != nil {
+		t.Errorf("Unexpected fingerprints: %+v", fingerprints)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"strings"
+)
+
+// AddHeaderParameter adds a required string header parameter with a name
+// and a description to each operation of a document. Operations that
+// already have a header parameter with the name, or whose path items
+// have one, are skipped. Header names are not case-sensitive. It returns
+// the number of operations that were changed.
+func AddHeaderParameter(d *Document, name, description string) int {
+	hasHeader := func(parameters []*ParameterOrReference) bool {
+		for _, p := range parameters {
+			parameter := dereferenceParameter(d, p)
+			if parameter.GetIn() == "header" && strings.EqualFold(parameter.GetName(), name) {
+				return true
+			}
+		}
+		return false
+	}
+	count := 0
+	ForEachOperation(d, func(path, method string, item *PathItem, operation *Operation) {
+		if hasHeader(item.Parameters) || hasHeader(operation.Parameters) {
+			return
+		}
+		operation.Parameters = append(operation.Parameters, &ParameterOrReference{
+			Oneof: &ParameterOrReference_Parameter{
+				Parameter: &Parameter{
+					Name:        name,
+					In:          "header",
+					Description: description,
+					Required:    true,
+					Schema: &SchemaOrReference{
+						Oneof: &SchemaOrReference_Schema{Schema: &Schema{Type: "string"}},
+					},
+				},
+			},
+		})
+		count++
+	})
+	return count
+}
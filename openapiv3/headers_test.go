@@ -0,0 +1,79 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"testing"
+)
+
+const headersDocument = `
+openapi: 3.0.0
+info:
+  title: Things
+  version: 1.0.0
+paths:
+  /things:
+    get:
+      responses:
+        '200':
+          description: Things.
+    post:
+      parameters:
+      - $ref: '#/components/parameters/Version'
+      responses:
+        '201':
+          description: Created.
+  /widgets:
+    parameters:
+    - name: x-api-version
+      in: header
+      schema:
+        type: string
+    get:
+      responses:
+        '200':
+          description: Widgets.
+components:
+  parameters:
+    Version:
+      name: X-API-Version
+      in: header
+      schema:
+        type: string
+`
+
+func TestAddHeaderParameter(t *testing.T) {
+	d, err := ParseDocument([]byte(headersDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if count := AddHeaderParameter(d, "X-API-Version", "The version."); count != 1 {
+		t.Errorf("AddHeaderParameter changed %d operations, want 1", count)
+	}
+	parameters := d.Paths.Path[0].Value.Get.Parameters
+	if len(parameters) != 1 {
+		t.Fatalf("Unexpected parameters: %+v", parameters)
+	}
+	p := parameters[0].GetParameter()
+	if p.Name != "X-API-Version" || p.In != "header" || !p.Required || p.Description != "The version." || p.Schema.GetSchema().GetType() != "string" {
+		t.Errorf("Unexpected parameter: %+v", p)
+	}
+	if n := len(d.Paths.Path[0].Value.Post.Parameters); n != 1 {
+		t.Errorf("A referenced header was added again")
+	}
+	if n := len(d.Paths.Path[1].Value.Get.Parameters); n != 0 {
+		t.Errorf("A header of a path item was added again")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"strings"
+)
+
+// The page template, style sheet and script of the HTML reference are
+// embedded so that the generated page has no external dependencies.
+var (
+	//go:embed html/reference.html
+	htmlTemplate string
+	//go:embed html/reference.css
+	htmlStyle string
+	//go:embed html/reference.js
+	htmlScript string
+)
+
+// HTMLReference is the data of the HTML reference template.
+type HTMLReference struct {
+	Index   *MarkdownIndex
+	Schemas *MarkdownSchemas
+	Style   template.CSS
+	Script  template.JS
+}
+
+// GenerateHTML generates a single self-contained HTML page that
+// documents a document with the same content as GenerateMarkdown: the
+// description of the API, the operations grouped by their first tags,
+// and the schema components. The style sheet and script that make the
+// page browsable are included in the page, so it can be viewed offline.
+func GenerateHTML(d *Document) ([]byte, error) {
+	t, err := template.New("reference").Funcs(htmlFuncs).Parse(htmlTemplate)
+	if err != nil {
+		return nil, err
+	}
+	index, schemas := markdownModel(d, DefaultMarkdownDepth)
+	var b bytes.Buffer
+	err = t.Execute(&b, &HTMLReference{
+		Index:   index,
+		Schemas: schemas,
+		Style:   template.CSS(htmlStyle),
+		Script:  template.JS(htmlScript),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+var htmlFuncs = template.FuncMap{
+	"anchor": htmlAnchor,
+	"lower":  strings.ToLower,
+	"yesNo":  yesNo,
+}
+
+// htmlAnchor returns the id of an element for a tag, operation or schema,
+// e.g. "operation-get-pets-id" for ("operation", "GET", "/pets/{id}").
+func htmlAnchor(kind string, names ...string) string {
+	return kind + "-" + fileSlug(strings.Join(names, " "))
+}
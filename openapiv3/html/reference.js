@@ -0,0 +1,46 @@
+(function () {
+  "use strict";
+
+  var operations = document.querySelectorAll("details.operation");
+  var navOperations = document.querySelectorAll("#sidebar .nav-operation");
+
+  // Filter operations by their methods, paths and summaries.
+  document.getElementById("filter").addEventListener("input", function (event) {
+    var words = event.target.value.toLowerCase().split(/\s+/).filter(Boolean);
+    var matches = function (text) {
+      text = text.toLowerCase();
+      return words.every(function (word) {
+        return text.indexOf(word) >= 0;
+      });
+    };
+    operations.forEach(function (operation) {
+      operation.classList.toggle("hidden", !matches(operation.querySelector("summary").textContent));
+    });
+    navOperations.forEach(function (item) {
+      item.classList.toggle("hidden", !matches(item.textContent));
+    });
+  });
+
+  var setOpen = function (open) {
+    document.querySelectorAll("details").forEach(function (details) {
+      details.open = open;
+    });
+  };
+  document.getElementById("expand-all").addEventListener("click", function () {
+    setOpen(true);
+  });
+  document.getElementById("collapse-all").addEventListener("click", function () {
+    setOpen(false);
+  });
+
+  // Open the operation or schema that a link refers to.
+  var openTarget = function () {
+    var target = location.hash && document.getElementById(decodeURIComponent(location.hash.slice(1)));
+    if (target && target.tagName === "DETAILS") {
+      target.open = true;
+      target.scrollIntoView();
+    }
+  };
+  window.addEventListener("hashchange", openTarget);
+  openTarget();
+})();
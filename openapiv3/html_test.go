@@ -0,0 +1,71 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGenerateHTML(t *testing.T) {
+	d, err := ParseDocument([]byte(markdownDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	page, err := GenerateHTML(d)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	html := string(page)
+	for _, want := range []string{
+		"<title>Things 1.0.0</title>",
+		`<a href="#tag-things">Things</a>`,
+		`<details class="operation method-post" id="operation-post-things">`,
+		`<a href="#operation-get-health">`,
+		`<details class="schema" id="schema-thing">`,
+		"<td><code>[].name</code></td>",
+		"document.getElementById(\"filter\")",
+		".method-get .method",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %s", want)
+		}
+	}
+	// The page must not load anything from other locations.
+	if external := regexp.MustCompile(`(src|href)="[a-z]+:`).FindString(html); external != "" {
+		t.Errorf("unexpected external reference %s", external)
+	}
+}
+
+func TestGenerateHTMLEscapes(t *testing.T) {
+	d, err := ParseDocument([]byte(`
+openapi: 3.0.0
+info:
+  title: <script>alert(1)</script>
+  version: 1.0.0
+paths: {}
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	page, err := GenerateHTML(d)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if strings.Contains(string(page), "<script>alert(1)") {
+		t.Errorf("title is not escaped")
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// ParseComponents reads the components of a YAML/JSON representation of
+// a schema library. Libraries can be complete OpenAPI v3 descriptions or
+// files that contain only a components object at the top level.
+func ParseComponents(b []byte) (*Components, error) {
+	info, err := compiler.ReadInfoFromBytes("", b)
+	if err != nil {
+		return nil, err
+	}
+	if len(info.Content) < 1 {
+		return nil, errors.New("document has no content")
+	}
+	root := info.Content[0]
+	node := compiler.MapValueForKey(root, "components")
+	if node == nil {
+		return nil, errors.New("document has no components")
+	}
+	return NewComponents(node, compiler.NewContextWithExtensions("components", node, nil, nil))
+}
+
+// ImportComponents adds the components of a schema library to the
+// components of a document and returns the number of components that
+// were added. If prefix is not empty, it is prepended to the names of
+// the imported components and local references in them are changed to
+// use the new names. A component with the name of an existing component
+// is an error, unless there is no prefix and the components are equal;
+// then it is skipped. If there are errors, nothing is imported.
+func ImportComponents(d *Document, library *Components, prefix string) (int, error) {
+	if library == nil {
+		return 0, nil
+	}
+	library = proto.Clone(library).(*Components)
+	if prefix != "" {
+		prefixComponents(library, prefix)
+	}
+	if d.Components == nil {
+		d.Components = &Components{}
+	}
+	var messages []string
+	var additions []func()
+	count := 0
+	target := d.Components.ProtoReflect()
+	library.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if field.Kind() != protoreflect.MessageKind || field.IsList() {
+			return true
+		}
+		imported := componentPairs(v.Message())
+		var existing protoreflect.List
+		if target.Has(field) {
+			existing = componentPairs(target.Get(field).Message())
+		}
+		for i := 0; i < imported.Len(); i++ {
+			pair := imported.Get(i).Message()
+			name := pairName(pair)
+			if duplicate := findPair(existing, name); duplicate != nil {
+				if prefix == "" && proto.Equal(pairValue(duplicate).Interface(), pairValue(pair).Interface()) {
+					continue
+				}
+				messages = append(messages, fmt.Sprintf("#/components/%s/%s is already defined", field.JSONName(), escapeJSONPointer(name)))
+				continue
+			}
+			field, value := field, imported.Get(i)
+			additions = append(additions, func() {
+				section := target.Mutable(field).Message()
+				section.Mutable(section.Descriptor().Fields().ByName("additional_properties")).List().Append(value)
+			})
+			count++
+		}
+		return true
+	})
+	if len(messages) > 0 {
+		return 0, fmt.Errorf("%s", strings.Join(messages, "\n"))
+	}
+	for _, add := range additions {
+		add()
+	}
+	return count, nil
+}
+
+// prefixComponents renames components and updates local references to them.
+func prefixComponents(c *Components, prefix string) {
+	renamed := make(map[string]string)
+	filterComponents(c, func(pointer string) bool { return true }, func(pointer string, pair protoreflect.Message) {
+		name := prefix + pairName(pair)
+		pair.Set(pair.Descriptor().Fields().ByName("name"), protoreflect.ValueOfString(name))
+		renamed[pointer] = pointer[:strings.LastIndex(pointer, "/")+1] + escapeJSONPointer(name)
+	})
+	rewriteReferences(c.ProtoReflect(), func(ref string) string {
+		if target, ok := renamed[componentForRef(ref)]; ok {
+			return target + strings.TrimPrefix(ref, componentForRef(ref))
+		}
+		return ref
+	})
+}
+
+// rewriteReferences replaces each reference in a message with the result of f.
+func rewriteReferences(m protoreflect.Message, f func(ref string) string) {
+	if r, ok := m.Interface().(*Reference); ok {
+		r.XRef = f(r.XRef)
+		return
+	}
+	m.Range(func(field protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if field.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		if field.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				rewriteReferences(list.Get(i).Message(), f)
+			}
+		} else {
+			rewriteReferences(v.Message(), f)
+		}
+		return true
+	})
+}
+
+// componentPairs returns the list of named components of a section.
+func componentPairs(section protoreflect.Message) protoreflect.List {
+	return section.Get(section.Descriptor().Fields().ByName("additional_properties")).List()
+}
+
+func pairName(pair protoreflect.Message) string {
+	return pair.Get(pair.Descriptor().Fields().ByName("name")).String()
+}
+
+func pairValue(pair protoreflect.Message) protoreflect.Message {
+	return pair.Get(pair.Descriptor().Fields().ByName("value")).Message()
+}
+
+// findPair returns the named component with a given name, or nil.
+func findPair(pairs protoreflect.List, name string) protoreflect.Message {
+	if pairs == nil {
+		return nil
+	}
+	for i := 0; i < pairs.Len(); i++ {
+		if pair := pairs.Get(i).Message(); pairName(pair) == name {
+			return pair
+		}
+	}
+	return nil
+}
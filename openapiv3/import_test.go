@@ -0,0 +1,114 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const importLibrary = `
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+    Errors:
+      type: array
+      items:
+        $ref: '#/components/schemas/Error'
+`
+
+const importDocument = `
+openapi: 3.0.0
+info:
+  title: Things
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+`
+
+func TestImportComponents(t *testing.T) {
+	library, err := ParseComponents([]byte(importLibrary))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	d, err := ParseDocument([]byte(importDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	count, err := ImportComponents(d, library, "Common")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if count != 2 {
+		t.Errorf("ImportComponents added %d components, want 2", count)
+	}
+	schemaNamed(t, d, "CommonError")
+	items := schemaNamed(t, d, "CommonErrors").GetItems().GetSchemaOrReference()
+	if len(items) != 1 || items[0].GetReference().GetXRef() != "#/components/schemas/CommonError" {
+		t.Errorf("Unexpected items: %+v", items)
+	}
+	// The library is not changed.
+	if library.Schemas.AdditionalProperties[0].Name != "Error" {
+		t.Errorf("ImportComponents changed the library")
+	}
+
+	// Without a prefix, equal components are skipped.
+	d, err = ParseDocument([]byte(importDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if count, err := ImportComponents(d, library, ""); err != nil || count != 1 {
+		t.Errorf("ImportComponents added %d components: %v", count, err)
+	}
+	if n := len(d.Components.Schemas.AdditionalProperties); n != 2 {
+		t.Errorf("Document has %d schemas, want 2", n)
+	}
+}
+
+func TestImportComponentsErrors(t *testing.T) {
+	library, err := ParseComponents([]byte(importLibrary))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	d, err := ParseDocument([]byte(importDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// Components that differ from existing ones collide.
+	library.Schemas.AdditionalProperties[0].Value.GetSchema().Description = "An error."
+	original := proto.Clone(d)
+	_, err = ImportComponents(d, library, "")
+	expected := "#/components/schemas/Error is already defined"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !proto.Equal(d, original) {
+		t.Errorf("ImportComponents changed a document after an error")
+	}
+	if _, err := ParseComponents([]byte("openapi: 3.0.0\n")); err == nil {
+		t.Errorf("ParseComponents accepted a document without components")
+	}
+}
@@ -0,0 +1,454 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// An IncrementalParser parses successive versions of an OpenAPI v3
+// description, such as the versions of a file that is being edited.
+// Path items and component schemas whose text hasn't changed since the
+// previous call to Parse are not parsed again.
+//
+// Only descriptions that are written in YAML block style are parsed
+// incrementally. Other descriptions, descriptions with errors, and versions
+// in which a changed section is the target of a reference from another
+// section are parsed in full, so Parse always returns the same document
+// and errors as ParseDocument.
+type IncrementalParser struct {
+	sections map[[sha256.Size]byte]*parsedSection // by hash of section text
+	pointers map[string][sha256.Size]byte         // section hashes of the previous version
+
+	// Statistics of the last call to Parse.
+	full     bool // true if the description was parsed in full
+	reparsed int  // the number of sections that were parsed
+}
+
+// NewIncrementalParser creates a parser with an empty cache.
+func NewIncrementalParser() *IncrementalParser {
+	return &IncrementalParser{}
+}
+
+// A parsedSection is a section of a description and its model.
+type parsedSection struct {
+	refs     []string // local references in the section
+	pathItem *NamedPathItem
+	schema   *NamedSchemaOrReference
+	document *Document // for the skeleton
+}
+
+const (
+	skeletonSection = iota
+	pathSection
+	schemaSection
+)
+
+// A textSection is the text of a path item, a component schema, or the
+// skeleton that contains everything else.
+type textSection struct {
+	kind    int
+	pointer string // JSON pointer of the section, "#" for the skeleton
+	text    []byte
+	line    int // index of the first line of the section
+	hash    [sha256.Size]byte
+}
+
+// Parse parses a version of a description. The path items and schemas of
+// the returned document are shared with the parser's cache and with the
+// documents returned for other versions, so callers that modify them should
+// work on a copy made with proto.Clone.
+func (p *IncrementalParser) Parse(b []byte) (*Document, error) {
+	p.full, p.reparsed = false, 0
+	sections, ok := splitSections(b)
+	if !ok {
+		return p.parseFull(b, nil)
+	}
+	parsed := make([]*parsedSection, len(sections))
+	changed := make(map[string]bool)
+	for i, s := range sections {
+		parsed[i] = p.sections[s.hash]
+		if parsed[i] == nil {
+			var err error
+			if parsed[i], err = parseSection(s); err != nil {
+				return p.parseFull(b, sections)
+			}
+			p.reparsed++
+		}
+		if previous, ok := p.pointers[s.pointer]; !ok || previous != s.hash {
+			changed[s.pointer] = true
+		}
+	}
+	if p.pointers != nil {
+		// Sections that were removed are changed too.
+		current := make(map[string]bool, len(sections))
+		for _, s := range sections {
+			current[s.pointer] = true
+		}
+		for pointer := range p.pointers {
+			if !current[pointer] {
+				changed[pointer] = true
+			}
+		}
+		// Unchanged sections that refer to changed sections would
+		// need to be revisited by anything derived from them.
+		for i, s := range sections {
+			if changed[s.pointer] {
+				continue
+			}
+			for _, ref := range parsed[i].refs {
+				if changed[refTarget(ref)] {
+					return p.parseFull(b, sections)
+				}
+			}
+		}
+	}
+
+	p.cache(sections, parsed)
+	d := proto.Clone(parsed[0].document).(*Document)
+	for _, s := range parsed[1:] {
+		if s.pathItem != nil {
+			d.Paths.Path = append(d.Paths.Path, s.pathItem)
+		} else {
+			schemas := d.Components.Schemas
+			schemas.AdditionalProperties = append(schemas.AdditionalProperties, s.schema)
+		}
+	}
+	return d, nil
+}
+
+// parseFull parses a description in full and, if it was split into
+// sections, caches the models of the sections for the next version.
+func (p *IncrementalParser) parseFull(b []byte, sections []*textSection) (*Document, error) {
+	p.full, p.reparsed = true, len(sections)
+	p.sections, p.pointers = nil, nil
+	info, err := compiler.ParseYAML("", b)
+	if err != nil {
+		return nil, err
+	}
+	if len(info.Content) < 1 {
+		return nil, errors.New("document has no content")
+	}
+	root := info.Content[0]
+	d, err := NewDocument(root, compiler.NewContextWithExtensions("$root", root, nil, nil))
+	if err != nil || sections == nil {
+		return d, err
+	}
+	// The sections are in the same order as the entries of the document.
+	// Descriptions with path extensions or duplicate keys aren't cached.
+	parsed := make([]*parsedSection, len(sections))
+	pathNodes := compiler.MapValueForKey(root, "paths")
+	schemaNodes := compiler.MapValueForKey(compiler.MapValueForKey(root, "components"), "schemas")
+	var paths, schemas int
+	for i, s := range sections {
+		switch s.kind {
+		case skeletonSection:
+			parsed[i] = &parsedSection{refs: collectRefs(root, nil, pathNodes, schemaNodes), document: skeleton(d)}
+		case pathSection:
+			if paths >= len(d.Paths.Path) || 2*paths+1 >= len(pathNodes.Content) ||
+				s.pointer != "#/paths/"+escapeJSONPointer(d.Paths.Path[paths].Name) ||
+				pathNodes.Content[2*paths].Value != d.Paths.Path[paths].Name {
+				return d, nil
+			}
+			parsed[i] = &parsedSection{
+				refs:     collectRefs(pathNodes.Content[2*paths+1], nil),
+				pathItem: proto.Clone(d.Paths.Path[paths]).(*NamedPathItem),
+			}
+			paths++
+		case schemaSection:
+			pairs := d.GetComponents().GetSchemas().GetAdditionalProperties()
+			if schemas >= len(pairs) || schemaNodes == nil || 2*schemas+1 >= len(schemaNodes.Content) ||
+				s.pointer != "#/components/schemas/"+escapeJSONPointer(pairs[schemas].Name) ||
+				schemaNodes.Content[2*schemas].Value != pairs[schemas].Name {
+				return d, nil
+			}
+			parsed[i] = &parsedSection{
+				refs:   collectRefs(schemaNodes.Content[2*schemas+1], nil),
+				schema: proto.Clone(pairs[schemas]).(*NamedSchemaOrReference),
+			}
+			schemas++
+		}
+	}
+	p.cache(sections, parsed)
+	return d, nil
+}
+
+// skeleton returns a copy of a document without its path items and schemas.
+func skeleton(d *Document) *Document {
+	paths := d.Paths.Path
+	d.Paths.Path = nil
+	var schemas []*NamedSchemaOrReference
+	if c := d.Components; c != nil && c.Schemas != nil {
+		schemas, c.Schemas.AdditionalProperties = c.Schemas.AdditionalProperties, nil
+		defer func() { c.Schemas.AdditionalProperties = schemas }()
+	}
+	defer func() { d.Paths.Path = paths }()
+	return proto.Clone(d).(*Document)
+}
+
+// cache replaces the cached sections with the sections of a version.
+func (p *IncrementalParser) cache(sections []*textSection, parsed []*parsedSection) {
+	p.sections = make(map[[sha256.Size]byte]*parsedSection, len(sections))
+	p.pointers = make(map[string][sha256.Size]byte, len(sections))
+	for i, s := range sections {
+		p.sections[s.hash] = parsed[i]
+		p.pointers[s.pointer] = s.hash
+	}
+}
+
+// parseSection parses the text of a section and builds its model.
+func parseSection(s *textSection) (*parsedSection, error) {
+	var info yaml.Node
+	if err := yaml.Unmarshal(s.text, &info); err != nil {
+		return nil, err
+	}
+	if len(info.Content) < 1 {
+		return nil, errors.New("section has no content")
+	}
+	node := info.Content[0]
+	if s.kind == skeletonSection {
+		d, err := NewDocument(node, compiler.NewContextWithExtensions("$root", node, nil, nil))
+		if err != nil {
+			return nil, err
+		}
+		return &parsedSection{refs: collectRefs(node, nil), document: d}, nil
+	}
+	if node.Kind != yaml.MappingNode || len(node.Content) != 2 {
+		return nil, errors.New("section is not a single map entry")
+	}
+	name, value := node.Content[0].Value, node.Content[1]
+	if !strings.HasSuffix(s.pointer, "/"+escapeJSONPointer(name)) {
+		return nil, errors.New("section name doesn't match its key")
+	}
+	result := &parsedSection{refs: collectRefs(value, nil)}
+	root := compiler.NewContextWithExtensions("$root", nil, nil, nil)
+	var err error
+	if s.kind == pathSection {
+		if !strings.HasPrefix(name, "/") {
+			return nil, errors.New("section is not a path item")
+		}
+		context := compiler.NewContext(name, value, compiler.NewContext("paths", nil, root))
+		result.pathItem = &NamedPathItem{Name: name}
+		result.pathItem.Value, err = NewPathItem(value, context)
+	} else {
+		context := compiler.NewContext(name, value, compiler.NewContext("schemas", nil, compiler.NewContext("components", nil, root)))
+		result.schema = &NamedSchemaOrReference{Name: name}
+		result.schema.Value, err = NewSchemaOrReference(value, context)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// collectRefs appends the local references in a node to refs,
+// skipping the references in any of the skipped nodes.
+func collectRefs(node *yaml.Node, refs []string, skip ...*yaml.Node) []string {
+	if node == nil {
+		return refs
+	}
+	for _, s := range skip {
+		if node == s {
+			return refs
+		}
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "$ref" && strings.HasPrefix(node.Content[i+1].Value, "#") {
+				refs = append(refs, node.Content[i+1].Value)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		refs = collectRefs(child, refs, skip...)
+	}
+	return refs
+}
+
+// refTarget returns the pointer of the section that contains the target of a
+// local reference. References into the skeleton return "#".
+func refTarget(ref string) string {
+	for _, prefix := range []string{"#/paths/", "#/components/schemas/"} {
+		if strings.HasPrefix(ref, prefix) {
+			return prefix + strings.SplitN(strings.TrimPrefix(ref, prefix), "/", 2)[0]
+		}
+	}
+	return "#"
+}
+
+// splitSections splits a description that is written in YAML block style
+// into its skeleton, path items, and component schemas. The skeleton is the
+// text of the description with empty paths and schemas maps and with blank
+// lines in place of their entries, so that it keeps its line numbers.
+// It returns false if the description can't be split.
+func splitSections(b []byte) ([]*textSection, bool) {
+	lines := bytes.SplitAfter(b, []byte("\n"))
+	offsets := make([]int, len(lines)+1)
+	for i, line := range lines {
+		offsets[i+1] = offsets[i] + len(line)
+	}
+	skeleton := &textSection{kind: skeletonSection, pointer: "#"}
+	sections := []*textSection{skeleton}
+	replaced := make(map[int]string)
+	var blocks [][2]int // line ranges of entries, which are blank in the skeleton
+
+	// addEntries adds the entries of the block in lines[start:end]
+	// as sections and returns false if they can't be split.
+	addEntries := func(kind int, prefix string, start, end int) bool {
+		indent := -1
+		first := -1
+		for i := start; i < end; i++ {
+			line := lines[i]
+			if !isSignificant(line) {
+				continue
+			}
+			if indent < 0 {
+				indent, first = indentation(line), i
+			}
+			switch lineIndent := indentation(line); {
+			case lineIndent < indent:
+				return false
+			case lineIndent == indent:
+				name, ok := entryName(line)
+				if !ok {
+					return false
+				}
+				if n := len(sections); n > 1 && i > first {
+					sections[n-1].text = b[offsets[sections[n-1].line]:offsets[i]]
+				}
+				sections = append(sections, &textSection{kind: kind, pointer: prefix + escapeJSONPointer(name), line: i})
+			}
+		}
+		if first < 0 {
+			return false
+		}
+		last := sections[len(sections)-1]
+		last.text = b[offsets[last.line]:offsets[end]]
+		blocks = append(blocks, [2]int{first, end})
+		return true
+	}
+
+	paths, components := -1, -1
+	for i, line := range lines {
+		if bytes.HasPrefix(line, []byte("---")) || bytes.HasPrefix(line, []byte("...")) ||
+			bytes.HasPrefix(line, []byte("%")) || bytes.IndexByte(line, '\t') >= 0 {
+			return nil, false
+		}
+		if !isSignificant(line) || indentation(line) > 0 {
+			continue
+		}
+		if paths < 0 && components < 0 && (line[0] == '{' || line[0] == '[') {
+			return nil, false
+		}
+		switch string(bytes.TrimSpace(line)) {
+		case "paths:":
+			paths = i
+		case "components:":
+			components = i
+		}
+	}
+	if paths < 0 {
+		return nil, false
+	}
+	if !addEntries(pathSection, "#/paths/", paths+1, blockEnd(lines, paths, 0)) {
+		return nil, false
+	}
+	replaced[paths] = "paths: {}\n"
+	if components >= 0 {
+		end := blockEnd(lines, components, 0)
+		for i := components + 1; i < end; i++ {
+			if isSignificant(lines[i]) && string(bytes.TrimSpace(lines[i])) == "schemas:" {
+				indent := indentation(lines[i])
+				if !addEntries(schemaSection, "#/components/schemas/", i+1, blockEnd(lines, i, indent)) {
+					return nil, false
+				}
+				replaced[i] = strings.Repeat(" ", indent) + "schemas: {}\n"
+				break
+			}
+		}
+	}
+	skeleton.text = make([]byte, 0, len(b))
+	next := 0
+	for _, block := range blocks {
+		skeleton.text = appendSkeleton(skeleton.text, lines[next:block[0]], next, replaced)
+		skeleton.text = append(skeleton.text, bytes.Repeat([]byte("\n"), block[1]-block[0])...)
+		next = block[1]
+	}
+	skeleton.text = appendSkeleton(skeleton.text, lines[next:], next, replaced)
+	for _, s := range sections {
+		h := sha256.New()
+		h.Write([]byte(s.pointer))
+		h.Write([]byte{0})
+		h.Write(s.text)
+		h.Sum(s.hash[:0])
+	}
+	return sections, true
+}
+
+// appendSkeleton appends lines that start at index start to the text of
+// a skeleton, replacing the keys of the paths and schemas maps.
+func appendSkeleton(text []byte, lines [][]byte, start int, replaced map[int]string) []byte {
+	for i, line := range lines {
+		if r, ok := replaced[start+i]; ok {
+			line = []byte(r)
+		}
+		text = append(text, line...)
+	}
+	return text
+}
+
+// blockEnd returns the index of the first line after the block that
+// starts with the key on lines[start], which has the given indentation.
+func blockEnd(lines [][]byte, start, indent int) int {
+	for i := start + 1; i < len(lines); i++ {
+		if isSignificant(lines[i]) && indentation(lines[i]) <= indent {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// entryName returns the key of a line that starts a map entry in block style.
+func entryName(line []byte) (string, bool) {
+	key := string(bytes.TrimSpace(line))
+	if strings.HasPrefix(key, "-") || strings.HasPrefix(key, "?") {
+		return "", false
+	}
+	if i := strings.Index(key, ": "); i >= 0 {
+		key = key[:i]
+	} else if strings.HasSuffix(key, ":") {
+		key = key[:len(key)-1]
+	} else {
+		return "", false
+	}
+	return strings.Trim(key, `'"`), true
+}
+
+func isSignificant(line []byte) bool {
+	trimmed := bytes.TrimSpace(line)
+	return len(trimmed) > 0 && trimmed[0] != '#'
+}
+
+func indentation(line []byte) int {
+	return len(line) - len(bytes.TrimLeft(line, " "))
+}
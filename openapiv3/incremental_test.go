@@ -0,0 +1,160 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// largeDescription returns a description with n paths and 2n schemas.
+// Each path refers to one of the schemas, the others are unreferenced.
+func largeDescription(n int, edits map[string]string) []byte {
+	var b strings.Builder
+	b.WriteString("openapi: 3.0.0\ninfo:\n  title: Things\n  version: 1.0.0\npaths:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `  /things%d/{id}:
+    get:
+      operationId: getThing%d
+      description: Gets a thing.
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: A thing.
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Thing%d'
+`, i, i, i)
+	}
+	b.WriteString("components:\n  schemas:\n")
+	for i := 0; i < 2*n; i++ {
+		fmt.Fprintf(&b, `    Thing%d:
+      type: object
+      description: A thing.
+      properties:
+        name:
+          type: string
+        size:
+          type: integer
+          format: int32
+`, i)
+	}
+	text := b.String()
+	for old, new := range edits {
+		text = strings.Replace(text, old, new, 1)
+	}
+	return []byte(text)
+}
+
+func TestIncrementalParser(t *testing.T) {
+	petstore, err := ioutil.ReadFile("../examples/v3.0/json/petstore.json")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	p := NewIncrementalParser()
+	// Edits are relative to the first version, so sections that were edited
+	// in the previous version are parsed again. The skeleton is parsed again
+	// when the number of lines of the paths or schemas changes.
+	for _, test := range []struct {
+		name        string
+		description []byte
+		full        bool
+		reparsed    int
+	}{
+		{"first version", largeDescription(10, nil), false, 31},
+		{"unchanged", largeDescription(10, nil), false, 0},
+		{"path edit", largeDescription(10, map[string]string{"Gets a thing.": "Gets the first thing."}), false, 1},
+		{"skeleton edit", largeDescription(10, map[string]string{"title: Things": "title: Stuff"}), false, 2},
+		{"path removal", largeDescription(10, map[string]string{"  /things3/{id}:": "  /things3/{id}: {}\n  /unused:"}), false, 3},
+		{"unreferenced schema edit", largeDescription(10, map[string]string{"    Thing15:\n      type: object\n      description: A thing.": "    Thing15:\n      type: object\n      description: A big thing."}), false, 3},
+		{"referenced schema edit", largeDescription(10, map[string]string{"    Thing5:\n      type: object\n      description: A thing.": "    Thing5:\n      type: object\n      description: A big thing."}), true, 31},
+		{"path edit after full parse", largeDescription(10, map[string]string{"Gets a thing.": "Gets the first thing.", "    Thing5:\n      type: object\n      description: A thing.": "    Thing5:\n      type: object\n      description: A big thing."}), false, 1},
+		{"json", petstore, true, 0},
+		{"after json", largeDescription(10, nil), false, 31},
+	} {
+		d, err := p.Parse(test.description)
+		if err != nil {
+			t.Fatalf("%s: %+v", test.name, err)
+		}
+		expected, err := ParseDocument(test.description)
+		if err != nil {
+			t.Fatalf("%s: %+v", test.name, err)
+		}
+		if !proto.Equal(d, expected) {
+			t.Errorf("%s: incremental result differs from ParseDocument", test.name)
+		}
+		if p.full != test.full || p.reparsed != test.reparsed {
+			t.Errorf("%s: full=%t reparsed=%d, want full=%t reparsed=%d", test.name, p.full, p.reparsed, test.full, test.reparsed)
+		}
+		// Changes to the skeleton of results don't affect later versions.
+		d.Info.Title = "changed"
+	}
+}
+
+func TestIncrementalParserErrors(t *testing.T) {
+	p := NewIncrementalParser()
+	if _, err := p.Parse(largeDescription(3, nil)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, description := range [][]byte{
+		largeDescription(3, map[string]string{"          required: true": "          required: maybe"}),
+		largeDescription(3, map[string]string{"    get:\n      operationId: getThing1": "    get:\n     operationId: getThing1"}),
+		[]byte("openapi: 3.0.0\n"),
+	} {
+		_, err := p.Parse(description)
+		_, expected := ParseDocument(description)
+		if err == nil || expected == nil || err.Error() != expected.Error() {
+			t.Errorf("got error %v, want %v", err, expected)
+		}
+	}
+}
+
+func BenchmarkParseDocument(b *testing.B) {
+	description := largeDescription(1000, nil)
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseDocument(description); err != nil {
+			b.Fatalf("%+v", err)
+		}
+	}
+}
+
+// BenchmarkIncrementalParserPathEdit parses alternating versions of a
+// description that differ in the description of one path item.
+func BenchmarkIncrementalParserPathEdit(b *testing.B) {
+	versions := [][]byte{
+		largeDescription(1000, nil),
+		largeDescription(1000, map[string]string{"Gets a thing.": "Gets the first thing."}),
+	}
+	p := NewIncrementalParser()
+	if _, err := p.Parse(versions[1]); err != nil {
+		b.Fatalf("%+v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Parse(versions[i%2]); err != nil {
+			b.Fatalf("%+v", err)
+		}
+	}
+}
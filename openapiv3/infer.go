@@ -0,0 +1,144 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+	"github.com/google/gnostic/jsonschema"
+)
+
+// InferredExtensionName is the name of the extension that marks the
+// schemas that InferMissingSchemas inferred from examples.
+const InferredExtensionName = "x-gnostic-inferred"
+
+// InferMissingSchemas adds schemas to the media types that have examples
+// but no schema or an empty one. The schemas are inferred from all of the
+// examples of a media type: objects have the properties of every example
+// and require the properties that every example has. Inferred schemas are
+// marked with an x-gnostic-inferred extension. It returns the number of
+// schemas that were added.
+//
+// Since OpenAPI 3.0 does not allow type arrays, values that are sometimes
+// null are nullable, and values with several other types have no type.
+func InferMissingSchemas(d *Document) int {
+	if d == nil {
+		return 0
+	}
+	resolver := &exampleResolver{document: d}
+	count := 0
+	visitMessages(d.ProtoReflect(), func(m protoreflect.Message) {
+		mediaType, ok := m.Interface().(*MediaType)
+		if !ok || mediaType.Schema.GetReference() != nil || proto.Size(mediaType.Schema.GetSchema()) > 0 {
+			return
+		}
+		instances := mediaTypeInstances(resolver, mediaType)
+		if len(instances) == 0 {
+			return
+		}
+		inferred := jsonschema.InferFromInstances(instances, jsonschema.InferOptions{})
+		node := openAPISchemaNode(inferred)
+		schema, err := NewSchema(node, compiler.NewContext("schema", node, nil))
+		if err != nil {
+			return
+		}
+		SetExtension(schema, InferredExtensionName, true)
+		mediaType.Schema = &SchemaOrReference{Oneof: &SchemaOrReference_Schema{Schema: schema}}
+		count++
+	})
+	return count
+}
+
+// mediaTypeInstances returns the values of the examples of a media type.
+// Examples that can't be resolved are skipped.
+func mediaTypeInstances(resolver *exampleResolver, mediaType *MediaType) []*yaml.Node {
+	values := []*Any{mediaType.Example}
+	if mediaType.Examples != nil {
+		for _, pair := range mediaType.Examples.AdditionalProperties {
+			example := pair.Value.GetExample()
+			if ref := pair.Value.GetReference().GetXRef(); ref != "" {
+				example, _ = resolver.exampleForRef(ref)
+			}
+			values = append(values, ExampleValue(example))
+		}
+	}
+	var instances []*yaml.Node
+	for _, value := range values {
+		if value == nil || value.Yaml == "" {
+			continue
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(value.Yaml), &node); err != nil || len(node.Content) == 0 {
+			continue
+		}
+		instances = append(instances, node.Content[0])
+	}
+	return instances
+}
+
+// openAPISchemaNode returns the OpenAPI 3.0 form of an inferred schema.
+func openAPISchemaNode(s *jsonschema.Schema) *yaml.Node {
+	node := compiler.NewMappingNode()
+	add := func(name string, value *yaml.Node) {
+		node.Content = append(node.Content, compiler.NewScalarNodeForString(name), value)
+	}
+	var types []string
+	nullable := false
+	if s.Type != nil && s.Type.String != nil {
+		types = []string{*s.Type.String}
+	} else if s.Type != nil && s.Type.StringArray != nil {
+		types = *s.Type.StringArray
+	}
+	var others []string
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+		} else {
+			others = append(others, t)
+		}
+	}
+	if len(others) == 1 {
+		add("type", compiler.NewScalarNodeForString(others[0]))
+		if s.Format != nil {
+			add("format", compiler.NewScalarNodeForString(*s.Format))
+		}
+	}
+	if nullable {
+		add("nullable", compiler.NewScalarNodeForBool(true))
+	}
+	if s.Properties != nil {
+		properties := compiler.NewMappingNode()
+		for _, property := range *s.Properties {
+			properties.Content = append(properties.Content,
+				compiler.NewScalarNodeForString(property.Name), openAPISchemaNode(property.Value))
+		}
+		add("properties", properties)
+	}
+	if s.Required != nil && len(*s.Required) > 0 {
+		add("required", compiler.NewSequenceNodeForStringArray(*s.Required))
+	}
+	if s.TypeIs("array") {
+		// Arrays need items, even if there were no elements to infer them from.
+		items := compiler.NewMappingNode()
+		if s.Items != nil && s.Items.Schema != nil {
+			items = openAPISchemaNode(s.Items.Schema)
+		}
+		add("items", items)
+	}
+	return node
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"testing"
+
+	"github.com/google/gnostic/compiler"
+)
+
+const inferDocument = `
+openapi: 3.0.0
+info:
+  title: Inference
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: The pets.
+          content:
+            application/json:
+              example:
+                - id: 1
+                  name: Fido
+                  born: '2020-01-01'
+                - id: 2
+                  name: Rex
+                  tag: null
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {}
+            examples:
+              dog:
+                value:
+                  name: Fido
+                  weight: 12
+                  tag: dog
+              cat:
+                $ref: '#/components/examples/Cat'
+          text/plain:
+            schema:
+              type: string
+            example: Fido
+      responses:
+        '204':
+          description: Created.
+          content:
+            application/json: {}
+components:
+  examples:
+    Cat:
+      value:
+        name: Tom
+        weight: 4.5
+`
+
+func TestInferMissingSchemas(t *testing.T) {
+	d, err := ParseDocument([]byte(inferDocument))
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if count := InferMissingSchemas(d); count != 2 {
+		t.Errorf("unexpected number of inferred schemas: %d (expected 2)", count)
+	}
+	pets := d.Paths.Path[0].Value
+
+	list := pets.Get.Responses.ResponseOrReference[0].Value.GetResponse().Content.AdditionalProperties[0].Value.Schema.GetSchema()
+	expected := `type: array
+items:
+    required:
+        - id
+        - name
+    type: object
+    properties:
+        id:
+            type: integer
+        name:
+            type: string
+        born:
+            type: string
+            format: date
+        tag:
+            nullable: true
+x-gnostic-inferred: true
+`
+	if got := string(compiler.Marshal(list.ToRawInfo())); got != expected {
+		t.Errorf("unexpected schema for GET /pets:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	content := pets.Post.RequestBody.GetRequestBody().Content.AdditionalProperties
+	body := content[0].Value.Schema.GetSchema()
+	expected = `required:
+    - name
+    - weight
+type: object
+properties:
+    name:
+        type: string
+    weight:
+        type: number
+    tag:
+        type: string
+x-gnostic-inferred: true
+`
+	if got := string(compiler.Marshal(body.ToRawInfo())); got != expected {
+		t.Errorf("unexpected schema for POST /pets:\n%s\nexpected:\n%s", got, expected)
+	}
+	if text := content[1].Value.Schema.GetSchema(); text.Type != "string" || len(text.SpecificationExtension) != 0 {
+		t.Errorf("existing schema should not be replaced: %+v", text)
+	}
+
+	created := pets.Post.Responses.ResponseOrReference[0].Value.GetResponse().Content.AdditionalProperties[0].Value
+	if created.Schema != nil {
+		t.Errorf("media type without examples should not get a schema: %+v", created.Schema)
+	}
+}
@@ -0,0 +1,597 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// A DocFile is a generated documentation file.
+type DocFile struct {
+	Name string
+	Data []byte
+}
+
+// DefaultMarkdownDepth is the default nesting depth of the properties
+// that are listed in schema tables.
+const DefaultMarkdownDepth = 3
+
+// MarkdownOptions configure GenerateMarkdownWithOptions.
+type MarkdownOptions struct {
+	// Depth is the number of levels of nested properties that are listed
+	// in schema tables. Zero means DefaultMarkdownDepth.
+	Depth int
+	// Templates replace default templates by name. The "index" template
+	// renders README.md from a *MarkdownIndex, the "tag" template renders
+	// the file of each tag from a *MarkdownTag, and the "schemas" template
+	// renders schemas.md from a *MarkdownSchemas. The default templates
+	// also define "operation", "content" and "properties", which can be
+	// replaced separately.
+	Templates map[string]string
+}
+
+// A MarkdownIndex describes an API and links to the other files.
+type MarkdownIndex struct {
+	Title       string
+	Version     string
+	Description string
+	Servers     []*MarkdownServer
+	Tags        []*MarkdownTag
+	// SchemasFile is the name of the schemas file, or empty if the
+	// document has no schema components.
+	SchemasFile string
+}
+
+// A MarkdownServer is a server of an API.
+type MarkdownServer struct {
+	URL         string
+	Description string
+}
+
+// A MarkdownTag is a tag, or the path of operations without tags, and
+// the operations that it documents.
+type MarkdownTag struct {
+	Title       string
+	File        string
+	Description string
+	Operations  []*MarkdownOperation
+}
+
+// A MarkdownOperation is an operation of a tag.
+type MarkdownOperation struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	OperationID string
+	Deprecated  bool
+	Parameters  []*MarkdownParameter
+	RequestBody *MarkdownRequestBody
+	Responses   []*MarkdownResponse
+}
+
+// A MarkdownParameter is a parameter of an operation.
+type MarkdownParameter struct {
+	Name        string
+	In          string
+	Type        string
+	Required    bool
+	Description string
+}
+
+// A MarkdownRequestBody is the request body of an operation.
+type MarkdownRequestBody struct {
+	Description string
+	Required    bool
+	Content     []*MarkdownContent
+}
+
+// A MarkdownResponse is a response of an operation.
+type MarkdownResponse struct {
+	Status      string
+	Description string
+	Content     []*MarkdownContent
+}
+
+// A MarkdownContent is a media type of a request body or response.
+type MarkdownContent struct {
+	MediaType  string
+	Type       string
+	Properties []*MarkdownProperty
+	// Example is an indented JSON example for JSON media types.
+	Example string
+}
+
+// A MarkdownProperty is a property of a schema. Names of nested
+// properties are paths like "owner.name" or "tags[].name".
+type MarkdownProperty struct {
+	Name        string
+	Type        string
+	Required    bool
+	Description string
+}
+
+// MarkdownSchemas are the schema components of a document.
+type MarkdownSchemas struct {
+	Title   string
+	Schemas []*MarkdownSchema
+}
+
+// A MarkdownSchema is a schema component.
+type MarkdownSchema struct {
+	Name        string
+	Type        string
+	Description string
+	Properties  []*MarkdownProperty
+	Example     string
+}
+
+// GenerateMarkdown generates Markdown documentation for a document: a
+// README.md file with the description of the API and links to the other
+// files, a file for each tag with the operations whose first tag it is,
+// and a schemas.md file with the schema components. Operations without
+// tags are documented in a file for their path. Each operation is
+// described with tables of its parameters, request body, and responses,
+// the properties of their schemas, and examples. References are resolved
+// with Dereference; schemas are named by their components.
+func GenerateMarkdown(d *Document) []*DocFile {
+	files, _ := GenerateMarkdownWithOptions(d, nil)
+	return files
+}
+
+// GenerateMarkdownWithOptions generates Markdown documentation like
+// GenerateMarkdown with options that set the depth of property tables
+// and replace templates. It fails if a template is invalid.
+func GenerateMarkdownWithOptions(d *Document, opts *MarkdownOptions) ([]*DocFile, error) {
+	if opts == nil {
+		opts = &MarkdownOptions{}
+	}
+	t, err := markdownTemplates(opts.Templates)
+	if err != nil {
+		return nil, err
+	}
+	index, schemas := markdownModel(d, opts.Depth)
+
+	var files []*DocFile
+	render := func(name, template string, data interface{}) error {
+		var b bytes.Buffer
+		if err := t.ExecuteTemplate(&b, template, data); err != nil {
+			return err
+		}
+		files = append(files, &DocFile{Name: name, Data: markdownData(b.String())})
+		return nil
+	}
+	if err := render("README.md", "index", index); err != nil {
+		return nil, err
+	}
+	for _, tag := range index.Tags {
+		if err := render(tag.File, "tag", tag); err != nil {
+			return nil, err
+		}
+	}
+	if index.SchemasFile != "" {
+		if err := render(index.SchemasFile, "schemas", schemas); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// markdownModel returns the data of the documentation of a document:
+// the index with its tags and operations, and the schema components.
+func markdownModel(d *Document, depth int) (*MarkdownIndex, *MarkdownSchemas) {
+	g := &markdownGenerator{document: d, resolver: &mockHandler{document: d}, depth: depth, tags: make(map[string]*MarkdownTag)}
+	if g.depth <= 0 {
+		g.depth = DefaultMarkdownDepth
+	}
+	ForEachOperation(d, func(path, method string, item *PathItem, operation *Operation) {
+		title := path
+		if len(operation.Tags) > 0 {
+			title = operation.Tags[0]
+		}
+		tag := g.tag(title)
+		tag.Operations = append(tag.Operations, g.operation(path, method, item, operation))
+	})
+
+	info := d.GetInfo()
+	index := &MarkdownIndex{
+		Title:       info.GetTitle(),
+		Version:     info.GetVersion(),
+		Description: info.GetDescription(),
+	}
+	for _, server := range d.GetServers() {
+		index.Servers = append(index.Servers, &MarkdownServer{URL: server.GetUrl(), Description: server.GetDescription()})
+	}
+	for _, title := range g.titles {
+		index.Tags = append(index.Tags, g.tags[title])
+	}
+	schemas := &MarkdownSchemas{Title: "Schemas"}
+	for _, pair := range d.GetComponents().GetSchemas().GetAdditionalProperties() {
+		schemas.Schemas = append(schemas.Schemas, g.schema(pair))
+	}
+	if len(schemas.Schemas) > 0 {
+		index.SchemasFile = "schemas.md"
+	}
+	return index, schemas
+}
+
+// markdownTemplates returns the default templates with replacements.
+func markdownTemplates(replacements map[string]string) (*template.Template, error) {
+	t := template.Must(template.New("markdown").Funcs(markdownFuncs).Parse(defaultMarkdownTemplates))
+	for name, text := range replacements {
+		if _, err := t.New(name).Parse(text); err != nil {
+			return nil, fmt.Errorf("invalid %s template: %s", name, err.Error())
+		}
+	}
+	return t, nil
+}
+
+var markdownFuncs = template.FuncMap{
+	"row":     row,
+	"yesNo":   yesNo,
+	"oneLine": oneLine,
+	"trim":    strings.TrimSpace,
+	"fence":   func() string { return "```" },
+}
+
+// defaultMarkdownTemplates render every block followed by a blank line;
+// markdownData removes the blank lines at the ends of files.
+const defaultMarkdownTemplates = `
+{{- define "index"}}# {{.Title}}
+
+{{with .Version}}Version: {{.}}
+
+{{end}}{{with trim .Description}}{{.}}
+
+{{end}}{{if .Servers}}## Servers
+
+{{range .Servers}}- {{.URL}}{{with .Description}}: {{oneLine .}}{{end}}
+{{end}}
+{{end}}{{if .Tags}}## Operations
+
+{{range .Tags}}- [{{.Title}}]({{.File}}){{with .Description}}: {{oneLine .}}{{end}}
+{{end}}
+{{end}}{{with .SchemasFile}}## Schemas
+
+- [Schemas]({{.}})
+{{end}}{{end}}
+
+{{- define "tag"}}# {{.Title}}
+
+{{range .Operations}}{{template "operation" .}}{{end}}{{end}}
+
+{{- define "operation"}}## {{.Method}} {{.Path}}
+
+{{with .Summary}}{{oneLine .}}
+
+{{end}}{{with trim .Description}}{{.}}
+
+{{end}}{{with .OperationID}}Operation ID: ` + "`{{.}}`" + `
+
+{{end}}{{if .Deprecated}}**Deprecated.**
+
+{{end}}{{if .Parameters}}### Parameters
+
+| Name | In | Type | Required | Description |
+| --- | --- | --- | --- | --- |
+{{range .Parameters}}{{row .Name .In .Type (yesNo .Required) .Description}}
+{{end}}
+{{end}}{{with .RequestBody}}### Request body
+
+{{with trim .Description}}{{.}}
+
+{{end}}| Media type | Schema | Required |
+| --- | --- | --- |
+{{range .Content}}{{row .MediaType .Type (yesNo $.RequestBody.Required)}}
+{{end}}
+{{range .Content}}{{if or .Properties .Example}}#### {{.MediaType}}
+
+{{template "content" .}}{{end}}{{end}}{{end}}{{if .Responses}}### Responses
+
+| Status | Description | Media type | Schema |
+| --- | --- | --- | --- |
+{{range $r := .Responses}}{{range .Content}}{{row $r.Status $r.Description .MediaType .Type}}
+{{else}}{{row .Status .Description "" ""}}
+{{end}}{{end}}
+{{range .Responses}}{{$status := .Status}}{{range .Content}}{{if or .Properties .Example}}#### {{$status}} {{.MediaType}}
+
+{{template "content" .}}{{end}}{{end}}{{end}}{{end}}{{end}}
+
+{{- define "content"}}{{if .Properties}}{{template "properties" .Properties}}{{end}}{{with .Example}}{{fence}}json
+{{.}}
+{{fence}}
+
+{{end}}{{end}}
+
+{{- define "properties"}}| Property | Type | Required | Description |
+| --- | --- | --- | --- |
+{{range .}}{{row .Name .Type (yesNo .Required) .Description}}
+{{end}}
+{{end}}
+
+{{- define "schemas"}}# {{.Title}}
+
+{{range .Schemas}}## {{.Name}}
+
+{{with trim .Description}}{{.}}
+
+{{end}}{{with .Type}}Type: {{.}}
+
+{{end}}{{if .Properties}}{{template "properties" .Properties}}{{end}}{{with .Example}}{{fence}}json
+{{.}}
+{{fence}}
+
+{{end}}{{end}}{{end}}
+`
+
+// markdownData returns the contents of a file with a single final newline.
+func markdownData(s string) []byte {
+	return []byte(strings.TrimRight(s, "\n") + "\n")
+}
+
+// A markdownGenerator collects the tags of a document's documentation.
+type markdownGenerator struct {
+	document *Document
+	resolver *mockHandler
+	depth    int
+	titles   []string
+	tags     map[string]*MarkdownTag
+}
+
+// tag returns the tag with a title, creating it if necessary.
+func (g *markdownGenerator) tag(title string) *MarkdownTag {
+	if tag, ok := g.tags[title]; ok {
+		return tag
+	}
+	used := map[string]bool{"README.md": true, "schemas.md": true}
+	for _, tag := range g.tags {
+		used[tag.File] = true
+	}
+	base := fileSlug(title)
+	name := base + ".md"
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s-%d.md", base, i)
+	}
+	tag := &MarkdownTag{Title: title, File: name, Description: tagDescription(g.document, title)}
+	g.titles = append(g.titles, title)
+	g.tags[title] = tag
+	return tag
+}
+
+var nonSlugCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// fileSlug returns a file name without extension for a tag or path.
+func fileSlug(title string) string {
+	slug := strings.Trim(nonSlugCharacters.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if slug == "" {
+		return "root"
+	}
+	return slug
+}
+
+func tagDescription(d *Document, name string) string {
+	for _, tag := range d.GetTags() {
+		if tag.GetName() == name {
+			return tag.GetDescription()
+		}
+	}
+	return ""
+}
+
+func (g *markdownGenerator) operation(path, method string, item *PathItem, operation *Operation) *MarkdownOperation {
+	o := &MarkdownOperation{
+		Method:      strings.ToUpper(method),
+		Path:        path,
+		Summary:     operation.Summary,
+		Description: operation.Description,
+		OperationID: operation.OperationId,
+		Deprecated:  operation.Deprecated,
+	}
+	for _, p := range append(append([]*ParameterOrReference{}, item.Parameters...), operation.Parameters...) {
+		parameter := p.GetParameter()
+		if ref := p.GetReference().GetXRef(); ref != "" {
+			m, _ := Dereference(g.document, ref)
+			parameter, _ = m.(*Parameter)
+		}
+		if parameter != nil {
+			o.Parameters = append(o.Parameters, &MarkdownParameter{
+				Name:        parameter.Name,
+				In:          parameter.In,
+				Type:        schemaDescription(parameter.Schema),
+				Required:    parameter.Required,
+				Description: parameter.Description,
+			})
+		}
+	}
+
+	body := operation.GetRequestBody().GetRequestBody()
+	if ref := operation.GetRequestBody().GetReference().GetXRef(); ref != "" {
+		m, _ := Dereference(g.document, ref)
+		body, _ = m.(*RequestBody)
+	}
+	if body != nil {
+		o.RequestBody = &MarkdownRequestBody{
+			Description: body.Description,
+			Required:    body.Required,
+			Content:     g.content(body.GetContent()),
+		}
+	}
+
+	responses := operation.GetResponses()
+	pairs := append([]*NamedResponseOrReference{}, responses.GetResponseOrReference()...)
+	if responses.GetDefault() != nil {
+		pairs = append(pairs, &NamedResponseOrReference{Name: "default", Value: responses.GetDefault()})
+	}
+	for _, pair := range pairs {
+		response := pair.Value.GetResponse()
+		if ref := pair.Value.GetReference().GetXRef(); ref != "" {
+			m, _ := Dereference(g.document, ref)
+			response, _ = m.(*Response)
+		}
+		o.Responses = append(o.Responses, &MarkdownResponse{
+			Status:      pair.Name,
+			Description: response.GetDescription(),
+			Content:     g.content(response.GetContent()),
+		})
+	}
+	return o
+}
+
+func (g *markdownGenerator) content(content *MediaTypes) []*MarkdownContent {
+	var result []*MarkdownContent
+	for _, pair := range content.GetAdditionalProperties() {
+		c := &MarkdownContent{
+			MediaType:  pair.Name,
+			Type:       schemaDescription(pair.Value.GetSchema()),
+			Properties: g.properties(pair.Value.GetSchema(), "", g.depth, make(map[string]bool)),
+		}
+		if strings.Contains(pair.Name, "json") {
+			c.Example = exampleJSON(g.resolver.mediaTypeValue(pair.Value))
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+func (g *markdownGenerator) schema(pair *NamedSchemaOrReference) *MarkdownSchema {
+	s := &SchemaOrReference{Oneof: &SchemaOrReference_Reference{
+		Reference: &Reference{XRef: "#/components/schemas/" + escapeJSONPointer(pair.Name)},
+	}}
+	schema := dereferenceSchema(g.document, pair.Value)
+	return &MarkdownSchema{
+		Name:        pair.Name,
+		Type:        schemaDescription(&SchemaOrReference{Oneof: &SchemaOrReference_Schema{Schema: schema}}),
+		Description: schema.GetDescription(),
+		Properties:  g.properties(s, "", g.depth, make(map[string]bool)),
+		Example:     exampleJSON(g.resolver.schemaValue(s, make(map[string]bool))),
+	}
+}
+
+// properties returns the properties of a schema and, up to a depth, of
+// the schemas of its properties. The properties of allOf members are
+// merged and the items of arrays are expanded with "[]" names. Schema
+// components are expanded once in each path.
+func (g *markdownGenerator) properties(s *SchemaOrReference, prefix string, depth int, seen map[string]bool) []*MarkdownProperty {
+	if ref := s.GetReference().GetXRef(); ref != "" {
+		if seen[ref] {
+			return nil
+		}
+		seen[ref] = true
+		defer delete(seen, ref)
+	}
+	schema := dereferenceSchema(g.document, s)
+	if schema == nil || depth <= 0 {
+		return nil
+	}
+	if items := schema.GetItems().GetSchemaOrReference(); schema.Type == "array" && len(items) > 0 {
+		return g.properties(items[0], strings.TrimSuffix(prefix, ".")+"[].", depth, seen)
+	}
+	var result []*MarkdownProperty
+	for _, member := range schema.AllOf {
+		result = append(result, g.properties(member, prefix, depth, seen)...)
+	}
+	for _, pair := range schema.GetProperties().GetAdditionalProperties() {
+		required := false
+		for _, name := range schema.Required {
+			required = required || name == pair.Name
+		}
+		result = append(result, &MarkdownProperty{
+			Name:        prefix + pair.Name,
+			Type:        schemaDescription(pair.Value),
+			Required:    required,
+			Description: dereferenceSchema(g.document, pair.Value).GetDescription(),
+		})
+		result = append(result, g.properties(pair.Value, prefix+pair.Name+".", depth-1, seen)...)
+	}
+	return result
+}
+
+// exampleJSON returns a value as indented JSON, or an empty string for
+// nil values and values that can't be serialized.
+func exampleJSON(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// schemaDescription returns a short description of a schema's type, such
+// as "integer (int32)", "array of Pet", or "map of string".
+func schemaDescription(s *SchemaOrReference) string {
+	if ref := s.GetReference().GetXRef(); ref != "" {
+		return ref[strings.LastIndex(ref, "/")+1:]
+	}
+	schema := s.GetSchema()
+	if schema == nil {
+		return ""
+	}
+	switch {
+	case schema.Type == "array":
+		if items := schema.GetItems().GetSchemaOrReference(); len(items) > 0 {
+			return "array of " + schemaDescription(items[0])
+		}
+		return "array"
+	case schema.GetAdditionalProperties().GetSchemaOrReference() != nil:
+		return "map of " + schemaDescription(schema.GetAdditionalProperties().GetSchemaOrReference())
+	case len(schema.OneOf) > 0:
+		return unionDescription("one of", schema.OneOf)
+	case len(schema.AnyOf) > 0:
+		return unionDescription("any of", schema.AnyOf)
+	case len(schema.AllOf) > 0:
+		return unionDescription("all of", schema.AllOf)
+	case schema.Format != "":
+		return schema.Type + " (" + schema.Format + ")"
+	}
+	return schema.Type
+}
+
+func unionDescription(kind string, members []*SchemaOrReference) string {
+	descriptions := make([]string, len(members))
+	for i, member := range members {
+		descriptions[i] = schemaDescription(member)
+	}
+	return kind + " " + strings.Join(descriptions, ", ")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// row returns a table row. Newlines and pipes in cells are escaped.
+func row(cells ...string) string {
+	var b strings.Builder
+	b.WriteString("|")
+	for _, cell := range cells {
+		b.WriteString(" " + strings.Replace(oneLine(cell), "|", `\|`, -1) + " |")
+	}
+	return b.String()
+}
+
+// oneLine joins the lines of a text with spaces.
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
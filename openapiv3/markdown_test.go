@@ -0,0 +1,163 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"strings"
+	"testing"
+)
+
+const markdownDocument = `
+openapi: 3.0.0
+info:
+  title: Things
+  version: 1.0.0
+tags:
+- name: Things
+  description: Things and
+    more things.
+paths:
+  /things:
+    post:
+      tags: [Things]
+      parameters:
+      - $ref: '#/components/parameters/Filter'
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: array
+              items:
+                $ref: '#/components/schemas/Thing'
+      responses:
+        '201':
+          $ref: '#/components/responses/Created'
+  /health:
+    get:
+      responses:
+        '200':
+          description: Healthy.
+components:
+  schemas:
+    Thing:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          example: hammer
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+      description: The owner of a thing.
+      properties:
+        name:
+          type: string
+  parameters:
+    Filter:
+      name: filter
+      in: query
+      description: A filter like a|b.
+      schema:
+        type: string
+  responses:
+    Created:
+      description: Created.
+      content:
+        application/json:
+          schema:
+            type: object
+            additionalProperties:
+              $ref: '#/components/schemas/Thing'
+`
+
+func TestGenerateMarkdown(t *testing.T) {
+	d, err := ParseDocument([]byte(markdownDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	files := GenerateMarkdown(d)
+	contents := make(map[string]string)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+		contents[file.Name] = string(file.Data)
+	}
+	if strings.Join(names, ",") != "README.md,things.md,health.md,schemas.md" {
+		t.Fatalf("Unexpected files: %v", names)
+	}
+	for name, lines := range map[string][]string{
+		"README.md": {
+			"- [Things](things.md): Things and more things.",
+			"- [/health](health.md)",
+			"- [Schemas](schemas.md)",
+		},
+		"things.md": {
+			"| filter | query | string | no | A filter like a\\|b. |",
+			"| application/json | array of Thing | yes |",
+			"| 201 | Created. | application/json | map of Thing |",
+			"| [].name | string | yes |  |",
+			"| [].owner | Owner | no | The owner of a thing. |",
+			"| [].owner.name | string | no |  |",
+			`    "name": "hammer",`,
+		},
+		"health.md": {
+			"## GET /health",
+			"| 200 | Healthy. |  |  |",
+		},
+		"schemas.md": {
+			"## Owner",
+			"| owner.name | string | no |  |",
+		},
+	} {
+		for _, line := range lines {
+			if !strings.Contains(contents[name], line+"\n") {
+				t.Errorf("%s doesn't contain %s:\n%s", name, line, contents[name])
+			}
+		}
+	}
+}
+
+func TestGenerateMarkdownWithOptions(t *testing.T) {
+	d, err := ParseDocument([]byte(markdownDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	files, err := GenerateMarkdownWithOptions(d, &MarkdownOptions{
+		Depth: 1,
+		Templates: map[string]string{
+			"index": "{{.Title}}:{{range .Tags}} {{.File}}{{end}}",
+			"tag":   "{{range .Operations}}{{.Method}} {{.Path}}\n{{with .RequestBody}}{{template \"content\" index .Content 0}}{{end}}{{end}}",
+		},
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if got := string(files[0].Data); got != "Things: things.md health.md\n" {
+		t.Errorf("Unexpected index: %q", got)
+	}
+	things := string(files[1].Data)
+	if !strings.HasPrefix(things, "POST /things\n") || !strings.Contains(things, "| [].owner | Owner | no |") {
+		t.Errorf("Unexpected tag file:\n%s", things)
+	}
+	if strings.Contains(things, "[].owner.name") {
+		t.Errorf("Nested properties are deeper than the depth:\n%s", things)
+	}
+	if _, err := GenerateMarkdownWithOptions(d, &MarkdownOptions{Templates: map[string]string{"tag": "{{.Missing"}}); err == nil {
+		t.Errorf("Invalid template was accepted")
+	}
+}
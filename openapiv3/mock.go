@@ -0,0 +1,370 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NewMockHandler returns an http.Handler that answers requests for the
+// operations of a document with mock responses. The status and body of
+// a response are taken from the first 2xx response of the operation,
+// or its default response, and the first media type of that response.
+// The body is the example of the media type, the first of its examples,
+// the example of its schema, or a value that is generated from the schema.
+// Paths are matched with and without the paths of the document's servers.
+// Requests for undefined paths get 404 responses and requests with
+// undefined methods get 405 responses.
+func NewMockHandler(d *Document) http.Handler {
+	h := &mockHandler{document: d, basePaths: []string{""}}
+	for _, server := range d.GetServers() {
+		if path := serverPath(server); path != "" {
+			h.basePaths = append(h.basePaths, path)
+		}
+	}
+	routes := make(map[string]*mockRoute)
+	ForEachOperation(d, func(path, method string, item *PathItem, operation *Operation) {
+		route := routes[path]
+		if route == nil {
+			route = newMockRoute(path)
+			routes[path] = route
+			h.routes = append(h.routes, route)
+		}
+		route.operations[strings.ToUpper(method)] = operation
+	})
+	// Paths with fewer parameters take precedence, so that /pets/mine
+	// is matched before /pets/{id}.
+	sort.SliceStable(h.routes, func(i, j int) bool {
+		return h.routes[i].parameters < h.routes[j].parameters
+	})
+	return h
+}
+
+type mockHandler struct {
+	document  *Document
+	basePaths []string
+	routes    []*mockRoute
+	// Generate values for requests instead of responses: values
+	// of requests have writeOnly properties and no readOnly ones.
+	request bool
+}
+
+// A mockRoute holds the operations of a path.
+type mockRoute struct {
+	pattern    *regexp.Regexp
+	parameters int
+	operations map[string]*Operation
+}
+
+var pathParameterRegex = regexp.MustCompile(`\{[^}]*\}`)
+
+func newMockRoute(path string) *mockRoute {
+	pattern := "^"
+	last := 0
+	matches := pathParameterRegex.FindAllStringIndex(path, -1)
+	for _, m := range matches {
+		pattern += regexp.QuoteMeta(path[last:m[0]]) + "[^/]+"
+		last = m[1]
+	}
+	pattern += regexp.QuoteMeta(path[last:]) + "$"
+	return &mockRoute{
+		pattern:    regexp.MustCompile(pattern),
+		parameters: len(matches),
+		operations: make(map[string]*Operation),
+	}
+}
+
+// serverURL returns the URL of a server with the defaults of its variables.
+// URLs that can't be parsed are returned unchanged.
+func serverURL(server *Server) string {
+	u, err := ParseServerURL(server.GetUrl())
+	if err != nil {
+		return server.GetUrl()
+	}
+	return u.Expand(serverVariableDefaults(server))
+}
+
+// serverPath returns the path of a server URL without a trailing slash.
+// Server variables are replaced with their defaults.
+func serverPath(server *Server) string {
+	parsed, err := url.Parse(serverURL(server))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(parsed.Path, "/")
+}
+
+func (h *mockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, basePath := range h.basePaths {
+		if !strings.HasPrefix(r.URL.Path, basePath) {
+			continue
+		}
+		path := strings.TrimPrefix(r.URL.Path, basePath)
+		for _, route := range h.routes {
+			if !route.pattern.MatchString(path) {
+				continue
+			}
+			operation := route.operations[r.Method]
+			if operation == nil && r.Method == http.MethodHead {
+				operation = route.operations[http.MethodGet]
+			}
+			if operation == nil {
+				methods := make([]string, 0, len(route.operations))
+				for method := range route.operations {
+					methods = append(methods, method)
+				}
+				sort.Strings(methods)
+				w.Header().Set("Allow", strings.Join(methods, ", "))
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			h.respond(w, operation)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// respond writes the mock response of an operation.
+func (h *mockHandler) respond(w http.ResponseWriter, operation *Operation) {
+	status, response := h.mockResponse(operation)
+	content := response.GetContent().GetAdditionalProperties()
+	if len(content) == 0 {
+		w.WriteHeader(status)
+		return
+	}
+	mediaType := content[0].Name
+	value := h.mediaTypeValue(content[0].Value)
+	var body []byte
+	if s, ok := value.(string); ok && !strings.Contains(mediaType, "json") {
+		body = []byte(s)
+	} else {
+		var err error
+		if body, err = json.MarshalIndent(value, "", "  "); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		body = append(body, '\n')
+		if strings.Contains(mediaType, "*") {
+			mediaType = "application/json"
+		}
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// mockResponse returns the response of an operation that is used as mock
+// and its status code. Range codes like 2XX are answered with x00 and
+// default responses with 200.
+func (h *mockHandler) mockResponse(operation *Operation) (int, *Response) {
+	responses := operation.GetResponses()
+	for _, pair := range responses.GetResponseOrReference() {
+		if strings.HasPrefix(pair.Name, "2") {
+			status, err := strconv.Atoi(strings.Replace(strings.ToUpper(pair.Name), "XX", "00", 1))
+			if err != nil {
+				status = http.StatusOK
+			}
+			return status, h.response(pair.Value)
+		}
+	}
+	if responses.GetDefault() != nil {
+		return http.StatusOK, h.response(responses.GetDefault())
+	}
+	return http.StatusOK, nil
+}
+
+// response resolves references to response components.
+func (h *mockHandler) response(r *ResponseOrReference) *Response {
+	seen := make(map[string]bool)
+	for r != nil && r.GetResponse() == nil {
+		ref := r.GetReference().GetXRef()
+		if seen[ref] {
+			return nil
+		}
+		seen[ref] = true
+		r = nil
+		for _, pair := range h.document.GetComponents().GetResponses().GetAdditionalProperties() {
+			if ref == "#/components/responses/"+escapeJSONPointer(pair.Name) {
+				r = pair.Value
+			}
+		}
+	}
+	return r.GetResponse()
+}
+
+// mediaTypeValue returns the mock value of a media type.
+func (h *mockHandler) mediaTypeValue(mediaType *MediaType) interface{} {
+	if mediaType.GetExample() != nil {
+		return anyValue(mediaType.GetExample())
+	}
+	for _, pair := range mediaType.GetExamples().GetAdditionalProperties() {
+		example := pair.Value.GetExample()
+		if ref := pair.Value.GetReference().GetXRef(); ref != "" {
+			example, _ = (&exampleResolver{document: h.document}).exampleForRef(ref)
+		}
+		if value := ExampleValue(example); value != nil {
+			return anyValue(value)
+		}
+	}
+	return h.schemaValue(mediaType.GetSchema(), make(map[string]bool))
+}
+
+// schemaValue returns the example, default, or first enum value of a
+// schema, or else a value of its type. References to schema components
+// are resolved; recursive references produce nil values. Generated
+// objects omit writeOnly properties, or readOnly properties if h
+// generates request values.
+func (h *mockHandler) schemaValue(s *SchemaOrReference, seen map[string]bool) interface{} {
+	if ref := s.GetReference().GetXRef(); ref != "" {
+		if seen[ref] {
+			return nil
+		}
+		seen[ref] = true
+		defer delete(seen, ref)
+		for _, pair := range h.document.GetComponents().GetSchemas().GetAdditionalProperties() {
+			if ref == "#/components/schemas/"+escapeJSONPointer(pair.Name) {
+				return h.schemaValue(pair.Value, seen)
+			}
+		}
+		return nil
+	}
+	schema := s.GetSchema()
+	if schema == nil {
+		return nil
+	}
+	switch {
+	case schema.Example != nil:
+		return anyValue(schema.Example)
+	case schema.Default != nil:
+		switch v := schema.Default.Oneof.(type) {
+		case *DefaultType_Number:
+			return v.Number
+		case *DefaultType_Boolean:
+			return v.Boolean
+		case *DefaultType_String_:
+			return v.String_
+		}
+	case len(schema.Enum) > 0:
+		return anyValue(schema.Enum[0])
+	case len(schema.AllOf) > 0:
+		result := make(map[string]interface{})
+		for _, member := range schema.AllOf {
+			value, ok := h.schemaValue(member, seen).(map[string]interface{})
+			if !ok {
+				return h.schemaValue(member, seen)
+			}
+			for k, v := range value {
+				result[k] = v
+			}
+		}
+		return result
+	case len(schema.OneOf) > 0:
+		return h.schemaValue(schema.OneOf[0], seen)
+	case len(schema.AnyOf) > 0:
+		return h.schemaValue(schema.AnyOf[0], seen)
+	}
+	switch schema.Type {
+	case "array":
+		result := make([]interface{}, 0)
+		if items := schema.GetItems().GetSchemaOrReference(); len(items) > 0 {
+			result = append(result, h.schemaValue(items[0], seen))
+		}
+		return result
+	case "string":
+		return stringValue(schema.Format)
+	case "integer":
+		return int64(math.Ceil(numberValue(schema)))
+	case "number":
+		return numberValue(schema)
+	case "boolean":
+		return true
+	case "object", "":
+		if schema.Properties == nil && schema.Type == "" {
+			return nil
+		}
+		result := make(map[string]interface{})
+		for _, pair := range schema.GetProperties().GetAdditionalProperties() {
+			property := pair.Value.GetSchema()
+			if h.request && property.GetReadOnly() || !h.request && property.GetWriteOnly() {
+				continue
+			}
+			result[pair.Name] = h.schemaValue(pair.Value, seen)
+		}
+		return result
+	}
+	return nil
+}
+
+// stringValue returns a sample string in a given format.
+func stringValue(format string) string {
+	switch format {
+	case "date":
+		return "2023-01-01"
+	case "date-time":
+		return "2023-01-01T00:00:00Z"
+	case "time":
+		return "00:00:00"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	case "byte":
+		return "c3RyaW5n"
+	}
+	return "string"
+}
+
+// numberValue returns the smallest allowed value of a number schema
+// or zero if it is allowed.
+func numberValue(schema *Schema) float64 {
+	value := 0.0
+	if schema.Minimum > value || (schema.Minimum == value && schema.ExclusiveMinimum) {
+		value = schema.Minimum
+		if schema.ExclusiveMinimum {
+			value++
+		}
+	}
+	if schema.Maximum != 0 && schema.Maximum < value {
+		value = schema.Maximum
+	}
+	return value
+}
+
+// anyValue returns the value of an Any as a value that can be marshalled as JSON.
+func anyValue(a *Any) interface{} {
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(a.GetYaml()), &value); err != nil {
+		return nil
+	}
+	return value
+}
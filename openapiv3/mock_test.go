@@ -0,0 +1,181 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const mockDocument = `
+openapi: 3.0.0
+info:
+  title: Mocks
+  version: 1.0.0
+servers:
+  - url: https://{host}/api/{version}
+    variables:
+      host:
+        default: example.com
+      version:
+        default: v1
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      responses:
+        '201':
+          $ref: '#/components/responses/Created'
+  /pets/mine:
+    get:
+      responses:
+        default:
+          description: My pet
+          content:
+            application/json:
+              examples:
+                first:
+                  $ref: '#/components/examples/Rex'
+                second:
+                  value: {name: Fido}
+  /pets/{id}:
+    get:
+      responses:
+        '404':
+          description: Not found
+        '2XX':
+          description: A pet
+          content:
+            application/json:
+              example: {id: 7, name: Tom}
+    delete:
+      responses:
+        '204':
+          description: Deleted
+  /health:
+    get:
+      responses:
+        '200':
+          description: Health
+          content:
+            text/plain:
+              schema:
+                type: string
+                example: OK
+components:
+  examples:
+    Rex:
+      value: {name: Rex}
+  responses:
+    Created:
+      description: Created
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pet'
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+          minimum: 1
+        name:
+          type: string
+        born:
+          type: string
+          format: date
+        kind:
+          type: string
+          enum: [cat, dog]
+        weight:
+          type: number
+          default: 2.5
+        password:
+          type: string
+          writeOnly: true
+        parent:
+          $ref: '#/components/schemas/Pet'
+`
+
+func TestMockHandler(t *testing.T) {
+	d, err := ParseDocument([]byte(mockDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	server := httptest.NewServer(NewMockHandler(d))
+	defer server.Close()
+	pet := `{
+  "born": "2023-01-01",
+  "id": 1,
+  "kind": "cat",
+  "name": "string",
+  "parent": null,
+  "weight": 2.5
+}
+`
+	for _, test := range []struct {
+		method      string
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"GET", "/pets", 200, "application/json", "[\n" + strings.Replace("  "+strings.TrimSuffix(pet, "\n"), "\n", "\n  ", -1) + "\n]\n"},
+		{"POST", "/api/v1/pets", 201, "application/json", pet},
+		{"GET", "/pets/mine", 200, "application/json", "{\n  \"name\": \"Rex\"\n}\n"},
+		{"GET", "/pets/12", 200, "application/json", "{\n  \"id\": 7,\n  \"name\": \"Tom\"\n}\n"},
+		{"DELETE", "/pets/12", 204, "", ""},
+		{"PUT", "/pets/12", 405, "text/plain; charset=utf-8", "method not allowed\n"},
+		{"GET", "/health", 200, "text/plain", "OK"},
+		{"GET", "/owners", 404, "text/plain; charset=utf-8", "404 page not found\n"},
+	} {
+		request, err := http.NewRequest(test.method, server.URL+test.path, nil)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		body, _ := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		name := test.method + " " + test.path
+		if response.StatusCode != test.status {
+			t.Errorf("%s: unexpected status %d", name, response.StatusCode)
+		}
+		if contentType := response.Header.Get("Content-Type"); contentType != test.contentType {
+			t.Errorf("%s: unexpected content type %q", name, contentType)
+		}
+		if string(body) != test.body {
+			t.Errorf("%s: unexpected body:\n%s", name, body)
+		}
+		if test.status == 405 && response.Header.Get("Allow") != "DELETE, GET" {
+			t.Errorf("%s: unexpected Allow header %q", name, response.Header.Get("Allow"))
+		}
+	}
+}
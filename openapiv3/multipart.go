@@ -0,0 +1,94 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import "strings"
+
+// A MultipartPart is a property of a multipart request body that is
+// sent as a part of the body.
+type MultipartPart struct {
+	Name        string             // the name of the property and of the part
+	ContentType string             // the allowed content types, comma-separated
+	Required    bool               // true if the part must be present
+	Headers     []string           // the names of headers that the part can have
+	Schema      *SchemaOrReference // the schema of the property
+}
+
+// IsMultipart returns true for multipart media types like multipart/form-data.
+func IsMultipart(mediaType string) bool {
+	return strings.HasPrefix(strings.ToLower(mediaType), "multipart/")
+}
+
+// MultipartParts returns the parts of the body of a multipart media type,
+// one for each property of its schema, in the order of the properties.
+// Content types and headers are taken from the encoding of the media
+// type. Without an encoding, parts of objects are sent as
+// application/json, strings with binary or base64 formats as
+// application/octet-stream, and other values as text/plain; arrays are
+// sent as repeated parts with the content type of their items.
+func MultipartParts(d *Document, mediaType *MediaType) []*MultipartPart {
+	schema := dereferenceSchema(d, mediaType.GetSchema())
+	required := make(map[string]bool)
+	for _, name := range schema.GetRequired() {
+		required[name] = true
+	}
+	var parts []*MultipartPart
+	for _, pair := range schema.GetProperties().GetAdditionalProperties() {
+		part := &MultipartPart{Name: pair.Name, Required: required[pair.Name], Schema: pair.Value}
+		for _, encoding := range mediaType.GetEncoding().GetAdditionalProperties() {
+			if encoding.Name != pair.Name {
+				continue
+			}
+			part.ContentType = encoding.GetValue().GetContentType()
+			for _, header := range encoding.GetValue().GetHeaders().GetAdditionalProperties() {
+				part.Headers = append(part.Headers, header.Name)
+			}
+		}
+		if part.ContentType == "" {
+			part.ContentType = defaultPartContentType(d, pair.Value)
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// defaultPartContentType returns the content type that a property of a
+// multipart request body has if its encoding doesn't specify one.
+func defaultPartContentType(d *Document, s *SchemaOrReference) string {
+	schema := dereferenceSchema(d, s)
+	// Arrays have the content type of their items.
+	seen := make(map[*Schema]bool)
+	for schema.GetType() == "array" && !seen[schema] {
+		seen[schema] = true
+		items := schema.GetItems().GetSchemaOrReference()
+		if len(items) == 0 {
+			break
+		}
+		schema = dereferenceSchema(d, items[0])
+	}
+	switch schema.GetType() {
+	case "object":
+		return "application/json"
+	case "string":
+		if schema.Format == "binary" || schema.Format == "base64" {
+			return "application/octet-stream"
+		}
+	case "":
+		if schema.GetProperties() != nil || len(schema.GetAllOf()) > 0 {
+			return "application/json"
+		}
+	}
+	return "text/plain"
+}
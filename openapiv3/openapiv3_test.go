@@ -17,6 +17,9 @@ package openapi_v3
 import (
 	"io/ioutil"
 	"testing"
+
+	"github.com/google/gnostic/compiler"
+	"gopkg.in/yaml.v3"
 )
 
 func TestParseDocument(t *testing.T) {
@@ -61,3 +64,39 @@ func TestParseDocument_Empty(t *testing.T) {
 		})
 	}
 }
+
+func TestNewDocumentWithoutValidation(t *testing.T) {
+	var node yaml.Node
+	err := yaml.Unmarshal([]byte(`
+openapi: 3.0.0
+info:
+  title: Pets
+  summary: Unknown in OpenAPI 3.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - $ref: "#/components/parameters/limit"
+`), &node)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	root := node.Content[0]
+	if _, err := NewDocument(root, compiler.NewContext("$root", root, nil)); err == nil {
+		t.Fatalf("expected errors for a document without info.version")
+	}
+	context := compiler.WithValidation(compiler.NewContext("$root", root, nil), false)
+	defer compiler.ReleaseUserData(context)
+	d, err := NewDocument(root, context)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if d.Info.Title != "Pets" {
+		t.Errorf("unexpected value for Title: %s", d.Info.Title)
+	}
+	// Key checks still decide which possibility of a oneof matches.
+	parameter := d.Paths.Path[0].Value.Get.Parameters[0]
+	if parameter.GetReference() == nil {
+		t.Errorf("the parameter was not compiled as a reference: %+v", parameter)
+	}
+}
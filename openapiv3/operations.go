@@ -0,0 +1,42 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+// ForEachOperation calls f for each operation of a document with its path,
+// its lowercase method, and the path item that contains it. Operations are
+// visited in the order of their paths and, within a path item, in the
+// order get, put, post, delete, options, head, patch, trace.
+func ForEachOperation(d *Document, f func(path, method string, item *PathItem, operation *Operation)) {
+	if d == nil || d.Paths == nil {
+		return
+	}
+	for _, pair := range d.Paths.Path {
+		item := pair.Value
+		if item == nil {
+			continue
+		}
+		for _, o := range []struct {
+			method    string
+			operation *Operation
+		}{
+			{"get", item.Get}, {"put", item.Put}, {"post", item.Post}, {"delete", item.Delete},
+			{"options", item.Options}, {"head", item.Head}, {"patch", item.Patch}, {"trace", item.Trace},
+		} {
+			if o.operation != nil {
+				f(pair.Name, o.method, item, o.operation)
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// SourceExtensionName is the name of the extension that records
+// the source URL and original JSON pointer of a component.
+const SourceExtensionName = "x-gnostic-source"
+
+// Source describes where a component was originally defined.
+type Source struct {
+	URL     string `yaml:"url"`
+	Pointer string `yaml:"pointer"`
+}
+
+// AnnotateSources adds an x-gnostic-source extension to each component of
+// a document that records the source it was read from and its pointer in
+// that source. Components that already carry an x-gnostic-source extension
+// keep it, so provenance survives repeated round trips through gnostic.
+func AnnotateSources(d *Document, url string) {
+	if d == nil || d.Components == nil {
+		return
+	}
+	forEachAnnotatedComponent(d.Components, func(obj Extensible, section, name string) {
+		if _, ok := GetExtension(obj, SourceExtensionName); ok {
+			return
+		}
+		SetExtension(obj, SourceExtensionName, &Source{URL: url, Pointer: "#/components/" + section + "/" + escapeJSONPointer(name)})
+	})
+}
+
+// SourceForKeys returns the original location of the value at keys in a
+// document, such as "common.yaml#/components/schemas/Pet/properties/name",
+// if the value belongs to a component with an x-gnostic-source extension.
+// It returns "" for other values.
+func SourceForKeys(d *Document, keys []string) string {
+	if d == nil || d.Components == nil || len(keys) < 3 || keys[0] != "components" {
+		return ""
+	}
+	var source *Source
+	forEachAnnotatedComponent(d.Components, func(obj Extensible, section, name string) {
+		if section == keys[1] && name == keys[2] {
+			source = SourceForExtensions(obj.GetSpecificationExtension())
+		}
+	})
+	if source == nil {
+		return ""
+	}
+	location := source.URL + source.Pointer
+	for _, key := range keys[3:] {
+		location += "/" + escapeJSONPointer(key)
+	}
+	return location
+}
+
+// forEachAnnotatedComponent calls f for each component of a kind that
+// AnnotateSources annotates.
+func forEachAnnotatedComponent(c *Components, f func(obj Extensible, section, name string)) {
+	if c.Schemas != nil {
+		for _, pair := range c.Schemas.AdditionalProperties {
+			if s := pair.Value.GetSchema(); s != nil {
+				f(s, "schemas", pair.Name)
+			}
+		}
+	}
+	if c.Responses != nil {
+		for _, pair := range c.Responses.AdditionalProperties {
+			if r := pair.Value.GetResponse(); r != nil {
+				f(r, "responses", pair.Name)
+			}
+		}
+	}
+	if c.Parameters != nil {
+		for _, pair := range c.Parameters.AdditionalProperties {
+			if p := pair.Value.GetParameter(); p != nil {
+				f(p, "parameters", pair.Name)
+			}
+		}
+	}
+	if c.RequestBodies != nil {
+		for _, pair := range c.RequestBodies.AdditionalProperties {
+			if r := pair.Value.GetRequestBody(); r != nil {
+				f(r, "requestBodies", pair.Name)
+			}
+		}
+	}
+	if c.Headers != nil {
+		for _, pair := range c.Headers.AdditionalProperties {
+			if h := pair.Value.GetHeader(); h != nil {
+				f(h, "headers", pair.Name)
+			}
+		}
+	}
+}
+
+// SourceForExtensions returns the source recorded in a list of
+// specification extensions, or nil if none was recorded.
+func SourceForExtensions(extensions []*NamedAny) *Source {
+	for _, extension := range extensions {
+		if extension.Name != SourceExtensionName || extension.Value == nil {
+			continue
+		}
+		var source Source
+		if err := yaml.Unmarshal([]byte(extension.Value.Yaml), &source); err != nil {
+			return nil
+		}
+		return &source
+	}
+	return nil
+}
+
+// escapeJSONPointer escapes a reference token as described in RFC 6901.
+func escapeJSONPointer(token string) string {
+	return compiler.EscapeJSONPointer(token)
+}
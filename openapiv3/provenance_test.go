@@ -0,0 +1,76 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestAnnotateSources(t *testing.T) {
+	filename := "../examples/v3.0/yaml/petstore.yaml"
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("unable to read file %s", filename)
+	}
+	d, err := ParseDocument(b)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	AnnotateSources(d, filename)
+
+	// Provenance must survive a round trip, even if the result is annotated again.
+	b, err = d.YAMLValue("")
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	d, err = ParseDocument(b)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	AnnotateSources(d, "bundle.yaml")
+
+	pet := schemaNamed(t, d, "Pet")
+	source := SourceForExtensions(pet.SpecificationExtension)
+	if source == nil {
+		t.Fatalf("missing %s for Pet", SourceExtensionName)
+	}
+	if source.URL != filename || source.Pointer != "#/components/schemas/Pet" {
+		t.Errorf("unexpected source for Pet: %+v", source)
+	}
+	count := 0
+	for _, e := range pet.SpecificationExtension {
+		if e.Name == SourceExtensionName {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("unexpected number of %s extensions: %d (expected 1)", SourceExtensionName, count)
+	}
+
+	for _, test := range []struct {
+		keys     []string
+		expected string
+	}{
+		{[]string{"components", "schemas", "Pet", "properties", "name"}, filename + "#/components/schemas/Pet/properties/name"},
+		{[]string{"components", "schemas", "Pet"}, filename + "#/components/schemas/Pet"},
+		{[]string{"components", "schemas", "Missing"}, ""},
+		{[]string{"paths", "/pets", "get"}, ""},
+	} {
+		if got := SourceForKeys(d, test.keys); got != test.expected {
+			t.Errorf("unexpected source for %v: %q (expected %q)", test.keys, got, test.expected)
+		}
+	}
+}
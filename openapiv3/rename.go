@@ -0,0 +1,158 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// A ComponentRename renames a component of a document.
+type ComponentRename struct {
+	// Section is the components section of the component, such as
+	// "schemas", "parameters", "responses" or "securitySchemes".
+	Section string
+	From    string
+	To      string
+}
+
+func (r *ComponentRename) String() string {
+	return r.Section + "/" + r.From + "=" + r.To
+}
+
+// RenameComponents renames components of a document and rewrites the
+// references to them in paths and in other components, the mappings of
+// discriminators, the operation references of links, and the names of
+// security schemes in security requirements. Renames are applied in
+// order. It is an error to rename a component that doesn't exist, to use
+// the name of an existing component, or to rename a component that an
+// earlier rename created: A=B,B=C fails because the renames chain, while
+// B=C,A=B first moves B to C and then A to B. If there are errors, the
+// document is not changed.
+func RenameComponents(d *Document, renames []*ComponentRename) error {
+	var messages []string
+	names := make(map[string]map[string]bool)
+	created := make(map[string]bool)
+	for _, r := range renames {
+		field := (&Components{}).ProtoReflect().Descriptor().Fields().ByJSONName(r.Section)
+		if field == nil || field.IsList() || field.Message() == nil {
+			messages = append(messages, fmt.Sprintf("%s: unknown components section %s", r, r.Section))
+			continue
+		}
+		if names[r.Section] == nil {
+			names[r.Section] = make(map[string]bool)
+			if d.GetComponents() != nil {
+				pairs := componentPairs(d.GetComponents().ProtoReflect().Get(field).Message())
+				for i := 0; i < pairs.Len(); i++ {
+					names[r.Section][pairName(pairs.Get(i).Message())] = true
+				}
+			}
+		}
+		from := componentPointer(r.Section, r.From)
+		to := componentPointer(r.Section, r.To)
+		switch {
+		case created[from]:
+			messages = append(messages, fmt.Sprintf("%s: %s is the result of an earlier rename", r, from))
+		case !names[r.Section][r.From]:
+			messages = append(messages, fmt.Sprintf("%s: %s does not exist", r, from))
+		case names[r.Section][r.To]:
+			messages = append(messages, fmt.Sprintf("%s: %s already exists", r, to))
+		default:
+			delete(names[r.Section], r.From)
+			names[r.Section][r.To] = true
+			created[to] = true
+		}
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("%s", strings.Join(messages, "\n"))
+	}
+	for _, r := range renames {
+		renameComponent(d, r)
+	}
+	return nil
+}
+
+func componentPointer(section, name string) string {
+	return "#/components/" + section + "/" + escapeJSONPointer(name)
+}
+
+// renameComponent renames an existing component and rewrites the
+// references to it.
+func renameComponent(d *Document, r *ComponentRename) {
+	field := d.Components.ProtoReflect().Descriptor().Fields().ByJSONName(r.Section)
+	pair := findPair(componentPairs(d.Components.ProtoReflect().Get(field).Message()), r.From)
+	pair.Set(pair.Descriptor().Fields().ByName("name"), protoreflect.ValueOfString(r.To))
+
+	from := componentPointer(r.Section, r.From)
+	to := componentPointer(r.Section, r.To)
+	rewrite := func(ref string) string {
+		if componentForRef(ref) == from {
+			return to + strings.TrimPrefix(ref, from)
+		}
+		return ref
+	}
+	visitMessages(d.ProtoReflect(), func(m protoreflect.Message) {
+		switch v := m.Interface().(type) {
+		case *Reference:
+			v.XRef = rewrite(v.XRef)
+		case *PathItem:
+			v.XRef = rewrite(v.XRef)
+		case *Link:
+			v.OperationRef = rewrite(v.OperationRef)
+		case *Discriminator:
+			if r.Section != "schemas" {
+				return
+			}
+			// Mappings can use schema names or references.
+			for _, mapping := range v.GetMapping().GetAdditionalProperties() {
+				if mapping.Value == r.From {
+					mapping.Value = r.To
+				} else {
+					mapping.Value = rewrite(mapping.Value)
+				}
+			}
+		case *SecurityRequirement:
+			if r.Section != "securitySchemes" {
+				return
+			}
+			for _, requirement := range v.AdditionalProperties {
+				if requirement.Name == r.From {
+					requirement.Name = r.To
+				}
+			}
+		}
+	})
+}
+
+// visitMessages calls f for a message and each message that it contains.
+func visitMessages(m protoreflect.Message, f func(m protoreflect.Message)) {
+	f(m)
+	m.Range(func(field protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if field.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		if field.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				visitMessages(list.Get(i).Message(), f)
+			}
+		} else {
+			visitMessages(v.Message(), f)
+		}
+		return true
+	})
+}
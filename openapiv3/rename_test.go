@@ -0,0 +1,217 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+const renameDocument = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+security:
+- apiKey: []
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      security:
+      - apiKey: []
+        oauth: [read]
+      parameters:
+      - $ref: '#/components/parameters/Limit'
+      responses:
+        '200':
+          description: Pets.
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+          links:
+            first:
+              operationRef: '#/components/callbacks/Created/{$request.body#/url}/post'
+        default:
+          $ref: '#/components/responses/Error'
+components:
+  schemas:
+    Pet:
+      oneOf:
+      - $ref: '#/components/schemas/Cat'
+      - $ref: '#/components/schemas/Dog'
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: Cat
+          dog: '#/components/schemas/Dog'
+    Cat:
+      type: object
+      properties:
+        name:
+          $ref: '#/components/schemas/Dog/properties/name'
+    Dog:
+      type: object
+      properties:
+        name:
+          type: string
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+  responses:
+    Error:
+      description: An error.
+  callbacks:
+    Created:
+      '{$request.body#/url}':
+        post:
+          requestBody:
+            content:
+              application/json:
+                schema:
+                  $ref: '#/components/schemas/Dog'
+          responses:
+            '200':
+              description: OK.
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: key
+      in: header
+    oauth:
+      type: oauth2
+      flows:
+        implicit:
+          authorizationUrl: https://example.com/auth
+          scopes:
+            read: Read access.
+`
+
+func renameYAML(t *testing.T, d *Document) string {
+	b, err := yaml.Marshal(d.ToRawInfo())
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return string(b)
+}
+
+func TestRenameComponents(t *testing.T) {
+	d, err := ParseDocument([]byte(renameDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	err = RenameComponents(d, []*ComponentRename{
+		{Section: "schemas", From: "Dog", To: "Hound"},
+		{Section: "schemas", From: "Pet", To: "Animal"},
+		{Section: "schemas", From: "Cat", To: "Pet"},
+		{Section: "parameters", From: "Limit", To: "PageSize"},
+		{Section: "responses", From: "Error", To: "Problem"},
+		{Section: "callbacks", From: "Created", To: "OnCreate"},
+		{Section: "securitySchemes", From: "apiKey", To: "key"},
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	text := renameYAML(t, d)
+	for _, want := range []string{
+		"$ref: '#/components/schemas/Animal'",
+		"$ref: '#/components/schemas/Pet'",
+		"$ref: '#/components/schemas/Hound'",
+		"$ref: '#/components/schemas/Hound/properties/name'",
+		"$ref: '#/components/parameters/PageSize'",
+		"$ref: '#/components/responses/Problem'",
+		"operationRef: '#/components/callbacks/OnCreate/{$request.body#/url}/post'",
+		"- key: []",
+		"    Problem:",
+		"    key:",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %s in\n%s", want, text)
+		}
+	}
+	for _, unwanted := range []string{"Dog", "Cat", "Limit", "Error", "Created", "apiKey:"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("unexpected %s in\n%s", unwanted, text)
+		}
+	}
+	// Discriminator mappings are not included in YAML, so they are checked here.
+	animal, err := Dereference(d, "#/components/schemas/Animal")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	mapping := make(map[string]string)
+	for _, pair := range animal.(*Schema).GetDiscriminator().GetMapping().GetAdditionalProperties() {
+		mapping[pair.Name] = pair.Value
+	}
+	if mapping["cat"] != "Pet" || mapping["dog"] != "#/components/schemas/Hound" {
+		t.Errorf("unexpected discriminator mapping %v", mapping)
+	}
+}
+
+func TestRenameComponentsErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		renames []*ComponentRename
+		message string
+	}{
+		{
+			name:    "missing component",
+			renames: []*ComponentRename{{Section: "schemas", From: "Bird", To: "Parrot"}},
+			message: "#/components/schemas/Bird does not exist",
+		},
+		{
+			name:    "existing target",
+			renames: []*ComponentRename{{Section: "schemas", From: "Cat", To: "Dog"}},
+			message: "#/components/schemas/Dog already exists",
+		},
+		{
+			name: "chain",
+			renames: []*ComponentRename{
+				{Section: "schemas", From: "Cat", To: "Kitten"},
+				{Section: "schemas", From: "Kitten", To: "Cub"},
+			},
+			message: "#/components/schemas/Kitten is the result of an earlier rename",
+		},
+		{
+			name:    "unknown section",
+			renames: []*ComponentRename{{Section: "things", From: "Cat", To: "Kitten"}},
+			message: "unknown components section things",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseDocument([]byte(renameDocument))
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			original := proto.Clone(d)
+			err = RenameComponents(d, append([]*ComponentRename{{Section: "schemas", From: "Pet", To: "Animal"}}, tt.renames...))
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("expected an error with %q, got %v", tt.message, err)
+			}
+			if !proto.Equal(d, original) {
+				t.Errorf("document changed after an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,416 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+// ResolveReport describes the changes made by ResolveReferencesSmart.
+type ResolveReport struct {
+	// Inlined is the number of references that were replaced by their targets.
+	Inlined int
+	// Components is the number of schemas that were added to components
+	// because they are used more than once.
+	Components int
+	// SizeBefore and SizeAfter are the sizes of the YAML representation
+	// of the document before and after references were resolved.
+	SizeBefore int
+	SizeAfter  int
+}
+
+// String returns a one-line summary of a report.
+func (r *ResolveReport) String() string {
+	return fmt.Sprintf("%d references inlined, %d schemas moved to components, size %d -> %d bytes (%+d)",
+		r.Inlined, r.Components, r.SizeBefore, r.SizeAfter, r.SizeAfter-r.SizeBefore)
+}
+
+// PromoteSchemaAliases replaces the YAML aliases of schemas in the root
+// node of an OpenAPI v3 description with references to schema components,
+// so that the schemas aren't copied when aliases are expanded. Schemas
+// whose anchors are in schema positions are moved to components, other
+// anchored schemas, such as those in extensions, are copied to components
+// if they are aliased more than once. Components are named after their
+// anchors. Other aliases are left for compiler.ExpandAliases.
+// It returns the number of components that were added.
+func PromoteSchemaAliases(root *yaml.Node) int {
+	type anchor struct {
+		node     *yaml.Node
+		parent   *yaml.Node // the node that contains a schema anchor
+		index    int
+		name     string // the name of a schema component
+		inSchema bool
+		aliases  []func(ref string)
+	}
+	anchors := make(map[*yaml.Node]*anchor)
+	var order []*anchor
+	anchorFor := func(node *yaml.Node) *anchor {
+		a, ok := anchors[node]
+		if !ok {
+			a = &anchor{node: node}
+			anchors[node] = a
+			order = append(order, a)
+		}
+		return a
+	}
+	schemas := compiler.MapValueForKey(compiler.MapValueForKey(root, "components"), "schemas")
+	var visit func(parent *yaml.Node, index int, inSchema bool)
+	visitSchema := func(parent *yaml.Node, index int) { visit(parent, index, true) }
+	visit = func(parent *yaml.Node, index int, inSchema bool) {
+		node := parent.Content[index]
+		if node.Kind == yaml.AliasNode {
+			if inSchema && node.Alias != nil && node.Alias.Kind == yaml.MappingNode {
+				a := anchorFor(node.Alias)
+				a.aliases = append(a.aliases, func(ref string) { parent.Content[index] = refNode(ref) })
+			}
+			return
+		}
+		if node.Anchor != "" {
+			a := anchorFor(node)
+			if inSchema {
+				a.parent, a.index, a.inSchema = parent, index, true
+				if parent == schemas && index%2 == 1 {
+					a.name = parent.Content[index-1].Value
+				}
+			}
+		}
+		switch node.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for i := range node.Content {
+				visit(node, i, false)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i].Value
+				switch {
+				case node == schemas:
+					visitSchema(node, i+1)
+				case inSchema && (key == "properties" || key == "patternProperties"):
+					if properties := node.Content[i+1]; properties.Kind == yaml.MappingNode {
+						for j := 1; j < len(properties.Content); j += 2 {
+							visitSchema(properties, j)
+						}
+					}
+				case inSchema && (key == "allOf" || key == "anyOf" || key == "oneOf"):
+					if list := node.Content[i+1]; list.Kind == yaml.SequenceNode {
+						for j := range list.Content {
+							visitSchema(list, j)
+						}
+					}
+				case inSchema && (key == "items" || key == "not" || key == "additionalProperties"):
+					visitSchema(node, i+1)
+				case inSchema:
+					// Other keywords of schemas don't contain schemas.
+				case key == "schema":
+					visitSchema(node, i+1)
+				case key == "example" || key == "examples" || strings.HasPrefix(key, "x-"):
+					// Values and extensions don't contain schemas. Anchors
+					// in them are found through their aliases.
+				default:
+					visit(node, i+1, false)
+				}
+			}
+		}
+	}
+	visit(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}, 0, false)
+
+	count := 0
+	for _, a := range order {
+		uses := len(a.aliases)
+		if a.inSchema {
+			uses++
+		}
+		if len(a.aliases) == 0 || uses < 2 {
+			continue
+		}
+		if a.name == "" {
+			if schemas == nil {
+				components := compiler.MapValueForKey(root, "components")
+				if components == nil {
+					components = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+					root.Content = append(root.Content, stringNode("components"), components)
+				}
+				schemas = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				components.Content = append(components.Content, stringNode("schemas"), schemas)
+			}
+			a.name = uniqueComponentName(schemas, a.node.Anchor)
+			value := a.node
+			if a.inSchema {
+				a.parent.Content[a.index] = refNode("#/components/schemas/" + escapeJSONPointer(a.name))
+			} else {
+				value = &yaml.Node{Kind: yaml.AliasNode, Alias: a.node}
+			}
+			schemas.Content = append(schemas.Content, stringNode(a.name), value)
+			count++
+		}
+		for _, replace := range a.aliases {
+			replace("#/components/schemas/" + escapeJSONPointer(a.name))
+		}
+	}
+	return count
+}
+
+// uniqueComponentName returns a valid name based on a name that isn't
+// used in a mapping of components.
+func uniqueComponentName(components *yaml.Node, name string) string {
+	name = invalidComponentNameCharacters.ReplaceAllString(name, "_")
+	for i := 1; ; i++ {
+		candidate := name
+		if i > 1 {
+			candidate = fmt.Sprintf("%s%d", name, i)
+		}
+		if compiler.MapValueForKey(components, candidate) == nil {
+			return candidate
+		}
+	}
+}
+
+func refNode(ref string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{stringNode("$ref"), stringNode(ref)}}
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// ResolveReferencesSmart resolves the schema references of a document
+// without copying schemas. Schemas in other files that are referenced once
+// are inlined, and those that are referenced more than once are added to
+// the schema components of the document and referenced there. Then schema
+// components that are referenced exactly once, not from themselves, are
+// inlined and removed. References are resolved relative to root, the name
+// of the file of the document. Other references are left unchanged.
+func ResolveReferencesSmart(d *Document, root string) (*ResolveReport, error) {
+	report := &ResolveReport{SizeBefore: yamlSize(d)}
+	if err := resolveExternalSchemas(d, root, report); err != nil {
+		return nil, err
+	}
+	inlineSingleUseSchemas(d, report)
+	report.SizeAfter = yamlSize(d)
+	return report, nil
+}
+
+func yamlSize(d *Document) int {
+	b, _ := d.YAMLValue("")
+	return len(b)
+}
+
+// schemaReference is a reference to a schema and the name of the schema
+// component that contains it, if any.
+type schemaReference struct {
+	site  *SchemaOrReference
+	owner string
+}
+
+// schemaReferences returns the schema references of a document.
+func schemaReferences(d *Document) []*schemaReference {
+	references := make([]*schemaReference, 0)
+	var visit func(m protoreflect.Message, owner string)
+	visit = func(m protoreflect.Message, owner string) {
+		if s, ok := m.Interface().(*SchemaOrReference); ok && s.GetReference() != nil {
+			references = append(references, &schemaReference{site: s, owner: owner})
+			return
+		}
+		if schemas, ok := m.Interface().(*SchemasOrReferences); ok && schemas == d.GetComponents().GetSchemas() {
+			for _, pair := range schemas.AdditionalProperties {
+				visit(pair.Value.ProtoReflect(), pair.Name)
+			}
+			return
+		}
+		m.Range(func(field protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			if field.Kind() != protoreflect.MessageKind {
+				return true
+			}
+			if field.IsList() {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					visit(list.Get(i).Message(), owner)
+				}
+			} else {
+				visit(v.Message(), owner)
+			}
+			return true
+		})
+	}
+	visit(d.ProtoReflect(), "")
+	return references
+}
+
+// resolveExternalSchemas replaces references to schemas in other files.
+func resolveExternalSchemas(d *Document, root string, report *ResolveReport) error {
+	// Targets that were inlined before become components when they are
+	// referenced again, e.g. from a schema that refers to itself.
+	inlined := make(map[string]bool)
+	for {
+		sites := make(map[string][]*SchemaOrReference)
+		var refs []string
+		for _, r := range schemaReferences(d) {
+			ref := r.site.GetReference().XRef
+			if strings.HasPrefix(ref, "#") {
+				continue
+			}
+			if _, ok := sites[ref]; !ok {
+				refs = append(refs, ref)
+			}
+			sites[ref] = append(sites[ref], r.site)
+		}
+		if len(refs) == 0 {
+			return nil
+		}
+		for _, ref := range refs {
+			info, err := compiler.ReadInfoForRef(root, ref)
+			if err != nil {
+				return err
+			}
+			schema, err := NewSchema(info, compiler.NewContext("$ref", info, nil))
+			if err != nil {
+				return err
+			}
+			file := strings.SplitN(ref, "#", 2)[0]
+			rewriteReferences(schema.ProtoReflect(), func(nested string) string {
+				return rebaseReference(file, nested)
+			})
+			if len(sites[ref]) == 1 && !inlined[ref] {
+				inlined[ref] = true
+				sites[ref][0].Oneof = &SchemaOrReference_Schema{Schema: schema}
+				report.Inlined++
+				continue
+			}
+			name := addSchemaComponent(d, schemaNameForRef(ref), schema)
+			for _, site := range sites[ref] {
+				site.GetReference().XRef = "#/components/schemas/" + escapeJSONPointer(name)
+			}
+			report.Components++
+		}
+	}
+}
+
+// rebaseReference changes a reference in a file to be relative to the
+// directory of the document.
+func rebaseReference(file, ref string) string {
+	if strings.HasPrefix(ref, "#") {
+		return file + ref
+	}
+	if u, err := url.Parse(ref); err == nil && u.IsAbs() {
+		return ref
+	}
+	if base, err := url.Parse(file); err == nil && base.IsAbs() {
+		if u, err := base.Parse(ref); err == nil {
+			return u.String()
+		}
+	}
+	return path.Join(path.Dir(file), ref)
+}
+
+// schemaNameForRef returns a name for the target of a reference, the last
+// segment of its JSON pointer or the name of its file.
+func schemaNameForRef(ref string) string {
+	parts := strings.SplitN(ref, "#", 2)
+	if len(parts) == 2 && parts[1] != "" && parts[1] != "/" {
+		segments := strings.Split(parts[1], "/")
+		return strings.Replace(strings.Replace(segments[len(segments)-1], "~1", "/", -1), "~0", "~", -1)
+	}
+	name := path.Base(parts[0])
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// addSchemaComponent adds a schema component with a name based on name,
+// unless a component with that name and schema exists. It returns the
+// name of the component.
+func addSchemaComponent(d *Document, name string, schema *Schema) string {
+	if d.Components == nil {
+		d.Components = &Components{}
+	}
+	if d.Components.Schemas == nil {
+		d.Components.Schemas = &SchemasOrReferences{}
+	}
+	c := &inlineComponent{name: name, value: schema}
+	return addComponent(d.Components, "schemas", c, false)
+}
+
+// inlineSingleUseSchemas replaces references to schema components that
+// are referenced once with the components, and removes the components.
+func inlineSingleUseSchemas(d *Document, report *ResolveReport) {
+	// Components that are named in discriminator mappings are always kept.
+	mapped := make(map[string]bool)
+	var visit func(m protoreflect.Message)
+	visit = func(m protoreflect.Message) {
+		if discriminator, ok := m.Interface().(*Discriminator); ok {
+			for _, pair := range discriminator.GetMapping().GetAdditionalProperties() {
+				if strings.HasPrefix(pair.Value, "#") {
+					mapped[componentForRef(pair.Value)] = true
+				} else {
+					mapped["#/components/schemas/"+escapeJSONPointer(pair.Value)] = true
+				}
+			}
+		}
+		m.Range(func(field protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			if field.Kind() == protoreflect.MessageKind {
+				if field.IsList() {
+					for i := 0; i < v.List().Len(); i++ {
+						visit(v.List().Get(i).Message())
+					}
+				} else {
+					visit(v.Message())
+				}
+			}
+			return true
+		})
+	}
+	visit(d.ProtoReflect())
+	for changed := true; changed; {
+		changed = false
+		uses := make(map[string][]*schemaReference)
+		for _, r := range schemaReferences(d) {
+			pointer := componentForRef(r.site.GetReference().XRef)
+			uses[pointer] = append(uses[pointer], r)
+		}
+		removed := make(map[string]bool)
+		schemas := d.GetComponents().GetSchemas()
+		if schemas == nil {
+			return
+		}
+		kept := make([]*NamedSchemaOrReference, 0, len(schemas.AdditionalProperties))
+		for _, pair := range schemas.AdditionalProperties {
+			pointer := "#/components/schemas/" + escapeJSONPointer(pair.Name)
+			r := uses[pointer]
+			if len(r) != 1 || mapped[pointer] || r[0].site.GetReference().XRef != pointer ||
+				r[0].owner == pair.Name || removed[r[0].owner] {
+				kept = append(kept, pair)
+				continue
+			}
+			r[0].site.Oneof = pair.Value.Oneof
+			removed[pair.Name] = true
+			report.Inlined++
+			changed = true
+		}
+		schemas.AdditionalProperties = kept
+		if len(kept) == 0 {
+			d.Components.Schemas = nil
+			if proto.Equal(d.Components, &Components{}) {
+				d.Components = nil
+			}
+		}
+	}
+}
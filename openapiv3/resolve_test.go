@@ -0,0 +1,211 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"io/ioutil"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/compiler"
+)
+
+func TestPromoteSchemaAliases(t *testing.T) {
+	info, err := compiler.ParseYAML("", []byte(`
+openapi: 3.0.0
+info:
+  title: Things
+  version: 1.0.0
+x-shared:
+  id: &Id
+    type: string
+  once: &Once
+    type: integer
+paths:
+  /things:
+    get:
+      parameters:
+      - name: id
+        in: query
+        schema: *Id
+      - name: count
+        in: query
+        schema: *Once
+      responses:
+        '200':
+          description: Things.
+          content:
+            application/json:
+              schema: &Thing
+                type: object
+                properties:
+                  id: *Id
+              example: *Id
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: *Thing
+      responses:
+        '201':
+          description: Created.
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if count := PromoteSchemaAliases(info.Content[0]); count != 2 {
+		t.Errorf("PromoteSchemaAliases added %d components, want 2", count)
+	}
+	compiler.ExpandAliases(info)
+	d, err := NewDocument(info.Content[0], compiler.NewContext("$root", info.Content[0], nil))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	names := make([]string, 0)
+	for _, pair := range d.Components.Schemas.AdditionalProperties {
+		names = append(names, pair.Name)
+	}
+	if got := strings.Join(names, ","); got != "Id,Thing" {
+		t.Errorf("Unexpected components: %s", got)
+	}
+	get := d.Paths.Path[0].Value.Get
+	if ref := get.Parameters[0].GetParameter().Schema.GetReference().GetXRef(); ref != "#/components/schemas/Id" {
+		t.Errorf("Unexpected schema of a parameter: %+v", get.Parameters[0])
+	}
+	// An alias that is used once is expanded.
+	if schema := get.Parameters[1].GetParameter().Schema.GetSchema(); schema.GetType() != "integer" {
+		t.Errorf("Unexpected schema of a parameter: %+v", get.Parameters[1])
+	}
+	// Aliases in examples are values, not schemas.
+	mediaType := get.Responses.ResponseOrReference[0].Value.GetResponse().Content.AdditionalProperties[0].Value
+	if mediaType.Example.GetYaml() != "type: string\n" || mediaType.Schema.GetReference().GetXRef() != "#/components/schemas/Thing" {
+		t.Errorf("Unexpected media type: %+v", mediaType)
+	}
+	if ref := d.Paths.Path[0].Value.Post.RequestBody.GetRequestBody().Content.AdditionalProperties[0].Value.Schema.GetReference().GetXRef(); ref != "#/components/schemas/Thing" {
+		t.Errorf("Unexpected request body schema: %s", ref)
+	}
+}
+
+func TestResolveReferencesSmart(t *testing.T) {
+	root := "../testdata/resolve-smart/openapi.yaml"
+	bytes, err := ioutil.ReadFile(root)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	info, err := compiler.ParseYAML(root, bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	promoted := PromoteSchemaAliases(info.Content[0])
+	compiler.ExpandAliases(info)
+	d, err := NewDocument(info.Content[0], compiler.NewContext("$root", info.Content[0], nil))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	report, err := ResolveReferencesSmart(d, root)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// Error, Tag and Owner are used once; Pet, Filter and Page more than once.
+	if report.Inlined != 3 || report.Components+promoted != 3 {
+		t.Errorf("Unexpected report: %s", report)
+	}
+	if report.SizeBefore == 0 || report.SizeAfter == 0 {
+		t.Errorf("Unexpected sizes: %s", report)
+	}
+	names := make([]string, 0)
+	for _, pair := range d.Components.Schemas.AdditionalProperties {
+		names = append(names, pair.Name)
+	}
+	if got := strings.Join(names, ","); got != "Name,Shelter,Filter,Page,Pet" {
+		t.Errorf("Unexpected components: %s", got)
+	}
+
+	// The result compiles and describes the same API as the source.
+	output, err := d.YAMLValue("")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, err = ParseDocument(output); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	files := map[string]*yaml.Node{}
+	var result yaml.Node
+	if err = yaml.Unmarshal(output, &result); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	files["result.yaml"] = result.Content[0]
+	want := dereference(t, files, root, compiler.MapValueForKey(loadYAML(t, files, root), "paths"))
+	got := dereference(t, files, "result.yaml", compiler.MapValueForKey(files["result.yaml"], "paths"))
+	if !reflect.DeepEqual(decodeYAML(t, got), decodeYAML(t, want)) {
+		t.Errorf("The resolved document differs from the source")
+	}
+}
+
+// loadYAML reads a file with expanded aliases once.
+func loadYAML(t *testing.T, files map[string]*yaml.Node, file string) *yaml.Node {
+	if node, ok := files[file]; ok {
+		return node
+	}
+	bytes, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	info, err := compiler.ParseYAML(file, bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	compiler.ExpandAliases(info)
+	files[file] = info.Content[0]
+	return files[file]
+}
+
+// dereference returns a copy of a node in which references are replaced
+// by their targets. References in file are relative to file.
+func dereference(t *testing.T, files map[string]*yaml.Node, file string, node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.MappingNode && len(node.Content) == 2 && node.Content[0].Value == "$ref" {
+		parts := strings.SplitN(node.Content[1].Value, "#", 2)
+		target := file
+		if parts[0] != "" {
+			target = path.Join(path.Dir(file), parts[0])
+		}
+		value := loadYAML(t, files, target)
+		for _, key := range strings.Split(parts[1], "/")[1:] {
+			value = compiler.MapValueForKey(value, key)
+		}
+		if value == nil {
+			t.Fatalf("Can't resolve %s in %s", node.Content[1].Value, file)
+		}
+		return dereference(t, files, target, value)
+	}
+	c := *node
+	c.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		c.Content[i] = dereference(t, files, file, child)
+	}
+	return &c
+}
+
+func decodeYAML(t *testing.T, node *yaml.Node) interface{} {
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return value
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+// visitSchemas calls f for every inline schema in a document, including
+// schemas nested in properties, items, additionalProperties and combinators.
+// Nested schemas are visited before the schemas that contain them.
+func visitSchemas(d *Document, f func(*Schema)) {
+	if d == nil {
+		return
+	}
+	if d.Components != nil {
+		c := d.Components
+		if c.Schemas != nil {
+			for _, pair := range c.Schemas.AdditionalProperties {
+				visitSchemaOrReference(pair.Value, f)
+			}
+		}
+		if c.Parameters != nil {
+			for _, pair := range c.Parameters.AdditionalProperties {
+				visitParameter(pair.Value.GetParameter(), f)
+			}
+		}
+		if c.Headers != nil {
+			visitHeaders(c.Headers, f)
+		}
+		if c.RequestBodies != nil {
+			for _, pair := range c.RequestBodies.AdditionalProperties {
+				if requestBody := pair.Value.GetRequestBody(); requestBody != nil {
+					visitMediaTypes(requestBody.Content, f)
+				}
+			}
+		}
+		if c.Responses != nil {
+			for _, pair := range c.Responses.AdditionalProperties {
+				visitResponse(pair.Value.GetResponse(), f)
+			}
+		}
+		if c.Callbacks != nil {
+			visitCallbacks(c.Callbacks, f)
+		}
+	}
+	if d.Paths != nil {
+		for _, pair := range d.Paths.Path {
+			visitPathItem(pair.Value, f)
+		}
+	}
+}
+
+func visitPathItem(pathItem *PathItem, f func(*Schema)) {
+	if pathItem == nil {
+		return
+	}
+	for _, parameter := range pathItem.Parameters {
+		visitParameter(parameter.GetParameter(), f)
+	}
+	for _, operation := range []*Operation{
+		pathItem.Get, pathItem.Put, pathItem.Post, pathItem.Delete,
+		pathItem.Options, pathItem.Head, pathItem.Patch, pathItem.Trace,
+	} {
+		visitOperation(operation, f)
+	}
+}
+
+func visitOperation(operation *Operation, f func(*Schema)) {
+	if operation == nil {
+		return
+	}
+	for _, parameter := range operation.Parameters {
+		visitParameter(parameter.GetParameter(), f)
+	}
+	if requestBody := operation.RequestBody.GetRequestBody(); requestBody != nil {
+		visitMediaTypes(requestBody.Content, f)
+	}
+	if operation.Responses != nil {
+		visitResponse(operation.Responses.Default.GetResponse(), f)
+		for _, pair := range operation.Responses.ResponseOrReference {
+			visitResponse(pair.Value.GetResponse(), f)
+		}
+	}
+	if operation.Callbacks != nil {
+		visitCallbacks(operation.Callbacks, f)
+	}
+}
+
+func visitCallbacks(callbacks *CallbacksOrReferences, f func(*Schema)) {
+	for _, pair := range callbacks.AdditionalProperties {
+		if callback := pair.Value.GetCallback(); callback != nil {
+			for _, path := range callback.Path {
+				visitPathItem(path.Value, f)
+			}
+		}
+	}
+}
+
+func visitParameter(parameter *Parameter, f func(*Schema)) {
+	if parameter == nil {
+		return
+	}
+	visitSchemaOrReference(parameter.Schema, f)
+	visitMediaTypes(parameter.Content, f)
+}
+
+func visitHeaders(headers *HeadersOrReferences, f func(*Schema)) {
+	for _, pair := range headers.AdditionalProperties {
+		if header := pair.Value.GetHeader(); header != nil {
+			visitSchemaOrReference(header.Schema, f)
+			visitMediaTypes(header.Content, f)
+		}
+	}
+}
+
+func visitResponse(response *Response, f func(*Schema)) {
+	if response == nil {
+		return
+	}
+	if response.Headers != nil {
+		visitHeaders(response.Headers, f)
+	}
+	visitMediaTypes(response.Content, f)
+}
+
+func visitMediaTypes(content *MediaTypes, f func(*Schema)) {
+	if content == nil {
+		return
+	}
+	for _, pair := range content.AdditionalProperties {
+		if pair.Value != nil {
+			visitSchemaOrReference(pair.Value.Schema, f)
+		}
+	}
+}
+
+func visitSchemaOrReference(schemaOrReference *SchemaOrReference, f func(*Schema)) {
+	visitSchema(schemaOrReference.GetSchema(), f)
+}
+
+func visitSchema(schema *Schema, f func(*Schema)) {
+	if schema == nil {
+		return
+	}
+	for _, s := range schema.AllOf {
+		visitSchemaOrReference(s, f)
+	}
+	for _, s := range schema.OneOf {
+		visitSchemaOrReference(s, f)
+	}
+	for _, s := range schema.AnyOf {
+		visitSchemaOrReference(s, f)
+	}
+	visitSchema(schema.Not, f)
+	if schema.Items != nil {
+		for _, s := range schema.Items.SchemaOrReference {
+			visitSchemaOrReference(s, f)
+		}
+	}
+	if schema.Properties != nil {
+		for _, pair := range schema.Properties.AdditionalProperties {
+			visitSchemaOrReference(pair.Value, f)
+		}
+	}
+	if schema.AdditionalProperties != nil {
+		visitSchemaOrReference(schema.AdditionalProperties.GetSchemaOrReference(), f)
+	}
+	f(schema)
+}
@@ -0,0 +1,216 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A ServerURL is a parsed server URL template, such as
+// "https://{region}.example.com/v1".
+type ServerURL struct {
+	// parts alternate between literal text and variable names, starting
+	// with literal text.
+	parts []string
+}
+
+// ParseServerURL parses a server URL template. Variables are names in
+// braces. Templates with whitespace, unbalanced braces or empty variable
+// names are errors.
+func ParseServerURL(template string) (*ServerURL, error) {
+	if strings.ContainsAny(template, " \t\r\n") {
+		return nil, errors.New("server URL contains whitespace")
+	}
+	u := &ServerURL{}
+	literal := ""
+	for rest := template; rest != ""; {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			literal += rest
+			break
+		}
+		if rest[open] == '}' {
+			return nil, fmt.Errorf("server URL has an unexpected } at offset %d", len(template)-len(rest)+open)
+		}
+		end := strings.IndexAny(rest[open+1:], "{}")
+		if end < 0 || rest[open+1+end] == '{' {
+			return nil, fmt.Errorf("server URL has an unclosed { at offset %d", len(template)-len(rest)+open)
+		}
+		name := rest[open+1 : open+1+end]
+		if name == "" {
+			return nil, fmt.Errorf("server URL has an empty variable name at offset %d", len(template)-len(rest)+open)
+		}
+		u.parts = append(u.parts, literal+rest[:open], name)
+		literal = ""
+		rest = rest[open+1+end+1:]
+	}
+	u.parts = append(u.parts, literal)
+	return u, nil
+}
+
+// Variables returns the names of the variables of a server URL in the
+// order of their first use.
+func (u *ServerURL) Variables() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for i := 1; i < len(u.parts); i += 2 {
+		if !seen[u.parts[i]] {
+			names = append(names, u.parts[i])
+			seen[u.parts[i]] = true
+		}
+	}
+	return names
+}
+
+// Expand returns the URL with variables replaced by their values.
+// Variables without values are kept.
+func (u *ServerURL) Expand(values map[string]string) string {
+	var b strings.Builder
+	for i, part := range u.parts {
+		if i%2 == 0 {
+			b.WriteString(part)
+		} else if value, ok := values[part]; ok {
+			b.WriteString(value)
+		} else {
+			b.WriteString("{" + part + "}")
+		}
+	}
+	return b.String()
+}
+
+// serverVariableDefaults returns the defaults of the variables of a server.
+func serverVariableDefaults(server *Server) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range server.GetVariables().GetAdditionalProperties() {
+		values[pair.Name] = pair.Value.GetDefault()
+	}
+	return values
+}
+
+// A ServerProblem describes a server whose URL is malformed or doesn't
+// match its variables.
+type ServerProblem struct {
+	// Keys locate the server in the document, e.g. ["servers", "0", "url"].
+	Keys    []string
+	Message string
+}
+
+func (p *ServerProblem) String() string {
+	return strings.Join(p.Keys, ".") + ": " + p.Message
+}
+
+// schemePattern matches the scheme of absolute URLs.
+var schemePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://`)
+
+// CheckServers checks the servers of a document, its path items and its
+// operations: URLs must be well-formed, the variables that URLs use must
+// be declared, declared variables must be used, and defaults of variables
+// with enums must be enum values. Absolute URLs need a scheme, and URLs
+// that don't start with a scheme, a slash or a dot are absolute URLs if
+// their host has a dot or a port, as in "api.example.com/v1".
+func CheckServers(d *Document) []*ServerProblem {
+	problems := make([]*ServerProblem, 0)
+	check := func(servers []*Server, keys []string) {
+		for i, server := range servers {
+			problems = append(problems, checkServer(server, appendKeys(keys, "servers", strconv.Itoa(i)))...)
+		}
+	}
+	check(d.Servers, nil)
+	for _, pair := range d.GetPaths().GetPath() {
+		check(pair.Value.GetServers(), []string{"paths", pair.Name})
+	}
+	ForEachOperation(d, func(path, method string, _ *PathItem, operation *Operation) {
+		check(operation.Servers, []string{"paths", path, method})
+	})
+	return problems
+}
+
+func checkServer(server *Server, keys []string) []*ServerProblem {
+	var problems []*ServerProblem
+	fail := func(keys []string, format string, args ...interface{}) {
+		problems = append(problems, &ServerProblem{Keys: keys, Message: fmt.Sprintf(format, args...)})
+	}
+	u, err := ParseServerURL(server.Url)
+	if err != nil {
+		fail(appendKeys(keys, "url"), "%s", err.Error())
+		return problems
+	}
+	used := make(map[string]bool)
+	for _, name := range u.Variables() {
+		used[name] = true
+		if serverVariable(server, name) == nil {
+			fail(appendKeys(keys, "url"), "variable %s is not declared", name)
+		}
+	}
+	for _, pair := range server.GetVariables().GetAdditionalProperties() {
+		variableKeys := appendKeys(keys, "variables", pair.Name)
+		if !used[pair.Name] {
+			fail(variableKeys, "variable %s is not used in the URL", pair.Name)
+		}
+		if enum := pair.Value.GetEnum(); len(enum) > 0 && !containsString(enum, pair.Value.GetDefault()) {
+			fail(appendKeys(variableKeys, "default"), "default %q is not one of the enum values", pair.Value.GetDefault())
+		}
+	}
+	// Undeclared variables are replaced by a placeholder to check the
+	// rest of the URL.
+	values := serverVariableDefaults(server)
+	for _, name := range u.Variables() {
+		if _, ok := values[name]; !ok {
+			values[name] = "x"
+		}
+	}
+	expanded := u.Expand(values)
+	switch {
+	case strings.HasPrefix(expanded, "//"):
+		fail(appendKeys(keys, "url"), "absolute URL %s has no scheme", server.Url)
+	case schemePattern.MatchString(expanded), strings.HasPrefix(expanded, "/"), strings.HasPrefix(expanded, "."):
+		parsed, err := url.Parse(expanded)
+		switch {
+		case err != nil:
+			fail(appendKeys(keys, "url"), "URL %s is malformed", server.Url)
+		case parsed.Scheme != "" && parsed.Host == "":
+			fail(appendKeys(keys, "url"), "absolute URL %s has no host", server.Url)
+		}
+	default:
+		host := strings.SplitN(expanded, "/", 2)[0]
+		if strings.ContainsAny(host, ".:") {
+			fail(appendKeys(keys, "url"), "absolute URL %s has no scheme", server.Url)
+		}
+	}
+	return problems
+}
+
+func serverVariable(server *Server, name string) *ServerVariable {
+	for _, pair := range server.GetVariables().GetAdditionalProperties() {
+		if pair.Name == name {
+			return pair.Value
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseServerURL(t *testing.T) {
+	u, err := ParseServerURL("{scheme}://{region}.example.com/{region}/v1")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if variables := strings.Join(u.Variables(), ","); variables != "scheme,region" {
+		t.Errorf("Unexpected variables: %s", variables)
+	}
+	expanded := u.Expand(map[string]string{"region": "eu"})
+	if expanded != "{scheme}://eu.example.com/eu/v1" {
+		t.Errorf("Unexpected expansion: %s", expanded)
+	}
+	for template, message := range map[string]string{
+		"https://api.example.com/a b": "server URL contains whitespace",
+		"https://{region.example.com": "server URL has an unclosed { at offset 8",
+		"https://{a{b}}.example.com":  "server URL has an unclosed { at offset 8",
+		"https://a}.example.com":      "server URL has an unexpected } at offset 9",
+		"https://{}.example.com":      "server URL has an empty variable name at offset 8",
+	} {
+		if _, err := ParseServerURL(template); err == nil || err.Error() != message {
+			t.Errorf("Unexpected error for %s: %v", template, err)
+		}
+	}
+}
+
+func TestServerURLOfMocks(t *testing.T) {
+	// Mocks and code samples expand server URLs like CheckServers.
+	server := &Server{
+		Url: "https://{region}.example.com/{version}",
+		Variables: &ServerVariables{AdditionalProperties: []*NamedServerVariable{
+			{Name: "region", Value: &ServerVariable{Default: "us"}},
+			{Name: "version", Value: &ServerVariable{Default: "v1"}},
+		}},
+	}
+	if u := serverURL(server); u != "https://us.example.com/v1" {
+		t.Errorf("Unexpected URL: %s", u)
+	}
+	if path := serverPath(server); path != "/v1" {
+		t.Errorf("Unexpected path: %s", path)
+	}
+}
+
+func TestCheckServers(t *testing.T) {
+	d, err := ParseDocument([]byte(`
+openapi: 3.0.0
+info:
+  title: Servers
+  version: 1.0.0
+servers:
+  - url: https://{region}.example.com
+    variables:
+      region:
+        default: us
+        enum: [us, eu]
+paths:
+  /pets:
+    servers:
+      - url: https://{zone}.example.com
+    get:
+      servers:
+        - url: https://pets.example.com
+          variables:
+            region:
+              default: us
+      responses:
+        "200":
+          description: OK
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var problems []string
+	for _, problem := range CheckServers(d) {
+		problems = append(problems, problem.String())
+	}
+	expected := []string{
+		"paths./pets.servers.0.url: variable zone is not declared",
+		"paths./pets.get.servers.0.variables.region: variable region is not used in the URL",
+	}
+	if strings.Join(problems, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected problems:\n%s", strings.Join(problems, "\n"))
+	}
+}
@@ -0,0 +1,414 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// StripMarked removes the path items, operations, parameters, responses,
+// component schemas, and schema properties that carry a specification
+// extension with the given name and a truthy value, such as
+// "x-internal: true". Parameters and responses that refer to removed
+// components are removed with them. Components that were only used by
+// removed elements are then removed too; components that were unused
+// before are kept.
+//
+// It is an error to remove a schema that is still referenced by an element
+// that is kept. The error lists the referrers and the document is not
+// changed.
+func StripMarked(d *Document, marker string) error {
+	if d == nil {
+		return nil
+	}
+	result := proto.Clone(d).(*Document)
+	before := usedComponents(result)
+	sizes := sectionSizes(result.Components)
+	removed := make(map[string]bool)
+	if c := result.Components; c != nil {
+		if c.Schemas != nil {
+			var schemas []*NamedSchemaOrReference
+			for _, pair := range c.Schemas.AdditionalProperties {
+				if isMarked(pair.Value.GetSchema(), marker) {
+					removed["#/components/schemas/"+escapeJSONPointer(pair.Name)] = true
+				} else {
+					schemas = append(schemas, pair)
+				}
+			}
+			c.Schemas.AdditionalProperties = schemas
+		}
+		if c.Parameters != nil {
+			var parameters []*NamedParameterOrReference
+			for _, pair := range c.Parameters.AdditionalProperties {
+				if isMarked(pair.Value.GetParameter(), marker) {
+					removed["#/components/parameters/"+escapeJSONPointer(pair.Name)] = true
+				} else {
+					parameters = append(parameters, pair)
+				}
+			}
+			c.Parameters.AdditionalProperties = parameters
+		}
+		if c.Responses != nil {
+			var responses []*NamedResponseOrReference
+			for _, pair := range c.Responses.AdditionalProperties {
+				if isMarked(pair.Value.GetResponse(), marker) {
+					removed["#/components/responses/"+escapeJSONPointer(pair.Name)] = true
+				} else {
+					responses = append(responses, pair)
+				}
+			}
+			c.Responses.AdditionalProperties = responses
+		}
+	}
+	if result.Paths != nil {
+		var paths []*NamedPathItem
+		for _, pair := range result.Paths.Path {
+			if stripPathItem(pair.Value, marker, removed) {
+				paths = append(paths, pair)
+			}
+		}
+		result.Paths.Path = paths
+	}
+	visitSchemas(result, func(schema *Schema) {
+		if schema.Properties == nil {
+			return
+		}
+		var properties []*NamedSchemaOrReference
+		for _, pair := range schema.Properties.AdditionalProperties {
+			if isMarked(pair.Value.GetSchema(), marker) {
+				schema.Required = removeString(schema.Required, pair.Name)
+			} else {
+				properties = append(properties, pair)
+			}
+		}
+		schema.Properties.AdditionalProperties = properties
+	})
+
+	// Removed schemas must not be referenced by anything that remains.
+	referrers := make(map[string][]string)
+	visitReferences(result, func(owner, ref string) {
+		if target := componentForRef(ref); removed[target] && strings.HasPrefix(target, "#/components/schemas/") {
+			referrers[target] = appendUnique(referrers[target], owner)
+		}
+	})
+	if len(referrers) > 0 {
+		var targets []string
+		for target := range referrers {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		var messages []string
+		for _, target := range targets {
+			messages = append(messages, fmt.Sprintf("%s is marked with %s but is referenced by %s",
+				target, marker, strings.Join(referrers[target], ", ")))
+		}
+		return fmt.Errorf("%s", strings.Join(messages, "\n"))
+	}
+
+	// Remove components that were only used by removed elements.
+	after := usedComponents(result)
+	filterComponents(result.Components, func(pointer string) bool {
+		return !before[pointer] || after[pointer]
+	})
+	// Sections that were emptied are removed.
+	if c := result.Components; c != nil {
+		fields := c.ProtoReflect().Descriptor().Fields()
+		for name, size := range sectionSizes(c) {
+			if size == 0 && sizes[name] > 0 {
+				c.ProtoReflect().Clear(fields.ByName(name))
+			}
+		}
+	}
+	proto.Reset(d)
+	proto.Merge(d, result)
+	return nil
+}
+
+// StripExtensions removes the specification extensions whose names match
+// a pattern from a document and returns the number of removed extensions.
+func StripExtensions(d *Document, pattern *regexp.Regexp) int {
+	if d == nil {
+		return 0
+	}
+	return stripExtensions(d.ProtoReflect(), pattern)
+}
+
+func stripExtensions(m protoreflect.Message, pattern *regexp.Regexp) int {
+	count := 0
+	m.Range(func(f protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if f.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		if !f.IsList() {
+			count += stripExtensions(v.Message(), pattern)
+			return true
+		}
+		list := v.List()
+		kept := 0
+		for i := 0; i < list.Len(); i++ {
+			item := list.Get(i)
+			if extension, ok := item.Message().Interface().(*NamedAny); ok &&
+				f.Name() == "specification_extension" && pattern.MatchString(extension.Name) {
+				count++
+				continue
+			}
+			count += stripExtensions(item.Message(), pattern)
+			list.Set(kept, item)
+			kept++
+		}
+		if kept < list.Len() {
+			list.Truncate(kept)
+		}
+		return true
+	})
+	return count
+}
+
+// isMarked returns true if a message has the marker extension with a
+// truthy value: true, a nonzero number, or a string other than "false".
+func isMarked(obj Extensible, marker string) bool {
+	var value interface{}
+	if err := GetExtensionInto(obj, marker, &value); err != nil {
+		return false
+	}
+	switch v := value.(type) {
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		return v != "" && v != "false"
+	default:
+		return false
+	}
+}
+
+// stripPathItem removes the marked elements of a path item and returns
+// false if the path item itself should be removed.
+func stripPathItem(pathItem *PathItem, marker string, removed map[string]bool) bool {
+	if pathItem == nil {
+		return true
+	}
+	if isMarked(pathItem, marker) {
+		return false
+	}
+	pathItem.Parameters = stripParameters(pathItem.Parameters, marker, removed)
+	operations := 0
+	kept := 0
+	for _, operation := range []**Operation{
+		&pathItem.Get, &pathItem.Put, &pathItem.Post, &pathItem.Delete,
+		&pathItem.Options, &pathItem.Head, &pathItem.Patch, &pathItem.Trace,
+	} {
+		if *operation == nil {
+			continue
+		}
+		operations++
+		if isMarked(*operation, marker) {
+			*operation = nil
+			continue
+		}
+		kept++
+		(*operation).Parameters = stripParameters((*operation).Parameters, marker, removed)
+		if responses := (*operation).Responses; responses != nil {
+			if stripResponse(responses.Default, marker, removed) {
+				responses.Default = nil
+			}
+			var pairs []*NamedResponseOrReference
+			for _, pair := range responses.ResponseOrReference {
+				if !stripResponse(pair.Value, marker, removed) {
+					pairs = append(pairs, pair)
+				}
+			}
+			responses.ResponseOrReference = pairs
+		}
+	}
+	// Path items are removed with their last operation.
+	return operations == 0 || kept > 0
+}
+
+func stripParameters(parameters []*ParameterOrReference, marker string, removed map[string]bool) []*ParameterOrReference {
+	var kept []*ParameterOrReference
+	for _, parameter := range parameters {
+		if isMarked(parameter.GetParameter(), marker) ||
+			removed[componentForRef(parameter.GetReference().GetXRef())] {
+			continue
+		}
+		kept = append(kept, parameter)
+	}
+	return kept
+}
+
+// stripResponse returns true if a response should be removed.
+func stripResponse(response *ResponseOrReference, marker string, removed map[string]bool) bool {
+	return response != nil && (isMarked(response.GetResponse(), marker) ||
+		removed[componentForRef(response.GetReference().GetXRef())])
+}
+
+// usedComponents returns the pointers of the components that are referenced,
+// directly or indirectly, from outside the components of a document.
+func usedComponents(d *Document) map[string]bool {
+	withoutComponents := proto.Clone(d).(*Document)
+	withoutComponents.Components = nil
+	return newReferenceGraph(d.Components).reachable(func(use func(ref string)) {
+		walkReferences(withoutComponents.ProtoReflect(), use)
+	})
+}
+
+// visitReferences calls f for each reference in a document with a
+// description of the element that contains it: an operation such as
+// "GET /pets", a path item, or the pointer of a component.
+func visitReferences(d *Document, f func(owner, ref string)) {
+	if d.Paths != nil {
+		for _, pair := range d.Paths.Path {
+			pathItem := pair.Value
+			if pathItem == nil {
+				continue
+			}
+			for _, operation := range []struct {
+				method    string
+				operation *Operation
+			}{
+				{"GET", pathItem.Get}, {"PUT", pathItem.Put}, {"POST", pathItem.Post}, {"DELETE", pathItem.Delete},
+				{"OPTIONS", pathItem.Options}, {"HEAD", pathItem.Head}, {"PATCH", pathItem.Patch}, {"TRACE", pathItem.Trace},
+			} {
+				if operation.operation != nil {
+					owner := operation.method + " " + pair.Name
+					walkReferences(operation.operation.ProtoReflect(), func(ref string) { f(owner, ref) })
+				}
+			}
+			for _, parameter := range pathItem.Parameters {
+				walkReferences(parameter.ProtoReflect(), func(ref string) { f(pair.Name, ref) })
+			}
+		}
+	}
+	rangeComponents(d.Components, func(pointer string, pair protoreflect.Message) {
+		walkReferences(pair, func(ref string) { f(pointer, ref) })
+	})
+}
+
+// rangeComponents calls f with the pointer and the named pair of each
+// component.
+func rangeComponents(c *Components, f func(pointer string, pair protoreflect.Message)) {
+	filterComponents(c, func(pointer string) bool { return true }, f)
+}
+
+// sectionSizes returns the number of components in each section.
+func sectionSizes(c *Components) map[protoreflect.Name]int {
+	sizes := make(map[protoreflect.Name]int)
+	if c == nil {
+		return sizes
+	}
+	c.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if field.Kind() == protoreflect.MessageKind && !field.IsList() {
+			section := v.Message()
+			sizes[field.Name()] = section.Get(section.Descriptor().Fields().ByName("additional_properties")).List().Len()
+		}
+		return true
+	})
+	return sizes
+}
+
+// filterComponents removes the components for which keep returns false
+// and calls each of visit with the components that are kept.
+func filterComponents(c *Components, keep func(pointer string) bool, visit ...func(pointer string, pair protoreflect.Message)) {
+	if c == nil {
+		return
+	}
+	// Each section is a message with a list of named pairs.
+	c.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if field.Kind() != protoreflect.MessageKind || field.IsList() {
+			return true
+		}
+		section := v.Message()
+		pairs := section.Get(section.Descriptor().Fields().ByName("additional_properties")).List()
+		kept := 0
+		for i := 0; i < pairs.Len(); i++ {
+			pair := pairs.Get(i)
+			name := pair.Message().Get(pair.Message().Descriptor().Fields().ByName("name")).String()
+			pointer := "#/components/" + field.JSONName() + "/" + escapeJSONPointer(name)
+			if !keep(pointer) {
+				continue
+			}
+			for _, f := range visit {
+				f(pointer, pair.Message())
+			}
+			pairs.Set(kept, pair)
+			kept++
+		}
+		if kept < pairs.Len() {
+			pairs.Truncate(kept)
+		}
+		return true
+	})
+}
+
+// walkReferences calls f for each reference in a message.
+func walkReferences(m protoreflect.Message, f func(ref string)) {
+	if r, ok := m.Interface().(*Reference); ok {
+		f(r.XRef)
+		return
+	}
+	m.Range(func(field protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if field.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		if field.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				walkReferences(list.Get(i).Message(), f)
+			}
+		} else {
+			walkReferences(v.Message(), f)
+		}
+		return true
+	})
+}
+
+// componentForRef returns the pointer of the component that contains the
+// target of a local reference, or "" for other references.
+func componentForRef(ref string) string {
+	parts := strings.SplitN(ref, "/", 5)
+	if len(parts) < 4 || parts[0] != "#" || parts[1] != "components" {
+		return ""
+	}
+	return strings.Join(parts[:4], "/")
+}
+
+func removeString(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"regexp"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const stripDocument = `
+openapi: 3.0.0
+info:
+  title: Things
+  version: 1.0.0
+paths:
+  /things:
+    get:
+      responses:
+        '200':
+          description: Things.
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Thing'
+    post:
+      x-internal: true
+      responses:
+        '200':
+          description: A secret.
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Secret'
+components:
+  schemas:
+    Thing:
+      type: object
+      properties:
+        secret:
+          $ref: '#/components/schemas/Secret'
+    Secret:
+      type: string
+      x-internal: true
+      x-internal-owner: someone
+`
+
+func TestStripMarkedErrors(t *testing.T) {
+	d, err := ParseDocument([]byte(stripDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	original := proto.Clone(d)
+	err = StripMarked(d, "x-internal")
+	expected := "#/components/schemas/Secret is marked with x-internal but is referenced by #/components/schemas/Thing"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !proto.Equal(d, original) {
+		t.Errorf("StripMarked changed a document after an error")
+	}
+	// Values that aren't truthy don't mark elements.
+	for _, value := range []string{"false", "0", "''", "[true]"} {
+		if isMarked(&Schema{SpecificationExtension: []*NamedAny{{Name: "x-internal", Value: &Any{Yaml: value}}}}, "x-internal") {
+			t.Errorf("%s is a marker", value)
+		}
+	}
+}
+
+func TestStripExtensions(t *testing.T) {
+	d, err := ParseDocument([]byte(stripDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if count := StripExtensions(d, regexp.MustCompile("^x-internal-")); count != 1 {
+		t.Errorf("StripExtensions removed %d extensions, want 1", count)
+	}
+	if extensions := schemaNamed(t, d, "Secret").SpecificationExtension; len(extensions) != 1 || extensions[0].Name != "x-internal" {
+		t.Errorf("Unexpected extensions: %+v", extensions)
+	}
+}
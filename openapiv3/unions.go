@@ -0,0 +1,143 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"github.com/google/gnostic/compiler"
+)
+
+// SimplifiedExtensionName is the name of the extension that records
+// the original variants of a union collapsed by SimplifyUnions.
+const SimplifiedExtensionName = "x-gnostic-simplified"
+
+// SimplifyUnions collapses oneOf and anyOf combinators whose members are
+// all inline scalar schemas (string, number, integer, or boolean) into a
+// single schema. Unions that contain references, objects, or arrays are
+// left untouched. It returns the number of schemas that were changed.
+//
+// Members that share a type collapse to that type, and a mix of integer
+// and number collapses to number. Since OpenAPI 3.0 does not allow type
+// arrays, any other mix collapses to string, which accepts the serialized
+// form of every member but loses type information. In every case the
+// original variants are recorded in an x-gnostic-simplified extension.
+func SimplifyUnions(d *Document) int {
+	count := 0
+	visitSchemas(d, func(schema *Schema) {
+		if simplifyUnion(schema) {
+			count++
+		}
+	})
+	return count
+}
+
+func simplifyUnion(schema *Schema) bool {
+	if schema.Type != "" || len(schema.AllOf) > 0 {
+		return false
+	}
+	var combinator string
+	var members []*SchemaOrReference
+	switch {
+	case len(schema.OneOf) > 0 && len(schema.AnyOf) == 0:
+		combinator, members = "oneOf", schema.OneOf
+	case len(schema.AnyOf) > 0 && len(schema.OneOf) == 0:
+		combinator, members = "anyOf", schema.AnyOf
+	default:
+		return false
+	}
+	types := make(map[string]bool)
+	for _, member := range members {
+		s := member.GetSchema()
+		if !isScalarSchema(s) {
+			return false
+		}
+		types[s.Type] = true
+	}
+
+	lossy := false
+	var simplifiedType string
+	switch {
+	case len(types) == 1:
+		simplifiedType = members[0].GetSchema().Type
+	case len(types) == 2 && types["integer"] && types["number"]:
+		simplifiedType = "number"
+	default:
+		simplifiedType = "string"
+		lossy = true
+	}
+
+	format := members[0].GetSchema().Format
+	keepEnum := !lossy
+	var enum []*Any
+	for _, member := range members {
+		s := member.GetSchema()
+		if s.Format != format {
+			format = ""
+		}
+		if s.Nullable {
+			schema.Nullable = true
+		}
+		if len(s.Enum) == 0 {
+			keepEnum = false
+		}
+		enum = append(enum, s.Enum...)
+	}
+	if lossy {
+		format = ""
+	}
+
+	// Record the original variants before discarding them.
+	variants := compiler.NewSequenceNode()
+	for _, member := range members {
+		variants.Content = append(variants.Content, member.ToRawInfo())
+	}
+	info := compiler.NewMappingNode()
+	info.Content = append(info.Content,
+		compiler.NewScalarNodeForString(combinator), variants)
+	if lossy {
+		info.Content = append(info.Content,
+			compiler.NewScalarNodeForString("lossy"), compiler.NewScalarNodeForBool(true))
+	}
+	SetExtension(schema, SimplifiedExtensionName, info)
+
+	schema.OneOf = nil
+	schema.AnyOf = nil
+	schema.Type = simplifiedType
+	if schema.Format == "" {
+		schema.Format = format
+	}
+	if keepEnum && len(schema.Enum) == 0 {
+		schema.Enum = enum
+	}
+	return true
+}
+
+// isScalarSchema returns true if s is an inline schema for a single scalar type.
+func isScalarSchema(s *Schema) bool {
+	if s == nil {
+		return false
+	}
+	switch s.Type {
+	case "string", "number", "integer", "boolean":
+	default:
+		return false
+	}
+	return len(s.AllOf) == 0 &&
+		len(s.OneOf) == 0 &&
+		len(s.AnyOf) == 0 &&
+		s.Not == nil &&
+		s.Items == nil &&
+		s.Properties == nil &&
+		s.AdditionalProperties == nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"strings"
+	"testing"
+)
+
+const unionsDocument = `
+openapi: 3.0.0
+info:
+  title: Unions
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Mixed:
+      oneOf:
+        - type: string
+        - type: number
+    Numeric:
+      anyOf:
+        - type: integer
+          format: int64
+        - type: number
+    Objects:
+      oneOf:
+        - $ref: '#/components/schemas/Numeric'
+        - type: object
+    ListOfUnions:
+      type: array
+      items:
+        oneOf:
+          - type: string
+          - type: boolean
+    MapOfUnions:
+      type: object
+      additionalProperties:
+        anyOf:
+          - type: string
+            enum: [a, b]
+          - type: string
+            enum: [c]
+`
+
+func schemaNamed(t *testing.T, d *Document, name string) *Schema {
+	for _, pair := range d.Components.Schemas.AdditionalProperties {
+		if pair.Name == name {
+			return pair.Value.GetSchema()
+		}
+	}
+	t.Fatalf("missing schema %s", name)
+	return nil
+}
+
+func simplifiedExtension(s *Schema) string {
+	for _, e := range s.SpecificationExtension {
+		if e.Name == SimplifiedExtensionName {
+			return e.Value.Yaml
+		}
+	}
+	return ""
+}
+
+func TestSimplifyUnions(t *testing.T) {
+	d, err := ParseDocument([]byte(unionsDocument))
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if count := SimplifyUnions(d); count != 4 {
+		t.Errorf("unexpected number of simplified schemas: %d (expected 4)", count)
+	}
+
+	mixed := schemaNamed(t, d, "Mixed")
+	if mixed.Type != "string" || len(mixed.OneOf) != 0 {
+		t.Errorf("unexpected simplification of Mixed: %+v", mixed)
+	}
+	if x := simplifiedExtension(mixed); !strings.Contains(x, "oneOf:") || !strings.Contains(x, "lossy: true") {
+		t.Errorf("unexpected %s for Mixed: %s", SimplifiedExtensionName, x)
+	}
+
+	numeric := schemaNamed(t, d, "Numeric")
+	if numeric.Type != "number" || numeric.Format != "" || len(numeric.AnyOf) != 0 {
+		t.Errorf("unexpected simplification of Numeric: %+v", numeric)
+	}
+	if x := simplifiedExtension(numeric); !strings.Contains(x, "format: int64") || strings.Contains(x, "lossy") {
+		t.Errorf("unexpected %s for Numeric: %s", SimplifiedExtensionName, x)
+	}
+
+	objects := schemaNamed(t, d, "Objects")
+	if objects.Type != "" || len(objects.OneOf) != 2 || simplifiedExtension(objects) != "" {
+		t.Errorf("object union should not be simplified: %+v", objects)
+	}
+
+	items := schemaNamed(t, d, "ListOfUnions").Items.SchemaOrReference[0].GetSchema()
+	if items.Type != "string" || simplifiedExtension(items) == "" {
+		t.Errorf("unexpected simplification of array items: %+v", items)
+	}
+
+	values := schemaNamed(t, d, "MapOfUnions").AdditionalProperties.GetSchemaOrReference().GetSchema()
+	if values.Type != "string" || len(values.Enum) != 3 || simplifiedExtension(values) == "" {
+		t.Errorf("unexpected simplification of map values: %+v", values)
+	}
+}
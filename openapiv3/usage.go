@@ -0,0 +1,177 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// A UsageIndex records which operations of a document use which component
+// schemas. Operations are named by their uppercase methods and paths, such
+// as "GET /pets". An operation uses the schemas that it refers to in its
+// parameters, request bodies, responses, headers, and callbacks, the schemas
+// that the parameters of its path item refer to, and all schemas that these
+// refer to, directly or through other components.
+type UsageIndex struct {
+	// operations are the names of all operations in document order.
+	operations []string
+	// uses are the pointers of the components used by each operation.
+	uses map[string]map[string]bool
+	// referrers are the pointers of the components that refer to a component.
+	referrers map[string]map[string]bool
+}
+
+// BuildUsageIndex builds the usage index of a document.
+func BuildUsageIndex(d *Document) *UsageIndex {
+	graph := newReferenceGraph(d.GetComponents())
+	index := &UsageIndex{
+		uses:      make(map[string]map[string]bool),
+		referrers: graph.referrers(),
+	}
+	ForEachOperation(d, func(path, method string, item *PathItem, operation *Operation) {
+		name := strings.ToUpper(method) + " " + path
+		index.operations = append(index.operations, name)
+		index.uses[name] = graph.reachable(func(use func(ref string)) {
+			walkReferences(operation.ProtoReflect(), use)
+			for _, parameter := range item.Parameters {
+				walkReferences(parameter.ProtoReflect(), use)
+			}
+		})
+	})
+	return index
+}
+
+// OperationsUsingSchema returns the operations that use a component schema
+// in document order.
+func (index *UsageIndex) OperationsUsingSchema(name string) []string {
+	pointer := schemaPointer(name)
+	var operations []string
+	for _, operation := range index.operations {
+		if index.uses[operation][pointer] {
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
+
+// SchemasUsedByOperation returns the sorted names of the component schemas
+// that an operation uses. The method is not case-sensitive.
+func (index *UsageIndex) SchemasUsedByOperation(path, method string) []string {
+	var names []string
+	for pointer := range index.uses[strings.ToUpper(method)+" "+path] {
+		if name, ok := schemaName(pointer); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TransitiveDependents returns the sorted names of the component schemas
+// that refer to a component schema, directly or through other schemas.
+func (index *UsageIndex) TransitiveDependents(name string) []string {
+	start := schemaPointer(name)
+	visited := map[string]bool{start: true}
+	pending := []string{start}
+	var names []string
+	for len(pending) > 0 {
+		target := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for referrer := range index.referrers[target] {
+			if visited[referrer] {
+				continue
+			}
+			visited[referrer] = true
+			if name, ok := schemaName(referrer); ok {
+				names = append(names, name)
+				pending = append(pending, referrer)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// A referenceGraph maps the pointers of the components of a document to
+// the pointers of the components that they refer to.
+type referenceGraph map[string][]string
+
+func newReferenceGraph(c *Components) referenceGraph {
+	graph := make(referenceGraph)
+	rangeComponents(c, func(pointer string, pair protoreflect.Message) {
+		walkReferences(pair, func(ref string) {
+			if target := componentForRef(ref); target != "" {
+				graph[pointer] = append(graph[pointer], target)
+			}
+		})
+	})
+	return graph
+}
+
+// reachable returns the pointers of the components that walk passes
+// references to, and of all components that these refer to, directly
+// or through other components.
+func (graph referenceGraph) reachable(walk func(use func(ref string))) map[string]bool {
+	used := make(map[string]bool)
+	var pending []string
+	use := func(ref string) {
+		if target := componentForRef(ref); target != "" && !used[target] {
+			used[target] = true
+			pending = append(pending, target)
+		}
+	}
+	walk(use)
+	for len(pending) > 0 {
+		target := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, ref := range graph[target] {
+			use(ref)
+		}
+	}
+	return used
+}
+
+// referrers returns the pointers of the components that refer to each
+// component.
+func (graph referenceGraph) referrers() map[string]map[string]bool {
+	referrers := make(map[string]map[string]bool)
+	for pointer, targets := range graph {
+		for _, target := range targets {
+			if referrers[target] == nil {
+				referrers[target] = make(map[string]bool)
+			}
+			referrers[target][pointer] = true
+		}
+	}
+	return referrers
+}
+
+const schemasPointerPrefix = "#/components/schemas/"
+
+func schemaPointer(name string) string {
+	return schemasPointerPrefix + escapeJSONPointer(name)
+}
+
+// schemaName returns the name of the schema with a pointer.
+func schemaName(pointer string) (string, bool) {
+	if !strings.HasPrefix(pointer, schemasPointerPrefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(pointer, schemasPointerPrefix)
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(name), true
+}
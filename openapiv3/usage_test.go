@@ -0,0 +1,144 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"reflect"
+	"testing"
+)
+
+const usageDocument = `
+openapi: 3.0.0
+info:
+  title: Payments
+  version: 1.0.0
+paths:
+  /accounts/{id}:
+    parameters:
+    - name: id
+      in: path
+      required: true
+      schema:
+        $ref: '#/components/schemas/AccountId'
+    get:
+      responses:
+        '200':
+          description: An account.
+          headers:
+            X-Balance:
+              schema:
+                $ref: '#/components/schemas/Money'
+  /payments:
+    post:
+      requestBody:
+        $ref: '#/components/requestBodies/Payment'
+      callbacks:
+        settled:
+          '{$request.body#/callback}':
+            post:
+              requestBody:
+                content:
+                  application/json:
+                    schema:
+                      $ref: '#/components/schemas/Settlement'
+              responses:
+                '200':
+                  description: Received.
+      responses:
+        '201':
+          $ref: '#/components/responses/Created'
+  /health:
+    get:
+      responses:
+        '200':
+          description: Healthy.
+components:
+  schemas:
+    AccountId:
+      type: string
+    Money:
+      type: object
+      properties:
+        amount:
+          type: number
+        currency:
+          $ref: '#/components/schemas/Currency'
+    Currency:
+      type: string
+    Payment:
+      type: object
+      properties:
+        amount:
+          $ref: '#/components/schemas/Money'
+    Settlement:
+      type: object
+    Receipt:
+      allOf:
+      - $ref: '#/components/schemas/Payment'
+  requestBodies:
+    Payment:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Payment'
+  responses:
+    Created:
+      description: Created.
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Receipt'
+`
+
+func TestUsageIndex(t *testing.T) {
+	d, err := ParseDocument([]byte(usageDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	index := BuildUsageIndex(d)
+	for _, test := range []struct {
+		schema     string
+		operations []string
+	}{
+		{"AccountId", []string{"GET /accounts/{id}"}},
+		{"Money", []string{"GET /accounts/{id}", "POST /payments"}},
+		{"Currency", []string{"GET /accounts/{id}", "POST /payments"}},
+		{"Settlement", []string{"POST /payments"}},
+		{"Receipt", []string{"POST /payments"}},
+		{"Missing", nil},
+	} {
+		if operations := index.OperationsUsingSchema(test.schema); !reflect.DeepEqual(operations, test.operations) {
+			t.Errorf("OperationsUsingSchema(%q) = %v, want %v", test.schema, operations, test.operations)
+		}
+	}
+	for _, test := range []struct {
+		path, method string
+		schemas      []string
+	}{
+		{"/accounts/{id}", "get", []string{"AccountId", "Currency", "Money"}},
+		{"/payments", "POST", []string{"Currency", "Money", "Payment", "Receipt", "Settlement"}},
+		{"/health", "get", nil},
+	} {
+		if schemas := index.SchemasUsedByOperation(test.path, test.method); !reflect.DeepEqual(schemas, test.schemas) {
+			t.Errorf("SchemasUsedByOperation(%q, %q) = %v, want %v", test.path, test.method, schemas, test.schemas)
+		}
+	}
+	if dependents := index.TransitiveDependents("Currency"); !reflect.DeepEqual(dependents, []string{"Money", "Payment", "Receipt"}) {
+		t.Errorf("Unexpected dependents: %v", dependents)
+	}
+	if dependents := index.TransitiveDependents("Receipt"); dependents != nil {
+		t.Errorf("Unexpected dependents: %v", dependents)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/jsonschema"
+)
+
+// An ExampleError describes an example that doesn't conform to its schema.
+type ExampleError struct {
+	// Keys locate the example in the document,
+	// e.g. ["paths", "/pets", "get", "parameters", "0", "example"].
+	Keys []string
+	// Pointer is the JSON Pointer of the value in the example that
+	// violates the schema.
+	Pointer string
+	Message string
+}
+
+func (e *ExampleError) String() string {
+	if e.Pointer == "" {
+		return strings.Join(e.Keys, ".") + ": " + e.Message
+	}
+	return strings.Join(e.Keys, ".") + ": " + e.Pointer + ": " + e.Message
+}
+
+// ValidateExamples validates the examples of parameters, request bodies
+// and responses against their schemas and describes the values that don't
+// conform in the returned errors. References to component schemas are
+// followed and nullable schemas accept null. Examples whose schemas or
+// references can't be resolved are reported too.
+func ValidateExamples(d *Document) []*ExampleError {
+	v := &exampleValidator{document: d, errors: make([]*ExampleError, 0)}
+	if d == nil {
+		return v.errors
+	}
+	definitions := make([]*jsonschema.NamedSchema, 0)
+	for _, pair := range d.GetComponents().GetSchemas().GetAdditionalProperties() {
+		schema := jsonschema.NewSchemaFromObject(jsonSchemaNode(pair.Value.ToRawInfo()))
+		definitions = append(definitions, jsonschema.NewNamedSchema(pair.Name, schema))
+	}
+	v.definitions = &definitions
+	if c := d.Components; c != nil {
+		for _, pair := range c.GetParameters().GetAdditionalProperties() {
+			v.validateParameter(pair.Value.GetParameter(), []string{"components", "parameters", pair.Name})
+		}
+		for _, pair := range c.GetRequestBodies().GetAdditionalProperties() {
+			v.validateMediaTypes(pair.Value.GetRequestBody().GetContent(), []string{"components", "requestBodies", pair.Name, "content"})
+		}
+		for _, pair := range c.GetResponses().GetAdditionalProperties() {
+			v.validateMediaTypes(pair.Value.GetResponse().GetContent(), []string{"components", "responses", pair.Name, "content"})
+		}
+	}
+	for _, pair := range d.GetPaths().GetPath() {
+		for i, parameter := range pair.Value.GetParameters() {
+			v.validateParameter(parameter.GetParameter(), []string{"paths", pair.Name, "parameters", fmt.Sprintf("%d", i)})
+		}
+	}
+	ForEachOperation(d, func(path, method string, _ *PathItem, operation *Operation) {
+		keys := []string{"paths", path, method}
+		for i, parameter := range operation.Parameters {
+			v.validateParameter(parameter.GetParameter(), appendKeys(keys, "parameters", fmt.Sprintf("%d", i)))
+		}
+		v.validateMediaTypes(operation.GetRequestBody().GetRequestBody().GetContent(), appendKeys(keys, "requestBody", "content"))
+		if operation.Responses != nil {
+			v.validateMediaTypes(operation.Responses.Default.GetResponse().GetContent(), appendKeys(keys, "responses", "default", "content"))
+			for _, pair := range operation.Responses.ResponseOrReference {
+				v.validateMediaTypes(pair.Value.GetResponse().GetContent(), appendKeys(keys, "responses", pair.Name, "content"))
+			}
+		}
+	})
+	return v.errors
+}
+
+type exampleValidator struct {
+	document    *Document
+	definitions *[]*jsonschema.NamedSchema
+	errors      []*ExampleError
+}
+
+func (v *exampleValidator) fail(keys []string, pointer string, message string) {
+	v.errors = append(v.errors, &ExampleError{Keys: keys, Pointer: pointer, Message: message})
+}
+
+func (v *exampleValidator) validateParameter(parameter *Parameter, keys []string) {
+	if parameter == nil {
+		return
+	}
+	if parameter.Schema != nil {
+		v.validateExamples(parameter.Schema, parameter.Example, parameter.Examples, keys)
+	}
+	v.validateMediaTypes(parameter.Content, appendKeys(keys, "content"))
+}
+
+func (v *exampleValidator) validateMediaTypes(content *MediaTypes, keys []string) {
+	for _, pair := range content.GetAdditionalProperties() {
+		if mediaType := pair.Value; mediaType != nil && mediaType.Schema != nil {
+			v.validateExamples(mediaType.Schema, mediaType.Example, mediaType.Examples, appendKeys(keys, pair.Name))
+		}
+	}
+}
+
+// validateExamples validates the example and the named examples of a
+// parameter or a media type.
+func (v *exampleValidator) validateExamples(s *SchemaOrReference, example *Any, examples *ExamplesOrReferences, keys []string) {
+	if example == nil && len(examples.GetAdditionalProperties()) == 0 {
+		return
+	}
+	schema := v.jsonSchema(s)
+	if example != nil {
+		v.validateValue(schema, example, appendKeys(keys, "example"))
+	}
+	for _, pair := range examples.GetAdditionalProperties() {
+		exampleKeys := appendKeys(keys, "examples", pair.Name)
+		value := pair.Value.GetExample()
+		if ref := pair.Value.GetReference(); ref != nil {
+			var err error
+			if value, err = (&exampleResolver{document: v.document}).exampleForRef(ref.XRef); err != nil {
+				v.fail(exampleKeys, "", fmt.Sprintf("unable to resolve %s: %s", ref.XRef, err.Error()))
+				continue
+			}
+		}
+		if a := ExampleValue(value); a != nil {
+			v.validateValue(schema, a, appendKeys(exampleKeys, "value"))
+		}
+	}
+}
+
+func (v *exampleValidator) validateValue(schema *jsonschema.Schema, value *Any, keys []string) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(value.GetYaml()), &node); err != nil {
+		v.fail(keys, "", err.Error())
+		return
+	}
+	for _, err := range schema.Validate(&node) {
+		v.fail(keys, err.Pointer, err.Message)
+	}
+}
+
+// jsonSchema returns the JSON Schema of a schema or a reference with the
+// component schemas of the document as its definitions.
+func (v *exampleValidator) jsonSchema(s *SchemaOrReference) *jsonschema.Schema {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	if ref := s.GetReference(); ref != nil {
+		node.Content = []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "$ref"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: ref.XRef},
+		}
+		node = jsonSchemaNode(node)
+	} else if schema := s.GetSchema(); schema != nil {
+		node = jsonSchemaNode(schema.ToRawInfo())
+	}
+	schema := jsonschema.NewSchemaFromObject(node)
+	if schema == nil {
+		schema = &jsonschema.Schema{}
+	}
+	schema.Definitions = v.definitions
+	return schema
+}
+
+// jsonSchemaKeywords are the keywords of OpenAPI schemas that are
+// validated. Other keywords, such as examples and extensions, are dropped.
+var jsonSchemaKeywords = map[string]bool{
+	"$ref": true, "multipleOf": true, "maximum": true, "exclusiveMaximum": true,
+	"minimum": true, "exclusiveMinimum": true, "maxLength": true, "minLength": true,
+	"pattern": true, "items": true, "maxItems": true, "minItems": true,
+	"uniqueItems": true, "maxProperties": true, "minProperties": true,
+	"required": true, "additionalProperties": true, "properties": true,
+	"enum": true, "type": true, "allOf": true, "anyOf": true, "oneOf": true,
+	"not": true,
+}
+
+// jsonSchemaNode converts the YAML of an OpenAPI schema to a JSON Schema:
+// references to component schemas refer to definitions, nullable types
+// also accept null, and keywords that aren't validated are removed.
+func jsonSchemaNode(node *yaml.Node) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return node
+	}
+	nullable := false
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "nullable" && node.Content[i+1].Value == "true" {
+			nullable = true
+		}
+	}
+	result := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !jsonSchemaKeywords[key.Value] {
+			continue
+		}
+		switch key.Value {
+		case "$ref":
+			if strings.HasPrefix(value.Value, "#/components/schemas/") {
+				value = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str",
+					Value: "#/definitions/" + strings.TrimPrefix(value.Value, "#/components/schemas/")}
+			}
+		case "type":
+			if nullable && value.Kind == yaml.ScalarNode {
+				value = &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{
+					value,
+					{Kind: yaml.ScalarNode, Tag: "!!str", Value: "null"},
+				}}
+			}
+		case "enum":
+			// Enumerations of other values than strings and booleans
+			// can't be represented.
+			supported := value.Kind == yaml.SequenceNode
+			for _, item := range value.Content {
+				if tag := item.ShortTag(); tag != "!!str" && tag != "!!bool" {
+					supported = false
+				}
+			}
+			if !supported {
+				continue
+			}
+		case "properties":
+			properties := &yaml.Node{Kind: yaml.MappingNode}
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				properties.Content = append(properties.Content, value.Content[j], jsonSchemaNode(value.Content[j+1]))
+			}
+			value = properties
+		case "items", "additionalProperties", "not":
+			value = jsonSchemaNode(value)
+		case "allOf", "anyOf", "oneOf":
+			schemas := &yaml.Node{Kind: yaml.SequenceNode}
+			for _, item := range value.Content {
+				schemas.Content = append(schemas.Content, jsonSchemaNode(item))
+			}
+			value = schemas
+		}
+		result.Content = append(result.Content, key, value)
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v3
+
+import (
+	"strings"
+	"testing"
+)
+
+const validateExamplesDocument = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    parameters:
+    - name: tag
+      in: query
+      schema:
+        type: string
+        maxLength: 5
+      example: toolong
+    get:
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+          minimum: 1
+        example: 0
+      responses:
+        '200':
+          description: Pets.
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+              example:
+              - name: Fido
+              - id: 2
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+            examples:
+              valid:
+                value:
+                  name: Rex
+                  tag: null
+              invalid:
+                $ref: '#/components/examples/InvalidPet'
+      responses:
+        default:
+          description: Error.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  code:
+                    type: integer
+              example:
+                code: oops
+components:
+  examples:
+    InvalidPet:
+      value:
+        name: 7
+  schemas:
+    Pet:
+      type: object
+      required:
+      - name
+      properties:
+        name:
+          type: string
+        tag:
+          type: string
+          nullable: true
+`
+
+func TestValidateExamples(t *testing.T) {
+	d, err := ParseDocument([]byte(validateExamplesDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	errors := ValidateExamples(d)
+	got := make([]string, 0)
+	for _, e := range errors {
+		got = append(got, e.String())
+	}
+	want := []string{
+		"paths./pets.parameters.0.example: string is longer than 5 characters",
+		"paths./pets.get.parameters.0.example: 0 is less than 1",
+		"paths./pets.get.responses.200.content.application/json.example: /1: missing required property name",
+		"paths./pets.post.requestBody.content.application/json.examples.invalid.value: /name: integer is not of type string",
+		"paths./pets.post.responses.default.content.application/json.example: /code: string is not of type integer",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ValidateExamples() returned\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
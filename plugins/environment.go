@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"os"
 	"path"
 	"strings"
@@ -14,6 +13,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/any"
 
+	"github.com/google/gnostic/compiler"
 	discovery "github.com/google/gnostic/discovery"
 	openapiv2 "github.com/google/gnostic/openapiv2"
 	openapiv3 "github.com/google/gnostic/openapiv3"
@@ -27,6 +27,17 @@ type Environment struct {
 	Invocation      string    // string representation of call
 	RunningAsPlugin bool      // true if app is being run as a plugin
 	Verbose         bool      // if true, plugin should log details to stderr
+	// Logger receives the messages of the plugin. NewEnvironment sets a
+	// standard Logger that writes details only if Verbose is true.
+	Logger compiler.Logger
+}
+
+// logger returns the Logger of an environment.
+func (env *Environment) logger() compiler.Logger {
+	if env.Logger != nil {
+		return env.Logger
+	}
+	return compiler.CurrentLogger()
 }
 
 // NewEnvironment creates a plugin context from arguments and standard input.
@@ -44,6 +55,11 @@ func NewEnvironment() (env *Environment, err error) {
 
 	env.RunningAsPlugin = *plugin
 	env.Verbose = *verbose
+	if env.Verbose {
+		env.Logger = &compiler.StandardLogger{Level: compiler.LogLevelDebug}
+	} else {
+		env.Logger = &compiler.StandardLogger{Level: compiler.LogLevelWarn}
+	}
 	programName := path.Base(os.Args[0])
 
 	if (*input == "") && !*plugin {
@@ -85,7 +101,7 @@ When the -plugin option is specified, these flags are ignored.`)
 		}
 
 		// Log the invocation.
-		//log.Printf("Running plugin %s", env.Invocation)
+		env.logger().Debugf("Running plugin %s", env.Invocation)
 
 		env.Request = request
 
@@ -158,7 +174,7 @@ func (env *Environment) RespondAndExit() {
 	} else {
 		err := HandleResponse(env.Response, env.Request.OutputPath)
 		if err != nil {
-			log.Printf("%s", err.Error())
+			env.logger().Warnf("%s", err.Error())
 		}
 	}
 	os.Exit(0)
@@ -190,9 +206,9 @@ func HandleResponse(response *Response, outputLocation string) error {
 			p := outputLocation + "/" + file.Name
 			dir := path.Dir(p)
 			os.MkdirAll(dir, 0755)
-			f, _ := os.Create(p)
-			defer f.Close()
-			f.Write(file.Data)
+			if err := compiler.WriteFileAtomically(p, file.Data); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -22,12 +22,22 @@
 //  - The response types used and their frequencies.
 //  - The types used in definition objects and arrays and their frequencies.
 // Results are returned in a JSON structure.
+//
+// It also reports, overall and for each tag, the HTTP methods, response
+// codes and media types that operations use, the operations that document
+// no 4xx responses and the operations whose 200 response has no schema.
+// These results are returned in a JSON structure and in a table. With the
+// "threshold-4xx" and "threshold-schema" parameters, the plugin fails if
+// more than the given percentage of operations document no 4xx responses
+// or have no 200 response schema, so that it can be used to gate changes.
 package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/google/gnostic/plugins/gnostic-analyze/statistics"
@@ -59,7 +69,25 @@ func main() {
 	env, err := plugins.NewEnvironment()
 	env.RespondAndExitIfError(err)
 
+	// Thresholds are percentages of operations; negative values are unset.
+	threshold4xx, thresholdSchema := -1.0, -1.0
+	for _, parameter := range env.Request.Parameters {
+		switch parameter.Name {
+		case "threshold-4xx", "threshold-schema":
+			value, err := strconv.ParseFloat(parameter.Value, 64)
+			if err != nil {
+				env.RespondAndExitIfError(fmt.Errorf("invalid value for %s: %s", parameter.Name, parameter.Value))
+			}
+			if parameter.Name == "threshold-4xx" {
+				threshold4xx = value
+			} else {
+				thresholdSchema = value
+			}
+		}
+	}
+
 	var stats *statistics.DocumentStatistics
+	var coverage *statistics.Coverage
 
 	for _, model := range env.Request.Models {
 		switch model.TypeUrl {
@@ -69,6 +97,7 @@ func main() {
 			if err == nil {
 				// Analyze the API document.
 				stats = statistics.NewDocumentStatistics(env.Request.SourceName, documentv2)
+				coverage = statistics.NewCoverage(env.Request.SourceName, documentv2)
 			}
 		case "openapi.v3.Document":
 			documentv3 := &openapiv3.Document{}
@@ -76,6 +105,7 @@ func main() {
 			if err == nil {
 				// Analyze the API document.
 				stats = statistics.NewDocumentStatisticsV3(env.Request.SourceName, documentv3)
+				coverage = statistics.NewCoverageV3(env.Request.SourceName, documentv3)
 			}
 		}
 	}
@@ -92,5 +122,29 @@ func main() {
 		env.Response.Files = append(env.Response.Files, file)
 	}
 
+	if coverage != nil {
+		// Coverage results are in files named "coverage.json" and
+		// "coverage.txt" next to the summary.
+		file := &plugins.File{}
+		file.Name = strings.Replace(coverage.Name, path.Base(coverage.Name), "coverage.json", -1)
+		file.Data, err = json.MarshalIndent(coverage, "", "  ")
+		file.Data = append(file.Data, []byte("\n")...)
+		env.RespondAndExitIfError(err)
+		env.Response.Files = append(env.Response.Files, file,
+			&plugins.File{
+				Name: strings.Replace(coverage.Name, path.Base(coverage.Name), "coverage.txt", -1),
+				Data: []byte(coverage.Table()),
+			})
+
+		if p := coverage.Overall.PercentWithout4xx(); threshold4xx >= 0 && p > threshold4xx {
+			env.Response.Errors = append(env.Response.Errors,
+				fmt.Sprintf("%.1f%% of operations document no 4xx responses (threshold %g%%)", p, threshold4xx))
+		}
+		if p := coverage.Overall.PercentWithoutSchema(); thresholdSchema >= 0 && p > thresholdSchema {
+			env.Response.Errors = append(env.Response.Errors,
+				fmt.Sprintf("%.1f%% of operations have no 200 response schema (threshold %g%%)", p, thresholdSchema))
+		}
+	}
+
 	env.RespondAndExit()
 }
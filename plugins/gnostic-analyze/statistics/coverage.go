@@ -0,0 +1,261 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	openapiv2 "github.com/google/gnostic/openapiv2"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+)
+
+// Coverage describes how the operations of an API description use HTTP
+// methods, response codes and media types, overall and for each tag.
+type Coverage struct {
+	Name    string                     `json:"name"`
+	Overall *CoverageCounts            `json:"overall"`
+	Tags    map[string]*CoverageCounts `json:"tags"`
+}
+
+// CoverageCounts contains the counts of a group of operations.
+type CoverageCounts struct {
+	Operations    int            `json:"operations"`
+	Methods       map[string]int `json:"methods"`
+	ResponseCodes map[string]int `json:"responseCodes"`
+	MediaTypes    map[string]int `json:"mediaTypes"`
+	// OperationsWithout4xx are the operations that document no 4xx responses.
+	OperationsWithout4xx []string `json:"operationsWithout4xx"`
+	// OperationsWithoutSchema are the operations whose 200 response
+	// has no schema.
+	OperationsWithoutSchema []string `json:"operationsWithoutSchema"`
+}
+
+func newCoverageCounts() *CoverageCounts {
+	return &CoverageCounts{
+		Methods:                 make(map[string]int),
+		ResponseCodes:           make(map[string]int),
+		MediaTypes:              make(map[string]int),
+		OperationsWithout4xx:    make([]string, 0),
+		OperationsWithoutSchema: make([]string, 0),
+	}
+}
+
+// PercentWithout4xx returns the percentage of operations that document
+// no 4xx responses.
+func (c *CoverageCounts) PercentWithout4xx() float64 {
+	return percent(len(c.OperationsWithout4xx), c.Operations)
+}
+
+// PercentWithoutSchema returns the percentage of operations whose 200
+// response has no schema.
+func (c *CoverageCounts) PercentWithoutSchema() float64 {
+	return percent(len(c.OperationsWithoutSchema), c.Operations)
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// coverageOperation contains the properties of an operation that are
+// counted in Coverage.
+type coverageOperation struct {
+	name          string
+	method        string
+	tags          []string
+	codes         []string
+	mediaTypes    []string
+	has200        bool
+	has200Schema  bool
+	hasClientCode bool
+}
+
+func (c *CoverageCounts) add(o *coverageOperation) {
+	c.Operations++
+	c.Methods[o.method]++
+	for _, code := range o.codes {
+		c.ResponseCodes[code]++
+	}
+	for _, mediaType := range o.mediaTypes {
+		c.MediaTypes[mediaType]++
+	}
+	if !o.hasClientCode {
+		c.OperationsWithout4xx = append(c.OperationsWithout4xx, o.name)
+	}
+	if o.has200 && !o.has200Schema {
+		c.OperationsWithoutSchema = append(c.OperationsWithoutSchema, o.name)
+	}
+}
+
+func newCoverage(source string) *Coverage {
+	return &Coverage{
+		Name:    source,
+		Overall: newCoverageCounts(),
+		Tags:    make(map[string]*CoverageCounts),
+	}
+}
+
+func (c *Coverage) add(o *coverageOperation) {
+	for _, code := range o.codes {
+		if strings.HasPrefix(code, "4") {
+			o.hasClientCode = true
+		}
+	}
+	c.Overall.add(o)
+	for _, tag := range o.tags {
+		if c.Tags[tag] == nil {
+			c.Tags[tag] = newCoverageCounts()
+		}
+		c.Tags[tag].add(o)
+	}
+}
+
+// NewCoverage computes the Coverage of an OpenAPI v2 document.
+func NewCoverage(source string, document *openapiv2.Document) *Coverage {
+	c := newCoverage(source)
+	openapiv2.ForEachOperation(document, func(path, method string, item *openapiv2.PathItem, operation *openapiv2.Operation) {
+		o := &coverageOperation{
+			name:   strings.ToUpper(method) + " " + path,
+			method: method,
+			tags:   operation.Tags,
+		}
+		produces := operation.Produces
+		if produces == nil {
+			produces = document.Produces
+		}
+		consumes := operation.Consumes
+		if consumes == nil {
+			consumes = document.Consumes
+		}
+		o.mediaTypes = append(append(o.mediaTypes, consumes...), produces...)
+		for _, pair := range operation.GetResponses().GetResponseCode() {
+			o.codes = append(o.codes, pair.Name)
+			if pair.Name != "200" {
+				continue
+			}
+			o.has200 = true
+			response := pair.GetValue().GetResponse()
+			if ref := pair.GetValue().GetJsonReference(); ref != nil {
+				name := strings.TrimPrefix(ref.XRef, "#/responses/")
+				for _, named := range document.GetResponses().GetAdditionalProperties() {
+					if named.Name == name {
+						response = named.Value
+					}
+				}
+			}
+			o.has200Schema = response.GetSchema() != nil
+		}
+		c.add(o)
+	})
+	return c
+}
+
+// NewCoverageV3 computes the Coverage of an OpenAPI v3 document.
+func NewCoverageV3(source string, document *openapiv3.Document) *Coverage {
+	c := newCoverage(source)
+	openapiv3.ForEachOperation(document, func(path, method string, item *openapiv3.PathItem, operation *openapiv3.Operation) {
+		o := &coverageOperation{
+			name:   strings.ToUpper(method) + " " + path,
+			method: method,
+			tags:   operation.Tags,
+		}
+		for _, pair := range operation.GetRequestBody().GetRequestBody().GetContent().GetAdditionalProperties() {
+			o.mediaTypes = append(o.mediaTypes, pair.Name)
+		}
+		add := func(code string, r *openapiv3.ResponseOrReference) {
+			o.codes = append(o.codes, code)
+			response := r.GetResponse()
+			if ref := r.GetReference(); ref != nil {
+				name := strings.TrimPrefix(ref.XRef, "#/components/responses/")
+				for _, named := range document.GetComponents().GetResponses().GetAdditionalProperties() {
+					if named.Name == name {
+						response = named.Value.GetResponse()
+					}
+				}
+			}
+			hasSchema := false
+			for _, pair := range response.GetContent().GetAdditionalProperties() {
+				o.mediaTypes = append(o.mediaTypes, pair.Name)
+				if pair.GetValue().GetSchema() != nil {
+					hasSchema = true
+				}
+			}
+			if code == "200" {
+				o.has200 = true
+				o.has200Schema = hasSchema
+			}
+		}
+		for _, pair := range operation.GetResponses().GetResponseOrReference() {
+			add(pair.Name, pair.Value)
+		}
+		if operation.GetResponses().GetDefault() != nil {
+			add("default", operation.Responses.Default)
+		}
+		c.add(o)
+	})
+	return c
+}
+
+// Table returns a table of the counts, with a row for all operations
+// followed by a row for each tag.
+func (c *Coverage) Table() string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TAG\tOPERATIONS\tMETHODS\tRESPONSE CODES\tMEDIA TYPES\tWITHOUT 4XX\tWITHOUT 200 SCHEMA")
+	row := func(name string, counts *CoverageCounts) {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%d (%.1f%%)\t%d (%.1f%%)\n",
+			name,
+			counts.Operations,
+			formatCounts(counts.Methods),
+			formatCounts(counts.ResponseCodes),
+			formatCounts(counts.MediaTypes),
+			len(counts.OperationsWithout4xx), counts.PercentWithout4xx(),
+			len(counts.OperationsWithoutSchema), counts.PercentWithoutSchema())
+	}
+	row("(all)", c.Overall)
+	tags := make([]string, 0, len(c.Tags))
+	for tag := range c.Tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		row(tag, c.Tags[tag])
+	}
+	w.Flush()
+	return b.String()
+}
+
+// formatCounts formats counts as "key=count" pairs sorted by key.
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=%d", key, counts[key])
+	}
+	if len(pairs) == 0 {
+		return "-"
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"reflect"
+	"testing"
+
+	openapiv2 "github.com/google/gnostic/openapiv2"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+)
+
+func TestCoverageV3(t *testing.T) {
+	document, err := openapiv3.ParseDocument([]byte(`openapi: 3.0.0
+info:
+  title: Coverage
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+        "404":
+          $ref: "#/components/responses/NotFound"
+    delete:
+      tags: [pets, admin]
+      responses:
+        "200":
+          description: ok
+components:
+  responses:
+    NotFound:
+      description: not found
+      content:
+        application/problem+json:
+          schema:
+            type: object
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	coverage := NewCoverageV3("openapi.yaml", document)
+	overall := coverage.Overall
+	if overall.Operations != 2 {
+		t.Errorf("Unexpected number of operations: %d", overall.Operations)
+	}
+	if expected := map[string]int{"get": 1, "delete": 1}; !reflect.DeepEqual(overall.Methods, expected) {
+		t.Errorf("Unexpected methods: %v", overall.Methods)
+	}
+	if expected := map[string]int{"200": 2, "404": 1}; !reflect.DeepEqual(overall.ResponseCodes, expected) {
+		t.Errorf("Unexpected response codes: %v", overall.ResponseCodes)
+	}
+	if expected := map[string]int{"application/json": 1, "application/problem+json": 1}; !reflect.DeepEqual(overall.MediaTypes, expected) {
+		t.Errorf("Unexpected media types: %v", overall.MediaTypes)
+	}
+	if expected := []string{"DELETE /pets"}; !reflect.DeepEqual(overall.OperationsWithout4xx, expected) {
+		t.Errorf("Unexpected operations without 4xx responses: %v", overall.OperationsWithout4xx)
+	}
+	if expected := []string{"DELETE /pets"}; !reflect.DeepEqual(overall.OperationsWithoutSchema, expected) {
+		t.Errorf("Unexpected operations without schemas: %v", overall.OperationsWithoutSchema)
+	}
+	if overall.PercentWithout4xx() != 50 {
+		t.Errorf("Unexpected percentage of operations without 4xx responses: %f", overall.PercentWithout4xx())
+	}
+	if coverage.Tags["pets"].Operations != 2 || coverage.Tags["admin"].Operations != 1 {
+		t.Errorf("Unexpected tag counts: %+v", coverage.Tags)
+	}
+	if len(coverage.Tags["admin"].OperationsWithout4xx) != 1 {
+		t.Errorf("Unexpected admin operations without 4xx responses: %v", coverage.Tags["admin"].OperationsWithout4xx)
+	}
+}
+
+func TestCoverageV2(t *testing.T) {
+	document, err := openapiv2.ParseDocument([]byte(`swagger: "2.0"
+info:
+  title: Coverage
+  version: 1.0.0
+produces: [application/json]
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          $ref: "#/responses/Pets"
+        "400":
+          description: bad request
+    post:
+      consumes: [application/xml]
+      responses:
+        "200":
+          description: ok
+responses:
+  Pets:
+    description: pets
+    schema:
+      type: array
+      items:
+        type: string
+`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	overall := NewCoverage("swagger.yaml", document).Overall
+	if expected := []string{"POST /pets"}; !reflect.DeepEqual(overall.OperationsWithout4xx, expected) {
+		t.Errorf("Unexpected operations without 4xx responses: %v", overall.OperationsWithout4xx)
+	}
+	if expected := []string{"POST /pets"}; !reflect.DeepEqual(overall.OperationsWithoutSchema, expected) {
+		t.Errorf("Unexpected operations without schemas: %v", overall.OperationsWithoutSchema)
+	}
+	if expected := map[string]int{"application/json": 2, "application/xml": 1}; !reflect.DeepEqual(overall.MediaTypes, expected) {
+		t.Errorf("Unexpected media types: %v", overall.MediaTypes)
+	}
+}
+
+func TestCoverageTable(t *testing.T) {
+	coverage := newCoverage("openapi.yaml")
+	coverage.add(&coverageOperation{
+		name:   "GET /pets",
+		method: "get",
+		tags:   []string{"pets"},
+		codes:  []string{"200", "404"},
+		has200: true,
+	})
+	expected := `TAG    OPERATIONS  METHODS  RESPONSE CODES  MEDIA TYPES  WITHOUT 4XX  WITHOUT 200 SCHEMA
+(all)  1           get=1    200=1 404=1     -            0 (0.0%)     1 (100.0%)
+pets   1           get=1    200=1 404=1     -            0 (0.0%)     1 (100.0%)
+`
+	if table := coverage.Table(); table != expected {
+		t.Errorf("Unexpected table:\n%s", table)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	surface "github.com/google/gnostic/surface"
+)
+
+// bookstoreTest is compiled and run with the code generated for the bookstore sample.
+const bookstoreTest = `package bookstore
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /shelves":
+			if r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+			}
+			w.Write(body)
+		case "GET /shelves/1/books":
+			if r.URL.RawQuery != "limit=10&order=title" || r.Header.Get("Accept-Language") != "en" {
+				t.Errorf("unexpected request %s %s", r.URL, r.Header)
+			}
+			w.Write([]byte(` + "`" + `{"books":[{"name":"shelves/1/books/2","title":"Moby Dick"}]}` + "`" + `))
+		case "GET /shelves/2":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(` + "`" + `{"code":404,"message":"not found"}` + "`" + `))
+		case "DELETE /shelves/1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	c := NewClient(server.URL + "/")
+
+	created, err := c.CreateShelf(ctx, &CreateShelfParameters{Body: &Shelf{Name: "shelves/1", Theme: "Fiction"}})
+	if err != nil || created.StatusCode != 200 || created.JSON200 == nil || created.JSON200.Theme != "Fiction" {
+		t.Errorf("CreateShelf: %+v %v", created, err)
+	}
+	limit, order, language := int32(10), "title", "en"
+	books, err := c.ListBooks(ctx, &ListBooksParameters{Shelf: 1, Limit: &limit, Order: &order, AcceptLanguage: &language})
+	if err != nil || books.JSON200 == nil || len(books.JSON200.Books) != 1 || books.JSON200.Books[0].Title != "Moby Dick" {
+		t.Errorf("ListBooks: %+v %v", books, err)
+	}
+	shelf, err := c.GetShelf(ctx, &GetShelfParameters{Shelf: 2})
+	if err != nil || shelf.StatusCode != 404 || shelf.JSON200 != nil || shelf.JSONDefault == nil || shelf.JSONDefault.Message != "not found" {
+		t.Errorf("GetShelf: %+v %v", shelf, err)
+	}
+	deleted, err := c.DeleteShelf(ctx, &DeleteShelfParameters{Shelf: 1})
+	if err != nil || deleted.StatusCode != 204 || len(deleted.Body) != 0 {
+		t.Errorf("DeleteShelf: %+v %v", deleted, err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(created.Body, &decoded); err != nil || decoded["name"] != "shelves/1" {
+		t.Errorf("unexpected body %s", created.Body)
+	}
+}
+`
+
+func TestBookstoreClient(t *testing.T) {
+	const filename = "../../examples/v3.0/yaml/bookstore.yaml"
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	testClient(t, bytes, filename, "bookstore", bookstoreTest)
+}
+
+// retriesDocument describes operations with client behavior extensions.
+const retriesDocument = `
+openapi: 3.0.0
+info:
+  title: Retries
+  version: 1.0.0
+paths:
+  /flaky:
+    get:
+      operationId: getFlaky
+      x-retryable: true
+      responses:
+        '200':
+          description: Success.
+    post:
+      operationId: postFlaky
+      x-retryable: true
+      responses:
+        '200':
+          description: Success.
+  /slow:
+    get:
+      operationId: getSlow
+      x-client-timeout: 100ms
+      responses:
+        '200':
+          description: Success.
+`
+
+// retriesTest is compiled and run with the code generated for retriesDocument.
+const retriesTest = `package retries
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			attempts++
+			if attempts%2 == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		case "/slow":
+			time.Sleep(time.Second)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	c := NewClient(server.URL)
+
+	result, err := c.GetFlaky(ctx, nil)
+	if err != nil || result.StatusCode != 200 || attempts != 2 {
+		t.Errorf("GetFlaky: %+v %v after %d attempts", result, err, attempts)
+	}
+	// POST is not idempotent, so it is not retried.
+	result2, err := c.PostFlaky(ctx, nil)
+	if err != nil || result2.StatusCode != 503 || attempts != 3 {
+		t.Errorf("PostFlaky: %+v %v after %d attempts", result2, err, attempts)
+	}
+	start := time.Now()
+	if _, err := c.GetSlow(ctx, nil); err == nil || time.Since(start) > 900*time.Millisecond {
+		t.Errorf("GetSlow should time out: %v after %s", err, time.Since(start))
+	}
+}
+`
+
+func TestRetriesClient(t *testing.T) {
+	testClient(t, []byte(retriesDocument), "retries.yaml", "retries", retriesTest)
+}
+
+// testClient generates a client for an OpenAPI v3 document and runs
+// go vet and a test with it.
+func testClient(t *testing.T, bytes []byte, filename, packageName, test string) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+	document, err := openapiv3.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	model, err := surface.NewModelFromOpenAPI3(document, filename)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	client, err := generateClient(model, packageName)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	dir, err := ioutil.TempDir("", "gnostic-go-client")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"go.mod":         "module " + packageName + "\n\ngo 1.12\n",
+		"client.go":      string(client),
+		"client_test.go": test,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+	for _, args := range [][]string{{"vet", "."}, {"test", "."}} {
+		cmd := exec.Command(goTool, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("go %s failed: %+v\n%s", args[0], err, output)
+		}
+	}
+}
+
+func TestGenerateClientWithoutMethods(t *testing.T) {
+	if _, err := generateClient(&surface.Model{Name: "Empty"}, "empty"); err != nil {
+		t.Errorf("%+v", err)
+	}
+}
@@ -0,0 +1,684 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"go/format"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/gnostic/printer"
+	surface "github.com/google/gnostic/surface"
+)
+
+// A generator writes client code for a surface model.
+type generator struct {
+	model       *surface.Model
+	packageName string
+	code        *printer.Code
+	types       map[string]*surface.Type // model types by name
+	typeNames   map[string]string        // Go names of model types
+	usedNames   map[string]bool          // Go names of top-level declarations
+}
+
+// generateClient returns the formatted source of a Go file that contains
+// a Client type with one method for each method of a model.
+func generateClient(model *surface.Model, packageName string) ([]byte, error) {
+	g := &generator{
+		model:       model,
+		packageName: packageName,
+		code:        &printer.Code{},
+		types:       make(map[string]*surface.Type),
+		typeNames:   make(map[string]string),
+		usedNames:   make(map[string]bool),
+	}
+	for _, name := range []string{"Client", "NewClient"} {
+		g.usedNames[name] = true
+	}
+	for _, m := range model.Methods {
+		name := goName(m.Name)
+		for _, suffix := range []string{"Parameters", "Result"} {
+			g.usedNames[name+suffix] = true
+		}
+	}
+	for _, t := range model.Types {
+		g.types[t.Name] = t
+	}
+	for _, t := range model.Types {
+		if g.isMessageType(t) {
+			g.typeNames[t.Name] = g.uniqueName(goName(t.Name))
+		}
+	}
+	g.generate()
+	return format.Source([]byte(g.code.String()))
+}
+
+// isMessageType returns true for types that are generated as Go types.
+// Parameter, response, and request body types of methods are replaced
+// by the parameter and response structures of the methods, and types
+// that hold parameters from components are inlined where they are used.
+func (g *generator) isMessageType(t *surface.Type) bool {
+	for _, m := range g.model.Methods {
+		if t.Name == m.ParametersTypeName || t.Name == m.ResponsesTypeName {
+			return false
+		}
+		if parameters := g.types[m.ParametersTypeName]; parameters != nil {
+			for _, f := range parameters.Fields {
+				if f.Position == surface.Position_BODY && f.Name == "request_body" && f.Type == t.Name {
+					return false
+				}
+			}
+		}
+	}
+	if len(t.Fields) == 0 {
+		return true
+	}
+	for _, f := range t.Fields {
+		if f.Position == surface.Position_BODY {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) uniqueName(name string) string {
+	unique := name
+	for i := 2; g.usedNames[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	g.usedNames[unique] = true
+	return unique
+}
+
+func (g *generator) generate() {
+	code := g.code
+	code.Print("// Code generated by gnostic-go-client. DO NOT EDIT.")
+	code.Print()
+	code.Print("package %s", g.packageName)
+	code.Print()
+	usesTime, usesRetries := false, false
+	for _, m := range g.model.Methods {
+		usesTime = usesTime || m.TimeoutMilliseconds > 0 || retries(m)
+		usesRetries = usesRetries || retries(m)
+	}
+	code.Print("import (")
+	for _, name := range []string{"bytes", "context", "encoding/json", "fmt", "io/ioutil", "net/http", "net/url", "strings"} {
+		code.Print("%q", name)
+	}
+	if usesTime {
+		code.Print("%q", "time")
+	}
+	code.Print(")")
+	g.generateTypes()
+	g.generateClient()
+	for _, m := range g.model.Methods {
+		g.generateMethod(m)
+	}
+	code.Print("%s", clientSupport)
+	if usesRetries {
+		code.Print("%s", retrySupport)
+	}
+}
+
+// retries returns true if calls of a method are retried, which requires
+// the method to be retryable and idempotent.
+func retries(m *surface.Method) bool {
+	return m.Retryable && m.IsIdempotent()
+}
+
+// durationExpression returns a Go expression for a number of milliseconds
+// as a time.Duration, such as "90 * time.Second".
+func durationExpression(milliseconds int64) string {
+	for _, unit := range []struct {
+		name         string
+		milliseconds int64
+	}{
+		{"time.Hour", 3600000},
+		{"time.Minute", 60000},
+		{"time.Second", 1000},
+	} {
+		if milliseconds%unit.milliseconds == 0 {
+			return strconv.FormatInt(milliseconds/unit.milliseconds, 10) + " * " + unit.name
+		}
+	}
+	return strconv.FormatInt(milliseconds, 10) + " * time.Millisecond"
+}
+
+func (g *generator) generateTypes() {
+	for _, t := range g.model.Types {
+		name, ok := g.typeNames[t.Name]
+		if !ok {
+			continue
+		}
+		g.code.Print()
+		g.printComment(name+" is the "+t.Name+" type of the API.", t.Description)
+		if t.Kind == surface.TypeKind_OBJECT {
+			contentType := t.ContentType
+			if contentType == "" {
+				contentType = "interface{}"
+			}
+			g.code.Print("type %s map[string]%s", name, contentType)
+			continue
+		}
+		g.code.Print("type %s struct {", name)
+		fieldNames := make(map[string]bool)
+		for _, f := range t.Fields {
+			g.code.Print("%s %s `json:\"%s,omitempty\"`", uniqueFieldName(fieldNames, goName(f.Name)), g.goType(f), f.Name)
+		}
+		g.code.Print("}")
+	}
+}
+
+func (g *generator) generateClient() {
+	code := g.code
+	code.Print()
+	code.Print("// Client calls the methods of the %s API.", g.model.Name)
+	code.Print("type Client struct {")
+	code.Print(`// BaseURL is the URL that paths are appended to, such as "https://example.com/v1".`)
+	code.Print("BaseURL string")
+	code.Print("// HTTPClient sends requests. If it is nil, http.DefaultClient is used.")
+	code.Print("HTTPClient *http.Client")
+	code.Print("}")
+	code.Print()
+	code.Print("// NewClient returns a Client for the server at baseURL.")
+	code.Print("func NewClient(baseURL string) *Client {")
+	code.Print("return &Client{BaseURL: baseURL}")
+	code.Print("}")
+}
+
+// A parameter is a method parameter that is sent in a request.
+type parameter struct {
+	name      string // the name of the parameter in requests
+	fieldName string // the name of the field in the parameters structure
+	goType    string // the Go type of the (element of the) field
+	position  surface.Position
+	repeated  bool
+}
+
+// parameters returns the path, query, header, and form parameters of a
+// method, and the Go type of its request body (or "" if it has none).
+func (g *generator) parameters(m *surface.Method) (parameters []*parameter, bodyType string) {
+	t := g.types[m.ParametersTypeName]
+	if t == nil {
+		return nil, ""
+	}
+	fieldNames := map[string]bool{"Body": true}
+	for _, f := range t.Fields {
+		if f.Position == surface.Position_BODY {
+			if bodyType == "" {
+				bodyType = g.bodyType(f)
+			}
+			continue
+		}
+		// Parameters from components are references to types with one field.
+		if f.Kind == surface.FieldKind_REFERENCE {
+			if component := g.types[f.Type]; component != nil && len(component.Fields) == 1 {
+				f = component.Fields[0]
+			}
+		}
+		p := &parameter{
+			name:      f.Name,
+			fieldName: uniqueFieldName(fieldNames, goName(f.Name)),
+			position:  f.Position,
+			repeated:  f.Kind == surface.FieldKind_ARRAY,
+		}
+		if p.goType = scalarType(f.Type, f.Format); p.goType == "interface{}" {
+			// Parameters with structured values are sent as strings.
+			p.goType = "string"
+		}
+		parameters = append(parameters, p)
+	}
+	return parameters, bodyType
+}
+
+// bodyType returns the Go type of a request body. Request bodies with
+// several media types are encoded as JSON using the type of the JSON media type.
+func (g *generator) bodyType(f *surface.Field) string {
+	if f.Name != "request_body" || f.Kind != surface.FieldKind_REFERENCE {
+		return g.goType(f)
+	}
+	t := g.types[f.Type]
+	if t == nil || len(t.Fields) == 0 {
+		return "interface{}"
+	}
+	for _, mediaType := range t.Fields {
+		if isJSON(mediaType.Name) {
+			return g.goType(mediaType)
+		}
+	}
+	return g.goType(t.Fields[0])
+}
+
+// isNillable returns true for Go types whose zero value is nil.
+func (g *generator) isNillable(goType string) bool {
+	if strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") || goType == "interface{}" {
+		return true
+	}
+	for typeName, name := range g.typeNames {
+		if name == goType {
+			return g.types[typeName].Kind == surface.TypeKind_OBJECT
+		}
+	}
+	return false
+}
+
+// A responseVariant is a declared response of a method with a JSON body.
+type responseVariant struct {
+	fieldName string
+	status    string // the status code as specified in the API description
+	condition string // the condition on response.StatusCode, "" for default responses
+	bodyType  string
+}
+
+// responses returns the responses of a method that have JSON bodies, one
+// per status code, with specific status codes before ranges of status
+// codes and default responses last.
+func (g *generator) responses(m *surface.Method) []*responseVariant {
+	t := g.types[m.ResponsesTypeName]
+	if t == nil {
+		return nil
+	}
+	var codes, ranges, defaults []*responseVariant
+	seen := make(map[string]bool)
+	for _, f := range t.Fields {
+		// Fields are named with a status code and a media type, except for
+		// default responses which are named "default". References to
+		// responses in components have no names and are not included.
+		parts := strings.SplitN(f.Name, " ", 2)
+		status := parts[0]
+		if status == "" || seen[status] || (len(parts) == 2 && !isJSON(parts[1])) {
+			continue
+		}
+		seen[status] = true
+		v := &responseVariant{fieldName: goName("JSON " + status), status: status, bodyType: g.goType(f)}
+		if code, err := strconv.Atoi(status); err == nil {
+			v.condition = "response.StatusCode == " + strconv.Itoa(code)
+			codes = append(codes, v)
+		} else if len(status) == 3 && status[0] >= '1' && status[0] <= '5' && strings.ToUpper(status[1:]) == "XX" {
+			v.condition = "response.StatusCode/100 == " + status[:1]
+			ranges = append(ranges, v)
+		} else {
+			defaults = append(defaults, v)
+		}
+	}
+	return append(append(codes, ranges...), defaults...)
+}
+
+func (g *generator) generateMethod(m *surface.Method) {
+	code := g.code
+	name := goName(m.Name)
+	parameters, bodyType := g.parameters(m)
+	variants := g.responses(m)
+
+	code.Print()
+	code.Print("// %sParameters holds the parameters of %s.", name, name)
+	code.Print("type %sParameters struct {", name)
+	for _, p := range parameters {
+		switch {
+		case p.repeated:
+			code.Print("%s []%s // %s parameter %q", p.fieldName, p.goType, positionName(p.position), p.name)
+		case p.position == surface.Position_PATH:
+			code.Print("%s %s // path parameter %q", p.fieldName, p.goType, p.name)
+		default:
+			code.Print("%s *%s // %s parameter %q, omitted if it is nil", p.fieldName, p.goType, positionName(p.position), p.name)
+		}
+	}
+	if bodyType != "" {
+		code.Print("Body %s // the request body, encoded as JSON", bodyType)
+	}
+	code.Print("}")
+
+	code.Print()
+	code.Print("// %sResult holds a response of %s. The body of a response with", name, name)
+	code.Print("// a declared status code is decoded into the field for that status code.")
+	code.Print("type %sResult struct {", name)
+	code.Print("StatusCode int")
+	code.Print("Header http.Header")
+	code.Print("Body []byte // the undecoded body")
+	for _, v := range variants {
+		code.Print("%s %s // the body of %q responses", v.fieldName, v.bodyType, v.status)
+	}
+	code.Print("}")
+
+	code.Print()
+	g.printComment(name+" calls "+m.Method+" "+m.Path+".", m.Description)
+	if m.TimeoutMilliseconds > 0 {
+		timeout := time.Duration(m.TimeoutMilliseconds) * time.Millisecond
+		g.printComment("Calls time out after " + timeout.String() + " unless ctx has a deadline.")
+	}
+	if retries(m) {
+		g.printComment("Calls that fail with network errors or temporary server errors are retried.")
+	}
+	code.Print("func (c *Client) %s(ctx context.Context, parameters *%sParameters) (*%sResult, error) {", name, name, name)
+	if m.TimeoutMilliseconds > 0 {
+		code.Print("if _, ok := ctx.Deadline(); !ok {")
+		code.Print("var cancel context.CancelFunc")
+		code.Print("ctx, cancel = context.WithTimeout(ctx, %s)", durationExpression(m.TimeoutMilliseconds))
+		code.Print("defer cancel()")
+		code.Print("}")
+	}
+	if len(parameters) > 0 || bodyType != "" {
+		code.Print("if parameters == nil {")
+		code.Print("parameters = &%sParameters{}", name)
+		code.Print("}")
+	}
+	code.Print("r := newRequest(%q, %q)", m.Method, m.Path)
+	for _, p := range parameters {
+		g.generateParameterEncoding(p)
+	}
+	if bodyType != "" {
+		if g.isNillable(bodyType) {
+			code.Print("if parameters.Body != nil {")
+			code.Print("r.body = parameters.Body")
+			code.Print("}")
+		} else {
+			code.Print("r.body = parameters.Body")
+		}
+	}
+	if retries(m) {
+		code.Print("response, body, err := c.doWithRetries(ctx, r)")
+	} else {
+		code.Print("response, body, err := c.do(ctx, r)")
+	}
+	code.Print("if err != nil {")
+	code.Print("return nil, err")
+	code.Print("}")
+	code.Print("result := &%sResult{StatusCode: response.StatusCode, Header: response.Header, Body: body}", name)
+	if len(variants) > 0 {
+		code.Print("switch {")
+		for _, v := range variants {
+			if v.condition != "" {
+				code.Print("case %s:", v.condition)
+			} else {
+				code.Print("default:")
+			}
+			code.Print("err = decodeJSON(body, &result.%s)", v.fieldName)
+		}
+		code.Print("}")
+	}
+	code.Print("return result, err")
+	code.Print("}")
+}
+
+func (g *generator) generateParameterEncoding(p *parameter) {
+	code := g.code
+	var add string
+	switch p.position {
+	case surface.Position_PATH:
+		code.Print("r.path = strings.Replace(r.path, %q, url.PathEscape(fmt.Sprint(parameters.%s)), -1)", "{"+p.name+"}", p.fieldName)
+		return
+	case surface.Position_QUERY:
+		add = "r.query.Add"
+	case surface.Position_HEADER:
+		add = "r.header.Add"
+	default:
+		add = "r.form.Add"
+	}
+	if p.repeated {
+		code.Print("for _, v := range parameters.%s {", p.fieldName)
+		code.Print("%s(%q, fmt.Sprint(v))", add, p.name)
+	} else {
+		code.Print("if parameters.%s != nil {", p.fieldName)
+		code.Print("%s(%q, fmt.Sprint(*parameters.%s))", add, p.name, p.fieldName)
+	}
+	code.Print("}")
+}
+
+func (g *generator) printComment(lines ...string) {
+	for _, line := range lines {
+		for _, l := range strings.Split(strings.TrimSpace(line), "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				g.code.Print("// %s", l)
+			}
+		}
+	}
+}
+
+// goType returns the Go type of a field.
+func (g *generator) goType(f *surface.Field) string {
+	switch f.Kind {
+	case surface.FieldKind_SCALAR:
+		return scalarType(f.Type, f.Format)
+	case surface.FieldKind_REFERENCE:
+		return g.referenceType(f.Type)
+	case surface.FieldKind_ARRAY:
+		return "[]" + g.elementType(f.Type, f.Format)
+	case surface.FieldKind_MAP:
+		// Map types are written as "map[string]" followed by the value
+		// type, which may be an array and may be named by its format.
+		valueType := strings.TrimPrefix(f.Type, "map[string]")
+		prefix := ""
+		if strings.HasPrefix(valueType, "[]") {
+			prefix, valueType = "[]", strings.TrimPrefix(valueType, "[]")
+		}
+		if formatType, ok := formatTypes[valueType]; ok {
+			return "map[string]" + prefix + formatType
+		}
+		return "map[string]" + prefix + g.elementType(valueType, "")
+	}
+	return "interface{}"
+}
+
+func (g *generator) elementType(typeName, format string) string {
+	if scalar := scalarType(typeName, format); scalar != "interface{}" {
+		return scalar
+	}
+	return g.referenceType(typeName)
+}
+
+func (g *generator) referenceType(typeName string) string {
+	name, ok := g.typeNames[typeName]
+	if !ok {
+		return "interface{}"
+	}
+	if g.types[typeName].Kind == surface.TypeKind_OBJECT {
+		return name
+	}
+	return "*" + name
+}
+
+var formatTypes = map[string]string{
+	"int32":       "int32",
+	"int64":       "int64",
+	"float":       "float32",
+	"double":      "float64",
+	"interface{}": "interface{}",
+}
+
+// scalarType returns the Go type of an OpenAPI type and format.
+func scalarType(typeName, format string) string {
+	switch typeName {
+	case "integer":
+		if format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "string":
+		return "string"
+	}
+	return "interface{}"
+}
+
+func positionName(position surface.Position) string {
+	switch position {
+	case surface.Position_PATH:
+		return "path"
+	case surface.Position_QUERY:
+		return "query"
+	case surface.Position_HEADER:
+		return "header"
+	}
+	return "form"
+}
+
+func isJSON(mediaType string) bool {
+	return strings.Contains(mediaType, "json") || mediaType == "*/*"
+}
+
+// goName returns an exported Go identifier for a name from an API description.
+func goName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	result := strings.Join(parts, "")
+	if result == "" || unicode.IsDigit(rune(result[0])) {
+		result = "X" + result
+	}
+	return result
+}
+
+func uniqueFieldName(used map[string]bool, name string) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// clientSupport is included in every generated file.
+const clientSupport = `
+// A request is a request that is being built by a method of Client.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	form   url.Values
+	body   interface{} // encoded as JSON if it is not nil
+}
+
+func newRequest(method, path string) *request {
+	return &request{
+		method: method,
+		path:   path,
+		query:  make(url.Values),
+		header: make(http.Header),
+		form:   make(url.Values),
+	}
+}
+
+// do sends a request and reads its response.
+func (c *Client) do(ctx context.Context, r *request) (*http.Response, []byte, error) {
+	var body bytes.Buffer
+	contentType := ""
+	if r.body != nil {
+		if err := json.NewEncoder(&body).Encode(r.body); err != nil {
+			return nil, nil, fmt.Errorf("encoding request body: %v", err)
+		}
+		contentType = "application/json"
+	} else if len(r.form) > 0 {
+		body.WriteString(r.form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	}
+	target := strings.TrimRight(c.BaseURL, "/") + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+	request, err := http.NewRequest(r.method, target, &body)
+	if err != nil {
+		return nil, nil, err
+	}
+	request = request.WithContext(ctx)
+	for name, values := range r.header {
+		request.Header[name] = values
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	if request.Header.Get("Accept") == "" {
+		request.Header.Set("Accept", "application/json")
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, data, nil
+}
+
+// decodeJSON decodes a response body. Empty bodies are not decoded.
+func decodeJSON(body []byte, v interface{}) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decoding response body: %v", err)
+	}
+	return nil
+}
+`
+
+// retrySupport is included in files with methods that are retried.
+const retrySupport = `
+// retryAttempts is the number of times that a call of a retryable method
+// is attempted.
+const retryAttempts = 3
+
+// doWithRetries sends a request of a retryable and idempotent method until
+// it gets a response that isn't retryable, the context is done, or the
+// request was sent retryAttempts times. Attempts are separated by
+// exponentially increasing delays.
+func (c *Client) doWithRetries(ctx context.Context, r *request) (*http.Response, []byte, error) {
+	delay := 100 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		response, body, err := c.do(ctx, r)
+		if attempt == retryAttempts || !isRetryable(response, err) {
+			return response, body, err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response, body, err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// isRetryable returns true for network errors and for status codes that
+// indicate temporary failures.
+func isRetryable(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+`
@@ -0,0 +1,907 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/google/gnostic/printer"
+	surface "github.com/google/gnostic/surface"
+)
+
+// A generator writes server code for a surface model.
+type generator struct {
+	model       *surface.Model
+	packageName string
+	code        *printer.Code
+	types       map[string]*surface.Type // model types by name
+	typeNames   map[string]string        // Go names of model types
+	usedNames   map[string]bool          // Go names of top-level declarations
+	multipart   bool                     // true if a method accepts multipart request bodies
+}
+
+// generateServer returns the formatted source of a Go file that contains
+// a Service interface for the methods of a model and a RegisterHandlers
+// function that routes requests to implementations of that interface.
+func generateServer(model *surface.Model, packageName string) ([]byte, error) {
+	g := &generator{
+		model:       model,
+		packageName: packageName,
+		code:        &printer.Code{},
+		types:       make(map[string]*surface.Type),
+		typeNames:   make(map[string]string),
+		usedNames:   make(map[string]bool),
+	}
+	for _, name := range []string{"Service", "RegisterHandlers"} {
+		g.usedNames[name] = true
+	}
+	for _, m := range model.Methods {
+		name := goName(m.Name)
+		for _, suffix := range []string{"", "Parameters", "Responses"} {
+			g.usedNames[name+suffix] = true
+		}
+	}
+	for _, t := range model.Types {
+		g.types[t.Name] = t
+	}
+	for _, m := range model.Methods {
+		if g.multipartBody(m) != nil {
+			g.multipart = true
+			g.usedNames["Part"] = true
+		}
+	}
+	for _, t := range model.Types {
+		if g.isMessageType(t) {
+			g.typeNames[t.Name] = g.uniqueName(goName(t.Name))
+		}
+	}
+	g.generate()
+	return format.Source([]byte(g.code.String()))
+}
+
+// isMessageType returns true for types that are generated as Go types.
+// Parameter, response, and request body types of methods are replaced
+// by the request structures and response unions of the methods, and
+// types that hold parameters from components are inlined where they are used.
+func (g *generator) isMessageType(t *surface.Type) bool {
+	for _, m := range g.model.Methods {
+		if t.Name == m.ParametersTypeName || t.Name == m.ResponsesTypeName {
+			return false
+		}
+		if parameters := g.types[m.ParametersTypeName]; parameters != nil {
+			for _, f := range parameters.Fields {
+				if f.Position == surface.Position_BODY && f.Name == "request_body" && f.Type == t.Name {
+					return false
+				}
+			}
+		}
+	}
+	if len(t.Fields) == 0 {
+		return true
+	}
+	for _, f := range t.Fields {
+		if f.Position == surface.Position_BODY {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) uniqueName(name string) string {
+	unique := name
+	for i := 2; g.usedNames[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	g.usedNames[unique] = true
+	return unique
+}
+
+func (g *generator) generate() {
+	code := g.code
+	code.Print("// Code generated by gnostic-go-server. DO NOT EDIT.")
+	code.Print()
+	code.Print("package %s", g.packageName)
+	code.Print()
+	code.Print("import (")
+	code.PrintIf(len(g.model.Methods) > 0, `"context"`)
+	for _, name := range []string{"encoding/json", "fmt", "io"} {
+		code.Print("%q", name)
+	}
+	code.PrintIf(g.multipart, `"mime"`)
+	code.Print(`"net/http"`)
+	code.PrintIf(g.multipart, `"net/textproto"`)
+	for _, name := range []string{"net/url", "strconv", "strings"} {
+		code.Print("%q", name)
+	}
+	code.Print(")")
+	g.generateTypes()
+	g.generateService()
+	for _, m := range g.model.Methods {
+		g.generateMethod(m)
+	}
+	g.generateRegisterHandlers()
+	code.Print("%s", serverSupport)
+	if g.multipart {
+		code.Print("%s", multipartSupport)
+	}
+}
+
+func (g *generator) generateTypes() {
+	for _, t := range g.model.Types {
+		name, ok := g.typeNames[t.Name]
+		if !ok {
+			continue
+		}
+		g.code.Print()
+		g.printComment(name+" is the "+t.Name+" type of the API.", t.Description)
+		if t.Kind == surface.TypeKind_OBJECT {
+			contentType := t.ContentType
+			if contentType == "" {
+				contentType = "interface{}"
+			}
+			g.code.Print("type %s map[string]%s", name, contentType)
+			continue
+		}
+		g.code.Print("type %s struct {", name)
+		fieldNames := make(map[string]bool)
+		for _, f := range t.Fields {
+			g.code.Print("%s %s `json:\"%s,omitempty\"`", uniqueFieldName(fieldNames, goName(f.Name)), g.goType(f), f.Name)
+		}
+		g.code.Print("}")
+	}
+}
+
+func (g *generator) generateService() {
+	g.code.Print()
+	g.code.Print("// Service is implemented by servers of the %s API.", g.model.Name)
+	g.code.Print("// Each method returns one of the responses declared for its operation;")
+	g.code.Print("// errors are reported to clients as internal server errors.")
+	g.code.Print("type Service interface {")
+	for _, m := range g.model.Methods {
+		name := goName(m.Name)
+		g.printComment(name+" handles "+m.Method+" "+m.Path+".", m.Description)
+		g.code.Print("%s(ctx context.Context, parameters *%sParameters) (%sResponses, error)", name, name, name)
+	}
+	g.code.Print("}")
+}
+
+// A parameter is a method parameter that is read from a request.
+type parameter struct {
+	name       string // the name of the parameter in requests
+	fieldName  string // the name of the field in the parameters structure
+	goType     string // the Go type of the (element of the) field
+	position   surface.Position
+	repeated   bool
+	enumValues []string
+}
+
+// parameters returns the path, query, header, and form parameters of a
+// method, and the Go type of its request body (or "" if it has none).
+func (g *generator) parameters(m *surface.Method) (parameters []*parameter, bodyType string) {
+	t := g.types[m.ParametersTypeName]
+	if t == nil {
+		return nil, ""
+	}
+	fieldNames := map[string]bool{"Body": true, "Parts": g.multipartBody(m) != nil}
+	for _, f := range t.Fields {
+		if f.Position == surface.Position_BODY {
+			if bodyType == "" {
+				bodyType = g.bodyType(f)
+			}
+			continue
+		}
+		// Parameters from components are references to types with one field.
+		if f.Kind == surface.FieldKind_REFERENCE {
+			if component := g.types[f.Type]; component != nil && len(component.Fields) == 1 {
+				f = component.Fields[0]
+			}
+		}
+		p := &parameter{
+			name:       f.Name,
+			fieldName:  uniqueFieldName(fieldNames, goName(f.Name)),
+			position:   f.Position,
+			repeated:   f.Kind == surface.FieldKind_ARRAY,
+			enumValues: f.EnumValues,
+		}
+		p.goType = scalarType(f.Type, f.Format)
+		if parseFunctions[p.goType] == "" {
+			// Parameters with structured values are read as strings.
+			p.goType = "string"
+		}
+		parameters = append(parameters, p)
+	}
+	return parameters, bodyType
+}
+
+// bodyType returns the Go type of a request body. Request bodies with
+// several media types are decoded as JSON using the type of the JSON media type.
+// Multipart media types are read as parts (see multipartBody), so request
+// bodies that only have multipart media types have no Go type.
+func (g *generator) bodyType(f *surface.Field) string {
+	if f.Name != "request_body" || f.Kind != surface.FieldKind_REFERENCE {
+		return g.goType(f)
+	}
+	t := g.types[f.Type]
+	if t == nil || len(t.Fields) == 0 {
+		return "interface{}"
+	}
+	var mediaTypes []*surface.Field
+	for _, mediaType := range t.Fields {
+		if !isMultipart(mediaType.Name) {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		return ""
+	}
+	for _, mediaType := range mediaTypes {
+		if isJSON(mediaType.Name) {
+			return g.goType(mediaType)
+		}
+	}
+	return g.goType(mediaTypes[0])
+}
+
+// multipartBody returns the multipart media type of the request body of
+// a method with the declared parts of the body, or nil if there is none.
+func (g *generator) multipartBody(m *surface.Method) *surface.Field {
+	t := g.types[m.ParametersTypeName]
+	if t == nil {
+		return nil
+	}
+	for _, f := range t.Fields {
+		if f.Position != surface.Position_BODY || f.Name != "request_body" || f.Kind != surface.FieldKind_REFERENCE {
+			continue
+		}
+		if body := g.types[f.Type]; body != nil {
+			for _, mediaType := range body.Fields {
+				if isMultipart(mediaType.Name) {
+					return mediaType
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// A responseVariant is a declared response of a method.
+type responseVariant struct {
+	typeName   string
+	statusCode int    // 0 if the status code is set by the implementation
+	status     string // the status code as specified in the API description
+	bodyType   string
+}
+
+// responses returns the responses of a method that have JSON bodies,
+// one per status code.
+func (g *generator) responses(m *surface.Method) []*responseVariant {
+	t := g.types[m.ResponsesTypeName]
+	if t == nil {
+		return nil
+	}
+	var variants []*responseVariant
+	seen := make(map[string]bool)
+	for _, f := range t.Fields {
+		// Fields are named with a status code and a media type, except for
+		// default responses which are named "default". References to
+		// responses in components have no names and are not included.
+		parts := strings.SplitN(f.Name, " ", 2)
+		status := parts[0]
+		if status == "" || seen[status] || (len(parts) == 2 && !isJSON(parts[1])) {
+			continue
+		}
+		seen[status] = true
+		v := &responseVariant{status: status, bodyType: g.goType(f)}
+		v.statusCode, _ = strconv.Atoi(status)
+		v.typeName = g.uniqueName(goName(m.Name + " " + status + " response"))
+		variants = append(variants, v)
+	}
+	return variants
+}
+
+func (g *generator) generateMethod(m *surface.Method) {
+	code := g.code
+	name := goName(m.Name)
+	parameters, bodyType := g.parameters(m)
+	multipartBody := g.multipartBody(m)
+
+	code.Print()
+	code.Print("// %sParameters holds the parameters of %s.", name, name)
+	code.Print("type %sParameters struct {", name)
+	for _, p := range parameters {
+		switch {
+		case p.repeated:
+			code.Print("%s []%s // %s parameter %q", p.fieldName, p.goType, positionName(p.position), p.name)
+		case p.position == surface.Position_PATH:
+			code.Print("%s %s // path parameter %q", p.fieldName, p.goType, p.name)
+		default:
+			code.Print("%s *%s // %s parameter %q, nil if it is absent", p.fieldName, p.goType, positionName(p.position), p.name)
+		}
+	}
+	if bodyType != "" {
+		code.Print("Body %s // the decoded request body", bodyType)
+	}
+	if multipartBody != nil {
+		code.Print("Parts map[string][]*Part // the parts of a multipart request body by name")
+	}
+	code.Print("}")
+
+	code.Print()
+	code.Print("// %sResponses is implemented by the responses of %s.", name, name)
+	code.Print("type %sResponses interface {", name)
+	code.Print("response")
+	code.Print("is%sResponses()", name)
+	code.Print("}")
+
+	variants := g.responses(m)
+	for _, v := range variants {
+		code.Print()
+		if v.statusCode != 0 {
+			code.Print("// %s is the %s response of %s.", v.typeName, v.status, name)
+		} else {
+			code.Print("// %s is the %s response of %s.", v.typeName, strconv.Quote(v.status), name)
+		}
+		code.Print("type %s struct {", v.typeName)
+		if v.statusCode == 0 {
+			code.Print("StatusCode int // defaults to 500")
+		}
+		code.Print("Body %s", v.bodyType)
+		code.Print("}")
+		code.Print()
+		code.Print("func (*%s) is%sResponses() {}", v.typeName, name)
+		code.Print()
+		code.Print("func (r *%s) writeResponse(w http.ResponseWriter) error {", v.typeName)
+		if v.statusCode != 0 {
+			code.Print("return writeJSON(w, %d, r.Body)", v.statusCode)
+		} else {
+			code.Print("return writeJSON(w, statusCodeOrDefault(r.StatusCode, http.StatusInternalServerError), r.Body)")
+		}
+		code.Print("}")
+	}
+
+	// Responses without bodies aren't recorded in surface models,
+	// so every method can return a status code without a body.
+	statusTypeName := g.uniqueName(name + "StatusResponse")
+	code.Print()
+	code.Print("// %s is a response of %s without a body.", statusTypeName, name)
+	code.Print("type %s struct {", statusTypeName)
+	code.Print("StatusCode int // defaults to 204")
+	code.Print("}")
+	code.Print()
+	code.Print("func (*%s) is%sResponses() {}", statusTypeName, name)
+	code.Print()
+	code.Print("func (r *%s) writeResponse(w http.ResponseWriter) error {", statusTypeName)
+	code.Print("w.WriteHeader(statusCodeOrDefault(r.StatusCode, http.StatusNoContent))")
+	code.Print("return nil")
+	code.Print("}")
+
+	code.Print()
+	code.Print("func (s *server) handle%s(w http.ResponseWriter, r *http.Request, pathParameters map[string]string) {", name)
+	code.Print("parameters := &%sParameters{}", name)
+	for _, p := range parameters {
+		g.generateParameterDecoding(p)
+	}
+	switch {
+	case multipartBody != nil && bodyType != "":
+		code.Print("if isMultipart(r) {")
+		g.generatePartsReading(multipartBody)
+		code.Print("} else if err := decodeBody(r, &parameters.Body); err != nil {")
+		code.Print(`http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)`)
+		code.Print("return")
+		code.Print("}")
+	case multipartBody != nil:
+		g.generatePartsReading(multipartBody)
+	case bodyType != "":
+		code.Print("if err := decodeBody(r, &parameters.Body); err != nil {")
+		code.Print(`http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)`)
+		code.Print("return")
+		code.Print("}")
+	}
+	code.Print("responses, err := s.impl.%s(r.Context(), parameters)", name)
+	code.Print("writeResponse(w, responses, err)")
+	code.Print("}")
+}
+
+func (g *generator) generateParameterDecoding(p *parameter) {
+	code := g.code
+	var values string
+	switch p.position {
+	case surface.Position_PATH:
+		values = "[]string{pathParameters[%q]}"
+	case surface.Position_QUERY:
+		values = "r.URL.Query()[%q]"
+	case surface.Position_HEADER:
+		values = "r.Header[http.CanonicalHeaderKey(%q)]"
+	default:
+		values = "formValues(r, %q)"
+	}
+	values = strings.Replace(values, "%q", strconv.Quote(p.name), 1)
+	code.Print("for _, value := range %s {", values)
+	if len(p.enumValues) > 0 {
+		enumValues := make([]string, len(p.enumValues))
+		for i, v := range p.enumValues {
+			if unquoted, err := strconv.Unquote(v); err == nil {
+				v = unquoted
+			} else {
+				v = strings.Trim(v, "'")
+			}
+			enumValues[i] = strconv.Quote(v)
+		}
+		code.Print("if err := checkEnum(value, %s); err != nil {", strings.Join(enumValues, ", "))
+		code.Print("writeParameterError(w, %q, %q, err)", positionName(p.position), p.name)
+		code.Print("return")
+		code.Print("}")
+	}
+	code.Print("v, err := %s(value)", parseFunctions[p.goType])
+	code.Print("if err != nil {")
+	code.Print("writeParameterError(w, %q, %q, err)", positionName(p.position), p.name)
+	code.Print("return")
+	code.Print("}")
+	switch {
+	case p.repeated:
+		code.Print("parameters.%s = append(parameters.%s, v)", p.fieldName, p.fieldName)
+	case p.position == surface.Position_PATH:
+		code.Print("parameters.%s = v", p.fieldName)
+	default:
+		code.Print("parameters.%s = &v", p.fieldName)
+		code.Print("break")
+	}
+	code.Print("}")
+}
+
+// generatePartsReading reads the parts of a multipart request body and
+// checks them against the declared parts of the body.
+func (g *generator) generatePartsReading(mediaType *surface.Field) {
+	code := g.code
+	code.Print("parts, err := readParts(r,")
+	for _, part := range mediaType.Parts {
+		var contentTypes []string
+		for _, contentType := range strings.Split(part.ContentType, ",") {
+			if contentType = strings.TrimSpace(contentType); contentType != "" {
+				contentTypes = append(contentTypes, strconv.Quote(contentType))
+			}
+		}
+		code.Print("partSpec{name: %q, contentTypes: []string{%s}, required: %t},", part.Name, strings.Join(contentTypes, ", "), part.Required)
+	}
+	code.Print(")")
+	code.Print("if err != nil {")
+	code.Print(`http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)`)
+	code.Print("return")
+	code.Print("}")
+	code.Print("parameters.Parts = parts")
+}
+
+func (g *generator) generateRegisterHandlers() {
+	code := g.code
+	// Methods are grouped by the ServeMux patterns that match their paths.
+	patterns := make(map[string][]*surface.Method)
+	for _, m := range g.model.Methods {
+		pattern := servePattern(m.Path)
+		patterns[pattern] = append(patterns[pattern], m)
+	}
+	keys := make([]string, 0, len(patterns))
+	for k := range patterns {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	code.Print()
+	code.Print("// RegisterHandlers registers handlers for the methods of impl with mux.")
+	code.Print("// Requests are routed by method and path; path, query, and header")
+	code.Print("// parameters are decoded and validated and request bodies are decoded")
+	code.Print("// from JSON before impl is called.")
+	if g.multipart {
+		code.Print("// Multipart request bodies are read into parts, which are checked for")
+		code.Print("// required parts and allowed content types.")
+	}
+	code.Print("func RegisterHandlers(mux *http.ServeMux, impl Service) {")
+	code.Print("s := &server{impl: impl}")
+	for _, pattern := range keys {
+		code.Print("mux.HandleFunc(%q, routeHandler(", pattern)
+		for _, m := range patterns[pattern] {
+			code.Print("newRoute(%q, %q, s.handle%s),", m.Method, m.Path, goName(m.Name))
+		}
+		code.Print("))")
+	}
+	code.Print("}")
+}
+
+func (g *generator) printComment(lines ...string) {
+	for _, line := range lines {
+		for _, l := range strings.Split(strings.TrimSpace(line), "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				g.code.Print("// %s", l)
+			}
+		}
+	}
+}
+
+// goType returns the Go type of a field.
+func (g *generator) goType(f *surface.Field) string {
+	switch f.Kind {
+	case surface.FieldKind_SCALAR:
+		return scalarType(f.Type, f.Format)
+	case surface.FieldKind_REFERENCE:
+		return g.referenceType(f.Type)
+	case surface.FieldKind_ARRAY:
+		return "[]" + g.elementType(f.Type, f.Format)
+	case surface.FieldKind_MAP:
+		// Map types are written as "map[string]" followed by the value
+		// type, which may be an array and may be named by its format.
+		valueType := strings.TrimPrefix(f.Type, "map[string]")
+		prefix := ""
+		if strings.HasPrefix(valueType, "[]") {
+			prefix, valueType = "[]", strings.TrimPrefix(valueType, "[]")
+		}
+		if formatType, ok := formatTypes[valueType]; ok {
+			return "map[string]" + prefix + formatType
+		}
+		return "map[string]" + prefix + g.elementType(valueType, "")
+	}
+	return "interface{}"
+}
+
+func (g *generator) elementType(typeName, format string) string {
+	if scalar := scalarType(typeName, format); scalar != "interface{}" {
+		return scalar
+	}
+	return g.referenceType(typeName)
+}
+
+func (g *generator) referenceType(typeName string) string {
+	name, ok := g.typeNames[typeName]
+	if !ok {
+		return "interface{}"
+	}
+	if g.types[typeName].Kind == surface.TypeKind_OBJECT {
+		return name
+	}
+	return "*" + name
+}
+
+var formatTypes = map[string]string{
+	"int32":       "int32",
+	"int64":       "int64",
+	"float":       "float32",
+	"double":      "float64",
+	"interface{}": "interface{}",
+}
+
+// scalarType returns the Go type of an OpenAPI type and format.
+func scalarType(typeName, format string) string {
+	switch typeName {
+	case "integer":
+		if format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "string":
+		return "string"
+	}
+	return "interface{}"
+}
+
+// parseFunctions are the names of the generated functions
+// that parse parameter values of each Go type.
+var parseFunctions = map[string]string{
+	"bool":    "strconv.ParseBool",
+	"int32":   "parseInt32",
+	"int64":   "parseInt64",
+	"float32": "parseFloat32",
+	"float64": "parseFloat64",
+	"string":  "parseString",
+}
+
+func positionName(position surface.Position) string {
+	switch position {
+	case surface.Position_PATH:
+		return "path"
+	case surface.Position_QUERY:
+		return "query"
+	case surface.Position_HEADER:
+		return "header"
+	}
+	return "form"
+}
+
+func isJSON(mediaType string) bool {
+	return strings.Contains(mediaType, "json") || mediaType == "*/*"
+}
+
+func isMultipart(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "multipart/")
+}
+
+// servePattern returns the ServeMux pattern that matches the paths of a
+// path template: the template itself if it has no parameters, otherwise
+// the subtree that contains the first parameter.
+func servePattern(path string) string {
+	i := strings.Index(path, "{")
+	if i < 0 {
+		return path
+	}
+	return path[:strings.LastIndex(path[:i], "/")+1]
+}
+
+// goName returns an exported Go identifier for a name from an API description.
+func goName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	result := strings.Join(parts, "")
+	if result == "" || unicode.IsDigit(rune(result[0])) {
+		result = "X" + result
+	}
+	return result
+}
+
+func uniqueFieldName(used map[string]bool, name string) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// serverSupport is included in every generated file.
+const serverSupport = `
+type server struct {
+	impl Service
+}
+
+// A response can be written to clients.
+type response interface {
+	writeResponse(w http.ResponseWriter) error
+}
+
+func writeResponse(w http.ResponseWriter, r response, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if r == nil {
+		http.Error(w, "no response", http.StatusInternalServerError)
+		return
+	}
+	r.writeResponse(w)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	return json.NewEncoder(w).Encode(body)
+}
+
+func statusCodeOrDefault(statusCode, defaultStatusCode int) int {
+	if statusCode == 0 {
+		return defaultStatusCode
+	}
+	return statusCode
+}
+
+func writeParameterError(w http.ResponseWriter, position, name string, err error) {
+	http.Error(w, fmt.Sprintf("invalid %s parameter %q: %s", position, name, err), http.StatusBadRequest)
+}
+
+// decodeBody decodes a JSON request body. Empty bodies are allowed.
+func decodeBody(r *http.Request, v interface{}) error {
+	if r.Body == nil {
+		return nil
+	}
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+func formValues(r *http.Request, name string) []string {
+	if err := r.ParseForm(); err != nil {
+		return nil
+	}
+	return r.PostForm[name]
+}
+
+func checkEnum(value string, values ...string) error {
+	for _, v := range values {
+		if value == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not one of %s", value, strings.Join(values, ", "))
+}
+
+func parseString(value string) (string, error) {
+	return value, nil
+}
+
+func parseInt32(value string) (int32, error) {
+	v, err := strconv.ParseInt(value, 10, 32)
+	return int32(v), err
+}
+
+func parseInt64(value string) (int64, error) {
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func parseFloat32(value string) (float32, error) {
+	v, err := strconv.ParseFloat(value, 32)
+	return float32(v), err
+}
+
+func parseFloat64(value string) (float64, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+// A route matches requests with a method and a path template.
+type route struct {
+	method   string
+	segments []string
+	handle   func(w http.ResponseWriter, r *http.Request, pathParameters map[string]string)
+}
+
+func newRoute(method, path string, handle func(w http.ResponseWriter, r *http.Request, pathParameters map[string]string)) *route {
+	return &route{method: method, segments: strings.Split(path, "/"), handle: handle}
+}
+
+// match returns the path parameters of an escaped request path
+// if it matches the route's path template.
+func (rt *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	parameters := make(map[string]string)
+	for i, s := range rt.segments {
+		open, close := strings.Index(s, "{"), strings.LastIndex(s, "}")
+		if open < 0 || close < open {
+			if s != segments[i] {
+				return nil, false
+			}
+			continue
+		}
+		prefix, suffix := s[:open], s[close+1:]
+		segment := segments[i]
+		if len(segment) <= len(prefix)+len(suffix) || !strings.HasPrefix(segment, prefix) || !strings.HasSuffix(segment, suffix) {
+			return nil, false
+		}
+		value, err := url.PathUnescape(segment[len(prefix) : len(segment)-len(suffix)])
+		if err != nil {
+			return nil, false
+		}
+		parameters[s[open+1:close]] = value
+	}
+	return parameters, true
+}
+
+// routeHandler returns a handler that calls the first route that
+// matches a request. Requests with paths that match routes for other
+// methods are rejected with 405 (Method Not Allowed).
+func routeHandler(routes ...*route) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		segments := strings.Split(r.URL.EscapedPath(), "/")
+		var allowed []string
+		for _, rt := range routes {
+			parameters, ok := rt.match(segments)
+			if !ok {
+				continue
+			}
+			if rt.method == r.Method {
+				rt.handle(w, r, parameters)
+				return
+			}
+			allowed = append(allowed, rt.method)
+		}
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		http.NotFound(w, r)
+	}
+}`
+
+// multipartSupport is included in files with methods that accept
+// multipart request bodies.
+const multipartSupport = `
+// A Part is a part of a multipart request body.
+type Part struct {
+	ContentType string // the content type of the part, text/plain if it has none
+	Filename    string // the file name of the part, "" if it has none
+	Header      textproto.MIMEHeader
+	Content     []byte
+}
+
+// A partSpec is a declared part of a multipart request body.
+type partSpec struct {
+	name         string
+	contentTypes []string // media ranges like image/png or image/*
+	required     bool
+}
+
+func isMultipart(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && strings.HasPrefix(mediaType, "multipart/")
+}
+
+// readParts reads the parts of a multipart request body by name and checks
+// that the required parts are present and that the declared parts have
+// allowed content types. Parts that aren't declared aren't checked.
+func readParts(r *http.Request, specs ...partSpec) (map[string][]*Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	parts := make(map[string][]*Part)
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(p)
+		if err != nil {
+			return nil, err
+		}
+		contentType := p.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = "text/plain"
+		}
+		parts[p.FormName()] = append(parts[p.FormName()], &Part{ContentType: contentType, Filename: p.FileName(), Header: p.Header, Content: content})
+	}
+	for _, spec := range specs {
+		if len(parts[spec.name]) == 0 && spec.required {
+			return nil, fmt.Errorf("missing part %q", spec.name)
+		}
+		for _, p := range parts[spec.name] {
+			if !matchContentType(p.ContentType, spec.contentTypes) {
+				return nil, fmt.Errorf("part %q has content type %q, expected %s", spec.name, p.ContentType, strings.Join(spec.contentTypes, " or "))
+			}
+		}
+	}
+	return parts, nil
+}
+
+// matchContentType returns true if a content type matches one of some media ranges.
+func matchContentType(contentType string, mediaRanges []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, mediaRange := range mediaRanges {
+		mediaRange = strings.ToLower(mediaRange)
+		if mediaRange == "*/*" || mediaRange == mediaType ||
+			(strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*"))) {
+			return true
+		}
+	}
+	return false
+}`
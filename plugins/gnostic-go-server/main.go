@@ -0,0 +1,80 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnostic-go-server is a plugin that generates net/http server stubs
+// for an API from its surface model.
+//
+// The generated file contains a Service interface with one method per
+// operation and a RegisterHandlers function that routes requests to an
+// implementation of Service.
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+
+	plugins "github.com/google/gnostic/plugins"
+	surface "github.com/google/gnostic/surface"
+)
+
+// This is the main function for the plugin.
+func main() {
+	env, err := plugins.NewEnvironment()
+	env.RespondAndExitIfError(err)
+
+	// The package name can be set with a "package" parameter and
+	// defaults to the name of the output directory.
+	packageName := filepath.Base(env.Request.OutputPath)
+	for _, parameter := range env.Request.Parameters {
+		if parameter.Name == "package" {
+			packageName = parameter.Value
+		}
+	}
+	if packageName = goPackageName(packageName); packageName == "" {
+		packageName = "server"
+	}
+
+	for _, model := range env.Request.Models {
+		if model.TypeUrl != "surface.v1.Model" {
+			continue
+		}
+		surfaceModel := &surface.Model{}
+		err = proto.Unmarshal(model.Value, surfaceModel)
+		env.RespondAndExitIfError(err)
+		data, err := generateServer(surfaceModel, packageName)
+		env.RespondAndExitIfError(err)
+		env.Response.Files = append(env.Response.Files, &plugins.File{
+			Name: "server.go",
+			Data: data,
+		})
+	}
+
+	env.RespondAndExit()
+}
+
+// goPackageName returns a valid Go package name for a name, or "" if
+// the name contains no letters.
+func goPackageName(name string) string {
+	result := make([]rune, 0, len(name))
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9' && len(result) > 0:
+			result = append(result, r)
+		case r >= 'A' && r <= 'Z':
+			result = append(result, r-'A'+'a')
+		}
+	}
+	return string(result)
+}
@@ -0,0 +1,295 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	surface "github.com/google/gnostic/surface"
+)
+
+// bookstoreTest is compiled and run with the code generated for the bookstore sample.
+const bookstoreTest = `package bookstore
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type service struct{}
+
+func (service) ListShelves(ctx context.Context, parameters *ListShelvesParameters) (ListShelvesResponses, error) {
+	return &ListShelves200Response{Body: &ListShelvesResponse{Shelves: []*Shelf{{Name: "fiction", Theme: "Fiction"}}}}, nil
+}
+
+func (service) CreateShelf(ctx context.Context, parameters *CreateShelfParameters) (CreateShelfResponses, error) {
+	return &CreateShelf200Response{Body: parameters.Body}, nil
+}
+
+func (service) DeleteShelves(ctx context.Context, parameters *DeleteShelvesParameters) (DeleteShelvesResponses, error) {
+	return &DeleteShelvesStatusResponse{}, nil
+}
+
+func (service) GetShelf(ctx context.Context, parameters *GetShelfParameters) (GetShelfResponses, error) {
+	if parameters.Shelf != 1 {
+		return &GetShelfDefaultResponse{StatusCode: http.StatusNotFound, Body: &Error{Code: 404, Message: "not found"}}, nil
+	}
+	return &GetShelf200Response{Body: &Shelf{Name: "fiction", Theme: "Fiction"}}, nil
+}
+
+func (service) DeleteShelf(ctx context.Context, parameters *DeleteShelfParameters) (DeleteShelfResponses, error) {
+	return nil, fmt.Errorf("shelf %d is locked", parameters.Shelf)
+}
+
+func (service) ListBooks(ctx context.Context, parameters *ListBooksParameters) (ListBooksResponses, error) {
+	title := fmt.Sprintf("%d", parameters.Shelf)
+	if parameters.Limit != nil {
+		title += fmt.Sprintf(" limit=%d", *parameters.Limit)
+	}
+	if parameters.Order != nil {
+		title += " order=" + *parameters.Order
+	}
+	if parameters.AcceptLanguage != nil {
+		title += " language=" + *parameters.AcceptLanguage
+	}
+	return &ListBooks200Response{Body: &ListBooksResponse{Books: []*Book{{Title: title}}}}, nil
+}
+
+func (service) CreateBook(ctx context.Context, parameters *CreateBookParameters) (CreateBookResponses, error) {
+	return &CreateBook200Response{Body: parameters.Body}, nil
+}
+
+func (service) GetBook(ctx context.Context, parameters *GetBookParameters) (GetBookResponses, error) {
+	return &GetBook200Response{Body: &Book{Name: fmt.Sprintf("shelves/%d/books/%d", parameters.Shelf, parameters.Book)}}, nil
+}
+
+func (service) DeleteBook(ctx context.Context, parameters *DeleteBookParameters) (DeleteBookResponses, error) {
+	return &DeleteBookStatusResponse{StatusCode: http.StatusAccepted}, nil
+}
+
+func TestHandlers(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterHandlers(mux, service{})
+	for _, test := range []struct {
+		method, path, header, body string
+		code                       int
+		response                   string
+	}{
+		{"GET", "/shelves", "", "", 200, ` + "`" + `{"shelves":[{"name":"fiction","theme":"Fiction"}]}` + "`" + `},
+		{"POST", "/shelves", "", ` + "`" + `{"name":"poetry","theme":"Poetry"}` + "`" + `, 200, ` + "`" + `{"name":"poetry","theme":"Poetry"}` + "`" + `},
+		{"POST", "/shelves", "", "{", 400, "invalid request body: unexpected EOF"},
+		{"DELETE", "/shelves", "", "", 204, ""},
+		{"GET", "/shelves/1", "", "", 200, ` + "`" + `{"name":"fiction","theme":"Fiction"}` + "`" + `},
+		{"GET", "/shelves/2", "", "", 404, ` + "`" + `{"code":404,"message":"not found"}` + "`" + `},
+		{"GET", "/shelves/x", "", "", 400, ` + "`" + `invalid path parameter "shelf": strconv.ParseInt: parsing "x": invalid syntax` + "`" + `},
+		{"DELETE", "/shelves/1", "", "", 500, "shelf 1 is locked"},
+		{"GET", "/shelves/1/books?limit=2&order=title", "fr", "", 200, ` + "`" + `{"books":[{"title":"1 limit=2 order=title language=fr"}]}` + "`" + `},
+		{"GET", "/shelves/1/books?order=price", "", "", 400, ` + "`" + `invalid query parameter "order": "price" is not one of title, author` + "`" + `},
+		{"POST", "/shelves/1/books", "", ` + "`" + `{"title":"Odes"}` + "`" + `, 200, ` + "`" + `{"title":"Odes"}` + "`" + `},
+		{"GET", "/shelves/1/books/2", "", "", 200, ` + "`" + `{"name":"shelves/1/books/2"}` + "`" + `},
+		{"DELETE", "/shelves/1/books/2", "", "", 202, ""},
+		{"PUT", "/shelves/1/books/2", "", "", 405, "Method Not Allowed"},
+		{"GET", "/shelves/1/pens", "", "", 404, "404 page not found"},
+	} {
+		request := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+		if test.header != "" {
+			request.Header.Set("Accept-Language", test.header)
+		}
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		body, _ := ioutil.ReadAll(recorder.Body)
+		if recorder.Code != test.code || strings.TrimSpace(string(body)) != test.response {
+			t.Errorf("%s %s: got %d %s, want %d %s", test.method, test.path, recorder.Code, body, test.code, test.response)
+		}
+	}
+}
+`
+
+func TestBookstoreServer(t *testing.T) {
+	testServer(t, "../../examples/v3.0/yaml/bookstore.yaml", "bookstore", bookstoreTest)
+}
+
+// uploadsTest is compiled and run with the code generated for the uploads sample.
+const uploadsTest = `package uploads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+type service struct{}
+
+func (service) CreatePet(ctx context.Context, parameters *CreatePetParameters) (CreatePetResponses, error) {
+	if parameters.Parts == nil {
+		return &CreatePet201Response{Body: parameters.Body}, nil
+	}
+	pet := &Pet{}
+	if err := json.Unmarshal(parameters.Parts["pet"][0].Content, pet); err != nil {
+		return nil, err
+	}
+	pet.Name += fmt.Sprintf(" with %d documents", len(parameters.Parts["documents"]))
+	return &CreatePet201Response{Body: pet}, nil
+}
+
+func (service) UploadPhoto(ctx context.Context, parameters *UploadPhotoParameters) (UploadPhotoResponses, error) {
+	if photo := parameters.Parts["photo"][0]; photo.Filename != "rex.png" || string(photo.Content) != "PNG" {
+		return nil, fmt.Errorf("unexpected photo %s", photo.Filename)
+	}
+	return &UploadPhotoStatusResponse{}, nil
+}
+
+// A part is a part of a multipart request body.
+type part struct {
+	name, filename, contentType, content string
+}
+
+func multipartBody(parts ...part) (string, string) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	for _, p := range parts {
+		header := make(textproto.MIMEHeader)
+		disposition := fmt.Sprintf("form-data; name=%q", p.name)
+		if p.filename != "" {
+			disposition += fmt.Sprintf("; filename=%q", p.filename)
+		}
+		header.Set("Content-Disposition", disposition)
+		if p.contentType != "" {
+			header.Set("Content-Type", p.contentType)
+		}
+		pw, _ := w.CreatePart(header)
+		pw.Write([]byte(p.content))
+	}
+	w.Close()
+	return w.FormDataContentType(), b.String()
+}
+
+func TestHandlers(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterHandlers(mux, service{})
+	pet := part{"pet", "", "application/json", ` + "`" + `{"name":"Rex"}` + "`" + `}
+	for _, test := range []struct {
+		method, path string
+		parts        []part
+		body         string
+		code         int
+		response     string
+	}{
+		{"POST", "/pets", nil, ` + "`" + `{"name":"Rex"}` + "`" + `, 201, ` + "`" + `{"name":"Rex"}` + "`" + `},
+		{"POST", "/pets", []part{pet, {"documents", "a.pdf", "application/pdf", "PDF"}, {"documents", "b.jpg", "image/jpeg", "JPEG"}, {"tags", "", "", "dog"}}, "", 201, ` + "`" + `{"name":"Rex with 2 documents"}` + "`" + `},
+		{"POST", "/pets", []part{{"tags", "", "", "dog"}}, "", 400, ` + "`" + `invalid request body: missing part "pet"` + "`" + `},
+		{"POST", "/pets", []part{pet, {"documents", "a.txt", "text/plain", "text"}}, "", 400, ` + "`" + `invalid request body: part "documents" has content type "text/plain", expected application/pdf or image/*` + "`" + `},
+		{"PUT", "/pets/1/photo", []part{{"photo", "rex.png", "image/png", "PNG"}, {"caption", "", "", "Rex"}}, "", 204, ""},
+		{"PUT", "/pets/1/photo", []part{{"photo", "rex.gif", "image/gif", "GIF"}}, "", 400, ` + "`" + `invalid request body: part "photo" has content type "image/gif", expected image/png or image/jpeg` + "`" + `},
+		{"PUT", "/pets/1/photo", nil, "PNG", 400, "invalid request body: request Content-Type isn't multipart/form-data"},
+	} {
+		contentType, body := "application/json", test.body
+		if test.parts != nil {
+			contentType, body = multipartBody(test.parts...)
+		}
+		request := httptest.NewRequest(test.method, test.path, strings.NewReader(body))
+		request.Header.Set("Content-Type", contentType)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		response, _ := ioutil.ReadAll(recorder.Body)
+		if recorder.Code != test.code || strings.TrimSpace(string(response)) != test.response {
+			t.Errorf("%s %s: got %d %s, want %d %s", test.method, test.path, recorder.Code, response, test.code, test.response)
+		}
+	}
+}
+`
+
+func TestUploadsServer(t *testing.T) {
+	testServer(t, "../../examples/v3.0/yaml/uploads.yaml", "uploads", uploadsTest)
+}
+
+// testServer generates a server for an API description and runs a test
+// with the generated code in a temporary module.
+func testServer(t *testing.T, filename, packageName, test string) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	document, err := openapiv3.ParseDocument(bytes)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	model, err := surface.NewModelFromOpenAPI3(document, filename)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	server, err := generateServer(model, packageName)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	dir, err := ioutil.TempDir("", "gnostic-go-server")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"go.mod":         "module " + packageName + "\n\ngo 1.16\n",
+		"server.go":      string(server),
+		"server_test.go": test,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+	for _, args := range [][]string{{"vet", "."}, {"test", "."}} {
+		cmd := exec.Command(goTool, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("go %s failed: %+v\n%s", args[0], err, output)
+		}
+	}
+}
+
+func TestGoPackageName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"bookstore":    "bookstore",
+		"Book-Store":   "bookstore",
+		"2bookstore_2": "bookstore2",
+		".":            "",
+	} {
+		if got := goPackageName(name); got != expected {
+			t.Errorf("goPackageName(%q) = %q, want %q", name, got, expected)
+		}
+	}
+}
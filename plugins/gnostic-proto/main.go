@@ -0,0 +1,70 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnostic-proto is a plugin that generates a Protocol Buffer description
+// of an API from its OpenAPI v3 description.
+//
+// It writes the proto3 source of the API and a compiled FileDescriptorSet
+// that contains the generated file and all of its dependencies.
+package main
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	protov2 "google.golang.org/protobuf/proto"
+
+	"github.com/google/gnostic/conversions"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+)
+
+// This is the main function for the plugin.
+func main() {
+	env, err := plugins.NewEnvironment()
+	env.RespondAndExitIfError(err)
+
+	for _, model := range env.Request.Models {
+		if model.TypeUrl != "openapi.v3.Document" {
+			continue
+		}
+		document := &openapiv3.Document{}
+		err = proto.Unmarshal(model.Value, document)
+		env.RespondAndExitIfError(err)
+
+		// The package name can be set with a "package" parameter and
+		// defaults to the title of the API.
+		packageName := conversions.ProtoPackageName(document.GetInfo().GetTitle())
+		for _, parameter := range env.Request.Parameters {
+			if parameter.Name == "package" {
+				packageName = conversions.ProtoPackageName(parameter.Value)
+			}
+		}
+		if packageName == "" {
+			packageName = "api"
+		}
+		fileName := strings.Replace(packageName, ".", "_", -1) + ".proto"
+
+		source, set, err := conversions.ProtoFromOpenAPIv3(document, fileName, packageName)
+		env.RespondAndExitIfError(err)
+		data, err := protov2.Marshal(set)
+		env.RespondAndExitIfError(err)
+		env.Response.Files = append(env.Response.Files,
+			&plugins.File{Name: fileName, Data: source},
+			&plugins.File{Name: strings.TrimSuffix(fileName, ".proto") + ".pb", Data: data},
+		)
+	}
+
+	env.RespondAndExit()
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gnostic-report is a plugin that generates a JSON report of the
+// operations and schemas in an API description.
+//
+// Each operation is listed with summaries of its parameters and responses.
+// The format is described in the report package and is versioned with
+// report.Version.
+package main
+
+import (
+	"path"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	openapiv2 "github.com/google/gnostic/openapiv2"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+	"github.com/google/gnostic/plugins/gnostic-report/report"
+)
+
+// This is the main function for the plugin.
+func main() {
+	env, err := plugins.NewEnvironment()
+	env.RespondAndExitIfError(err)
+
+	var r *report.Report
+
+	for _, model := range env.Request.Models {
+		switch model.TypeUrl {
+		case "openapi.v2.Document":
+			documentv2 := &openapiv2.Document{}
+			err = proto.Unmarshal(model.Value, documentv2)
+			if err == nil {
+				r = report.NewReportV2(env.Request.SourceName, documentv2)
+			}
+		case "openapi.v3.Document":
+			documentv3 := &openapiv3.Document{}
+			err = proto.Unmarshal(model.Value, documentv3)
+			if err == nil {
+				r = report.NewReportV3(env.Request.SourceName, documentv3)
+			}
+		}
+	}
+	env.RespondAndExitIfError(err)
+
+	if r != nil {
+		// Reports are in files named "report.json" in the same relative
+		// locations as the description source files.
+		file := &plugins.File{}
+		file.Name = strings.Replace(r.Source, path.Base(r.Source), "report.json", -1)
+		file.Data, err = r.JSON()
+		env.RespondAndExitIfError(err)
+		env.Response.Files = append(env.Response.Files, file)
+	}
+
+	env.RespondAndExit()
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package report builds machine-readable reports of OpenAPI descriptions.
+package report
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Version is the version of the report format. It is incremented when
+// fields are removed or their meanings change; new fields may be added
+// without changing the version.
+const Version = "1"
+
+// Report describes the operations and schemas of an API description.
+type Report struct {
+	// Version is the version of the report format.
+	Version string `json:"version"`
+	// Source is the name of the API description.
+	Source string `json:"source"`
+	// OpenAPI is the version of OpenAPI used by the description, e.g. "2.0" or "3.0.0".
+	OpenAPI string `json:"openapi"`
+	// Title is the title of the API.
+	Title string `json:"title"`
+	// APIVersion is the version of the API.
+	APIVersion string `json:"apiVersion"`
+	// Operations are the operations of the API in the order of their paths.
+	Operations []*Operation `json:"operations"`
+	// Schemas are the names of the named schemas (definitions) of the API.
+	Schemas []string `json:"schemas"`
+}
+
+// Operation describes an operation of an API.
+type Operation struct {
+	Method      string       `json:"method"`
+	Path        string       `json:"path"`
+	OperationID string       `json:"operationId,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Deprecated  bool         `json:"deprecated,omitempty"`
+	Parameters  []*Parameter `json:"parameters"`
+	Responses   []*Response  `json:"responses"`
+}
+
+// Parameter describes a parameter of an operation. The request body of
+// an OpenAPI v3 operation is described as a parameter in "body".
+// Parameters that are references that can't be resolved only have a Ref.
+type Parameter struct {
+	Name     string `json:"name,omitempty"`
+	In       string `json:"in,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Ref      string `json:"ref,omitempty"`
+}
+
+// Response describes a response of an operation. Type is the type of the
+// response schema or, if the schema is a reference, the name of the
+// referenced schema. Responses that are references that can't be resolved
+// only have a Code and a Ref.
+type Response struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Ref         string `json:"ref,omitempty"`
+}
+
+// JSON returns an indented JSON representation of a report.
+func (r *Report) JSON() ([]byte, error) {
+	bytes, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes, '\n'), nil
+}
+
+// newReport creates a report with empty lists.
+func newReport(source string) *Report {
+	return &Report{
+		Version:    Version,
+		Source:     source,
+		Operations: make([]*Operation, 0),
+		Schemas:    make([]string, 0),
+	}
+}
+
+// newOperation creates an operation with empty lists.
+func newOperation(method, path, operationID string, tags []string, deprecated bool) *Operation {
+	return &Operation{
+		Method:      method,
+		Path:        path,
+		OperationID: operationID,
+		Tags:        tags,
+		Deprecated:  deprecated,
+		Parameters:  make([]*Parameter, 0),
+		Responses:   make([]*Response, 0),
+	}
+}
+
+// mergeParameters returns the parameters of a path item that aren't
+// overridden by a parameter of an operation with the same name and
+// location, followed by the parameters of the operation.
+func mergeParameters(pathParameters, operationParameters []*Parameter) []*Parameter {
+	merged := make([]*Parameter, 0, len(pathParameters)+len(operationParameters))
+	for _, p := range pathParameters {
+		overridden := false
+		for _, o := range operationParameters {
+			if p.Name != "" && o.Name == p.Name && o.In == p.In {
+				overridden = true
+			}
+		}
+		if !overridden {
+			merged = append(merged, p)
+		}
+	}
+	return append(merged, operationParameters...)
+}
+
+// typeNameForRef returns the last component of a reference, which is
+// usually the name of the referenced schema.
+func typeNameForRef(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"strings"
+
+	openapi "github.com/google/gnostic/openapiv2"
+)
+
+// NewReportV2 builds a report for an OpenAPI v2 description.
+func NewReportV2(source string, document *openapi.Document) *Report {
+	r := newReport(source)
+	r.OpenAPI = document.Swagger
+	if document.Info != nil {
+		r.Title = document.Info.Title
+		r.APIVersion = document.Info.Version
+	}
+	if document.Paths != nil {
+		for _, pair := range document.Paths.Path {
+			v := pair.Value
+			for _, o := range []struct {
+				method    string
+				operation *openapi.Operation
+			}{
+				{"GET", v.Get}, {"PUT", v.Put}, {"POST", v.Post}, {"DELETE", v.Delete},
+				{"OPTIONS", v.Options}, {"HEAD", v.Head}, {"PATCH", v.Patch},
+			} {
+				if o.operation != nil {
+					r.Operations = append(r.Operations, newOperationV2(document, o.method, pair.Name, v.Parameters, o.operation))
+				}
+			}
+		}
+	}
+	if document.Definitions != nil {
+		for _, pair := range document.Definitions.AdditionalProperties {
+			r.Schemas = append(r.Schemas, pair.Name)
+		}
+	}
+	return r
+}
+
+func newOperationV2(document *openapi.Document, method, path string, pathParameters []*openapi.ParametersItem, operation *openapi.Operation) *Operation {
+	o := newOperation(method, path, operation.OperationId, operation.Tags, operation.Deprecated)
+	inherited := make([]*Parameter, 0, len(pathParameters))
+	for _, item := range pathParameters {
+		inherited = append(inherited, parameterV2(document, item))
+	}
+	for _, item := range operation.Parameters {
+		o.Parameters = append(o.Parameters, parameterV2(document, item))
+	}
+	o.Parameters = mergeParameters(inherited, o.Parameters)
+	if operation.Responses != nil {
+		for _, pair := range operation.Responses.ResponseCode {
+			response := &Response{Code: pair.Name}
+			if r := pair.Value.GetResponse(); r != nil {
+				response.Description = r.Description
+				if r.Schema != nil {
+					if schema := r.Schema.GetSchema(); schema != nil {
+						response.Type = typeForSchemaV2(schema)
+					} else if r.Schema.GetFileSchema() != nil {
+						response.Type = "file"
+					}
+				}
+			} else if ref := pair.Value.GetJsonReference(); ref != nil {
+				response.Ref = ref.XRef
+			}
+			o.Responses = append(o.Responses, response)
+		}
+	}
+	return o
+}
+
+func parameterV2(document *openapi.Document, item *openapi.ParametersItem) *Parameter {
+	parameter := item.GetParameter()
+	if ref := item.GetJsonReference(); ref != nil {
+		parameter = parameterForRefV2(document, ref.XRef)
+		if parameter == nil {
+			return &Parameter{Ref: ref.XRef}
+		}
+	}
+	if body := parameter.GetBodyParameter(); body != nil {
+		p := &Parameter{Name: body.Name, In: body.In, Required: body.Required}
+		if body.Schema != nil {
+			p.Type = typeForSchemaV2(body.Schema)
+		}
+		return p
+	}
+	nonBody := parameter.GetNonBodyParameter()
+	if p := nonBody.GetHeaderParameterSubSchema(); p != nil {
+		return &Parameter{Name: p.Name, In: p.In, Type: p.Type, Required: p.Required}
+	}
+	if p := nonBody.GetFormDataParameterSubSchema(); p != nil {
+		return &Parameter{Name: p.Name, In: p.In, Type: p.Type, Required: p.Required}
+	}
+	if p := nonBody.GetQueryParameterSubSchema(); p != nil {
+		return &Parameter{Name: p.Name, In: p.In, Type: p.Type, Required: p.Required}
+	}
+	if p := nonBody.GetPathParameterSubSchema(); p != nil {
+		return &Parameter{Name: p.Name, In: p.In, Type: p.Type, Required: p.Required}
+	}
+	return &Parameter{}
+}
+
+// parameterForRefV2 returns the parameter named by a local reference, or nil.
+func parameterForRefV2(document *openapi.Document, ref string) *openapi.Parameter {
+	if document.Parameters == nil || !strings.HasPrefix(ref, "#/parameters/") {
+		return nil
+	}
+	name := strings.TrimPrefix(ref, "#/parameters/")
+	for _, pair := range document.Parameters.AdditionalProperties {
+		if pair.Name == name {
+			return pair.Value
+		}
+	}
+	return nil
+}
+
+func typeForSchemaV2(schema *openapi.Schema) string {
+	if schema.XRef != "" {
+		return typeNameForRef(schema.XRef)
+	}
+	if schema.Type != nil {
+		return strings.Join(schema.Type.Value, ",")
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"strings"
+
+	openapi "github.com/google/gnostic/openapiv3"
+)
+
+// NewReportV3 builds a report for an OpenAPI v3 description.
+func NewReportV3(source string, document *openapi.Document) *Report {
+	r := newReport(source)
+	r.OpenAPI = document.Openapi
+	if document.Info != nil {
+		r.Title = document.Info.Title
+		r.APIVersion = document.Info.Version
+	}
+	if document.Paths != nil {
+		for _, pair := range document.Paths.Path {
+			v := pair.Value
+			for _, o := range []struct {
+				method    string
+				operation *openapi.Operation
+			}{
+				{"GET", v.Get}, {"PUT", v.Put}, {"POST", v.Post}, {"DELETE", v.Delete},
+				{"OPTIONS", v.Options}, {"HEAD", v.Head}, {"PATCH", v.Patch}, {"TRACE", v.Trace},
+			} {
+				if o.operation != nil {
+					r.Operations = append(r.Operations, newOperationV3(document, o.method, pair.Name, v.Parameters, o.operation))
+				}
+			}
+		}
+	}
+	if document.Components != nil && document.Components.Schemas != nil {
+		for _, pair := range document.Components.Schemas.AdditionalProperties {
+			r.Schemas = append(r.Schemas, pair.Name)
+		}
+	}
+	return r
+}
+
+func newOperationV3(document *openapi.Document, method, path string, pathParameters []*openapi.ParameterOrReference, operation *openapi.Operation) *Operation {
+	o := newOperation(method, path, operation.OperationId, operation.Tags, operation.Deprecated)
+	inherited := make([]*Parameter, 0, len(pathParameters))
+	for _, item := range pathParameters {
+		inherited = append(inherited, parameterV3(document, item))
+	}
+	for _, item := range operation.Parameters {
+		o.Parameters = append(o.Parameters, parameterV3(document, item))
+	}
+	o.Parameters = mergeParameters(inherited, o.Parameters)
+	if body := operation.RequestBody; body != nil {
+		if requestBody := body.GetRequestBody(); requestBody != nil {
+			o.Parameters = append(o.Parameters, &Parameter{
+				In:       "body",
+				Type:     typeForMediaTypesV3(requestBody.Content),
+				Required: requestBody.Required,
+			})
+		} else if ref := body.GetReference(); ref != nil {
+			o.Parameters = append(o.Parameters, &Parameter{In: "body", Ref: ref.XRef})
+		}
+	}
+	if operation.Responses != nil {
+		for _, pair := range operation.Responses.ResponseOrReference {
+			o.Responses = append(o.Responses, responseV3(pair.Name, pair.Value))
+		}
+		if operation.Responses.Default != nil {
+			o.Responses = append(o.Responses, responseV3("default", operation.Responses.Default))
+		}
+	}
+	return o
+}
+
+func parameterV3(document *openapi.Document, item *openapi.ParameterOrReference) *Parameter {
+	parameter := item.GetParameter()
+	if ref := item.GetReference(); ref != nil {
+		parameter = parameterForRefV3(document, ref.XRef)
+		if parameter == nil {
+			return &Parameter{Ref: ref.XRef}
+		}
+	}
+	p := &Parameter{Name: parameter.Name, In: parameter.In, Required: parameter.Required}
+	if parameter.Schema != nil {
+		p.Type = typeForSchemaOrReferenceV3(parameter.Schema)
+	} else {
+		p.Type = typeForMediaTypesV3(parameter.Content)
+	}
+	return p
+}
+
+// parameterForRefV3 returns the parameter named by a local reference, or nil.
+func parameterForRefV3(document *openapi.Document, ref string) *openapi.Parameter {
+	if document.Components == nil || document.Components.Parameters == nil ||
+		!strings.HasPrefix(ref, "#/components/parameters/") {
+		return nil
+	}
+	name := strings.TrimPrefix(ref, "#/components/parameters/")
+	for _, pair := range document.Components.Parameters.AdditionalProperties {
+		if pair.Name == name {
+			return pair.Value.GetParameter()
+		}
+	}
+	return nil
+}
+
+func responseV3(code string, value *openapi.ResponseOrReference) *Response {
+	response := &Response{Code: code}
+	if r := value.GetResponse(); r != nil {
+		response.Description = r.Description
+		response.Type = typeForMediaTypesV3(r.Content)
+	} else if ref := value.GetReference(); ref != nil {
+		response.Ref = ref.XRef
+	}
+	return response
+}
+
+// typeForMediaTypesV3 returns the type of the schema of the first media type.
+func typeForMediaTypesV3(content *openapi.MediaTypes) string {
+	if content == nil || len(content.AdditionalProperties) == 0 {
+		return ""
+	}
+	mediaType := content.AdditionalProperties[0].Value
+	if mediaType == nil || mediaType.Schema == nil {
+		return ""
+	}
+	return typeForSchemaOrReferenceV3(mediaType.Schema)
+}
+
+func typeForSchemaOrReferenceV3(schema *openapi.SchemaOrReference) string {
+	if ref := schema.GetReference(); ref != nil {
+		return typeNameForRef(ref.XRef)
+	}
+	if s := schema.GetSchema(); s != nil {
+		return s.Type
+	}
+	return ""
+}
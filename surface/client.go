@@ -0,0 +1,127 @@
+// Copyright 2023 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package surface_v1
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic/jsonschema"
+)
+
+// The extensions of operations that describe the behavior of clients.
+const (
+	TimeoutExtensionName    = "x-client-timeout"
+	RetryableExtensionName  = "x-retryable"
+	IdempotentExtensionName = "x-idempotent"
+)
+
+// clientExtensionsSchemaSource describes the values of the client behavior
+// extensions in the format of extension schemas (see generate-gnostic
+// --extension): the id of each definition is the name of an extension.
+//
+//go:embed x-client.json
+var clientExtensionsSchemaSource []byte
+
+// clientExtensionSchemas are the schemas of the values of the client
+// behavior extensions by extension name.
+var clientExtensionSchemas = make(map[string]*jsonschema.Schema)
+
+func init() {
+	var node yaml.Node
+	if err := yaml.Unmarshal(clientExtensionsSchemaSource, &node); err != nil {
+		panic(err)
+	}
+	for _, pair := range *jsonschema.NewSchemaFromObject(&node).Definitions {
+		clientExtensionSchemas[*pair.Value.ID] = pair.Value
+	}
+}
+
+// isIdempotentMethod returns true for the HTTP methods that RFC 7231
+// defines as idempotent.
+func isIdempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// IsIdempotent returns true if repeated calls of a method have the effect
+// of a single call. Methods are idempotent if their HTTP methods are,
+// unless x-idempotent says otherwise.
+func (m *Method) IsIdempotent() bool {
+	if m.Idempotent != nil {
+		return *m.Idempotent
+	}
+	return isIdempotentMethod(m.Method)
+}
+
+// setClientBehavior sets the timeout, retryability, and idempotency of a
+// method from the client behavior extensions of its operation, which are
+// given as YAML by extension name. The idempotency is only set if it
+// differs from the idempotency of the HTTP method. Extension values that
+// don't conform to their schemas are ignored with a warning for each.
+func setClientBehavior(m *Method, extensions map[string]string) (warnings []string) {
+	for _, name := range []string{TimeoutExtensionName, RetryableExtensionName, IdempotentExtensionName} {
+		text, ok := extensions[name]
+		if !ok {
+			continue
+		}
+		warn := func(message string) {
+			warnings = append(warnings, fmt.Sprintf("%s %s: %s: %s, ignoring it", m.Method, m.Path, name, message))
+		}
+		var value yaml.Node
+		if err := yaml.Unmarshal([]byte(text), &value); err != nil || len(value.Content) == 0 {
+			warn("invalid value")
+			continue
+		}
+		if errors := clientExtensionSchemas[name].Validate(value.Content[0]); len(errors) > 0 {
+			for _, e := range errors {
+				warn(e.Error())
+			}
+			continue
+		}
+		switch name {
+		case TimeoutExtensionName:
+			timeout, err := time.ParseDuration(value.Content[0].Value)
+			if err != nil {
+				warn(err.Error())
+				continue
+			}
+			m.TimeoutMilliseconds = timeoutMilliseconds(timeout)
+		case RetryableExtensionName:
+			value.Content[0].Decode(&m.Retryable)
+		case IdempotentExtensionName:
+			var idempotent bool
+			value.Content[0].Decode(&idempotent)
+			if idempotent != isIdempotentMethod(m.Method) {
+				m.Idempotent = &idempotent
+			}
+		}
+	}
+	return warnings
+}
+
+// timeoutMilliseconds returns a timeout in milliseconds. Timeouts are
+// rounded up, so that shorter timeouts than a millisecond don't become
+// zero, which means that there is no timeout.
+func timeoutMilliseconds(timeout time.Duration) int64 {
+	return int64((timeout + time.Millisecond - 1) / time.Millisecond)
+}
@@ -33,6 +33,8 @@ type FieldInfo struct {
 	fieldPosition Position
 	fieldName     string
 	enumValues    []string
+	// For multipart request bodies
+	parts []*Part
 }
 
 func (m *Model) addType(t *Type) {
@@ -91,6 +93,7 @@ func makeFieldAndAppendToType(info *FieldInfo, schemaType *Type, fieldName strin
 			f.Name = fieldName
 		}
 		f.Type, f.Kind, f.Format, f.Position, f.EnumValues = info.fieldType, info.fieldKind, info.fieldFormat, info.fieldPosition, info.enumValues
+		f.Parts = info.parts
 		schemaType.Fields = append(schemaType.Fields, f)
 	}
 }
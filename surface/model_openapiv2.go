@@ -17,6 +17,7 @@ package surface_v1
 import (
 	"log"
 	"strconv"
+	"strings"
 
 	"github.com/google/gnostic/compiler"
 	openapiv2 "github.com/google/gnostic/openapiv2"
@@ -25,6 +26,8 @@ import (
 type OpenAPI2Builder struct {
 	model    *Model
 	document *openapiv2.Document
+	// Warnings about the client behavior extensions of operations.
+	warnings []string
 }
 
 // NewModelFromOpenAPI2 builds a model of an API service for use in code generation.
@@ -53,6 +56,9 @@ func (b *OpenAPI2Builder) buildModel(document *openapiv2.Document, sourceName st
 	if err != nil {
 		log.Printf("Error while building symbolic references. This might cause the plugin to fail: %v", err)
 	}
+	for _, warning := range b.warnings {
+		log.Printf("Warning: %s", warning)
+	}
 	return b.model, nil
 }
 
@@ -184,19 +190,71 @@ func (b *OpenAPI2Builder) buildFromNamedPath(name string, pathItem *openapiv2.Pa
 			if m.Name == "" {
 				m.Name = generateOperationName(method, name)
 			}
-			m.ParametersTypeName, m.ResponsesTypeName = b.buildFromNamedOperation(m.Name, op)
+			extensions := make(map[string]string)
+			for _, extension := range op.VendorExtension {
+				extensions[extension.Name] = extension.Value.GetYaml()
+			}
+			b.warnings = append(b.warnings, setClientBehavior(m, extensions)...)
+			parameters := b.operationParameters(pathItem.Parameters, op.Parameters)
+			m.ParametersTypeName, m.ResponsesTypeName = b.buildFromNamedOperation(m.Name, parameters, op)
 			b.model.addMethod(m)
 		}
 	}
 }
 
+// Returns the parameters of a path item that an operation doesn't override, followed by the parameters of the operation.
+// Parameters are identified by their names and locations.
+func (b *OpenAPI2Builder) operationParameters(pathParameters, parameters []*openapiv2.ParametersItem) []*openapiv2.ParametersItem {
+	overridden := make(map[string]bool)
+	for _, paramOrRef := range parameters {
+		overridden[b.parameterKey(paramOrRef)] = true
+	}
+	result := make([]*openapiv2.ParametersItem, 0, len(pathParameters)+len(parameters))
+	for _, paramOrRef := range pathParameters {
+		if key := b.parameterKey(paramOrRef); key == "" || !overridden[key] {
+			result = append(result, paramOrRef)
+		}
+	}
+	return append(result, parameters...)
+}
+
+// Returns the location and name of a parameter, or "" if it is a reference that can't be resolved.
+func (b *OpenAPI2Builder) parameterKey(paramOrRef *openapiv2.ParametersItem) string {
+	param := paramOrRef.GetParameter()
+	if ref := paramOrRef.GetJsonReference(); ref != nil && strings.HasPrefix(ref.XRef, "#/parameters/") {
+		name := strings.TrimPrefix(ref.XRef, "#/parameters/")
+		for _, pair := range b.document.GetParameters().GetAdditionalProperties() {
+			if pair.Name == name {
+				param = pair.Value
+			}
+		}
+	}
+	if body := param.GetBodyParameter(); body != nil {
+		return body.In + " " + body.Name
+	}
+	nonBody := param.GetNonBodyParameter()
+	if p := nonBody.GetHeaderParameterSubSchema(); p != nil {
+		return p.In + " " + p.Name
+	}
+	if p := nonBody.GetFormDataParameterSubSchema(); p != nil {
+		return p.In + " " + p.Name
+	}
+	if p := nonBody.GetQueryParameterSubSchema(); p != nil {
+		return p.In + " " + p.Name
+	}
+	if p := nonBody.GetPathParameterSubSchema(); p != nil {
+		return p.In + " " + p.Name
+	}
+	return ""
+}
+
 // Builds the "Parameters" and "Responses" types for an operation, adds them to the model, and returns the names of the types.
 // If no such Type is added to the model an empty string is returned.
-func (b *OpenAPI2Builder) buildFromNamedOperation(name string, operation *openapiv2.Operation) (parametersTypeName string, responseTypeName string) {
+func (b *OpenAPI2Builder) buildFromNamedOperation(name string, parameters []*openapiv2.ParametersItem, operation *openapiv2.Operation) (parametersTypeName string, responseTypeName string) {
 	// At first, we build the operations input parameters. This includes parameters (like PATH or QUERY parameters).
 	operationParameters := makeType(name + "Parameters")
 	operationParameters.Description = operationParameters.Name + " holds parameters to " + name
-	for _, paramOrRef := range operation.Parameters {
+	for _, paramOrRef := range parameters {
 		fieldInfo := b.buildFromParamOrRef(paramOrRef)
 		// For parameters the name of the field is contained inside fieldInfo. That is why we pass "" as fieldName
 		makeFieldAndAppendToType(fieldInfo, operationParameters, "")
@@ -12,9 +12,14 @@ import (
 )
 
 func TestModelOpenAPIV2(t *testing.T) {
-	refFile := "testdata/v2.0/petstore.json"
-	modelFile := "testdata/v2.0/petstore.model.json"
+	testModelOpenAPIV2(t, "testdata/v2.0/petstore.json", "testdata/v2.0/petstore.model.json")
+}
+
+func TestModelOpenAPIV2PathParameters(t *testing.T) {
+	testModelOpenAPIV2(t, "testdata/v2.0/pathparameters.json", "testdata/v2.0/pathparameters.model.json")
+}
 
+func testModelOpenAPIV2(t *testing.T, refFile string, modelFile string) {
 	bFile, err := os.ReadFile(refFile)
 	if err != nil {
 		t.Logf("Failed to read file: %+v", err)
@@ -18,6 +18,8 @@ import (
 	"log"
 	"strings"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/google/gnostic/compiler"
 	openapiv3 "github.com/google/gnostic/openapiv3"
 )
@@ -25,6 +27,12 @@ import (
 type OpenAPI3Builder struct {
 	model    *Model
 	document *openapiv3.Document
+	// Maps names of types with readOnly properties to their input variants.
+	inputTypes map[string]string
+	// Maps names of types with writeOnly properties to their output variants.
+	outputTypes map[string]string
+	// Warnings about the client behavior extensions of operations.
+	warnings []string
 }
 
 // NewModelFromOpenAPIv3 builds a model of an API service for use in code generation.
@@ -33,7 +41,7 @@ func NewModelFromOpenAPI3(document *openapiv3.Document, sourceName string) (*Mod
 }
 
 func newOpenAPI3Builder(document *openapiv3.Document) *OpenAPI3Builder {
-	return &OpenAPI3Builder{model: &Model{}, document: document}
+	return &OpenAPI3Builder{model: &Model{}, document: document, inputTypes: make(map[string]string), outputTypes: make(map[string]string)}
 }
 
 // Fills the surface model with information from a parsed OpenAPI description. The surface model provides that information
@@ -53,6 +61,9 @@ func (b *OpenAPI3Builder) buildModel(document *openapiv3.Document, sourceName st
 	if err != nil {
 		log.Printf("Error while building symbolic references. This might cause the plugin to fail: %v", err)
 	}
+	for _, warning := range b.warnings {
+		log.Printf("Warning: %s", warning)
+	}
 	return b.model, nil
 }
 
@@ -72,6 +83,7 @@ func (b *OpenAPI3Builder) buildFromComponents(components *openapiv3.Components)
 		fInfo := b.buildFromSchemaOrReference(namedSchema.Name, namedSchema.Value)
 		b.checkForExistence(namedSchema.Name, fInfo)
 	}
+	b.buildVariantTypes(components)
 
 	for _, namedParameter := range components.GetParameters().GetAdditionalProperties() {
 		// Parameters in OpenAPI have a name field. See: https://swagger.io/specification/#parameterObject
@@ -161,19 +173,58 @@ func (b *OpenAPI3Builder) buildFromNamedPath(name string, pathItem *openapiv3.Pa
 			if m.Name == "" {
 				m.Name = generateOperationName(method, name)
 			}
-			m.ParametersTypeName, m.ResponsesTypeName = b.buildFromNamedOperation(m.Name, op)
+			extensions := make(map[string]string)
+			for _, extension := range op.SpecificationExtension {
+				extensions[extension.Name] = extension.Value.GetYaml()
+			}
+			b.warnings = append(b.warnings, setClientBehavior(m, extensions)...)
+			parameters := b.operationParameters(pathItem.Parameters, op.Parameters)
+			m.ParametersTypeName, m.ResponsesTypeName = b.buildFromNamedOperation(m.Name, parameters, op)
 			b.model.addMethod(m)
 		}
 	}
 }
 
+// Returns the parameters of a path item that an operation doesn't override, followed by the parameters of the operation.
+// Parameters are identified by their names and locations.
+func (b *OpenAPI3Builder) operationParameters(pathParameters, parameters []*openapiv3.ParameterOrReference) []*openapiv3.ParameterOrReference {
+	overridden := make(map[string]bool)
+	for _, paramOrRef := range parameters {
+		overridden[b.parameterKey(paramOrRef)] = true
+	}
+	result := make([]*openapiv3.ParameterOrReference, 0, len(pathParameters)+len(parameters))
+	for _, paramOrRef := range pathParameters {
+		if key := b.parameterKey(paramOrRef); key == "" || !overridden[key] {
+			result = append(result, paramOrRef)
+		}
+	}
+	return append(result, parameters...)
+}
+
+// Returns the location and name of a parameter, or "" if it is a reference that can't be resolved.
+func (b *OpenAPI3Builder) parameterKey(paramOrRef *openapiv3.ParameterOrReference) string {
+	param := paramOrRef.GetParameter()
+	if ref := paramOrRef.GetReference(); ref != nil && strings.HasPrefix(ref.XRef, "#/components/parameters/") {
+		name := strings.TrimPrefix(ref.XRef, "#/components/parameters/")
+		for _, pair := range b.document.GetComponents().GetParameters().GetAdditionalProperties() {
+			if pair.Name == name {
+				param = pair.Value.GetParameter()
+			}
+		}
+	}
+	if param == nil {
+		return ""
+	}
+	return param.In + " " + param.Name
+}
+
 // Builds the "Parameters" and "Responses" types for an operation, adds them to the model, and returns the names of the types.
 // If no such Type is added to the model an empty string is returned.
-func (b *OpenAPI3Builder) buildFromNamedOperation(name string, operation *openapiv3.Operation) (parametersTypeName string, responseTypeName string) {
+func (b *OpenAPI3Builder) buildFromNamedOperation(name string, parameters []*openapiv3.ParameterOrReference, operation *openapiv3.Operation) (parametersTypeName string, responseTypeName string) {
 	// At first, we build the operations input parameters. This includes parameters (like PATH or QUERY parameters) and a request body
 	operationParameters := makeType(name + "Parameters")
 	operationParameters.Description = operationParameters.Name + " holds parameters to " + name
-	for _, paramOrRef := range operation.Parameters {
+	for _, paramOrRef := range parameters {
 		fieldInfo := b.buildFromParamOrRef(paramOrRef)
 		// For parameters the name of the field is contained inside fieldInfo. That is why we pass "" as fieldName
 		makeFieldAndAppendToType(fieldInfo, operationParameters, "")
@@ -277,6 +328,12 @@ func (b *OpenAPI3Builder) buildFromRequestBody(name string, reqBody *openapiv3.R
 		schemaType := makeType(name)
 		for _, namedMediaType := range reqBody.Content.AdditionalProperties {
 			fieldInfo := b.buildFromSchemaOrReference(name+namedMediaType.Name, namedMediaType.GetValue().GetSchema())
+			if fieldInfo != nil {
+				fieldInfo.fieldType = variantTypeName(b.inputTypes, fieldInfo.fieldKind, fieldInfo.fieldType)
+				if openapiv3.IsMultipart(namedMediaType.Name) {
+					fieldInfo.parts = b.buildParts(namedMediaType.Value)
+				}
+			}
 			makeFieldAndAppendToType(fieldInfo, schemaType, namedMediaType.Name)
 		}
 		b.model.addType(schemaType)
@@ -286,6 +343,15 @@ func (b *OpenAPI3Builder) buildFromRequestBody(name string, reqBody *openapiv3.R
 	return nil
 }
 
+// Returns the parts of a multipart request body with their content types and headers.
+func (b *OpenAPI3Builder) buildParts(mediaType *openapiv3.MediaType) []*Part {
+	var parts []*Part
+	for _, p := range openapiv3.MultipartParts(b.document, mediaType) {
+		parts = append(parts, &Part{Name: p.Name, ContentType: p.ContentType, Required: p.Required, Headers: p.Headers})
+	}
+	return parts
+}
+
 // A helper method to differentiate between references and actual objects
 func (b *OpenAPI3Builder) buildFromResponseOrRef(name string, responseOrRef *openapiv3.ResponseOrReference) (fInfo []*FieldInfo) {
 	if response := responseOrRef.GetResponse(); response != nil {
@@ -306,6 +372,7 @@ func (b *OpenAPI3Builder) buildFromResponse(name string, response *openapiv3.Res
 			name := name + " " + namedMediaType.Name
 			fieldInfo := b.buildFromSchemaOrReference(name, namedMediaType.GetValue().GetSchema())
 			fieldInfo.fieldName = name
+			fieldInfo.fieldType = variantTypeName(b.outputTypes, fieldInfo.fieldKind, fieldInfo.fieldType)
 			fInfos = append(fInfos, fieldInfo)
 		}
 	}
@@ -441,6 +508,58 @@ func (b *OpenAPI3Builder) buildFromOneOfAnyOfAndAllOf(schemaOrRef *openapiv3.Sch
 	}
 }
 
+// buildVariantTypes adds variants of component schemas that have readOnly or writeOnly properties. Input
+// variants named "<Name>Input" omit the readOnly properties and are used for request bodies. Output variants
+// named "<Name>Output" omit the writeOnly properties and are used for responses.
+func (b *OpenAPI3Builder) buildVariantTypes(components *openapiv3.Components) {
+	b.buildVariants(components, b.inputTypes, "Input", "writable", (*openapiv3.Schema).GetReadOnly)
+	b.buildVariants(components, b.outputTypes, "Output", "readable", (*openapiv3.Schema).GetWriteOnly)
+}
+
+// buildVariants adds a variant named "<Name><suffix>" for each component schema with properties for which
+// omit returns true, and records the variant names in variants.
+func (b *OpenAPI3Builder) buildVariants(components *openapiv3.Components, variants map[string]string, suffix, adjective string, omit func(*openapiv3.Schema) bool) {
+	types := make([]*Type, 0)
+	omitted := make(map[string]map[string]bool)
+	for _, namedSchema := range components.GetSchemas().GetAdditionalProperties() {
+		properties := make(map[string]bool)
+		for _, property := range namedSchema.Value.GetSchema().GetProperties().GetAdditionalProperties() {
+			if schema := property.Value.GetSchema(); schema != nil && omit(schema) {
+				properties[property.Name] = true
+			}
+		}
+		t := findType(b.model.Types, namedSchema.Name)
+		if len(properties) == 0 || t == nil || findType(b.model.Types, t.Name+suffix) != nil {
+			continue
+		}
+		types = append(types, t)
+		omitted[t.Name] = properties
+		variants[t.Name] = t.Name + suffix
+	}
+	for _, t := range types {
+		variant := makeType(variants[t.Name])
+		variant.Kind, variant.ContentType = t.Kind, t.ContentType
+		variant.Description = variant.Name + " holds the " + adjective + " fields of " + t.Name
+		for _, f := range t.Fields {
+			if omitted[t.Name][f.Name] {
+				continue
+			}
+			field := proto.Clone(f).(*Field)
+			field.Type = variantTypeName(variants, field.Kind, field.Type)
+			variant.addField(field)
+		}
+		b.model.addType(variant)
+	}
+}
+
+// variantTypeName returns the name of the variant of a referenced type, if there is one.
+func variantTypeName(variants map[string]string, kind FieldKind, typeName string) string {
+	if variant, ok := variants[typeName]; ok && (kind == FieldKind_REFERENCE || kind == FieldKind_ARRAY) {
+		return variant
+	}
+	return typeName
+}
+
 // removeType removes the Type 'toRemove' from the model.
 func (b *OpenAPI3Builder) removeType(toRemove *Type) {
 	res := make([]*Type, 0)
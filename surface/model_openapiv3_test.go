@@ -1,7 +1,10 @@
 package surface_v1
 
 import (
+	"bytes"
+	"log"
 	"os"
+	"strings"
 	"testing"
 
 	openapiv3 "github.com/google/gnostic/openapiv3"
@@ -12,9 +15,22 @@ import (
 )
 
 func TestModelOpenAPIV3(t *testing.T) {
-	refFile := "testdata/v3.0/petstore.json"
-	modelFile := "testdata/v3.0/petstore.model.json"
+	testModelOpenAPIV3(t, "testdata/v3.0/petstore.json", "testdata/v3.0/petstore.model.json")
+}
+
+func TestModelOpenAPIV3VariantTypes(t *testing.T) {
+	testModelOpenAPIV3(t, "testdata/v3.0/accounts.json", "testdata/v3.0/accounts.model.json")
+}
 
+func TestModelOpenAPIV3PathParameters(t *testing.T) {
+	testModelOpenAPIV3(t, "testdata/v3.0/pathparameters.json", "testdata/v3.0/pathparameters.model.json")
+}
+
+func TestModelOpenAPIV3MultipartParts(t *testing.T) {
+	testModelOpenAPIV3(t, "testdata/v3.0/uploads.json", "testdata/v3.0/uploads.model.json")
+}
+
+func testModelOpenAPIV3(t *testing.T, refFile string, modelFile string) {
 	bFile, err := os.ReadFile(refFile)
 	if err != nil {
 		t.Logf("Failed to read file: %+v", err)
@@ -53,3 +69,107 @@ func TestModelOpenAPIV3(t *testing.T) {
 	x, _ := protojson.Marshal(m)
 	t.Logf("Model: %s", x)
 }
+
+const clientBehaviorDocument = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      x-client-timeout: 1m30s
+      x-retryable: true
+      responses:
+        '200':
+          description: The pets.
+    post:
+      operationId: createPet
+      x-retryable: true
+      responses:
+        '201':
+          description: The created pet.
+    patch:
+      operationId: updatePets
+      x-client-timeout: 500ms
+      x-idempotent: true
+      responses:
+        '200':
+          description: The updated pets.
+    delete:
+      operationId: deletePets
+      x-idempotent: false
+      responses:
+        '204':
+          description: The pets were deleted.
+`
+
+func TestModelOpenAPIV3ClientBehavior(t *testing.T) {
+	document, err := openapiv3.ParseDocument([]byte(clientBehaviorDocument))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	m, err := NewModelFromOpenAPI3(document, "pets.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	idempotent, notIdempotent := true, false
+	expected := map[string]*Method{
+		"ListPets":   {TimeoutMilliseconds: 90000, Retryable: true},
+		"CreatePet":  {Retryable: true},
+		"UpdatePets": {TimeoutMilliseconds: 500, Idempotent: &idempotent},
+		"DeletePets": {Idempotent: &notIdempotent},
+	}
+	for _, method := range m.Methods {
+		want := expected[method.Name]
+		if want == nil {
+			t.Errorf("unexpected method %s", method.Name)
+			continue
+		}
+		if method.TimeoutMilliseconds != want.TimeoutMilliseconds || method.Retryable != want.Retryable ||
+			(method.Idempotent == nil) != (want.Idempotent == nil) || method.GetIdempotent() != want.GetIdempotent() {
+			t.Errorf("unexpected client behavior of %s: timeout %d, retryable %t, idempotent %v",
+				method.Name, method.TimeoutMilliseconds, method.Retryable, method.Idempotent)
+		}
+		if method.IsIdempotent() != (method.Name == "ListPets" || method.Name == "UpdatePets") {
+			t.Errorf("unexpected idempotency of %s: %t", method.Name, method.IsIdempotent())
+		}
+	}
+
+	invalid := strings.Replace(strings.Replace(strings.Replace(clientBehaviorDocument,
+		"x-client-timeout: 1m30s", "x-client-timeout: 90", 1),
+		"x-retryable: true", "x-retryable: sometimes", 1),
+		"x-client-timeout: 500ms", "x-client-timeout: 500us", 1)
+	if document, err = openapiv3.ParseDocument([]byte(invalid)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+	m, err = NewModelFromOpenAPI3(document, "pets.yaml")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, message := range []string{
+		"GET /pets: x-client-timeout: integer is not of type string, ignoring it",
+		"GET /pets: x-retryable: string is not of type boolean, ignoring it",
+	} {
+		if !strings.Contains(output.String(), message) {
+			t.Errorf("missing warning %q in %s", message, output.String())
+		}
+	}
+	for _, method := range m.Methods {
+		switch method.Name {
+		case "ListPets":
+			if method.TimeoutMilliseconds != 0 || method.Retryable {
+				t.Errorf("invalid extensions of ListPets weren't ignored: timeout %d, retryable %t", method.TimeoutMilliseconds, method.Retryable)
+			}
+		case "UpdatePets":
+			// Timeouts are rounded up to whole milliseconds.
+			if method.TimeoutMilliseconds != 1 {
+				t.Errorf("unexpected timeout of UpdatePets: %d", method.TimeoutMilliseconds)
+			}
+		}
+	}
+}